@@ -4,14 +4,20 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"time"
 
 	"github.com/polyfant/hulta_pregnancy_app/internal/api"
+	"github.com/polyfant/hulta_pregnancy_app/internal/audit"
 	"github.com/polyfant/hulta_pregnancy_app/internal/cache"
 	"github.com/polyfant/hulta_pregnancy_app/internal/config"
 	"github.com/polyfant/hulta_pregnancy_app/internal/database"
 	"github.com/polyfant/hulta_pregnancy_app/internal/repository"
 	"github.com/polyfant/hulta_pregnancy_app/internal/service"
 	"github.com/polyfant/hulta_pregnancy_app/internal/service/breeding"
+	"github.com/polyfant/hulta_pregnancy_app/internal/service/growth"
+	"github.com/polyfant/hulta_pregnancy_app/internal/service/health"
+	"github.com/polyfant/hulta_pregnancy_app/internal/service/notification"
+	"github.com/polyfant/hulta_pregnancy_app/internal/service/storage"
 	"github.com/gin-gonic/gin"
 )
 
@@ -57,16 +63,93 @@ func run() error {
 	pregnancyRepo := repository.NewPregnancyRepository(db.DB)
 	healthRepo := repository.NewHealthRepository(db.DB)
 	breedingRepo := repository.NewBreedingRepository(db.DB)
+	orgRepo := repository.NewOrganizationRepository(db.DB)
+	vetAccessRepo := repository.NewVetAccessRepository(db.DB)
+	privacyRepo := repository.NewPrivacyRepository(db.DB)
+	mediaRepo := repository.NewMediaRepository(db.DB)
+	vitalSignsRepo := repository.NewVitalSignsRepository(db.DB)
+	healthReminderRepo := repository.NewHealthReminderRepository(db.DB)
+	eggCountRepo := repository.NewFecalEggCountRepository(db.DB)
+	treatmentRepo := repository.NewTreatmentRepository(db.DB)
+	farrierRepo := repository.NewFarrierRepository(db.DB)
+	bodyConditionRepo := repository.NewBodyConditionRepository(db.DB)
+	weightRepo := repository.NewWeightRepository(db.DB)
+	neonatalRepo := repository.NewNeonatalRepository(db.DB)
+	weaningRepo := repository.NewWeaningRepository(db.DB)
+	feedRepo := repository.NewFeedRepository(db.DB)
+	pastureRepo := repository.NewPastureRepository(db.DB)
+	growthRepo := repository.NewGrowthRepository(db.DB)
+	notificationRepo := notification.NewGormRepository(db.DB)
+
+	// Initialize file storage
+	if cfg.Storage.Backend == storage.BackendLocal && cfg.Storage.SigningKey == "" {
+		return fmt.Errorf("STORAGE_SIGNING_KEY is required for local file storage")
+	}
+	blobStore, err := storage.NewBlobStore(storage.Config{
+		Backend:     cfg.Storage.Backend,
+		BasePath:    cfg.Storage.BasePath,
+		PublicURL:   cfg.Storage.PublicURL,
+		SigningKey:  cfg.Storage.SigningKey,
+		S3Endpoint:  cfg.Storage.S3Endpoint,
+		S3Bucket:    cfg.Storage.S3Bucket,
+		S3Region:    cfg.Storage.S3Region,
+		S3AccessKey: cfg.Storage.S3AccessKey,
+		S3SecretKey: cfg.Storage.S3SecretKey,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to initialize file storage: %w", err)
+	}
+	fileStorage := storage.NewFileStorage(blobStore)
+	localFiles, _ := blobStore.(*storage.LocalBlobStore)
 
 	// Initialize cache
 	cache := cache.NewMemoryCache()
 
+	// Initialize audit trail
+	if err := os.MkdirAll("./logs", 0755); err != nil {
+		return fmt.Errorf("failed to create log directory: %w", err)
+	}
+	auditTrail, err := audit.NewAuditTrail("./logs")
+	if err != nil {
+		return fmt.Errorf("failed to initialize audit trail: %w", err)
+	}
+	defer auditTrail.Close()
+
 	// Initialize services
 	userService := service.NewUserService(userRepo)
-	horseService := service.NewHorseService(horseRepo)
-	pregnancyService := service.NewPregnancyService(horseRepo, pregnancyRepo)
-	healthService := service.NewHealthService(healthRepo)
+	vetAccessService := service.NewVetAccessService(vetAccessRepo, horseRepo, privacyRepo, auditTrail)
+	horseService := service.NewHorseService(horseRepo, orgRepo, vetAccessService)
+	pregnancyService := service.NewPregnancyService(horseRepo, pregnancyRepo, horseService)
+	healthService := service.NewHealthService(healthRepo, horseService)
 	breedingService := breeding.NewBreedingService(breedingRepo)
+	orgService := service.NewOrganizationService(orgRepo)
+	mediaService := service.NewMediaService(mediaRepo, fileStorage, healthService, pregnancyService)
+	notificationService := notification.NewService(notificationRepo, userRepo, nil, nil, nil)
+	vitalSignsService := service.NewVitalSignsService(vitalSignsRepo, notificationService)
+	healthScheduleService := service.NewHealthScheduleService(horseService, horseRepo, healthRepo, eggCountRepo, farrierRepo, healthReminderRepo, notificationService)
+	healthScheduleService.ScheduleReminders(24 * time.Hour)
+	eggCountService := service.NewFecalEggCountService(eggCountRepo, healthRepo)
+	conditions, err := health.LoadConditionCatalogue(cfg.Health.ConditionsPath)
+	if err != nil {
+		return fmt.Errorf("failed to load condition catalogue: %w", err)
+	}
+	symptomChecker := service.NewSymptomCheckerService(conditions, healthRepo, vitalSignsRepo)
+	treatmentService := service.NewTreatmentService(treatmentRepo, pregnancyRepo, weightRepo)
+	farrierService := service.NewFarrierService(farrierRepo)
+	bodyConditionService := service.NewBodyConditionService(bodyConditionRepo, pregnancyRepo)
+	nutritionService := health.NewNutritionService(healthRepo, horseRepo, bodyConditionRepo, weightRepo, pregnancyRepo, weaningRepo, pastureRepo)
+	weightService := service.NewWeightService(weightRepo)
+	neonatalService := service.NewNeonatalService(neonatalRepo, horseRepo, vitalSignsRepo, weightRepo, healthReminderRepo, notificationService)
+	neonatalService.ScheduleMilestoneChecks(15 * time.Minute)
+	growthCurves, err := growth.LoadReferenceCurves(cfg.Health.GrowthCurvesPath)
+	if err != nil {
+		return fmt.Errorf("failed to load growth reference curves: %w", err)
+	}
+	growthService := service.NewGrowthService(growthRepo, horseRepo, growthCurves, notificationService)
+	weaningService := service.NewWeaningService(weaningRepo, growthRepo, pregnancyRepo, growthCurves)
+	feedService := service.NewFeedService(feedRepo, horseRepo, nutritionService, notificationService)
+	feedService.ScheduleStockChecks(24 * time.Hour)
+	pastureService := service.NewPastureService(pastureRepo)
 
 	// Initialize handler
 	handler := api.NewHandler(api.HandlerConfig{
@@ -76,9 +159,28 @@ func run() error {
 		PregnancyService: pregnancyService,
 		HealthService:    healthService,
 		BreedingService:  breedingService,
+		GrowthService:    growthService,
+		OrgService:       orgService,
+		VetAccessService: vetAccessService,
+		MediaService:     mediaService,
+		VitalSigns:       vitalSignsService,
+		HealthSchedule:   healthScheduleService,
+		EggCounts:        eggCountService,
+		SymptomChecker:   symptomChecker,
+		Treatments:       treatmentService,
+		Farrier:          farrierService,
+		BodyConditions:   bodyConditionService,
+		Nutrition:        nutritionService,
+		Weights:          weightService,
+		Neonatal:         neonatalService,
+		Weaning:          weaningService,
+		Feed:             feedService,
+		Pastures:         pastureService,
+		LocalFiles:       localFiles,
 		Cache:           cache,
 		HorseRepo:       horseRepo,
 		BreedingRepo:    breedingRepo,
+		GrowthRepo:      growthRepo,
 		Auth0:           cfg.Auth0,
 	})
 
@@ -0,0 +1,120 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/polyfant/hulta_pregnancy_app/internal/api/types"
+	"github.com/polyfant/hulta_pregnancy_app/internal/models"
+	"github.com/polyfant/hulta_pregnancy_app/internal/service/health"
+)
+
+// GetBodyConditionHistory handles GET /horses/:id/body-condition
+func (h *Handler) GetBodyConditionHistory(c *gin.Context) {
+	horse, ok := h.authorizeHorseHealth(c, models.PermissionView)
+	if !ok {
+		return
+	}
+
+	history, err := h.bodyConditions.History(c.Request.Context(), horse)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, types.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, history)
+}
+
+// AddBodyConditionScore handles POST /horses/:id/body-condition
+func (h *Handler) AddBodyConditionScore(c *gin.Context) {
+	horse, ok := h.authorizeHorseHealth(c, models.PermissionLog)
+	if !ok {
+		return
+	}
+
+	var record models.BodyCondition
+	if err := c.ShouldBindJSON(&record); err != nil {
+		c.JSON(http.StatusBadRequest, types.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	record.ID = 0
+	record.UserID = c.GetString("user_id")
+
+	if err := h.bodyConditions.Record(c.Request.Context(), horse, &record); err != nil {
+		bodyConditionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, record)
+}
+
+// GetPregnancyBodyCondition handles GET /horses/:id/body-condition/pregnancy
+func (h *Handler) GetPregnancyBodyCondition(c *gin.Context) {
+	horse, ok := h.authorizeHorseHealth(c, models.PermissionView)
+	if !ok {
+		return
+	}
+
+	history, err := h.bodyConditions.PregnancyTrend(c.Request.Context(), horse)
+	if err != nil {
+		bodyConditionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, history)
+}
+
+// GetFeedRecommendation handles GET /horses/:id/nutrition
+func (h *Handler) GetFeedRecommendation(c *gin.Context) {
+	horse, ok := h.authorizeHorseHealth(c, models.PermissionView)
+	if !ok {
+		return
+	}
+
+	activity, err := health.ParseActivityLevel(c.Query("activity"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, types.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	recommendation, err := h.nutrition.RecommendFeed(c.Request.Context(), horse, activity)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, types.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, recommendation)
+}
+
+// GetLactation handles GET /horses/:id/lactation
+func (h *Handler) GetLactation(c *gin.Context) {
+	horse, ok := h.authorizeHorseHealth(c, models.PermissionView)
+	if !ok {
+		return
+	}
+
+	lactation, err := h.nutrition.Lactation(c.Request.Context(), horse)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, types.ErrorResponse{Error: err.Error()})
+		return
+	}
+	if lactation == nil {
+		c.JSON(http.StatusNotFound, types.ErrorResponse{Error: "horse is not lactating"})
+		return
+	}
+
+	c.JSON(http.StatusOK, lactation)
+}
+
+func bodyConditionError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, models.ErrInvalidBodyCondition):
+		c.JSON(http.StatusBadRequest, types.ErrorResponse{Error: err.Error()})
+	case errors.Is(err, models.ErrNoActivePregnancy):
+		c.JSON(http.StatusNotFound, types.ErrorResponse{Error: err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, types.ErrorResponse{Error: err.Error()})
+	}
+}
@@ -25,19 +25,20 @@ type APIHandlerConfig struct {
 
 // HealthService defines the interface for health-related operations
 type HealthService interface {
-	CreateRecord(ctx context.Context, record *models.HealthRecord) error
-	GetRecords(ctx context.Context, horseID uint) ([]models.HealthRecord, error)
-	UpdateRecord(ctx context.Context, record *models.HealthRecord) error
-	DeleteRecord(ctx context.Context, id uint) error
+	CreateRecord(ctx context.Context, userID string, record *models.HealthRecord) error
+	GetRecords(ctx context.Context, userID string, horseID uint) ([]models.HealthRecord, error)
+	FindRecords(ctx context.Context, userID string, horseID uint, filter models.HealthRecordFilter) ([]models.HealthRecord, error)
+	UpdateRecord(ctx context.Context, userID string, record *models.HealthRecord) error
+	DeleteRecord(ctx context.Context, userID string, horseID, id uint) error
 }
 
 // PregnancyService defines the interface for pregnancy-related operations
 type PregnancyService interface {
-	GetPregnancy(ctx context.Context, horseID uint) (*models.Pregnancy, error)
-	StartTracking(ctx context.Context, horseID uint, start models.PregnancyStart) error
-	GetStatus(ctx context.Context, horseID uint) (*models.PregnancyStatus, error)
-	GetPregnancyEvents(ctx context.Context, horseID uint) ([]models.PregnancyEvent, error)
-	AddPregnancyEvent(ctx context.Context, event *models.PregnancyEvent) error
+	GetPregnancy(ctx context.Context, userID string, horseID uint) (*models.Pregnancy, error)
+	StartTracking(ctx context.Context, userID string, horseID uint, start models.PregnancyStart) error
+	GetStatus(ctx context.Context, userID string, horseID uint) (*models.PregnancyStatus, error)
+	GetPregnancyEvents(ctx context.Context, userID string, horseID uint) ([]models.PregnancyEvent, error)
+	AddPregnancyEvent(ctx context.Context, userID string, horseID uint, event *models.PregnancyEvent) error
 	GetGuidelines(ctx context.Context, stage models.PregnancyStage) ([]models.Guideline, error)
 }
 
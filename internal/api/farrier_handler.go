@@ -0,0 +1,113 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/polyfant/hulta_pregnancy_app/internal/api/types"
+	"github.com/polyfant/hulta_pregnancy_app/internal/models"
+)
+
+// GetFarrierVisits handles GET /horses/:id/farrier/visits
+func (h *Handler) GetFarrierVisits(c *gin.Context) {
+	horse, ok := h.authorizeHorseHealth(c, models.PermissionView)
+	if !ok {
+		return
+	}
+
+	visits, err := h.farrier.ListVisits(c.Request.Context(), horse.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, types.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, visits)
+}
+
+// AddFarrierVisit handles POST /horses/:id/farrier/visits
+func (h *Handler) AddFarrierVisit(c *gin.Context) {
+	horse, ok := h.authorizeHorseHealth(c, models.PermissionLog)
+	if !ok {
+		return
+	}
+
+	var visit models.FarrierVisit
+	if err := c.ShouldBindJSON(&visit); err != nil {
+		c.JSON(http.StatusBadRequest, types.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	visit.ID = 0
+	visit.UserID = c.GetString("user_id")
+
+	if err := h.farrier.RecordVisit(c.Request.Context(), horse, &visit); err != nil {
+		farrierError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, visit)
+}
+
+// GetFarrierSchedule handles GET /horses/:id/farrier/schedule
+func (h *Handler) GetFarrierSchedule(c *gin.Context) {
+	horse, ok := h.authorizeHorseHealth(c, models.PermissionView)
+	if !ok {
+		return
+	}
+
+	status, err := h.farrier.GetSchedule(c.Request.Context(), horse)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, types.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, status)
+}
+
+// SetFarrierSchedule handles PUT /horses/:id/farrier/schedule
+func (h *Handler) SetFarrierSchedule(c *gin.Context) {
+	horse, ok := h.authorizeHorseHealth(c, models.PermissionLog)
+	if !ok {
+		return
+	}
+
+	var schedule models.FarrierSchedule
+	if err := c.ShouldBindJSON(&schedule); err != nil {
+		c.JSON(http.StatusBadRequest, types.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	schedule.ID = 0
+	schedule.UserID = c.GetString("user_id")
+
+	if err := h.farrier.SetSchedule(c.Request.Context(), horse, &schedule); err != nil {
+		farrierError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, schedule)
+}
+
+// DeleteFarrierSchedule handles DELETE /horses/:id/farrier/schedule
+func (h *Handler) DeleteFarrierSchedule(c *gin.Context) {
+	horse, ok := h.authorizeHorseHealth(c, models.PermissionLog)
+	if !ok {
+		return
+	}
+
+	if err := h.farrier.DeleteSchedule(c.Request.Context(), horse.ID); err != nil {
+		c.JSON(http.StatusInternalServerError, types.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func farrierError(c *gin.Context, err error) {
+	if errors.Is(err, models.ErrInvalidFarrierVisit) {
+		c.JSON(http.StatusBadRequest, types.ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, types.ErrorResponse{Error: err.Error()})
+}
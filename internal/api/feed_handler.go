@@ -0,0 +1,358 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/polyfant/hulta_pregnancy_app/internal/api/types"
+	"github.com/polyfant/hulta_pregnancy_app/internal/models"
+	"github.com/polyfant/hulta_pregnancy_app/internal/service/health"
+)
+
+// GetFeedTypes handles GET /feed/types
+func (h *Handler) GetFeedTypes(c *gin.Context) {
+	feedTypes, err := h.feed.ListTypes(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, types.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, feedTypes)
+}
+
+// AddFeedType handles POST /feed/types
+func (h *Handler) AddFeedType(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, types.ErrorResponse{Error: "User not authenticated"})
+		return
+	}
+
+	var feedType models.FeedType
+	if err := c.ShouldBindJSON(&feedType); err != nil {
+		c.JSON(http.StatusBadRequest, types.ErrorResponse{Error: err.Error()})
+		return
+	}
+	feedType.CreatedBy = &userID
+
+	if err := h.feed.CreateType(c.Request.Context(), &feedType); err != nil {
+		feedError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, feedType)
+}
+
+// SetFeedNutrients handles PUT /feed/types/:typeId/nutrients. The body is
+// the feed's composition per kg, or litre, as fed; only the user who added
+// the feed may set it.
+func (h *Handler) SetFeedNutrients(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, types.ErrorResponse{Error: "User not authenticated"})
+		return
+	}
+
+	typeID, err := strconv.ParseUint(c.Param("typeId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, types.ErrorResponse{Error: "Invalid feed type ID"})
+		return
+	}
+
+	var nutrients models.Nutrients
+	if err := c.ShouldBindJSON(&nutrients); err != nil {
+		c.JSON(http.StatusBadRequest, types.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	feedType, err := h.feed.SetNutrients(c.Request.Context(), userID, uint(typeID), &nutrients)
+	if err != nil {
+		feedError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, feedType)
+}
+
+// GetFeedInventory handles GET /feed/inventory
+func (h *Handler) GetFeedInventory(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, types.ErrorResponse{Error: "User not authenticated"})
+		return
+	}
+
+	items, err := h.feed.ListInventory(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, types.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, items)
+}
+
+// AddFeedInventory handles POST /feed/inventory
+func (h *Handler) AddFeedInventory(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, types.ErrorResponse{Error: "User not authenticated"})
+		return
+	}
+
+	var item models.FeedInventoryItem
+	if err := c.ShouldBindJSON(&item); err != nil {
+		c.JSON(http.StatusBadRequest, types.ErrorResponse{Error: err.Error()})
+		return
+	}
+	item.UserID = userID
+
+	if err := h.feed.AddInventory(c.Request.Context(), &item); err != nil {
+		feedError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, item)
+}
+
+// UpdateFeedInventory handles PUT /feed/inventory/:itemId
+func (h *Handler) UpdateFeedInventory(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, types.ErrorResponse{Error: "User not authenticated"})
+		return
+	}
+	itemID, ok := parseInventoryItemID(c)
+	if !ok {
+		return
+	}
+
+	var item models.FeedInventoryItem
+	if err := c.ShouldBindJSON(&item); err != nil {
+		c.JSON(http.StatusBadRequest, types.ErrorResponse{Error: err.Error()})
+		return
+	}
+	item.ID = itemID
+	item.UserID = userID
+
+	if err := h.feed.UpdateInventory(c.Request.Context(), &item); err != nil {
+		feedError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, item)
+}
+
+// DeleteFeedInventory handles DELETE /feed/inventory/:itemId
+func (h *Handler) DeleteFeedInventory(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, types.ErrorResponse{Error: "User not authenticated"})
+		return
+	}
+	itemID, ok := parseInventoryItemID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.feed.DeleteInventory(c.Request.Context(), userID, itemID); err != nil {
+		feedError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GetFeedStock handles GET /feed/stock
+func (h *Handler) GetFeedStock(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, types.ErrorResponse{Error: "User not authenticated"})
+		return
+	}
+
+	levels, err := h.feed.StockLevels(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, types.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, levels)
+}
+
+// GetFeedForecast handles GET /feed/forecast. The optional days query
+// parameter sets the period the purchase list covers.
+func (h *Handler) GetFeedForecast(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, types.ErrorResponse{Error: "User not authenticated"})
+		return
+	}
+
+	days := 0
+	if raw := c.Query("days"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, types.ErrorResponse{Error: "Invalid days"})
+			return
+		}
+		days = parsed
+	}
+
+	forecast, err := h.feed.Forecast(c.Request.Context(), userID, days)
+	if err != nil {
+		feedError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, forecast)
+}
+
+// GetRations handles GET /horses/:id/rations
+func (h *Handler) GetRations(c *gin.Context) {
+	horse, ok := h.authorizeHorseHealth(c, models.PermissionView)
+	if !ok {
+		return
+	}
+
+	rations, err := h.feed.GetRations(c.Request.Context(), horse)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, types.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, rations)
+}
+
+// EvaluateRation handles GET /horses/:id/rations/evaluation. The activity
+// query parameter is as for the feed recommendation; lactation_month, from
+// 1 to 6, overrides the month worked out from the mare's last foaling.
+func (h *Handler) EvaluateRation(c *gin.Context) {
+	horse, ok := h.authorizeHorseHealth(c, models.PermissionView)
+	if !ok {
+		return
+	}
+
+	activity, err := health.ParseActivityLevel(c.Query("activity"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, types.ErrorResponse{Error: err.Error()})
+		return
+	}
+	lactationMonth := 0
+	if raw := c.Query("lactation_month"); raw != "" {
+		lactationMonth, err = strconv.Atoi(raw)
+		if err != nil || lactationMonth < 1 || lactationMonth > models.LactationMonths {
+			c.JSON(http.StatusBadRequest, types.ErrorResponse{Error: "lactation_month must be between 1 and 6"})
+			return
+		}
+	}
+
+	rations, err := h.feed.GetRations(c.Request.Context(), horse)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, types.ErrorResponse{Error: err.Error()})
+		return
+	}
+	evaluation, err := h.nutrition.EvaluateRation(c.Request.Context(), horse, activity, lactationMonth, rations)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, types.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, evaluation)
+}
+
+// SetRations handles PUT /horses/:id/rations. The body is the horse's whole
+// ration; feeds left out are dropped from it.
+func (h *Handler) SetRations(c *gin.Context) {
+	horse, ok := h.authorizeHorseHealth(c, models.PermissionEdit)
+	if !ok {
+		return
+	}
+
+	var rations []models.FeedRation
+	if err := c.ShouldBindJSON(&rations); err != nil {
+		c.JSON(http.StatusBadRequest, types.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	saved, err := h.feed.SetRations(c.Request.Context(), horse, rations)
+	if err != nil {
+		feedError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, saved)
+}
+
+// GetFeedings handles GET /horses/:id/feedings?since=RFC3339
+func (h *Handler) GetFeedings(c *gin.Context) {
+	horse, ok := h.authorizeHorseHealth(c, models.PermissionView)
+	if !ok {
+		return
+	}
+
+	var since *time.Time
+	if value := c.Query("since"); value != "" {
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, types.ErrorResponse{Error: "Invalid since date, expected RFC3339"})
+			return
+		}
+		since = &parsed
+	}
+
+	logs, err := h.feed.ListFeedings(c.Request.Context(), horse, since)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, types.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, logs)
+}
+
+// LogFeeding handles POST /horses/:id/feedings. The amount is taken from
+// the owner's feed store.
+func (h *Handler) LogFeeding(c *gin.Context) {
+	horse, ok := h.authorizeHorseHealth(c, models.PermissionLog)
+	if !ok {
+		return
+	}
+
+	var log models.FeedLog
+	if err := c.ShouldBindJSON(&log); err != nil {
+		c.JSON(http.StatusBadRequest, types.ErrorResponse{Error: err.Error()})
+		return
+	}
+	log.UserID = c.GetString("user_id")
+
+	if err := h.feed.LogFeeding(c.Request.Context(), horse, &log); err != nil {
+		feedError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, log)
+}
+
+func parseInventoryItemID(c *gin.Context) (uint, bool) {
+	itemID, err := strconv.ParseUint(c.Param("itemId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, types.ErrorResponse{Error: "Invalid inventory item ID"})
+		return 0, false
+	}
+	return uint(itemID), true
+}
+
+func feedError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, models.ErrInvalidFeed):
+		c.JSON(http.StatusBadRequest, types.ErrorResponse{Error: err.Error()})
+	case errors.Is(err, models.ErrFeedNotFound):
+		c.JSON(http.StatusNotFound, types.ErrorResponse{Error: err.Error()})
+	case errors.Is(err, models.ErrAccessDenied):
+		c.JSON(http.StatusForbidden, types.ErrorResponse{Error: err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, types.ErrorResponse{Error: err.Error()})
+	}
+}
@@ -0,0 +1,54 @@
+package api
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/polyfant/hulta_pregnancy_app/internal/api/types"
+	"github.com/polyfant/hulta_pregnancy_app/internal/service/storage"
+)
+
+// FileHandler serves signed download links issued by the local blob store.
+// With an S3 backend links point at the bucket and files is nil.
+type FileHandler struct {
+	files *storage.LocalBlobStore
+}
+
+func NewFileHandler(files *storage.LocalBlobStore) *FileHandler {
+	return &FileHandler{files: files}
+}
+
+// GetFile handles GET /files/*key
+func (h *FileHandler) GetFile(c *gin.Context) {
+	if h.files == nil {
+		c.JSON(http.StatusNotFound, types.ErrorResponse{Error: "Not found"})
+		return
+	}
+
+	key := strings.TrimPrefix(c.Param("key"), "/")
+	expires, err := strconv.ParseInt(c.Query("expires"), 10, 64)
+	if err != nil || h.files.VerifySignature(key, expires, c.Query("signature")) != nil {
+		c.JSON(http.StatusForbidden, types.ErrorResponse{Error: "Access denied"})
+		return
+	}
+
+	file, err := h.files.Get(c.Request.Context(), key)
+	if err != nil {
+		if errors.Is(err, storage.ErrBlobNotFound) || errors.Is(err, storage.ErrInvalidBlobKey) {
+			c.JSON(http.StatusNotFound, types.ErrorResponse{Error: "Not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, types.ErrorResponse{Error: err.Error()})
+		return
+	}
+	defer file.Close()
+
+	c.Header("Content-Type", "application/octet-stream")
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.Status(http.StatusOK)
+	io.Copy(c.Writer, file)
+}
@@ -1,36 +1,43 @@
 package api
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/polyfant/hulta_pregnancy_app/internal/models"
 	"github.com/polyfant/hulta_pregnancy_app/internal/service"
 	"github.com/polyfant/hulta_pregnancy_app/internal/types"
+	"gorm.io/gorm"
 )
 
 type GrowthHandler struct {
 	growthService service.GrowthService
+	horseService  service.HorseService
 }
 
-func NewGrowthHandler(growthService service.GrowthService) *GrowthHandler {
+func NewGrowthHandler(growthService service.GrowthService, horseService service.HorseService) *GrowthHandler {
 	return &GrowthHandler{
 		growthService: growthService,
+		horseService:  horseService,
 	}
 }
 
 // RecordGrowthMeasurement handles recording a new growth measurement for a foal
 func (h *GrowthHandler) RecordGrowthMeasurement(c *gin.Context) {
-	foalIDStr := c.Param("foalId")
-	foalID, err := strconv.ParseUint(foalIDStr, 10, 64)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, types.ErrorResponse{Error: "Invalid foal ID"})
+	foalID, ok := h.authorize(c, models.PermissionLog)
+	if !ok {
 		return
 	}
 
 	var measurementData struct {
-		Weight float64 `json:"weight" binding:"required,min=0"`
-		Height float64 `json:"height" binding:"required,min=0"`
+		Weight              float64   `json:"weight" binding:"min=0"`
+		Height              float64   `json:"height" binding:"min=0"`
+		CannonCircumference float64   `json:"cannonCircumference" binding:"min=0"`
+		MeasurementDate     time.Time `json:"measurementDate"`
+		Notes               string    `json:"notes"`
 	}
 
 	if err := c.ShouldBindJSON(&measurementData); err != nil {
@@ -38,24 +45,33 @@ func (h *GrowthHandler) RecordGrowthMeasurement(c *gin.Context) {
 		return
 	}
 
-	if err := h.growthService.RecordGrowthMeasurement(c.Request.Context(), uint(foalID), measurementData.Weight, measurementData.Height); err != nil {
+	measurement := &models.GrowthData{
+		Weight:              measurementData.Weight,
+		Height:              measurementData.Height,
+		CannonCircumference: measurementData.CannonCircumference,
+		MeasurementDate:     measurementData.MeasurementDate,
+		Notes:               measurementData.Notes,
+	}
+	if err := h.growthService.RecordGrowthMeasurement(c.Request.Context(), foalID, measurement); err != nil {
+		if errors.Is(err, models.ErrInvalidGrowthMeasurement) {
+			c.JSON(http.StatusBadRequest, types.ErrorResponse{Error: err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, types.ErrorResponse{Error: "Failed to record growth measurement"})
 		return
 	}
 
-	c.JSON(http.StatusCreated, gin.H{"message": "Growth measurement recorded successfully"})
+	c.JSON(http.StatusCreated, measurement)
 }
 
 // GetFoalGrowthData retrieves all growth data for a specific foal
 func (h *GrowthHandler) GetFoalGrowthData(c *gin.Context) {
-	foalIDStr := c.Param("foalId")
-	foalID, err := strconv.ParseUint(foalIDStr, 10, 64)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, types.ErrorResponse{Error: "Invalid foal ID"})
+	foalID, ok := h.authorize(c, models.PermissionView)
+	if !ok {
 		return
 	}
 
-	growthData, err := h.growthService.GetFoalGrowthData(c.Request.Context(), uint(foalID))
+	growthData, err := h.growthService.GetFoalGrowthData(c.Request.Context(), foalID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, types.ErrorResponse{Error: "Failed to retrieve growth data"})
 		return
@@ -66,14 +82,12 @@ func (h *GrowthHandler) GetFoalGrowthData(c *gin.Context) {
 
 // AnalyzeGrowthTrends provides an analysis of a foal's growth trends
 func (h *GrowthHandler) AnalyzeGrowthTrends(c *gin.Context) {
-	foalIDStr := c.Param("foalId")
-	foalID, err := strconv.ParseUint(foalIDStr, 10, 64)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, types.ErrorResponse{Error: "Invalid foal ID"})
+	foalID, ok := h.authorize(c, models.PermissionView)
+	if !ok {
 		return
 	}
 
-	analysis, err := h.growthService.AnalyzeGrowthTrends(c.Request.Context(), uint(foalID))
+	analysis, err := h.growthService.AnalyzeGrowthTrends(c.Request.Context(), foalID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, types.ErrorResponse{Error: "Failed to analyze growth trends"})
 		return
@@ -81,3 +95,47 @@ func (h *GrowthHandler) AnalyzeGrowthTrends(c *gin.Context) {
 
 	c.JSON(http.StatusOK, analysis)
 }
+
+// GetReferenceCurve returns the percentile bands a foal's growth is charted against
+func (h *GrowthHandler) GetReferenceCurve(c *gin.Context) {
+	foalID, ok := h.authorize(c, models.PermissionView)
+	if !ok {
+		return
+	}
+
+	reference, err := h.growthService.GetReferenceCurve(c.Request.Context(), foalID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, types.ErrorResponse{Error: "Failed to retrieve growth reference"})
+		return
+	}
+
+	c.JSON(http.StatusOK, reference)
+}
+
+// authorize resolves the foal from the path and checks the caller's access
+// to its health records, writing the error response itself when it fails
+func (h *GrowthHandler) authorize(c *gin.Context, permission models.Permission) (uint, bool) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, types.ErrorResponse{Error: "User not authenticated"})
+		return 0, false
+	}
+
+	foalID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, types.ErrorResponse{Error: "Invalid foal ID"})
+		return 0, false
+	}
+
+	foal, err := h.horseService.GetForRecords(c.Request.Context(), userID, uint(foalID), models.RecordScopeHealth, permission)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, types.ErrorResponse{Error: "Foal not found"})
+		return 0, false
+	}
+	if err != nil {
+		respondHorseError(c, err)
+		return 0, false
+	}
+
+	return foal.ID, true
+}
@@ -0,0 +1,59 @@
+package api
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/polyfant/hulta_pregnancy_app/internal/mocks"
+	"github.com/polyfant/hulta_pregnancy_app/internal/models"
+	"github.com/polyfant/hulta_pregnancy_app/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func TestGrowthHandler_Authorization(t *testing.T) {
+	foal := &models.Horse{ID: 1, UserID: "owner", Name: "Foal"}
+
+	tests := []struct {
+		name       string
+		userID     string
+		method     string
+		path       string
+		wantStatus int
+	}{
+		{name: "owner reads growth data", userID: "owner", method: http.MethodGet, path: "/horses/1/growth", wantStatus: http.StatusOK},
+		{name: "stranger reads growth data", userID: "stranger", method: http.MethodGet, path: "/horses/1/growth", wantStatus: http.StatusForbidden},
+		{name: "stranger reads the analysis", userID: "stranger", method: http.MethodGet, path: "/horses/1/growth/analysis", wantStatus: http.StatusForbidden},
+		{name: "stranger reads the reference curve", userID: "stranger", method: http.MethodGet, path: "/horses/1/growth/reference", wantStatus: http.StatusForbidden},
+		{name: "stranger records a measurement", userID: "stranger", method: http.MethodPost, path: "/horses/1/growth", wantStatus: http.StatusForbidden},
+		{name: "unknown foal", userID: "owner", method: http.MethodGet, path: "/horses/2/growth", wantStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			horseRepo := new(mocks.MockHorseRepository)
+			horseRepo.On("GetByID", mock.Anything, uint(1)).Return(foal, nil)
+			horseRepo.On("GetByID", mock.Anything, uint(2)).Return(nil, gorm.ErrRecordNotFound)
+			growthRepo := new(mocks.MockGrowthRepository)
+			growthRepo.On("GetGrowthDataByFoalID", mock.Anything, uint(1)).Return([]models.GrowthData{}, nil)
+			growthService := service.NewGrowthService(growthRepo, horseRepo, nil, nil)
+			handler := NewGrowthHandler(growthService, service.NewHorseService(horseRepo, new(mocks.MockOrganizationRepository), nil))
+
+			router := setupTestRouter(tt.userID)
+			router.POST("/horses/:id/growth", handler.RecordGrowthMeasurement)
+			router.GET("/horses/:id/growth", handler.GetFoalGrowthData)
+			router.GET("/horses/:id/growth/analysis", handler.AnalyzeGrowthTrends)
+			router.GET("/horses/:id/growth/reference", handler.GetReferenceCurve)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(tt.method, tt.path, bytes.NewBufferString(`{"weight": 80}`))
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			growthRepo.AssertNotCalled(t, "CreateGrowthData", mock.Anything, mock.Anything)
+		})
+	}
+}
@@ -1,8 +1,14 @@
 package api
 
 import (
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
+	"path/filepath"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/polyfant/hulta_pregnancy_app/internal/api/types"
@@ -11,6 +17,7 @@ import (
 	"github.com/polyfant/hulta_pregnancy_app/internal/models"
 	"github.com/polyfant/hulta_pregnancy_app/internal/repository"
 	"github.com/polyfant/hulta_pregnancy_app/internal/service"
+	"github.com/polyfant/hulta_pregnancy_app/internal/service/storage"
 	"gorm.io/gorm"
 )
 
@@ -29,6 +36,23 @@ type Handler struct {
 	growthRepo       repository.GrowthRepository
 	config           HandlerConfig
 	growthHandler    *GrowthHandler
+	orgHandler       *OrganizationHandler
+	vetAccessHandler *VetAccessHandler
+	vitalSigns       service.VitalSignsService
+	healthSchedule   service.HealthScheduleService
+	eggCounts        service.FecalEggCountService
+	symptomChecker   service.SymptomCheckerService
+	treatments       service.TreatmentService
+	farrier          service.FarrierService
+	bodyConditions   service.BodyConditionService
+	nutrition        service.NutritionService
+	weights          service.WeightService
+	neonatal         service.NeonatalService
+	weaning          service.WeaningService
+	feed             service.FeedService
+	pastures         service.PastureService
+	mediaHandler     *MediaHandler
+	fileHandler      *FileHandler
 }
 
 // HandlerConfig defines the configuration for creating a new handler
@@ -40,6 +64,23 @@ type HandlerConfig struct {
 	HealthService    service.HealthService
 	BreedingService  service.BreedingService
 	GrowthService    service.GrowthService
+	OrgService       service.OrganizationService
+	VetAccessService service.VetAccessService
+	MediaService     service.MediaService
+	VitalSigns       service.VitalSignsService
+	HealthSchedule   service.HealthScheduleService
+	EggCounts        service.FecalEggCountService
+	SymptomChecker   service.SymptomCheckerService
+	Treatments       service.TreatmentService
+	Farrier          service.FarrierService
+	BodyConditions   service.BodyConditionService
+	Nutrition        service.NutritionService
+	Weights          service.WeightService
+	Neonatal         service.NeonatalService
+	Weaning          service.WeaningService
+	Feed             service.FeedService
+	Pastures         service.PastureService
+	LocalFiles       *storage.LocalBlobStore
 	Cache            cache.Cache
 	HorseRepo        repository.HorseRepository
 	BreedingRepo     repository.BreedingRepository
@@ -50,7 +91,19 @@ type HandlerConfig struct {
 // NewHandler creates a new handler instance
 func NewHandler(config HandlerConfig) *Handler {
 	// Create growth handler
-	growthHandler := NewGrowthHandler(config.GrowthService)
+	growthHandler := NewGrowthHandler(config.GrowthService, config.HorseService)
+
+	// Create organization handler
+	orgHandler := NewOrganizationHandler(config.OrgService)
+
+	// Create vet access handler
+	vetAccessHandler := NewVetAccessHandler(config.VetAccessService, config.HealthService, config.PregnancyService)
+
+	// Create media handler
+	mediaHandler := NewMediaHandler(config.MediaService, config.HorseService)
+
+	// Create file handler
+	fileHandler := NewFileHandler(config.LocalFiles)
 
 	return &Handler{
 		horseService:     config.HorseService,
@@ -66,6 +119,23 @@ func NewHandler(config HandlerConfig) *Handler {
 		growthRepo:       config.GrowthRepo,
 		config:           config,
 		growthHandler:    growthHandler,
+		orgHandler:       orgHandler,
+		vetAccessHandler: vetAccessHandler,
+		vitalSigns:       config.VitalSigns,
+		healthSchedule:   config.HealthSchedule,
+		eggCounts:        config.EggCounts,
+		symptomChecker:   config.SymptomChecker,
+		treatments:       config.Treatments,
+		farrier:          config.Farrier,
+		bodyConditions:   config.BodyConditions,
+		nutrition:        config.Nutrition,
+		weights:          config.Weights,
+		neonatal:         config.Neonatal,
+		weaning:          config.Weaning,
+		feed:             config.Feed,
+		pastures:         config.Pastures,
+		mediaHandler:     mediaHandler,
+		fileHandler:      fileHandler,
 	}
 }
 
@@ -84,7 +154,14 @@ func (h *Handler) ListHorses(c *gin.Context) {
 		return
 	}
 
-	horses, err := h.horseService.ListByUserID(c.Request.Context(), userID)
+	// Sold, deceased and archived horses are only listed on request
+	var horses []models.Horse
+	var err error
+	if c.Query("include_inactive") == "true" {
+		horses, err = h.horseService.ListAllByUserID(c.Request.Context(), userID)
+	} else {
+		horses, err = h.horseService.ListByUserID(c.Request.Context(), userID)
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, types.ErrorResponse{Error: err.Error()})
 		return
@@ -108,6 +185,10 @@ func (h *Handler) AddHorse(c *gin.Context) {
 	
 	horse.UserID = userID
 	if err := h.horseService.Create(c.Request.Context(), &horse); err != nil {
+		if errors.Is(err, models.ErrAccessDenied) {
+			c.JSON(http.StatusForbidden, types.ErrorResponse{Error: err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, types.ErrorResponse{Error: err.Error()})
 		return
 	}
@@ -115,6 +196,74 @@ func (h *Handler) AddHorse(c *gin.Context) {
 	c.JSON(http.StatusCreated, horse)
 }
 
+// maxImportSize limits the size of an uploaded horse import file
+const maxImportSize = 5 << 20
+
+// isSpreadsheetContentType reports whether a content type names a workbook
+// format rather than CSV. application/vnd.ms-excel is left out because
+// browsers send it for .csv files too; .xls bodies are caught by the service.
+func isSpreadsheetContentType(contentType string) bool {
+	switch contentType {
+	case "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+		"application/vnd.oasis.opendocument.spreadsheet":
+		return true
+	}
+	return false
+}
+
+// ImportHorses handles POST /horses/import. Only CSV is parsed: spreadsheet
+// workbooks (.xlsx, .xls, .ods) must be exported as CSV before uploading.
+func (h *Handler) ImportHorses(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, types.ErrorResponse{Error: "User not authenticated"})
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxImportSize)
+
+	// Accept either a multipart upload or a raw CSV body
+	if isSpreadsheetContentType(c.ContentType()) {
+		c.JSON(http.StatusBadRequest, types.ErrorResponse{Error: models.ErrImportNotCSV.Error()})
+		return
+	}
+	var body io.Reader = c.Request.Body
+	if strings.HasPrefix(c.ContentType(), "multipart/") {
+		fileHeader, err := c.FormFile("file")
+		if err != nil {
+			c.JSON(http.StatusBadRequest, types.ErrorResponse{Error: "CSV file is required"})
+			return
+		}
+		if ext := strings.ToLower(filepath.Ext(fileHeader.Filename)); ext != "" && ext != ".csv" && ext != ".txt" {
+			c.JSON(http.StatusBadRequest, types.ErrorResponse{Error: models.ErrImportNotCSV.Error()})
+			return
+		}
+		file, err := fileHeader.Open()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, types.ErrorResponse{Error: err.Error()})
+			return
+		}
+		defer file.Close()
+		body = file
+	}
+
+	dryRun := c.Query("dry_run") == "true"
+	result, err := h.horseService.ImportCSV(c.Request.Context(), userID, body, dryRun)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, types.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	switch {
+	case dryRun:
+		c.JSON(http.StatusOK, result)
+	case result.HasErrors():
+		c.JSON(http.StatusUnprocessableEntity, result)
+	default:
+		c.JSON(http.StatusCreated, result)
+	}
+}
+
 // GetHorse handles GET /horses/:id
 func (h *Handler) GetHorse(c *gin.Context) {
 	userID := c.GetString("user_id")
@@ -129,14 +278,9 @@ func (h *Handler) GetHorse(c *gin.Context) {
 		return
 	}
 
-	horse, err := h.horseService.GetByID(c.Request.Context(), uint(id))
+	horse, err := h.horseService.GetByID(c.Request.Context(), userID, uint(id), models.PermissionView)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, types.ErrorResponse{Error: err.Error()})
-		return
-	}
-
-	if horse.UserID != userID {
-		c.JSON(http.StatusForbidden, types.ErrorResponse{Error: "Access denied"})
+		respondHorseError(c, err)
 		return
 	}
 
@@ -157,18 +301,30 @@ func (h *Handler) UpdateHorse(c *gin.Context) {
 		return
 	}
 
-	// Verify ownership
-	existingHorse, err := h.horseService.GetByID(c.Request.Context(), horse.ID)
+	existingHorse, err := h.horseService.GetByID(c.Request.Context(), userID, horse.ID, models.PermissionEdit)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, types.ErrorResponse{Error: err.Error()})
+		respondHorseError(c, err)
 		return
 	}
-	if existingHorse.UserID != userID {
-		c.JSON(http.StatusForbidden, types.ErrorResponse{Error: "Access denied"})
+
+	// Farm members may edit a horse but only its owner can move it between farms
+	if !sameOrganization(existingHorse.OrganizationID, horse.OrganizationID) && existingHorse.UserID != userID {
+		c.JSON(http.StatusForbidden, types.ErrorResponse{Error: "Only the owner can change a horse's organization"})
 		return
 	}
+	horse.UserID = existingHorse.UserID
+
+	// Lifecycle changes go through UpdateHorseStatus
+	horse.Status = existingHorse.Status
+	horse.StatusDate = existingHorse.StatusDate
+	horse.StatusReason = existingHorse.StatusReason
+	horse.DeletedAt = existingHorse.DeletedAt
 
 	if err := h.horseService.Update(c.Request.Context(), &horse); err != nil {
+		if errors.Is(err, models.ErrAccessDenied) {
+			c.JSON(http.StatusForbidden, types.ErrorResponse{Error: err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, types.ErrorResponse{Error: err.Error()})
 		return
 	}
@@ -190,14 +346,23 @@ func (h *Handler) DeleteHorse(c *gin.Context) {
 		return
 	}
 
-	// Verify ownership
-	horse, err := h.horseService.GetByID(c.Request.Context(), uint(id))
+	horse, err := h.horseService.GetByID(c.Request.Context(), userID, uint(id), models.PermissionDelete)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, types.ErrorResponse{Error: err.Error()})
+		respondHorseError(c, err)
 		return
 	}
-	if horse.UserID != userID {
-		c.JSON(http.StatusForbidden, types.ErrorResponse{Error: "Access denied"})
+
+	// Deleting archives the horse; purging removes it and its history for good
+	if c.Query("purge") == "true" {
+		if horse.UserID != userID {
+			c.JSON(http.StatusForbidden, types.ErrorResponse{Error: "Only the owner can purge a horse"})
+			return
+		}
+		if err := h.horseService.Purge(c.Request.Context(), uint(id)); err != nil {
+			c.JSON(http.StatusInternalServerError, types.ErrorResponse{Error: err.Error()})
+			return
+		}
+		c.Status(http.StatusNoContent)
 		return
 	}
 
@@ -209,42 +374,46 @@ func (h *Handler) DeleteHorse(c *gin.Context) {
 	c.Status(http.StatusNoContent)
 }
 
-// GetHealthRecords handles GET /horses/:id/health
-func (h *Handler) GetHealthRecords(c *gin.Context) {
+// UpdateHorseStatus handles PUT /horses/:id/status
+func (h *Handler) UpdateHorseStatus(c *gin.Context) {
 	userID := c.GetString("user_id")
 	if userID == "" {
 		c.JSON(http.StatusUnauthorized, types.ErrorResponse{Error: "User not authenticated"})
 		return
 	}
 
-	horseID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, types.ErrorResponse{Error: "Invalid horse ID"})
 		return
 	}
 
-	// Verify horse ownership
-	horse, err := h.horseService.GetByID(c.Request.Context(), uint(horseID))
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, types.ErrorResponse{Error: err.Error()})
+	if _, err := h.horseService.GetByID(c.Request.Context(), userID, uint(id), models.PermissionEdit); err != nil {
+		respondHorseError(c, err)
 		return
 	}
-	if horse.UserID != userID {
-		c.JSON(http.StatusForbidden, types.ErrorResponse{Error: "Access denied"})
+
+	var change models.HorseStatusChange
+	if err := c.ShouldBindJSON(&change); err != nil {
+		c.JSON(http.StatusBadRequest, types.ErrorResponse{Error: err.Error()})
 		return
 	}
 
-	records, err := h.healthService.GetRecords(c.Request.Context(), uint(horseID))
+	updated, err := h.horseService.ChangeStatus(c.Request.Context(), uint(id), change)
 	if err != nil {
+		if errors.Is(err, models.ErrInvalidHorseStatus) {
+			c.JSON(http.StatusBadRequest, types.ErrorResponse{Error: err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, types.ErrorResponse{Error: err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, records)
+	c.JSON(http.StatusOK, updated)
 }
 
-// AddHealthRecord handles POST /horses/:id/health
-func (h *Handler) AddHealthRecord(c *gin.Context) {
+// GetHealthRecords handles GET /horses/:id/health
+func (h *Handler) GetHealthRecords(c *gin.Context) {
 	userID := c.GetString("user_id")
 	if userID == "" {
 		c.JSON(http.StatusUnauthorized, types.ErrorResponse{Error: "User not authenticated"})
@@ -257,14 +426,82 @@ func (h *Handler) AddHealthRecord(c *gin.Context) {
 		return
 	}
 
-	// Verify horse ownership
-	horse, err := h.horseService.GetByID(c.Request.Context(), uint(horseID))
+	filter, err := parseHealthRecordFilter(c)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, types.ErrorResponse{Error: err.Error()})
+		c.JSON(http.StatusBadRequest, types.ErrorResponse{Error: err.Error()})
 		return
 	}
-	if horse.UserID != userID {
-		c.JSON(http.StatusForbidden, types.ErrorResponse{Error: "Access denied"})
+
+	// Without filters keep returning every record, as before typed records existed
+	var records []models.HealthRecord
+	if filter == nil {
+		records, err = h.healthService.GetRecords(c.Request.Context(), userID, uint(horseID))
+	} else {
+		records, err = h.healthService.FindRecords(c.Request.Context(), userID, uint(horseID), *filter)
+	}
+	if err != nil {
+		respondHealthRecordError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, records)
+}
+
+// parseHealthRecordFilter reads ?type=, ?from=, ?to= and ?details.<field>=
+// query parameters. It returns nil when no filter was given.
+func parseHealthRecordFilter(c *gin.Context) (*models.HealthRecordFilter, error) {
+	filter := models.HealthRecordFilter{Type: models.HealthRecordType(c.Query("type"))}
+	hasFilter := filter.Type != ""
+
+	for name, target := range map[string]**time.Time{"from": &filter.From, "to": &filter.To} {
+		value := c.Query(name)
+		if value == "" {
+			continue
+		}
+		date, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s date, expected YYYY-MM-DD", name)
+		}
+		*target = &date
+		hasFilter = true
+	}
+
+	for key, values := range c.Request.URL.Query() {
+		if field, ok := strings.CutPrefix(key, "details."); ok && len(values) > 0 {
+			if filter.Fields == nil {
+				filter.Fields = make(map[string]string)
+			}
+			filter.Fields[field] = values[0]
+			hasFilter = true
+		}
+	}
+
+	if !hasFilter {
+		return nil, nil
+	}
+	return &filter, nil
+}
+
+// respondHealthRecordError maps health record errors to HTTP status codes
+func respondHealthRecordError(c *gin.Context, err error) {
+	if errors.Is(err, models.ErrInvalidHealthRecord) {
+		c.JSON(http.StatusBadRequest, types.ErrorResponse{Error: err.Error()})
+		return
+	}
+	respondHorseError(c, err)
+}
+
+// AddHealthRecord handles POST /horses/:id/health
+func (h *Handler) AddHealthRecord(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, types.ErrorResponse{Error: "User not authenticated"})
+		return
+	}
+
+	horseID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, types.ErrorResponse{Error: "Invalid horse ID"})
 		return
 	}
 
@@ -277,8 +514,8 @@ func (h *Handler) AddHealthRecord(c *gin.Context) {
 	record.HorseID = uint(horseID)
 	record.UserID = userID
 
-	if err := h.healthService.CreateRecord(c.Request.Context(), &record); err != nil {
-		c.JSON(http.StatusInternalServerError, types.ErrorResponse{Error: err.Error()})
+	if err := h.healthService.CreateRecord(c.Request.Context(), userID, &record); err != nil {
+		respondHealthRecordError(c, err)
 		return
 	}
 
@@ -299,17 +536,6 @@ func (h *Handler) UpdateHealthRecord(c *gin.Context) {
 		return
 	}
 
-	// Verify horse ownership
-	horse, err := h.horseService.GetByID(c.Request.Context(), uint(horseID))
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, types.ErrorResponse{Error: err.Error()})
-		return
-	}
-	if horse.UserID != userID {
-		c.JSON(http.StatusForbidden, types.ErrorResponse{Error: "Access denied"})
-		return
-	}
-
 	var record models.HealthRecord
 	if err := c.ShouldBindJSON(&record); err != nil {
 		c.JSON(http.StatusBadRequest, types.ErrorResponse{Error: err.Error()})
@@ -319,8 +545,8 @@ func (h *Handler) UpdateHealthRecord(c *gin.Context) {
 	record.HorseID = uint(horseID)
 	record.UserID = userID
 
-	if err := h.healthService.UpdateRecord(c.Request.Context(), &record); err != nil {
-		c.JSON(http.StatusInternalServerError, types.ErrorResponse{Error: err.Error()})
+	if err := h.healthService.UpdateRecord(c.Request.Context(), userID, &record); err != nil {
+		respondHealthRecordError(c, err)
 		return
 	}
 
@@ -341,29 +567,220 @@ func (h *Handler) DeleteHealthRecord(c *gin.Context) {
 		return
 	}
 
-	// Verify horse ownership
-	horse, err := h.horseService.GetByID(c.Request.Context(), uint(horseID))
+	recordID, err := strconv.ParseUint(c.Param("recordId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, types.ErrorResponse{Error: "Invalid record ID"})
+		return
+	}
+
+	if err := h.healthService.DeleteRecord(c.Request.Context(), userID, uint(horseID), uint(recordID)); err != nil {
+		respondHorseError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GetHealthDue handles GET /horses/:id/health/due
+func (h *Handler) GetHealthDue(c *gin.Context) {
+	horse, ok := h.authorizeHorseHealth(c, models.PermissionView)
+	if !ok {
+		return
+	}
+
+	items, err := h.healthSchedule.GetDueItems(c.Request.Context(), horse)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, types.ErrorResponse{Error: err.Error()})
 		return
 	}
-	if horse.UserID != userID {
-		c.JSON(http.StatusForbidden, types.ErrorResponse{Error: "Access denied"})
+
+	c.JSON(http.StatusOK, items)
+}
+
+// ListOverdueHealth handles GET /health/overdue
+func (h *Handler) ListOverdueHealth(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, types.ErrorResponse{Error: "User not authenticated"})
 		return
 	}
 
-	recordID, err := strconv.ParseUint(c.Param("recordId"), 10, 64)
+	items, err := h.healthSchedule.ListOverdue(c.Request.Context(), userID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, types.ErrorResponse{Error: "Invalid record ID"})
+		c.JSON(http.StatusInternalServerError, types.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, items)
+}
+
+// GetVitalSigns handles GET /horses/:id/vitals
+func (h *Handler) GetVitalSigns(c *gin.Context) {
+	horse, ok := h.authorizeHorseHealth(c, models.PermissionView)
+	if !ok {
 		return
 	}
 
-	if err := h.healthService.DeleteRecord(c.Request.Context(), uint(recordID)); err != nil {
+	var since *time.Time
+	if value := c.Query("since"); value != "" {
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, types.ErrorResponse{Error: "Invalid since date, expected RFC3339"})
+			return
+		}
+		since = &parsed
+	}
+
+	records, err := h.vitalSigns.List(c.Request.Context(), horse.ID, since)
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, types.ErrorResponse{Error: err.Error()})
 		return
 	}
 
-	c.Status(http.StatusNoContent)
+	c.JSON(http.StatusOK, records)
+}
+
+// AddVitalSigns handles POST /horses/:id/vitals
+func (h *Handler) AddVitalSigns(c *gin.Context) {
+	horse, ok := h.authorizeHorseHealth(c, models.PermissionLog)
+	if !ok {
+		return
+	}
+
+	var record models.VitalSignsRecord
+	if err := c.ShouldBindJSON(&record); err != nil {
+		c.JSON(http.StatusBadRequest, types.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	record.ID = 0
+	record.UserID = c.GetString("user_id")
+
+	if err := h.vitalSigns.Record(c.Request.Context(), horse, &record); err != nil {
+		if errors.Is(err, models.ErrInvalidVitalSigns) {
+			c.JSON(http.StatusBadRequest, types.ErrorResponse{Error: err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, types.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, record)
+}
+
+// GetEggCounts handles GET /horses/:id/egg-counts
+func (h *Handler) GetEggCounts(c *gin.Context) {
+	horse, ok := h.authorizeHorseHealth(c, models.PermissionView)
+	if !ok {
+		return
+	}
+
+	counts, err := h.eggCounts.List(c.Request.Context(), horse.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, types.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, counts)
+}
+
+// AddEggCount handles POST /horses/:id/egg-counts
+func (h *Handler) AddEggCount(c *gin.Context) {
+	horse, ok := h.authorizeHorseHealth(c, models.PermissionLog)
+	if !ok {
+		return
+	}
+
+	var count models.FecalEggCount
+	if err := c.ShouldBindJSON(&count); err != nil {
+		c.JSON(http.StatusBadRequest, types.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	count.ID = 0
+	count.UserID = c.GetString("user_id")
+
+	if err := h.eggCounts.Record(c.Request.Context(), horse, &count); err != nil {
+		if errors.Is(err, models.ErrInvalidEggCount) {
+			c.JSON(http.StatusBadRequest, types.ErrorResponse{Error: err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, types.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, count)
+}
+
+// GetDewormingRecommendation handles GET /horses/:id/deworming/recommendation
+func (h *Handler) GetDewormingRecommendation(c *gin.Context) {
+	horse, ok := h.authorizeHorseHealth(c, models.PermissionView)
+	if !ok {
+		return
+	}
+
+	recommendation, err := h.eggCounts.Recommend(c.Request.Context(), horse)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, types.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, recommendation)
+}
+
+// AssessSymptoms handles POST /horses/:id/symptom-check
+func (h *Handler) AssessSymptoms(c *gin.Context) {
+	horse, ok := h.authorizeHorseHealth(c, models.PermissionLog)
+	if !ok {
+		return
+	}
+
+	var req models.SymptomAssessmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, types.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	assessment, err := h.symptomChecker.Assess(c.Request.Context(), horse, c.GetString("user_id"), req)
+	if err != nil {
+		if errors.Is(err, models.ErrInvalidHealthRecord) {
+			c.JSON(http.StatusBadRequest, types.ErrorResponse{Error: err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, types.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, assessment)
+}
+
+// authorizeHorseHealth loads the horse from the path for health record access,
+// writing the error response itself when it returns false
+func (h *Handler) authorizeHorseHealth(c *gin.Context, permission models.Permission) (*models.Horse, bool) {
+	return h.authorizeHorseRecords(c, models.RecordScopeHealth, permission)
+}
+
+// authorizeHorseRecords loads the horse from the path for access to its
+// records in scope, writing the error response itself when it returns false
+func (h *Handler) authorizeHorseRecords(c *gin.Context, scope models.RecordScope, permission models.Permission) (*models.Horse, bool) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, types.ErrorResponse{Error: "User not authenticated"})
+		return nil, false
+	}
+
+	horseID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, types.ErrorResponse{Error: "Invalid horse ID"})
+		return nil, false
+	}
+
+	horse, err := h.horseService.GetForRecords(c.Request.Context(), userID, uint(horseID), scope, permission)
+	if err != nil {
+		respondHorseError(c, err)
+		return nil, false
+	}
+	return horse, true
 }
 
 // GetUserProfile handles GET /user/profile
@@ -418,6 +835,36 @@ func (h *Handler) GetDashboardStats(c *gin.Context) {
 
 // GetPregnancyGuidelines handles GET /horses/:id/pregnancy/guidelines
 func (h *Handler) GetPregnancyGuidelines(c *gin.Context) {
+	horse, ok := h.authorizeHorseRecords(c, models.RecordScopePregnancy, models.PermissionView)
+	if !ok {
+		return
+	}
+
+	stage := models.PregnancyStage(c.Query("stage"))
+	if stage == "" {
+		// Default to the mare's current stage: lactating after foaling,
+		// otherwise the stage of her active pregnancy
+		lactation, err := h.nutrition.Lactation(c.Request.Context(), horse)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, types.ErrorResponse{Error: err.Error()})
+			return
+		}
+		if lactation != nil {
+			stage = lactation.Stage
+		} else if current, err := h.pregnancyService.GetPregnancyStage(c.Request.Context(), c.GetString("user_id"), horse.ID); err == nil {
+			stage = current
+		}
+	}
+	guidelines, err := h.pregnancyService.GetGuidelines(c.Request.Context(), stage)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, types.ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, guidelines)
+}
+
+// StartPregnancyTracking handles POST /horses/:id/pregnancy/start
+func (h *Handler) StartPregnancyTracking(c *gin.Context) {
 	userID := c.GetString("user_id")
 	if userID == "" {
 		c.JSON(http.StatusUnauthorized, types.ErrorResponse{Error: "User not authenticated"})
@@ -430,28 +877,29 @@ func (h *Handler) GetPregnancyGuidelines(c *gin.Context) {
 		return
 	}
 
-	// Verify horse ownership
-	horse, err := h.horseService.GetByID(c.Request.Context(), uint(horseID))
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, types.ErrorResponse{Error: err.Error()})
-		return
-	}
-	if horse.UserID != userID {
-		c.JSON(http.StatusForbidden, types.ErrorResponse{Error: "Access denied"})
+	var start models.PregnancyStart
+	if err := c.ShouldBindJSON(&start); err != nil {
+		c.JSON(http.StatusBadRequest, types.ErrorResponse{Error: err.Error()})
 		return
 	}
 
-	stage := models.PregnancyStage(c.Query("stage"))
-	guidelines, err := h.pregnancyService.GetGuidelines(c.Request.Context(), stage)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, types.ErrorResponse{Error: err.Error()})
+	if err := h.pregnancyService.StartTracking(c.Request.Context(), userID, uint(horseID), start); err != nil {
+		respondHorseError(c, err)
 		return
 	}
-	c.JSON(http.StatusOK, guidelines)
+
+	c.Status(http.StatusCreated)
 }
 
-// StartPregnancyTracking handles POST /horses/:id/pregnancy/start
-func (h *Handler) StartPregnancyTracking(c *gin.Context) {
+// EndPregnancyRequest records how a pregnancy ended
+type EndPregnancyRequest struct {
+	Status string    `json:"status" binding:"required"`
+	Date   time.Time `json:"date" binding:"required"`
+}
+
+// EndPregnancy handles POST /horses/:id/pregnancy/end. Ending it COMPLETE
+// records the foaling, which starts the mare's lactation.
+func (h *Handler) EndPregnancy(c *gin.Context) {
 	userID := c.GetString("user_id")
 	if userID == "" {
 		c.JSON(http.StatusUnauthorized, types.ErrorResponse{Error: "User not authenticated"})
@@ -464,29 +912,29 @@ func (h *Handler) StartPregnancyTracking(c *gin.Context) {
 		return
 	}
 
-	// Verify horse ownership
-	horse, err := h.horseService.GetByID(c.Request.Context(), uint(horseID))
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, types.ErrorResponse{Error: err.Error()})
+	var req EndPregnancyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, types.ErrorResponse{Error: err.Error()})
 		return
 	}
-	if horse.UserID != userID {
+
+	pregnancy, err := h.pregnancyService.EndPregnancy(c.Request.Context(), userID, uint(horseID), strings.ToUpper(req.Status), req.Date)
+	switch {
+	case errors.Is(err, models.ErrAccessDenied):
 		c.JSON(http.StatusForbidden, types.ErrorResponse{Error: "Access denied"})
 		return
-	}
-
-	var start models.PregnancyStart
-	if err := c.ShouldBindJSON(&start); err != nil {
+	case errors.Is(err, models.ErrInvalidPregnancyEnd):
 		c.JSON(http.StatusBadRequest, types.ErrorResponse{Error: err.Error()})
 		return
-	}
-
-	if err := h.pregnancyService.StartTracking(c.Request.Context(), uint(horseID), start); err != nil {
+	case errors.Is(err, models.ErrNoActivePregnancy):
+		c.JSON(http.StatusNotFound, types.ErrorResponse{Error: err.Error()})
+		return
+	case err != nil:
 		c.JSON(http.StatusInternalServerError, types.ErrorResponse{Error: err.Error()})
 		return
 	}
 
-	c.Status(http.StatusCreated)
+	c.JSON(http.StatusOK, pregnancy)
 }
 
 // GetPregnancyEvents handles GET /horses/:id/pregnancy/events
@@ -503,28 +951,55 @@ func (h *Handler) GetPregnancyEvents(c *gin.Context) {
 		return
 	}
 
-	// Verify horse ownership
-	horse, err := h.horseService.GetByID(c.Request.Context(), uint(horseID))
+	events, err := h.pregnancyService.GetPregnancyEvents(c.Request.Context(), userID, uint(horseID))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, types.ErrorResponse{Error: err.Error()})
+		respondHorseError(c, err)
 		return
 	}
-	if horse.UserID != userID {
-		c.JSON(http.StatusForbidden, types.ErrorResponse{Error: "Access denied"})
+
+	c.JSON(http.StatusOK, events)
+}
+
+// AddPregnancyEvent handles POST /horses/:id/pregnancy/events
+func (h *Handler) AddPregnancyEvent(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, types.ErrorResponse{Error: "User not authenticated"})
 		return
 	}
 
-	events, err := h.pregnancyService.GetPregnancyEvents(c.Request.Context(), uint(horseID))
+	horseID, err := strconv.ParseUint(c.Param("id"), 10, 64)
 	if err != nil {
+		c.JSON(http.StatusBadRequest, types.ErrorResponse{Error: "Invalid horse ID"})
+		return
+	}
+
+	var event models.PregnancyEvent
+	if err := c.ShouldBindJSON(&event); err != nil {
+		c.JSON(http.StatusBadRequest, types.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	event.UserID = userID
+
+	err = h.pregnancyService.AddPregnancyEvent(c.Request.Context(), userID, uint(horseID), &event)
+	switch {
+	case errors.Is(err, models.ErrAccessDenied):
+		c.JSON(http.StatusForbidden, types.ErrorResponse{Error: "Access denied"})
+		return
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, types.ErrorResponse{Error: "No pregnancy recorded for this horse"})
+		return
+	case err != nil:
 		c.JSON(http.StatusInternalServerError, types.ErrorResponse{Error: err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, events)
+	c.JSON(http.StatusCreated, event)
 }
 
-// AddPregnancyEvent handles POST /horses/:id/pregnancy/events
-func (h *Handler) AddPregnancyEvent(c *gin.Context) {
+// GetPreFoalingSigns handles GET /horses/:id/pregnancy/signs
+func (h *Handler) GetPreFoalingSigns(c *gin.Context) {
 	userID := c.GetString("user_id")
 	if userID == "" {
 		c.JSON(http.StatusUnauthorized, types.ErrorResponse{Error: "User not authenticated"})
@@ -537,32 +1012,43 @@ func (h *Handler) AddPregnancyEvent(c *gin.Context) {
 		return
 	}
 
-	// Verify horse ownership
-	horse, err := h.horseService.GetByID(c.Request.Context(), uint(horseID))
+	signs, err := h.pregnancyService.GetPreFoalingSigns(c.Request.Context(), userID, uint(horseID))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, types.ErrorResponse{Error: err.Error()})
+		respondHorseError(c, err)
 		return
 	}
-	if horse.UserID != userID {
-		c.JSON(http.StatusForbidden, types.ErrorResponse{Error: "Access denied"})
+
+	c.JSON(http.StatusOK, signs)
+}
+
+// AddPreFoalingSign handles POST /horses/:id/pregnancy/signs
+func (h *Handler) AddPreFoalingSign(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, types.ErrorResponse{Error: "User not authenticated"})
 		return
 	}
 
-	var event models.PregnancyEvent
-	if err := c.ShouldBindJSON(&event); err != nil {
+	horseID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, types.ErrorResponse{Error: "Invalid horse ID"})
+		return
+	}
+
+	var sign models.PreFoalingSign
+	if err := c.ShouldBindJSON(&sign); err != nil {
 		c.JSON(http.StatusBadRequest, types.ErrorResponse{Error: err.Error()})
 		return
 	}
 
-	event.PregnancyID = uint(horseID)
-	event.UserID = userID
+	sign.HorseID = uint(horseID)
 
-	if err := h.pregnancyService.AddPregnancyEvent(c.Request.Context(), &event); err != nil {
-		c.JSON(http.StatusInternalServerError, types.ErrorResponse{Error: err.Error()})
+	if err := h.pregnancyService.AddPreFoalingSign(c.Request.Context(), userID, &sign); err != nil {
+		respondHorseError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusCreated, event)
+	c.JSON(http.StatusCreated, sign)
 }
 
 // GetBreedingRecords handles GET /horses/:id/breeding
@@ -579,14 +1065,8 @@ func (h *Handler) GetBreedingRecords(c *gin.Context) {
 		return
 	}
 
-	// Verify horse ownership
-	horse, err := h.horseService.GetByID(c.Request.Context(), uint(horseID))
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, types.ErrorResponse{Error: err.Error()})
-		return
-	}
-	if horse.UserID != userID {
-		c.JSON(http.StatusForbidden, types.ErrorResponse{Error: "Access denied"})
+	if _, err := h.horseService.GetByID(c.Request.Context(), userID, uint(horseID), models.PermissionView); err != nil {
+		respondHorseError(c, err)
 		return
 	}
 
@@ -613,14 +1093,8 @@ func (h *Handler) AddBreedingRecord(c *gin.Context) {
 		return
 	}
 
-	// Verify horse ownership
-	horse, err := h.horseService.GetByID(c.Request.Context(), uint(horseID))
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, types.ErrorResponse{Error: err.Error()})
-		return
-	}
-	if horse.UserID != userID {
-		c.JSON(http.StatusForbidden, types.ErrorResponse{Error: "Access denied"})
+	if _, err := h.horseService.GetByID(c.Request.Context(), userID, uint(horseID), models.PermissionEdit); err != nil {
+		respondHorseError(c, err)
 		return
 	}
 
@@ -655,14 +1129,8 @@ func (h *Handler) UpdateBreedingRecord(c *gin.Context) {
 		return
 	}
 
-	// Verify horse ownership
-	horse, err := h.horseService.GetByID(c.Request.Context(), uint(horseID))
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, types.ErrorResponse{Error: err.Error()})
-		return
-	}
-	if horse.UserID != userID {
-		c.JSON(http.StatusForbidden, types.ErrorResponse{Error: "Access denied"})
+	if _, err := h.horseService.GetByID(c.Request.Context(), userID, uint(horseID), models.PermissionEdit); err != nil {
+		respondHorseError(c, err)
 		return
 	}
 
@@ -697,14 +1165,8 @@ func (h *Handler) DeleteBreedingRecord(c *gin.Context) {
 		return
 	}
 
-	// Verify horse ownership
-	horse, err := h.horseService.GetByID(c.Request.Context(), uint(horseID))
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, types.ErrorResponse{Error: err.Error()})
-		return
-	}
-	if horse.UserID != userID {
-		c.JSON(http.StatusForbidden, types.ErrorResponse{Error: "Access denied"})
+	if _, err := h.horseService.GetByID(c.Request.Context(), userID, uint(horseID), models.PermissionDelete); err != nil {
+		respondHorseError(c, err)
 		return
 	}
 
@@ -736,20 +1198,9 @@ func (h *Handler) GetPregnancy(c *gin.Context) {
 		return
 	}
 
-	// Verify horse ownership
-	horse, err := h.horseService.GetByID(c.Request.Context(), uint(horseID))
+	pregnancy, err := h.pregnancyService.GetPregnancy(c.Request.Context(), userID, uint(horseID))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, types.ErrorResponse{Error: err.Error()})
-		return
-	}
-	if horse.UserID != userID {
-		c.JSON(http.StatusForbidden, types.ErrorResponse{Error: "Access denied"})
-		return
-	}
-
-	pregnancy, err := h.pregnancyService.GetPregnancy(c.Request.Context(), uint(horseID))
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, types.ErrorResponse{Error: err.Error()})
+		respondHorseError(c, err)
 		return
 	}
 
@@ -770,24 +1221,29 @@ func (h *Handler) GetPregnancyStatus(c *gin.Context) {
 		return
 	}
 
-	// Verify horse ownership
-	horse, err := h.horseService.GetByID(c.Request.Context(), uint(horseID))
+	status, err := h.pregnancyService.GetStatus(c.Request.Context(), userID, uint(horseID))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, types.ErrorResponse{Error: err.Error()})
+		respondHorseError(c, err)
 		return
 	}
-	if horse.UserID != userID {
+
+	c.JSON(http.StatusOK, status)
+}
+
+// respondHorseError writes the response for a failed horse lookup or access check
+func respondHorseError(c *gin.Context, err error) {
+	if errors.Is(err, models.ErrAccessDenied) {
 		c.JSON(http.StatusForbidden, types.ErrorResponse{Error: "Access denied"})
 		return
 	}
-
-	status, err := h.pregnancyService.GetStatus(c.Request.Context(), uint(horseID))
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, types.ErrorResponse{Error: err.Error()})
-		return
+	c.JSON(http.StatusInternalServerError, types.ErrorResponse{Error: err.Error()})
+}
+// sameOrganization reports whether two optional organization IDs refer to the same farm
+func sameOrganization(a, b *uint) bool {
+	if a == nil || b == nil {
+		return a == b
 	}
-
-	c.JSON(http.StatusOK, status)
+	return *a == *b
 }
 
 // GetHorseService returns the horse service
@@ -0,0 +1,212 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/polyfant/hulta_pregnancy_app/internal/api/types"
+	"github.com/polyfant/hulta_pregnancy_app/internal/models"
+	"github.com/polyfant/hulta_pregnancy_app/internal/service"
+)
+
+// maxMediaRequestSize bounds the whole multipart request, leaving room for form fields
+const maxMediaRequestSize = service.MaxDocumentSize + 1<<20
+
+type MediaHandler struct {
+	mediaService service.MediaService
+	horseService service.HorseService
+}
+
+func NewMediaHandler(mediaService service.MediaService, horseService service.HorseService) *MediaHandler {
+	return &MediaHandler{
+		mediaService: mediaService,
+		horseService: horseService,
+	}
+}
+
+// ListMedia handles GET /horses/:id/media
+func (h *MediaHandler) ListMedia(c *gin.Context) {
+	horseID, ok := h.authorize(c, models.PermissionView)
+	if !ok {
+		return
+	}
+
+	filter := models.MediaFilter{Type: models.MediaType(c.Query("type"))}
+	var err error
+	if filter.HealthRecordID, err = optionalUintQuery(c, "health_record_id"); err != nil {
+		c.JSON(http.StatusBadRequest, types.ErrorResponse{Error: err.Error()})
+		return
+	}
+	if filter.PregnancyEventID, err = optionalUintQuery(c, "pregnancy_event_id"); err != nil {
+		c.JSON(http.StatusBadRequest, types.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	media, err := h.mediaService.List(c.Request.Context(), horseID, filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, types.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, media)
+}
+
+// UploadMedia handles POST /horses/:id/media
+func (h *MediaHandler) UploadMedia(c *gin.Context) {
+	horseID, ok := h.authorize(c, models.PermissionLog)
+	if !ok {
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxMediaRequestSize)
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			respondMediaError(c, err)
+			return
+		}
+		c.JSON(http.StatusBadRequest, types.ErrorResponse{Error: "File is required"})
+		return
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, types.ErrorResponse{Error: err.Error()})
+		return
+	}
+	defer file.Close()
+
+	upload := service.MediaUpload{
+		HorseID:      horseID,
+		UserID:       c.GetString("user_id"),
+		Type:         models.MediaType(c.DefaultPostForm("type", string(models.MediaTypePhoto))),
+		OriginalName: fileHeader.Filename,
+		Description:  c.PostForm("description"),
+		Content:      file,
+	}
+	if upload.HealthRecordID, err = optionalUint(c.PostForm("health_record_id"), "health_record_id"); err != nil {
+		c.JSON(http.StatusBadRequest, types.ErrorResponse{Error: err.Error()})
+		return
+	}
+	if upload.PregnancyEventID, err = optionalUint(c.PostForm("pregnancy_event_id"), "pregnancy_event_id"); err != nil {
+		c.JSON(http.StatusBadRequest, types.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	media, err := h.mediaService.Upload(c.Request.Context(), upload)
+	if err != nil {
+		respondMediaError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, media)
+}
+
+// DownloadMedia handles GET /horses/:id/media/:mediaId
+func (h *MediaHandler) DownloadMedia(c *gin.Context) {
+	horseID, ok := h.authorize(c, models.PermissionView)
+	if !ok {
+		return
+	}
+
+	mediaID, err := strconv.ParseUint(c.Param("mediaId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, types.ErrorResponse{Error: "Invalid media ID"})
+		return
+	}
+
+	media, file, err := h.mediaService.Open(c.Request.Context(), horseID, uint(mediaID), c.Query("thumbnail") == "true")
+	if err != nil {
+		respondMediaError(c, err)
+		return
+	}
+	defer file.Close()
+
+	c.Header("Content-Type", media.MimeType)
+	c.Header("Content-Disposition", fmt.Sprintf("inline; filename=%q", media.OriginalName))
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.Status(http.StatusOK)
+	io.Copy(c.Writer, file)
+}
+
+// DeleteMedia handles DELETE /horses/:id/media/:mediaId
+func (h *MediaHandler) DeleteMedia(c *gin.Context) {
+	horseID, ok := h.authorize(c, models.PermissionDelete)
+	if !ok {
+		return
+	}
+
+	mediaID, err := strconv.ParseUint(c.Param("mediaId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, types.ErrorResponse{Error: "Invalid media ID"})
+		return
+	}
+
+	if err := h.mediaService.Delete(c.Request.Context(), horseID, uint(mediaID)); err != nil {
+		respondMediaError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// authorize resolves the horse from the path and checks the caller's access,
+// writing the error response itself when access is refused
+func (h *MediaHandler) authorize(c *gin.Context, permission models.Permission) (uint, bool) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, types.ErrorResponse{Error: "User not authenticated"})
+		return 0, false
+	}
+
+	horseID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, types.ErrorResponse{Error: "Invalid horse ID"})
+		return 0, false
+	}
+
+	if _, err := h.horseService.GetByID(c.Request.Context(), userID, uint(horseID), permission); err != nil {
+		respondHorseError(c, err)
+		return 0, false
+	}
+
+	return uint(horseID), true
+}
+
+func optionalUintQuery(c *gin.Context, key string) (*uint, error) {
+	return optionalUint(c.Query(key), key)
+}
+
+func optionalUint(value, name string) (*uint, error) {
+	if value == "" {
+		return nil, nil
+	}
+	parsed, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s", name)
+	}
+	id := uint(parsed)
+	return &id, nil
+}
+
+// respondMediaError maps media errors to HTTP status codes
+func respondMediaError(c *gin.Context, err error) {
+	var maxBytesErr *http.MaxBytesError
+	switch {
+	case errors.Is(err, models.ErrMediaNotFound):
+		c.JSON(http.StatusNotFound, types.ErrorResponse{Error: err.Error()})
+	case errors.Is(err, models.ErrMediaTooLarge), errors.As(err, &maxBytesErr):
+		c.JSON(http.StatusRequestEntityTooLarge, types.ErrorResponse{Error: err.Error()})
+	case errors.Is(err, models.ErrUnsupportedMediaType):
+		c.JSON(http.StatusUnsupportedMediaType, types.ErrorResponse{Error: err.Error()})
+	case errors.Is(err, models.ErrInvalidAttachment):
+		c.JSON(http.StatusBadRequest, types.ErrorResponse{Error: err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, types.ErrorResponse{Error: err.Error()})
+	}
+}
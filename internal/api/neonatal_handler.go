@@ -0,0 +1,86 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/polyfant/hulta_pregnancy_app/internal/api/types"
+	"github.com/polyfant/hulta_pregnancy_app/internal/models"
+)
+
+// GetNeonatalSummary handles GET /horses/:id/neonatal
+func (h *Handler) GetNeonatalSummary(c *gin.Context) {
+	horse, ok := h.authorizeHorseHealth(c, models.PermissionView)
+	if !ok {
+		return
+	}
+
+	summary, err := h.neonatal.Summary(c.Request.Context(), horse)
+	if err != nil {
+		neonatalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, summary)
+}
+
+// UpdateNeonatalRecord handles PUT /horses/:id/neonatal. The first call
+// starts monitoring; later calls record milestones as they happen. Fields
+// left out are unchanged.
+func (h *Handler) UpdateNeonatalRecord(c *gin.Context) {
+	horse, ok := h.authorizeHorseHealth(c, models.PermissionLog)
+	if !ok {
+		return
+	}
+
+	var update models.NeonatalRecord
+	if err := c.ShouldBindJSON(&update); err != nil {
+		c.JSON(http.StatusBadRequest, types.ErrorResponse{Error: err.Error()})
+		return
+	}
+	update.UserID = c.GetString("user_id")
+
+	record, err := h.neonatal.Update(c.Request.Context(), horse, &update)
+	if err != nil {
+		neonatalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, record)
+}
+
+// AddNeonatalAssessment handles POST /horses/:id/neonatal/assessments
+func (h *Handler) AddNeonatalAssessment(c *gin.Context) {
+	horse, ok := h.authorizeHorseHealth(c, models.PermissionLog)
+	if !ok {
+		return
+	}
+
+	var assessment models.NeonatalAssessment
+	if err := c.ShouldBindJSON(&assessment); err != nil {
+		c.JSON(http.StatusBadRequest, types.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	assessment.ID = 0
+	assessment.UserID = c.GetString("user_id")
+
+	if err := h.neonatal.RecordAssessment(c.Request.Context(), horse, &assessment); err != nil {
+		neonatalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, assessment)
+}
+
+func neonatalError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, models.ErrInvalidNeonatal):
+		c.JSON(http.StatusBadRequest, types.ErrorResponse{Error: err.Error()})
+	case errors.Is(err, models.ErrNeonatalNotFound):
+		c.JSON(http.StatusNotFound, types.ErrorResponse{Error: err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, types.ErrorResponse{Error: err.Error()})
+	}
+}
@@ -0,0 +1,211 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/polyfant/hulta_pregnancy_app/internal/api/types"
+	"github.com/polyfant/hulta_pregnancy_app/internal/models"
+	"github.com/polyfant/hulta_pregnancy_app/internal/service"
+)
+
+type OrganizationHandler struct {
+	orgService service.OrganizationService
+}
+
+func NewOrganizationHandler(orgService service.OrganizationService) *OrganizationHandler {
+	return &OrganizationHandler{
+		orgService: orgService,
+	}
+}
+
+// ListOrganizations handles GET /organizations
+func (h *OrganizationHandler) ListOrganizations(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, types.ErrorResponse{Error: "User not authenticated"})
+		return
+	}
+
+	orgs, err := h.orgService.ListOrganizations(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, types.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, orgs)
+}
+
+// CreateOrganization handles POST /organizations
+func (h *OrganizationHandler) CreateOrganization(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, types.ErrorResponse{Error: "User not authenticated"})
+		return
+	}
+
+	var org models.Organization
+	if err := c.ShouldBindJSON(&org); err != nil {
+		c.JSON(http.StatusBadRequest, types.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	// The creator always becomes the owner
+	org.OwnerID = userID
+	org.Members = nil
+
+	if err := h.orgService.CreateOrganization(c.Request.Context(), &org); err != nil {
+		c.JSON(http.StatusBadRequest, types.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, org)
+}
+
+// GetOrganization handles GET /organizations/:orgId
+func (h *OrganizationHandler) GetOrganization(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, types.ErrorResponse{Error: "User not authenticated"})
+		return
+	}
+
+	orgID, err := strconv.ParseUint(c.Param("orgId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, types.ErrorResponse{Error: "Invalid organization ID"})
+		return
+	}
+
+	org, err := h.orgService.GetOrganization(c.Request.Context(), userID, uint(orgID))
+	if err != nil {
+		respondOrganizationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, org)
+}
+
+// GetMembers handles GET /organizations/:orgId/members
+func (h *OrganizationHandler) GetMembers(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, types.ErrorResponse{Error: "User not authenticated"})
+		return
+	}
+
+	orgID, err := strconv.ParseUint(c.Param("orgId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, types.ErrorResponse{Error: "Invalid organization ID"})
+		return
+	}
+
+	members, err := h.orgService.GetMembers(c.Request.Context(), userID, uint(orgID))
+	if err != nil {
+		respondOrganizationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, members)
+}
+
+// AddMember handles POST /organizations/:orgId/members
+func (h *OrganizationHandler) AddMember(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, types.ErrorResponse{Error: "User not authenticated"})
+		return
+	}
+
+	orgID, err := strconv.ParseUint(c.Param("orgId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, types.ErrorResponse{Error: "Invalid organization ID"})
+		return
+	}
+
+	var member models.OrganizationMember
+	if err := c.ShouldBindJSON(&member); err != nil {
+		c.JSON(http.StatusBadRequest, types.ErrorResponse{Error: err.Error()})
+		return
+	}
+	if member.UserID == "" {
+		c.JSON(http.StatusBadRequest, types.ErrorResponse{Error: "user_id is required"})
+		return
+	}
+
+	member.ID = 0
+	member.OrganizationID = uint(orgID)
+
+	if err := h.orgService.AddMember(c.Request.Context(), userID, &member); err != nil {
+		respondOrganizationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, member)
+}
+
+// UpdateMemberRole handles PUT /organizations/:orgId/members/:userId
+func (h *OrganizationHandler) UpdateMemberRole(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, types.ErrorResponse{Error: "User not authenticated"})
+		return
+	}
+
+	orgID, err := strconv.ParseUint(c.Param("orgId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, types.ErrorResponse{Error: "Invalid organization ID"})
+		return
+	}
+
+	var req struct {
+		Role models.MemberRole `json:"role" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, types.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	member, err := h.orgService.UpdateMemberRole(c.Request.Context(), userID, uint(orgID), c.Param("userId"), req.Role)
+	if err != nil {
+		respondOrganizationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, member)
+}
+
+// RemoveMember handles DELETE /organizations/:orgId/members/:userId
+func (h *OrganizationHandler) RemoveMember(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, types.ErrorResponse{Error: "User not authenticated"})
+		return
+	}
+
+	orgID, err := strconv.ParseUint(c.Param("orgId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, types.ErrorResponse{Error: "Invalid organization ID"})
+		return
+	}
+
+	if err := h.orgService.RemoveMember(c.Request.Context(), userID, uint(orgID), c.Param("userId")); err != nil {
+		respondOrganizationError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// respondOrganizationError maps membership errors to HTTP status codes
+func respondOrganizationError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, models.ErrAccessDenied):
+		c.JSON(http.StatusForbidden, types.ErrorResponse{Error: "Access denied"})
+	case errors.Is(err, models.ErrInvalidRole):
+		c.JSON(http.StatusBadRequest, types.ErrorResponse{Error: err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, types.ErrorResponse{Error: err.Error()})
+	}
+}
@@ -0,0 +1,193 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/polyfant/hulta_pregnancy_app/internal/api/types"
+	"github.com/polyfant/hulta_pregnancy_app/internal/models"
+)
+
+// GetPastures handles GET /pastures
+func (h *Handler) GetPastures(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, types.ErrorResponse{Error: "User not authenticated"})
+		return
+	}
+
+	pastures, err := h.pastures.ListPastures(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, types.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, pastures)
+}
+
+// AddPasture handles POST /pastures
+func (h *Handler) AddPasture(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, types.ErrorResponse{Error: "User not authenticated"})
+		return
+	}
+
+	var pasture models.Pasture
+	if err := c.ShouldBindJSON(&pasture); err != nil {
+		c.JSON(http.StatusBadRequest, types.ErrorResponse{Error: err.Error()})
+		return
+	}
+	pasture.UserID = userID
+
+	if err := h.pastures.CreatePasture(c.Request.Context(), &pasture); err != nil {
+		pastureError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, pasture)
+}
+
+// UpdatePasture handles PUT /pastures/:pastureId
+func (h *Handler) UpdatePasture(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, types.ErrorResponse{Error: "User not authenticated"})
+		return
+	}
+	pastureID, ok := parsePastureID(c)
+	if !ok {
+		return
+	}
+
+	var pasture models.Pasture
+	if err := c.ShouldBindJSON(&pasture); err != nil {
+		c.JSON(http.StatusBadRequest, types.ErrorResponse{Error: err.Error()})
+		return
+	}
+	pasture.ID = pastureID
+	pasture.UserID = userID
+
+	if err := h.pastures.UpdatePasture(c.Request.Context(), &pasture); err != nil {
+		pastureError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, pasture)
+}
+
+// DeletePasture handles DELETE /pastures/:pastureId
+func (h *Handler) DeletePasture(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, types.ErrorResponse{Error: "User not authenticated"})
+		return
+	}
+	pastureID, ok := parsePastureID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.pastures.DeletePasture(c.Request.Context(), userID, pastureID); err != nil {
+		pastureError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GetPastureRotation handles GET /pastures/rotation
+func (h *Handler) GetPastureRotation(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, types.ErrorResponse{Error: "User not authenticated"})
+		return
+	}
+
+	rotation, err := h.pastures.Rotation(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, types.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, rotation)
+}
+
+// GetTurnout handles GET /horses/:id/turnout
+func (h *Handler) GetTurnout(c *gin.Context) {
+	horse, ok := h.authorizeHorseHealth(c, models.PermissionView)
+	if !ok {
+		return
+	}
+
+	turnout, err := h.pastures.GetTurnout(c.Request.Context(), horse)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, types.ErrorResponse{Error: err.Error()})
+		return
+	}
+	if turnout == nil {
+		pastureError(c, models.ErrNotTurnedOut)
+		return
+	}
+
+	c.JSON(http.StatusOK, turnout)
+}
+
+// SetTurnout handles PUT /horses/:id/turnout. The horse is moved to the
+// pasture in the body, on one of its owner's pastures.
+func (h *Handler) SetTurnout(c *gin.Context) {
+	horse, ok := h.authorizeHorseHealth(c, models.PermissionEdit)
+	if !ok {
+		return
+	}
+
+	var turnout models.Turnout
+	if err := c.ShouldBindJSON(&turnout); err != nil {
+		c.JSON(http.StatusBadRequest, types.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	if err := h.pastures.TurnOut(c.Request.Context(), horse, &turnout); err != nil {
+		pastureError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, turnout)
+}
+
+// EndTurnout handles DELETE /horses/:id/turnout, bringing the horse in
+func (h *Handler) EndTurnout(c *gin.Context) {
+	horse, ok := h.authorizeHorseHealth(c, models.PermissionEdit)
+	if !ok {
+		return
+	}
+
+	if err := h.pastures.BringIn(c.Request.Context(), horse); err != nil {
+		pastureError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func parsePastureID(c *gin.Context) (uint, bool) {
+	pastureID, err := strconv.ParseUint(c.Param("pastureId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, types.ErrorResponse{Error: "Invalid pasture ID"})
+		return 0, false
+	}
+	return uint(pastureID), true
+}
+
+func pastureError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, models.ErrInvalidPasture):
+		c.JSON(http.StatusBadRequest, types.ErrorResponse{Error: err.Error()})
+	case errors.Is(err, models.ErrPastureNotFound), errors.Is(err, models.ErrNotTurnedOut):
+		c.JSON(http.StatusNotFound, types.ErrorResponse{Error: err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, types.ErrorResponse{Error: err.Error()})
+	}
+}
@@ -44,7 +44,7 @@ func (h *PregnancyHandler) GetPregnancyStage(c *gin.Context) {
 		return
 	}
 
-	stage, err := h.service.GetPregnancyStage(c.Request.Context(), uint(horseID))
+	stage, err := h.service.GetPregnancyStage(c.Request.Context(), c.GetString("user_id"), uint(horseID))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, types.ErrorResponse{Error: err.Error()})
 		return
@@ -60,7 +60,7 @@ func (h *PregnancyHandler) GetPregnancyStatus(c *gin.Context) {
 		return
 	}
 
-	status, err := h.service.GetStatus(c.Request.Context(), uint(horseID))
+	status, err := h.service.GetStatus(c.Request.Context(), c.GetString("user_id"), uint(horseID))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, types.ErrorResponse{Error: err.Error()})
 		return
@@ -82,7 +82,7 @@ func (h *PregnancyHandler) StartPregnancyTracking(c *gin.Context) {
 		return
 	}
 
-	if err := h.service.StartTracking(c.Request.Context(), uint(horseID), start); err != nil {
+	if err := h.service.StartTracking(c.Request.Context(), c.GetString("user_id"), uint(horseID), start); err != nil {
 		c.JSON(http.StatusInternalServerError, types.ErrorResponse{Error: err.Error()})
 		return
 	}
@@ -106,7 +106,7 @@ func (h *PregnancyHandler) EndPregnancyTracking(c *gin.Context) {
 		return
 	}
 
-	pregnancy, err := h.service.GetPregnancy(c.Request.Context(), uint(horseID))
+	pregnancy, err := h.service.GetPregnancy(c.Request.Context(), c.GetString("user_id"), uint(horseID))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, types.ErrorResponse{Error: err.Error()})
 		return
@@ -115,7 +115,7 @@ func (h *PregnancyHandler) EndPregnancyTracking(c *gin.Context) {
 	pregnancy.Status = data.Status
 	pregnancy.EndDate = &data.Date
 
-	if err := h.service.UpdatePregnancy(c.Request.Context(), pregnancy); err != nil {
+	if err := h.service.UpdatePregnancy(c.Request.Context(), c.GetString("user_id"), pregnancy); err != nil {
 		c.JSON(http.StatusInternalServerError, types.ErrorResponse{Error: err.Error()})
 		return
 	}
@@ -130,7 +130,7 @@ func (h *PregnancyHandler) GetPregnancyEvents(c *gin.Context) {
 		return
 	}
 
-	events, err := h.service.GetPregnancyEvents(c.Request.Context(), uint(horseID))
+	events, err := h.service.GetPregnancyEvents(c.Request.Context(), c.GetString("user_id"), uint(horseID))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, types.ErrorResponse{Error: err.Error()})
 		return
@@ -140,13 +140,19 @@ func (h *PregnancyHandler) GetPregnancyEvents(c *gin.Context) {
 }
 
 func (h *PregnancyHandler) AddPregnancyEvent(c *gin.Context) {
+	horseID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, types.ErrorResponse{Error: "Invalid horse ID"})
+		return
+	}
+
 	var event models.PregnancyEvent
 	if err := c.ShouldBindJSON(&event); err != nil {
 		c.JSON(http.StatusBadRequest, types.ErrorResponse{Error: err.Error()})
 		return
 	}
 
-	if err := h.service.AddPregnancyEvent(c.Request.Context(), &event); err != nil {
+	if err := h.service.AddPregnancyEvent(c.Request.Context(), c.GetString("user_id"), uint(horseID), &event); err != nil {
 		c.JSON(http.StatusInternalServerError, types.ErrorResponse{Error: err.Error()})
 		return
 	}
@@ -162,7 +168,7 @@ func (h *PregnancyHandler) GetFoalingChecklist(c *gin.Context) {
 	}
 
 	// Get existing checklist
-	items, err := h.service.GetPreFoalingChecklist(c.Request.Context(), uint(horseID))
+	items, err := h.service.GetPreFoalingChecklist(c.Request.Context(), c.GetString("user_id"), uint(horseID))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, types.ErrorResponse{Error: err.Error()})
 		return
@@ -171,7 +177,7 @@ func (h *PregnancyHandler) GetFoalingChecklist(c *gin.Context) {
 	// If no checklist exists, initialize with default items
 	if len(items) == 0 {
 		// Get horse to check pregnancy status and due date
-		pregnancy, err := h.service.GetPregnancy(c.Request.Context(), uint(horseID))
+		pregnancy, err := h.service.GetPregnancy(c.Request.Context(), c.GetString("user_id"), uint(horseID))
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, types.ErrorResponse{Error: err.Error()})
 			return
@@ -267,7 +273,7 @@ func (h *PregnancyHandler) GetFoalingChecklist(c *gin.Context) {
 
 		// Add each item to database
 		for _, item := range items {
-			if err := h.service.AddPreFoalingChecklistItem(c.Request.Context(), &item); err != nil {
+			if err := h.service.AddPreFoalingChecklistItem(c.Request.Context(), c.GetString("user_id"), &item); err != nil {
 				c.JSON(http.StatusInternalServerError, types.ErrorResponse{Error: "Failed to initialize checklist"})
 				return
 			}
@@ -348,7 +354,7 @@ func (h *PregnancyHandler) CheckPreFoalingSigns(c *gin.Context) {
 		return
 	}
 
-	signs, err := h.service.GetPreFoalingSigns(c.Request.Context(), uint(horseID))
+	signs, err := h.service.GetPreFoalingSigns(c.Request.Context(), c.GetString("user_id"), uint(horseID))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, types.ErrorResponse{Error: err.Error()})
 		return
@@ -372,7 +378,7 @@ func (h *PregnancyHandler) RecordPreFoalingSign(c *gin.Context) {
 	sign.HorseID = uint(horseID)
 	sign.Date = time.Now()
 
-	if err := h.service.AddPreFoalingSign(c.Request.Context(), &sign); err != nil {
+	if err := h.service.AddPreFoalingSign(c.Request.Context(), c.GetString("user_id"), &sign); err != nil {
 		c.JSON(http.StatusInternalServerError, types.ErrorResponse{Error: err.Error()})
 		return
 	}
@@ -386,7 +392,7 @@ func (h *PregnancyHandler) UpdatePregnancy(c *gin.Context) {
 		return
 	}
 
-	if err := h.service.UpdatePregnancy(c.Request.Context(), &pregnancy); err != nil {
+	if err := h.service.UpdatePregnancy(c.Request.Context(), c.GetString("user_id"), &pregnancy); err != nil {
 		c.JSON(http.StatusInternalServerError, types.ErrorResponse{Error: err.Error()})
 		return
 	}
@@ -401,7 +407,7 @@ func (h *PregnancyHandler) GetPregnancy(c *gin.Context) {
 		return
 	}
 
-	pregnancy, err := h.service.GetPregnancy(c.Request.Context(), uint(horseID))
+	pregnancy, err := h.service.GetPregnancy(c.Request.Context(), c.GetString("user_id"), uint(horseID))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, types.ErrorResponse{Error: err.Error()})
 		return
@@ -41,6 +41,15 @@ func SetupRouter(router *gin.Engine, h *Handler) *gin.Engine {
 	{
 		public.GET("/health", HealthCheck)
 		public.GET("/version", Version)
+
+		// Signed vet links carry their own authorization
+		public.GET("/shared/:token/horses/:id/health", h.vetAccessHandler.GetSharedHealthRecords)
+		public.POST("/shared/:token/horses/:id/health", h.vetAccessHandler.AddSharedHealthRecord)
+		public.GET("/shared/:token/horses/:id/pregnancy", h.vetAccessHandler.GetSharedPregnancy)
+		public.GET("/shared/:token/horses/:id/pregnancy/events", h.vetAccessHandler.GetSharedPregnancyEvents)
+
+		// Signed download links for locally stored files
+		public.GET("/files/*key", h.fileHandler.GetFile)
 	}
 
 	// Protected routes
@@ -54,23 +63,99 @@ func SetupRouter(router *gin.Engine, h *Handler) *gin.Engine {
 		// Horse routes
 		protected.GET("/horses", h.ListHorses)
 		protected.POST("/horses", h.AddHorse)
+		protected.POST("/horses/import", h.ImportHorses)
 		protected.GET("/horses/:id", h.GetHorse)
 		protected.PUT("/horses/:id", h.UpdateHorse)
 		protected.DELETE("/horses/:id", h.DeleteHorse)
+		protected.PUT("/horses/:id/status", h.UpdateHorseStatus)
 
 		// Health routes
 		protected.GET("/horses/:id/health", h.GetHealthRecords)
+		protected.GET("/horses/:id/health/due", h.GetHealthDue)
 		protected.POST("/horses/:id/health", h.AddHealthRecord)
 		protected.PUT("/horses/:id/health/:recordId", h.UpdateHealthRecord)
 		protected.DELETE("/horses/:id/health/:recordId", h.DeleteHealthRecord)
+		protected.GET("/health/overdue", h.ListOverdueHealth)
+
+		// Vital signs routes
+		protected.GET("/horses/:id/vitals", h.GetVitalSigns)
+		protected.POST("/horses/:id/vitals", h.AddVitalSigns)
+
+		// Symptom checker route
+		protected.POST("/horses/:id/symptom-check", h.AssessSymptoms)
+
+		// Parasite control routes
+		protected.GET("/horses/:id/egg-counts", h.GetEggCounts)
+		protected.POST("/horses/:id/egg-counts", h.AddEggCount)
+		protected.GET("/horses/:id/deworming/recommendation", h.GetDewormingRecommendation)
+
+		// Medication routes
+		protected.GET("/horses/:id/treatments", h.GetTreatmentPlans)
+		protected.POST("/horses/:id/treatments", h.AddTreatmentPlan)
+		protected.GET("/horses/:id/treatments/:planId", h.GetTreatmentPlan)
+		protected.POST("/horses/:id/treatments/:planId/doses", h.LogTreatmentDose)
+		protected.POST("/horses/:id/treatments/:planId/stop", h.StopTreatmentPlan)
+		protected.GET("/horses/:id/withdrawal", h.GetWithdrawalStatus)
+
+		// Farrier routes
+		protected.GET("/horses/:id/farrier/visits", h.GetFarrierVisits)
+		protected.POST("/horses/:id/farrier/visits", h.AddFarrierVisit)
+		protected.GET("/horses/:id/farrier/schedule", h.GetFarrierSchedule)
+		protected.PUT("/horses/:id/farrier/schedule", h.SetFarrierSchedule)
+		protected.DELETE("/horses/:id/farrier/schedule", h.DeleteFarrierSchedule)
+
+		// Body condition and nutrition routes
+		protected.GET("/horses/:id/body-condition", h.GetBodyConditionHistory)
+		protected.POST("/horses/:id/body-condition", h.AddBodyConditionScore)
+		protected.GET("/horses/:id/body-condition/pregnancy", h.GetPregnancyBodyCondition)
+		protected.GET("/horses/:id/nutrition", h.GetFeedRecommendation)
+		protected.GET("/horses/:id/lactation", h.GetLactation)
+		protected.GET("/horses/:id/weights", h.GetWeightHistory)
+		protected.POST("/horses/:id/weights", h.AddWeight)
+		protected.GET("/horses/:id/neonatal", h.GetNeonatalSummary)
+		protected.PUT("/horses/:id/neonatal", h.UpdateNeonatalRecord)
+		protected.POST("/horses/:id/neonatal/assessments", h.AddNeonatalAssessment)
+		protected.GET("/horses/:id/weaning", h.GetWeaningProgress)
+		protected.PUT("/horses/:id/weaning", h.RecordWeaning)
+		protected.GET("/horses/:id/weaning/plan", h.GetWeaningPlan)
+		protected.POST("/horses/:id/weaning/checks", h.AddWeaningCheck)
+
+		// Feed routes
+		protected.GET("/feed/types", h.GetFeedTypes)
+		protected.POST("/feed/types", h.AddFeedType)
+		protected.PUT("/feed/types/:typeId/nutrients", h.SetFeedNutrients)
+		protected.GET("/feed/inventory", h.GetFeedInventory)
+		protected.POST("/feed/inventory", h.AddFeedInventory)
+		protected.PUT("/feed/inventory/:itemId", h.UpdateFeedInventory)
+		protected.DELETE("/feed/inventory/:itemId", h.DeleteFeedInventory)
+		protected.GET("/feed/stock", h.GetFeedStock)
+		protected.GET("/feed/forecast", h.GetFeedForecast)
+		protected.GET("/horses/:id/rations", h.GetRations)
+		protected.PUT("/horses/:id/rations", h.SetRations)
+		protected.GET("/horses/:id/rations/evaluation", h.EvaluateRation)
+		protected.GET("/horses/:id/feedings", h.GetFeedings)
+		protected.POST("/horses/:id/feedings", h.LogFeeding)
+
+		// Pasture routes
+		protected.GET("/pastures", h.GetPastures)
+		protected.POST("/pastures", h.AddPasture)
+		protected.GET("/pastures/rotation", h.GetPastureRotation)
+		protected.PUT("/pastures/:pastureId", h.UpdatePasture)
+		protected.DELETE("/pastures/:pastureId", h.DeletePasture)
+		protected.GET("/horses/:id/turnout", h.GetTurnout)
+		protected.PUT("/horses/:id/turnout", h.SetTurnout)
+		protected.DELETE("/horses/:id/turnout", h.EndTurnout)
 
 		// Pregnancy routes
 		protected.GET("/horses/:id/pregnancy", h.GetPregnancy)
 		protected.POST("/horses/:id/pregnancy/start", h.StartPregnancyTracking)
+		protected.POST("/horses/:id/pregnancy/end", h.EndPregnancy)
 		protected.GET("/horses/:id/pregnancy/status", h.GetPregnancyStatus)
 		protected.GET("/horses/:id/pregnancy/events", h.GetPregnancyEvents)
 		protected.POST("/horses/:id/pregnancy/events", h.AddPregnancyEvent)
 		protected.GET("/horses/:id/pregnancy/guidelines", h.GetPregnancyGuidelines)
+		protected.GET("/horses/:id/pregnancy/signs", h.GetPreFoalingSigns)
+		protected.POST("/horses/:id/pregnancy/signs", h.AddPreFoalingSign)
 
 		// Breeding routes
 		protected.GET("/horses/:id/breeding", h.GetBreedingRecords)
@@ -78,10 +163,32 @@ func SetupRouter(router *gin.Engine, h *Handler) *gin.Engine {
 		protected.PUT("/horses/:id/breeding/:recordId", h.UpdateBreedingRecord)
 		protected.DELETE("/horses/:id/breeding/:recordId", h.DeleteBreedingRecord)
 
+		// Media routes
+		protected.GET("/horses/:id/media", h.mediaHandler.ListMedia)
+		protected.POST("/horses/:id/media", h.mediaHandler.UploadMedia)
+		protected.GET("/horses/:id/media/:mediaId", h.mediaHandler.DownloadMedia)
+		protected.DELETE("/horses/:id/media/:mediaId", h.mediaHandler.DeleteMedia)
+
 		// Growth routes
 		protected.POST("/horses/:id/growth", h.growthHandler.RecordGrowthMeasurement)
 		protected.GET("/horses/:id/growth", h.growthHandler.GetFoalGrowthData)
 		protected.GET("/horses/:id/growth/analysis", h.growthHandler.AnalyzeGrowthTrends)
+		protected.GET("/horses/:id/growth/reference", h.growthHandler.GetReferenceCurve)
+
+		// Organization routes
+		protected.GET("/organizations", h.orgHandler.ListOrganizations)
+		protected.POST("/organizations", h.orgHandler.CreateOrganization)
+		protected.GET("/organizations/:orgId", h.orgHandler.GetOrganization)
+		protected.GET("/organizations/:orgId/members", h.orgHandler.GetMembers)
+		protected.POST("/organizations/:orgId/members", h.orgHandler.AddMember)
+		protected.PUT("/organizations/:orgId/members/:userId", h.orgHandler.UpdateMemberRole)
+		protected.DELETE("/organizations/:orgId/members/:userId", h.orgHandler.RemoveMember)
+
+		// Vet access routes
+		protected.GET("/vet-access", h.vetAccessHandler.ListGrants)
+		protected.POST("/vet-access", h.vetAccessHandler.CreateGrant)
+		protected.GET("/vet-access/received", h.vetAccessHandler.ListReceivedGrants)
+		protected.DELETE("/vet-access/:grantId", h.vetAccessHandler.RevokeGrant)
 
 		// Dashboard route
 		protected.GET("/dashboard", h.GetDashboardStats)
@@ -0,0 +1,168 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/polyfant/hulta_pregnancy_app/internal/api/types"
+	"github.com/polyfant/hulta_pregnancy_app/internal/models"
+)
+
+// StopTreatmentRequest explains why a course was ended early
+type StopTreatmentRequest struct {
+	Reason string `json:"reason"`
+}
+
+// GetTreatmentPlans handles GET /horses/:id/treatments
+func (h *Handler) GetTreatmentPlans(c *gin.Context) {
+	horse, ok := h.authorizeHorseHealth(c, models.PermissionView)
+	if !ok {
+		return
+	}
+
+	plans, err := h.treatments.ListPlans(c.Request.Context(), horse)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, types.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, plans)
+}
+
+// AddTreatmentPlan handles POST /horses/:id/treatments
+func (h *Handler) AddTreatmentPlan(c *gin.Context) {
+	horse, ok := h.authorizeHorseHealth(c, models.PermissionLog)
+	if !ok {
+		return
+	}
+
+	var plan models.TreatmentPlan
+	if err := c.ShouldBindJSON(&plan); err != nil {
+		c.JSON(http.StatusBadRequest, types.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	plan.ID = 0
+	plan.UserID = c.GetString("user_id")
+
+	if err := h.treatments.CreatePlan(c.Request.Context(), horse, &plan); err != nil {
+		treatmentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, plan)
+}
+
+// GetTreatmentPlan handles GET /horses/:id/treatments/:planId
+func (h *Handler) GetTreatmentPlan(c *gin.Context) {
+	horse, ok := h.authorizeHorseHealth(c, models.PermissionView)
+	if !ok {
+		return
+	}
+	planID, ok := parsePlanID(c)
+	if !ok {
+		return
+	}
+
+	plan, err := h.treatments.GetPlan(c.Request.Context(), horse, planID)
+	if err != nil {
+		treatmentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, plan)
+}
+
+// LogTreatmentDose handles POST /horses/:id/treatments/:planId/doses
+func (h *Handler) LogTreatmentDose(c *gin.Context) {
+	horse, ok := h.authorizeHorseHealth(c, models.PermissionLog)
+	if !ok {
+		return
+	}
+	planID, ok := parsePlanID(c)
+	if !ok {
+		return
+	}
+
+	var dose models.DoseAdministration
+	if err := c.ShouldBindJSON(&dose); err != nil {
+		c.JSON(http.StatusBadRequest, types.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	dose.ID = 0
+	dose.UserID = c.GetString("user_id")
+
+	if err := h.treatments.LogDose(c.Request.Context(), horse, planID, &dose); err != nil {
+		treatmentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dose)
+}
+
+// StopTreatmentPlan handles POST /horses/:id/treatments/:planId/stop
+func (h *Handler) StopTreatmentPlan(c *gin.Context) {
+	horse, ok := h.authorizeHorseHealth(c, models.PermissionLog)
+	if !ok {
+		return
+	}
+	planID, ok := parsePlanID(c)
+	if !ok {
+		return
+	}
+
+	var req StopTreatmentRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, types.ErrorResponse{Error: err.Error()})
+			return
+		}
+	}
+
+	plan, err := h.treatments.StopPlan(c.Request.Context(), horse, planID, req.Reason)
+	if err != nil {
+		treatmentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, plan)
+}
+
+// GetWithdrawalStatus handles GET /horses/:id/withdrawal
+func (h *Handler) GetWithdrawalStatus(c *gin.Context) {
+	horse, ok := h.authorizeHorseHealth(c, models.PermissionView)
+	if !ok {
+		return
+	}
+
+	status, err := h.treatments.GetWithdrawal(c.Request.Context(), horse)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, types.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, status)
+}
+
+func parsePlanID(c *gin.Context) (uint, bool) {
+	planID, err := strconv.ParseUint(c.Param("planId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, types.ErrorResponse{Error: "Invalid treatment plan ID"})
+		return 0, false
+	}
+	return uint(planID), true
+}
+
+func treatmentError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, models.ErrTreatmentNotFound):
+		c.JSON(http.StatusNotFound, types.ErrorResponse{Error: err.Error()})
+	case errors.Is(err, models.ErrInvalidTreatment):
+		c.JSON(http.StatusBadRequest, types.ErrorResponse{Error: err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, types.ErrorResponse{Error: err.Error()})
+	}
+}
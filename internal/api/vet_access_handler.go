@@ -0,0 +1,244 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/polyfant/hulta_pregnancy_app/internal/api/types"
+	"github.com/polyfant/hulta_pregnancy_app/internal/models"
+	"github.com/polyfant/hulta_pregnancy_app/internal/service"
+)
+
+type VetAccessHandler struct {
+	vetAccessService service.VetAccessService
+	healthService    service.HealthService
+	pregnancyService service.PregnancyService
+}
+
+func NewVetAccessHandler(
+	vetAccessService service.VetAccessService,
+	healthService service.HealthService,
+	pregnancyService service.PregnancyService,
+) *VetAccessHandler {
+	return &VetAccessHandler{
+		vetAccessService: vetAccessService,
+		healthService:    healthService,
+		pregnancyService: pregnancyService,
+	}
+}
+
+// CreateGrantRequest describes a new vet access grant. Leaving vet_user_id
+// empty issues a signed link instead of sharing with a vet account.
+type CreateGrantRequest struct {
+	VetUserID        string             `json:"vet_user_id"`
+	HorseIDs         []uint             `json:"horse_ids" binding:"required,min=1"`
+	AccessLevel      models.AccessLevel `json:"access_level" binding:"required"`
+	IncludeHealth    bool               `json:"include_health"`
+	IncludePregnancy bool               `json:"include_pregnancy"`
+	ExpiresAt        time.Time          `json:"expires_at" binding:"required"`
+}
+
+// CreateGrantResponse returns the stored grant and, for signed links, the one-time token
+type CreateGrantResponse struct {
+	Grant *models.VetAccessGrant `json:"grant"`
+	Token string                 `json:"token,omitempty"`
+}
+
+// CreateGrant handles POST /vet-access
+func (h *VetAccessHandler) CreateGrant(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, types.ErrorResponse{Error: "User not authenticated"})
+		return
+	}
+
+	var req CreateGrantRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, types.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	grant := &models.VetAccessGrant{
+		OwnerID:          userID,
+		AccessLevel:      req.AccessLevel,
+		IncludeHealth:    req.IncludeHealth,
+		IncludePregnancy: req.IncludePregnancy,
+		ExpiresAt:        req.ExpiresAt,
+	}
+	if req.VetUserID != "" {
+		grant.VetUserID = &req.VetUserID
+	}
+
+	token, err := h.vetAccessService.CreateGrant(c.Request.Context(), grant, req.HorseIDs)
+	if err != nil {
+		respondVetAccessError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, CreateGrantResponse{Grant: grant, Token: token})
+}
+
+// ListGrants handles GET /vet-access
+func (h *VetAccessHandler) ListGrants(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, types.ErrorResponse{Error: "User not authenticated"})
+		return
+	}
+
+	grants, err := h.vetAccessService.ListGrants(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, types.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, grants)
+}
+
+// ListReceivedGrants handles GET /vet-access/received
+func (h *VetAccessHandler) ListReceivedGrants(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, types.ErrorResponse{Error: "User not authenticated"})
+		return
+	}
+
+	grants, err := h.vetAccessService.ListReceivedGrants(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, types.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, grants)
+}
+
+// RevokeGrant handles DELETE /vet-access/:grantId
+func (h *VetAccessHandler) RevokeGrant(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, types.ErrorResponse{Error: "User not authenticated"})
+		return
+	}
+
+	grantID, err := strconv.ParseUint(c.Param("grantId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, types.ErrorResponse{Error: "Invalid grant ID"})
+		return
+	}
+
+	if err := h.vetAccessService.RevokeGrant(c.Request.Context(), userID, uint(grantID)); err != nil {
+		respondVetAccessError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GetSharedHealthRecords handles GET /shared/:token/horses/:id/health
+func (h *VetAccessHandler) GetSharedHealthRecords(c *gin.Context) {
+	horseID, grant, ok := h.authorizeLink(c, models.RecordScopeHealth, models.PermissionView)
+	if !ok {
+		return
+	}
+
+	records, err := h.healthService.GetRecords(c.Request.Context(), grant.OwnerID, horseID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, types.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, records)
+}
+
+// AddSharedHealthRecord handles POST /shared/:token/horses/:id/health
+func (h *VetAccessHandler) AddSharedHealthRecord(c *gin.Context) {
+	horseID, grant, ok := h.authorizeLink(c, models.RecordScopeHealth, models.PermissionLog)
+	if !ok {
+		return
+	}
+
+	var record models.HealthRecord
+	if err := c.ShouldBindJSON(&record); err != nil {
+		c.JSON(http.StatusBadRequest, types.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	record.ID = 0
+	record.HorseID = horseID
+	record.UserID = grant.OwnerID
+
+	if err := h.healthService.CreateRecord(c.Request.Context(), grant.OwnerID, &record); err != nil {
+		respondHealthRecordError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, record)
+}
+
+// GetSharedPregnancy handles GET /shared/:token/horses/:id/pregnancy
+func (h *VetAccessHandler) GetSharedPregnancy(c *gin.Context) {
+	horseID, grant, ok := h.authorizeLink(c, models.RecordScopePregnancy, models.PermissionView)
+	if !ok {
+		return
+	}
+
+	pregnancy, err := h.pregnancyService.GetPregnancy(c.Request.Context(), grant.OwnerID, horseID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, types.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, pregnancy)
+}
+
+// GetSharedPregnancyEvents handles GET /shared/:token/horses/:id/pregnancy/events
+func (h *VetAccessHandler) GetSharedPregnancyEvents(c *gin.Context) {
+	horseID, grant, ok := h.authorizeLink(c, models.RecordScopePregnancy, models.PermissionView)
+	if !ok {
+		return
+	}
+
+	events, err := h.pregnancyService.GetPregnancyEvents(c.Request.Context(), grant.OwnerID, horseID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, types.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, events)
+}
+
+// authorizeLink validates the signed link token for the horse in the path,
+// writing the error response itself when access is refused. A link acts for
+// the owner who shared it, so record calls pass grant.OwnerID as the user.
+func (h *VetAccessHandler) authorizeLink(c *gin.Context, scope models.RecordScope, permission models.Permission) (uint, *models.VetAccessGrant, bool) {
+	horseID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, types.ErrorResponse{Error: "Invalid horse ID"})
+		return 0, nil, false
+	}
+
+	grant, err := h.vetAccessService.AuthorizeToken(c.Request.Context(), c.Param("token"), uint(horseID), scope, permission)
+	if err != nil {
+		c.JSON(http.StatusForbidden, types.ErrorResponse{Error: "Access denied"})
+		return 0, nil, false
+	}
+
+	return uint(horseID), grant, true
+}
+
+// respondVetAccessError maps sharing errors to HTTP status codes
+func respondVetAccessError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, models.ErrAccessDenied):
+		c.JSON(http.StatusForbidden, types.ErrorResponse{Error: "Access denied"})
+	case errors.Is(err, models.ErrVetSharingDisabled):
+		c.JSON(http.StatusForbidden, types.ErrorResponse{Error: err.Error()})
+	case errors.Is(err, models.ErrInvalidAccessGrant):
+		c.JSON(http.StatusBadRequest, types.ErrorResponse{Error: err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, types.ErrorResponse{Error: err.Error()})
+	}
+}
@@ -0,0 +1,99 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/polyfant/hulta_pregnancy_app/internal/api/types"
+	"github.com/polyfant/hulta_pregnancy_app/internal/models"
+)
+
+// GetWeaningPlan handles GET /horses/:id/weaning/plan
+func (h *Handler) GetWeaningPlan(c *gin.Context) {
+	horse, ok := h.authorizeHorseHealth(c, models.PermissionView)
+	if !ok {
+		return
+	}
+
+	plan, err := h.weaning.Plan(c.Request.Context(), horse)
+	if err != nil {
+		weaningError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, plan)
+}
+
+// GetWeaningProgress handles GET /horses/:id/weaning
+func (h *Handler) GetWeaningProgress(c *gin.Context) {
+	horse, ok := h.authorizeHorseHealth(c, models.PermissionView)
+	if !ok {
+		return
+	}
+
+	progress, err := h.weaning.Progress(c.Request.Context(), horse)
+	if err != nil {
+		weaningError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, progress)
+}
+
+// RecordWeaning handles PUT /horses/:id/weaning
+func (h *Handler) RecordWeaning(c *gin.Context) {
+	horse, ok := h.authorizeHorseHealth(c, models.PermissionLog)
+	if !ok {
+		return
+	}
+
+	var record models.WeaningRecord
+	if err := c.ShouldBindJSON(&record); err != nil {
+		c.JSON(http.StatusBadRequest, types.ErrorResponse{Error: err.Error()})
+		return
+	}
+	record.UserID = c.GetString("user_id")
+
+	if err := h.weaning.Record(c.Request.Context(), horse, &record); err != nil {
+		weaningError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, record)
+}
+
+// AddWeaningCheck handles POST /horses/:id/weaning/checks
+func (h *Handler) AddWeaningCheck(c *gin.Context) {
+	horse, ok := h.authorizeHorseHealth(c, models.PermissionLog)
+	if !ok {
+		return
+	}
+
+	var check models.WeaningCheck
+	if err := c.ShouldBindJSON(&check); err != nil {
+		c.JSON(http.StatusBadRequest, types.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	check.ID = 0
+	check.UserID = c.GetString("user_id")
+
+	if err := h.weaning.RecordCheck(c.Request.Context(), horse, &check); err != nil {
+		weaningError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, check)
+}
+
+func weaningError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, models.ErrInvalidWeaning):
+		c.JSON(http.StatusBadRequest, types.ErrorResponse{Error: err.Error()})
+	case errors.Is(err, models.ErrWeaningNotFound):
+		c.JSON(http.StatusNotFound, types.ErrorResponse{Error: err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, types.ErrorResponse{Error: err.Error()})
+	}
+}
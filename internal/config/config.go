@@ -11,6 +11,27 @@ import (
 type Config struct {
     Database DatabaseConfig
     Auth0    Auth0Config    `yaml:"auth0"`
+    Storage  StorageConfig  `yaml:"storage"`
+    Health   HealthConfig   `yaml:"health"`
+}
+
+// HealthConfig holds health feature configuration
+type HealthConfig struct {
+    ConditionsPath   string `yaml:"conditions_path"`    // Directory of symptom checker catalogue files
+    GrowthCurvesPath string `yaml:"growth_curves_path"` // Directory of foal growth reference curves
+}
+
+// StorageConfig holds file storage configuration
+type StorageConfig struct {
+    Backend     string `yaml:"backend"`
+    BasePath    string `yaml:"base_path"`
+    PublicURL   string `yaml:"public_url"`
+    SigningKey  string `yaml:"signing_key"`
+    S3Endpoint  string `yaml:"s3_endpoint"`
+    S3Bucket    string `yaml:"s3_bucket"`
+    S3Region    string `yaml:"s3_region"`
+    S3AccessKey string `yaml:"s3_access_key"`
+    S3SecretKey string `yaml:"s3_secret_key"`
 }
 
 type DatabaseConfig struct {
@@ -67,6 +88,21 @@ func Load() (*Config, error) {
             Issuer:   issuer,
             Algorithms: []string{"RS256"},
         },
+        Storage: StorageConfig{
+            Backend:     getEnv("STORAGE_BACKEND", "local"),
+            BasePath:    getEnv("STORAGE_PATH", "./uploads"),
+            PublicURL:   getEnv("STORAGE_PUBLIC_URL", "http://localhost:8080/api/v1/files"),
+            SigningKey:  getEnv("STORAGE_SIGNING_KEY", ""),
+            S3Endpoint:  getEnv("S3_ENDPOINT", ""),
+            S3Bucket:    getEnv("S3_BUCKET", ""),
+            S3Region:    getEnv("S3_REGION", "us-east-1"),
+            S3AccessKey: getEnv("S3_ACCESS_KEY", ""),
+            S3SecretKey: getEnv("S3_SECRET_KEY", ""),
+        },
+        Health: HealthConfig{
+            ConditionsPath:   getEnv("CONDITIONS_PATH", "./config/conditions"),
+            GrowthCurvesPath: getEnv("GROWTH_CURVES_PATH", "./config/growth"),
+        },
     }, nil
 }
 
@@ -1,28 +1,36 @@
 package database
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
-	"sort"
-	"strings"
 	"time"
 
 	"github.com/polyfant/hulta_pregnancy_app/internal/logger"
+	"github.com/polyfant/hulta_pregnancy_app/internal/service/storage"
 )
 
+// backupKeyPrefix is the blob key prefix backups are stored under
+const backupKeyPrefix = "backups/"
+
 type DatabaseBackup struct {
 	dsn          string
 	backupDir    string
 	backupPrefix string
+	blobs        storage.BlobStore
 }
 
-func NewDatabaseBackup(dsn, backupDir string) *DatabaseBackup {
+// NewDatabaseBackup creates a backup job that dumps into backupDir and
+// uploads each dump to blobs, so every backend instance sees the same backups
+func NewDatabaseBackup(dsn, backupDir string, blobs storage.BlobStore) *DatabaseBackup {
 	return &DatabaseBackup{
 		dsn:          dsn,
 		backupDir:    backupDir,
 		backupPrefix: "horse_tracking_db_backup_",
+		blobs:        blobs,
 	}
 }
 
@@ -49,12 +57,46 @@ func (db *DatabaseBackup) Backup() error {
 		return fmt.Errorf("backup failed: %w, output: %s", err, string(output))
 	}
 
+	// Upload the dump; the local copy is only a staging file
+	key, err := db.upload(backupPath, backupFilename)
+	if err != nil {
+		return err
+	}
+	os.Remove(backupPath)
+
 	logger.Info("Database backup completed successfully", 
-		"backup_path", backupPath)
+		"backup_key", key)
 
 	return nil
 }
 
+// upload stores a dump in the blob store and reads it back to verify the checksum
+func (db *DatabaseBackup) upload(backupPath, backupFilename string) (string, error) {
+	ctx := context.Background()
+
+	file, err := os.Open(backupPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to open backup: %w", err)
+	}
+	defer file.Close()
+
+	info, err := db.blobs.Put(ctx, backupKeyPrefix+backupFilename, file, "")
+	if err != nil {
+		return "", fmt.Errorf("failed to upload backup: %w", err)
+	}
+
+	stored, err := db.blobs.Get(ctx, info.Key)
+	if err != nil {
+		return "", fmt.Errorf("failed to verify backup: %w", err)
+	}
+	defer stored.Close()
+	if _, err := io.Copy(io.Discard, stored); err != nil {
+		return "", fmt.Errorf("failed to verify backup: %w", err)
+	}
+
+	return info.Key, nil
+}
+
 func (db *DatabaseBackup) ScheduleBackups(interval time.Duration) {
 	ticker := time.NewTicker(interval)
 	go func() {
@@ -68,28 +110,19 @@ func (db *DatabaseBackup) ScheduleBackups(interval time.Duration) {
 
 // Retention management: Delete old backups
 func (db *DatabaseBackup) ManageBackupRetention(maxBackups int) error {
-	files, err := os.ReadDir(db.backupDir)
+	ctx := context.Background()
+
+	// Keys embed the timestamp, so lexical order is oldest first
+	keys, err := db.blobs.List(ctx, backupKeyPrefix+db.backupPrefix)
 	if err != nil {
 		return err
 	}
 
-	var backupFiles []os.DirEntry
-	for _, file := range files {
-		if !file.IsDir() && strings.HasPrefix(file.Name(), db.backupPrefix) {
-			backupFiles = append(backupFiles, file)
-		}
-	}
-
-	// Sort files by modification time, oldest first
-	sort.Slice(backupFiles, func(i, j int) bool {
-		infoI, _ := backupFiles[i].Info()
-		infoJ, _ := backupFiles[j].Info()
-		return infoI.ModTime().Before(infoJ.ModTime())
-	})
-
 	// Remove old backups
-	for i := 0; i < len(backupFiles)-maxBackups; i++ {
-		os.Remove(filepath.Join(db.backupDir, backupFiles[i].Name()))
+	for i := 0; i < len(keys)-maxBackups; i++ {
+		if err := db.blobs.Delete(ctx, keys[i]); err != nil {
+			logger.Error(err, "Failed to delete old backup")
+		}
 	}
 
 	return nil
@@ -0,0 +1,34 @@
+-- +goose Up
+-- Create organizations table (farms/barns sharing horses between members)
+CREATE TABLE organizations (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    owner_id VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create organization_members table
+CREATE TABLE organization_members (
+    id SERIAL PRIMARY KEY,
+    organization_id INTEGER NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    user_id VARCHAR(255) NOT NULL,
+    role VARCHAR(50) NOT NULL CHECK (role IN ('owner', 'farm_manager', 'staff', 'vet')),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Link horses to an organization
+ALTER TABLE horses ADD COLUMN IF NOT EXISTS organization_id INTEGER REFERENCES organizations(id) ON DELETE SET NULL;
+
+-- Add indexes
+CREATE INDEX idx_organizations_owner ON organizations(owner_id);
+CREATE UNIQUE INDEX idx_org_member ON organization_members(organization_id, user_id);
+CREATE INDEX idx_organization_members_user ON organization_members(user_id);
+CREATE INDEX idx_horses_organization_id ON horses(organization_id);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_horses_organization_id;
+ALTER TABLE horses DROP COLUMN IF EXISTS organization_id;
+DROP TABLE IF EXISTS organization_members;
+DROP TABLE IF EXISTS organizations;
@@ -0,0 +1,39 @@
+-- +goose Up
+-- Health sharing preference enforced by vet access grants
+ALTER TABLE privacy_preferences ADD COLUMN IF NOT EXISTS share_with_vets BOOLEAN DEFAULT FALSE;
+
+-- Create vet_access_grants table (time-limited access for a vet account or signed link)
+CREATE TABLE vet_access_grants (
+    id SERIAL PRIMARY KEY,
+    owner_id VARCHAR(255) NOT NULL,
+    vet_user_id VARCHAR(255),
+    token_hash VARCHAR(64),
+    access_level VARCHAR(20) NOT NULL CHECK (access_level IN ('read', 'read_write')),
+    include_health BOOLEAN NOT NULL DEFAULT FALSE,
+    include_pregnancy BOOLEAN NOT NULL DEFAULT FALSE,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    revoked_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    -- A grant is for a vet account or a signed link, never both
+    CONSTRAINT chk_vet_access_grantee CHECK ((vet_user_id IS NULL) <> (token_hash IS NULL))
+);
+
+-- Create vet_access_grant_horses table
+CREATE TABLE vet_access_grant_horses (
+    id SERIAL PRIMARY KEY,
+    grant_id INTEGER NOT NULL REFERENCES vet_access_grants(id) ON DELETE CASCADE,
+    horse_id INTEGER NOT NULL REFERENCES horses(id) ON DELETE CASCADE
+);
+
+-- Add indexes
+CREATE INDEX idx_vet_access_grants_owner ON vet_access_grants(owner_id);
+CREATE INDEX idx_vet_access_grants_vet ON vet_access_grants(vet_user_id);
+CREATE UNIQUE INDEX idx_vet_access_grants_token ON vet_access_grants(token_hash);
+CREATE INDEX idx_vet_access_grant_horses_grant ON vet_access_grant_horses(grant_id);
+CREATE INDEX idx_vet_access_grant_horses_horse ON vet_access_grant_horses(horse_id);
+
+-- +goose Down
+DROP TABLE IF EXISTS vet_access_grant_horses;
+DROP TABLE IF EXISTS vet_access_grants;
+ALTER TABLE privacy_preferences DROP COLUMN IF EXISTS share_with_vets;
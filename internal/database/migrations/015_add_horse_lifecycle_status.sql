@@ -0,0 +1,23 @@
+-- +goose Up
+-- Explicit lifecycle status so sold or deceased horses keep their history
+ALTER TABLE horses ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'ACTIVE';
+ALTER TABLE horses ADD COLUMN IF NOT EXISTS status_date TIMESTAMP WITH TIME ZONE;
+ALTER TABLE horses ADD COLUMN IF NOT EXISTS status_reason TEXT;
+ALTER TABLE horses ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP WITH TIME ZONE;
+
+ALTER TABLE horses ADD CONSTRAINT chk_horses_status
+    CHECK (status IN ('ACTIVE', 'RETIRED', 'LEASED', 'SOLD', 'DECEASED', 'ARCHIVED'));
+
+-- Horses that were already soft deleted become archived
+UPDATE horses SET status = 'ARCHIVED', status_date = deleted_at WHERE deleted_at IS NOT NULL;
+
+-- Add indexes
+CREATE INDEX idx_horses_status ON horses(status);
+CREATE INDEX IF NOT EXISTS idx_horses_deleted_at ON horses(deleted_at);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_horses_status;
+ALTER TABLE horses DROP CONSTRAINT IF EXISTS chk_horses_status;
+ALTER TABLE horses DROP COLUMN IF EXISTS status_reason;
+ALTER TABLE horses DROP COLUMN IF EXISTS status_date;
+ALTER TABLE horses DROP COLUMN IF EXISTS status;
@@ -0,0 +1,7 @@
+-- +goose Up
+-- Registration numbers identify parents during bulk imports
+ALTER TABLE horses ADD COLUMN IF NOT EXISTS registration_number VARCHAR(100);
+CREATE INDEX idx_horses_registration_number ON horses(registration_number);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_horses_registration_number;
@@ -0,0 +1,26 @@
+-- +goose Up
+-- Create horse_media table (photos and documents stored through FileStorage)
+CREATE TABLE horse_media (
+    id SERIAL PRIMARY KEY,
+    horse_id INTEGER NOT NULL REFERENCES horses(id) ON DELETE CASCADE,
+    user_id VARCHAR(255) NOT NULL,
+    type VARCHAR(20) NOT NULL CHECK (type IN ('PHOTO', 'ULTRASOUND', 'VET_REPORT', 'PASSPORT', 'OTHER')),
+    file_name VARCHAR(255) NOT NULL,
+    original_name VARCHAR(255),
+    mime_type VARCHAR(100) NOT NULL,
+    size BIGINT NOT NULL DEFAULT 0,
+    thumbnail_name VARCHAR(255),
+    description TEXT,
+    health_record_id INTEGER REFERENCES health_records(id) ON DELETE SET NULL,
+    pregnancy_event_id INTEGER REFERENCES pregnancy_events(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Add indexes
+CREATE INDEX idx_horse_media_horse_id ON horse_media(horse_id);
+CREATE INDEX idx_horse_media_health_record_id ON horse_media(health_record_id);
+CREATE INDEX idx_horse_media_pregnancy_event_id ON horse_media(pregnancy_event_id);
+
+-- +goose Down
+DROP TABLE IF EXISTS horse_media;
@@ -0,0 +1,42 @@
+-- +goose Up
+-- Create vital_signs_records table (structured vital signs checks)
+CREATE TABLE vital_signs_records (
+    id SERIAL PRIMARY KEY,
+    horse_id INTEGER NOT NULL REFERENCES horses(id) ON DELETE CASCADE,
+    user_id VARCHAR(255) NOT NULL,
+    recorded_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    temperature DOUBLE PRECISION,
+    heart_rate INTEGER,
+    respiration_rate INTEGER,
+    gut_sounds VARCHAR(20) CHECK (gut_sounds IN ('', 'NORMAL', 'REDUCED', 'INCREASED', 'ABSENT')),
+    capillary_refill DOUBLE PRECISION,
+    mucous_membrane VARCHAR(20) CHECK (mucous_membrane IN ('', 'PINK', 'PALE', 'BRICK_RED', 'YELLOW', 'CYANOTIC')),
+    notes TEXT,
+    category VARCHAR(20),
+    abnormal BOOLEAN NOT NULL DEFAULT FALSE,
+    findings JSONB,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create notifications table
+CREATE TABLE notifications (
+    id SERIAL PRIMARY KEY,
+    type VARCHAR(50) NOT NULL,
+    user_id VARCHAR(255) NOT NULL,
+    horse_id INTEGER,
+    title VARCHAR(255),
+    message TEXT NOT NULL,
+    due_date TIMESTAMP WITH TIME ZONE,
+    priority VARCHAR(10) NOT NULL DEFAULT 'MEDIUM',
+    read BOOLEAN NOT NULL DEFAULT FALSE,
+    completed BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Add indexes
+CREATE INDEX idx_vital_signs_records_horse_id ON vital_signs_records(horse_id, recorded_at);
+CREATE INDEX idx_notifications_user_id ON notifications(user_id, created_at);
+
+-- +goose Down
+DROP TABLE IF EXISTS notifications;
+DROP TABLE IF EXISTS vital_signs_records;
@@ -0,0 +1,12 @@
+-- +goose Up
+-- Structured payloads for typed health records; NULL for description-only records
+ALTER TABLE health_records ADD COLUMN IF NOT EXISTS details JSONB;
+
+-- Add indexes
+CREATE INDEX IF NOT EXISTS idx_health_records_type ON health_records(horse_id, type);
+CREATE INDEX IF NOT EXISTS idx_health_records_details ON health_records USING GIN (details jsonb_path_ops);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_health_records_details;
+DROP INDEX IF EXISTS idx_health_records_type;
+ALTER TABLE health_records DROP COLUMN IF EXISTS details;
@@ -0,0 +1,15 @@
+-- +goose Up
+-- Create health_reminders table (one reminder per horse, care item and due date)
+CREATE TABLE health_reminders (
+    id SERIAL PRIMARY KEY,
+    horse_id INTEGER NOT NULL REFERENCES horses(id) ON DELETE CASCADE,
+    item VARCHAR(100) NOT NULL,
+    due_date DATE NOT NULL,
+    sent_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+-- Add indexes
+CREATE UNIQUE INDEX idx_health_reminder ON health_reminders(horse_id, item, due_date);
+
+-- +goose Down
+DROP TABLE IF EXISTS health_reminders;
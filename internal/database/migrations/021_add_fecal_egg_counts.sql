@@ -0,0 +1,19 @@
+-- +goose Up
+-- Create fecal_egg_counts table (parasite monitoring)
+CREATE TABLE fecal_egg_counts (
+    id SERIAL PRIMARY KEY,
+    horse_id INTEGER NOT NULL REFERENCES horses(id) ON DELETE CASCADE,
+    user_id VARCHAR(255) NOT NULL,
+    sample_date TIMESTAMP WITH TIME ZONE NOT NULL,
+    eggs_per_gram INTEGER NOT NULL CHECK (eggs_per_gram >= 0),
+    parasite_type VARCHAR(20) NOT NULL CHECK (parasite_type IN ('STRONGYLE', 'ASCARID', 'TAPEWORM', 'OTHER')),
+    lab VARCHAR(100),
+    notes TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Add indexes
+CREATE INDEX idx_fecal_egg_counts_horse_id ON fecal_egg_counts(horse_id, sample_date);
+
+-- +goose Down
+DROP TABLE IF EXISTS fecal_egg_counts;
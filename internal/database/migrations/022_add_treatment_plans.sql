@@ -0,0 +1,46 @@
+-- +goose Up
+-- Create treatment_plans table (courses of medication)
+CREATE TABLE treatment_plans (
+    id SERIAL PRIMARY KEY,
+    horse_id INTEGER NOT NULL REFERENCES horses(id) ON DELETE CASCADE,
+    user_id VARCHAR(255) NOT NULL,
+    drug VARCHAR(100) NOT NULL,
+    dose DECIMAL(10,3) NOT NULL CHECK (dose > 0),
+    dose_unit VARCHAR(20) NOT NULL,
+    route VARCHAR(20) NOT NULL CHECK (route IN ('ORAL', 'INTRAVENOUS', 'INTRAMUSCULAR', 'SUBCUTANEOUS', 'TOPICAL', 'INHALED', 'OPHTHALMIC')),
+    frequency_hours INTEGER NOT NULL DEFAULT 0 CHECK (frequency_hours >= 0),
+    start_date TIMESTAMP WITH TIME ZONE NOT NULL,
+    end_date TIMESTAMP WITH TIME ZONE,
+    reason TEXT,
+    prescribed_by VARCHAR(100),
+    competition_withdrawal_hours INTEGER NOT NULL DEFAULT 0 CHECK (competition_withdrawal_hours >= 0),
+    slaughter_withdrawal_days INTEGER NOT NULL DEFAULT 0 CHECK (slaughter_withdrawal_days >= 0),
+    food_chain_excluded BOOLEAN NOT NULL DEFAULT FALSE,
+    status VARCHAR(20) NOT NULL DEFAULT 'ACTIVE' CHECK (status IN ('ACTIVE', 'COMPLETED', 'STOPPED')),
+    stopped_reason TEXT,
+    notes TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create dose_administrations table (per-dose log)
+CREATE TABLE dose_administrations (
+    id SERIAL PRIMARY KEY,
+    plan_id INTEGER NOT NULL REFERENCES treatment_plans(id) ON DELETE CASCADE,
+    horse_id INTEGER NOT NULL REFERENCES horses(id) ON DELETE CASCADE,
+    user_id VARCHAR(255) NOT NULL,
+    administered_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    dose DECIMAL(10,3) NOT NULL CHECK (dose > 0),
+    notes TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Add indexes
+CREATE INDEX idx_treatment_plans_horse_id ON treatment_plans(horse_id, start_date);
+CREATE INDEX idx_treatment_plans_status ON treatment_plans(status);
+CREATE INDEX idx_dose_administrations_plan_id ON dose_administrations(plan_id);
+CREATE INDEX idx_dose_administrations_horse_id ON dose_administrations(horse_id, administered_at);
+
+-- +goose Down
+DROP TABLE IF EXISTS dose_administrations;
+DROP TABLE IF EXISTS treatment_plans;
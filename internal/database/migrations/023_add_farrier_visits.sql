@@ -0,0 +1,40 @@
+-- +goose Up
+-- Create farrier_visits table (hoof care history)
+CREATE TABLE farrier_visits (
+    id SERIAL PRIMARY KEY,
+    horse_id INTEGER NOT NULL REFERENCES horses(id) ON DELETE CASCADE,
+    user_id VARCHAR(255) NOT NULL,
+    visit_date TIMESTAMP WITH TIME ZONE NOT NULL,
+    work VARCHAR(20) NOT NULL CHECK (work IN ('TRIM', 'SHOES', 'RESET', 'REMEDIAL')),
+    hooves JSONB,
+    farrier_name VARCHAR(100),
+    farrier_phone VARCHAR(50),
+    farrier_email VARCHAR(255),
+    cost DECIMAL(10,2) NOT NULL DEFAULT 0 CHECK (cost >= 0),
+    expense_id INTEGER REFERENCES expenses(id) ON DELETE SET NULL,
+    next_visit_date TIMESTAMP WITH TIME ZONE,
+    notes TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create farrier_schedules table (one recurring schedule per horse)
+CREATE TABLE farrier_schedules (
+    id SERIAL PRIMARY KEY,
+    horse_id INTEGER NOT NULL UNIQUE REFERENCES horses(id) ON DELETE CASCADE,
+    user_id VARCHAR(255) NOT NULL,
+    interval_weeks INTEGER NOT NULL CHECK (interval_weeks BETWEEN 2 AND 26),
+    work VARCHAR(20) CHECK (work IN ('TRIM', 'SHOES', 'RESET', 'REMEDIAL')),
+    farrier_name VARCHAR(100),
+    farrier_phone VARCHAR(50),
+    farrier_email VARCHAR(255),
+    notes TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Add indexes
+CREATE INDEX idx_farrier_visits_horse_id ON farrier_visits(horse_id, visit_date);
+
+-- +goose Down
+DROP TABLE IF EXISTS farrier_schedules;
+DROP TABLE IF EXISTS farrier_visits;
@@ -0,0 +1,23 @@
+-- +goose Up
+-- Create body_conditions table (Henneke body condition scores)
+CREATE TABLE body_conditions (
+    id SERIAL PRIMARY KEY,
+    horse_id INTEGER NOT NULL REFERENCES horses(id) ON DELETE CASCADE,
+    user_id VARCHAR(255) NOT NULL,
+    date TIMESTAMP WITH TIME ZONE NOT NULL,
+    score DECIMAL(3,1) NOT NULL CHECK (score BETWEEN 1 AND 9),
+    neck DECIMAL(3,1),
+    withers DECIMAL(3,1),
+    loin DECIMAL(3,1),
+    tailhead DECIMAL(3,1),
+    ribs DECIMAL(3,1),
+    shoulder DECIMAL(3,1),
+    notes TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Add indexes
+CREATE INDEX idx_body_conditions_horse_id ON body_conditions(horse_id, date);
+
+-- +goose Down
+DROP TABLE IF EXISTS body_conditions;
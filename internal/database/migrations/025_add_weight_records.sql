@@ -0,0 +1,27 @@
+-- +goose Up
+-- Create weight_records table (weight history from scales and tape estimates)
+CREATE TABLE weight_records (
+    id SERIAL PRIMARY KEY,
+    horse_id INTEGER NOT NULL REFERENCES horses(id) ON DELETE CASCADE,
+    user_id VARCHAR(255) NOT NULL,
+    date TIMESTAMP WITH TIME ZONE NOT NULL,
+    weight_kg DECIMAL(6,1) NOT NULL CHECK (weight_kg > 0),
+    method VARCHAR(20) NOT NULL CHECK (method IN ('SCALE', 'TAPE', 'VISUAL')),
+    heart_girth_cm DECIMAL(5,1),
+    body_length_cm DECIMAL(5,1),
+    formula VARCHAR(20) CHECK (formula IN ('ADULT', 'PONY', 'YEARLING', 'WEANLING')),
+    notes TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Per-kg dosing: the rate prescribed and the body weight the dose was calculated for
+ALTER TABLE treatment_plans ADD COLUMN IF NOT EXISTS dose_per_kg DECIMAL(10,4);
+ALTER TABLE treatment_plans ADD COLUMN IF NOT EXISTS weight_kg DECIMAL(6,1);
+
+-- Add indexes
+CREATE INDEX idx_weight_records_horse_id ON weight_records(horse_id, date);
+
+-- +goose Down
+ALTER TABLE treatment_plans DROP COLUMN IF EXISTS weight_kg;
+ALTER TABLE treatment_plans DROP COLUMN IF EXISTS dose_per_kg;
+DROP TABLE IF EXISTS weight_records;
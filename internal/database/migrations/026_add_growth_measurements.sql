@@ -0,0 +1,20 @@
+-- +goose Up
+-- Create growth_data table (foal measurements keyed on age in days; expected
+-- values and percentiles come from the reference curves when read)
+CREATE TABLE IF NOT EXISTS growth_data (
+    id SERIAL PRIMARY KEY,
+    foal_id INTEGER NOT NULL REFERENCES horses(id) ON DELETE CASCADE,
+    age_days INTEGER NOT NULL DEFAULT 0 CHECK (age_days >= 0),
+    weight DECIMAL(6,1),
+    height DECIMAL(5,1),
+    cannon_circumference DECIMAL(4,1),
+    measurement_date TIMESTAMP WITH TIME ZONE NOT NULL,
+    notes TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Add indexes
+CREATE INDEX IF NOT EXISTS idx_growth_data_foal_id ON growth_data(foal_id, measurement_date);
+
+-- +goose Down
+DROP TABLE IF EXISTS growth_data;
@@ -0,0 +1,41 @@
+-- +goose Up
+-- Create neonatal_records table (milestones and IgG result of a newborn foal)
+CREATE TABLE neonatal_records (
+    id SERIAL PRIMARY KEY,
+    horse_id INTEGER NOT NULL UNIQUE REFERENCES horses(id) ON DELETE CASCADE,
+    user_id VARCHAR(255) NOT NULL,
+    foaled_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    stood_at TIMESTAMP WITH TIME ZONE,
+    nursed_at TIMESTAMP WITH TIME ZONE,
+    meconium_at TIMESTAMP WITH TIME ZONE,
+    urinated_at TIMESTAMP WITH TIME ZONE,
+    igg_tested_at TIMESTAMP WITH TIME ZONE,
+    igg DECIMAL(6,1) CHECK (igg >= 0),
+    notes TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create neonatal_assessments table (modified APGAR scores)
+CREATE TABLE neonatal_assessments (
+    id SERIAL PRIMARY KEY,
+    horse_id INTEGER NOT NULL REFERENCES horses(id) ON DELETE CASCADE,
+    user_id VARCHAR(255) NOT NULL,
+    assessed_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    heart_rate SMALLINT NOT NULL CHECK (heart_rate BETWEEN 0 AND 2),
+    respiration SMALLINT NOT NULL CHECK (respiration BETWEEN 0 AND 2),
+    muscle_tone SMALLINT NOT NULL CHECK (muscle_tone BETWEEN 0 AND 2),
+    nasal_response SMALLINT NOT NULL CHECK (nasal_response BETWEEN 0 AND 2),
+    score SMALLINT NOT NULL,
+    interpretation VARCHAR(20) NOT NULL CHECK (interpretation IN ('NORMAL', 'MODERATE', 'SEVERE')),
+    notes TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Add indexes
+CREATE INDEX idx_neonatal_records_foaled_at ON neonatal_records(foaled_at);
+CREATE INDEX idx_neonatal_assessments_horse_id ON neonatal_assessments(horse_id, assessed_at);
+
+-- +goose Down
+DROP TABLE IF EXISTS neonatal_assessments;
+DROP TABLE IF EXISTS neonatal_records;
@@ -0,0 +1,32 @@
+-- +goose Up
+-- Create weaning_records table (when, how and with which group a foal was weaned)
+CREATE TABLE weaning_records (
+    id SERIAL PRIMARY KEY,
+    horse_id INTEGER NOT NULL UNIQUE REFERENCES horses(id) ON DELETE CASCADE,
+    user_id VARCHAR(255) NOT NULL,
+    date TIMESTAMP WITH TIME ZONE NOT NULL,
+    method VARCHAR(20) NOT NULL CHECK (method IN ('ABRUPT', 'GRADUAL', 'FENCE_LINE', 'INTERVAL')),
+    weaning_group VARCHAR(100),
+    notes TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create weaning_checks table (stress signs seen after weaning)
+CREATE TABLE weaning_checks (
+    id SERIAL PRIMARY KEY,
+    horse_id INTEGER NOT NULL REFERENCES horses(id) ON DELETE CASCADE,
+    user_id VARCHAR(255) NOT NULL,
+    checked_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    signs JSONB,
+    notes TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Add indexes
+CREATE INDEX idx_weaning_records_date ON weaning_records(date);
+CREATE INDEX idx_weaning_checks_horse_id ON weaning_checks(horse_id, checked_at);
+
+-- +goose Down
+DROP TABLE IF EXISTS weaning_checks;
+DROP TABLE IF EXISTS weaning_records;
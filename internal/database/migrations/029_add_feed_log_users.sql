@@ -0,0 +1,14 @@
+-- +goose Up
+-- Record who logged each feeding
+ALTER TABLE feed_logs ADD COLUMN user_id TEXT;
+
+-- A horse's ration has one line per feed
+CREATE UNIQUE INDEX idx_feed_requirements_horse_feed ON feed_requirements(horse_id, feed_type_id);
+
+-- Feedings draw stock down to zero, never below
+ALTER TABLE feed_inventory ADD CONSTRAINT chk_feed_inventory_quantity CHECK (quantity >= 0);
+
+-- +goose Down
+ALTER TABLE feed_inventory DROP CONSTRAINT IF EXISTS chk_feed_inventory_quantity;
+DROP INDEX IF EXISTS idx_feed_requirements_horse_feed;
+ALTER TABLE feed_logs DROP COLUMN IF EXISTS user_id;
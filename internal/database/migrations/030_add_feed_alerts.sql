@@ -0,0 +1,14 @@
+-- +goose Up
+-- Create feed_alerts table (low-stock and expiry alerts sent, one per user, item and date)
+CREATE TABLE feed_alerts (
+    id SERIAL PRIMARY KEY,
+    user_id TEXT NOT NULL,
+    item VARCHAR(100) NOT NULL,
+    due_date DATE NOT NULL,
+    sent_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE UNIQUE INDEX idx_feed_alert ON feed_alerts(user_id, item, due_date);
+
+-- +goose Down
+DROP TABLE IF EXISTS feed_alerts;
@@ -0,0 +1,28 @@
+-- +goose Up
+-- Nutrient composition of each feed, per kg (or litre) as fed
+ALTER TABLE feed_types ADD COLUMN nutrients JSONB;
+
+-- Who added each feed type, as only they may change its nutrients; the
+-- seeded catalogue has no creator and is only changed by migrations
+ALTER TABLE feed_types ADD COLUMN created_by TEXT;
+ALTER TABLE feed_types ADD CONSTRAINT chk_feed_types_created_by CHECK (created_by IS NULL OR created_by <> '');
+
+-- Typical values for the common feeds; salt adds none of the nutrients
+-- rations are balanced on, and vitamin supplements vary too much to guess
+UPDATE feed_types SET nutrients = '{"digestible_energy_mcal": 1.9, "crude_protein_g": 90, "lysine_g": 3.2, "calcium_g": 4.0, "phosphorus_g": 2.2, "copper_mg": 6, "zinc_mg": 22, "selenium_mg": 0.05}'
+    WHERE name = 'Hay' AND category = 'hay';
+UPDATE feed_types SET nutrients = '{"digestible_energy_mcal": 0.55, "crude_protein_g": 40, "lysine_g": 1.5, "calcium_g": 1.1, "phosphorus_g": 0.8, "copper_mg": 2, "zinc_mg": 6, "selenium_mg": 0.01}'
+    WHERE name = 'Grass' AND category = 'hay';
+UPDATE feed_types SET nutrients = '{"digestible_energy_mcal": 2.85, "crude_protein_g": 115, "lysine_g": 4.3, "calcium_g": 0.8, "phosphorus_g": 3.4, "copper_mg": 5.5, "zinc_mg": 35, "selenium_mg": 0.2}'
+    WHERE name = 'Oats' AND category = 'grain';
+UPDATE feed_types SET nutrients = '{"digestible_energy_mcal": 3.2, "crude_protein_g": 115, "lysine_g": 4.0, "calcium_g": 0.5, "phosphorus_g": 3.5, "copper_mg": 5.5, "zinc_mg": 28, "selenium_mg": 0.2}'
+    WHERE name = 'Barley' AND category = 'grain';
+UPDATE feed_types SET nutrients = '{"digestible_energy_mcal": 0, "crude_protein_g": 0, "lysine_g": 0, "calcium_g": 0, "phosphorus_g": 0, "copper_mg": 0, "zinc_mg": 0, "selenium_mg": 0}'
+    WHERE name = 'Salt block' AND category = 'mineral';
+UPDATE feed_types SET nutrients = '{"digestible_energy_mcal": 0, "crude_protein_g": 0, "lysine_g": 0, "calcium_g": 200, "phosphorus_g": 60, "copper_mg": 1200, "zinc_mg": 4500, "selenium_mg": 25}'
+    WHERE name = 'Mineral supplement' AND category = 'mineral';
+
+-- +goose Down
+ALTER TABLE feed_types DROP CONSTRAINT IF EXISTS chk_feed_types_created_by;
+ALTER TABLE feed_types DROP COLUMN IF EXISTS created_by;
+ALTER TABLE feed_types DROP COLUMN IF EXISTS nutrients;
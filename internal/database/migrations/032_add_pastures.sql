@@ -0,0 +1,37 @@
+-- +goose Up
+-- Create pastures table (paddocks and fields with their grazing rotation)
+CREATE TABLE pastures (
+    id SERIAL PRIMARY KEY,
+    user_id TEXT NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    area_hectares DECIMAL(8,2) NOT NULL CHECK (area_hectares > 0),
+    grass_condition VARCHAR(20) NOT NULL CHECK (grass_condition IN ('POOR', 'FAIR', 'GOOD', 'LUSH')),
+    grazing_days INTEGER NOT NULL CHECK (grazing_days > 0),
+    rest_days INTEGER NOT NULL CHECK (rest_days > 0),
+    notes TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create turnouts table (which horse is out on which pasture, and for how long a day)
+CREATE TABLE turnouts (
+    id SERIAL PRIMARY KEY,
+    horse_id INTEGER NOT NULL REFERENCES horses(id) ON DELETE CASCADE,
+    pasture_id INTEGER NOT NULL REFERENCES pastures(id) ON DELETE CASCADE,
+    hours_per_day DECIMAL(4,1) NOT NULL CHECK (hours_per_day > 0 AND hours_per_day <= 24),
+    start_date TIMESTAMP WITH TIME ZONE NOT NULL,
+    end_date TIMESTAMP WITH TIME ZONE,
+    notes TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Add indexes
+CREATE INDEX idx_pastures_user_id ON pastures(user_id);
+CREATE INDEX idx_turnouts_horse_id ON turnouts(horse_id, start_date);
+CREATE INDEX idx_turnouts_pasture_id ON turnouts(pasture_id, start_date);
+CREATE UNIQUE INDEX idx_turnouts_open ON turnouts(horse_id) WHERE end_date IS NULL;
+
+-- +goose Down
+DROP TABLE IF EXISTS turnouts;
+DROP TABLE IF EXISTS pastures;
@@ -274,6 +274,36 @@ func (_m *PregnancyRepository) GetEvents(ctx context.Context, horseID uint) ([]m
 	return r0, r1
 }
 
+// GetLatestCompleted provides a mock function with given fields: ctx, horseID
+func (_m *PregnancyRepository) GetLatestCompleted(ctx context.Context, horseID uint) (*models.Pregnancy, error) {
+	ret := _m.Called(ctx, horseID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetLatestCompleted")
+	}
+
+	var r0 *models.Pregnancy
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) (*models.Pregnancy, error)); ok {
+		return rf(ctx, horseID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint) *models.Pregnancy); ok {
+		r0 = rf(ctx, horseID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Pregnancy)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, horseID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetPreFoaling provides a mock function with given fields: ctx, horseID
 func (_m *PregnancyRepository) GetPreFoaling(ctx context.Context, horseID uint) ([]models.PreFoalingSign, error) {
 	ret := _m.Called(ctx, horseID)
@@ -2,6 +2,7 @@ package mocks
 
 import (
 	"context"
+	"time"
 
 	"github.com/polyfant/hulta_pregnancy_app/internal/models"
 	"github.com/stretchr/testify/mock"
@@ -57,6 +58,31 @@ func (m *MockHorseRepository) ListByUser(ctx context.Context, userID string) ([]
 	return args.Get(0).([]models.Horse), args.Error(1)
 }
 
+func (m *MockHorseRepository) ListAllByUser(ctx context.Context, userID string) ([]models.Horse, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]models.Horse), args.Error(1)
+}
+
+func (m *MockHorseRepository) ListActive(ctx context.Context) ([]models.Horse, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]models.Horse), args.Error(1)
+}
+
+func (m *MockHorseRepository) UpdateStatus(ctx context.Context, id uint, status models.HorseStatus, date time.Time, reason string) error {
+	args := m.Called(ctx, id, status, date, reason)
+	return args.Error(0)
+}
+
+func (m *MockHorseRepository) CreateBatch(ctx context.Context, items []models.HorseImportItem) error {
+	args := m.Called(ctx, items)
+	return args.Error(0)
+}
+
+func (m *MockHorseRepository) ListByOrganization(ctx context.Context, orgID uint) ([]models.Horse, error) {
+	args := m.Called(ctx, orgID)
+	return args.Get(0).([]models.Horse), args.Error(1)
+}
+
 func (m *MockHorseRepository) GetOffspring(ctx context.Context, horseID uint) ([]models.Horse, error) {
 	args := m.Called(ctx, horseID)
 	return args.Get(0).([]models.Horse), args.Error(1)
@@ -127,6 +153,14 @@ func (m *MockPregnancyRepository) GetCurrentPregnancy(ctx context.Context, horse
 	return args.Get(0).(*models.Pregnancy), args.Error(1)
 }
 
+func (m *MockPregnancyRepository) GetLatestCompleted(ctx context.Context, horseID uint) (*models.Pregnancy, error) {
+	args := m.Called(ctx, horseID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Pregnancy), args.Error(1)
+}
+
 func (m *MockPregnancyRepository) GetPreFoalingChecklist(ctx context.Context, pregnancyID uint) ([]models.PreFoalingChecklistItem, error) {
 	args := m.Called(ctx, pregnancyID)
 	return args.Get(0).([]models.PreFoalingChecklistItem), args.Error(1)
@@ -173,6 +207,16 @@ func (m *MockPregnancyRepository) InitializePreFoalingChecklist(ctx context.Cont
 	return args.Error(0)
 }
 
+func (m *MockPregnancyRepository) UpdatePregnancyStatus(ctx context.Context, horseID uint, isPregnant bool, conceptionDate *time.Time) error {
+	args := m.Called(ctx, horseID, isPregnant, conceptionDate)
+	return args.Error(0)
+}
+
+func (m *MockPregnancyRepository) UpdatePreFoalingChecklistItem(ctx context.Context, item *models.PreFoalingChecklistItem) error {
+	args := m.Called(ctx, item)
+	return args.Error(0)
+}
+
 type MockUserRepository struct {
 	mock.Mock
 }
@@ -230,6 +274,11 @@ func (m *MockHealthRepository) GetRecords(ctx context.Context, horseID uint) ([]
 	return args.Get(0).([]models.HealthRecord), args.Error(1)
 }
 
+func (m *MockHealthRepository) FindRecords(ctx context.Context, horseID uint, filter models.HealthRecordFilter) ([]models.HealthRecord, error) {
+	args := m.Called(ctx, horseID, filter)
+	return args.Get(0).([]models.HealthRecord), args.Error(1)
+}
+
 func (m *MockHealthRepository) UpdateRecord(ctx context.Context, record *models.HealthRecord) error {
 	args := m.Called(ctx, record)
 	return args.Error(0)
@@ -272,4 +321,496 @@ func (m *MockBreedingRepository) UpdateRecord(ctx context.Context, record *model
 func (m *MockBreedingRepository) DeleteRecord(ctx context.Context, id uint) error {
 	args := m.Called(ctx, id)
 	return args.Error(0)
-}
\ No newline at end of file
+}
+
+type MockOrganizationRepository struct {
+	mock.Mock
+}
+
+func (m *MockOrganizationRepository) Create(ctx context.Context, org *models.Organization) error {
+	args := m.Called(ctx, org)
+	return args.Error(0)
+}
+
+func (m *MockOrganizationRepository) GetByID(ctx context.Context, id uint) (*models.Organization, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Organization), args.Error(1)
+}
+
+func (m *MockOrganizationRepository) ListByUser(ctx context.Context, userID string) ([]models.Organization, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]models.Organization), args.Error(1)
+}
+
+func (m *MockOrganizationRepository) GetMember(ctx context.Context, orgID uint, userID string) (*models.OrganizationMember, error) {
+	args := m.Called(ctx, orgID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.OrganizationMember), args.Error(1)
+}
+
+func (m *MockOrganizationRepository) GetMembers(ctx context.Context, orgID uint) ([]models.OrganizationMember, error) {
+	args := m.Called(ctx, orgID)
+	return args.Get(0).([]models.OrganizationMember), args.Error(1)
+}
+
+func (m *MockOrganizationRepository) GetMemberships(ctx context.Context, userID string) ([]models.OrganizationMember, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]models.OrganizationMember), args.Error(1)
+}
+
+func (m *MockOrganizationRepository) AddMember(ctx context.Context, member *models.OrganizationMember) error {
+	args := m.Called(ctx, member)
+	return args.Error(0)
+}
+
+func (m *MockOrganizationRepository) UpdateMember(ctx context.Context, member *models.OrganizationMember) error {
+	args := m.Called(ctx, member)
+	return args.Error(0)
+}
+
+func (m *MockOrganizationRepository) RemoveMember(ctx context.Context, orgID uint, userID string) error {
+	args := m.Called(ctx, orgID, userID)
+	return args.Error(0)
+}
+
+type MockVetAccessRepository struct {
+	mock.Mock
+}
+
+func (m *MockVetAccessRepository) Create(ctx context.Context, grant *models.VetAccessGrant) error {
+	args := m.Called(ctx, grant)
+	return args.Error(0)
+}
+
+func (m *MockVetAccessRepository) GetByID(ctx context.Context, id uint) (*models.VetAccessGrant, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.VetAccessGrant), args.Error(1)
+}
+
+func (m *MockVetAccessRepository) GetByTokenHash(ctx context.Context, tokenHash string) (*models.VetAccessGrant, error) {
+	args := m.Called(ctx, tokenHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.VetAccessGrant), args.Error(1)
+}
+
+func (m *MockVetAccessRepository) ListByOwner(ctx context.Context, ownerID string) ([]models.VetAccessGrant, error) {
+	args := m.Called(ctx, ownerID)
+	return args.Get(0).([]models.VetAccessGrant), args.Error(1)
+}
+
+func (m *MockVetAccessRepository) ListByVet(ctx context.Context, vetUserID string) ([]models.VetAccessGrant, error) {
+	args := m.Called(ctx, vetUserID)
+	return args.Get(0).([]models.VetAccessGrant), args.Error(1)
+}
+
+func (m *MockVetAccessRepository) ListActiveForVetAndHorse(ctx context.Context, vetUserID string, horseID uint, now time.Time) ([]models.VetAccessGrant, error) {
+	args := m.Called(ctx, vetUserID, horseID, now)
+	return args.Get(0).([]models.VetAccessGrant), args.Error(1)
+}
+
+func (m *MockVetAccessRepository) Revoke(ctx context.Context, id uint, revokedAt time.Time) error {
+	args := m.Called(ctx, id, revokedAt)
+	return args.Error(0)
+}
+
+type MockMediaRepository struct {
+	mock.Mock
+}
+
+func (m *MockMediaRepository) Create(ctx context.Context, media *models.HorseMedia) error {
+	args := m.Called(ctx, media)
+	return args.Error(0)
+}
+
+func (m *MockMediaRepository) GetByID(ctx context.Context, id uint) (*models.HorseMedia, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.HorseMedia), args.Error(1)
+}
+
+func (m *MockMediaRepository) ListByHorse(ctx context.Context, horseID uint, filter models.MediaFilter) ([]models.HorseMedia, error) {
+	args := m.Called(ctx, horseID, filter)
+	return args.Get(0).([]models.HorseMedia), args.Error(1)
+}
+
+func (m *MockMediaRepository) Delete(ctx context.Context, id uint) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+type MockVitalSignsRepository struct {
+	mock.Mock
+}
+
+func (m *MockVitalSignsRepository) Create(ctx context.Context, record *models.VitalSignsRecord) error {
+	args := m.Called(ctx, record)
+	return args.Error(0)
+}
+
+func (m *MockVitalSignsRepository) ListByHorse(ctx context.Context, horseID uint, since *time.Time) ([]models.VitalSignsRecord, error) {
+	args := m.Called(ctx, horseID, since)
+	return args.Get(0).([]models.VitalSignsRecord), args.Error(1)
+}
+
+type MockHealthReminderRepository struct {
+	mock.Mock
+}
+
+func (m *MockHealthReminderRepository) MarkSent(ctx context.Context, reminder *models.HealthReminder) (bool, error) {
+	args := m.Called(ctx, reminder)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockHealthReminderRepository) Unmark(ctx context.Context, reminder *models.HealthReminder) error {
+	args := m.Called(ctx, reminder)
+	return args.Error(0)
+}
+
+type MockFecalEggCountRepository struct {
+	mock.Mock
+}
+
+func (m *MockFecalEggCountRepository) Create(ctx context.Context, count *models.FecalEggCount) error {
+	args := m.Called(ctx, count)
+	return args.Error(0)
+}
+
+func (m *MockFecalEggCountRepository) ListByHorse(ctx context.Context, horseID uint) ([]models.FecalEggCount, error) {
+	args := m.Called(ctx, horseID)
+	return args.Get(0).([]models.FecalEggCount), args.Error(1)
+}
+
+type MockTreatmentRepository struct {
+	mock.Mock
+}
+
+func (m *MockTreatmentRepository) CreatePlan(ctx context.Context, plan *models.TreatmentPlan) error {
+	args := m.Called(ctx, plan)
+	return args.Error(0)
+}
+
+func (m *MockTreatmentRepository) GetPlan(ctx context.Context, id uint) (*models.TreatmentPlan, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.TreatmentPlan), args.Error(1)
+}
+
+func (m *MockTreatmentRepository) UpdatePlan(ctx context.Context, plan *models.TreatmentPlan) error {
+	args := m.Called(ctx, plan)
+	return args.Error(0)
+}
+
+func (m *MockTreatmentRepository) ListPlansByHorse(ctx context.Context, horseID uint) ([]models.TreatmentPlan, error) {
+	args := m.Called(ctx, horseID)
+	return args.Get(0).([]models.TreatmentPlan), args.Error(1)
+}
+
+func (m *MockTreatmentRepository) CreateDose(ctx context.Context, dose *models.DoseAdministration) error {
+	args := m.Called(ctx, dose)
+	return args.Error(0)
+}
+
+func (m *MockTreatmentRepository) ListDosesByHorse(ctx context.Context, horseID uint) ([]models.DoseAdministration, error) {
+	args := m.Called(ctx, horseID)
+	return args.Get(0).([]models.DoseAdministration), args.Error(1)
+}
+
+type MockFarrierRepository struct {
+	mock.Mock
+}
+
+func (m *MockFarrierRepository) CreateVisit(ctx context.Context, visit *models.FarrierVisit, expense *models.Expense) error {
+	args := m.Called(ctx, visit, expense)
+	return args.Error(0)
+}
+
+func (m *MockFarrierRepository) ListVisitsByHorse(ctx context.Context, horseID uint) ([]models.FarrierVisit, error) {
+	args := m.Called(ctx, horseID)
+	return args.Get(0).([]models.FarrierVisit), args.Error(1)
+}
+
+func (m *MockFarrierRepository) GetSchedule(ctx context.Context, horseID uint) (*models.FarrierSchedule, error) {
+	args := m.Called(ctx, horseID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.FarrierSchedule), args.Error(1)
+}
+
+func (m *MockFarrierRepository) SaveSchedule(ctx context.Context, schedule *models.FarrierSchedule) error {
+	args := m.Called(ctx, schedule)
+	return args.Error(0)
+}
+
+func (m *MockFarrierRepository) DeleteSchedule(ctx context.Context, horseID uint) error {
+	args := m.Called(ctx, horseID)
+	return args.Error(0)
+}
+
+type MockBodyConditionRepository struct {
+	mock.Mock
+}
+
+func (m *MockBodyConditionRepository) Create(ctx context.Context, record *models.BodyCondition) error {
+	args := m.Called(ctx, record)
+	return args.Error(0)
+}
+
+func (m *MockBodyConditionRepository) ListByHorse(ctx context.Context, horseID uint, since *time.Time) ([]models.BodyCondition, error) {
+	args := m.Called(ctx, horseID, since)
+	return args.Get(0).([]models.BodyCondition), args.Error(1)
+}
+
+type MockWeightRepository struct {
+	mock.Mock
+}
+
+func (m *MockWeightRepository) Create(ctx context.Context, record *models.WeightRecord) error {
+	args := m.Called(ctx, record)
+	return args.Error(0)
+}
+
+func (m *MockWeightRepository) ListByHorse(ctx context.Context, horseID uint, since *time.Time) ([]models.WeightRecord, error) {
+	args := m.Called(ctx, horseID, since)
+	return args.Get(0).([]models.WeightRecord), args.Error(1)
+}
+
+func (m *MockWeightRepository) GetLatest(ctx context.Context, horseID uint) (*models.WeightRecord, error) {
+	args := m.Called(ctx, horseID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.WeightRecord), args.Error(1)
+}
+
+type MockNeonatalRepository struct {
+	mock.Mock
+}
+
+func (m *MockNeonatalRepository) GetByHorse(ctx context.Context, horseID uint) (*models.NeonatalRecord, error) {
+	args := m.Called(ctx, horseID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.NeonatalRecord), args.Error(1)
+}
+
+func (m *MockNeonatalRepository) Save(ctx context.Context, record *models.NeonatalRecord) error {
+	args := m.Called(ctx, record)
+	return args.Error(0)
+}
+
+func (m *MockNeonatalRepository) ListFoaledSince(ctx context.Context, since time.Time) ([]models.NeonatalRecord, error) {
+	args := m.Called(ctx, since)
+	return args.Get(0).([]models.NeonatalRecord), args.Error(1)
+}
+
+func (m *MockNeonatalRepository) CreateAssessment(ctx context.Context, assessment *models.NeonatalAssessment) error {
+	args := m.Called(ctx, assessment)
+	return args.Error(0)
+}
+
+func (m *MockNeonatalRepository) ListAssessments(ctx context.Context, horseID uint) ([]models.NeonatalAssessment, error) {
+	args := m.Called(ctx, horseID)
+	return args.Get(0).([]models.NeonatalAssessment), args.Error(1)
+}
+
+type MockFeedRepository struct {
+	mock.Mock
+}
+
+func (m *MockFeedRepository) ListTypes(ctx context.Context) ([]models.FeedType, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]models.FeedType), args.Error(1)
+}
+
+func (m *MockFeedRepository) GetType(ctx context.Context, id uint) (*models.FeedType, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.FeedType), args.Error(1)
+}
+
+func (m *MockFeedRepository) CreateType(ctx context.Context, feedType *models.FeedType) error {
+	args := m.Called(ctx, feedType)
+	return args.Error(0)
+}
+
+func (m *MockFeedRepository) SaveType(ctx context.Context, feedType *models.FeedType) error {
+	args := m.Called(ctx, feedType)
+	return args.Error(0)
+}
+
+func (m *MockFeedRepository) ListInventory(ctx context.Context, userID string) ([]models.FeedInventoryItem, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]models.FeedInventoryItem), args.Error(1)
+}
+
+func (m *MockFeedRepository) GetInventoryItem(ctx context.Context, id uint) (*models.FeedInventoryItem, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.FeedInventoryItem), args.Error(1)
+}
+
+func (m *MockFeedRepository) SaveInventoryItem(ctx context.Context, item *models.FeedInventoryItem) error {
+	args := m.Called(ctx, item)
+	return args.Error(0)
+}
+
+func (m *MockFeedRepository) DeleteInventoryItem(ctx context.Context, id uint) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockFeedRepository) ListRations(ctx context.Context, horseID uint) ([]models.FeedRation, error) {
+	args := m.Called(ctx, horseID)
+	return args.Get(0).([]models.FeedRation), args.Error(1)
+}
+
+func (m *MockFeedRepository) ReplaceRations(ctx context.Context, horseID uint, rations []models.FeedRation) error {
+	args := m.Called(ctx, horseID, rations)
+	return args.Error(0)
+}
+
+func (m *MockFeedRepository) CreateLog(ctx context.Context, log *models.FeedLog, draws []models.FeedDraw) error {
+	args := m.Called(ctx, log, draws)
+	return args.Error(0)
+}
+
+func (m *MockFeedRepository) ListRationsForHorses(ctx context.Context, horseIDs []uint) ([]models.FeedRation, error) {
+	args := m.Called(ctx, horseIDs)
+	return args.Get(0).([]models.FeedRation), args.Error(1)
+}
+
+func (m *MockFeedRepository) ListInventoryUsers(ctx context.Context) ([]string, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockFeedRepository) MarkAlertSent(ctx context.Context, alert *models.FeedAlert) (bool, error) {
+	args := m.Called(ctx, alert)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockFeedRepository) ListLogs(ctx context.Context, horseID uint, since *time.Time) ([]models.FeedLog, error) {
+	args := m.Called(ctx, horseID, since)
+	return args.Get(0).([]models.FeedLog), args.Error(1)
+}
+
+type MockWeaningRepository struct {
+	mock.Mock
+}
+
+func (m *MockWeaningRepository) GetByHorse(ctx context.Context, horseID uint) (*models.WeaningRecord, error) {
+	args := m.Called(ctx, horseID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.WeaningRecord), args.Error(1)
+}
+
+func (m *MockWeaningRepository) Save(ctx context.Context, record *models.WeaningRecord) error {
+	args := m.Called(ctx, record)
+	return args.Error(0)
+}
+
+func (m *MockWeaningRepository) CreateCheck(ctx context.Context, check *models.WeaningCheck) error {
+	args := m.Called(ctx, check)
+	return args.Error(0)
+}
+
+func (m *MockWeaningRepository) ListChecks(ctx context.Context, horseID uint) ([]models.WeaningCheck, error) {
+	args := m.Called(ctx, horseID)
+	return args.Get(0).([]models.WeaningCheck), args.Error(1)
+}
+
+type MockPastureRepository struct {
+	mock.Mock
+}
+
+func (m *MockPastureRepository) ListPastures(ctx context.Context, userID string) ([]models.Pasture, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]models.Pasture), args.Error(1)
+}
+
+func (m *MockPastureRepository) GetPasture(ctx context.Context, id uint) (*models.Pasture, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Pasture), args.Error(1)
+}
+
+func (m *MockPastureRepository) SavePasture(ctx context.Context, pasture *models.Pasture) error {
+	args := m.Called(ctx, pasture)
+	return args.Error(0)
+}
+
+func (m *MockPastureRepository) DeletePasture(ctx context.Context, id uint) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockPastureRepository) GetTurnout(ctx context.Context, horseID uint) (*models.Turnout, error) {
+	args := m.Called(ctx, horseID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Turnout), args.Error(1)
+}
+
+func (m *MockPastureRepository) StartTurnout(ctx context.Context, turnout *models.Turnout) error {
+	args := m.Called(ctx, turnout)
+	return args.Error(0)
+}
+
+func (m *MockPastureRepository) EndTurnout(ctx context.Context, turnoutID uint, at time.Time) error {
+	args := m.Called(ctx, turnoutID, at)
+	return args.Error(0)
+}
+
+func (m *MockPastureRepository) ListTurnouts(ctx context.Context, pastureIDs []uint, since time.Time) ([]models.Turnout, error) {
+	args := m.Called(ctx, pastureIDs, since)
+	return args.Get(0).([]models.Turnout), args.Error(1)
+}
+
+type MockGrowthRepository struct {
+	mock.Mock
+}
+
+func (m *MockGrowthRepository) CreateGrowthData(ctx context.Context, growthData *models.GrowthData) error {
+	args := m.Called(ctx, growthData)
+	return args.Error(0)
+}
+
+func (m *MockGrowthRepository) GetGrowthDataByFoalID(ctx context.Context, foalID uint) ([]models.GrowthData, error) {
+	args := m.Called(ctx, foalID)
+	return args.Get(0).([]models.GrowthData), args.Error(1)
+}
+
+func (m *MockGrowthRepository) UpdateGrowthData(ctx context.Context, growthData *models.GrowthData) error {
+	args := m.Called(ctx, growthData)
+	return args.Error(0)
+}
+
+func (m *MockGrowthRepository) DeleteGrowthData(ctx context.Context, id uint) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
@@ -0,0 +1,67 @@
+package models
+
+import "time"
+
+// BodyConditionTrend is the direction a horse's condition has been moving
+type BodyConditionTrend string
+
+const (
+	BodyConditionLosing  BodyConditionTrend = "LOSING"
+	BodyConditionStable  BodyConditionTrend = "STABLE"
+	BodyConditionGaining BodyConditionTrend = "GAINING"
+	BodyConditionUnknown BodyConditionTrend = "UNKNOWN" // Too few scores to tell
+)
+
+// BodyCondition is a Henneke body condition score. Each region is scored
+// 1-9; Score is their average. A score taken without the regional
+// breakdown leaves the regions at zero.
+type BodyCondition struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	HorseID   uint      `json:"horse_id" gorm:"index;not null"`
+	UserID    string    `json:"user_id" gorm:"not null"`
+	Date      time.Time `json:"date" gorm:"not null"`
+	Score     float64   `json:"score" gorm:"type:decimal(3,1);not null"`
+	Neck      float64   `json:"neck" gorm:"type:decimal(3,1)"`
+	Withers   float64   `json:"withers" gorm:"type:decimal(3,1)"`
+	Loin      float64   `json:"loin" gorm:"type:decimal(3,1)"`
+	Tailhead  float64   `json:"tailhead" gorm:"type:decimal(3,1)"`
+	Ribs      float64   `json:"ribs" gorm:"type:decimal(3,1)"`
+	Shoulder  float64   `json:"shoulder" gorm:"type:decimal(3,1)"`
+	Notes     string    `json:"notes" gorm:"type:text"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// BodyConditionPoint is one score on a condition chart
+type BodyConditionPoint struct {
+	Date         time.Time `json:"date"`
+	Score        float64   `json:"score"`
+	GestationDay *int      `json:"gestation_day,omitempty"`
+}
+
+// BodyConditionHistory charts a horse's scores against its target range
+type BodyConditionHistory struct {
+	HorseID        uint                 `json:"horse_id"`
+	Points         []BodyConditionPoint `json:"points"`
+	Latest         *float64             `json:"latest,omitempty"`
+	Category       string               `json:"category,omitempty"`
+	Trend          BodyConditionTrend   `json:"trend"`
+	ChangePerMonth float64              `json:"change_per_month"`
+	TargetMin      float64              `json:"target_min"`
+	TargetMax      float64              `json:"target_max"`
+	ConceptionDate *time.Time           `json:"conception_date,omitempty"`
+	Advice         string               `json:"advice,omitempty"`
+}
+
+// FeedRecommendation is the daily ration for a horse with the reasons it
+// differs from the base requirement. WeightKg is zero when the horse has
+// never been weighed.
+type FeedRecommendation struct {
+	HorseID       uint                  `json:"horse_id"`
+	WeightKg      float64               `json:"weight_kg,omitempty"` // Weight the ration was calculated for
+	Requirements  FeedRequirements      `json:"requirements"`
+	BodyCondition *BodyConditionHistory `json:"body_condition,omitempty"`
+	Lactation     *Lactation            `json:"lactation,omitempty"`
+	Turnout       *Turnout              `json:"turnout,omitempty"`
+	Adjustments   []string              `json:"adjustments"`
+	Warnings      []string              `json:"warnings,omitempty"`
+}
@@ -7,4 +7,46 @@ var (
 	ErrInvalidAmount      = errors.New("expense amount must be non-negative")
 	ErrInvalidExpenseType = errors.New("invalid expense type")
 	ErrInvalidFrequency   = errors.New("invalid frequency type")
+
+	// Access errors
+	ErrAccessDenied = errors.New("access denied")
+	ErrInvalidRole  = errors.New("invalid member role")
+
+	// Horse lifecycle errors
+	ErrInvalidHorseStatus = errors.New("invalid horse status")
+	ErrImportNotCSV       = errors.New("only CSV files can be imported; export the spreadsheet as CSV")
+
+	// Media errors
+	ErrUnsupportedMediaType = errors.New("unsupported media type")
+	ErrMediaTooLarge        = errors.New("media file too large")
+	ErrInvalidAttachment    = errors.New("attachment does not belong to this horse")
+	ErrMediaNotFound        = errors.New("media not found")
+
+	// Health errors
+	ErrInvalidHealthRecord  = errors.New("invalid health record")
+	ErrInvalidVitalSigns    = errors.New("invalid vital signs")
+	ErrInvalidEggCount      = errors.New("invalid fecal egg count")
+	ErrInvalidTreatment     = errors.New("invalid treatment plan")
+	ErrTreatmentNotFound    = errors.New("treatment plan not found")
+	ErrInvalidFarrierVisit  = errors.New("invalid farrier visit")
+	ErrInvalidBodyCondition = errors.New("invalid body condition score")
+	ErrNoActivePregnancy    = errors.New("horse has no active pregnancy")
+	ErrInvalidPregnancyEnd  = errors.New("invalid pregnancy outcome")
+	ErrInvalidWeight        = errors.New("invalid weight record")
+	ErrInvalidNeonatal      = errors.New("invalid neonatal record")
+	ErrNeonatalNotFound     = errors.New("neonatal monitoring has not been started for this foal")
+	ErrInvalidWeaning       = errors.New("invalid weaning record")
+	ErrWeaningNotFound      = errors.New("foal has not been weaned")
+	ErrInvalidFeed          = errors.New("invalid feed record")
+	ErrFeedNotFound         = errors.New("feed record not found")
+	ErrInvalidPasture       = errors.New("invalid pasture")
+	ErrPastureNotFound      = errors.New("pasture not found")
+	ErrNotTurnedOut         = errors.New("horse is not turned out")
+
+	// Growth errors
+	ErrInvalidGrowthMeasurement = errors.New("invalid growth measurement")
+
+	// Vet sharing errors
+	ErrVetSharingDisabled = errors.New("sharing with vets is disabled in privacy preferences")
+	ErrInvalidAccessGrant = errors.New("invalid vet access grant")
 )
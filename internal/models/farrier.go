@@ -0,0 +1,92 @@
+package models
+
+import "time"
+
+// FarrierWork is what the farrier did at a visit
+type FarrierWork string
+
+const (
+	FarrierWorkTrim     FarrierWork = "TRIM"
+	FarrierWorkShoes    FarrierWork = "SHOES"    // New shoes
+	FarrierWorkReset    FarrierWork = "RESET"    // Existing shoes taken off, trimmed and refitted
+	FarrierWorkRemedial FarrierWork = "REMEDIAL" // Corrective or therapeutic shoeing
+)
+
+// Hoof identifies one of the four hooves
+type Hoof string
+
+const (
+	HoofLeftFront  Hoof = "LF"
+	HoofRightFront Hoof = "RF"
+	HoofLeftHind   Hoof = "LH"
+	HoofRightHind  Hoof = "RH"
+)
+
+// HoofNote records the state of one hoof at a farrier visit
+type HoofNote struct {
+	Hoof      Hoof   `json:"hoof"`
+	Shod      bool   `json:"shod"`
+	Condition string `json:"condition,omitempty"` // e.g. cracks, thrush, flare, bruising
+	Notes     string `json:"notes,omitempty"`
+}
+
+// FarrierVisit is one visit of the farrier. A visit with a cost is linked to
+// the farrier expense created for it.
+type FarrierVisit struct {
+	ID            uint        `json:"id" gorm:"primaryKey"`
+	HorseID       uint        `json:"horse_id" gorm:"index;not null"`
+	UserID        string      `json:"user_id" gorm:"not null"`
+	VisitDate     time.Time   `json:"visit_date" gorm:"not null"`
+	Work          FarrierWork `json:"work" gorm:"size:20;not null"`
+	Hooves        []HoofNote  `json:"hooves" gorm:"type:jsonb;serializer:json"`
+	FarrierName   string      `json:"farrier_name" gorm:"size:100"`
+	FarrierPhone  string      `json:"farrier_phone" gorm:"size:50"`
+	FarrierEmail  string      `json:"farrier_email" gorm:"size:255"`
+	Cost          float64     `json:"cost" gorm:"type:decimal(10,2)"`
+	ExpenseID     *uint       `json:"expense_id,omitempty"`
+	NextVisitDate *time.Time  `json:"next_visit_date,omitempty"` // Booked with the farrier; overrides the interval
+	Notes         string      `json:"notes" gorm:"type:text"`
+	CreatedAt     time.Time   `json:"created_at"`
+}
+
+// FarrierSchedule is how often a horse sees the farrier and who it usually is
+type FarrierSchedule struct {
+	ID            uint        `json:"id" gorm:"primaryKey"`
+	HorseID       uint        `json:"horse_id" gorm:"uniqueIndex;not null"`
+	UserID        string      `json:"user_id" gorm:"not null"`
+	IntervalWeeks int         `json:"interval_weeks" gorm:"not null"`
+	Work          FarrierWork `json:"work" gorm:"size:20"`
+	FarrierName   string      `json:"farrier_name" gorm:"size:100"`
+	FarrierPhone  string      `json:"farrier_phone" gorm:"size:50"`
+	FarrierEmail  string      `json:"farrier_email" gorm:"size:255"`
+	Notes         string      `json:"notes" gorm:"type:text"`
+	CreatedAt     time.Time   `json:"created_at"`
+	UpdatedAt     time.Time   `json:"updated_at"`
+}
+
+// FarrierScheduleStatus is the horse's schedule, if any, and the next visit due
+type FarrierScheduleStatus struct {
+	Schedule  *FarrierSchedule `json:"schedule"`
+	NextVisit *HealthDueItem   `json:"next_visit"`
+}
+
+func (w FarrierWork) IsValid() bool {
+	switch w {
+	case FarrierWorkTrim, FarrierWorkShoes, FarrierWorkReset, FarrierWorkRemedial:
+		return true
+	}
+	return false
+}
+
+// IsShod reports whether the horse leaves the visit wearing shoes
+func (w FarrierWork) IsShod() bool {
+	return w == FarrierWorkShoes || w == FarrierWorkReset || w == FarrierWorkRemedial
+}
+
+func (h Hoof) IsValid() bool {
+	switch h {
+	case HoofLeftFront, HoofRightFront, HoofLeftHind, HoofRightHind:
+		return true
+	}
+	return false
+}
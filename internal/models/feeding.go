@@ -0,0 +1,168 @@
+package models
+
+import "time"
+
+// Feed categories, as seeded in feed_types
+const (
+	FeedCategoryHay         = "hay"
+	FeedCategoryGrain       = "grain"
+	FeedCategoryConcentrate = "concentrate"
+	FeedCategorySupplement  = "supplement"
+	FeedCategoryMineral     = "mineral"
+)
+
+// Feed units; amounts of a feed are always in its type's unit
+const (
+	FeedUnitKg    = "kg"
+	FeedUnitGram  = "g"
+	FeedUnitLitre = "l"
+	FeedUnitMl    = "ml"
+)
+
+// FeedType is a feed in the shared catalogue. Nutrients is its composition
+// per kg, or per litre, as fed; nil when it is not known. CreatedBy is the
+// user who added it, nil for the seeded feeds.
+type FeedType struct {
+	ID        uint       `json:"id" gorm:"primaryKey"`
+	Name      string     `json:"name" gorm:"size:100;not null"`
+	Category  string     `json:"category" gorm:"size:50;not null"`
+	Unit      string     `json:"unit" gorm:"size:20;not null"`
+	Nutrients *Nutrients `json:"nutrients,omitempty" gorm:"type:jsonb;serializer:json"`
+	Notes     string     `json:"notes" gorm:"type:text"`
+	CreatedBy *string    `json:"created_by,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+// FeedInventoryItem is one lot of feed in a user's store, such as a load
+// of hay or a pallet of bags. Quantity is what is left, in the feed's unit.
+type FeedInventoryItem struct {
+	ID           uint       `json:"id" gorm:"primaryKey"`
+	UserID       string     `json:"user_id" gorm:"index;not null"`
+	FeedTypeID   uint       `json:"feed_type_id" gorm:"index;not null"`
+	FeedType     *FeedType  `json:"feed_type,omitempty" gorm:"foreignKey:FeedTypeID"`
+	Quantity     float64    `json:"quantity" gorm:"not null"`
+	UnitPrice    *float64   `json:"unit_price,omitempty"`
+	PurchaseDate *time.Time `json:"purchase_date,omitempty" gorm:"type:date"`
+	ExpiryDate   *time.Time `json:"expiry_date,omitempty" gorm:"type:date"`
+	Supplier     string     `json:"supplier" gorm:"size:100"`
+	BatchNumber  string     `json:"batch_number" gorm:"size:50"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+func (FeedInventoryItem) TableName() string {
+	return "feed_inventory"
+}
+
+// Expired reports whether the lot is past its expiry date on a day
+func (i *FeedInventoryItem) Expired(on time.Time) bool {
+	return i.ExpiryDate != nil && i.ExpiryDate.Before(on.Truncate(24*time.Hour))
+}
+
+// FeedRation is how much of one feed a horse gets a day. A horse's ration
+// is all of its FeedRations.
+type FeedRation struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	HorseID     uint      `json:"horse_id" gorm:"index;not null"`
+	FeedTypeID  uint      `json:"feed_type_id" gorm:"not null"`
+	FeedType    *FeedType `json:"feed_type,omitempty" gorm:"foreignKey:FeedTypeID"`
+	DailyAmount float64   `json:"daily_amount" gorm:"not null"`
+	Notes       string    `json:"notes" gorm:"type:text"`
+	LastUpdated time.Time `json:"last_updated"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+func (FeedRation) TableName() string {
+	return "feed_requirements"
+}
+
+// FeedLog is one feeding. Shortfall is the part of the amount the store
+// did not hold when it was logged.
+type FeedLog struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	HorseID     uint      `json:"horse_id" gorm:"index;not null"`
+	UserID      string    `json:"user_id"`
+	FeedTypeID  uint      `json:"feed_type_id" gorm:"not null"`
+	FeedType    *FeedType `json:"feed_type,omitempty" gorm:"foreignKey:FeedTypeID"`
+	Amount      float64   `json:"amount" gorm:"not null"`
+	FeedingTime time.Time `json:"feeding_time" gorm:"index;not null"`
+	Notes       string    `json:"notes" gorm:"type:text"`
+	CreatedAt   time.Time `json:"created_at"`
+
+	Shortfall float64 `json:"shortfall,omitempty" gorm:"-"`
+}
+
+// FeedDraw is an amount taken from one inventory lot
+type FeedDraw struct {
+	InventoryID uint
+	Amount      float64
+}
+
+// FeedStockLevel is a user's stock of one feed across all its lots.
+// Quantity counts only lots that have not expired.
+type FeedStockLevel struct {
+	FeedType         FeedType   `json:"feed_type"`
+	Quantity         float64    `json:"quantity"`
+	ExpiredQuantity  float64    `json:"expired_quantity"`
+	ExpiringQuantity float64    `json:"expiring_quantity"` // Expiring within the warning period
+	NextExpiry       *time.Time `json:"next_expiry,omitempty"`
+	Lots             int        `json:"lots"`
+	Value            float64    `json:"value"` // Of the unexpired lots with a unit price
+}
+
+// FeedSupplyStatus is how long a user's stock of a feed will last
+type FeedSupplyStatus string
+
+const (
+	FeedSupplyOK     FeedSupplyStatus = "OK"
+	FeedSupplyLow    FeedSupplyStatus = "LOW"    // Runs out within the low-stock period
+	FeedSupplyOut    FeedSupplyStatus = "OUT"    // Nothing usable left for horses that are fed it
+	FeedSupplyUnused FeedSupplyStatus = "UNUSED" // In stock but in no horse's ration
+)
+
+// FeedSupply projects a user's stock of one feed against the daily amounts
+// in their horses' rations. Lots are used in expiry order, and whatever a
+// lot still holds when it expires is counted as wasted.
+type FeedSupply struct {
+	FeedType       FeedType         `json:"feed_type"`
+	DailyUse       float64          `json:"daily_use"` // Summed over the rations, raised for pregnant mares
+	Horses         int              `json:"horses"`
+	Quantity       float64          `json:"quantity"`                 // In unexpired lots
+	DaysOfSupply   *float64         `json:"days_of_supply,omitempty"` // Nil when nothing uses the feed
+	StockoutDate   *time.Time       `json:"stockout_date,omitempty"`
+	WastedQuantity float64          `json:"wasted_quantity"` // Expected to expire before it is used
+	Status         FeedSupplyStatus `json:"status"`
+}
+
+// FeedPurchase is a suggested order of one feed, enough to cover the
+// forecast period. Supplier and price are taken from the latest lot.
+type FeedPurchase struct {
+	FeedType      FeedType   `json:"feed_type"`
+	Quantity      float64    `json:"quantity"`
+	NeededBy      *time.Time `json:"needed_by,omitempty"`
+	Supplier      string     `json:"supplier,omitempty"`
+	EstimatedCost *float64   `json:"estimated_cost,omitempty"`
+}
+
+// FeedForecast is a user's feed supply over the coming days, with what to
+// buy to get through them
+type FeedForecast struct {
+	UserID    string         `json:"user_id"`
+	Date      time.Time      `json:"date"`
+	Days      int            `json:"days"`
+	Supplies  []FeedSupply   `json:"supplies"`
+	Purchases []FeedPurchase `json:"purchases"`
+	Warnings  []string       `json:"warnings"`
+}
+
+// FeedAlert records a low-stock or expiry alert sent to a user, so the
+// daily check sends each one once
+type FeedAlert struct {
+	ID      uint      `gorm:"primaryKey"`
+	UserID  string    `gorm:"not null;uniqueIndex:idx_feed_alert"`
+	Item    string    `gorm:"size:100;not null;uniqueIndex:idx_feed_alert"`
+	DueDate time.Time `gorm:"type:date;not null;uniqueIndex:idx_feed_alert"`
+	SentAt  time.Time `gorm:"not null"`
+}
@@ -2,26 +2,77 @@ package models
 
 import "time"
 
+// GrowthData is one set of measurements of a foal. AgeDays is counted from
+// the foal's birth date. Measurements not taken are left at zero. The
+// expected values and percentiles come from the breed's reference curve and
+// are filled in when the data is read.
 type GrowthData struct {
-	ID              uint      `json:"id" gorm:"primaryKey"`
-	FoalID          uint      `json:"foalId"`
-	Age             int       `json:"age"`
-	Weight          float64   `json:"weight"`
-	Height          float64   `json:"height"`
-	ExpectedWeight  float64   `json:"expectedWeight"`
-	ExpectedHeight  float64   `json:"expectedHeight"`
-	MeasurementDate time.Time `json:"measurementDate"`
+	ID                  uint      `json:"id" gorm:"primaryKey"`
+	FoalID              uint      `json:"foalId" gorm:"index;not null"`
+	AgeDays             int       `json:"ageDays" gorm:"not null"`
+	Weight              float64   `json:"weight"`              // kg
+	Height              float64   `json:"height"`              // Wither height in cm
+	CannonCircumference float64   `json:"cannonCircumference"` // cm
+	MeasurementDate     time.Time `json:"measurementDate" gorm:"not null"`
+	Notes               string    `json:"notes" gorm:"type:text"`
+	CreatedAt           time.Time `json:"createdAt"`
+
+	ExpectedWeight              float64  `json:"expectedWeight" gorm:"-"`
+	ExpectedHeight              float64  `json:"expectedHeight" gorm:"-"`
+	ExpectedCannonCircumference float64  `json:"expectedCannonCircumference" gorm:"-"`
+	WeightPercentile            *float64 `json:"weightPercentile,omitempty" gorm:"-"`
+	HeightPercentile            *float64 `json:"heightPercentile,omitempty" gorm:"-"`
+	CannonPercentile            *float64 `json:"cannonPercentile,omitempty" gorm:"-"`
+}
+
+// GrowthVelocityStatus classifies average daily gain against the breed reference
+type GrowthVelocityStatus string
+
+const (
+	GrowthVelocitySlow    GrowthVelocityStatus = "SLOW"
+	GrowthVelocityNormal  GrowthVelocityStatus = "NORMAL"
+	GrowthVelocityRapid   GrowthVelocityStatus = "RAPID"
+	GrowthVelocitySpurt   GrowthVelocityStatus = "SPURT"   // Beyond the breed's limit; a risk factor for DOD
+	GrowthVelocityUnknown GrowthVelocityStatus = "UNKNOWN" // Outside the reference ages
+)
+
+// GrowthInterval is the average daily gain between two weighings
+type GrowthInterval struct {
+	FromDate          time.Time            `json:"fromDate"`
+	ToDate            time.Time            `json:"toDate"`
+	FromAgeDays       int                  `json:"fromAgeDays"`
+	ToAgeDays         int                  `json:"toAgeDays"`
+	AverageDailyGain  float64              `json:"averageDailyGain"`            // kg/day
+	ExpectedDailyGain float64              `json:"expectedDailyGain,omitempty"` // kg/day along the breed median
+	Ratio             float64              `json:"ratio,omitempty"`             // Actual over expected gain
+	Status            GrowthVelocityStatus `json:"status"`
+}
+
+// GrowthVelocity is a foal's rate of gain over time and what to do about it
+type GrowthVelocity struct {
+	FoalID      uint                 `json:"foalId"`
+	Intervals   []GrowthInterval     `json:"intervals"`
+	Current     *GrowthInterval      `json:"current,omitempty"`
+	Status      GrowthVelocityStatus `json:"status"`
+	Suggestions []string             `json:"suggestions"`
+}
+
+// MatureEstimate is the projected adult size of one measure with a 90%
+// band. The estimate tracks the horse's breed percentile and, with enough
+// measurements, averages in a growth curve fitted to them.
+type MatureEstimate struct {
+	Expected   float64  `json:"expected"`
+	Lower      float64  `json:"lower"`
+	Upper      float64  `json:"upper"`
+	Percentile float64  `json:"percentile"`       // Breed percentile the horse is tracking
+	Fitted     *float64 `json:"fitted,omitempty"` // Asymptote of the curve fitted to the horse's own measurements
 }
 
-type BodyCondition struct {
-	ID           uint      `json:"id" gorm:"primaryKey"`
-	FoalID       uint      `json:"foalId"`
-	Score        float64   `json:"score"`
-	Neck         float64   `json:"neck"`
-	Withers      float64   `json:"withers"`
-	Loin         float64   `json:"loin"`
-	Tailhead     float64   `json:"tailhead"`
-	Ribs         float64   `json:"ribs"`
-	Shoulder     float64   `json:"shoulder"`
-	LastUpdated  time.Time `json:"lastUpdated"`
+// MatureProjection is a young horse's projected adult weight (kg) and
+// wither height (cm)
+type MatureProjection struct {
+	MatureAgeDays int             `json:"matureAgeDays"`
+	MatureDate    time.Time       `json:"matureDate"`
+	Weight        *MatureEstimate `json:"weight,omitempty"`
+	Height        *MatureEstimate `json:"height,omitempty"`
 }
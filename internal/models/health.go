@@ -9,6 +9,7 @@ type HealthRecord struct {
     Type        string    `gorm:"size:50"`
     Date        time.Time
     Description string
+    Details     *HealthRecordDetails `json:"Details,omitempty" gorm:"type:jsonb;serializer:json"`
     CreatedAt   time.Time
     UpdatedAt   time.Time
-} 
\ No newline at end of file
+}
@@ -0,0 +1,219 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// VaccinationRoute is how a vaccine was administered
+type VaccinationRoute string
+
+const (
+	VaccinationRouteIntramuscular VaccinationRoute = "INTRAMUSCULAR"
+	VaccinationRouteIntranasal    VaccinationRoute = "INTRANASAL"
+	VaccinationRouteSubcutaneous  VaccinationRoute = "SUBCUTANEOUS"
+	VaccinationRouteOral          VaccinationRoute = "ORAL"
+)
+
+// InjurySeverity grades an injury
+type InjurySeverity string
+
+const (
+	InjurySeverityMinor    InjurySeverity = "MINOR"
+	InjurySeverityModerate InjurySeverity = "MODERATE"
+	InjurySeveritySevere   InjurySeverity = "SEVERE"
+)
+
+// HealthRecordDetails holds the structured payload of a typed health record.
+// Exactly one block is set, matching the record's Type. Records created before
+// typed payloads existed have no details and rely on Description alone.
+type HealthRecordDetails struct {
+	VetVisit    *VetVisitDetails    `json:"vet_visit,omitempty"`
+	Vaccination *VaccinationDetails `json:"vaccination,omitempty"`
+	Deworming   *DewormingDetails   `json:"deworming,omitempty"`
+	Dental      *DentalDetails      `json:"dental,omitempty"`
+	Injury      *InjuryDetails      `json:"injury,omitempty"`
+}
+
+type VetVisitDetails struct {
+	VetName      string     `json:"vet_name"`
+	Reason       string     `json:"reason"`
+	Diagnosis    string     `json:"diagnosis"`
+	FollowUpDate *time.Time `json:"follow_up_date,omitempty"`
+}
+
+type VaccinationDetails struct {
+	Product      string           `json:"product"`
+	Manufacturer string           `json:"manufacturer"`
+	LotNumber    string           `json:"lot_number"`
+	Route        VaccinationRoute `json:"route"`
+	NextDueDate  *time.Time       `json:"next_due_date,omitempty"`
+}
+
+type DewormingDetails struct {
+	ActiveIngredient string  `json:"active_ingredient"`
+	Product          string  `json:"product"`
+	Dose             float64 `json:"dose"`
+	DoseUnit         string  `json:"dose_unit"`
+	WeightKg         float64 `json:"weight_kg,omitempty"` // Body weight the dose was calculated for
+}
+
+type DentalDetails struct {
+	Findings      []string   `json:"findings"`
+	Floated       bool       `json:"floated"`
+	Sedated       bool       `json:"sedated"`
+	NextCheckDate *time.Time `json:"next_check_date,omitempty"`
+}
+
+type InjuryDetails struct {
+	Location  string         `json:"location"`
+	Severity  InjurySeverity `json:"severity"`
+	Cause     string         `json:"cause"`
+	Treatment string         `json:"treatment"`
+}
+
+// HealthRecordFilter narrows a health record query. Fields matches payload
+// fields of the filtered Type by their JSON name, e.g. "lot_number".
+type HealthRecordFilter struct {
+	Type   HealthRecordType
+	From   *time.Time
+	To     *time.Time
+	Fields map[string]string
+}
+
+// healthDetailFields lists the payload fields that can be queried per record type
+var healthDetailFields = map[HealthRecordType]struct {
+	key    string
+	fields []string
+}{
+	HealthRecordTypeVetVisit:    {"vet_visit", []string{"vet_name", "reason", "diagnosis"}},
+	HealthRecordTypeVaccination: {"vaccination", []string{"product", "manufacturer", "lot_number", "route"}},
+	HealthRecordTypeDeworming:   {"deworming", []string{"active_ingredient", "product", "dose_unit"}},
+	HealthRecordTypeInjury:      {"injury", []string{"location", "severity", "cause"}},
+}
+
+// DetailFieldPath returns the JSON object key and field name used to query a
+// payload field, or false if the field is not queryable for the record type
+func DetailFieldPath(recordType HealthRecordType, field string) (string, string, bool) {
+	entry, ok := healthDetailFields[recordType]
+	if !ok {
+		return "", "", false
+	}
+	for _, f := range entry.fields {
+		if f == field {
+			return entry.key, f, true
+		}
+	}
+	return "", "", false
+}
+
+func (t HealthRecordType) IsValid() bool {
+	switch t {
+	case HealthRecordTypeVetVisit, HealthRecordTypeVaccination, HealthRecordTypeDeworming,
+		HealthRecordTypeDental, HealthRecordTypeInjury, HealthRecordTypeOther:
+		return true
+	}
+	return false
+}
+
+func (r VaccinationRoute) IsValid() bool {
+	switch r {
+	case VaccinationRouteIntramuscular, VaccinationRouteIntranasal, VaccinationRouteSubcutaneous, VaccinationRouteOral:
+		return true
+	}
+	return false
+}
+
+func (s InjurySeverity) IsValid() bool {
+	switch s {
+	case InjurySeverityMinor, InjurySeverityModerate, InjurySeveritySevere:
+		return true
+	}
+	return false
+}
+
+// ValidateDetails checks the structured payload against the record type.
+// Records without details are always valid so description-only records keep working.
+func (r *HealthRecord) ValidateDetails() error {
+	if r.Details == nil {
+		return nil
+	}
+
+	recordType := HealthRecordType(r.Type)
+	if !recordType.IsValid() {
+		return fmt.Errorf("%w: details require a known type, got %q", ErrInvalidHealthRecord, r.Type)
+	}
+
+	d := r.Details
+	blocks := map[HealthRecordType]bool{
+		HealthRecordTypeVetVisit:    d.VetVisit != nil,
+		HealthRecordTypeVaccination: d.Vaccination != nil,
+		HealthRecordTypeDeworming:   d.Deworming != nil,
+		HealthRecordTypeDental:      d.Dental != nil,
+		HealthRecordTypeInjury:      d.Injury != nil,
+	}
+	for blockType, set := range blocks {
+		if set && blockType != recordType {
+			return fmt.Errorf("%w: %s details do not match type %s", ErrInvalidHealthRecord, strings.ToLower(string(blockType)), r.Type)
+		}
+	}
+
+	switch recordType {
+	case HealthRecordTypeVetVisit:
+		if d.VetVisit == nil {
+			return fmt.Errorf("%w: vet_visit details are missing", ErrInvalidHealthRecord)
+		}
+		if err := r.checkFutureDate("follow_up_date", d.VetVisit.FollowUpDate); err != nil {
+			return err
+		}
+	case HealthRecordTypeVaccination:
+		v := d.Vaccination
+		if v == nil || strings.TrimSpace(v.Product) == "" {
+			return fmt.Errorf("%w: vaccination product is required", ErrInvalidHealthRecord)
+		}
+		if v.Route != "" && !v.Route.IsValid() {
+			return fmt.Errorf("%w: unknown vaccination route %q", ErrInvalidHealthRecord, v.Route)
+		}
+		if err := r.checkFutureDate("next_due_date", v.NextDueDate); err != nil {
+			return err
+		}
+	case HealthRecordTypeDeworming:
+		v := d.Deworming
+		if v == nil || strings.TrimSpace(v.ActiveIngredient) == "" {
+			return fmt.Errorf("%w: deworming active_ingredient is required", ErrInvalidHealthRecord)
+		}
+		if v.Dose <= 0 || strings.TrimSpace(v.DoseUnit) == "" {
+			return fmt.Errorf("%w: deworming dose and dose_unit are required", ErrInvalidHealthRecord)
+		}
+		if v.WeightKg < 0 {
+			return fmt.Errorf("%w: weight_kg cannot be negative", ErrInvalidHealthRecord)
+		}
+	case HealthRecordTypeDental:
+		if d.Dental == nil {
+			return fmt.Errorf("%w: dental details are missing", ErrInvalidHealthRecord)
+		}
+		if err := r.checkFutureDate("next_check_date", d.Dental.NextCheckDate); err != nil {
+			return err
+		}
+	case HealthRecordTypeInjury:
+		v := d.Injury
+		if v == nil || strings.TrimSpace(v.Location) == "" {
+			return fmt.Errorf("%w: injury location is required", ErrInvalidHealthRecord)
+		}
+		if !v.Severity.IsValid() {
+			return fmt.Errorf("%w: injury severity must be MINOR, MODERATE or SEVERE", ErrInvalidHealthRecord)
+		}
+	case HealthRecordTypeOther:
+		return fmt.Errorf("%w: OTHER records have no structured details", ErrInvalidHealthRecord)
+	}
+	return nil
+}
+
+// checkFutureDate makes sure a follow-up date falls after the record date
+func (r *HealthRecord) checkFutureDate(field string, date *time.Time) error {
+	if date != nil && !r.Date.IsZero() && date.Before(r.Date) {
+		return fmt.Errorf("%w: %s must be after the record date", ErrInvalidHealthRecord, field)
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package models
+
+import "time"
+
+// HealthDueCategory groups preventive care items
+type HealthDueCategory string
+
+const (
+	HealthDueVaccination HealthDueCategory = "VACCINATION"
+	HealthDueDeworming   HealthDueCategory = "DEWORMING"
+	HealthDueDental      HealthDueCategory = "DENTAL"
+	HealthDueFarrier     HealthDueCategory = "FARRIER"
+)
+
+// HealthDueStatus describes how close a care item is to its due date
+type HealthDueStatus string
+
+const (
+	HealthDueUpcoming HealthDueStatus = "UPCOMING"
+	HealthDueSoon     HealthDueStatus = "DUE_SOON"
+	HealthDueOverdue  HealthDueStatus = "OVERDUE"
+)
+
+// HealthDueItem is the next due date for one vaccine, deworming, dental check or farrier visit
+type HealthDueItem struct {
+	HorseID   uint              `json:"horse_id"`
+	HorseName string            `json:"horse_name"`
+	Category  HealthDueCategory `json:"category"`
+	Name      string            `json:"name"`
+	LastDate  *time.Time        `json:"last_date,omitempty"`
+	DueDate   time.Time         `json:"due_date"`
+	Status    HealthDueStatus   `json:"status"`
+	Reason    string            `json:"reason"`
+}
+
+// HealthReminder records that a reminder was sent for a due date, so the
+// scheduler notifies once per due date
+type HealthReminder struct {
+	ID      uint      `gorm:"primaryKey"`
+	HorseID uint      `gorm:"not null;uniqueIndex:idx_health_reminder"`
+	Item    string    `gorm:"size:100;not null;uniqueIndex:idx_health_reminder"`
+	DueDate time.Time `gorm:"type:date;not null;uniqueIndex:idx_health_reminder"`
+	SentAt  time.Time `gorm:"not null"`
+}
@@ -10,16 +10,45 @@ const (
 	GenderGelding  Gender = "GELDING"
 )
 
+// HorseStatus represents where a horse is in its lifecycle
+type HorseStatus string
+
+const (
+	HorseStatusActive   HorseStatus = "ACTIVE"
+	HorseStatusRetired  HorseStatus = "RETIRED"  // Still owned and cared for, no longer worked or bred
+	HorseStatusLeased   HorseStatus = "LEASED"   // Still owned, temporarily with a lessee
+	HorseStatusSold     HorseStatus = "SOLD"
+	HorseStatusDeceased HorseStatus = "DECEASED"
+	HorseStatusArchived HorseStatus = "ARCHIVED" // Removed from the stable list by the owner
+)
+
+// ActiveHorseStatuses are the statuses of horses still in the owner's care.
+// Horses in any other status are hidden from lists and dashboard counts but
+// kept for pedigrees and reports.
+var ActiveHorseStatuses = []HorseStatus{HorseStatusActive, HorseStatusRetired, HorseStatusLeased}
+
+// HorseStatusChange describes a lifecycle transition
+type HorseStatusChange struct {
+	Status HorseStatus `json:"status" binding:"required"`
+	Date   *time.Time  `json:"date"`
+	Reason string      `json:"reason"`
+}
+
 type Horse struct {
 	ID             uint       `json:"id" gorm:"primaryKey"`
 	UserID         string     `json:"user_id"`
+	OrganizationID *uint      `json:"organization_id" gorm:"index"`
 	Name           string     `json:"name"`
+	Status         HorseStatus `json:"status" gorm:"type:varchar(20);default:ACTIVE;index"`
+	StatusDate     *time.Time  `json:"status_date,omitempty"`
+	StatusReason   string      `json:"status_reason,omitempty" gorm:"type:text"`
 	Breed          string     `gorm:"type:varchar(100)"`
 	Gender         Gender     `json:"gender"`
 	BirthDate      time.Time  `json:"birth_date"`
 	Weight         float64
 	Height         float64
 	Color          string     `gorm:"size:100"`
+	RegistrationNumber string `json:"registration_number" gorm:"size:100;index"`
 	IsPregnant     bool       `gorm:"default:false"`
 	ConceptionDate *time.Time
 	MotherId       *uint
@@ -105,6 +134,30 @@ func (h *Horse) ExpectedFoalingDate() *time.Time {
 	return &foalingDate
 }
 
+// IsValid checks whether the status is a known lifecycle status
+func (s HorseStatus) IsValid() bool {
+	switch s {
+	case HorseStatusActive, HorseStatusRetired, HorseStatusLeased,
+		HorseStatusSold, HorseStatusDeceased, HorseStatusArchived:
+		return true
+	}
+	return false
+}
+
+// IsActive reports whether the horse is still in the owner's care.
+// Horses created before lifecycle tracking have an empty status and count as active.
+func (h *Horse) IsActive() bool {
+	if h.Status == "" {
+		return true
+	}
+	for _, s := range ActiveHorseStatuses {
+		if h.Status == s {
+			return true
+		}
+	}
+	return false
+}
+
 func (h *Horse) ValidateGender() bool {
 	return h.Gender == GenderMare || h.Gender == GenderStallion || h.Gender == GenderGelding
 }
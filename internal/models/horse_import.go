@@ -0,0 +1,31 @@
+package models
+
+// HorseImportItem is a horse ready to be stored by a bulk import. Parents that
+// are themselves part of the import are referenced by their index in the batch
+// because they have no ID until the batch is committed.
+type HorseImportItem struct {
+	Horse     *Horse
+	MotherRef *int
+	FatherRef *int
+}
+
+// HorseImportError describes a problem with a single CSV row
+type HorseImportError struct {
+	Row     int    `json:"row"`
+	Column  string `json:"column,omitempty"`
+	Message string `json:"message"`
+}
+
+// HorseImportResult summarises a bulk import or dry run
+type HorseImportResult struct {
+	DryRun   bool               `json:"dry_run"`
+	Rows     int                `json:"rows"`
+	Imported int                `json:"imported"`
+	Errors   []HorseImportError `json:"errors"`
+	Horses   []Horse            `json:"horses"`
+}
+
+// HasErrors reports whether any row failed validation
+func (r *HorseImportResult) HasErrors() bool {
+	return len(r.Errors) > 0
+}
@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+// Lactation stages follow on from the gestation stages, so the pregnancy
+// guidelines cover a mare through to weaning
+const (
+	PregnancyStageEarlyLactation PregnancyStage = "EARLY_LACTATION" // Months 1-3, peak milk yield
+	PregnancyStageLateLactation  PregnancyStage = "LATE_LACTATION"  // Months 4-6, yield falling as the foal eats more
+)
+
+// LactationMonths is how long after foaling a mare is treated as nursing,
+// unless her foal is weaned sooner
+const LactationMonths = 6
+
+// Lactation is a mare nursing the foal from her last completed pregnancy.
+// Requirements are her daily nutrient needs for the month at her current
+// weight; WaterLitres is her daily water intake.
+type Lactation struct {
+	HorseID          uint           `json:"horse_id"`
+	PregnancyID      uint           `json:"pregnancy_id"`
+	FoalingDate      time.Time      `json:"foaling_date"`
+	FoalID           *uint          `json:"foal_id,omitempty"`
+	DaysSinceFoaling int            `json:"days_since_foaling"`
+	Month            int            `json:"month"`
+	Stage            PregnancyStage `json:"stage"`
+	EndsOn           time.Time      `json:"ends_on"`
+	WeightKg         float64        `json:"weight_kg"`
+	Requirements     Nutrients      `json:"requirements"`
+	WaterLitres      float64        `json:"water_litres"`
+}
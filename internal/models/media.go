@@ -0,0 +1,52 @@
+package models
+
+import "time"
+
+// MediaType categorises files attached to a horse
+type MediaType string
+
+const (
+	MediaTypePhoto      MediaType = "PHOTO"
+	MediaTypeUltrasound MediaType = "ULTRASOUND"
+	MediaTypeVetReport  MediaType = "VET_REPORT"
+	MediaTypePassport   MediaType = "PASSPORT"
+	MediaTypeOther      MediaType = "OTHER"
+)
+
+// HorseMedia is a photo or document stored for a horse, optionally attached
+// to a health record or pregnancy event
+type HorseMedia struct {
+	ID               uint      `json:"id" gorm:"primaryKey"`
+	HorseID          uint      `json:"horse_id" gorm:"index;not null"`
+	UserID           string    `json:"user_id" gorm:"index;not null"`
+	Type             MediaType `json:"type" gorm:"size:20;not null"`
+	FileName         string    `json:"-" gorm:"size:255;not null"`
+	OriginalName     string    `json:"original_name" gorm:"size:255"`
+	MimeType         string    `json:"mime_type" gorm:"size:100;not null"`
+	Size             int64     `json:"size"`
+	ThumbnailName    string    `json:"-" gorm:"size:255"`
+	HasThumbnail     bool      `json:"has_thumbnail" gorm:"-"`
+	URL              string    `json:"url,omitempty" gorm:"-"`
+	ThumbnailURL     string    `json:"thumbnail_url,omitempty" gorm:"-"`
+	Description      string    `json:"description" gorm:"type:text"`
+	HealthRecordID   *uint     `json:"health_record_id,omitempty" gorm:"index"`
+	PregnancyEventID *uint     `json:"pregnancy_event_id,omitempty" gorm:"index"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+// MediaFilter narrows a media listing
+type MediaFilter struct {
+	Type             MediaType
+	HealthRecordID   *uint
+	PregnancyEventID *uint
+}
+
+// IsValid checks whether the media type is known
+func (t MediaType) IsValid() bool {
+	switch t {
+	case MediaTypePhoto, MediaTypeUltrasound, MediaTypeVetReport, MediaTypePassport, MediaTypeOther:
+		return true
+	}
+	return false
+}
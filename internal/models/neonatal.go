@@ -0,0 +1,124 @@
+package models
+
+import "time"
+
+// NeonatalMilestone is an event a newborn foal should reach within hours of foaling
+type NeonatalMilestone string
+
+const (
+	NeonatalStand    NeonatalMilestone = "STAND"
+	NeonatalNurse    NeonatalMilestone = "NURSE"
+	NeonatalMeconium NeonatalMilestone = "MECONIUM" // First manure passed
+	NeonatalUrinate  NeonatalMilestone = "URINATE"
+	NeonatalIgGTest  NeonatalMilestone = "IGG_TEST" // Blood test for passive transfer of antibodies from colostrum
+)
+
+// NeonatalMilestoneStatus is how a milestone stands against its deadlines
+type NeonatalMilestoneStatus string
+
+const (
+	NeonatalMilestonePending NeonatalMilestoneStatus = "PENDING"
+	NeonatalMilestoneDone    NeonatalMilestoneStatus = "DONE"
+	NeonatalMilestoneLate    NeonatalMilestoneStatus = "LATE"   // Past the usual time; watch closely and warn the vet
+	NeonatalMilestoneUrgent  NeonatalMilestoneStatus = "URGENT" // Past the time to call the vet
+)
+
+// IgGStatus is the result of the IgG test for passive transfer
+type IgGStatus string
+
+const (
+	IgGAdequate IgGStatus = "ADEQUATE" // 800 mg/dL or more
+	IgGPartial  IgGStatus = "PARTIAL"  // 400-799 mg/dL: partial failure of passive transfer
+	IgGFailure  IgGStatus = "FAILURE"  // Below 400 mg/dL: failure of passive transfer
+)
+
+// NeonatalRecord is a foal's first days: when it reached each milestone
+// after foaling and the IgG result. Milestones not yet reached are nil.
+type NeonatalRecord struct {
+	ID          uint       `json:"id" gorm:"primaryKey"`
+	HorseID     uint       `json:"horse_id" gorm:"uniqueIndex;not null"`
+	UserID      string     `json:"user_id" gorm:"not null"`
+	FoaledAt    time.Time  `json:"foaled_at" gorm:"index;not null"`
+	StoodAt     *time.Time `json:"stood_at,omitempty"`
+	NursedAt    *time.Time `json:"nursed_at,omitempty"`
+	MeconiumAt  *time.Time `json:"meconium_at,omitempty"`
+	UrinatedAt  *time.Time `json:"urinated_at,omitempty"`
+	IgGTestedAt *time.Time `json:"igg_tested_at,omitempty"`
+	IgG         *float64   `json:"igg,omitempty" gorm:"column:igg"` // mg/dL
+	Notes       string     `json:"notes" gorm:"type:text"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// ReachedAt returns when a milestone was reached, or nil
+func (r *NeonatalRecord) ReachedAt(milestone NeonatalMilestone) *time.Time {
+	switch milestone {
+	case NeonatalStand:
+		return r.StoodAt
+	case NeonatalNurse:
+		return r.NursedAt
+	case NeonatalMeconium:
+		return r.MeconiumAt
+	case NeonatalUrinate:
+		return r.UrinatedAt
+	case NeonatalIgGTest:
+		return r.IgGTestedAt
+	}
+	return nil
+}
+
+// NeonatalMilestoneCheck is one milestone against its deadlines
+type NeonatalMilestoneCheck struct {
+	Milestone  NeonatalMilestone       `json:"milestone"`
+	Name       string                  `json:"name"`
+	DueBy      time.Time               `json:"due_by"`
+	UrgentBy   time.Time               `json:"urgent_by"`
+	ReachedAt  *time.Time              `json:"reached_at,omitempty"`
+	HoursAfter *float64                `json:"hours_after,omitempty"` // Hours after foaling it was reached
+	Status     NeonatalMilestoneStatus `json:"status"`
+	Advice     string                  `json:"advice,omitempty"`
+}
+
+// ApgarScore rates one sign of the foal's APGAR assessment from 0 to 2
+type ApgarScore int
+
+// ApgarInterpretation groups APGAR totals
+type ApgarInterpretation string
+
+const (
+	ApgarNormal   ApgarInterpretation = "NORMAL"   // 7-8
+	ApgarModerate ApgarInterpretation = "MODERATE" // 4-6: moderate asphyxia
+	ApgarSevere   ApgarInterpretation = "SEVERE"   // 0-3: severe asphyxia
+)
+
+// NeonatalAssessment is a modified APGAR assessment of a newborn foal, best
+// taken within five minutes of birth. Each sign scores 0 to 2; Score and
+// Interpretation are filled in by the service.
+type NeonatalAssessment struct {
+	ID             uint                `json:"id" gorm:"primaryKey"`
+	HorseID        uint                `json:"horse_id" gorm:"index;not null"`
+	UserID         string              `json:"user_id" gorm:"not null"`
+	AssessedAt     time.Time           `json:"assessed_at" gorm:"not null"`
+	HeartRate      ApgarScore          `json:"heart_rate"`     // 0 absent, 1 under 60 bpm, 2 60 bpm or more
+	Respiration    ApgarScore          `json:"respiration"`    // 0 absent, 1 slow or irregular, 2 regular
+	MuscleTone     ApgarScore          `json:"muscle_tone"`    // 0 limp, 1 some flexion, 2 sternal
+	NasalResponse  ApgarScore          `json:"nasal_response"` // To a straw in the nostril: 0 none, 1 grimace, 2 sneeze or cough
+	Score          int                 `json:"score"`
+	Interpretation ApgarInterpretation `json:"interpretation" gorm:"size:20"`
+	Notes          string              `json:"notes" gorm:"type:text"`
+	CreatedAt      time.Time           `json:"created_at"`
+}
+
+// NeonatalSummary is everything recorded for a foal in its first 14 days
+type NeonatalSummary struct {
+	HorseID     uint                     `json:"horse_id"`
+	Record      *NeonatalRecord          `json:"record"`
+	AgeHours    float64                  `json:"age_hours"`
+	Milestones  []NeonatalMilestoneCheck `json:"milestones"`
+	IgGStatus   IgGStatus                `json:"igg_status,omitempty"`
+	Assessments []NeonatalAssessment     `json:"assessments"`
+	VitalSigns  []VitalSignsRecord       `json:"vital_signs"`
+	Weights     []WeightRecord           `json:"weights"`
+	DailyGainKg *float64                 `json:"daily_gain_kg,omitempty"` // Between the first and latest weighing
+	Alerts      []string                 `json:"alerts"`
+}
@@ -0,0 +1,88 @@
+package models
+
+// Nutrients are the amounts of the nutrients a ration is balanced on. On a
+// FeedType they are per kg of feed as fed, or per litre for liquids; in a
+// requirement or a ration they are per day.
+type Nutrients struct {
+	DigestibleEnergy float64 `json:"digestible_energy_mcal"`
+	CrudeProtein     float64 `json:"crude_protein_g"`
+	Lysine           float64 `json:"lysine_g"`
+	Calcium          float64 `json:"calcium_g"`
+	Phosphorus       float64 `json:"phosphorus_g"`
+	Copper           float64 `json:"copper_mg"`
+	Zinc             float64 `json:"zinc_mg"`
+	Selenium         float64 `json:"selenium_mg"`
+}
+
+// Add sums two sets of nutrients
+func (n Nutrients) Add(o Nutrients) Nutrients {
+	return Nutrients{
+		DigestibleEnergy: n.DigestibleEnergy + o.DigestibleEnergy,
+		CrudeProtein:     n.CrudeProtein + o.CrudeProtein,
+		Lysine:           n.Lysine + o.Lysine,
+		Calcium:          n.Calcium + o.Calcium,
+		Phosphorus:       n.Phosphorus + o.Phosphorus,
+		Copper:           n.Copper + o.Copper,
+		Zinc:             n.Zinc + o.Zinc,
+		Selenium:         n.Selenium + o.Selenium,
+	}
+}
+
+// Scale multiplies every nutrient by f
+func (n Nutrients) Scale(f float64) Nutrients {
+	return Nutrients{
+		DigestibleEnergy: n.DigestibleEnergy * f,
+		CrudeProtein:     n.CrudeProtein * f,
+		Lysine:           n.Lysine * f,
+		Calcium:          n.Calcium * f,
+		Phosphorus:       n.Phosphorus * f,
+		Copper:           n.Copper * f,
+		Zinc:             n.Zinc * f,
+		Selenium:         n.Selenium * f,
+	}
+}
+
+// Negative reports whether any nutrient is below zero
+func (n Nutrients) Negative() bool {
+	return n.DigestibleEnergy < 0 || n.CrudeProtein < 0 || n.Lysine < 0 || n.Calcium < 0 ||
+		n.Phosphorus < 0 || n.Copper < 0 || n.Zinc < 0 || n.Selenium < 0
+}
+
+// NutrientStatus is how a ration's supply of a nutrient compares with what
+// the horse needs
+type NutrientStatus string
+
+const (
+	NutrientDeficit  NutrientStatus = "DEFICIT"
+	NutrientAdequate NutrientStatus = "ADEQUATE"
+	NutrientExcess   NutrientStatus = "EXCESS"
+)
+
+// NutrientBalance compares the daily supply of one nutrient with the
+// requirement. Maximum is the most the horse should get, where there is a
+// limit.
+type NutrientBalance struct {
+	Nutrient   string         `json:"nutrient"`
+	Unit       string         `json:"unit"`
+	Required   float64        `json:"required"`
+	Supplied   float64        `json:"supplied"`
+	Difference float64        `json:"difference"` // Supplied less required
+	Percent    float64        `json:"percent"`    // Supplied as a percentage of required
+	Maximum    *float64       `json:"maximum,omitempty"`
+	Status     NutrientStatus `json:"status"`
+}
+
+// RationEvaluation balances a horse's actual ration against its nutrient
+// requirements for its weight, work and reproductive state. Feeds without
+// a nutrient composition are listed as unrated and count for nothing.
+type RationEvaluation struct {
+	HorseID                uint              `json:"horse_id"`
+	WeightKg               float64           `json:"weight_kg"`
+	States                 []string          `json:"states"` // Such as "moderate work" or "gestation month 9"
+	Required               Nutrients         `json:"required"`
+	Supplied               Nutrients         `json:"supplied"`
+	Balance                []NutrientBalance `json:"balance"`
+	CalciumPhosphorusRatio *float64          `json:"calcium_phosphorus_ratio,omitempty"`
+	UnratedFeeds           []string          `json:"unrated_feeds"`
+	Warnings               []string          `json:"warnings"`
+}
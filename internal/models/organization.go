@@ -0,0 +1,68 @@
+package models
+
+import "time"
+
+// MemberRole represents the role a user holds within an organization (farm)
+type MemberRole string
+
+const (
+	MemberRoleOwner       MemberRole = "owner"
+	MemberRoleFarmManager MemberRole = "farm_manager"
+	MemberRoleStaff       MemberRole = "staff"
+	MemberRoleVet         MemberRole = "vet"
+)
+
+// Permission represents an action a user may perform on a horse and its records
+type Permission string
+
+const (
+	PermissionView          Permission = "VIEW"           // Read horse, health, pregnancy and breeding data
+	PermissionLog           Permission = "LOG"            // Log observations: health records, pre-foaling signs, pregnancy events
+	PermissionEdit          Permission = "EDIT"           // Edit horse details, breeding records, start/end pregnancies
+	PermissionDelete        Permission = "DELETE"         // Delete horses and records
+	PermissionManageMembers Permission = "MANAGE_MEMBERS" // Add, change and remove organization members
+)
+
+// rolePermissions maps each member role to the permissions it grants
+var rolePermissions = map[MemberRole][]Permission{
+	MemberRoleOwner:       {PermissionView, PermissionLog, PermissionEdit, PermissionDelete, PermissionManageMembers},
+	MemberRoleFarmManager: {PermissionView, PermissionLog, PermissionEdit, PermissionManageMembers},
+	MemberRoleStaff:       {PermissionView, PermissionLog},
+	MemberRoleVet:         {PermissionView},
+}
+
+// Organization represents a farm or barn whose horses are shared between members
+type Organization struct {
+	ID        uint                 `json:"id" gorm:"primaryKey"`
+	Name      string               `json:"name" gorm:"size:255;not null"`
+	OwnerID   string               `json:"owner_id" gorm:"index;not null"`
+	Members   []OrganizationMember `json:"members,omitempty"`
+	CreatedAt time.Time            `json:"created_at"`
+	UpdatedAt time.Time            `json:"updated_at"`
+}
+
+// OrganizationMember links a user to an organization with a role
+type OrganizationMember struct {
+	ID             uint       `json:"id" gorm:"primaryKey"`
+	OrganizationID uint       `json:"organization_id" gorm:"uniqueIndex:idx_org_member;not null"`
+	UserID         string     `json:"user_id" gorm:"uniqueIndex:idx_org_member;not null"`
+	Role           MemberRole `json:"role" gorm:"size:50;not null"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+// IsValid checks whether the role is one of the known member roles
+func (r MemberRole) IsValid() bool {
+	_, ok := rolePermissions[r]
+	return ok
+}
+
+// Can reports whether the role grants the given permission
+func (r MemberRole) Can(permission Permission) bool {
+	for _, p := range rolePermissions[r] {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
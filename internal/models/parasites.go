@@ -0,0 +1,76 @@
+package models
+
+import "time"
+
+// ParasiteType is the parasite group a fecal egg count was made for
+type ParasiteType string
+
+const (
+	ParasiteStrongyle ParasiteType = "STRONGYLE"
+	ParasiteAscarid   ParasiteType = "ASCARID"
+	ParasiteTapeworm  ParasiteType = "TAPEWORM"
+	ParasiteOther     ParasiteType = "OTHER"
+)
+
+// ShedderLevel classifies how many strongyle eggs an adult horse typically sheds
+type ShedderLevel string
+
+const (
+	ShedderUnknown  ShedderLevel = "UNKNOWN"
+	ShedderLow      ShedderLevel = "LOW"      // Under 200 eggs per gram
+	ShedderModerate ShedderLevel = "MODERATE" // 200-500 eggs per gram
+	ShedderHigh     ShedderLevel = "HIGH"     // Over 500 eggs per gram
+)
+
+// EggCountReductionResult is the outcome of a fecal egg count reduction test
+type EggCountReductionResult string
+
+const (
+	ReductionEffective           EggCountReductionResult = "EFFECTIVE"
+	ReductionSuspectedResistance EggCountReductionResult = "SUSPECTED_RESISTANCE"
+	ReductionResistant           EggCountReductionResult = "RESISTANT"
+)
+
+// FecalEggCount is a lab or on-farm fecal egg count result
+type FecalEggCount struct {
+	ID           uint         `json:"id" gorm:"primaryKey"`
+	HorseID      uint         `json:"horse_id" gorm:"index;not null"`
+	UserID       string       `json:"user_id" gorm:"index;not null"`
+	SampleDate   time.Time    `json:"sample_date" gorm:"not null"`
+	EggsPerGram  int          `json:"eggs_per_gram"`
+	ParasiteType ParasiteType `json:"parasite_type" gorm:"size:20;not null"`
+	Lab          string       `json:"lab,omitempty" gorm:"size:100"`
+	Notes        string       `json:"notes,omitempty" gorm:"type:text"`
+	CreatedAt    time.Time    `json:"created_at"`
+}
+
+// EggCountReductionTest compares counts before and after a deworming to
+// check whether the dewormer still works on the horse's parasites
+type EggCountReductionTest struct {
+	TreatmentDate    time.Time               `json:"treatment_date"`
+	ActiveIngredient string                  `json:"active_ingredient"`
+	DrugClass        string                  `json:"drug_class"`
+	PreTreatmentEPG  int                     `json:"pre_treatment_epg"`
+	PostTreatmentEPG int                     `json:"post_treatment_epg"`
+	ReductionPercent float64                 `json:"reduction_percent"`
+	Result           EggCountReductionResult `json:"result"`
+}
+
+// DewormingRecommendation is the targeted deworming plan for one horse
+type DewormingRecommendation struct {
+	HorseID        uint                    `json:"horse_id"`
+	ShedderLevel   ShedderLevel            `json:"shedder_level"`
+	TypicalEPG     *int                    `json:"typical_epg,omitempty"`
+	IntervalMonths int                     `json:"interval_months"`
+	Reason         string                  `json:"reason"`
+	ReductionTests []EggCountReductionTest `json:"reduction_tests"`
+	AvoidDrugs     []string                `json:"avoid_drugs,omitempty"` // Drug classes with confirmed resistance
+}
+
+func (t ParasiteType) IsValid() bool {
+	switch t {
+	case ParasiteStrongyle, ParasiteAscarid, ParasiteTapeworm, ParasiteOther:
+		return true
+	}
+	return false
+}
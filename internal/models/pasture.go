@@ -0,0 +1,82 @@
+package models
+
+import "time"
+
+// GrassCondition grades how much grazing a pasture offers
+type GrassCondition string
+
+const (
+	GrassConditionPoor GrassCondition = "POOR" // Bare or weedy, little to eat
+	GrassConditionFair GrassCondition = "FAIR"
+	GrassConditionGood GrassCondition = "GOOD"
+	GrassConditionLush GrassCondition = "LUSH" // Fast-growing and sugar-rich
+)
+
+// PastureStatus is where a pasture is in its grazing rotation
+type PastureStatus string
+
+const (
+	PastureStatusGrazing PastureStatus = "GRAZING"
+	PastureStatusResting PastureStatus = "RESTING"
+	PastureStatusReady   PastureStatus = "READY" // Rested long enough to be grazed again
+)
+
+// Pasture is a paddock or field horses are turned out on. It is grazed for
+// GrazingDays at a time and then rested for RestDays.
+type Pasture struct {
+	ID             uint           `json:"id" gorm:"primaryKey"`
+	UserID         string         `json:"user_id" gorm:"index;not null"`
+	Name           string         `json:"name" gorm:"size:100;not null"`
+	AreaHectares   float64        `json:"area_hectares" gorm:"type:decimal(8,2);not null"`
+	GrassCondition GrassCondition `json:"grass_condition" gorm:"size:20;not null"`
+	GrazingDays    int            `json:"grazing_days" gorm:"not null"`
+	RestDays       int            `json:"rest_days" gorm:"not null"`
+	Notes          string         `json:"notes" gorm:"type:text"`
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+}
+
+// Turnout puts a horse on a pasture for some hours a day. EndDate is nil
+// while the horse is still turned out there; a horse has one open turnout
+// at a time.
+type Turnout struct {
+	ID          uint       `json:"id" gorm:"primaryKey"`
+	HorseID     uint       `json:"horse_id" gorm:"index;not null"`
+	PastureID   uint       `json:"pasture_id" gorm:"index;not null"`
+	Pasture     *Pasture   `json:"pasture,omitempty" gorm:"foreignKey:PastureID"`
+	HoursPerDay float64    `json:"hours_per_day" gorm:"type:decimal(4,1);not null"`
+	StartDate   time.Time  `json:"start_date" gorm:"not null"`
+	EndDate     *time.Time `json:"end_date,omitempty"`
+	Notes       string     `json:"notes" gorm:"type:text"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// Open reports whether the horse is still out on the pasture at a time
+func (t *Turnout) Open(at time.Time) bool {
+	return !t.StartDate.After(at) && (t.EndDate == nil || t.EndDate.After(at))
+}
+
+// PastureRotation is a pasture's place in the rotation: who is grazing it
+// and until when, or how long it has rested and when it can be grazed
+// again. NextPastureID is where its horses should move to next.
+type PastureRotation struct {
+	Pasture          Pasture       `json:"pasture"`
+	Status           PastureStatus `json:"status"`
+	HorseIDs         []uint        `json:"horse_ids"`
+	HectaresPerHorse float64       `json:"hectares_per_horse,omitempty"`
+	GrazedSince      *time.Time    `json:"grazed_since,omitempty"`
+	MoveBy           *time.Time    `json:"move_by,omitempty"`
+	NextPastureID    *uint         `json:"next_pasture_id,omitempty"`
+	RestingSince     *time.Time    `json:"resting_since,omitempty"`
+	ReadyOn          *time.Time    `json:"ready_on,omitempty"`
+	Warnings         []string      `json:"warnings"`
+}
+
+func (c GrassCondition) IsValid() bool {
+	switch c {
+	case GrassConditionPoor, GrassConditionFair, GrassConditionGood, GrassConditionLush:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,38 @@
+package models
+
+import "time"
+
+// SymptomAssessmentRequest lists what the owner observed. Vitals are
+// optional; without them the latest recorded vital signs are used.
+type SymptomAssessmentRequest struct {
+	Symptoms []string          `json:"symptoms" binding:"required,min=1"`
+	Vitals   *VitalSignsRecord `json:"vitals,omitempty"`
+	Notes    string            `json:"notes"`
+}
+
+// ConditionMatch is a catalogue condition that fits the observed symptoms
+type ConditionMatch struct {
+	Name            string   `json:"name"`
+	Category        string   `json:"category"`
+	Urgency         int      `json:"urgency"` // 1-5, with 5 being most urgent
+	Action          string   `json:"action"`
+	Prevention      []string `json:"prevention,omitempty"`
+	Confidence      float64  `json:"confidence"` // Share of the condition's signs that were observed, 0-1
+	MatchedSymptoms []string `json:"matched_symptoms"`
+	MatchedVitals   []string `json:"matched_vitals,omitempty"`
+}
+
+// SymptomAssessment is the result of a symptom check. It is a triage aid,
+// not a diagnosis.
+type SymptomAssessment struct {
+	HorseID        uint               `json:"horse_id"`
+	AssessedAt     time.Time          `json:"assessed_at"`
+	Symptoms       []string           `json:"symptoms"`
+	Unrecognised   []string           `json:"unrecognised,omitempty"`
+	VitalFindings  []VitalSignFinding `json:"vital_findings,omitempty"`
+	Conditions     []ConditionMatch   `json:"conditions"`
+	Urgency        int                `json:"urgency"`
+	Action         string             `json:"action"`
+	Disclaimer     string             `json:"disclaimer"`
+	HealthRecordID uint               `json:"health_record_id"`
+}
@@ -0,0 +1,104 @@
+package models
+
+import "time"
+
+// MedicationRoute is how a medication is given
+type MedicationRoute string
+
+const (
+	MedicationRouteOral          MedicationRoute = "ORAL"
+	MedicationRouteIntravenous   MedicationRoute = "INTRAVENOUS"
+	MedicationRouteIntramuscular MedicationRoute = "INTRAMUSCULAR"
+	MedicationRouteSubcutaneous  MedicationRoute = "SUBCUTANEOUS"
+	MedicationRouteTopical       MedicationRoute = "TOPICAL"
+	MedicationRouteInhaled       MedicationRoute = "INHALED"
+	MedicationRouteOphthalmic    MedicationRoute = "OPHTHALMIC"
+)
+
+// TreatmentStatus is where a treatment plan is in its course
+type TreatmentStatus string
+
+const (
+	TreatmentStatusActive    TreatmentStatus = "ACTIVE"
+	TreatmentStatusCompleted TreatmentStatus = "COMPLETED"
+	TreatmentStatusStopped   TreatmentStatus = "STOPPED" // Ended early, e.g. after a reaction
+)
+
+// PregnancyRisk grades how safe a medication is for pregnant mares
+type PregnancyRisk string
+
+const (
+	PregnancyRiskContraindicated PregnancyRisk = "CONTRAINDICATED"
+	PregnancyRiskCaution         PregnancyRisk = "CAUTION"
+)
+
+// TreatmentPlan is a prescribed course of medication. FrequencyHours is the
+// interval between doses; zero means a single dose at StartDate.
+type TreatmentPlan struct {
+	ID                         uint            `json:"id" gorm:"primaryKey"`
+	HorseID                    uint            `json:"horse_id" gorm:"index;not null"`
+	UserID                     string          `json:"user_id" gorm:"index;not null"`
+	Drug                       string          `json:"drug" gorm:"size:100;not null"`
+	Dose                       float64         `json:"dose"`
+	DoseUnit                   string          `json:"dose_unit" gorm:"size:20"`
+	DosePerKg                  float64         `json:"dose_per_kg,omitempty" gorm:"type:decimal(10,4)"` // Dose units per kg; Dose is calculated from the latest weight
+	WeightKg                   float64         `json:"weight_kg,omitempty" gorm:"type:decimal(6,1)"`    // Body weight the dose was calculated for
+	Route                      MedicationRoute `json:"route" gorm:"size:20"`
+	FrequencyHours             int             `json:"frequency_hours"`
+	StartDate                  time.Time       `json:"start_date" gorm:"not null"`
+	EndDate                    *time.Time      `json:"end_date,omitempty"`
+	Reason                     string          `json:"reason" gorm:"type:text"`
+	PrescribedBy               string          `json:"prescribed_by" gorm:"size:100"`
+	CompetitionWithdrawalHours int             `json:"competition_withdrawal_hours"`
+	SlaughterWithdrawalDays    int             `json:"slaughter_withdrawal_days"`
+	FoodChainExcluded          bool            `json:"food_chain_excluded"` // Drug that permanently excludes the horse from the food chain
+	Status                     TreatmentStatus `json:"status" gorm:"size:20;default:ACTIVE;index"`
+	StoppedReason              string          `json:"stopped_reason,omitempty" gorm:"type:text"`
+	Notes                      string          `json:"notes" gorm:"type:text"`
+	CreatedAt                  time.Time       `json:"created_at"`
+	UpdatedAt                  time.Time       `json:"updated_at"`
+
+	Warnings []string `json:"warnings,omitempty" gorm:"-"`
+}
+
+// DoseAdministration is one logged dose of a treatment plan
+type DoseAdministration struct {
+	ID             uint      `json:"id" gorm:"primaryKey"`
+	PlanID         uint      `json:"plan_id" gorm:"index;not null"`
+	HorseID        uint      `json:"horse_id" gorm:"index;not null"`
+	UserID         string    `json:"user_id" gorm:"not null"`
+	AdministeredAt time.Time `json:"administered_at" gorm:"not null"`
+	Dose           float64   `json:"dose"` // Defaults to the planned dose
+	Notes          string    `json:"notes" gorm:"type:text"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// TreatmentPlanSummary is a plan together with its dose log and what follows from it
+type TreatmentPlanSummary struct {
+	TreatmentPlan
+	Doses              []DoseAdministration `json:"doses"`
+	MissedDoses        []time.Time          `json:"missed_doses"`
+	NextDoseAt         *time.Time           `json:"next_dose_at,omitempty"`
+	CompetitionClearAt *time.Time           `json:"competition_clear_at,omitempty"`
+	SlaughterClearAt   *time.Time           `json:"slaughter_clear_at,omitempty"`
+}
+
+// WithdrawalStatus tells whether a horse may compete or enter the food chain
+type WithdrawalStatus struct {
+	HorseID             uint       `json:"horse_id"`
+	ClearForCompetition bool       `json:"clear_for_competition"`
+	CompetitionClearAt  *time.Time `json:"competition_clear_at,omitempty"`
+	ClearForSlaughter   bool       `json:"clear_for_slaughter"`
+	SlaughterClearAt    *time.Time `json:"slaughter_clear_at,omitempty"`
+	FoodChainExcluded   bool       `json:"food_chain_excluded"`
+	BlockingPlans       []uint     `json:"blocking_plans"`
+}
+
+func (r MedicationRoute) IsValid() bool {
+	switch r {
+	case MedicationRouteOral, MedicationRouteIntravenous, MedicationRouteIntramuscular, MedicationRouteSubcutaneous,
+		MedicationRouteTopical, MedicationRouteInhaled, MedicationRouteOphthalmic:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,90 @@
+package models
+
+import "time"
+
+// AccessLevel defines what a vet access grant allows
+type AccessLevel string
+
+const (
+	AccessLevelRead      AccessLevel = "read"       // View records only
+	AccessLevelReadWrite AccessLevel = "read_write" // View records and log new ones
+)
+
+// RecordScope identifies which records of a horse a grant covers
+type RecordScope string
+
+const (
+	RecordScopeHealth    RecordScope = "health"
+	RecordScopePregnancy RecordScope = "pregnancy"
+)
+
+// VetAccessGrant gives a vet account or a signed link temporary access to
+// the health and/or pregnancy records of selected horses. Exactly one of
+// VetUserID and TokenHash is set; the other stays NULL.
+type VetAccessGrant struct {
+	ID               uint                  `json:"id" gorm:"primaryKey"`
+	OwnerID          string                `json:"owner_id" gorm:"index;not null"`
+	VetUserID        *string               `json:"vet_user_id,omitempty" gorm:"index"`
+	TokenHash        *string               `json:"-" gorm:"size:64;uniqueIndex"`
+	AccessLevel      AccessLevel           `json:"access_level" gorm:"size:20;not null"`
+	IncludeHealth    bool                  `json:"include_health"`
+	IncludePregnancy bool                  `json:"include_pregnancy"`
+	Horses           []VetAccessGrantHorse `json:"horses" gorm:"foreignKey:GrantID"`
+	ExpiresAt        time.Time             `json:"expires_at" gorm:"not null"`
+	RevokedAt        *time.Time            `json:"revoked_at,omitempty"`
+	CreatedAt        time.Time             `json:"created_at"`
+	UpdatedAt        time.Time             `json:"updated_at"`
+}
+
+// VetAccessGrantHorse links a grant to one of the horses it covers
+type VetAccessGrantHorse struct {
+	ID      uint `json:"-" gorm:"primaryKey"`
+	GrantID uint `json:"-" gorm:"index;not null"`
+	HorseID uint `json:"horse_id" gorm:"index;not null"`
+}
+
+// IsValid checks whether the access level is known
+func (l AccessLevel) IsValid() bool {
+	return l == AccessLevelRead || l == AccessLevelReadWrite
+}
+
+// IsActive reports whether the grant is neither revoked nor expired at the given time
+func (g *VetAccessGrant) IsActive(now time.Time) bool {
+	return g.RevokedAt == nil && now.Before(g.ExpiresAt)
+}
+
+// CoversHorse reports whether the grant includes the horse
+func (g *VetAccessGrant) CoversHorse(horseID uint) bool {
+	for _, h := range g.Horses {
+		if h.HorseID == horseID {
+			return true
+		}
+	}
+	return false
+}
+
+// CoversScope reports whether the grant includes the record scope
+func (g *VetAccessGrant) CoversScope(scope RecordScope) bool {
+	switch scope {
+	case RecordScopeHealth:
+		return g.IncludeHealth
+	case RecordScopePregnancy:
+		return g.IncludePregnancy
+	default:
+		return false
+	}
+}
+
+// Allows reports whether the grant's access level permits the action.
+// Vets may view records and, with read/write access, log new ones; they can
+// never edit horse details or delete anything.
+func (g *VetAccessGrant) Allows(permission Permission) bool {
+	switch permission {
+	case PermissionView:
+		return true
+	case PermissionLog:
+		return g.AccessLevel == AccessLevelReadWrite
+	default:
+		return false
+	}
+}
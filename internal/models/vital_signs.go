@@ -0,0 +1,73 @@
+package models
+
+import "time"
+
+// GutSounds describes borborygmi heard on auscultation
+type GutSounds string
+
+const (
+	GutSoundsNormal    GutSounds = "NORMAL"
+	GutSoundsReduced   GutSounds = "REDUCED"
+	GutSoundsIncreased GutSounds = "INCREASED"
+	GutSoundsAbsent    GutSounds = "ABSENT"
+)
+
+// MucousMembraneColour is the gum colour observed during an exam
+type MucousMembraneColour string
+
+const (
+	MucousMembranePink     MucousMembraneColour = "PINK"
+	MucousMembranePale     MucousMembraneColour = "PALE"
+	MucousMembraneBrickRed MucousMembraneColour = "BRICK_RED"
+	MucousMembraneYellow   MucousMembraneColour = "YELLOW"
+	MucousMembraneCyanotic MucousMembraneColour = "CYANOTIC"
+)
+
+// VitalSignsRecord is a structured vital signs check. Category, Abnormal and
+// Findings are filled in by the service when the record is evaluated.
+type VitalSignsRecord struct {
+	ID              uint                 `json:"id" gorm:"primaryKey"`
+	HorseID         uint                 `json:"horse_id" gorm:"index;not null"`
+	UserID          string               `json:"user_id" gorm:"index;not null"`
+	RecordedAt      time.Time            `json:"recorded_at" gorm:"not null"`
+	Temperature     *float64             `json:"temperature,omitempty"`      // Celsius
+	HeartRate       *int                 `json:"heart_rate,omitempty"`       // Beats per minute
+	RespirationRate *int                 `json:"respiration_rate,omitempty"` // Breaths per minute
+	GutSounds       GutSounds            `json:"gut_sounds,omitempty" gorm:"size:20"`
+	CapillaryRefill *float64             `json:"capillary_refill,omitempty"` // Seconds
+	MucousMembrane  MucousMembraneColour `json:"mucous_membrane,omitempty" gorm:"size:20"`
+	Notes           string               `json:"notes" gorm:"type:text"`
+	Category        string               `json:"category" gorm:"size:20"`
+	Abnormal        bool                 `json:"abnormal" gorm:"index"`
+	Findings        []VitalSignFinding   `json:"findings" gorm:"type:jsonb;serializer:json"`
+	CreatedAt       time.Time            `json:"created_at"`
+}
+
+// VitalSignFinding describes a single value outside its expected range
+type VitalSignFinding struct {
+	Sign     string `json:"sign"`
+	Value    string `json:"value"`
+	Expected string `json:"expected"`
+}
+
+func (g GutSounds) IsValid() bool {
+	switch g {
+	case GutSoundsNormal, GutSoundsReduced, GutSoundsIncreased, GutSoundsAbsent:
+		return true
+	}
+	return false
+}
+
+func (m MucousMembraneColour) IsValid() bool {
+	switch m {
+	case MucousMembranePink, MucousMembranePale, MucousMembraneBrickRed, MucousMembraneYellow, MucousMembraneCyanotic:
+		return true
+	}
+	return false
+}
+
+// HasMeasurements reports whether at least one vital sign was recorded
+func (v *VitalSignsRecord) HasMeasurements() bool {
+	return v.Temperature != nil || v.HeartRate != nil || v.RespirationRate != nil ||
+		v.GutSounds != "" || v.CapillaryRefill != nil || v.MucousMembrane != ""
+}
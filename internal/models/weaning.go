@@ -0,0 +1,111 @@
+package models
+
+import "time"
+
+// WeaningMethod is how a foal was separated from its dam
+type WeaningMethod string
+
+const (
+	WeaningAbrupt    WeaningMethod = "ABRUPT"     // Separated at once, out of sight and hearing
+	WeaningGradual   WeaningMethod = "GRADUAL"    // Time apart lengthened over days or weeks
+	WeaningFenceLine WeaningMethod = "FENCE_LINE" // Mare in the next paddock, able to see and touch the foal but not nurse
+	WeaningInterval  WeaningMethod = "INTERVAL"   // Mares taken one or two at a time from a group of mares and foals
+)
+
+func (m WeaningMethod) IsValid() bool {
+	switch m {
+	case WeaningAbrupt, WeaningGradual, WeaningFenceLine, WeaningInterval:
+		return true
+	}
+	return false
+}
+
+// WeaningRecord is when and how a foal was weaned. Group names the foals
+// weaned and kept together.
+type WeaningRecord struct {
+	ID        uint          `json:"id" gorm:"primaryKey"`
+	HorseID   uint          `json:"horse_id" gorm:"uniqueIndex;not null"`
+	UserID    string        `json:"user_id" gorm:"not null"`
+	Date      time.Time     `json:"date" gorm:"index;not null"`
+	Method    WeaningMethod `json:"method" gorm:"size:20;not null"`
+	Group     string        `json:"group" gorm:"column:weaning_group;size:100"`
+	Notes     string        `json:"notes" gorm:"type:text"`
+	CreatedAt time.Time     `json:"created_at"`
+	UpdatedAt time.Time     `json:"updated_at"`
+}
+
+// WeaningStressSign is a sign of stress or illness after weaning
+type WeaningStressSign string
+
+const (
+	WeaningVocalizing   WeaningStressSign = "VOCALIZING"
+	WeaningPacing       WeaningStressSign = "PACING"
+	WeaningFenceRunning WeaningStressSign = "FENCE_RUNNING"
+	WeaningNotEating    WeaningStressSign = "NOT_EATING"
+	WeaningInjury       WeaningStressSign = "INJURY"
+	WeaningDiarrhea     WeaningStressSign = "DIARRHEA"
+	WeaningCough        WeaningStressSign = "COUGH" // Or nasal discharge; respiratory disease often follows weaning stress
+)
+
+func (s WeaningStressSign) IsValid() bool {
+	switch s {
+	case WeaningVocalizing, WeaningPacing, WeaningFenceRunning, WeaningNotEating, WeaningInjury, WeaningDiarrhea, WeaningCough:
+		return true
+	}
+	return false
+}
+
+// WeaningCheck is one look at a foal after weaning. No signs means the foal
+// was settled.
+type WeaningCheck struct {
+	ID        uint                `json:"id" gorm:"primaryKey"`
+	HorseID   uint                `json:"horse_id" gorm:"index;not null"`
+	UserID    string              `json:"user_id" gorm:"not null"`
+	CheckedAt time.Time           `json:"checked_at" gorm:"not null"`
+	Signs     []WeaningStressSign `json:"signs" gorm:"type:jsonb;serializer:json"`
+	Notes     string              `json:"notes" gorm:"type:text"`
+	CreatedAt time.Time           `json:"created_at"`
+}
+
+// WeaningPlanStatus is where a foal is against its weaning window
+type WeaningPlanStatus string
+
+const (
+	WeaningTooYoung WeaningPlanStatus = "TOO_YOUNG"
+	WeaningReady    WeaningPlanStatus = "READY"
+	WeaningOverdue  WeaningPlanStatus = "OVERDUE"
+)
+
+// WeaningPlan proposes when to wean a foal. The window follows the foal's
+// age and is moved by its weight percentile and by the dam's new pregnancy.
+type WeaningPlan struct {
+	HorseID          uint              `json:"horse_id"`
+	AgeDays          int               `json:"age_days"`
+	Earliest         time.Time         `json:"earliest"`
+	Recommended      time.Time         `json:"recommended"`
+	Latest           time.Time         `json:"latest"`
+	Status           WeaningPlanStatus `json:"status"`
+	WeightPercentile *float64          `json:"weight_percentile,omitempty"`  // From the latest weighing
+	DamGestationDays *int              `json:"dam_gestation_days,omitempty"` // Days since the dam conceived again
+	DamStage         PregnancyStage    `json:"dam_stage,omitempty"`
+	Reasons          []string          `json:"reasons"`
+}
+
+// WeaningProgress is a foal's weight and stress through the monitoring
+// period after weaning. The pre-weaning weight is the last weighing in the
+// month up to the weaning date.
+type WeaningProgress struct {
+	HorseID           uint           `json:"horse_id"`
+	Record            *WeaningRecord `json:"record"`
+	DaysSinceWeaning  int            `json:"days_since_weaning"`
+	MonitoringEnds    time.Time      `json:"monitoring_ends"`
+	Monitoring        bool           `json:"monitoring"` // Still within the monitoring period
+	PreWeaningWeight  *float64       `json:"pre_weaning_weight,omitempty"`
+	LatestWeight      *float64       `json:"latest_weight,omitempty"`
+	WeightChangeKg    *float64       `json:"weight_change_kg,omitempty"`
+	DailyGainKg       *float64       `json:"daily_gain_kg,omitempty"`
+	ExpectedDailyGain *float64       `json:"expected_daily_gain,omitempty"` // Along the breed median over the same ages
+	Measurements      []GrowthData   `json:"measurements"`
+	Checks            []WeaningCheck `json:"checks"`
+	Alerts            []string       `json:"alerts"`
+}
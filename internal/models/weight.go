@@ -0,0 +1,62 @@
+package models
+
+import "time"
+
+// WeightMethod is how a weight was arrived at
+type WeightMethod string
+
+const (
+	WeightMethodScale  WeightMethod = "SCALE"  // Weighbridge or livestock scale
+	WeightMethodTape   WeightMethod = "TAPE"   // Estimated from heart girth and body length
+	WeightMethodVisual WeightMethod = "VISUAL" // Eyeballed or taken from a weigh tape's printed scale
+)
+
+func (m WeightMethod) IsValid() bool {
+	switch m {
+	case WeightMethodScale, WeightMethodTape, WeightMethodVisual:
+		return true
+	}
+	return false
+}
+
+// WeightFormula is the variant of the girth and length formula a tape
+// estimate was calculated with
+type WeightFormula string
+
+const (
+	WeightFormulaAdult    WeightFormula = "ADULT"
+	WeightFormulaPony     WeightFormula = "PONY"
+	WeightFormulaYearling WeightFormula = "YEARLING"
+	WeightFormulaWeanling WeightFormula = "WEANLING" // Foals up to a year old
+)
+
+func (f WeightFormula) IsValid() bool {
+	switch f {
+	case WeightFormulaAdult, WeightFormulaPony, WeightFormulaYearling, WeightFormulaWeanling:
+		return true
+	}
+	return false
+}
+
+// WeightRecord is one weighing of a horse. Tape estimates keep the
+// measurements and formula they were calculated from.
+type WeightRecord struct {
+	ID           uint          `json:"id" gorm:"primaryKey"`
+	HorseID      uint          `json:"horse_id" gorm:"index;not null"`
+	UserID       string        `json:"user_id" gorm:"not null"`
+	Date         time.Time     `json:"date" gorm:"not null"`
+	WeightKg     float64       `json:"weight_kg" gorm:"type:decimal(6,1);not null"`
+	Method       WeightMethod  `json:"method" gorm:"size:20;not null"`
+	HeartGirthCm float64       `json:"heart_girth_cm,omitempty" gorm:"type:decimal(5,1)"`
+	BodyLengthCm float64       `json:"body_length_cm,omitempty" gorm:"type:decimal(5,1)"`
+	Formula      WeightFormula `json:"formula,omitempty" gorm:"size:20"`
+	Notes        string        `json:"notes" gorm:"type:text"`
+	CreatedAt    time.Time     `json:"created_at"`
+}
+
+// WeightHistory is a horse's weight over time, oldest first
+type WeightHistory struct {
+	HorseID uint           `json:"horse_id"`
+	Records []WeightRecord `json:"records"`
+	Latest  *WeightRecord  `json:"latest,omitempty"`
+}
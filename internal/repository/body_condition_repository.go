@@ -0,0 +1,34 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/polyfant/hulta_pregnancy_app/internal/models"
+	"gorm.io/gorm"
+)
+
+type PostgresBodyConditionRepository struct {
+	db *gorm.DB
+}
+
+func NewBodyConditionRepository(db *gorm.DB) BodyConditionRepository {
+	return &PostgresBodyConditionRepository{db: db}
+}
+
+func (r *PostgresBodyConditionRepository) Create(ctx context.Context, record *models.BodyCondition) error {
+	return r.db.WithContext(ctx).Create(record).Error
+}
+
+// ListByHorse returns scores oldest first, the order they are charted in
+func (r *PostgresBodyConditionRepository) ListByHorse(ctx context.Context, horseID uint, since *time.Time) ([]models.BodyCondition, error) {
+	var records []models.BodyCondition
+	query := r.db.WithContext(ctx).Where("horse_id = ?", horseID)
+	if since != nil {
+		query = query.Where("date >= ?", *since)
+	}
+	if err := query.Order("date ASC").Find(&records).Error; err != nil {
+		return nil, err
+	}
+	return records, nil
+}
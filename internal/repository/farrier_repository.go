@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/polyfant/hulta_pregnancy_app/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PostgresFarrierRepository struct {
+	db *gorm.DB
+}
+
+func NewFarrierRepository(db *gorm.DB) FarrierRepository {
+	return &PostgresFarrierRepository{db: db}
+}
+
+// CreateVisit stores a visit together with its expense, if any, so a visit
+// is never saved without the expense it paid for
+func (r *PostgresFarrierRepository) CreateVisit(ctx context.Context, visit *models.FarrierVisit, expense *models.Expense) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if expense != nil {
+			if err := tx.Create(expense).Error; err != nil {
+				return err
+			}
+			visit.ExpenseID = &expense.ID
+		}
+		return tx.Create(visit).Error
+	})
+}
+
+// ListVisitsByHorse returns the most recent visits first
+func (r *PostgresFarrierRepository) ListVisitsByHorse(ctx context.Context, horseID uint) ([]models.FarrierVisit, error) {
+	var visits []models.FarrierVisit
+	if err := r.db.WithContext(ctx).
+		Where("horse_id = ?", horseID).
+		Order("visit_date DESC").
+		Find(&visits).Error; err != nil {
+		return nil, err
+	}
+	return visits, nil
+}
+
+// GetSchedule returns nil without an error when the horse has no schedule
+func (r *PostgresFarrierRepository) GetSchedule(ctx context.Context, horseID uint) (*models.FarrierSchedule, error) {
+	var schedule models.FarrierSchedule
+	err := r.db.WithContext(ctx).Where("horse_id = ?", horseID).First(&schedule).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &schedule, nil
+}
+
+// SaveSchedule creates the horse's schedule or replaces the existing one
+func (r *PostgresFarrierRepository) SaveSchedule(ctx context.Context, schedule *models.FarrierSchedule) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "horse_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"user_id", "interval_weeks", "work", "farrier_name", "farrier_phone", "farrier_email", "notes", "updated_at",
+		}),
+	}).Create(schedule).Error
+}
+
+func (r *PostgresFarrierRepository) DeleteSchedule(ctx context.Context, horseID uint) error {
+	return r.db.WithContext(ctx).Where("horse_id = ?", horseID).Delete(&models.FarrierSchedule{}).Error
+}
@@ -0,0 +1,32 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/polyfant/hulta_pregnancy_app/internal/models"
+	"gorm.io/gorm"
+)
+
+type PostgresFecalEggCountRepository struct {
+	db *gorm.DB
+}
+
+func NewFecalEggCountRepository(db *gorm.DB) FecalEggCountRepository {
+	return &PostgresFecalEggCountRepository{db: db}
+}
+
+func (r *PostgresFecalEggCountRepository) Create(ctx context.Context, count *models.FecalEggCount) error {
+	return r.db.WithContext(ctx).Create(count).Error
+}
+
+// ListByHorse returns counts oldest first, the order the classification works through them
+func (r *PostgresFecalEggCountRepository) ListByHorse(ctx context.Context, horseID uint) ([]models.FecalEggCount, error) {
+	var counts []models.FecalEggCount
+	if err := r.db.WithContext(ctx).
+		Where("horse_id = ?", horseID).
+		Order("sample_date ASC").
+		Find(&counts).Error; err != nil {
+		return nil, err
+	}
+	return counts, nil
+}
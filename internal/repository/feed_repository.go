@@ -0,0 +1,181 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/polyfant/hulta_pregnancy_app/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PostgresFeedRepository struct {
+	db *gorm.DB
+}
+
+func NewFeedRepository(db *gorm.DB) FeedRepository {
+	return &PostgresFeedRepository{db: db}
+}
+
+func (r *PostgresFeedRepository) ListTypes(ctx context.Context) ([]models.FeedType, error) {
+	var feedTypes []models.FeedType
+	if err := r.db.WithContext(ctx).Order("category ASC, name ASC").Find(&feedTypes).Error; err != nil {
+		return nil, err
+	}
+	return feedTypes, nil
+}
+
+// GetType returns nil without an error when there is no such feed type
+func (r *PostgresFeedRepository) GetType(ctx context.Context, id uint) (*models.FeedType, error) {
+	var feedType models.FeedType
+	err := r.db.WithContext(ctx).First(&feedType, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &feedType, nil
+}
+
+func (r *PostgresFeedRepository) CreateType(ctx context.Context, feedType *models.FeedType) error {
+	return r.db.WithContext(ctx).Create(feedType).Error
+}
+
+func (r *PostgresFeedRepository) SaveType(ctx context.Context, feedType *models.FeedType) error {
+	return r.db.WithContext(ctx).Save(feedType).Error
+}
+
+// ListInventory returns the user's lots with their feed types, soonest to expire first
+func (r *PostgresFeedRepository) ListInventory(ctx context.Context, userID string) ([]models.FeedInventoryItem, error) {
+	var items []models.FeedInventoryItem
+	if err := r.db.WithContext(ctx).
+		Preload("FeedType").
+		Where("user_id = ?", userID).
+		Order("expiry_date ASC NULLS LAST, purchase_date ASC, id ASC").
+		Find(&items).Error; err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// GetInventoryItem returns nil without an error when there is no such lot
+func (r *PostgresFeedRepository) GetInventoryItem(ctx context.Context, id uint) (*models.FeedInventoryItem, error) {
+	var item models.FeedInventoryItem
+	err := r.db.WithContext(ctx).Preload("FeedType").First(&item, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+// SaveInventoryItem creates the lot or, when it has an ID, updates it
+func (r *PostgresFeedRepository) SaveInventoryItem(ctx context.Context, item *models.FeedInventoryItem) error {
+	return r.db.WithContext(ctx).Omit("FeedType").Save(item).Error
+}
+
+func (r *PostgresFeedRepository) DeleteInventoryItem(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Delete(&models.FeedInventoryItem{}, id).Error
+}
+
+func (r *PostgresFeedRepository) ListRations(ctx context.Context, horseID uint) ([]models.FeedRation, error) {
+	var rations []models.FeedRation
+	if err := r.db.WithContext(ctx).
+		Preload("FeedType").
+		Where("horse_id = ?", horseID).
+		Order("id ASC").
+		Find(&rations).Error; err != nil {
+		return nil, err
+	}
+	return rations, nil
+}
+
+func (r *PostgresFeedRepository) ReplaceRations(ctx context.Context, horseID uint, rations []models.FeedRation) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("horse_id = ?", horseID).Delete(&models.FeedRation{}).Error; err != nil {
+			return err
+		}
+		if len(rations) == 0 {
+			return nil
+		}
+		return tx.Omit("FeedType").Create(&rations).Error
+	})
+}
+
+// CreateLog stores the feeding and draws the lots down in one transaction.
+// A lot is never drawn below zero, even if another feeding took from it
+// since the draws were worked out.
+func (r *PostgresFeedRepository) CreateLog(ctx context.Context, log *models.FeedLog, draws []models.FeedDraw) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("FeedType").Create(log).Error; err != nil {
+			return err
+		}
+		for _, draw := range draws {
+			if err := tx.Model(&models.FeedInventoryItem{}).
+				Where("id = ?", draw.InventoryID).
+				Updates(map[string]interface{}{
+					"quantity":   gorm.Expr("GREATEST(quantity - ?, 0)", draw.Amount),
+					"updated_at": time.Now(),
+				}).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// ListLogs returns the horse's feedings, most recent first
+func (r *PostgresFeedRepository) ListLogs(ctx context.Context, horseID uint, since *time.Time) ([]models.FeedLog, error) {
+	query := r.db.WithContext(ctx).Preload("FeedType").Where("horse_id = ?", horseID)
+	if since != nil {
+		query = query.Where("feeding_time >= ?", *since)
+	}
+	var logs []models.FeedLog
+	if err := query.Order("feeding_time DESC").Find(&logs).Error; err != nil {
+		return nil, err
+	}
+	return logs, nil
+}
+
+func (r *PostgresFeedRepository) ListRationsForHorses(ctx context.Context, horseIDs []uint) ([]models.FeedRation, error) {
+	var rations []models.FeedRation
+	if len(horseIDs) == 0 {
+		return rations, nil
+	}
+	if err := r.db.WithContext(ctx).
+		Preload("FeedType").
+		Where("horse_id IN ?", horseIDs).
+		Order("horse_id ASC, id ASC").
+		Find(&rations).Error; err != nil {
+		return nil, err
+	}
+	return rations, nil
+}
+
+func (r *PostgresFeedRepository) ListInventoryUsers(ctx context.Context) ([]string, error) {
+	var users []string
+	if err := r.db.WithContext(ctx).
+		Model(&models.FeedInventoryItem{}).
+		Distinct().
+		Order("user_id ASC").
+		Pluck("user_id", &users).Error; err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// MarkAlertSent relies on the unique (user_id, item, due_date) index so
+// concurrent checks cannot both claim the same alert
+func (r *PostgresFeedRepository) MarkAlertSent(ctx context.Context, alert *models.FeedAlert) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(alert)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
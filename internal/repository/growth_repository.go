@@ -32,7 +32,7 @@ func (r *PostgresGrowthRepository) CreateGrowthData(ctx context.Context, growthD
 
 func (r *PostgresGrowthRepository) GetGrowthDataByFoalID(ctx context.Context, foalID uint) ([]models.GrowthData, error) {
 	var growthData []models.GrowthData
-	result := r.db.WithContext(ctx).Where("foal_id = ?", foalID).Order("age ASC").Find(&growthData)
+	result := r.db.WithContext(ctx).Where("foal_id = ?", foalID).Order("measurement_date ASC").Find(&growthData)
 	if result.Error != nil {
 		return nil, fmt.Errorf("failed to retrieve growth data: %w", result.Error)
 	}
@@ -0,0 +1,35 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/polyfant/hulta_pregnancy_app/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PostgresHealthReminderRepository struct {
+	db *gorm.DB
+}
+
+func NewHealthReminderRepository(db *gorm.DB) HealthReminderRepository {
+	return &PostgresHealthReminderRepository{db: db}
+}
+
+// MarkSent relies on the unique (horse_id, item, due_date) index so concurrent
+// sweeps cannot both claim the same reminder
+func (r *PostgresHealthReminderRepository) MarkSent(ctx context.Context, reminder *models.HealthReminder) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(reminder)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *PostgresHealthReminderRepository) Unmark(ctx context.Context, reminder *models.HealthReminder) error {
+	return r.db.WithContext(ctx).
+		Where("horse_id = ? AND item = ? AND due_date = ?", reminder.HorseID, reminder.Item, reminder.DueDate).
+		Delete(&models.HealthReminder{}).Error
+}
@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/polyfant/hulta_pregnancy_app/internal/models"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHorseRepository_Delete(t *testing.T) {
+	db := setupTestDB(t)
+	tables := []interface{}{&models.FeedLog{}, &models.BreedingCost{}, &models.HealthRecord{}, &models.Horse{}, &models.FeedType{}}
+	for _, table := range tables {
+		require.NoError(t, db.Migrator().DropTable(table))
+	}
+	// Migrating the horse adds foreign keys from its health records and
+	// breeding costs that, like the ones in the SQL migrations, do not cascade
+	require.NoError(t, db.AutoMigrate(&models.Horse{}, &models.HealthRecord{}, &models.BreedingCost{}, &models.FeedType{}, &models.FeedLog{}))
+	repo := NewHorseRepository(db)
+	ctx := context.Background()
+
+	horse := &models.Horse{UserID: "owner", Name: "Bella", Gender: models.GenderMare, BirthDate: time.Now().AddDate(-8, 0, 0)}
+	require.NoError(t, db.Create(horse).Error)
+	feedType := &models.FeedType{Name: "Hay", Category: "hay", Unit: "kg"}
+	require.NoError(t, db.Create(feedType).Error)
+	require.NoError(t, db.Create(&models.HealthRecord{HorseID: horse.ID, UserID: "owner", Type: "Vaccination", Date: time.Now()}).Error)
+	require.NoError(t, db.Create(&models.BreedingCost{HorseID: horse.ID, UserID: "owner", Type: "Stud fee", Amount: 800, Date: time.Now()}).Error)
+	require.NoError(t, db.Create(&models.FeedLog{HorseID: horse.ID, UserID: "owner", FeedTypeID: feedType.ID, Amount: 8, FeedingTime: time.Now()}).Error)
+
+	require.NoError(t, repo.Delete(ctx, horse.ID))
+
+	for _, table := range []interface{}{&models.Horse{}, &models.HealthRecord{}, &models.BreedingCost{}, &models.FeedLog{}} {
+		var count int64
+		require.NoError(t, db.Model(table).Count(&count).Error)
+		require.Zero(t, count)
+	}
+}
@@ -18,6 +18,11 @@ type HorseRepository interface {
 	GetOffspring(ctx context.Context, horseID uint) ([]models.Horse, error)
 	GetFamilyTree(ctx context.Context, horseID uint) (*models.FamilyTree, error)
 	GetPregnant(ctx context.Context, userID string) ([]models.Horse, error)
+	ListByOrganization(ctx context.Context, orgID uint) ([]models.Horse, error)
+	ListAllByUser(ctx context.Context, userID string) ([]models.Horse, error)
+	ListActive(ctx context.Context) ([]models.Horse, error)
+	CreateBatch(ctx context.Context, items []models.HorseImportItem) error
+	UpdateStatus(ctx context.Context, id uint, status models.HorseStatus, date time.Time, reason string) error
 }
 
 type ExpenseRepository interface {
@@ -68,6 +73,8 @@ type PregnancyRepository interface {
 	GetPreFoalingSigns(ctx context.Context, horseID uint) ([]models.PreFoalingSign, error)
 	AddPreFoalingSign(ctx context.Context, sign *models.PreFoalingSign) error
 	GetCurrentPregnancy(ctx context.Context, horseID uint) (*models.Pregnancy, error)
+	// GetLatestCompleted returns the mare's last pregnancy that ended in a foal, or nil
+	GetLatestCompleted(ctx context.Context, horseID uint) (*models.Pregnancy, error)
 	UpdatePregnancyStatus(ctx context.Context, horseID uint, isPregnant bool, conceptionDate *time.Time) error
 	UpdatePreFoalingChecklistItem(ctx context.Context, item *models.PreFoalingChecklistItem) error
 	GetActive(ctx context.Context, userID string) ([]models.Pregnancy, error)
@@ -75,9 +82,136 @@ type PregnancyRepository interface {
 	GetPreFoaling(ctx context.Context, horseID uint) ([]models.PreFoalingSign, error)
 }
 
+type OrganizationRepository interface {
+	Create(ctx context.Context, org *models.Organization) error
+	GetByID(ctx context.Context, id uint) (*models.Organization, error)
+	ListByUser(ctx context.Context, userID string) ([]models.Organization, error)
+	GetMember(ctx context.Context, orgID uint, userID string) (*models.OrganizationMember, error)
+	GetMembers(ctx context.Context, orgID uint) ([]models.OrganizationMember, error)
+	GetMemberships(ctx context.Context, userID string) ([]models.OrganizationMember, error)
+	AddMember(ctx context.Context, member *models.OrganizationMember) error
+	UpdateMember(ctx context.Context, member *models.OrganizationMember) error
+	RemoveMember(ctx context.Context, orgID uint, userID string) error
+}
+
+type MediaRepository interface {
+	Create(ctx context.Context, media *models.HorseMedia) error
+	GetByID(ctx context.Context, id uint) (*models.HorseMedia, error)
+	ListByHorse(ctx context.Context, horseID uint, filter models.MediaFilter) ([]models.HorseMedia, error)
+	Delete(ctx context.Context, id uint) error
+}
+
+type VitalSignsRepository interface {
+	Create(ctx context.Context, record *models.VitalSignsRecord) error
+	ListByHorse(ctx context.Context, horseID uint, since *time.Time) ([]models.VitalSignsRecord, error)
+}
+
+type FecalEggCountRepository interface {
+	Create(ctx context.Context, count *models.FecalEggCount) error
+	ListByHorse(ctx context.Context, horseID uint) ([]models.FecalEggCount, error)
+}
+
+type TreatmentRepository interface {
+	CreatePlan(ctx context.Context, plan *models.TreatmentPlan) error
+	GetPlan(ctx context.Context, id uint) (*models.TreatmentPlan, error)
+	UpdatePlan(ctx context.Context, plan *models.TreatmentPlan) error
+	ListPlansByHorse(ctx context.Context, horseID uint) ([]models.TreatmentPlan, error)
+	CreateDose(ctx context.Context, dose *models.DoseAdministration) error
+	ListDosesByHorse(ctx context.Context, horseID uint) ([]models.DoseAdministration, error)
+}
+
+type BodyConditionRepository interface {
+	Create(ctx context.Context, record *models.BodyCondition) error
+	ListByHorse(ctx context.Context, horseID uint, since *time.Time) ([]models.BodyCondition, error)
+}
+
+type WeightRepository interface {
+	Create(ctx context.Context, record *models.WeightRecord) error
+	ListByHorse(ctx context.Context, horseID uint, since *time.Time) ([]models.WeightRecord, error)
+	GetLatest(ctx context.Context, horseID uint) (*models.WeightRecord, error)
+}
+
+type NeonatalRepository interface {
+	GetByHorse(ctx context.Context, horseID uint) (*models.NeonatalRecord, error)
+	Save(ctx context.Context, record *models.NeonatalRecord) error
+	ListFoaledSince(ctx context.Context, since time.Time) ([]models.NeonatalRecord, error)
+	CreateAssessment(ctx context.Context, assessment *models.NeonatalAssessment) error
+	ListAssessments(ctx context.Context, horseID uint) ([]models.NeonatalAssessment, error)
+}
+
+type FeedRepository interface {
+	ListTypes(ctx context.Context) ([]models.FeedType, error)
+	GetType(ctx context.Context, id uint) (*models.FeedType, error)
+	CreateType(ctx context.Context, feedType *models.FeedType) error
+	SaveType(ctx context.Context, feedType *models.FeedType) error
+	ListInventory(ctx context.Context, userID string) ([]models.FeedInventoryItem, error)
+	GetInventoryItem(ctx context.Context, id uint) (*models.FeedInventoryItem, error)
+	SaveInventoryItem(ctx context.Context, item *models.FeedInventoryItem) error
+	DeleteInventoryItem(ctx context.Context, id uint) error
+	ListRations(ctx context.Context, horseID uint) ([]models.FeedRation, error)
+	// ReplaceRations swaps the horse's whole ration for the given one
+	ReplaceRations(ctx context.Context, horseID uint, rations []models.FeedRation) error
+	// CreateLog stores a feeding and takes the drawn amounts off the inventory lots
+	CreateLog(ctx context.Context, log *models.FeedLog, draws []models.FeedDraw) error
+	ListLogs(ctx context.Context, horseID uint, since *time.Time) ([]models.FeedLog, error)
+	ListRationsForHorses(ctx context.Context, horseIDs []uint) ([]models.FeedRation, error)
+	// ListInventoryUsers returns the users who have ever stocked feed
+	ListInventoryUsers(ctx context.Context) ([]string, error)
+	// MarkAlertSent records an alert and reports false if it was already sent
+	MarkAlertSent(ctx context.Context, alert *models.FeedAlert) (bool, error)
+}
+
+type WeaningRepository interface {
+	GetByHorse(ctx context.Context, horseID uint) (*models.WeaningRecord, error)
+	Save(ctx context.Context, record *models.WeaningRecord) error
+	CreateCheck(ctx context.Context, check *models.WeaningCheck) error
+	ListChecks(ctx context.Context, horseID uint) ([]models.WeaningCheck, error)
+}
+
+type PastureRepository interface {
+	ListPastures(ctx context.Context, userID string) ([]models.Pasture, error)
+	GetPasture(ctx context.Context, id uint) (*models.Pasture, error)
+	SavePasture(ctx context.Context, pasture *models.Pasture) error
+	DeletePasture(ctx context.Context, id uint) error
+	// GetTurnout returns the horse's open turnout with its pasture
+	GetTurnout(ctx context.Context, horseID uint) (*models.Turnout, error)
+	// StartTurnout ends the horse's open turnout, if any, when the new one starts
+	StartTurnout(ctx context.Context, turnout *models.Turnout) error
+	EndTurnout(ctx context.Context, turnoutID uint, at time.Time) error
+	// ListTurnouts returns the turnouts on the pastures that were still open at since
+	ListTurnouts(ctx context.Context, pastureIDs []uint, since time.Time) ([]models.Turnout, error)
+}
+
+type FarrierRepository interface {
+	// CreateVisit stores the visit and, when given, the expense it is linked to
+	CreateVisit(ctx context.Context, visit *models.FarrierVisit, expense *models.Expense) error
+	ListVisitsByHorse(ctx context.Context, horseID uint) ([]models.FarrierVisit, error)
+	GetSchedule(ctx context.Context, horseID uint) (*models.FarrierSchedule, error)
+	SaveSchedule(ctx context.Context, schedule *models.FarrierSchedule) error
+	DeleteSchedule(ctx context.Context, horseID uint) error
+}
+
+type HealthReminderRepository interface {
+	// MarkSent records a reminder and reports false if one was already sent for the same due date
+	MarkSent(ctx context.Context, reminder *models.HealthReminder) (bool, error)
+	// Unmark removes a reminder recorded by MarkSent so that it is sent again
+	Unmark(ctx context.Context, reminder *models.HealthReminder) error
+}
+
+type VetAccessRepository interface {
+	Create(ctx context.Context, grant *models.VetAccessGrant) error
+	GetByID(ctx context.Context, id uint) (*models.VetAccessGrant, error)
+	GetByTokenHash(ctx context.Context, tokenHash string) (*models.VetAccessGrant, error)
+	ListByOwner(ctx context.Context, ownerID string) ([]models.VetAccessGrant, error)
+	ListByVet(ctx context.Context, vetUserID string) ([]models.VetAccessGrant, error)
+	ListActiveForVetAndHorse(ctx context.Context, vetUserID string, horseID uint, now time.Time) ([]models.VetAccessGrant, error)
+	Revoke(ctx context.Context, id uint, revokedAt time.Time) error
+}
+
 type HealthRepository interface {
 	CreateRecord(ctx context.Context, record *models.HealthRecord) error
 	GetRecords(ctx context.Context, horseID uint) ([]models.HealthRecord, error)
+	FindRecords(ctx context.Context, horseID uint, filter models.HealthRecordFilter) ([]models.HealthRecord, error)
 	UpdateRecord(ctx context.Context, record *models.HealthRecord) error
 	DeleteRecord(ctx context.Context, id uint) error
 }
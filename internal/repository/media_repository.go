@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/polyfant/hulta_pregnancy_app/internal/models"
+	"gorm.io/gorm"
+)
+
+type PostgresMediaRepository struct {
+	db *gorm.DB
+}
+
+func NewMediaRepository(db *gorm.DB) MediaRepository {
+	return &PostgresMediaRepository{db: db}
+}
+
+func (r *PostgresMediaRepository) Create(ctx context.Context, media *models.HorseMedia) error {
+	return r.db.WithContext(ctx).Create(media).Error
+}
+
+func (r *PostgresMediaRepository) GetByID(ctx context.Context, id uint) (*models.HorseMedia, error) {
+	var media models.HorseMedia
+	if err := r.db.WithContext(ctx).First(&media, id).Error; err != nil {
+		return nil, err
+	}
+	return &media, nil
+}
+
+func (r *PostgresMediaRepository) ListByHorse(ctx context.Context, horseID uint, filter models.MediaFilter) ([]models.HorseMedia, error) {
+	query := r.db.WithContext(ctx).Where("horse_id = ?", horseID)
+	if filter.Type != "" {
+		query = query.Where("type = ?", filter.Type)
+	}
+	if filter.HealthRecordID != nil {
+		query = query.Where("health_record_id = ?", *filter.HealthRecordID)
+	}
+	if filter.PregnancyEventID != nil {
+		query = query.Where("pregnancy_event_id = ?", *filter.PregnancyEventID)
+	}
+
+	var media []models.HorseMedia
+	err := query.Order("created_at DESC").Find(&media).Error
+	return media, err
+}
+
+func (r *PostgresMediaRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Delete(&models.HorseMedia{}, id).Error
+}
@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/polyfant/hulta_pregnancy_app/internal/models"
+	"gorm.io/gorm"
+)
+
+type PostgresNeonatalRepository struct {
+	db *gorm.DB
+}
+
+func NewNeonatalRepository(db *gorm.DB) NeonatalRepository {
+	return &PostgresNeonatalRepository{db: db}
+}
+
+// GetByHorse returns the foal's neonatal record, or nil if monitoring was never started
+func (r *PostgresNeonatalRepository) GetByHorse(ctx context.Context, horseID uint) (*models.NeonatalRecord, error) {
+	var record models.NeonatalRecord
+	err := r.db.WithContext(ctx).Where("horse_id = ?", horseID).First(&record).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+// Save creates the record or, when it has an ID, updates it
+func (r *PostgresNeonatalRepository) Save(ctx context.Context, record *models.NeonatalRecord) error {
+	return r.db.WithContext(ctx).Save(record).Error
+}
+
+// ListFoaledSince returns the records of foals born at or after since, for the milestone sweep
+func (r *PostgresNeonatalRepository) ListFoaledSince(ctx context.Context, since time.Time) ([]models.NeonatalRecord, error) {
+	var records []models.NeonatalRecord
+	if err := r.db.WithContext(ctx).Where("foaled_at >= ?", since).Order("foaled_at ASC").Find(&records).Error; err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+func (r *PostgresNeonatalRepository) CreateAssessment(ctx context.Context, assessment *models.NeonatalAssessment) error {
+	return r.db.WithContext(ctx).Create(assessment).Error
+}
+
+// ListAssessments returns the foal's APGAR assessments oldest first
+func (r *PostgresNeonatalRepository) ListAssessments(ctx context.Context, horseID uint) ([]models.NeonatalAssessment, error) {
+	var assessments []models.NeonatalAssessment
+	if err := r.db.WithContext(ctx).Where("horse_id = ?", horseID).Order("assessed_at ASC").Find(&assessments).Error; err != nil {
+		return nil, err
+	}
+	return assessments, nil
+}
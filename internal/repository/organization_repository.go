@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/polyfant/hulta_pregnancy_app/internal/models"
+	"gorm.io/gorm"
+)
+
+type PostgresOrganizationRepository struct {
+	db *gorm.DB
+}
+
+func NewOrganizationRepository(db *gorm.DB) OrganizationRepository {
+	return &PostgresOrganizationRepository{db: db}
+}
+
+// Create stores a new organization and registers its owner as a member
+func (r *PostgresOrganizationRepository) Create(ctx context.Context, org *models.Organization) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(org).Error; err != nil {
+			return err
+		}
+		owner := &models.OrganizationMember{
+			OrganizationID: org.ID,
+			UserID:         org.OwnerID,
+			Role:           models.MemberRoleOwner,
+		}
+		return tx.Create(owner).Error
+	})
+}
+
+func (r *PostgresOrganizationRepository) GetByID(ctx context.Context, id uint) (*models.Organization, error) {
+	var org models.Organization
+	if err := r.db.WithContext(ctx).Preload("Members").First(&org, id).Error; err != nil {
+		return nil, err
+	}
+	return &org, nil
+}
+
+func (r *PostgresOrganizationRepository) ListByUser(ctx context.Context, userID string) ([]models.Organization, error) {
+	var orgs []models.Organization
+	err := r.db.WithContext(ctx).
+		Joins("JOIN organization_members ON organization_members.organization_id = organizations.id").
+		Where("organization_members.user_id = ?", userID).
+		Find(&orgs).Error
+	return orgs, err
+}
+
+func (r *PostgresOrganizationRepository) GetMember(ctx context.Context, orgID uint, userID string) (*models.OrganizationMember, error) {
+	var member models.OrganizationMember
+	err := r.db.WithContext(ctx).
+		Where("organization_id = ? AND user_id = ?", orgID, userID).
+		First(&member).Error
+	if err != nil {
+		return nil, err
+	}
+	return &member, nil
+}
+
+func (r *PostgresOrganizationRepository) GetMembers(ctx context.Context, orgID uint) ([]models.OrganizationMember, error) {
+	var members []models.OrganizationMember
+	err := r.db.WithContext(ctx).
+		Where("organization_id = ?", orgID).
+		Order("created_at ASC").
+		Find(&members).Error
+	return members, err
+}
+
+func (r *PostgresOrganizationRepository) GetMemberships(ctx context.Context, userID string) ([]models.OrganizationMember, error) {
+	var members []models.OrganizationMember
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Find(&members).Error
+	return members, err
+}
+
+func (r *PostgresOrganizationRepository) AddMember(ctx context.Context, member *models.OrganizationMember) error {
+	return r.db.WithContext(ctx).Create(member).Error
+}
+
+func (r *PostgresOrganizationRepository) UpdateMember(ctx context.Context, member *models.OrganizationMember) error {
+	return r.db.WithContext(ctx).Save(member).Error
+}
+
+func (r *PostgresOrganizationRepository) RemoveMember(ctx context.Context, orgID uint, userID string) error {
+	return r.db.WithContext(ctx).
+		Where("organization_id = ? AND user_id = ?", orgID, userID).
+		Delete(&models.OrganizationMember{}).Error
+}
@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/polyfant/hulta_pregnancy_app/internal/models"
+	"gorm.io/gorm"
+)
+
+type PostgresPastureRepository struct {
+	db *gorm.DB
+}
+
+func NewPastureRepository(db *gorm.DB) PastureRepository {
+	return &PostgresPastureRepository{db: db}
+}
+
+func (r *PostgresPastureRepository) ListPastures(ctx context.Context, userID string) ([]models.Pasture, error) {
+	var pastures []models.Pasture
+	if err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("name ASC, id ASC").
+		Find(&pastures).Error; err != nil {
+		return nil, err
+	}
+	return pastures, nil
+}
+
+// GetPasture returns nil without an error when there is no such pasture
+func (r *PostgresPastureRepository) GetPasture(ctx context.Context, id uint) (*models.Pasture, error) {
+	var pasture models.Pasture
+	err := r.db.WithContext(ctx).First(&pasture, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &pasture, nil
+}
+
+// SavePasture creates the pasture or, when it has an ID, updates it
+func (r *PostgresPastureRepository) SavePasture(ctx context.Context, pasture *models.Pasture) error {
+	return r.db.WithContext(ctx).Save(pasture).Error
+}
+
+// DeletePasture removes the pasture and its turnouts
+func (r *PostgresPastureRepository) DeletePasture(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("pasture_id = ?", id).Delete(&models.Turnout{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.Pasture{}, id).Error
+	})
+}
+
+// GetTurnout returns nil without an error when the horse is not turned out
+func (r *PostgresPastureRepository) GetTurnout(ctx context.Context, horseID uint) (*models.Turnout, error) {
+	var turnout models.Turnout
+	err := r.db.WithContext(ctx).
+		Preload("Pasture").
+		Where("horse_id = ? AND end_date IS NULL", horseID).
+		First(&turnout).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &turnout, nil
+}
+
+func (r *PostgresPastureRepository) StartTurnout(ctx context.Context, turnout *models.Turnout) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Turnout{}).
+			Where("horse_id = ? AND end_date IS NULL", turnout.HorseID).
+			Updates(map[string]interface{}{
+				"end_date":   turnout.StartDate,
+				"updated_at": time.Now(),
+			}).Error; err != nil {
+			return err
+		}
+		return tx.Omit("Pasture").Create(turnout).Error
+	})
+}
+
+func (r *PostgresPastureRepository) EndTurnout(ctx context.Context, turnoutID uint, at time.Time) error {
+	return r.db.WithContext(ctx).
+		Model(&models.Turnout{}).
+		Where("id = ?", turnoutID).
+		Updates(map[string]interface{}{
+			"end_date":   at,
+			"updated_at": time.Now(),
+		}).Error
+}
+
+func (r *PostgresPastureRepository) ListTurnouts(ctx context.Context, pastureIDs []uint, since time.Time) ([]models.Turnout, error) {
+	var turnouts []models.Turnout
+	if len(pastureIDs) == 0 {
+		return turnouts, nil
+	}
+	if err := r.db.WithContext(ctx).
+		Where("pasture_id IN ?", pastureIDs).
+		Where("end_date IS NULL OR end_date >= ?", since).
+		Order("pasture_id ASC, start_date ASC").
+		Find(&turnouts).Error; err != nil {
+		return nil, err
+	}
+	return turnouts, nil
+}
@@ -2,6 +2,9 @@ package repository
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"time"
 
 	"github.com/polyfant/hulta_pregnancy_app/internal/models"
@@ -97,8 +100,27 @@ func (r *PostgresBreedingRepository) CreateRecord(ctx context.Context, record *m
 	return r.db.WithContext(ctx).Create(record).Error
 }
 
+// Delete permanently removes a horse. Use UpdateStatus to archive instead.
+// The records whose foreign keys do not cascade go with it, in the same
+// transaction.
 func (r *PostgresHorseRepository) Delete(ctx context.Context, id uint) error {
-	return r.db.WithContext(ctx).Delete(&models.Horse{}, id).Error
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		dependents := []interface{}{
+			&models.Expense{},
+			&models.RecurringExpense{},
+			&models.HealthRecord{},
+			&models.BreedingCost{},
+			&models.WeatherImpact{},
+			&models.FeedRation{},
+			&models.FeedLog{},
+		}
+		for _, dependent := range dependents {
+			if err := tx.Unscoped().Where("horse_id = ?", id).Delete(dependent).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Delete(&models.Horse{}, id).Error
+	})
 }
 
 func (r *PostgresUserRepository) GetByEmail(ctx context.Context, email string) (*models.User, error) {
@@ -145,6 +167,45 @@ func (r *PostgresBreedingRepository) GetCosts(ctx context.Context, horseID uint)
     return costs, nil
 }
 
+// FindRecords queries health records of a horse. Payload fields are matched
+// with JSONB containment so the GIN index on details can be used.
+func (r *PostgresHealthRepository) FindRecords(ctx context.Context, horseID uint, filter models.HealthRecordFilter) ([]models.HealthRecord, error) {
+    query := r.db.WithContext(ctx).Where("horse_id = ?", horseID)
+    if filter.Type != "" {
+        query = query.Where("type = ?", filter.Type)
+    }
+    if filter.From != nil {
+        query = query.Where("date >= ?", *filter.From)
+    }
+    if filter.To != nil {
+        query = query.Where("date <= ?", *filter.To)
+    }
+    if len(filter.Fields) > 0 {
+        contains := make(map[string]map[string]string)
+        for field, value := range filter.Fields {
+            key, name, ok := models.DetailFieldPath(filter.Type, field)
+            if !ok {
+                return nil, fmt.Errorf("%w: cannot filter %s records by %q", models.ErrInvalidHealthRecord, filter.Type, field)
+            }
+            if contains[key] == nil {
+                contains[key] = make(map[string]string)
+            }
+            contains[key][name] = value
+        }
+        payload, err := json.Marshal(contains)
+        if err != nil {
+            return nil, err
+        }
+        query = query.Where("details @> ?::jsonb", string(payload))
+    }
+
+    var records []models.HealthRecord
+    if err := query.Order("date DESC").Find(&records).Error; err != nil {
+        return nil, err
+    }
+    return records, nil
+}
+
 func (r *PostgresBreedingRepository) GetRecords(ctx context.Context, horseID uint) ([]models.BreedingRecord, error) {
     var records []models.BreedingRecord
     if err := r.db.WithContext(ctx).Where("horse_id = ?", horseID).Find(&records).Error; err != nil {
@@ -190,14 +251,14 @@ func (r *PostgresHorseRepository) GetFamilyTree(ctx context.Context, horseID uin
 func (r *PostgresUserRepository) GetDashboardStats(ctx context.Context, userID string) (*models.DashboardStats, error) {
     var stats models.DashboardStats
     
-    // Get total number of horses
-    if err := r.db.WithContext(ctx).Model(&models.Horse{}).Where("owner_id = ?", userID).Count(&stats.TotalHorses).Error; err != nil {
+    // Get total number of horses still in the user's care
+    if err := r.db.WithContext(ctx).Model(&models.Horse{}).Where("user_id = ? AND status IN ?", userID, models.ActiveHorseStatuses).Count(&stats.TotalHorses).Error; err != nil {
         return nil, err
     }
     
     // Get number of pregnant mares
     if err := r.db.WithContext(ctx).Model(&models.Horse{}).
-        Where("owner_id = ? AND is_pregnant = ?", userID, true).
+        Where("user_id = ? AND status IN ? AND is_pregnant = ?", userID, models.ActiveHorseStatuses, true).
         Count(&stats.PregnantMares).Error; err != nil {
         return nil, err
     }
@@ -206,7 +267,7 @@ func (r *PostgresUserRepository) GetDashboardStats(ctx context.Context, userID s
     var totalExpenses float64
     if err := r.db.WithContext(ctx).Model(&models.BreedingCost{}).
         Joins("JOIN horses ON horses.id = breeding_costs.horse_id").
-        Where("horses.owner_id = ?", userID).
+        Where("horses.user_id = ?", userID).
         Select("COALESCE(SUM(amount), 0)").
         Scan(&totalExpenses).Error; err != nil {
         return nil, err
@@ -215,7 +276,7 @@ func (r *PostgresUserRepository) GetDashboardStats(ctx context.Context, userID s
     
     // Get upcoming foalings
     if err := r.db.WithContext(ctx).Model(&models.Horse{}).
-        Where("owner_id = ? AND is_pregnant = ? AND conception_date IS NOT NULL", userID, true).
+        Where("user_id = ? AND status IN ? AND is_pregnant = ? AND conception_date IS NOT NULL", userID, models.ActiveHorseStatuses, true).
         Count(&stats.UpcomingFoalings).Error; err != nil {
         return nil, err
     }
@@ -268,10 +329,12 @@ func (r *PostgresHorseRepository) GetPregnantHorses(ctx context.Context, userID
     return horses, err
 }
 
+// GetByHorseID returns the horse's latest pregnancy
 func (r *PostgresPregnancyRepository) GetByHorseID(ctx context.Context, horseID uint) (*models.Pregnancy, error) {
     var pregnancy models.Pregnancy
     err := r.db.WithContext(ctx).
         Where("horse_id = ?", horseID).
+        Order("start_date DESC").
         First(&pregnancy).Error
     if err != nil {
         return nil, err
@@ -307,7 +370,65 @@ func (r *PostgresPregnancyRepository) GetCurrentPregnancy(ctx context.Context, h
     return &pregnancy, nil
 }
 
+func (r *PostgresPregnancyRepository) GetLatestCompleted(ctx context.Context, horseID uint) (*models.Pregnancy, error) {
+    var pregnancy models.Pregnancy
+    err := r.db.WithContext(ctx).
+        Where("horse_id = ? AND status = ? AND end_date IS NOT NULL", horseID, models.PregnancyStatusComplete).
+        Order("end_date DESC").
+        First(&pregnancy).Error
+    if errors.Is(err, gorm.ErrRecordNotFound) {
+        return nil, nil
+    }
+    if err != nil {
+        return nil, err
+    }
+    return &pregnancy, nil
+}
+
+// ListByUser returns the user's horses that are still in their care
 func (r *PostgresHorseRepository) ListByUser(ctx context.Context, userID string) ([]models.Horse, error) {
+    var horses []models.Horse
+    err := r.db.WithContext(ctx).
+        Where("user_id = ? AND status IN ?", userID, models.ActiveHorseStatuses).
+        Find(&horses).Error
+    return horses, err
+}
+
+// CreateBatch stores all imported horses in one transaction and then links
+// parents that were part of the same batch
+func (r *PostgresHorseRepository) CreateBatch(ctx context.Context, items []models.HorseImportItem) error {
+    return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+        for _, item := range items {
+            if err := tx.Create(item.Horse).Error; err != nil {
+                return err
+            }
+        }
+
+        for _, item := range items {
+            updates := map[string]interface{}{}
+            if item.MotherRef != nil {
+                motherID := items[*item.MotherRef].Horse.ID
+                item.Horse.MotherId, item.Horse.DamID = &motherID, &motherID
+                updates["mother_id"], updates["dam_id"] = motherID, motherID
+            }
+            if item.FatherRef != nil {
+                fatherID := items[*item.FatherRef].Horse.ID
+                item.Horse.FatherId, item.Horse.SireID = &fatherID, &fatherID
+                updates["father_id"], updates["sire_id"] = fatherID, fatherID
+            }
+            if len(updates) == 0 {
+                continue
+            }
+            if err := tx.Model(&models.Horse{}).Where("id = ?", item.Horse.ID).Updates(updates).Error; err != nil {
+                return err
+            }
+        }
+        return nil
+    })
+}
+
+// ListAllByUser returns every horse the user has owned, including sold, deceased and archived ones
+func (r *PostgresHorseRepository) ListAllByUser(ctx context.Context, userID string) ([]models.Horse, error) {
     var horses []models.Horse
     err := r.db.WithContext(ctx).
         Where("user_id = ?", userID).
@@ -315,10 +436,47 @@ func (r *PostgresHorseRepository) ListByUser(ctx context.Context, userID string)
     return horses, err
 }
 
-func (r *PostgresPregnancyRepository) GetEvents(ctx context.Context, pregnancyID uint) ([]models.PregnancyEvent, error) {
+// ListActive returns the active horses of all users, for background jobs
+func (r *PostgresHorseRepository) ListActive(ctx context.Context) ([]models.Horse, error) {
+    var horses []models.Horse
+    err := r.db.WithContext(ctx).
+        Where("status IN ?", models.ActiveHorseStatuses).
+        Find(&horses).Error
+    return horses, err
+}
+
+func (r *PostgresHorseRepository) ListByOrganization(ctx context.Context, orgID uint) ([]models.Horse, error) {
+    var horses []models.Horse
+    err := r.db.WithContext(ctx).
+        Where("organization_id = ? AND status IN ?", orgID, models.ActiveHorseStatuses).
+        Find(&horses).Error
+    return horses, err
+}
+
+// UpdateStatus records a lifecycle transition. Archiving also sets DeletedAt;
+// any other status clears it.
+func (r *PostgresHorseRepository) UpdateStatus(ctx context.Context, id uint, status models.HorseStatus, date time.Time, reason string) error {
+    var deletedAt *time.Time
+    if status == models.HorseStatusArchived {
+        deletedAt = &date
+    }
+    return r.db.WithContext(ctx).
+        Model(&models.Horse{}).
+        Where("id = ?", id).
+        Updates(map[string]interface{}{
+            "status":        status,
+            "status_date":   date,
+            "status_reason": reason,
+            "deleted_at":    deletedAt,
+        }).Error
+}
+
+// GetEvents returns the events of all of the horse's pregnancies
+func (r *PostgresPregnancyRepository) GetEvents(ctx context.Context, horseID uint) ([]models.PregnancyEvent, error) {
     var events []models.PregnancyEvent
+    pregnancies := r.db.Model(&models.Pregnancy{}).Select("id").Where("horse_id = ?", horseID)
     err := r.db.WithContext(ctx).
-        Where("pregnancy_id = ?", pregnancyID).
+        Where("pregnancy_id IN (?)", pregnancies).
         Order("created_at DESC").
         Find(&events).Error
     return events, err
@@ -328,10 +486,10 @@ func (r *PostgresHorseRepository) Update(ctx context.Context, horse *models.Hors
     return r.db.WithContext(ctx).Save(horse).Error
 }
 
-func (r *PostgresPregnancyRepository) GetPreFoaling(ctx context.Context, pregnancyID uint) ([]models.PreFoalingSign, error) {
+func (r *PostgresPregnancyRepository) GetPreFoaling(ctx context.Context, horseID uint) ([]models.PreFoalingSign, error) {
     var signs []models.PreFoalingSign
     err := r.db.WithContext(ctx).
-        Where("pregnancy_id = ?", pregnancyID).
+        Where("horse_id = ?", horseID).
         Order("created_at ASC").
         Find(&signs).Error
     return signs, err
@@ -357,10 +515,10 @@ func (r *PostgresPregnancyRepository) GetPreFoalingChecklistItem(ctx context.Con
     return &item, nil
 }
 
-func (r *PostgresPregnancyRepository) GetPreFoalingSigns(ctx context.Context, pregnancyID uint) ([]models.PreFoalingSign, error) {
+func (r *PostgresPregnancyRepository) GetPreFoalingSigns(ctx context.Context, horseID uint) ([]models.PreFoalingSign, error) {
     var signs []models.PreFoalingSign
     err := r.db.WithContext(ctx).
-        Where("pregnancy_id = ?", pregnancyID).
+        Where("horse_id = ?", horseID).
         Order("created_at ASC").
         Find(&signs).Error
     return signs, err
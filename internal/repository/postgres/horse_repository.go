@@ -115,7 +115,7 @@ func (r *HorseRepository) Delete(ctx context.Context, id uint) error {
 
 func (r *HorseRepository) ListByUser(ctx context.Context, userID string) ([]models.Horse, error) {
 	var horses []models.Horse
-	err := r.db.WithContext(ctx).Where("user_id = ?", userID).Find(&horses).Error
+	err := r.db.WithContext(ctx).Where("user_id = ? AND status IN ?", userID, models.ActiveHorseStatuses).Find(&horses).Error
 	return horses, err
 }
 
@@ -51,12 +51,12 @@ func (r *UserRepository) GetDashboardStats(ctx context.Context, userID string) (
 	stats := &models.DashboardStats{}
 	
 	// Get total horses
-	if err := r.db.WithContext(ctx).Model(&models.Horse{}).Where("user_id = ?", userID).Count(&stats.TotalHorses).Error; err != nil {
+	if err := r.db.WithContext(ctx).Model(&models.Horse{}).Where("user_id = ? AND status IN ?", userID, models.ActiveHorseStatuses).Count(&stats.TotalHorses).Error; err != nil {
 		return nil, err
 	}
 
 	// Get pregnant mares
-	if err := r.db.WithContext(ctx).Model(&models.Horse{}).Where("user_id = ? AND is_pregnant = true", userID).Count(&stats.PregnantMares).Error; err != nil {
+	if err := r.db.WithContext(ctx).Model(&models.Horse{}).Where("user_id = ? AND status IN ? AND is_pregnant = true", userID, models.ActiveHorseStatuses).Count(&stats.PregnantMares).Error; err != nil {
 		return nil, err
 	}
 
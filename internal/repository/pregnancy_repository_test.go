@@ -0,0 +1,33 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/polyfant/hulta_pregnancy_app/internal/models"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPregnancyRepository_GetEvents(t *testing.T) {
+	db := setupTestDB(t)
+	require.NoError(t, db.Migrator().DropTable(&models.Pregnancy{}))
+	require.NoError(t, db.AutoMigrate(&models.Pregnancy{}))
+	repo := NewPregnancyRepository(db)
+	ctx := context.Background()
+
+	// Horse IDs and pregnancy IDs overlap, so filtering events by the wrong
+	// one returns another mare's events
+	bella := &models.Pregnancy{ID: 2, HorseID: 1, StartDate: time.Now().AddDate(0, -6, 0), Status: models.PregnancyStatusActive}
+	luna := &models.Pregnancy{ID: 1, HorseID: 2, StartDate: time.Now().AddDate(0, -4, 0), Status: models.PregnancyStatusActive}
+	require.NoError(t, db.Create(bella).Error)
+	require.NoError(t, db.Create(luna).Error)
+	require.NoError(t, repo.AddPregnancyEvent(ctx, &models.PregnancyEvent{PregnancyID: bella.ID, UserID: "owner", Type: "SCAN", Date: time.Now()}))
+	require.NoError(t, repo.AddPregnancyEvent(ctx, &models.PregnancyEvent{PregnancyID: luna.ID, UserID: "owner", Type: "VACCINATION", Date: time.Now()}))
+
+	events, err := repo.GetEvents(ctx, 1)
+
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	require.Equal(t, bella.ID, events[0].PregnancyID)
+}
@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/polyfant/hulta_pregnancy_app/internal/models"
+	"gorm.io/gorm"
+)
+
+type PostgresTreatmentRepository struct {
+	db *gorm.DB
+}
+
+func NewTreatmentRepository(db *gorm.DB) TreatmentRepository {
+	return &PostgresTreatmentRepository{db: db}
+}
+
+func (r *PostgresTreatmentRepository) CreatePlan(ctx context.Context, plan *models.TreatmentPlan) error {
+	return r.db.WithContext(ctx).Create(plan).Error
+}
+
+func (r *PostgresTreatmentRepository) GetPlan(ctx context.Context, id uint) (*models.TreatmentPlan, error) {
+	var plan models.TreatmentPlan
+	if err := r.db.WithContext(ctx).First(&plan, id).Error; err != nil {
+		return nil, err
+	}
+	return &plan, nil
+}
+
+func (r *PostgresTreatmentRepository) UpdatePlan(ctx context.Context, plan *models.TreatmentPlan) error {
+	return r.db.WithContext(ctx).Save(plan).Error
+}
+
+// ListPlansByHorse returns the most recently started plans first
+func (r *PostgresTreatmentRepository) ListPlansByHorse(ctx context.Context, horseID uint) ([]models.TreatmentPlan, error) {
+	var plans []models.TreatmentPlan
+	if err := r.db.WithContext(ctx).
+		Where("horse_id = ?", horseID).
+		Order("start_date DESC").
+		Find(&plans).Error; err != nil {
+		return nil, err
+	}
+	return plans, nil
+}
+
+func (r *PostgresTreatmentRepository) CreateDose(ctx context.Context, dose *models.DoseAdministration) error {
+	return r.db.WithContext(ctx).Create(dose).Error
+}
+
+// ListDosesByHorse returns the dose log of every plan for the horse, oldest first
+func (r *PostgresTreatmentRepository) ListDosesByHorse(ctx context.Context, horseID uint) ([]models.DoseAdministration, error) {
+	var doses []models.DoseAdministration
+	if err := r.db.WithContext(ctx).
+		Where("horse_id = ?", horseID).
+		Order("administered_at ASC").
+		Find(&doses).Error; err != nil {
+		return nil, err
+	}
+	return doses, nil
+}
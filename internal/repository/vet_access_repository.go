@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/polyfant/hulta_pregnancy_app/internal/models"
+	"gorm.io/gorm"
+)
+
+type PostgresVetAccessRepository struct {
+	db *gorm.DB
+}
+
+func NewVetAccessRepository(db *gorm.DB) VetAccessRepository {
+	return &PostgresVetAccessRepository{db: db}
+}
+
+func (r *PostgresVetAccessRepository) Create(ctx context.Context, grant *models.VetAccessGrant) error {
+	return r.db.WithContext(ctx).Create(grant).Error
+}
+
+func (r *PostgresVetAccessRepository) GetByID(ctx context.Context, id uint) (*models.VetAccessGrant, error) {
+	var grant models.VetAccessGrant
+	if err := r.db.WithContext(ctx).Preload("Horses").First(&grant, id).Error; err != nil {
+		return nil, err
+	}
+	return &grant, nil
+}
+
+func (r *PostgresVetAccessRepository) GetByTokenHash(ctx context.Context, tokenHash string) (*models.VetAccessGrant, error) {
+	var grant models.VetAccessGrant
+	err := r.db.WithContext(ctx).
+		Preload("Horses").
+		Where("token_hash = ?", tokenHash).
+		First(&grant).Error
+	if err != nil {
+		return nil, err
+	}
+	return &grant, nil
+}
+
+func (r *PostgresVetAccessRepository) ListByOwner(ctx context.Context, ownerID string) ([]models.VetAccessGrant, error) {
+	var grants []models.VetAccessGrant
+	err := r.db.WithContext(ctx).
+		Preload("Horses").
+		Where("owner_id = ?", ownerID).
+		Order("created_at DESC").
+		Find(&grants).Error
+	return grants, err
+}
+
+func (r *PostgresVetAccessRepository) ListByVet(ctx context.Context, vetUserID string) ([]models.VetAccessGrant, error) {
+	var grants []models.VetAccessGrant
+	err := r.db.WithContext(ctx).
+		Preload("Horses").
+		Where("vet_user_id = ?", vetUserID).
+		Order("created_at DESC").
+		Find(&grants).Error
+	return grants, err
+}
+
+// ListActiveForVetAndHorse returns unrevoked, unexpired grants giving the vet access to the horse
+func (r *PostgresVetAccessRepository) ListActiveForVetAndHorse(ctx context.Context, vetUserID string, horseID uint, now time.Time) ([]models.VetAccessGrant, error) {
+	var grants []models.VetAccessGrant
+	err := r.db.WithContext(ctx).
+		Preload("Horses").
+		Joins("JOIN vet_access_grant_horses ON vet_access_grant_horses.grant_id = vet_access_grants.id").
+		Where("vet_access_grants.vet_user_id = ? AND vet_access_grant_horses.horse_id = ?", vetUserID, horseID).
+		Where("vet_access_grants.revoked_at IS NULL AND vet_access_grants.expires_at > ?", now).
+		Find(&grants).Error
+	return grants, err
+}
+
+func (r *PostgresVetAccessRepository) Revoke(ctx context.Context, id uint, revokedAt time.Time) error {
+	return r.db.WithContext(ctx).
+		Model(&models.VetAccessGrant{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", revokedAt).Error
+}
@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/polyfant/hulta_pregnancy_app/internal/models"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVetAccessRepository_Create(t *testing.T) {
+	db := setupTestDB(t)
+	for _, table := range []interface{}{&models.VetAccessGrantHorse{}, &models.VetAccessGrant{}} {
+		require.NoError(t, db.Migrator().DropTable(table))
+	}
+	require.NoError(t, db.AutoMigrate(&models.VetAccessGrant{}, &models.VetAccessGrantHorse{}))
+	repo := NewVetAccessRepository(db)
+	ctx := context.Background()
+
+	accountGrant := func(vetUserID string) *models.VetAccessGrant {
+		return &models.VetAccessGrant{
+			OwnerID:       "owner",
+			VetUserID:     &vetUserID,
+			AccessLevel:   models.AccessLevelRead,
+			IncludeHealth: true,
+			ExpiresAt:     time.Now().Add(24 * time.Hour),
+		}
+	}
+
+	t.Run("several account grants", func(t *testing.T) {
+		require.NoError(t, repo.Create(ctx, accountGrant("vet1")))
+		require.NoError(t, repo.Create(ctx, accountGrant("vet2")))
+
+		var withoutToken int64
+		require.NoError(t, db.Model(&models.VetAccessGrant{}).Where("token_hash IS NULL").Count(&withoutToken).Error)
+		require.Equal(t, int64(2), withoutToken)
+	})
+
+	t.Run("signed link has no vet account", func(t *testing.T) {
+		tokenHash := "abc123"
+		require.NoError(t, repo.Create(ctx, &models.VetAccessGrant{
+			OwnerID:          "owner",
+			TokenHash:        &tokenHash,
+			AccessLevel:      models.AccessLevelRead,
+			IncludePregnancy: true,
+			ExpiresAt:        time.Now().Add(24 * time.Hour),
+		}))
+
+		grant, err := repo.GetByTokenHash(ctx, tokenHash)
+		require.NoError(t, err)
+		require.Nil(t, grant.VetUserID)
+	})
+}
@@ -0,0 +1,34 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/polyfant/hulta_pregnancy_app/internal/models"
+	"gorm.io/gorm"
+)
+
+type PostgresVitalSignsRepository struct {
+	db *gorm.DB
+}
+
+func NewVitalSignsRepository(db *gorm.DB) VitalSignsRepository {
+	return &PostgresVitalSignsRepository{db: db}
+}
+
+func (r *PostgresVitalSignsRepository) Create(ctx context.Context, record *models.VitalSignsRecord) error {
+	return r.db.WithContext(ctx).Create(record).Error
+}
+
+// ListByHorse returns records newest first, optionally limited to those recorded since the given time
+func (r *PostgresVitalSignsRepository) ListByHorse(ctx context.Context, horseID uint, since *time.Time) ([]models.VitalSignsRecord, error) {
+	var records []models.VitalSignsRecord
+	query := r.db.WithContext(ctx).Where("horse_id = ?", horseID)
+	if since != nil {
+		query = query.Where("recorded_at >= ?", *since)
+	}
+	if err := query.Order("recorded_at DESC").Find(&records).Error; err != nil {
+		return nil, err
+	}
+	return records, nil
+}
@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/polyfant/hulta_pregnancy_app/internal/models"
+	"gorm.io/gorm"
+)
+
+type PostgresWeaningRepository struct {
+	db *gorm.DB
+}
+
+func NewWeaningRepository(db *gorm.DB) WeaningRepository {
+	return &PostgresWeaningRepository{db: db}
+}
+
+// GetByHorse returns the foal's weaning record, or nil if it has not been weaned
+func (r *PostgresWeaningRepository) GetByHorse(ctx context.Context, horseID uint) (*models.WeaningRecord, error) {
+	var record models.WeaningRecord
+	err := r.db.WithContext(ctx).Where("horse_id = ?", horseID).First(&record).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+// Save creates the record or, when it has an ID, updates it
+func (r *PostgresWeaningRepository) Save(ctx context.Context, record *models.WeaningRecord) error {
+	return r.db.WithContext(ctx).Save(record).Error
+}
+
+func (r *PostgresWeaningRepository) CreateCheck(ctx context.Context, check *models.WeaningCheck) error {
+	return r.db.WithContext(ctx).Create(check).Error
+}
+
+// ListChecks returns the foal's post-weaning checks oldest first
+func (r *PostgresWeaningRepository) ListChecks(ctx context.Context, horseID uint) ([]models.WeaningCheck, error) {
+	var checks []models.WeaningCheck
+	if err := r.db.WithContext(ctx).Where("horse_id = ?", horseID).Order("checked_at ASC").Find(&checks).Error; err != nil {
+		return nil, err
+	}
+	return checks, nil
+}
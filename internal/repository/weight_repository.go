@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/polyfant/hulta_pregnancy_app/internal/models"
+	"gorm.io/gorm"
+)
+
+type PostgresWeightRepository struct {
+	db *gorm.DB
+}
+
+func NewWeightRepository(db *gorm.DB) WeightRepository {
+	return &PostgresWeightRepository{db: db}
+}
+
+func (r *PostgresWeightRepository) Create(ctx context.Context, record *models.WeightRecord) error {
+	return r.db.WithContext(ctx).Create(record).Error
+}
+
+// ListByHorse returns weighings oldest first
+func (r *PostgresWeightRepository) ListByHorse(ctx context.Context, horseID uint, since *time.Time) ([]models.WeightRecord, error) {
+	var records []models.WeightRecord
+	query := r.db.WithContext(ctx).Where("horse_id = ?", horseID)
+	if since != nil {
+		query = query.Where("date >= ?", *since)
+	}
+	if err := query.Order("date ASC").Find(&records).Error; err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+// GetLatest returns the most recent weighing, or nil if the horse has never been weighed
+func (r *PostgresWeightRepository) GetLatest(ctx context.Context, horseID uint) (*models.WeightRecord, error) {
+	var record models.WeightRecord
+	err := r.db.WithContext(ctx).
+		Where("horse_id = ?", horseID).
+		Order("date DESC, id DESC").
+		First(&record).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
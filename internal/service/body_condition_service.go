@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/polyfant/hulta_pregnancy_app/internal/models"
+	"github.com/polyfant/hulta_pregnancy_app/internal/repository"
+	"github.com/polyfant/hulta_pregnancy_app/internal/service/health"
+	"gorm.io/gorm"
+)
+
+// bodyConditionHistoryMonths is how far back the condition chart reaches
+// outside of a pregnancy
+const bodyConditionHistoryMonths = 12
+
+// BodyConditionServiceImpl records Henneke scores and charts them, over the
+// last year or over the current pregnancy
+type BodyConditionServiceImpl struct {
+	repo          repository.BodyConditionRepository
+	pregnancyRepo repository.PregnancyRepository
+}
+
+func NewBodyConditionService(repo repository.BodyConditionRepository, pregnancyRepo repository.PregnancyRepository) BodyConditionService {
+	return &BodyConditionServiceImpl{
+		repo:          repo,
+		pregnancyRepo: pregnancyRepo,
+	}
+}
+
+// Record scores the horse from its regional scores, or takes the overall
+// score as given when no regions were scored
+func (s *BodyConditionServiceImpl) Record(ctx context.Context, horse *models.Horse, record *models.BodyCondition) error {
+	now := timeNow()
+	if record.Date.IsZero() {
+		record.Date = now
+	}
+	if record.Date.After(now) {
+		return fmt.Errorf("%w: date cannot be in the future", models.ErrInvalidBodyCondition)
+	}
+
+	score, err := health.HennekeScore(*record)
+	if err != nil {
+		return fmt.Errorf("%w: %v", models.ErrInvalidBodyCondition, err)
+	}
+
+	record.HorseID = horse.ID
+	record.Score = score
+	if err := s.repo.Create(ctx, record); err != nil {
+		return fmt.Errorf("failed to save body condition score: %w", err)
+	}
+	return nil
+}
+
+func (s *BodyConditionServiceImpl) History(ctx context.Context, horse *models.Horse) (*models.BodyConditionHistory, error) {
+	now := timeNow()
+	since := now.AddDate(0, -bodyConditionHistoryMonths, 0)
+	records, err := s.repo.ListByHorse(ctx, horse.ID, &since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get body condition scores: %w", err)
+	}
+
+	history := health.SummarizeBodyCondition(*horse, records, nil, now)
+	return &history, nil
+}
+
+// PregnancyTrend charts the scores since conception against the day of gestation
+func (s *BodyConditionServiceImpl) PregnancyTrend(ctx context.Context, horse *models.Horse) (*models.BodyConditionHistory, error) {
+	pregnancy, err := s.pregnancyRepo.GetCurrentPregnancy(ctx, horse.ID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, models.ErrNoActivePregnancy
+		}
+		return nil, fmt.Errorf("failed to get current pregnancy: %w", err)
+	}
+	if pregnancy == nil || !pregnancy.IsActive() {
+		return nil, models.ErrNoActivePregnancy
+	}
+
+	conception := pregnancy.StartDate
+	if pregnancy.ConceptionDate != nil {
+		conception = *pregnancy.ConceptionDate
+	}
+
+	records, err := s.repo.ListByHorse(ctx, horse.ID, &conception)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get body condition scores: %w", err)
+	}
+
+	// A mare being charted over a pregnancy is pregnant whatever the horse
+	// record says, so hold her to the pregnancy target
+	mare := *horse
+	mare.IsPregnant = true
+	history := health.SummarizeBodyCondition(mare, records, &conception, timeNow())
+	return &history, nil
+}
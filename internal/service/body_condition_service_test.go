@@ -0,0 +1,105 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/polyfant/hulta_pregnancy_app/internal/mocks"
+	"github.com/polyfant/hulta_pregnancy_app/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestBodyConditionService_Record(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	horse := &models.Horse{ID: 5}
+
+	tests := []struct {
+		name      string
+		record    models.BodyCondition
+		wantScore float64
+		wantDate  time.Time
+		wantErr   error
+	}{
+		{
+			name:      "scored by region",
+			record:    models.BodyCondition{Date: now.AddDate(0, 0, -1), Neck: 6, Withers: 6, Loin: 6, Tailhead: 7, Ribs: 6, Shoulder: 6},
+			wantScore: 6,
+			wantDate:  now.AddDate(0, 0, -1),
+		},
+		{name: "date defaults to today", record: models.BodyCondition{Score: 5}, wantScore: 5, wantDate: now},
+		{name: "future date", record: models.BodyCondition{Date: now.Add(time.Hour), Score: 5}, wantErr: models.ErrInvalidBodyCondition},
+		{name: "partial regions", record: models.BodyCondition{Neck: 5, Loin: 5}, wantErr: models.ErrInvalidBodyCondition},
+		{name: "score out of range", record: models.BodyCondition{Score: 11}, wantErr: models.ErrInvalidBodyCondition},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setTestNow(t, now)
+			repo := new(mocks.MockBodyConditionRepository)
+			svc := NewBodyConditionService(repo, new(mocks.MockPregnancyRepository))
+			repo.On("Create", ctx, mock.AnythingOfType("*models.BodyCondition")).Return(nil)
+
+			record := tt.record
+			err := svc.Record(ctx, horse, &record)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				repo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, horse.ID, record.HorseID)
+			assert.Equal(t, tt.wantScore, record.Score)
+			assert.Equal(t, tt.wantDate, record.Date)
+		})
+	}
+}
+
+func TestBodyConditionService_PregnancyTrend(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	conception := now.AddDate(0, 0, -120)
+	horse := &models.Horse{ID: 5, Gender: models.GenderMare}
+
+	setTestNow(t, now)
+	repo := new(mocks.MockBodyConditionRepository)
+	pregnancyRepo := new(mocks.MockPregnancyRepository)
+	svc := NewBodyConditionService(repo, pregnancyRepo)
+	pregnancyRepo.On("GetCurrentPregnancy", ctx, horse.ID).Return(&models.Pregnancy{
+		HorseID:        horse.ID,
+		Status:         models.PregnancyStatusActive,
+		StartDate:      conception.AddDate(0, 0, 14),
+		ConceptionDate: &conception,
+	}, nil)
+	repo.On("ListByHorse", ctx, horse.ID, &conception).Return([]models.BodyCondition{
+		{Date: conception.AddDate(0, 0, 30), Score: 6},
+		{Date: conception.AddDate(0, 0, 90), Score: 5},
+	}, nil)
+
+	history, err := svc.PregnancyTrend(ctx, horse)
+
+	require.NoError(t, err)
+	require.Len(t, history.Points, 2)
+	assert.Equal(t, 30, *history.Points[0].GestationDay)
+	assert.Equal(t, 5.0, history.TargetMin, "held to the pregnancy target")
+	assert.Equal(t, models.BodyConditionLosing, history.Trend)
+	assert.Equal(t, &conception, history.ConceptionDate)
+}
+
+func TestBodyConditionService_PregnancyTrendNotPregnant(t *testing.T) {
+	ctx := context.Background()
+	repo := new(mocks.MockBodyConditionRepository)
+	pregnancyRepo := new(mocks.MockPregnancyRepository)
+	svc := NewBodyConditionService(repo, pregnancyRepo)
+	pregnancyRepo.On("GetCurrentPregnancy", ctx, uint(5)).Return(nil, gorm.ErrRecordNotFound)
+
+	_, err := svc.PregnancyTrend(ctx, &models.Horse{ID: 5})
+
+	assert.ErrorIs(t, err, models.ErrNoActivePregnancy)
+	repo.AssertNotCalled(t, "ListByHorse", mock.Anything, mock.Anything, mock.Anything)
+}
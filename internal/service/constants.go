@@ -6,6 +6,8 @@ const (
     DefaultGestationDays = 340
 )
 
+var timeNow = time.Now // Allow overriding in tests
+
 func CalculateDueDate(conceptionDate time.Time) time.Time {
     return conceptionDate.AddDate(0, 0, DefaultGestationDays)
 } 
\ No newline at end of file
@@ -1,42 +1,51 @@
 package export
 
 import (
+	"bytes"
+	"context"
 	"encoding/csv"
 	"fmt"
-	"os"
 	"strconv"
 	"time"
 
 	"github.com/polyfant/hulta_pregnancy_app/internal/logger"
 	"github.com/polyfant/hulta_pregnancy_app/internal/models"
+	"github.com/polyfant/hulta_pregnancy_app/internal/service/storage"
 )
 
+// exportPrefix is the blob key prefix exports are stored under
+const exportPrefix = "exports"
+
 type ExportService struct {
-	exportPath string
+	blobs storage.BlobStore
 }
 
-func NewExportService(exportPath string) (*ExportService, error) {
-	if err := os.MkdirAll(exportPath, 0755); err != nil {
-		return nil, fmt.Errorf("failed to create export directory: %w", err)
-	}
-	return &ExportService{exportPath: exportPath}, nil
+func NewExportService(blobs storage.BlobStore) *ExportService {
+	return &ExportService{blobs: blobs}
 }
 
-func (es *ExportService) ExportHorsesToCSV(horses []models.Horse) (string, error) {
-	filename := fmt.Sprintf("horses_export_%s.csv", time.Now().Format("2006-01-02_15-04-05"))
-	filepath := fmt.Sprintf("%s/%s", es.exportPath, filename)
+// DownloadURL returns a signed link for an export key returned by one of the Export methods
+func (es *ExportService) DownloadURL(ctx context.Context, key string, expiry time.Duration) (string, error) {
+	return es.blobs.SignedURL(ctx, key, expiry)
+}
 
-	file, err := os.Create(filepath)
+// save stores a finished export and returns its blob key
+func (es *ExportService) save(ctx context.Context, filename string, buf *bytes.Buffer) (string, error) {
+	info, err := es.blobs.Put(ctx, exportPrefix+"/"+filename, buf, "")
 	if err != nil {
-		return "", fmt.Errorf("failed to create export file: %w", err)
+		return "", fmt.Errorf("failed to store export file: %w", err)
 	}
-	defer file.Close()
+	return info.Key, nil
+}
 
-	writer := csv.NewWriter(file)
-	defer writer.Flush()
+func (es *ExportService) ExportHorsesToCSV(ctx context.Context, horses []models.Horse) (string, error) {
+	filename := fmt.Sprintf("horses_export_%s.csv", time.Now().Format("2006-01-02_15-04-05"))
+
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
 
 	// Write header
-	header := []string{"ID", "Name", "Breed", "Date of Birth", "Conception Date", "Mother ID", "Father ID"}
+	header := []string{"ID", "Name", "Breed", "Gender", "Date of Birth", "Conception Date", "Mother ID", "Father ID", "Status", "Status Date"}
 	if err := writer.Write(header); err != nil {
 		return "", fmt.Errorf("failed to write header: %w", err)
 	}
@@ -51,8 +60,12 @@ func (es *ExportService) ExportHorsesToCSV(horses []models.Horse) (string, error
 		if horse.FatherId != nil {
 			fatherID = strconv.FormatInt(int64(*horse.FatherId), 10)
 		}
+		statusDate := ""
+		if horse.StatusDate != nil {
+			statusDate = horse.StatusDate.Format("2006-01-02")
+		}
 		conceptionDate := ""
-		if horse.ConceptionDate != nil {
+		if horse.IsPregnant && horse.ConceptionDate != nil {
 			conceptionDate = horse.ConceptionDate.Format("2006-01-02")
 		}
 
@@ -60,10 +73,13 @@ func (es *ExportService) ExportHorsesToCSV(horses []models.Horse) (string, error
 			strconv.FormatInt(int64(horse.ID), 10),
 			horse.Name,
 			horse.Breed,
+			string(horse.Gender),
 			horse.BirthDate.Format("2006-01-02"),
 			conceptionDate,
 			motherID,
 			fatherID,
+			string(horse.Status),
+			statusDate,
 		}
 
 		if err := writer.Write(record); err != nil {
@@ -71,26 +87,29 @@ func (es *ExportService) ExportHorsesToCSV(horses []models.Horse) (string, error
 		}
 	}
 
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return "", fmt.Errorf("failed to write export: %w", err)
+	}
+
+	key, err := es.save(ctx, filename, &buf)
+	if err != nil {
+		return "", err
+	}
+
 	logger.Info("Exported horses to CSV", map[string]interface{}{
-		"filename": filename,
-		"count":    len(horses),
+		"key":   key,
+		"count": len(horses),
 	})
 
-	return filename, nil
+	return key, nil
 }
 
-func (es *ExportService) ExportHealthRecordsToCSV(records []models.HealthRecord) (string, error) {
+func (es *ExportService) ExportHealthRecordsToCSV(ctx context.Context, records []models.HealthRecord) (string, error) {
 	filename := fmt.Sprintf("health_records_export_%s.csv", time.Now().Format("2006-01-02_15-04-05"))
-	filepath := fmt.Sprintf("%s/%s", es.exportPath, filename)
-
-	file, err := os.Create(filepath)
-	if err != nil {
-		return "", fmt.Errorf("failed to create export file: %w", err)
-	}
-	defer file.Close()
 
-	writer := csv.NewWriter(file)
-	defer writer.Flush()
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
 
 	// Write header
 	header := []string{"ID", "Horse ID", "Date", "Type", "Notes"}
@@ -113,10 +132,20 @@ func (es *ExportService) ExportHealthRecordsToCSV(records []models.HealthRecord)
 		}
 	}
 
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return "", fmt.Errorf("failed to write export: %w", err)
+	}
+
+	key, err := es.save(ctx, filename, &buf)
+	if err != nil {
+		return "", err
+	}
+
 	logger.Info("Exported health records to CSV", map[string]interface{}{
-		"filename": filename,
-		"count":    len(records),
+		"key":   key,
+		"count": len(records),
 	})
 
-	return filename, nil
+	return key, nil
 }
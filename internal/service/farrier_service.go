@@ -0,0 +1,154 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/polyfant/hulta_pregnancy_app/internal/models"
+	"github.com/polyfant/hulta_pregnancy_app/internal/repository"
+	"github.com/polyfant/hulta_pregnancy_app/internal/service/health"
+)
+
+const (
+	minFarrierIntervalWeeks = 2
+	maxFarrierIntervalWeeks = 26
+)
+
+// FarrierServiceImpl records farrier visits and keeps each horse's hoof care schedule
+type FarrierServiceImpl struct {
+	repo repository.FarrierRepository
+}
+
+func NewFarrierService(repo repository.FarrierRepository) FarrierService {
+	return &FarrierServiceImpl{
+		repo: repo,
+	}
+}
+
+// RecordVisit saves a visit and books its cost as a farrier expense for the
+// horse's owner. Missing farrier contact details are taken from the schedule.
+func (s *FarrierServiceImpl) RecordVisit(ctx context.Context, horse *models.Horse, visit *models.FarrierVisit) error {
+	if err := validateFarrierVisit(visit, timeNow()); err != nil {
+		return err
+	}
+
+	schedule, err := s.repo.GetSchedule(ctx, horse.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get farrier schedule: %w", err)
+	}
+	if schedule != nil && visit.FarrierName == "" {
+		visit.FarrierName = schedule.FarrierName
+		visit.FarrierPhone = schedule.FarrierPhone
+		visit.FarrierEmail = schedule.FarrierEmail
+	}
+
+	visit.HorseID = horse.ID
+	visit.ExpenseID = nil
+
+	var expense *models.Expense
+	if visit.Cost > 0 {
+		expense = &models.Expense{
+			UserID:      horse.UserID,
+			HorseID:     horse.ID,
+			ExpenseType: models.ExpenseTypeFarrier,
+			Amount:      visit.Cost,
+			Date:        visit.VisitDate,
+			Description: describeFarrierVisit(visit),
+		}
+		if expense.UserID == "" {
+			expense.UserID = visit.UserID
+		}
+	}
+
+	if err := s.repo.CreateVisit(ctx, visit, expense); err != nil {
+		return fmt.Errorf("failed to save farrier visit: %w", err)
+	}
+	return nil
+}
+
+func (s *FarrierServiceImpl) ListVisits(ctx context.Context, horseID uint) ([]models.FarrierVisit, error) {
+	visits, err := s.repo.ListVisitsByHorse(ctx, horseID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get farrier visits: %w", err)
+	}
+	return visits, nil
+}
+
+// GetSchedule returns the horse's schedule, which may be nil, and the next visit due
+func (s *FarrierServiceImpl) GetSchedule(ctx context.Context, horse *models.Horse) (*models.FarrierScheduleStatus, error) {
+	schedule, err := s.repo.GetSchedule(ctx, horse.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get farrier schedule: %w", err)
+	}
+	visits, err := s.ListVisits(ctx, horse.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &models.FarrierScheduleStatus{Schedule: schedule}
+	if item, ok := health.FarrierDue(*horse, schedule, visits, timeNow()); ok {
+		status.NextVisit = &item
+	}
+	return status, nil
+}
+
+// SetSchedule creates or replaces the horse's schedule
+func (s *FarrierServiceImpl) SetSchedule(ctx context.Context, horse *models.Horse, schedule *models.FarrierSchedule) error {
+	if schedule.IntervalWeeks < minFarrierIntervalWeeks || schedule.IntervalWeeks > maxFarrierIntervalWeeks {
+		return fmt.Errorf("%w: interval_weeks must be between %d and %d", models.ErrInvalidFarrierVisit, minFarrierIntervalWeeks, maxFarrierIntervalWeeks)
+	}
+	if schedule.Work != "" && !schedule.Work.IsValid() {
+		return fmt.Errorf("%w: unknown work %q", models.ErrInvalidFarrierVisit, schedule.Work)
+	}
+
+	schedule.HorseID = horse.ID
+	schedule.UpdatedAt = timeNow()
+	if err := s.repo.SaveSchedule(ctx, schedule); err != nil {
+		return fmt.Errorf("failed to save farrier schedule: %w", err)
+	}
+	return nil
+}
+
+func (s *FarrierServiceImpl) DeleteSchedule(ctx context.Context, horseID uint) error {
+	if err := s.repo.DeleteSchedule(ctx, horseID); err != nil {
+		return fmt.Errorf("failed to delete farrier schedule: %w", err)
+	}
+	return nil
+}
+
+func validateFarrierVisit(visit *models.FarrierVisit, now time.Time) error {
+	switch {
+	case visit.VisitDate.IsZero():
+		return fmt.Errorf("%w: visit_date is required", models.ErrInvalidFarrierVisit)
+	case visit.VisitDate.After(now):
+		return fmt.Errorf("%w: visit_date cannot be in the future", models.ErrInvalidFarrierVisit)
+	case !visit.Work.IsValid():
+		return fmt.Errorf("%w: unknown work %q", models.ErrInvalidFarrierVisit, visit.Work)
+	case visit.Cost < 0:
+		return fmt.Errorf("%w: cost cannot be negative", models.ErrInvalidFarrierVisit)
+	case visit.NextVisitDate != nil && !visit.NextVisitDate.After(visit.VisitDate):
+		return fmt.Errorf("%w: next_visit_date must be after visit_date", models.ErrInvalidFarrierVisit)
+	}
+
+	seen := map[models.Hoof]bool{}
+	for _, note := range visit.Hooves {
+		if !note.Hoof.IsValid() {
+			return fmt.Errorf("%w: unknown hoof %q", models.ErrInvalidFarrierVisit, note.Hoof)
+		}
+		if seen[note.Hoof] {
+			return fmt.Errorf("%w: hoof %s listed twice", models.ErrInvalidFarrierVisit, note.Hoof)
+		}
+		seen[note.Hoof] = true
+	}
+	return nil
+}
+
+func describeFarrierVisit(visit *models.FarrierVisit) string {
+	description := "Farrier: " + strings.ToLower(string(visit.Work))
+	if visit.FarrierName != "" {
+		description += " by " + visit.FarrierName
+	}
+	return description
+}
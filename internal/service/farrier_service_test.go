@@ -0,0 +1,150 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/polyfant/hulta_pregnancy_app/internal/mocks"
+	"github.com/polyfant/hulta_pregnancy_app/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFarrierService_RecordVisit(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	horse := &models.Horse{ID: 3, UserID: "owner"}
+	before := now.AddDate(0, 0, -1)
+
+	tests := []struct {
+		name        string
+		visit       models.FarrierVisit
+		schedule    *models.FarrierSchedule
+		wantErr     error
+		wantExpense bool
+		wantFarrier string
+	}{
+		{
+			name:        "visit with cost books an expense",
+			visit:       models.FarrierVisit{VisitDate: now, Work: models.FarrierWorkShoes, Cost: 120, FarrierName: "Sam Smith"},
+			wantExpense: true,
+			wantFarrier: "Sam Smith",
+		},
+		{
+			name:        "free visit takes contact from schedule",
+			visit:       models.FarrierVisit{VisitDate: now, Work: models.FarrierWorkTrim},
+			schedule:    &models.FarrierSchedule{IntervalWeeks: 6, FarrierName: "Alex Jones", FarrierPhone: "0700"},
+			wantFarrier: "Alex Jones",
+		},
+		{name: "missing date", visit: models.FarrierVisit{Work: models.FarrierWorkTrim}, wantErr: models.ErrInvalidFarrierVisit},
+		{name: "future visit", visit: models.FarrierVisit{VisitDate: now.Add(time.Hour), Work: models.FarrierWorkTrim}, wantErr: models.ErrInvalidFarrierVisit},
+		{name: "unknown work", visit: models.FarrierVisit{VisitDate: now, Work: "PAINT"}, wantErr: models.ErrInvalidFarrierVisit},
+		{name: "negative cost", visit: models.FarrierVisit{VisitDate: now, Work: models.FarrierWorkTrim, Cost: -1}, wantErr: models.ErrInvalidFarrierVisit},
+		{name: "next visit before this one", visit: models.FarrierVisit{VisitDate: now, Work: models.FarrierWorkTrim, NextVisitDate: &before}, wantErr: models.ErrInvalidFarrierVisit},
+		{
+			name:    "hoof listed twice",
+			visit:   models.FarrierVisit{VisitDate: now, Work: models.FarrierWorkTrim, Hooves: []models.HoofNote{{Hoof: models.HoofLeftFront}, {Hoof: models.HoofLeftFront}}},
+			wantErr: models.ErrInvalidFarrierVisit,
+		},
+		{
+			name:    "unknown hoof",
+			visit:   models.FarrierVisit{VisitDate: now, Work: models.FarrierWorkTrim, Hooves: []models.HoofNote{{Hoof: "XX"}}},
+			wantErr: models.ErrInvalidFarrierVisit,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(mocks.MockFarrierRepository)
+			svc := NewFarrierService(repo).(*FarrierServiceImpl)
+			setTestNow(t, now)
+			repo.On("GetSchedule", ctx, horse.ID).Return(tt.schedule, nil)
+
+			var expense *models.Expense
+			repo.On("CreateVisit", ctx, mock.AnythingOfType("*models.FarrierVisit"), mock.Anything).
+				Run(func(args mock.Arguments) { expense, _ = args.Get(2).(*models.Expense) }).
+				Return(nil)
+
+			visit := tt.visit
+			err := svc.RecordVisit(ctx, horse, &visit)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				repo.AssertNotCalled(t, "CreateVisit", mock.Anything, mock.Anything, mock.Anything)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, horse.ID, visit.HorseID)
+			assert.Equal(t, tt.wantFarrier, visit.FarrierName)
+			if !tt.wantExpense {
+				assert.Nil(t, expense)
+				return
+			}
+			require.NotNil(t, expense)
+			assert.Equal(t, models.ExpenseTypeFarrier, expense.ExpenseType)
+			assert.Equal(t, "owner", expense.UserID)
+			assert.Equal(t, horse.ID, expense.HorseID)
+			assert.Equal(t, 120.0, expense.Amount)
+			assert.Equal(t, "Farrier: shoes by Sam Smith", expense.Description)
+		})
+	}
+}
+
+func TestFarrierService_SetSchedule(t *testing.T) {
+	ctx := context.Background()
+	horse := &models.Horse{ID: 3}
+
+	tests := []struct {
+		name     string
+		schedule models.FarrierSchedule
+		wantErr  bool
+	}{
+		{name: "valid", schedule: models.FarrierSchedule{IntervalWeeks: 6, Work: models.FarrierWorkShoes}},
+		{name: "too short", schedule: models.FarrierSchedule{IntervalWeeks: 1}, wantErr: true},
+		{name: "too long", schedule: models.FarrierSchedule{IntervalWeeks: 30}, wantErr: true},
+		{name: "unknown work", schedule: models.FarrierSchedule{IntervalWeeks: 6, Work: "GLUE"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(mocks.MockFarrierRepository)
+			repo.On("SaveSchedule", ctx, mock.AnythingOfType("*models.FarrierSchedule")).Return(nil)
+			svc := NewFarrierService(repo)
+
+			schedule := tt.schedule
+			err := svc.SetSchedule(ctx, horse, &schedule)
+
+			if tt.wantErr {
+				assert.ErrorIs(t, err, models.ErrInvalidFarrierVisit)
+				repo.AssertNotCalled(t, "SaveSchedule", mock.Anything, mock.Anything)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, horse.ID, schedule.HorseID)
+		})
+	}
+}
+
+func TestFarrierService_GetSchedule(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	horse := &models.Horse{ID: 3, BirthDate: now.AddDate(-6, 0, 0)}
+	last := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	repo := new(mocks.MockFarrierRepository)
+	svc := NewFarrierService(repo).(*FarrierServiceImpl)
+	setTestNow(t, now)
+	schedule := &models.FarrierSchedule{HorseID: 3, IntervalWeeks: 5}
+	repo.On("GetSchedule", ctx, horse.ID).Return(schedule, nil)
+	repo.On("ListVisitsByHorse", ctx, horse.ID).Return([]models.FarrierVisit{{VisitDate: last, Work: models.FarrierWorkTrim}}, nil)
+
+	status, err := svc.GetSchedule(ctx, horse)
+
+	require.NoError(t, err)
+	assert.Equal(t, schedule, status.Schedule)
+	require.NotNil(t, status.NextVisit)
+	assert.Equal(t, last.AddDate(0, 0, 35), status.NextVisit.DueDate)
+	assert.Equal(t, models.HealthDueSoon, status.NextVisit.Status)
+}
@@ -0,0 +1,81 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/polyfant/hulta_pregnancy_app/internal/models"
+	"github.com/polyfant/hulta_pregnancy_app/internal/repository"
+	"github.com/polyfant/hulta_pregnancy_app/internal/service/health"
+)
+
+// maxEggsPerGram is far above any real count and catches typing mistakes
+const maxEggsPerGram = 50000
+
+// FecalEggCountServiceImpl stores fecal egg counts and turns them into deworming advice
+type FecalEggCountServiceImpl struct {
+	repo       repository.FecalEggCountRepository
+	healthRepo repository.HealthRepository
+}
+
+func NewFecalEggCountService(repo repository.FecalEggCountRepository, healthRepo repository.HealthRepository) FecalEggCountService {
+	return &FecalEggCountServiceImpl{
+		repo:       repo,
+		healthRepo: healthRepo,
+	}
+}
+
+func (s *FecalEggCountServiceImpl) Record(ctx context.Context, horse *models.Horse, count *models.FecalEggCount) error {
+	if count.ParasiteType == "" {
+		count.ParasiteType = models.ParasiteStrongyle
+	}
+	if err := validateEggCount(count, timeNow()); err != nil {
+		return err
+	}
+
+	count.HorseID = horse.ID
+	if err := s.repo.Create(ctx, count); err != nil {
+		return fmt.Errorf("failed to save fecal egg count: %w", err)
+	}
+	return nil
+}
+
+func (s *FecalEggCountServiceImpl) List(ctx context.Context, horseID uint) ([]models.FecalEggCount, error) {
+	counts, err := s.repo.ListByHorse(ctx, horseID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get fecal egg counts: %w", err)
+	}
+	return counts, nil
+}
+
+// Recommend classifies the horse and checks past dewormings for resistance
+func (s *FecalEggCountServiceImpl) Recommend(ctx context.Context, horse *models.Horse) (*models.DewormingRecommendation, error) {
+	counts, err := s.List(ctx, horse.ID)
+	if err != nil {
+		return nil, err
+	}
+	records, err := s.healthRepo.GetRecords(ctx, horse.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get health records: %w", err)
+	}
+
+	recommendation := health.RecommendDeworming(*horse, counts, records, timeNow())
+	return &recommendation, nil
+}
+
+func validateEggCount(count *models.FecalEggCount, now time.Time) error {
+	if count.SampleDate.IsZero() {
+		return fmt.Errorf("%w: sample_date is required", models.ErrInvalidEggCount)
+	}
+	if count.SampleDate.After(now) {
+		return fmt.Errorf("%w: sample_date cannot be in the future", models.ErrInvalidEggCount)
+	}
+	if count.EggsPerGram < 0 || count.EggsPerGram > maxEggsPerGram {
+		return fmt.Errorf("%w: eggs_per_gram must be between 0 and %d", models.ErrInvalidEggCount, maxEggsPerGram)
+	}
+	if !count.ParasiteType.IsValid() {
+		return fmt.Errorf("%w: unknown parasite type %q", models.ErrInvalidEggCount, count.ParasiteType)
+	}
+	return nil
+}
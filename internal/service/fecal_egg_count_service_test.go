@@ -0,0 +1,74 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/polyfant/hulta_pregnancy_app/internal/mocks"
+	"github.com/polyfant/hulta_pregnancy_app/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFecalEggCountService_Record(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	horse := &models.Horse{ID: 5}
+
+	tests := []struct {
+		name    string
+		count   models.FecalEggCount
+		wantErr error
+	}{
+		{name: "defaults to strongyles", count: models.FecalEggCount{SampleDate: now.AddDate(0, 0, -1), EggsPerGram: 350}},
+		{name: "missing sample date", count: models.FecalEggCount{EggsPerGram: 350}, wantErr: models.ErrInvalidEggCount},
+		{name: "future sample", count: models.FecalEggCount{SampleDate: now.AddDate(0, 0, 1)}, wantErr: models.ErrInvalidEggCount},
+		{name: "negative count", count: models.FecalEggCount{SampleDate: now, EggsPerGram: -5}, wantErr: models.ErrInvalidEggCount},
+		{name: "unknown parasite", count: models.FecalEggCount{SampleDate: now, ParasiteType: "LICE"}, wantErr: models.ErrInvalidEggCount},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(mocks.MockFecalEggCountRepository)
+			svc := NewFecalEggCountService(repo, new(mocks.MockHealthRepository)).(*FecalEggCountServiceImpl)
+			setTestNow(t, now)
+			repo.On("Create", ctx, mock.AnythingOfType("*models.FecalEggCount")).Return(nil)
+
+			count := tt.count
+			err := svc.Record(ctx, horse, &count)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				repo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, models.ParasiteStrongyle, count.ParasiteType)
+			assert.Equal(t, horse.ID, count.HorseID)
+		})
+	}
+}
+
+func TestFecalEggCountService_Recommend(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	horse := &models.Horse{ID: 5, BirthDate: now.AddDate(-12, 0, 0)}
+
+	repo := new(mocks.MockFecalEggCountRepository)
+	healthRepo := new(mocks.MockHealthRepository)
+	svc := NewFecalEggCountService(repo, healthRepo).(*FecalEggCountServiceImpl)
+	setTestNow(t, now)
+
+	repo.On("ListByHorse", ctx, uint(5)).Return([]models.FecalEggCount{
+		{SampleDate: now.AddDate(0, -1, 0), EggsPerGram: 300, ParasiteType: models.ParasiteStrongyle},
+	}, nil)
+	healthRepo.On("GetRecords", ctx, uint(5)).Return([]models.HealthRecord{}, nil)
+
+	rec, err := svc.Recommend(ctx, horse)
+
+	require.NoError(t, err)
+	assert.Equal(t, models.ShedderModerate, rec.ShedderLevel)
+	assert.Equal(t, 4, rec.IntervalMonths)
+}
@@ -0,0 +1,453 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/polyfant/hulta_pregnancy_app/internal/logger"
+	"github.com/polyfant/hulta_pregnancy_app/internal/models"
+	"github.com/polyfant/hulta_pregnancy_app/internal/service/notification"
+)
+
+const (
+	// feedForecastDays is the period a forecast and its purchase list cover
+	// unless another is asked for
+	feedForecastDays    = 30
+	feedForecastMaxDays = 365
+	// feedLowStockDays is the supply left at which a feed is low
+	feedLowStockDays = 14
+	// feedExpiryAlertDays is how far ahead the daily check warns of a lot
+	// that will expire before it is used
+	feedExpiryAlertDays = 14
+	// feedSupplyHorizonDays caps the projected stockout date for feeds that
+	// are used very slowly
+	feedSupplyHorizonDays = 10 * 365
+)
+
+// feedUse is how much of a feed a user's horses get a day between them
+type feedUse struct {
+	feedType models.FeedType
+	daily    float64
+	horses   int
+}
+
+// feedLotWaste is what a lot will still hold when it expires
+type feedLotWaste struct {
+	item   models.FeedInventoryItem
+	amount float64
+}
+
+// feedProjection is the supply of one feed with the lots behind it.
+// Restocked is when the latest lot was added, zero if none ever was.
+type feedProjection struct {
+	supply    models.FeedSupply
+	restocked time.Time
+	latest    *models.FeedInventoryItem
+	waste     []feedLotWaste
+	expired   []models.FeedInventoryItem
+}
+
+// Forecast projects how long each feed in the user's store will last on
+// their horses' current rations, warns of stockouts and waste within the
+// period and suggests what to buy to get through it
+func (s *FeedServiceImpl) Forecast(ctx context.Context, userID string, days int) (*models.FeedForecast, error) {
+	if days == 0 {
+		days = feedForecastDays
+	}
+	if days < 1 || days > feedForecastMaxDays {
+		return nil, fmt.Errorf("%w: days must be between 1 and %d", models.ErrInvalidFeed, feedForecastMaxDays)
+	}
+	now := timeNow()
+	projections, err := s.project(ctx, userID, now)
+	if err != nil {
+		return nil, err
+	}
+	return buildFeedForecast(userID, projections, days, now), nil
+}
+
+// project works out the supply of every feed the user stocks or feeds
+func (s *FeedServiceImpl) project(ctx context.Context, userID string, now time.Time) ([]feedProjection, error) {
+	items, err := s.repo.ListInventory(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get feed inventory: %w", err)
+	}
+	horses, err := s.horseRepo.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get horses: %w", err)
+	}
+	horseIDs := make([]uint, 0, len(horses))
+	for _, horse := range horses {
+		horseIDs = append(horseIDs, horse.ID)
+	}
+	rations, err := s.repo.ListRationsForHorses(ctx, horseIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rations: %w", err)
+	}
+	return projectFeedSupplies(items, s.feedUses(horses, rations), now), nil
+}
+
+// feedUses sums the rations by feed. A pregnant mare's ration is raised by
+// the NutritionService multipliers for her stage, by the feed's category.
+func (s *FeedServiceImpl) feedUses(horses []models.Horse, rations []models.FeedRation) map[uint]*feedUse {
+	multipliers := map[uint]models.FeedRequirements{}
+	for _, horse := range horses {
+		if s.nutrition != nil {
+			multipliers[horse.ID] = s.nutrition.RationMultipliers(horse)
+		}
+	}
+
+	uses := map[uint]*feedUse{}
+	for _, ration := range rations {
+		use, ok := uses[ration.FeedTypeID]
+		if !ok {
+			use = &feedUse{feedType: models.FeedType{ID: ration.FeedTypeID}}
+			if ration.FeedType != nil {
+				use.feedType = *ration.FeedType
+			}
+			uses[ration.FeedTypeID] = use
+		}
+		amount := ration.DailyAmount
+		if m, ok := multipliers[ration.HorseID]; ok {
+			amount *= rationMultiplier(m, use.feedType.Category)
+		}
+		use.daily += amount
+		use.horses++
+	}
+	return uses
+}
+
+// rationMultiplier picks the multiplier for a feed category: forage follows
+// hay, hard feed follows grain and supplements follow minerals
+func rationMultiplier(m models.FeedRequirements, category string) float64 {
+	switch category {
+	case models.FeedCategoryHay:
+		return m.Hay
+	case models.FeedCategoryGrain, models.FeedCategoryConcentrate:
+		return m.Grain
+	case models.FeedCategorySupplement, models.FeedCategoryMineral:
+		return m.Minerals
+	}
+	return 1
+}
+
+// projectFeedSupplies runs each feed's lots down at its daily use, soonest
+// expiry first. A lot is usable through its expiry date; what it holds
+// after that is wasted. Feeds nobody is fed are listed as unused.
+func projectFeedSupplies(items []models.FeedInventoryItem, uses map[uint]*feedUse, now time.Time) []feedProjection {
+	byType := map[uint]*feedProjection{}
+	var order []uint
+	get := func(feedType models.FeedType) *feedProjection {
+		p, ok := byType[feedType.ID]
+		if !ok {
+			p = &feedProjection{supply: models.FeedSupply{FeedType: feedType}}
+			byType[feedType.ID] = p
+			order = append(order, feedType.ID)
+		}
+		return p
+	}
+
+	var lots []models.FeedInventoryItem
+	for _, item := range items {
+		feedType := models.FeedType{ID: item.FeedTypeID}
+		if item.FeedType != nil {
+			feedType = *item.FeedType
+		}
+		p := get(feedType)
+		if item.CreatedAt.After(p.restocked) {
+			p.restocked = item.CreatedAt
+		}
+		if p.latest == nil || lotDate(item).After(lotDate(*p.latest)) {
+			latest := item
+			p.latest = &latest
+		}
+		switch {
+		case item.Quantity <= 0:
+		case item.Expired(now):
+			p.expired = append(p.expired, item)
+		default:
+			lots = append(lots, item)
+		}
+	}
+	for _, use := range uses {
+		p := get(use.feedType)
+		if p.supply.FeedType.Name == "" {
+			p.supply.FeedType = use.feedType
+		}
+		p.supply.DailyUse = round2(use.daily)
+		p.supply.Horses = use.horses
+	}
+
+	sort.SliceStable(lots, func(i, j int) bool {
+		a, b := lots[i].ExpiryDate, lots[j].ExpiryDate
+		if a == nil || b == nil {
+			return a != nil && b == nil
+		}
+		return a.Before(*b)
+	})
+
+	days := map[uint]float64{}
+	for _, lot := range lots {
+		p := byType[lot.FeedTypeID]
+		p.supply.Quantity += lot.Quantity
+		daily := 0.0
+		if use, ok := uses[lot.FeedTypeID]; ok {
+			daily = use.daily
+		}
+		wasted := lot.Quantity
+		t := days[lot.FeedTypeID]
+		switch {
+		case daily <= 0:
+			if lot.ExpiryDate == nil {
+				wasted = 0
+			}
+		case lot.ExpiryDate == nil:
+			t += lot.Quantity / daily
+			wasted = 0
+		default:
+			end := lot.ExpiryDate.Truncate(24*time.Hour).Add(24*time.Hour).Sub(now).Hours() / 24
+			if capacity := (end - t) * daily; capacity > 0 {
+				used := math.Min(capacity, lot.Quantity)
+				t += used / daily
+				wasted = lot.Quantity - used
+			}
+		}
+		days[lot.FeedTypeID] = t
+		if wasted > 1e-9 {
+			p.supply.WastedQuantity += wasted
+			p.waste = append(p.waste, feedLotWaste{item: lot, amount: round2(wasted)})
+		}
+	}
+
+	projections := make([]feedProjection, 0, len(order))
+	for _, id := range order {
+		p := byType[id]
+		p.supply.Quantity = round2(p.supply.Quantity)
+		p.supply.WastedQuantity = round2(p.supply.WastedQuantity)
+		if p.supply.DailyUse <= 0 {
+			if p.supply.Quantity <= 0 {
+				continue
+			}
+			p.supply.Status = models.FeedSupplyUnused
+			projections = append(projections, *p)
+			continue
+		}
+
+		t := days[id]
+		supply := math.Round(t*10) / 10
+		p.supply.DaysOfSupply = &supply
+		if t < feedSupplyHorizonDays {
+			stockout := now.Add(time.Duration(t * float64(24*time.Hour))).Truncate(24 * time.Hour)
+			p.supply.StockoutDate = &stockout
+		}
+		switch {
+		case t <= 0:
+			p.supply.Status = models.FeedSupplyOut
+		case t < feedLowStockDays:
+			p.supply.Status = models.FeedSupplyLow
+		default:
+			p.supply.Status = models.FeedSupplyOK
+		}
+		projections = append(projections, *p)
+	}
+
+	sort.SliceStable(projections, func(i, j int) bool {
+		a, b := projections[i].supply, projections[j].supply
+		if a.Status != b.Status {
+			return feedSupplyRank(a.Status) < feedSupplyRank(b.Status)
+		}
+		return a.FeedType.Name < b.FeedType.Name
+	})
+	return projections
+}
+
+func feedSupplyRank(status models.FeedSupplyStatus) int {
+	switch status {
+	case models.FeedSupplyOut:
+		return 0
+	case models.FeedSupplyLow:
+		return 1
+	case models.FeedSupplyOK:
+		return 2
+	}
+	return 3
+}
+
+// lotDate is when a lot was bought, or added if that was not recorded
+func lotDate(item models.FeedInventoryItem) time.Time {
+	if item.PurchaseDate != nil {
+		return *item.PurchaseDate
+	}
+	return item.CreatedAt
+}
+
+// buildFeedForecast lists the supplies and, for each feed that will not
+// last the period, how much to buy to cover the rest of it
+func buildFeedForecast(userID string, projections []feedProjection, days int, now time.Time) *models.FeedForecast {
+	forecast := &models.FeedForecast{
+		UserID:    userID,
+		Date:      now,
+		Days:      days,
+		Supplies:  []models.FeedSupply{},
+		Purchases: []models.FeedPurchase{},
+		Warnings:  []string{},
+	}
+	until := now.AddDate(0, 0, days)
+
+	for _, p := range projections {
+		supply := p.supply
+		forecast.Supplies = append(forecast.Supplies, supply)
+		name, unit := supply.FeedType.Name, supply.FeedType.Unit
+
+		switch supply.Status {
+		case models.FeedSupplyOut:
+			forecast.Warnings = append(forecast.Warnings, fmt.Sprintf("No %s left for the %d horse(s) fed it.", name, supply.Horses))
+		case models.FeedSupplyLow:
+			forecast.Warnings = append(forecast.Warnings, fmt.Sprintf("%s runs out in %.0f days, on %s.", name, *supply.DaysOfSupply, supply.StockoutDate.Format("2006-01-02")))
+		}
+		for _, waste := range p.waste {
+			if waste.item.ExpiryDate.Before(until) {
+				forecast.Warnings = append(forecast.Warnings, fmt.Sprintf("%.2f %s of %s expires on %s before it can be used.", waste.amount, unit, name, waste.item.ExpiryDate.Format("2006-01-02")))
+			}
+		}
+		for _, item := range p.expired {
+			forecast.Warnings = append(forecast.Warnings, fmt.Sprintf("%.2f %s of %s expired on %s; take it out of the store.", item.Quantity, unit, name, item.ExpiryDate.Format("2006-01-02")))
+		}
+
+		if supply.DaysOfSupply == nil || *supply.DaysOfSupply >= float64(days) {
+			continue
+		}
+		purchase := models.FeedPurchase{
+			FeedType: supply.FeedType,
+			Quantity: round2(supply.DailyUse * (float64(days) - *supply.DaysOfSupply)),
+			NeededBy: supply.StockoutDate,
+		}
+		if p.latest != nil {
+			purchase.Supplier = p.latest.Supplier
+			if p.latest.UnitPrice != nil {
+				cost := round2(purchase.Quantity * *p.latest.UnitPrice)
+				purchase.EstimatedCost = &cost
+			}
+		}
+		forecast.Purchases = append(forecast.Purchases, purchase)
+	}
+	return forecast
+}
+
+// CheckStock alerts every user who stocks feed when a feed runs low or out,
+// once each time it is restocked, and when a lot will expire before it is
+// used. Feeds that were never stocked are not alerted on. It returns the
+// number of alerts sent.
+func (s *FeedServiceImpl) CheckStock(ctx context.Context) (int, error) {
+	users, err := s.repo.ListInventoryUsers(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get feed users: %w", err)
+	}
+
+	now := timeNow()
+	sent := 0
+	for _, userID := range users {
+		projections, err := s.project(ctx, userID, now)
+		if err != nil {
+			logger.Warn("Failed to forecast feed stock", map[string]interface{}{"userID": userID, "error": err.Error()})
+			continue
+		}
+		for _, p := range projections {
+			n, err := s.alertFeed(ctx, userID, p, now)
+			sent += n
+			if err != nil {
+				return sent, err
+			}
+		}
+	}
+	return sent, nil
+}
+
+// ScheduleStockChecks runs CheckStock in the background at the given interval
+func (s *FeedServiceImpl) ScheduleStockChecks(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	go func() {
+		for range ticker.C {
+			sent, err := s.CheckStock(context.Background())
+			if err != nil {
+				logger.Error(err, "Feed stock check failed")
+				continue
+			}
+			if sent > 0 {
+				logger.Info("Feed alerts sent", map[string]interface{}{"count": sent})
+			}
+		}
+	}()
+}
+
+func (s *FeedServiceImpl) alertFeed(ctx context.Context, userID string, p feedProjection, now time.Time) (int, error) {
+	supply := p.supply
+	name, unit := supply.FeedType.Name, supply.FeedType.Unit
+	sent := 0
+
+	if (supply.Status == models.FeedSupplyLow || supply.Status == models.FeedSupplyOut) && !p.restocked.IsZero() {
+		title := fmt.Sprintf("%s running low", name)
+		message := fmt.Sprintf("%s will run out in %.0f days, on %s, at %.2f %s a day.", name, *supply.DaysOfSupply, supply.StockoutDate.Format("2006-01-02"), supply.DailyUse, unit)
+		priority := notification.Medium
+		if supply.Status == models.FeedSupplyOut {
+			title = fmt.Sprintf("Out of %s", name)
+			message = fmt.Sprintf("There is no usable %s left for the %d horse(s) fed it.", name, supply.Horses)
+			priority = notification.High
+		}
+		ok, err := s.repo.MarkAlertSent(ctx, &models.FeedAlert{
+			UserID:  userID,
+			Item:    fmt.Sprintf("Feed %d %s", supply.FeedType.ID, supply.Status),
+			DueDate: p.restocked,
+			SentAt:  now,
+		})
+		if err != nil {
+			return sent, fmt.Errorf("failed to record feed alert: %w", err)
+		}
+		if ok && s.notify(ctx, userID, title, message, priority) {
+			sent++
+		}
+	}
+
+	alertBy := now.AddDate(0, 0, feedExpiryAlertDays)
+	for _, waste := range p.waste {
+		if !waste.item.ExpiryDate.Before(alertBy) {
+			continue
+		}
+		ok, err := s.repo.MarkAlertSent(ctx, &models.FeedAlert{
+			UserID:  userID,
+			Item:    fmt.Sprintf("Feed lot %d expiry", waste.item.ID),
+			DueDate: *waste.item.ExpiryDate,
+			SentAt:  now,
+		})
+		if err != nil {
+			return sent, fmt.Errorf("failed to record feed alert: %w", err)
+		}
+		message := fmt.Sprintf("%.2f %s of %s expires on %s before it can be used. Use it first or sell it on.", waste.amount, unit, name, waste.item.ExpiryDate.Format("2006-01-02"))
+		if ok && s.notify(ctx, userID, fmt.Sprintf("%s expiring", name), message, notification.Low) {
+			sent++
+		}
+	}
+	return sent, nil
+}
+
+// notify sends a feed alert. Delivery failures are logged rather than
+// returned, as the alert is already recorded.
+func (s *FeedServiceImpl) notify(ctx context.Context, userID, title, message string, priority notification.Priority) bool {
+	if s.notifier == nil {
+		return false
+	}
+	alert := &notification.Notification{
+		Type:     notification.FeedAlert,
+		UserID:   userID,
+		Title:    title,
+		Message:  message,
+		Priority: priority,
+	}
+	if err := s.notifier.SendNotification(ctx, alert); err != nil {
+		logger.Warn("Failed to send feed alert", map[string]interface{}{"userID": userID, "error": err.Error()})
+		return false
+	}
+	return true
+}
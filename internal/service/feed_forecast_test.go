@@ -0,0 +1,180 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/polyfant/hulta_pregnancy_app/internal/mocks"
+	"github.com/polyfant/hulta_pregnancy_app/internal/models"
+	"github.com/polyfant/hulta_pregnancy_app/internal/service/health"
+	"github.com/polyfant/hulta_pregnancy_app/internal/service/notification"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// stubNutrition raises the rations of pregnant mares by fixed multipliers
+type stubNutrition struct {
+	pregnant models.FeedRequirements
+}
+
+func (s stubNutrition) RecommendFeed(ctx context.Context, horse *models.Horse, activity health.ActivityLevel) (*models.FeedRecommendation, error) {
+	return nil, nil
+}
+
+func (s stubNutrition) EvaluateRation(ctx context.Context, horse *models.Horse, activity health.ActivityLevel, lactationMonth int, rations []models.FeedRation) (*models.RationEvaluation, error) {
+	return nil, nil
+}
+
+func (s stubNutrition) Lactation(ctx context.Context, horse *models.Horse) (*models.Lactation, error) {
+	return nil, nil
+}
+
+func (s stubNutrition) RationMultipliers(horse models.Horse) models.FeedRequirements {
+	if horse.IsPregnant {
+		return s.pregnant
+	}
+	return models.FeedRequirements{Hay: 1, Grain: 1, Minerals: 1, Water: 1}
+}
+
+func TestProjectFeedSupplies(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	date := func(y int, m time.Month, d int) *time.Time {
+		v := time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+		return &v
+	}
+	hay := models.FeedType{ID: 1, Name: "Hay", Category: models.FeedCategoryHay, Unit: "kg"}
+	oats := models.FeedType{ID: 2, Name: "Oats", Category: models.FeedCategoryGrain, Unit: "kg"}
+	salt := models.FeedType{ID: 3, Name: "Salt", Category: models.FeedCategoryMineral, Unit: "kg"}
+	items := []models.FeedInventoryItem{
+		{ID: 10, FeedTypeID: 1, FeedType: &hay, Quantity: 20},
+		{ID: 11, FeedTypeID: 1, FeedType: &hay, Quantity: 10, ExpiryDate: date(2024, 6, 3)},
+		{ID: 12, FeedTypeID: 1, FeedType: &hay, Quantity: 5, ExpiryDate: date(2024, 5, 20)},
+		{ID: 13, FeedTypeID: 3, FeedType: &salt, Quantity: 4, ExpiryDate: date(2024, 8, 1)},
+	}
+	uses := map[uint]*feedUse{
+		1: {feedType: hay, daily: 2, horses: 1},
+		2: {feedType: oats, daily: 1, horses: 1},
+	}
+
+	projections := projectFeedSupplies(items, uses, now)
+	require.Len(t, projections, 3)
+
+	out := projections[0].supply
+	assert.Equal(t, "Oats", out.FeedType.Name)
+	assert.Equal(t, models.FeedSupplyOut, out.Status)
+	assert.True(t, projections[0].restocked.IsZero())
+
+	// The lot expiring on the 3rd lasts two and a half days at 2 kg a day,
+	// leaving 5 kg to waste; the 20 kg without an expiry lasts ten more
+	low := projections[1]
+	assert.Equal(t, models.FeedSupplyLow, low.supply.Status)
+	assert.Equal(t, 30.0, low.supply.Quantity)
+	require.NotNil(t, low.supply.DaysOfSupply)
+	assert.Equal(t, 12.5, *low.supply.DaysOfSupply)
+	assert.Equal(t, *date(2024, 6, 14), *low.supply.StockoutDate)
+	assert.Equal(t, 5.0, low.supply.WastedQuantity)
+	require.Len(t, low.waste, 1)
+	assert.Equal(t, uint(11), low.waste[0].item.ID)
+	require.Len(t, low.expired, 1)
+	assert.Equal(t, uint(12), low.expired[0].ID)
+
+	unused := projections[2].supply
+	assert.Equal(t, models.FeedSupplyUnused, unused.Status)
+	assert.Nil(t, unused.DaysOfSupply)
+	assert.Equal(t, 4.0, unused.WastedQuantity)
+}
+
+func TestFeedService_Forecast(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	price := 0.5
+	hay := models.FeedType{ID: 1, Name: "Hay", Category: models.FeedCategoryHay, Unit: "kg"}
+	horses := []models.Horse{
+		{ID: 1, UserID: "owner"},
+		{ID: 2, UserID: "owner", IsPregnant: true},
+	}
+
+	t.Run("raises pregnant mares' rations and suggests a purchase", func(t *testing.T) {
+		setTestNow(t, now)
+		repo := new(mocks.MockFeedRepository)
+		horseRepo := new(mocks.MockHorseRepository)
+		svc := NewFeedService(repo, horseRepo, stubNutrition{pregnant: models.FeedRequirements{Hay: 1.5, Grain: 1.2, Minerals: 2, Water: 1}}, new(mockNotifier))
+		repo.On("ListInventory", ctx, "owner").Return([]models.FeedInventoryItem{
+			{ID: 10, FeedTypeID: 1, FeedType: &hay, Quantity: 100, UnitPrice: &price, Supplier: "Hay Farm"},
+		}, nil)
+		horseRepo.On("ListByUser", ctx, "owner").Return(horses, nil)
+		repo.On("ListRationsForHorses", ctx, []uint{1, 2}).Return([]models.FeedRation{
+			{HorseID: 1, FeedTypeID: 1, FeedType: &hay, DailyAmount: 8},
+			{HorseID: 2, FeedTypeID: 1, FeedType: &hay, DailyAmount: 8},
+		}, nil)
+
+		forecast, err := svc.Forecast(ctx, "owner", 0)
+		require.NoError(t, err)
+		assert.Equal(t, feedForecastDays, forecast.Days)
+		require.Len(t, forecast.Supplies, 1)
+		supply := forecast.Supplies[0]
+		assert.Equal(t, 20.0, supply.DailyUse)
+		assert.Equal(t, 2, supply.Horses)
+		assert.Equal(t, 5.0, *supply.DaysOfSupply)
+		assert.Equal(t, models.FeedSupplyLow, supply.Status)
+
+		require.Len(t, forecast.Purchases, 1)
+		purchase := forecast.Purchases[0]
+		assert.Equal(t, 500.0, purchase.Quantity)
+		assert.Equal(t, "Hay Farm", purchase.Supplier)
+		require.NotNil(t, purchase.EstimatedCost)
+		assert.Equal(t, 250.0, *purchase.EstimatedCost)
+		assert.Equal(t, now.AddDate(0, 0, 5), *purchase.NeededBy)
+		assert.Len(t, forecast.Warnings, 1)
+	})
+
+	t.Run("rejects a period over a year", func(t *testing.T) {
+		setTestNow(t, now)
+		svc := NewFeedService(new(mocks.MockFeedRepository), new(mocks.MockHorseRepository), stubNutrition{pregnant: models.FeedRequirements{Hay: 1.5, Grain: 1.2, Minerals: 2, Water: 1}}, new(mockNotifier))
+		_, err := svc.Forecast(ctx, "owner", 400)
+		assert.ErrorIs(t, err, models.ErrInvalidFeed)
+	})
+}
+
+func TestFeedService_CheckStock(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	restocked := time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)
+	expiry := time.Date(2024, 6, 5, 0, 0, 0, 0, time.UTC)
+	hay := models.FeedType{ID: 1, Name: "Hay", Category: models.FeedCategoryHay, Unit: "kg"}
+	oats := models.FeedType{ID: 2, Name: "Oats", Category: models.FeedCategoryGrain, Unit: "kg"}
+
+	setTestNow(t, now)
+	repo := new(mocks.MockFeedRepository)
+	horseRepo := new(mocks.MockHorseRepository)
+	notifier := new(mockNotifier)
+	svc := NewFeedService(repo, horseRepo, stubNutrition{pregnant: models.FeedRequirements{Hay: 1.5, Grain: 1.2, Minerals: 2, Water: 1}}, notifier)
+	repo.On("ListInventoryUsers", ctx).Return([]string{"owner"}, nil)
+	repo.On("ListInventory", ctx, "owner").Return([]models.FeedInventoryItem{
+		{ID: 10, FeedTypeID: 1, FeedType: &hay, Quantity: 20, CreatedAt: restocked},
+		{ID: 11, FeedTypeID: 1, FeedType: &hay, Quantity: 50, ExpiryDate: &expiry, CreatedAt: restocked},
+	}, nil)
+	horseRepo.On("ListByUser", ctx, "owner").Return([]models.Horse{{ID: 1, UserID: "owner"}}, nil)
+	repo.On("ListRationsForHorses", ctx, []uint{1}).Return([]models.FeedRation{
+		{HorseID: 1, FeedTypeID: 1, FeedType: &hay, DailyAmount: 5},
+		{HorseID: 1, FeedTypeID: 2, FeedType: &oats, DailyAmount: 1},
+	}, nil)
+	repo.On("MarkAlertSent", ctx, mock.MatchedBy(func(a *models.FeedAlert) bool {
+		return a.Item == "Feed 1 LOW" && a.DueDate.Equal(restocked)
+	})).Return(true, nil).Once()
+	repo.On("MarkAlertSent", ctx, mock.MatchedBy(func(a *models.FeedAlert) bool {
+		return a.Item == "Feed lot 11 expiry" && a.DueDate.Equal(expiry)
+	})).Return(false, nil).Once()
+	notifier.On("SendNotification", ctx, mock.MatchedBy(func(n *notification.Notification) bool {
+		return n.Type == notification.FeedAlert && n.UserID == "owner" && n.Priority == notification.Medium
+	})).Return(nil).Once()
+
+	sent, err := svc.CheckStock(ctx)
+	require.NoError(t, err)
+	// Oats were never stocked, and the expiry alert had already been sent
+	assert.Equal(t, 1, sent)
+	repo.AssertExpectations(t)
+	notifier.AssertExpectations(t)
+}
@@ -0,0 +1,390 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/polyfant/hulta_pregnancy_app/internal/models"
+	"github.com/polyfant/hulta_pregnancy_app/internal/repository"
+)
+
+// feedExpiryWarningDays is how far ahead stock levels count a lot as expiring
+const feedExpiryWarningDays = 30
+
+// FeedServiceImpl keeps each user's feed store. Feedings draw on the
+// store of the horse's owner, whoever logs them.
+type FeedServiceImpl struct {
+	repo      repository.FeedRepository
+	horseRepo repository.HorseRepository
+	nutrition NutritionService
+	notifier  Notifier
+}
+
+func NewFeedService(
+	repo repository.FeedRepository,
+	horseRepo repository.HorseRepository,
+	nutrition NutritionService,
+	notifier Notifier,
+) FeedService {
+	return &FeedServiceImpl{
+		repo:      repo,
+		horseRepo: horseRepo,
+		nutrition: nutrition,
+		notifier:  notifier,
+	}
+}
+
+func (s *FeedServiceImpl) ListTypes(ctx context.Context) ([]models.FeedType, error) {
+	feedTypes, err := s.repo.ListTypes(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get feed types: %w", err)
+	}
+	return feedTypes, nil
+}
+
+// CreateType adds a feed to the shared catalogue. Names are unique within
+// a category, ignoring case.
+func (s *FeedServiceImpl) CreateType(ctx context.Context, feedType *models.FeedType) error {
+	feedType.Name = strings.TrimSpace(feedType.Name)
+	feedType.Category = strings.ToLower(strings.TrimSpace(feedType.Category))
+	feedType.Unit = strings.ToLower(strings.TrimSpace(feedType.Unit))
+	if err := validateFeedType(feedType); err != nil {
+		return err
+	}
+
+	existing, err := s.repo.ListTypes(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get feed types: %w", err)
+	}
+	for _, other := range existing {
+		if other.Category == feedType.Category && strings.EqualFold(other.Name, feedType.Name) {
+			return fmt.Errorf("%w: %s is already a %s feed", models.ErrInvalidFeed, other.Name, other.Category)
+		}
+	}
+
+	feedType.ID = 0
+	if err := s.repo.CreateType(ctx, feedType); err != nil {
+		return fmt.Errorf("failed to create feed type: %w", err)
+	}
+	return nil
+}
+
+// SetNutrients replaces a feed's nutrient composition, per kg or litre as
+// fed. The catalogue is shared, so only the user who added the feed may
+// change it; the seeded feeds are changed by migrations alone.
+func (s *FeedServiceImpl) SetNutrients(ctx context.Context, userID string, feedTypeID uint, nutrients *models.Nutrients) (*models.FeedType, error) {
+	feedType, err := s.repo.GetType(ctx, feedTypeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get feed type: %w", err)
+	}
+	if feedType == nil {
+		return nil, models.ErrFeedNotFound
+	}
+	if feedType.CreatedBy == nil || *feedType.CreatedBy != userID {
+		return nil, fmt.Errorf("%w: only the user who added %s can change its nutrients", models.ErrAccessDenied, feedType.Name)
+	}
+	feedType.Nutrients = nutrients
+	if err := validateFeedType(feedType); err != nil {
+		return nil, err
+	}
+	if err := s.repo.SaveType(ctx, feedType); err != nil {
+		return nil, fmt.Errorf("failed to save feed type: %w", err)
+	}
+	return feedType, nil
+}
+
+func (s *FeedServiceImpl) ListInventory(ctx context.Context, userID string) ([]models.FeedInventoryItem, error) {
+	items, err := s.repo.ListInventory(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get feed inventory: %w", err)
+	}
+	return items, nil
+}
+
+// AddInventory stores a new lot in the user's feed store
+func (s *FeedServiceImpl) AddInventory(ctx context.Context, item *models.FeedInventoryItem) error {
+	if err := s.validateInventoryItem(ctx, item); err != nil {
+		return err
+	}
+	item.ID = 0
+	if err := s.repo.SaveInventoryItem(ctx, item); err != nil {
+		return fmt.Errorf("failed to save feed inventory: %w", err)
+	}
+	return nil
+}
+
+// UpdateInventory replaces a lot, for instance after a stock count
+func (s *FeedServiceImpl) UpdateInventory(ctx context.Context, item *models.FeedInventoryItem) error {
+	existing, err := s.ownedItem(ctx, item.UserID, item.ID)
+	if err != nil {
+		return err
+	}
+	if err := s.validateInventoryItem(ctx, item); err != nil {
+		return err
+	}
+	item.CreatedAt = existing.CreatedAt
+	if err := s.repo.SaveInventoryItem(ctx, item); err != nil {
+		return fmt.Errorf("failed to save feed inventory: %w", err)
+	}
+	return nil
+}
+
+func (s *FeedServiceImpl) DeleteInventory(ctx context.Context, userID string, itemID uint) error {
+	if _, err := s.ownedItem(ctx, userID, itemID); err != nil {
+		return err
+	}
+	if err := s.repo.DeleteInventoryItem(ctx, itemID); err != nil {
+		return fmt.Errorf("failed to delete feed inventory: %w", err)
+	}
+	return nil
+}
+
+// ownedItem loads a lot and makes sure it is in the user's store
+func (s *FeedServiceImpl) ownedItem(ctx context.Context, userID string, itemID uint) (*models.FeedInventoryItem, error) {
+	item, err := s.repo.GetInventoryItem(ctx, itemID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get feed inventory: %w", err)
+	}
+	if item == nil || item.UserID != userID {
+		return nil, models.ErrFeedNotFound
+	}
+	return item, nil
+}
+
+// StockLevels totals the user's lots by feed. Expired lots are counted
+// apart, as they should not be fed.
+func (s *FeedServiceImpl) StockLevels(ctx context.Context, userID string) ([]models.FeedStockLevel, error) {
+	items, err := s.repo.ListInventory(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get feed inventory: %w", err)
+	}
+	return feedStockLevels(items, timeNow()), nil
+}
+
+func feedStockLevels(items []models.FeedInventoryItem, now time.Time) []models.FeedStockLevel {
+	warnBy := now.AddDate(0, 0, feedExpiryWarningDays)
+	levels := map[uint]*models.FeedStockLevel{}
+	var order []uint
+	for _, item := range items {
+		level, ok := levels[item.FeedTypeID]
+		if !ok {
+			level = &models.FeedStockLevel{FeedType: models.FeedType{ID: item.FeedTypeID}}
+			if item.FeedType != nil {
+				level.FeedType = *item.FeedType
+			}
+			levels[item.FeedTypeID] = level
+			order = append(order, item.FeedTypeID)
+		}
+		if item.Quantity <= 0 {
+			continue
+		}
+		level.Lots++
+		if item.Expired(now) {
+			level.ExpiredQuantity += item.Quantity
+			continue
+		}
+		level.Quantity += item.Quantity
+		if item.UnitPrice != nil {
+			level.Value += item.Quantity * *item.UnitPrice
+		}
+		if item.ExpiryDate != nil {
+			if item.ExpiryDate.Before(warnBy) {
+				level.ExpiringQuantity += item.Quantity
+			}
+			if level.NextExpiry == nil || item.ExpiryDate.Before(*level.NextExpiry) {
+				expiry := *item.ExpiryDate
+				level.NextExpiry = &expiry
+			}
+		}
+	}
+
+	result := make([]models.FeedStockLevel, 0, len(order))
+	for _, id := range order {
+		level := levels[id]
+		level.Quantity = round2(level.Quantity)
+		level.ExpiredQuantity = round2(level.ExpiredQuantity)
+		level.ExpiringQuantity = round2(level.ExpiringQuantity)
+		level.Value = round2(level.Value)
+		result = append(result, *level)
+	}
+	sort.SliceStable(result, func(i, j int) bool { return result[i].FeedType.Name < result[j].FeedType.Name })
+	return result
+}
+
+func (s *FeedServiceImpl) GetRations(ctx context.Context, horse *models.Horse) ([]models.FeedRation, error) {
+	rations, err := s.repo.ListRations(ctx, horse.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rations: %w", err)
+	}
+	return rations, nil
+}
+
+// SetRations replaces the horse's ration, one line per feed. An empty list
+// clears it.
+func (s *FeedServiceImpl) SetRations(ctx context.Context, horse *models.Horse, rations []models.FeedRation) ([]models.FeedRation, error) {
+	now := timeNow()
+	seen := map[uint]bool{}
+	for i := range rations {
+		ration := &rations[i]
+		if seen[ration.FeedTypeID] {
+			return nil, fmt.Errorf("%w: feed type %d is in the ration twice", models.ErrInvalidFeed, ration.FeedTypeID)
+		}
+		seen[ration.FeedTypeID] = true
+		if ration.DailyAmount <= 0 {
+			return nil, fmt.Errorf("%w: daily_amount must be positive", models.ErrInvalidFeed)
+		}
+		feedType, err := s.feedType(ctx, ration.FeedTypeID)
+		if err != nil {
+			return nil, err
+		}
+		ration.ID = 0
+		ration.HorseID = horse.ID
+		ration.FeedType = feedType
+		ration.LastUpdated = now
+	}
+
+	if err := s.repo.ReplaceRations(ctx, horse.ID, rations); err != nil {
+		return nil, fmt.Errorf("failed to save rations: %w", err)
+	}
+	return rations, nil
+}
+
+// LogFeeding records a feeding and draws it from the owner's store, from
+// the lot that expires first. Expired lots are never drawn on; whatever the
+// store cannot cover is returned as the log's shortfall.
+func (s *FeedServiceImpl) LogFeeding(ctx context.Context, horse *models.Horse, log *models.FeedLog) error {
+	now := timeNow()
+	if log.FeedingTime.IsZero() {
+		log.FeedingTime = now
+	}
+	switch {
+	case log.Amount <= 0:
+		return fmt.Errorf("%w: amount must be positive", models.ErrInvalidFeed)
+	case log.FeedingTime.After(now):
+		return fmt.Errorf("%w: feeding_time cannot be in the future", models.ErrInvalidFeed)
+	}
+	feedType, err := s.feedType(ctx, log.FeedTypeID)
+	if err != nil {
+		return err
+	}
+
+	items, err := s.repo.ListInventory(ctx, horse.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to get feed inventory: %w", err)
+	}
+	draws, shortfall := drawFeedStock(items, log.FeedTypeID, log.Amount, now)
+
+	log.ID = 0
+	log.HorseID = horse.ID
+	if err := s.repo.CreateLog(ctx, log, draws); err != nil {
+		return fmt.Errorf("failed to log feeding: %w", err)
+	}
+	log.FeedType = feedType
+	log.Shortfall = shortfall
+	return nil
+}
+
+// drawFeedStock takes an amount of a feed from the unexpired lots, soonest
+// to expire first and lots without an expiry date last
+func drawFeedStock(items []models.FeedInventoryItem, feedTypeID uint, amount float64, now time.Time) ([]models.FeedDraw, float64) {
+	var lots []models.FeedInventoryItem
+	for _, item := range items {
+		if item.FeedTypeID == feedTypeID && item.Quantity > 0 && !item.Expired(now) {
+			lots = append(lots, item)
+		}
+	}
+	sort.SliceStable(lots, func(i, j int) bool {
+		a, b := lots[i].ExpiryDate, lots[j].ExpiryDate
+		switch {
+		case a == nil:
+			return false
+		case b == nil:
+			return true
+		}
+		return a.Before(*b)
+	})
+
+	var draws []models.FeedDraw
+	remaining := amount
+	for _, lot := range lots {
+		if remaining <= 0 {
+			break
+		}
+		take := math.Min(lot.Quantity, remaining)
+		draws = append(draws, models.FeedDraw{InventoryID: lot.ID, Amount: take})
+		remaining -= take
+	}
+	return draws, round2(math.Max(remaining, 0))
+}
+
+func (s *FeedServiceImpl) ListFeedings(ctx context.Context, horse *models.Horse, since *time.Time) ([]models.FeedLog, error) {
+	logs, err := s.repo.ListLogs(ctx, horse.ID, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get feedings: %w", err)
+	}
+	return logs, nil
+}
+
+// feedType loads a feed type, which must exist
+func (s *FeedServiceImpl) feedType(ctx context.Context, id uint) (*models.FeedType, error) {
+	feedType, err := s.repo.GetType(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get feed type: %w", err)
+	}
+	if feedType == nil {
+		return nil, fmt.Errorf("%w: unknown feed type %d", models.ErrInvalidFeed, id)
+	}
+	return feedType, nil
+}
+
+func (s *FeedServiceImpl) validateInventoryItem(ctx context.Context, item *models.FeedInventoryItem) error {
+	switch {
+	case item.Quantity < 0:
+		return fmt.Errorf("%w: quantity cannot be negative", models.ErrInvalidFeed)
+	case item.UnitPrice != nil && *item.UnitPrice < 0:
+		return fmt.Errorf("%w: unit_price cannot be negative", models.ErrInvalidFeed)
+	case item.PurchaseDate != nil && item.PurchaseDate.After(timeNow()):
+		return fmt.Errorf("%w: purchase_date cannot be in the future", models.ErrInvalidFeed)
+	case item.PurchaseDate != nil && item.ExpiryDate != nil && item.ExpiryDate.Before(*item.PurchaseDate):
+		return fmt.Errorf("%w: expiry_date is before purchase_date", models.ErrInvalidFeed)
+	case len(item.Supplier) > 100 || len(item.BatchNumber) > 50:
+		return fmt.Errorf("%w: supplier or batch_number is too long", models.ErrInvalidFeed)
+	}
+	feedType, err := s.feedType(ctx, item.FeedTypeID)
+	if err != nil {
+		return err
+	}
+	item.FeedType = feedType
+	return nil
+}
+
+func validateFeedType(feedType *models.FeedType) error {
+	switch {
+	case feedType.Name == "" || len(feedType.Name) > 100:
+		return fmt.Errorf("%w: name is required and at most 100 characters", models.ErrInvalidFeed)
+	case !oneOf(feedType.Category, models.FeedCategoryHay, models.FeedCategoryGrain, models.FeedCategoryConcentrate, models.FeedCategorySupplement, models.FeedCategoryMineral):
+		return fmt.Errorf("%w: category must be hay, grain, concentrate, supplement or mineral", models.ErrInvalidFeed)
+	case !oneOf(feedType.Unit, models.FeedUnitKg, models.FeedUnitGram, models.FeedUnitLitre, models.FeedUnitMl):
+		return fmt.Errorf("%w: unit must be kg, g, l or ml", models.ErrInvalidFeed)
+	case feedType.Nutrients != nil && feedType.Nutrients.Negative():
+		return fmt.Errorf("%w: nutrients cannot be negative", models.ErrInvalidFeed)
+	}
+	return nil
+}
+
+func oneOf(value string, allowed ...string) bool {
+	for _, a := range allowed {
+		if value == a {
+			return true
+		}
+	}
+	return false
+}
+
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
@@ -0,0 +1,346 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/polyfant/hulta_pregnancy_app/internal/mocks"
+	"github.com/polyfant/hulta_pregnancy_app/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFeedService_CreateType(t *testing.T) {
+	ctx := context.Background()
+	existing := []models.FeedType{{ID: 1, Name: "Hay", Category: "hay", Unit: "kg"}}
+
+	tests := []struct {
+		name     string
+		feedType models.FeedType
+		wantErr  bool
+	}{
+		{name: "valid", feedType: models.FeedType{Name: "Alfalfa pellets", Category: "Concentrate", Unit: "KG"}},
+		{name: "same name in another category", feedType: models.FeedType{Name: "hay", Category: "supplement", Unit: "kg"}},
+		{name: "duplicate", feedType: models.FeedType{Name: " hay ", Category: "hay", Unit: "kg"}, wantErr: true},
+		{name: "no name", feedType: models.FeedType{Category: "hay", Unit: "kg"}, wantErr: true},
+		{name: "unknown category", feedType: models.FeedType{Name: "Carrots", Category: "treats", Unit: "kg"}, wantErr: true},
+		{name: "unknown unit", feedType: models.FeedType{Name: "Oil", Category: "supplement", Unit: "cup"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(mocks.MockFeedRepository)
+			svc := NewFeedService(repo, nil, nil, nil)
+			repo.On("ListTypes", ctx).Return(existing, nil)
+			repo.On("CreateType", ctx, mock.AnythingOfType("*models.FeedType")).Return(nil)
+
+			feedType := tt.feedType
+			err := svc.CreateType(ctx, &feedType)
+
+			if tt.wantErr {
+				assert.ErrorIs(t, err, models.ErrInvalidFeed)
+				repo.AssertNotCalled(t, "CreateType", mock.Anything, mock.Anything)
+				return
+			}
+			require.NoError(t, err)
+			assert.Regexp(t, "^[a-z]+$", feedType.Category)
+			assert.Regexp(t, "^[a-z]+$", feedType.Unit)
+		})
+	}
+}
+
+func TestFeedService_SetNutrients(t *testing.T) {
+	ctx := context.Background()
+	creator := "owner"
+	owned := func() *models.FeedType {
+		return &models.FeedType{ID: 1, Name: "Haylage", Category: "hay", Unit: "kg", CreatedBy: &creator}
+	}
+
+	t.Run("replaces the composition", func(t *testing.T) {
+		repo := new(mocks.MockFeedRepository)
+		svc := NewFeedService(repo, nil, nil, nil)
+		repo.On("GetType", ctx, uint(1)).Return(owned(), nil)
+		repo.On("SaveType", ctx, mock.AnythingOfType("*models.FeedType")).Return(nil)
+
+		got, err := svc.SetNutrients(ctx, "owner", 1, &models.Nutrients{DigestibleEnergy: 1.9, CrudeProtein: 90})
+		require.NoError(t, err)
+		require.NotNil(t, got.Nutrients)
+		assert.Equal(t, 90.0, got.Nutrients.CrudeProtein)
+	})
+
+	t.Run("rejects negative amounts", func(t *testing.T) {
+		repo := new(mocks.MockFeedRepository)
+		svc := NewFeedService(repo, nil, nil, nil)
+		repo.On("GetType", ctx, uint(1)).Return(owned(), nil)
+
+		_, err := svc.SetNutrients(ctx, "owner", 1, &models.Nutrients{Calcium: -1})
+		assert.ErrorIs(t, err, models.ErrInvalidFeed)
+		repo.AssertNotCalled(t, "SaveType", mock.Anything, mock.Anything)
+	})
+
+	t.Run("unknown feed type", func(t *testing.T) {
+		repo := new(mocks.MockFeedRepository)
+		svc := NewFeedService(repo, nil, nil, nil)
+		repo.On("GetType", ctx, uint(7)).Return(nil, nil)
+
+		_, err := svc.SetNutrients(ctx, "owner", 7, &models.Nutrients{})
+		assert.ErrorIs(t, err, models.ErrFeedNotFound)
+	})
+
+	t.Run("another user's feed", func(t *testing.T) {
+		repo := new(mocks.MockFeedRepository)
+		svc := NewFeedService(repo, nil, nil, nil)
+		repo.On("GetType", ctx, uint(1)).Return(owned(), nil)
+
+		_, err := svc.SetNutrients(ctx, "neighbour", 1, &models.Nutrients{CrudeProtein: 90})
+		assert.ErrorIs(t, err, models.ErrAccessDenied)
+		repo.AssertNotCalled(t, "SaveType", mock.Anything, mock.Anything)
+	})
+
+	t.Run("seeded feed", func(t *testing.T) {
+		repo := new(mocks.MockFeedRepository)
+		svc := NewFeedService(repo, nil, nil, nil)
+		repo.On("GetType", ctx, uint(2)).Return(&models.FeedType{ID: 2, Name: "Hay", Category: "hay", Unit: "kg"}, nil)
+
+		_, err := svc.SetNutrients(ctx, "owner", 2, &models.Nutrients{CrudeProtein: 90})
+		assert.ErrorIs(t, err, models.ErrAccessDenied)
+		repo.AssertNotCalled(t, "SaveType", mock.Anything, mock.Anything)
+	})
+}
+
+func TestFeedService_Inventory(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	hay := &models.FeedType{ID: 1, Name: "Hay", Category: "hay", Unit: "kg"}
+	date := func(days int) *time.Time {
+		d := now.AddDate(0, 0, days)
+		return &d
+	}
+	price := func(p float64) *float64 { return &p }
+
+	t.Run("adds a lot", func(t *testing.T) {
+		setTestNow(t, now)
+		repo := new(mocks.MockFeedRepository)
+		svc := NewFeedService(repo, nil, nil, nil)
+		repo.On("GetType", ctx, uint(1)).Return(hay, nil)
+		repo.On("SaveInventoryItem", ctx, mock.AnythingOfType("*models.FeedInventoryItem")).Return(nil)
+
+		item := models.FeedInventoryItem{ID: 9, UserID: "owner", FeedTypeID: 1, Quantity: 500, PurchaseDate: date(-1), ExpiryDate: date(180)}
+		require.NoError(t, svc.AddInventory(ctx, &item))
+		assert.Zero(t, item.ID)
+		assert.Equal(t, hay, item.FeedType)
+	})
+
+	invalid := []struct {
+		name string
+		item models.FeedInventoryItem
+	}{
+		{"negative quantity", models.FeedInventoryItem{FeedTypeID: 1, Quantity: -1}},
+		{"negative price", models.FeedInventoryItem{FeedTypeID: 1, Quantity: 1, UnitPrice: price(-2)}},
+		{"expires before it was bought", models.FeedInventoryItem{FeedTypeID: 1, Quantity: 1, PurchaseDate: date(-1), ExpiryDate: date(-2)}},
+		{"bought in the future", models.FeedInventoryItem{FeedTypeID: 1, Quantity: 1, PurchaseDate: date(2)}},
+		{"unknown feed type", models.FeedInventoryItem{FeedTypeID: 2, Quantity: 1}},
+	}
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			setTestNow(t, now)
+			repo := new(mocks.MockFeedRepository)
+			svc := NewFeedService(repo, nil, nil, nil)
+			repo.On("GetType", ctx, uint(1)).Return(hay, nil)
+			repo.On("GetType", ctx, uint(2)).Return(nil, nil)
+
+			item := tt.item
+			assert.ErrorIs(t, svc.AddInventory(ctx, &item), models.ErrInvalidFeed)
+			repo.AssertNotCalled(t, "SaveInventoryItem", mock.Anything, mock.Anything)
+		})
+	}
+
+	t.Run("cannot update another user's lot", func(t *testing.T) {
+		setTestNow(t, now)
+		repo := new(mocks.MockFeedRepository)
+		svc := NewFeedService(repo, nil, nil, nil)
+		repo.On("GetInventoryItem", ctx, uint(4)).Return(&models.FeedInventoryItem{ID: 4, UserID: "someone else"}, nil)
+
+		item := models.FeedInventoryItem{ID: 4, UserID: "owner", FeedTypeID: 1, Quantity: 10}
+		assert.ErrorIs(t, svc.UpdateInventory(ctx, &item), models.ErrFeedNotFound)
+	})
+
+	t.Run("deletes own lot", func(t *testing.T) {
+		setTestNow(t, now)
+		repo := new(mocks.MockFeedRepository)
+		svc := NewFeedService(repo, nil, nil, nil)
+		repo.On("GetInventoryItem", ctx, uint(4)).Return(&models.FeedInventoryItem{ID: 4, UserID: "owner"}, nil)
+		repo.On("DeleteInventoryItem", ctx, uint(4)).Return(nil)
+
+		require.NoError(t, svc.DeleteInventory(ctx, "owner", 4))
+		repo.AssertExpectations(t)
+	})
+}
+
+func TestFeedService_StockLevels(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	hay := &models.FeedType{ID: 1, Name: "Hay", Unit: "kg"}
+	oats := &models.FeedType{ID: 3, Name: "Oats", Unit: "kg"}
+	date := func(days int) *time.Time {
+		d := now.AddDate(0, 0, days)
+		return &d
+	}
+	price := func(p float64) *float64 { return &p }
+
+	setTestNow(t, now)
+	repo := new(mocks.MockFeedRepository)
+	svc := NewFeedService(repo, nil, nil, nil)
+	repo.On("ListInventory", ctx, "owner").Return([]models.FeedInventoryItem{
+		{ID: 1, FeedTypeID: 3, FeedType: oats, Quantity: 20, ExpiryDate: date(-3)},
+		{ID: 2, FeedTypeID: 3, FeedType: oats, Quantity: 25, ExpiryDate: date(10), UnitPrice: price(0.5)},
+		{ID: 3, FeedTypeID: 3, FeedType: oats, Quantity: 25, ExpiryDate: date(90)},
+		{ID: 4, FeedTypeID: 1, FeedType: hay, Quantity: 400, UnitPrice: price(0.2)},
+		{ID: 5, FeedTypeID: 1, FeedType: hay, Quantity: 0},
+	}, nil)
+
+	levels, err := svc.StockLevels(ctx, "owner")
+
+	require.NoError(t, err)
+	require.Len(t, levels, 2)
+	assert.Equal(t, models.FeedStockLevel{FeedType: *hay, Quantity: 400, Lots: 1, Value: 80}, levels[0])
+	assert.Equal(t, 50.0, levels[1].Quantity)
+	assert.Equal(t, 20.0, levels[1].ExpiredQuantity)
+	assert.Equal(t, 25.0, levels[1].ExpiringQuantity)
+	assert.Equal(t, date(10), levels[1].NextExpiry)
+	assert.Equal(t, 3, levels[1].Lots)
+	assert.Equal(t, 12.5, levels[1].Value)
+}
+
+func TestFeedService_SetRations(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	horse := &models.Horse{ID: 7}
+	hay := &models.FeedType{ID: 1, Name: "Hay", Unit: "kg"}
+
+	t.Run("replaces the ration", func(t *testing.T) {
+		setTestNow(t, now)
+		repo := new(mocks.MockFeedRepository)
+		svc := NewFeedService(repo, nil, nil, nil)
+		repo.On("GetType", ctx, uint(1)).Return(hay, nil)
+		repo.On("ReplaceRations", ctx, uint(7), mock.Anything).Return(nil)
+
+		saved, err := svc.SetRations(ctx, horse, []models.FeedRation{{ID: 3, FeedTypeID: 1, DailyAmount: 9}})
+
+		require.NoError(t, err)
+		require.Len(t, saved, 1)
+		assert.Zero(t, saved[0].ID)
+		assert.Equal(t, uint(7), saved[0].HorseID)
+		assert.Equal(t, now, saved[0].LastUpdated)
+		assert.Equal(t, hay, saved[0].FeedType)
+	})
+
+	t.Run("clears the ration", func(t *testing.T) {
+		setTestNow(t, now)
+		repo := new(mocks.MockFeedRepository)
+		svc := NewFeedService(repo, nil, nil, nil)
+		repo.On("ReplaceRations", ctx, uint(7), []models.FeedRation{}).Return(nil)
+
+		_, err := svc.SetRations(ctx, horse, []models.FeedRation{})
+		require.NoError(t, err)
+	})
+
+	invalid := []struct {
+		name    string
+		rations []models.FeedRation
+	}{
+		{"feed twice", []models.FeedRation{{FeedTypeID: 1, DailyAmount: 5}, {FeedTypeID: 1, DailyAmount: 4}}},
+		{"no amount", []models.FeedRation{{FeedTypeID: 1}}},
+		{"unknown feed type", []models.FeedRation{{FeedTypeID: 2, DailyAmount: 1}}},
+	}
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			setTestNow(t, now)
+			repo := new(mocks.MockFeedRepository)
+			svc := NewFeedService(repo, nil, nil, nil)
+			repo.On("GetType", ctx, uint(1)).Return(hay, nil)
+			repo.On("GetType", ctx, uint(2)).Return(nil, nil)
+
+			_, err := svc.SetRations(ctx, horse, tt.rations)
+			assert.ErrorIs(t, err, models.ErrInvalidFeed)
+			repo.AssertNotCalled(t, "ReplaceRations", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestFeedService_LogFeeding(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	horse := &models.Horse{ID: 7, UserID: "owner"}
+	oats := &models.FeedType{ID: 3, Name: "Oats", Unit: "kg"}
+	date := func(days int) *time.Time {
+		d := now.AddDate(0, 0, days)
+		return &d
+	}
+	store := []models.FeedInventoryItem{
+		{ID: 1, FeedTypeID: 3, Quantity: 5},
+		{ID: 2, FeedTypeID: 3, Quantity: 1.5, ExpiryDate: date(20)},
+		{ID: 3, FeedTypeID: 3, Quantity: 10, ExpiryDate: date(-1)},
+		{ID: 4, FeedTypeID: 1, Quantity: 100, ExpiryDate: date(5)},
+		{ID: 5, FeedTypeID: 3, Quantity: 2, ExpiryDate: date(10)},
+	}
+
+	tests := []struct {
+		name          string
+		amount        float64
+		wantDraws     []models.FeedDraw
+		wantShortfall float64
+	}{
+		{name: "from the lot expiring first", amount: 1.5, wantDraws: []models.FeedDraw{{InventoryID: 5, Amount: 1.5}}},
+		{name: "across lots", amount: 4, wantDraws: []models.FeedDraw{{InventoryID: 5, Amount: 2}, {InventoryID: 2, Amount: 1.5}, {InventoryID: 1, Amount: 0.5}}},
+		{name: "more than in store", amount: 10, wantDraws: []models.FeedDraw{{InventoryID: 5, Amount: 2}, {InventoryID: 2, Amount: 1.5}, {InventoryID: 1, Amount: 5}}, wantShortfall: 1.5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setTestNow(t, now)
+			repo := new(mocks.MockFeedRepository)
+			svc := NewFeedService(repo, nil, nil, nil)
+			repo.On("GetType", ctx, uint(3)).Return(oats, nil)
+			repo.On("ListInventory", ctx, "owner").Return(store, nil)
+			repo.On("CreateLog", ctx, mock.AnythingOfType("*models.FeedLog"), tt.wantDraws).Return(nil)
+
+			log := models.FeedLog{UserID: "groom", FeedTypeID: 3, Amount: tt.amount}
+			require.NoError(t, svc.LogFeeding(ctx, horse, &log))
+
+			repo.AssertExpectations(t)
+			assert.Equal(t, uint(7), log.HorseID)
+			assert.Equal(t, now, log.FeedingTime)
+			assert.Equal(t, tt.wantShortfall, log.Shortfall)
+			assert.Equal(t, oats, log.FeedType)
+		})
+	}
+
+	t.Run("empty store", func(t *testing.T) {
+		setTestNow(t, now)
+		repo := new(mocks.MockFeedRepository)
+		svc := NewFeedService(repo, nil, nil, nil)
+		repo.On("GetType", ctx, uint(3)).Return(oats, nil)
+		repo.On("ListInventory", ctx, "owner").Return([]models.FeedInventoryItem{}, nil)
+		repo.On("CreateLog", ctx, mock.AnythingOfType("*models.FeedLog"), []models.FeedDraw(nil)).Return(nil)
+
+		log := models.FeedLog{FeedTypeID: 3, Amount: 2}
+		require.NoError(t, svc.LogFeeding(ctx, horse, &log))
+		assert.Equal(t, 2.0, log.Shortfall)
+	})
+
+	t.Run("no amount", func(t *testing.T) {
+		setTestNow(t, now)
+		svc := NewFeedService(new(mocks.MockFeedRepository), nil, nil, nil)
+		assert.ErrorIs(t, svc.LogFeeding(ctx, horse, &models.FeedLog{FeedTypeID: 3}), models.ErrInvalidFeed)
+	})
+
+	t.Run("in the future", func(t *testing.T) {
+		setTestNow(t, now)
+		svc := NewFeedService(new(mocks.MockFeedRepository), nil, nil, nil)
+		log := models.FeedLog{FeedTypeID: 3, Amount: 1, FeedingTime: now.Add(time.Hour)}
+		assert.ErrorIs(t, svc.LogFeeding(ctx, horse, &log), models.ErrInvalidFeed)
+	})
+}
@@ -0,0 +1,358 @@
+package growth
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/polyfant/hulta_pregnancy_app/internal/models"
+	"gopkg.in/yaml.v3"
+)
+
+var ErrInvalidReference = errors.New("invalid growth reference")
+
+// Measure is a body measurement with a reference curve
+type Measure string
+
+const (
+	MeasureWeight Measure = "weight" // kg
+	MeasureHeight Measure = "height" // Wither height in cm
+	MeasureCannon Measure = "cannon" // Cannon circumference in cm
+)
+
+// bandZ are the standard normal scores of the percentile bands
+var bandZ = [5]float64{-1.645, -0.674, 0, 0.674, 1.645}
+
+// ReferencePoint is the percentile bands of a measure at one age
+type ReferencePoint struct {
+	AgeDays int     `yaml:"age_days" json:"age_days"`
+	P5      float64 `yaml:"p5" json:"p5"`
+	P25     float64 `yaml:"p25" json:"p25"`
+	P50     float64 `yaml:"p50" json:"p50"`
+	P75     float64 `yaml:"p75" json:"p75"`
+	P95     float64 `yaml:"p95" json:"p95"`
+}
+
+func (p ReferencePoint) bands() [5]float64 {
+	return [5]float64{p.P5, p.P25, p.P50, p.P75, p.P95}
+}
+
+// Mature is the adult size of a breed and the age it is reached at
+type Mature struct {
+	AgeDays int            `yaml:"age_days" json:"age_days"`
+	Weight  ReferencePoint `yaml:"weight" json:"weight"`
+	Height  ReferencePoint `yaml:"height" json:"height"`
+}
+
+// Reference is the growth reference for one breed or type
+type Reference struct {
+	Breed     string           `yaml:"breed" json:"breed"`
+	Aliases   []string         `yaml:"aliases" json:"aliases,omitempty"`
+	Default   bool             `yaml:"default" json:"default,omitempty"`
+	GainLimit float64          `yaml:"max_gain_ratio" json:"max_gain_ratio,omitempty"` // Spurt limit as a multiple of the median daily gain
+	Weight    []ReferencePoint `yaml:"weight" json:"weight"`
+	Height    []ReferencePoint `yaml:"height" json:"height"`
+	Cannon    []ReferencePoint `yaml:"cannon" json:"cannon"`
+	Mature    *Mature          `yaml:"mature" json:"mature,omitempty"`
+}
+
+// Curve returns the reference points of a measure, youngest first
+func (r *Reference) Curve(measure Measure) []ReferencePoint {
+	switch measure {
+	case MeasureWeight:
+		return r.Weight
+	case MeasureHeight:
+		return r.Height
+	case MeasureCannon:
+		return r.Cannon
+	}
+	return nil
+}
+
+// At interpolates the bands of a measure at an age. Ages outside the curve
+// have no reference.
+func (r *Reference) At(measure Measure, ageDays int) (ReferencePoint, bool) {
+	curve := r.Curve(measure)
+	if len(curve) == 0 || ageDays < curve[0].AgeDays || ageDays > curve[len(curve)-1].AgeDays {
+		return ReferencePoint{}, false
+	}
+
+	i := sort.Search(len(curve), func(i int) bool { return curve[i].AgeDays >= ageDays })
+	if curve[i].AgeDays == ageDays {
+		return curve[i], true
+	}
+
+	before, after := curve[i-1], curve[i]
+	f := float64(ageDays-before.AgeDays) / float64(after.AgeDays-before.AgeDays)
+	lerp := func(a, b float64) float64 { return a + (b-a)*f }
+	return ReferencePoint{
+		AgeDays: ageDays,
+		P5:      lerp(before.P5, after.P5),
+		P25:     lerp(before.P25, after.P25),
+		P50:     lerp(before.P50, after.P50),
+		P75:     lerp(before.P75, after.P75),
+		P95:     lerp(before.P95, after.P95),
+	}, true
+}
+
+// ReferenceCurves holds the growth references of every breed
+type ReferenceCurves struct {
+	References []Reference
+}
+
+// LoadReferenceCurves reads every .yaml, .yml and .json file in dir, one
+// reference per file. Exactly one reference must be the default.
+func LoadReferenceCurves(dir string) (*ReferenceCurves, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read growth references: %w", err)
+	}
+
+	curves := &ReferenceCurves{}
+	for _, entry := range entries {
+		ext := strings.ToLower(filepath.Ext(entry.Name()))
+		if entry.IsDir() || (ext != ".yaml" && ext != ".yml" && ext != ".json") {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", entry.Name(), err)
+		}
+		reference, err := ParseReference(data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", entry.Name(), err)
+		}
+		curves.References = append(curves.References, *reference)
+	}
+
+	if err := curves.validate(); err != nil {
+		return nil, err
+	}
+	return curves, nil
+}
+
+// ParseReference parses and checks one reference file
+func ParseReference(data []byte) (*Reference, error) {
+	var reference Reference
+	if err := yaml.Unmarshal(data, &reference); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidReference, err)
+	}
+	if strings.TrimSpace(reference.Breed) == "" {
+		return nil, fmt.Errorf("%w: reference without a breed", ErrInvalidReference)
+	}
+	if reference.GainLimit != 0 && reference.GainLimit <= 1 {
+		return nil, fmt.Errorf("%w: %s max_gain_ratio must be above 1", ErrInvalidReference, reference.Breed)
+	}
+	for _, measure := range []Measure{MeasureWeight, MeasureHeight, MeasureCannon} {
+		if err := validateCurve(reference.Curve(measure)); err != nil {
+			return nil, fmt.Errorf("%w: %s %s: %v", ErrInvalidReference, reference.Breed, measure, err)
+		}
+	}
+	if err := validateMature(&reference); err != nil {
+		return nil, fmt.Errorf("%w: %s mature: %v", ErrInvalidReference, reference.Breed, err)
+	}
+	return &reference, nil
+}
+
+// validateMature checks the mature size comes after the curves end and is
+// no smaller than the median at their end
+func validateMature(reference *Reference) error {
+	mature := reference.Mature
+	if mature == nil {
+		return nil
+	}
+	for _, measure := range []Measure{MeasureWeight, MeasureHeight} {
+		curve := reference.Curve(measure)
+		last := curve[len(curve)-1]
+		if mature.AgeDays <= last.AgeDays {
+			return fmt.Errorf("age must be after the last %s age %d, got %d", measure, last.AgeDays, mature.AgeDays)
+		}
+		point, _ := mature.At(measure)
+		point.AgeDays = mature.AgeDays
+		if err := validateCurve([]ReferencePoint{last, point}); err != nil {
+			return fmt.Errorf("%s: %v", measure, err)
+		}
+		if point.P50 < last.P50 {
+			return fmt.Errorf("%s median %.1f is below the median of %.1f at day %d", measure, point.P50, last.P50, last.AgeDays)
+		}
+	}
+	return nil
+}
+
+// At returns the mature bands of a measure. Only weight and height have a
+// mature size.
+func (m *Mature) At(measure Measure) (ReferencePoint, bool) {
+	switch measure {
+	case MeasureWeight:
+		return m.Weight, true
+	case MeasureHeight:
+		return m.Height, true
+	}
+	return ReferencePoint{}, false
+}
+
+func validateCurve(curve []ReferencePoint) error {
+	if len(curve) < 2 {
+		return fmt.Errorf("needs at least two ages")
+	}
+	for i, point := range curve {
+		if i > 0 && point.AgeDays <= curve[i-1].AgeDays {
+			return fmt.Errorf("ages must increase, got %d after %d", point.AgeDays, curve[i-1].AgeDays)
+		}
+		bands := point.bands()
+		if bands[0] <= 0 {
+			return fmt.Errorf("bands must be positive at day %d", point.AgeDays)
+		}
+		for j := 1; j < len(bands); j++ {
+			if bands[j] < bands[j-1] {
+				return fmt.Errorf("bands must not decrease at day %d", point.AgeDays)
+			}
+		}
+	}
+	return nil
+}
+
+func (c *ReferenceCurves) validate() error {
+	defaults := 0
+	names := map[string]string{}
+	for _, reference := range c.References {
+		if reference.Default {
+			defaults++
+		}
+		for _, name := range append([]string{reference.Breed}, reference.Aliases...) {
+			key := strings.ToLower(strings.TrimSpace(name))
+			if other, ok := names[key]; ok {
+				return fmt.Errorf("%w: %q is claimed by %s and %s", ErrInvalidReference, name, other, reference.Breed)
+			}
+			names[key] = reference.Breed
+		}
+	}
+	if defaults != 1 {
+		return fmt.Errorf("%w: need exactly one default reference, got %d", ErrInvalidReference, defaults)
+	}
+	return nil
+}
+
+// ForBreed finds the reference for a breed by name or alias, then by a
+// name or alias the breed contains ("Welsh Section B" takes the Welsh
+// reference), and falls back to the default reference
+func (c *ReferenceCurves) ForBreed(breed string) *Reference {
+	breed = strings.ToLower(strings.TrimSpace(breed))
+	var partial, fallback *Reference
+	longest := 0
+	for i := range c.References {
+		reference := &c.References[i]
+		if reference.Default {
+			fallback = reference
+		}
+		if breed == "" {
+			continue
+		}
+		for _, name := range append([]string{reference.Breed}, reference.Aliases...) {
+			name = strings.ToLower(name)
+			if name == breed {
+				return reference
+			}
+			if strings.Contains(breed, name) && len(name) > longest {
+				partial, longest = reference, len(name)
+			}
+		}
+	}
+	if partial != nil {
+		return partial
+	}
+	return fallback
+}
+
+// Percentile places a value within the bands. Between bands the normal
+// score is interpolated; beyond the outer bands it is extrapolated from the
+// spread of the outer two. The result is clamped to 0.1-99.9.
+func Percentile(point ReferencePoint, value float64) float64 {
+	return percentileOf(ZScore(point, value))
+}
+
+// ZScore is the normal score of a value within the bands
+func ZScore(point ReferencePoint, value float64) float64 {
+	bands := point.bands()
+
+	switch {
+	case value <= bands[0]:
+		return bandZ[0] - extrapolate(bands[0]-value, bands[1]-bands[0], bandZ[1]-bandZ[0])
+	case value >= bands[4]:
+		return bandZ[4] + extrapolate(value-bands[4], bands[4]-bands[3], bandZ[4]-bandZ[3])
+	}
+	for i := 1; i < len(bands); i++ {
+		if value > bands[i] {
+			continue
+		}
+		width := bands[i] - bands[i-1]
+		if width == 0 {
+			return bandZ[i]
+		}
+		return bandZ[i-1] + (value-bands[i-1])/width*(bandZ[i]-bandZ[i-1])
+	}
+	return bandZ[4]
+}
+
+// ValueAt is the inverse of ZScore: the value at a normal score, read
+// between the bands and extrapolated beyond the outer two
+func ValueAt(point ReferencePoint, z float64) float64 {
+	bands := point.bands()
+
+	switch {
+	case z <= bandZ[0]:
+		return bands[0] - (bandZ[0]-z)/(bandZ[1]-bandZ[0])*(bands[1]-bands[0])
+	case z >= bandZ[4]:
+		return bands[4] + (z-bandZ[4])/(bandZ[4]-bandZ[3])*(bands[4]-bands[3])
+	}
+	i := 1
+	for z > bandZ[i] {
+		i++
+	}
+	return bands[i-1] + (z-bandZ[i-1])/(bandZ[i]-bandZ[i-1])*(bands[i]-bands[i-1])
+}
+
+func percentileOf(z float64) float64 {
+	percentile := 50 * (1 + math.Erf(z/math.Sqrt2))
+	return math.Max(0.1, math.Min(99.9, math.Round(percentile*10)/10))
+}
+
+func extrapolate(distance, bandWidth, zWidth float64) float64 {
+	if bandWidth <= 0 {
+		return 0
+	}
+	return distance / bandWidth * zWidth
+}
+
+// AgeInDays counts whole days from birth to the measurement
+func AgeInDays(birth, on time.Time) int {
+	return int(on.Sub(birth).Hours() / 24)
+}
+
+// Place fills in the expected (median) values and the percentile of each
+// measurement taken. Measurements outside the reference ages are left
+// without a percentile.
+func Place(reference *Reference, data *models.GrowthData) {
+	if reference == nil {
+		return
+	}
+	place := func(measure Measure, value float64, expected *float64, percentile **float64) {
+		point, ok := reference.At(measure, data.AgeDays)
+		if !ok {
+			return
+		}
+		*expected = math.Round(point.P50*10) / 10
+		if value > 0 {
+			p := Percentile(point, value)
+			*percentile = &p
+		}
+	}
+	place(MeasureWeight, data.Weight, &data.ExpectedWeight, &data.WeightPercentile)
+	place(MeasureHeight, data.Height, &data.ExpectedHeight, &data.HeightPercentile)
+	place(MeasureCannon, data.CannonCircumference, &data.ExpectedCannonCircumference, &data.CannonPercentile)
+}
@@ -0,0 +1,154 @@
+package growth
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/polyfant/hulta_pregnancy_app/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testReference = `
+breed: Test
+default: true
+weight:
+  - {age_days: 0, p5: 40, p25: 45, p50: 50, p75: 55, p95: 60}
+  - {age_days: 100, p5: 140, p25: 145, p50: 150, p75: 155, p95: 160}
+height:
+  - {age_days: 0, p5: 95, p25: 98, p50: 100, p75: 102, p95: 105}
+  - {age_days: 100, p5: 115, p25: 118, p50: 120, p75: 122, p95: 125}
+cannon:
+  - {age_days: 0, p5: 11, p25: 12, p50: 12.5, p75: 13, p95: 14}
+  - {age_days: 100, p5: 15, p25: 16, p50: 16.5, p75: 17, p95: 18}
+`
+
+func TestLoadReferenceCurves(t *testing.T) {
+	t.Run("shipped references are valid", func(t *testing.T) {
+		curves, err := LoadReferenceCurves("../../../config/growth")
+		require.NoError(t, err)
+
+		for _, breed := range []string{"Thoroughbred", "Warmblood", "Arabian", "Pony"} {
+			assert.Equal(t, breed, curves.ForBreed(breed).Breed)
+			assert.NotNil(t, curves.ForBreed(breed).Mature)
+		}
+		assert.True(t, curves.ForBreed("Appaloosa").Default, "unknown breeds take the default reference")
+	})
+
+	tests := []struct {
+		name string
+		file string
+	}{
+		{"no breed", "weight: []\n"},
+		{"single age", "breed: X\nweight:\n  - {age_days: 0, p5: 1, p25: 2, p50: 3, p75: 4, p95: 5}\n"},
+		{"ages out of order", "breed: X\nweight:\n  - {age_days: 30, p5: 1, p25: 2, p50: 3, p75: 4, p95: 5}\n  - {age_days: 0, p5: 1, p25: 2, p50: 3, p75: 4, p95: 5}\n"},
+		{"bands decrease", "breed: X\nweight:\n  - {age_days: 0, p5: 1, p25: 2, p50: 3, p75: 4, p95: 5}\n  - {age_days: 30, p5: 1, p25: 2, p50: 9, p75: 4, p95: 5}\n"},
+		{"not yaml", "breed: [\n"},
+		{"gain limit below median", "breed: X\nmax_gain_ratio: 0.9\nweight:\n  - {age_days: 0, p5: 1, p25: 2, p50: 3, p75: 4, p95: 5}\n  - {age_days: 30, p5: 1, p25: 2, p50: 3, p75: 4, p95: 5}\n"},
+		{"mature before the curve ends", "breed: X\nweight:\n  - {age_days: 0, p5: 1, p25: 2, p50: 3, p75: 4, p95: 5}\n  - {age_days: 30, p5: 1, p25: 2, p50: 3, p75: 4, p95: 5}\nheight:\n  - {age_days: 0, p5: 1, p25: 2, p50: 3, p75: 4, p95: 5}\n  - {age_days: 30, p5: 1, p25: 2, p50: 3, p75: 4, p95: 5}\ncannon:\n  - {age_days: 0, p5: 1, p25: 2, p50: 3, p75: 4, p95: 5}\n  - {age_days: 30, p5: 1, p25: 2, p50: 3, p75: 4, p95: 5}\nmature:\n  age_days: 20\n  weight: {p5: 1, p25: 2, p50: 3, p75: 4, p95: 5}\n  height: {p5: 1, p25: 2, p50: 3, p75: 4, p95: 5}\n"},
+		{"mature smaller than the curve", testReference + "mature:\n  age_days: 400\n  weight: {p5: 90, p25: 95, p50: 100, p75: 105, p95: 110}\n  height: {p5: 140, p25: 145, p50: 150, p75: 155, p95: 160}\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			require.NoError(t, os.WriteFile(filepath.Join(dir, "bad.yaml"), []byte(tt.file), 0644))
+
+			_, err := LoadReferenceCurves(dir)
+			assert.ErrorIs(t, err, ErrInvalidReference)
+		})
+	}
+
+	t.Run("needs one default", func(t *testing.T) {
+		dir := t.TempDir()
+		for _, name := range []string{"a.yaml", "b.yaml"} {
+			require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(testReference), 0644))
+		}
+		_, err := LoadReferenceCurves(dir)
+		assert.ErrorIs(t, err, ErrInvalidReference)
+	})
+}
+
+func TestReferenceCurves_ForBreed(t *testing.T) {
+	curves := &ReferenceCurves{References: []Reference{
+		{Breed: "Light horse", Default: true},
+		{Breed: "Pony", Aliases: []string{"Welsh", "Shetland"}},
+		{Breed: "Welsh Cob"},
+	}}
+
+	tests := []struct {
+		breed string
+		want  string
+	}{
+		{"pony", "Pony"},
+		{"Shetland", "Pony"},
+		{"Welsh Section B", "Pony"},
+		{"Welsh Cob", "Welsh Cob"},
+		{"Welsh Cob Section D", "Welsh Cob"},
+		{"Quarter Horse", "Light horse"},
+		{"", "Light horse"},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, curves.ForBreed(tt.breed).Breed, tt.breed)
+	}
+}
+
+func TestReference_At(t *testing.T) {
+	reference, err := ParseReference([]byte(testReference))
+	require.NoError(t, err)
+
+	point, ok := reference.At(MeasureWeight, 25)
+	require.True(t, ok)
+	assert.Equal(t, 75.0, point.P50)
+	assert.Equal(t, 65.0, point.P5)
+
+	_, ok = reference.At(MeasureWeight, 101)
+	assert.False(t, ok, "no reference past the end of the curve")
+}
+
+func TestPercentile(t *testing.T) {
+	point := ReferencePoint{P5: 40, P25: 45, P50: 50, P75: 55, P95: 60}
+
+	tests := []struct {
+		name  string
+		value float64
+		want  float64
+	}{
+		{name: "median", value: 50, want: 50},
+		{name: "on a band", value: 55, want: 75},
+		{name: "between bands", value: 52.5, want: 63.3},
+		{name: "outer band", value: 40, want: 5},
+		{name: "below the bands", value: 35, want: 0.5},
+		{name: "far above the bands", value: 200, want: 99.9},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.InDelta(t, tt.want, Percentile(point, tt.value), 0.1)
+		})
+	}
+}
+
+func TestPlace(t *testing.T) {
+	reference, err := ParseReference([]byte(testReference))
+	require.NoError(t, err)
+
+	data := models.GrowthData{AgeDays: 50, Weight: 100, CannonCircumference: 16}
+	Place(reference, &data)
+
+	assert.Equal(t, 100.0, data.ExpectedWeight)
+	assert.Equal(t, 110.0, data.ExpectedHeight)
+	assert.Equal(t, 14.5, data.ExpectedCannonCircumference)
+	require.NotNil(t, data.WeightPercentile)
+	assert.Equal(t, 50.0, *data.WeightPercentile)
+	assert.Nil(t, data.HeightPercentile, "height was not measured")
+	require.NotNil(t, data.CannonPercentile)
+	assert.Greater(t, *data.CannonPercentile, 90.0)
+
+	adult := models.GrowthData{AgeDays: 400, Weight: 450}
+	Place(reference, &adult)
+	assert.Nil(t, adult.WeightPercentile)
+	assert.Zero(t, adult.ExpectedWeight)
+}
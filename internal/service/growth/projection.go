@@ -0,0 +1,218 @@
+package growth
+
+import (
+	"math"
+	"sort"
+
+	"github.com/polyfant/hulta_pregnancy_app/internal/models"
+)
+
+const (
+	// trackingWindowDays is how far back from the latest measurement the
+	// tracked percentile is averaged over
+	trackingWindowDays = 90
+	// birthTrackingSD and curveEndTrackingSD are how far, as a normal score,
+	// a foal's percentile can still drift before maturity when measured at
+	// birth and at the end of the curve
+	birthTrackingSD    = 0.9
+	curveEndTrackingSD = 0.3
+	// bandZ90 is the normal score bounding a central 90% band
+	bandZ90 = 1.645
+	// minCurveFitSpanDays is the span of measurements needed before the
+	// rate of maturing is fitted to the horse rather than taken from the breed
+	minCurveFitSpanDays = 60
+)
+
+type observation struct {
+	ageDays int
+	value   float64
+	z       float64
+}
+
+// ProjectMature projects mature weight and height from the measurements
+// taken within the reference ages. It returns nil when the reference has no
+// mature size or nothing was measured within its ages.
+func ProjectMature(reference *Reference, data []models.GrowthData) *models.MatureProjection {
+	if reference == nil || reference.Mature == nil {
+		return nil
+	}
+
+	projection := &models.MatureProjection{
+		MatureAgeDays: reference.Mature.AgeDays,
+		Weight:        projectMature(reference, MeasureWeight, data, func(d models.GrowthData) float64 { return d.Weight }),
+		Height:        projectMature(reference, MeasureHeight, data, func(d models.GrowthData) float64 { return d.Height }),
+	}
+	if projection.Weight == nil && projection.Height == nil {
+		return nil
+	}
+	return projection
+}
+
+// projectMature carries the percentile the horse has tracked recently over
+// to the mature bands. The band widens with the drift still to come and
+// with how much the recent measurements disagree.
+func projectMature(reference *Reference, measure Measure, data []models.GrowthData, value func(models.GrowthData) float64) *models.MatureEstimate {
+	mature, _ := reference.Mature.At(measure)
+
+	var observations []observation
+	for _, d := range data {
+		v := value(d)
+		if v <= 0 {
+			continue
+		}
+		point, ok := reference.At(measure, d.AgeDays)
+		if !ok {
+			continue
+		}
+		observations = append(observations, observation{ageDays: d.AgeDays, value: v, z: ZScore(point, v)})
+	}
+	if len(observations) == 0 {
+		return nil
+	}
+	sort.SliceStable(observations, func(i, j int) bool { return observations[i].ageDays < observations[j].ageDays })
+
+	latest := observations[len(observations)-1].ageDays
+	var recent []float64
+	for _, o := range observations {
+		if latest-o.ageDays <= trackingWindowDays {
+			recent = append(recent, o.z)
+		}
+	}
+	z, variance := meanAndVariance(recent)
+
+	curve := reference.Curve(measure)
+	drift := birthTrackingSD + (curveEndTrackingSD-birthTrackingSD)*float64(latest)/float64(curve[len(curve)-1].AgeDays)
+	sd := math.Sqrt(drift*drift + variance/float64(len(recent)))
+
+	expected := ValueAt(mature, z)
+	lower := ValueAt(mature, z-bandZ90*sd)
+	upper := ValueAt(mature, z+bandZ90*sd)
+	estimate := &models.MatureEstimate{Percentile: percentileOf(z)}
+
+	if asymptote, ok := fitBrody(reference, measure, observations); ok {
+		fitted := round1(asymptote)
+		estimate.Fitted = &fitted
+		expected = (expected + asymptote) / 2
+		lower = math.Min(lower, asymptote)
+		upper = math.Max(upper, asymptote)
+	}
+
+	estimate.Expected = round1(expected)
+	estimate.Lower = round1(lower)
+	estimate.Upper = round1(upper)
+	return estimate
+}
+
+// brodyShape fits the Brody curve W(t) = A·(1 - b·exp(-k·t)) to the breed
+// median, with A the mature median, by regressing ln(1 - W/A) on age. Foals
+// grow fastest at birth, which the Brody curve follows more closely than
+// sigmoid curves such as the Gompertz.
+func brodyShape(reference *Reference, measure Measure) (b, k float64, ok bool) {
+	mature, _ := reference.Mature.At(measure)
+
+	var ages, ys []float64
+	for _, point := range reference.Curve(measure) {
+		if point.P50 >= mature.P50 {
+			continue
+		}
+		ages = append(ages, float64(point.AgeDays))
+		ys = append(ys, math.Log(1-point.P50/mature.P50))
+	}
+	if len(ages) < 2 {
+		return 0, 0, false
+	}
+
+	slope, intercept := linearFit(ages, ys)
+	if slope >= 0 {
+		return 0, 0, false
+	}
+	return math.Exp(intercept), -slope, true
+}
+
+// fitBrody fits the asymptote of a Brody curve to the horse's own
+// measurements. The curve keeps the breed's shape; with measurements over
+// minCurveFitSpanDays or more its rate of maturing is also fitted, within
+// half to double the breed's.
+func fitBrody(reference *Reference, measure Measure, observations []observation) (float64, bool) {
+	if len(observations) < 2 {
+		return 0, false
+	}
+	b, k, ok := brodyShape(reference, measure)
+	if !ok {
+		return 0, false
+	}
+
+	rates := []float64{k}
+	span := observations[len(observations)-1].ageDays - observations[0].ageDays
+	if len(observations) >= 3 && span >= minCurveFitSpanDays {
+		rates = rates[:0]
+		for i := -20; i <= 20; i++ {
+			rates = append(rates, k*math.Pow(2, float64(i)/20))
+		}
+	}
+
+	best, bestErr := 0.0, math.Inf(1)
+	for _, rate := range rates {
+		asymptote, sse := fitAsymptote(b, rate, observations)
+		if sse < bestErr {
+			best, bestErr = asymptote, sse
+		}
+	}
+	return best, best > 0
+}
+
+// fitAsymptote is the least-squares asymptote for a fixed curve shape and
+// its sum of squared errors
+func fitAsymptote(b, k float64, observations []observation) (float64, float64) {
+	var gy, gg float64
+	shape := make([]float64, len(observations))
+	for i, o := range observations {
+		shape[i] = 1 - b*math.Exp(-k*float64(o.ageDays))
+		gy += shape[i] * o.value
+		gg += shape[i] * shape[i]
+	}
+	asymptote := gy / gg
+
+	var sse float64
+	for i, o := range observations {
+		residual := o.value - asymptote*shape[i]
+		sse += residual * residual
+	}
+	return asymptote, sse
+}
+
+func linearFit(xs, ys []float64) (slope, intercept float64) {
+	meanX, _ := meanAndVariance(xs)
+	meanY, _ := meanAndVariance(ys)
+	var sxy, sxx float64
+	for i := range xs {
+		sxy += (xs[i] - meanX) * (ys[i] - meanY)
+		sxx += (xs[i] - meanX) * (xs[i] - meanX)
+	}
+	if sxx == 0 {
+		return 0, meanY
+	}
+	slope = sxy / sxx
+	return slope, meanY - slope*meanX
+}
+
+// meanAndVariance returns the mean and sample variance
+func meanAndVariance(values []float64) (float64, float64) {
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	mean := sum / float64(len(values))
+	if len(values) < 2 {
+		return mean, 0
+	}
+	var squares float64
+	for _, v := range values {
+		squares += (v - mean) * (v - mean)
+	}
+	return mean, squares / float64(len(values)-1)
+}
+
+func round1(v float64) float64 {
+	return math.Round(v*10) / 10
+}
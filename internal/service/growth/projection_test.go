@@ -0,0 +1,85 @@
+package growth
+
+import (
+	"testing"
+
+	"github.com/polyfant/hulta_pregnancy_app/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testMature = `
+mature:
+  age_days: 400
+  weight: {p5: 352, p25: 380, p50: 400, p75: 420, p95: 448}
+  height: {p5: 142, p25: 147, p50: 150, p75: 153, p95: 158}
+`
+
+func TestValueAt(t *testing.T) {
+	point := ReferencePoint{P5: 90, P25: 95, P50: 100, P75: 105, P95: 110}
+
+	for _, value := range []float64{80, 90, 93, 100, 108, 110, 120} {
+		assert.InDelta(t, value, ValueAt(point, ZScore(point, value)), 1e-9)
+	}
+	assert.Equal(t, 100.0, ValueAt(point, 0))
+}
+
+func TestProjectMature(t *testing.T) {
+	reference, err := ParseReference([]byte(testReference + testMature))
+	require.NoError(t, err)
+
+	t.Run("median foal tracks the mature median", func(t *testing.T) {
+		projection := ProjectMature(reference, []models.GrowthData{{AgeDays: 50, Weight: 100, Height: 110}})
+		require.NotNil(t, projection)
+		assert.Equal(t, 400, projection.MatureAgeDays)
+
+		require.NotNil(t, projection.Weight)
+		assert.Equal(t, 400.0, projection.Weight.Expected)
+		assert.Equal(t, 50.0, projection.Weight.Percentile)
+		assert.Less(t, projection.Weight.Lower, 400.0)
+		assert.Greater(t, projection.Weight.Upper, 400.0)
+		assert.Nil(t, projection.Weight.Fitted, "one measurement is not enough to fit a curve")
+
+		require.NotNil(t, projection.Height)
+		assert.Equal(t, 150.0, projection.Height.Expected)
+	})
+
+	t.Run("large foal projects large", func(t *testing.T) {
+		projection := ProjectMature(reference, []models.GrowthData{{AgeDays: 50, Weight: 110}})
+		require.NotNil(t, projection)
+		assert.Equal(t, 448.0, projection.Weight.Expected)
+		assert.Equal(t, 95.0, projection.Weight.Percentile)
+		assert.Nil(t, projection.Height, "height was not measured")
+	})
+
+	t.Run("band narrows with age", func(t *testing.T) {
+		young := ProjectMature(reference, []models.GrowthData{{AgeDays: 10, Weight: 60}})
+		old := ProjectMature(reference, []models.GrowthData{{AgeDays: 90, Weight: 140}})
+		require.NotNil(t, young)
+		require.NotNil(t, old)
+		assert.Less(t, old.Weight.Upper-old.Weight.Lower, young.Weight.Upper-young.Weight.Lower)
+	})
+
+	t.Run("curve fitted to repeated measurements", func(t *testing.T) {
+		projection := ProjectMature(reference, []models.GrowthData{
+			{AgeDays: 10, Weight: 60},
+			{AgeDays: 40, Weight: 90},
+			{AgeDays: 80, Weight: 130},
+		})
+		require.NotNil(t, projection)
+		require.NotNil(t, projection.Weight.Fitted)
+		assert.InDelta(t, 400, *projection.Weight.Fitted, 40)
+		assert.LessOrEqual(t, projection.Weight.Lower, *projection.Weight.Fitted)
+		assert.GreaterOrEqual(t, projection.Weight.Upper, *projection.Weight.Fitted)
+	})
+
+	t.Run("nothing within the reference ages", func(t *testing.T) {
+		assert.Nil(t, ProjectMature(reference, []models.GrowthData{{AgeDays: 200, Weight: 300}}))
+	})
+
+	t.Run("reference without a mature size", func(t *testing.T) {
+		withoutMature, err := ParseReference([]byte(testReference))
+		require.NoError(t, err)
+		assert.Nil(t, ProjectMature(withoutMature, []models.GrowthData{{AgeDays: 50, Weight: 100}}))
+	})
+}
@@ -0,0 +1,160 @@
+package growth
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/polyfant/hulta_pregnancy_app/internal/models"
+)
+
+const (
+	// minIntervalDays is the shortest span a daily gain is read from; over
+	// a few days scale and tape error swamp the gain
+	minIntervalDays = 7
+	// DefaultMaxGainRatio is how far above the breed median's daily gain a
+	// foal may grow before it counts as a spurt
+	DefaultMaxGainRatio = 1.4
+	// rapidGainRatio and slowGainRatio bound normal growth
+	rapidGainRatio = 1.2
+	slowGainRatio  = 0.6
+)
+
+// MaxGainRatio is the reference's spurt limit, or the default when the
+// reference does not set one
+func (r *Reference) MaxGainRatio() float64 {
+	if r.GainLimit > 0 {
+		return r.GainLimit
+	}
+	return DefaultMaxGainRatio
+}
+
+// ExpectedDailyGain is the daily gain of the median weight between two ages
+func (r *Reference) ExpectedDailyGain(fromAgeDays, toAgeDays int) (float64, bool) {
+	from, ok := r.At(MeasureWeight, fromAgeDays)
+	if !ok || toAgeDays <= fromAgeDays {
+		return 0, false
+	}
+	to, ok := r.At(MeasureWeight, toAgeDays)
+	if !ok {
+		return 0, false
+	}
+	return (to.P50 - from.P50) / float64(toAgeDays-fromAgeDays), true
+}
+
+// Velocity works out the average daily gain up to each weighing, from the
+// latest weighing at least minIntervalDays before it, and classifies it
+// against the breed reference
+func Velocity(reference *Reference, data []models.GrowthData) models.GrowthVelocity {
+	weighings := make([]models.GrowthData, 0, len(data))
+	for _, d := range data {
+		if d.Weight > 0 {
+			weighings = append(weighings, d)
+		}
+	}
+	sort.SliceStable(weighings, func(i, j int) bool { return weighings[i].AgeDays < weighings[j].AgeDays })
+
+	velocity := models.GrowthVelocity{
+		Intervals:   []models.GrowthInterval{},
+		Status:      models.GrowthVelocityUnknown,
+		Suggestions: []string{},
+	}
+	if len(weighings) > 0 {
+		velocity.FoalID = weighings[0].FoalID
+	}
+
+	for i, to := range weighings {
+		from := -1
+		for j := i - 1; j >= 0; j-- {
+			if to.AgeDays-weighings[j].AgeDays >= minIntervalDays {
+				from = j
+				break
+			}
+		}
+		if from < 0 {
+			continue
+		}
+		velocity.Intervals = append(velocity.Intervals, interval(reference, weighings[from], to))
+	}
+
+	if n := len(velocity.Intervals); n > 0 {
+		current := velocity.Intervals[n-1]
+		velocity.Current = &current
+		velocity.Status = current.Status
+		velocity.Suggestions = Suggestions(current, followsSlowGrowth(velocity.Intervals))
+	}
+	return velocity
+}
+
+func interval(reference *Reference, from, to models.GrowthData) models.GrowthInterval {
+	days := float64(to.AgeDays - from.AgeDays)
+	result := models.GrowthInterval{
+		FromDate:         from.MeasurementDate,
+		ToDate:           to.MeasurementDate,
+		FromAgeDays:      from.AgeDays,
+		ToAgeDays:        to.AgeDays,
+		AverageDailyGain: math.Round((to.Weight-from.Weight)/days*100) / 100,
+		Status:           models.GrowthVelocityUnknown,
+	}
+	if reference == nil {
+		return result
+	}
+
+	expected, ok := reference.ExpectedDailyGain(from.AgeDays, to.AgeDays)
+	if !ok || expected <= 0 {
+		return result
+	}
+	result.ExpectedDailyGain = math.Round(expected*100) / 100
+	result.Ratio = math.Round((to.Weight-from.Weight)/days/expected*100) / 100
+
+	switch {
+	case result.Ratio > reference.MaxGainRatio():
+		result.Status = models.GrowthVelocitySpurt
+	case result.Ratio > rapidGainRatio:
+		result.Status = models.GrowthVelocityRapid
+	case result.Ratio < slowGainRatio:
+		result.Status = models.GrowthVelocitySlow
+	default:
+		result.Status = models.GrowthVelocityNormal
+	}
+	return result
+}
+
+// followsSlowGrowth reports whether the latest interval comes straight
+// after slow growth: compensatory growth is itself a DOD risk
+func followsSlowGrowth(intervals []models.GrowthInterval) bool {
+	n := len(intervals)
+	return n >= 2 && intervals[n-2].Status == models.GrowthVelocitySlow
+}
+
+// Suggestions are the feed and management changes for a growth interval
+func Suggestions(current models.GrowthInterval, afterSlowGrowth bool) []string {
+	var suggestions []string
+	switch current.Status {
+	case models.GrowthVelocitySpurt:
+		suggestions = []string{
+			fmt.Sprintf("Growth spurt: gaining %.2f kg/day against %.2f kg/day for the breed at this age. Rapid growth is a risk factor for OCD and other developmental orthopaedic disease.", current.AverageDailyGain, current.ExpectedDailyGain),
+			"Cut back concentrates rather than forage; energy drives the spurt",
+			"Feed a ration balancer so protein, calcium, phosphorus, copper and zinc stay balanced on less feed",
+			"Keep daily turnout for free exercise and avoid forced exercise",
+			"Ask your vet to check the joints for heat, swelling or upright pasterns",
+		}
+	case models.GrowthVelocityRapid:
+		suggestions = []string{
+			fmt.Sprintf("Growing faster than the breed median (%.2f kg/day against %.2f kg/day)", current.AverageDailyGain, current.ExpectedDailyGain),
+			"Hold concentrates at the current level and weigh again in two weeks",
+		}
+	case models.GrowthVelocitySlow:
+		suggestions = []string{
+			fmt.Sprintf("Growing slower than the breed median (%.2f kg/day against %.2f kg/day)", current.AverageDailyGain, current.ExpectedDailyGain),
+			"Check the mare's milk supply or creep feed intake, teeth and worm burden",
+			"Raise the ration gradually; a sudden increase can trigger a compensatory growth spurt",
+		}
+	default:
+		return []string{}
+	}
+	if afterSlowGrowth && current.Status != models.GrowthVelocitySlow {
+		suggestions = append(suggestions, "This follows a period of slow growth. Compensatory growth carries a higher DOD risk, so increase feed gradually.")
+	}
+	return suggestions
+}
@@ -0,0 +1,143 @@
+package growth
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/polyfant/hulta_pregnancy_app/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVelocity(t *testing.T) {
+	reference, err := ParseReference([]byte(testReference))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name            string
+		gainLimit       float64
+		data            []models.GrowthData
+		wantIntervals   int
+		wantStatus      models.GrowthVelocityStatus
+		wantGain        float64
+		wantCompensated bool
+	}{
+		{
+			name:          "normal growth",
+			data:          []models.GrowthData{{AgeDays: 20, Weight: 70}, {AgeDays: 40, Weight: 91}},
+			wantIntervals: 1,
+			wantStatus:    models.GrowthVelocityNormal,
+			wantGain:      1.05,
+		},
+		{
+			name:          "rapid growth",
+			data:          []models.GrowthData{{AgeDays: 20, Weight: 70}, {AgeDays: 40, Weight: 96}},
+			wantIntervals: 1,
+			wantStatus:    models.GrowthVelocityRapid,
+			wantGain:      1.3,
+		},
+		{
+			name:          "spurt above the default limit",
+			data:          []models.GrowthData{{AgeDays: 20, Weight: 70}, {AgeDays: 40, Weight: 100}},
+			wantIntervals: 1,
+			wantStatus:    models.GrowthVelocitySpurt,
+			wantGain:      1.5,
+		},
+		{
+			name:          "breed limit is stricter than the default",
+			gainLimit:     1.25,
+			data:          []models.GrowthData{{AgeDays: 20, Weight: 70}, {AgeDays: 40, Weight: 96}},
+			wantIntervals: 1,
+			wantStatus:    models.GrowthVelocitySpurt,
+			wantGain:      1.3,
+		},
+		{
+			name:          "slow growth",
+			data:          []models.GrowthData{{AgeDays: 20, Weight: 70}, {AgeDays: 40, Weight: 80}},
+			wantIntervals: 1,
+			wantStatus:    models.GrowthVelocitySlow,
+			wantGain:      0.5,
+		},
+		{
+			name: "catch-up after slow growth",
+			data: []models.GrowthData{
+				{AgeDays: 20, Weight: 70},
+				{AgeDays: 40, Weight: 80},
+				{AgeDays: 60, Weight: 110},
+			},
+			wantIntervals:   2,
+			wantStatus:      models.GrowthVelocitySpurt,
+			wantGain:        1.5,
+			wantCompensated: true,
+		},
+		{
+			name: "weighings a few days apart are read over a longer span",
+			data: []models.GrowthData{
+				{AgeDays: 20, Weight: 70},
+				{AgeDays: 40, Weight: 90},
+				{AgeDays: 43, Weight: 97},
+			},
+			wantIntervals: 2,
+			wantStatus:    models.GrowthVelocityNormal,
+			wantGain:      1.17,
+		},
+		{
+			name: "height-only measurements are ignored",
+			data: []models.GrowthData{
+				{AgeDays: 20, Weight: 70},
+				{AgeDays: 30, Height: 106},
+				{AgeDays: 40, Weight: 90},
+			},
+			wantIntervals: 1,
+			wantStatus:    models.GrowthVelocityNormal,
+			wantGain:      1,
+		},
+		{
+			name:          "beyond the reference",
+			data:          []models.GrowthData{{AgeDays: 90, Weight: 140}, {AgeDays: 120, Weight: 170}},
+			wantIntervals: 1,
+			wantStatus:    models.GrowthVelocityUnknown,
+			wantGain:      1,
+		},
+		{
+			name:       "single weighing",
+			data:       []models.GrowthData{{AgeDays: 20, Weight: 70}},
+			wantStatus: models.GrowthVelocityUnknown,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ref := *reference
+			ref.GainLimit = tt.gainLimit
+
+			velocity := Velocity(&ref, tt.data)
+
+			assert.Len(t, velocity.Intervals, tt.wantIntervals)
+			assert.Equal(t, tt.wantStatus, velocity.Status)
+			if tt.wantIntervals == 0 {
+				assert.Nil(t, velocity.Current)
+				assert.Empty(t, velocity.Suggestions)
+				return
+			}
+			require.NotNil(t, velocity.Current)
+			assert.InDelta(t, tt.wantGain, velocity.Current.AverageDailyGain, 0.005)
+
+			compensated := false
+			for _, suggestion := range velocity.Suggestions {
+				compensated = compensated || strings.HasPrefix(suggestion, "This follows a period of slow growth")
+			}
+			assert.Equal(t, tt.wantCompensated, compensated)
+		})
+	}
+}
+
+func TestSuggestions(t *testing.T) {
+	spurt := Suggestions(models.GrowthInterval{Status: models.GrowthVelocitySpurt, AverageDailyGain: 1.5, ExpectedDailyGain: 1}, false)
+	assert.Contains(t, spurt[0], "1.50 kg/day against 1.00 kg/day")
+	assert.Contains(t, spurt[0], "OCD")
+
+	assert.Empty(t, Suggestions(models.GrowthInterval{Status: models.GrowthVelocityNormal}, true))
+	slow := Suggestions(models.GrowthInterval{Status: models.GrowthVelocitySlow}, true)
+	assert.Len(t, slow, 3, "slow growth after slow growth is not compensatory")
+}
@@ -0,0 +1,253 @@
+package growth
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/polyfant/hulta_pregnancy_app/internal/models"
+)
+
+const (
+	// Foals are weaned from four to eight months, most at about six
+	weaningEarliestDays = 120
+	weaningTargetDays   = 180
+	weaningLatestDays   = 240
+
+	// smallFoalPercentile and largeFoalPercentile are the weight percentiles
+	// that move the recommended date later or earlier
+	smallFoalPercentile = 10
+	largeFoalPercentile = 75
+	smallFoalDelayDays  = 30
+	largeFoalTargetDays = 150
+
+	// damWeanByGestationDays is the day of the dam's new pregnancy the foal
+	// should be weaned by, so she regains condition before the last third
+	// of gestation, when the foetus does most of its growing
+	damWeanByGestationDays = 225
+
+	// WeaningMonitoringDays is how long a foal is watched after weaning
+	WeaningMonitoringDays = 30
+	// weaningSettleDays is how long a weanling may go without gaining
+	// before it is a concern
+	weaningSettleDays = 14
+	// preWeaningWeighingDays is how long before weaning a weighing still
+	// counts as the pre-weaning weight
+	preWeaningWeighingDays = 30
+	// weaningLossPercent is a loss that is a concern however soon it comes
+	weaningLossPercent = 5
+)
+
+// PlanWeaning proposes a weaning window for a foal. The latest weighing, if
+// it falls within the reference ages, places the foal on the breed's weight
+// curve; damGestationDays is how far along the dam's new pregnancy is, or
+// nil if she is not in foal.
+func PlanWeaning(reference *Reference, foal *models.Horse, data []models.GrowthData, damGestationDays *int, now time.Time) models.WeaningPlan {
+	ageDays := AgeInDays(foal.BirthDate, now)
+	earliest, target, latest := weaningEarliestDays, weaningTargetDays, weaningLatestDays
+	plan := models.WeaningPlan{
+		HorseID: foal.ID,
+		AgeDays: ageDays,
+		Reasons: []string{"Foals are usually weaned between four and eight months old, most at about six."},
+	}
+
+	if percentile, ok := latestWeightPercentile(reference, data); ok {
+		plan.WeightPercentile = &percentile
+		switch {
+		case percentile < smallFoalPercentile:
+			target += smallFoalDelayDays
+			plan.Reasons = append(plan.Reasons, fmt.Sprintf("Weight is at the %.0fth percentile for the breed; a small foal does better left on the mare a little longer, with creep feed.", percentile))
+		case percentile > largeFoalPercentile:
+			target = largeFoalTargetDays
+			plan.Reasons = append(plan.Reasons, fmt.Sprintf("Weight is at the %.0fth percentile for the breed; a well-grown foal eating creep feed can be weaned from five months.", percentile))
+		}
+	}
+
+	if damGestationDays != nil {
+		gestation := *damGestationDays
+		plan.DamGestationDays = &gestation
+		plan.DamStage = gestationStage(gestation)
+		weanBy := ageDays + damWeanByGestationDays - gestation
+		if weanBy < latest {
+			latest = max(weanBy, earliest)
+			if weanBy < earliest {
+				plan.Reasons = append(plan.Reasons, "The mare reaches the last third of her pregnancy before the foal is four months old; wean as soon as it is and feed her for the pregnancy.")
+			} else {
+				plan.Reasons = append(plan.Reasons, fmt.Sprintf("The mare is %d days in foal; wean by day %d of her pregnancy so she regains condition before the last third, when the foetus grows fastest.", gestation, damWeanByGestationDays))
+			}
+		}
+	}
+	target = min(target, latest)
+
+	plan.Earliest = foal.BirthDate.AddDate(0, 0, earliest)
+	plan.Recommended = foal.BirthDate.AddDate(0, 0, target)
+	plan.Latest = foal.BirthDate.AddDate(0, 0, latest)
+	switch {
+	case ageDays < earliest:
+		plan.Status = models.WeaningTooYoung
+	case ageDays > latest:
+		plan.Status = models.WeaningOverdue
+	default:
+		plan.Status = models.WeaningReady
+	}
+	if plan.Status != models.WeaningTooYoung && plan.Recommended.Before(now) {
+		plan.Recommended = now
+	}
+	return plan
+}
+
+// latestWeightPercentile places the latest weighing on the breed's weight curve
+func latestWeightPercentile(reference *Reference, data []models.GrowthData) (float64, bool) {
+	if reference == nil {
+		return 0, false
+	}
+	var latest *models.GrowthData
+	for i := range data {
+		if data[i].Weight > 0 && (latest == nil || data[i].AgeDays > latest.AgeDays) {
+			latest = &data[i]
+		}
+	}
+	if latest == nil {
+		return 0, false
+	}
+	point, ok := reference.At(MeasureWeight, latest.AgeDays)
+	if !ok {
+		return 0, false
+	}
+	return Percentile(point, latest.Weight), true
+}
+
+// gestationStage uses the same boundaries as Pregnancy.GetStageInfo
+func gestationStage(days int) models.PregnancyStage {
+	switch {
+	case days <= 120:
+		return models.PregnancyStageEarly
+	case days <= 270:
+		return models.PregnancyStageMid
+	case days <= 340:
+		return models.PregnancyStageLate
+	}
+	return models.PregnancyStageOverdue
+}
+
+// MonitorWeaning follows a foal's weight and stress through the
+// WeaningMonitoringDays after weaning. Weight is compared with the last
+// weighing in the month before weaning; stress alerts come from the latest
+// check in the period.
+func MonitorWeaning(reference *Reference, record *models.WeaningRecord, data []models.GrowthData, checks []models.WeaningCheck, now time.Time) models.WeaningProgress {
+	end := record.Date.AddDate(0, 0, WeaningMonitoringDays)
+	progress := models.WeaningProgress{
+		HorseID:          record.HorseID,
+		Record:           record,
+		DaysSinceWeaning: AgeInDays(record.Date, now),
+		MonitoringEnds:   end,
+		Monitoring:       now.Before(end),
+		Measurements:     []models.GrowthData{},
+		Checks:           []models.WeaningCheck{},
+		Alerts:           []string{},
+	}
+
+	sorted := append([]models.GrowthData(nil), data...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].MeasurementDate.Before(sorted[j].MeasurementDate) })
+	var before, latest *models.GrowthData
+	for i := range sorted {
+		d := &sorted[i]
+		switch {
+		case !d.MeasurementDate.After(record.Date):
+			if d.Weight > 0 && record.Date.Sub(d.MeasurementDate) <= preWeaningWeighingDays*24*time.Hour {
+				before = d
+			}
+		case d.MeasurementDate.Before(end):
+			progress.Measurements = append(progress.Measurements, *d)
+			if d.Weight > 0 {
+				latest = d
+			}
+		}
+	}
+	if before != nil {
+		progress.Measurements = append([]models.GrowthData{*before}, progress.Measurements...)
+		progress.PreWeaningWeight = &before.Weight
+	}
+	if latest != nil {
+		progress.LatestWeight = &latest.Weight
+	}
+
+	for _, check := range checks {
+		if !check.CheckedAt.Before(record.Date) && check.CheckedAt.Before(end) {
+			progress.Checks = append(progress.Checks, check)
+		}
+	}
+	sort.SliceStable(progress.Checks, func(i, j int) bool { return progress.Checks[i].CheckedAt.Before(progress.Checks[j].CheckedAt) })
+
+	if before != nil && latest != nil {
+		progress.Alerts = append(progress.Alerts, weaningWeightAlerts(reference, record, before, latest, &progress)...)
+	} else if latest == nil && progress.Monitoring && progress.DaysSinceWeaning >= weaningSettleDays {
+		progress.Alerts = append(progress.Alerts, "No weight since weaning; weigh the foal to check it is eating enough without the mare.")
+	}
+	if n := len(progress.Checks); n > 0 {
+		progress.Alerts = append(progress.Alerts, weaningStressAlerts(record, progress.Checks[n-1])...)
+	}
+	return progress
+}
+
+// weaningWeightAlerts fills in the weight change since weaning and flags a
+// foal that has lost weight or, once settled, is gaining too slowly
+func weaningWeightAlerts(reference *Reference, record *models.WeaningRecord, before, latest *models.GrowthData, progress *models.WeaningProgress) []string {
+	change := round1(latest.Weight - before.Weight)
+	progress.WeightChangeKg = &change
+	days := latest.AgeDays - before.AgeDays
+	if days < 1 {
+		return nil
+	}
+	gain := math.Round(change/float64(days)*100) / 100
+	progress.DailyGainKg = &gain
+	expected, ok := 0.0, false
+	if reference != nil {
+		expected, ok = reference.ExpectedDailyGain(before.AgeDays, latest.AgeDays)
+	}
+	if ok {
+		expected = math.Round(expected*100) / 100
+		progress.ExpectedDailyGain = &expected
+	}
+
+	settled := latest.MeasurementDate.Sub(record.Date) >= weaningSettleDays*24*time.Hour
+	if change < 0 {
+		loss := -change / before.Weight * 100
+		if settled || loss >= weaningLossPercent {
+			return []string{fmt.Sprintf("Lost %.1f kg (%.1f%%) since weaning. Check it is eating its hard feed and forage and is not being pushed off the feed by other weanlings; call your vet if it keeps losing.", -change, loss)}
+		}
+		return nil
+	}
+	if settled && ok && gain < slowGainRatio*expected {
+		return []string{fmt.Sprintf("Gaining %.2f kg a day since weaning against %.2f expected for the breed. Check its ration and worm count.", gain, expected)}
+	}
+	return nil
+}
+
+// weaningStressAlerts flags the signs in the latest check that need action
+func weaningStressAlerts(record *models.WeaningRecord, check models.WeaningCheck) []string {
+	since := check.CheckedAt.Sub(record.Date)
+	var alerts []string
+	distressed := false
+	for _, sign := range check.Signs {
+		switch sign {
+		case models.WeaningNotEating:
+			if since >= 48*time.Hour {
+				alerts = append(alerts, "Still not eating two days after weaning. Call your vet; a weanling off its feed loses condition quickly and may develop ulcers.")
+			}
+		case models.WeaningInjury:
+			alerts = append(alerts, "Injured since weaning. Check the fencing and call your vet for any deep wound or one near a joint.")
+		case models.WeaningDiarrhea:
+			alerts = append(alerts, "Diarrhea after weaning. Call your vet; weanlings dehydrate quickly.")
+		case models.WeaningCough:
+			alerts = append(alerts, "Cough or nasal discharge after weaning. Take its temperature and call your vet if it has a fever; respiratory infections often follow weaning stress.")
+		case models.WeaningVocalizing, models.WeaningPacing, models.WeaningFenceRunning:
+			distressed = true
+		}
+	}
+	if distressed && since >= 7*24*time.Hour {
+		alerts = append(alerts, "Still calling or pacing a week after weaning. A quiet older companion often settles a weanling.")
+	}
+	return alerts
+}
@@ -0,0 +1,168 @@
+package growth
+
+import (
+	"testing"
+	"time"
+
+	"github.com/polyfant/hulta_pregnancy_app/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testWeaningReference = `
+breed: Test
+default: true
+weight:
+  - {age_days: 0, p5: 40, p25: 45, p50: 50, p75: 55, p95: 60}
+  - {age_days: 300, p5: 240, p25: 270, p50: 290, p75: 310, p95: 340}
+height:
+  - {age_days: 0, p5: 95, p25: 98, p50: 100, p75: 102, p95: 105}
+  - {age_days: 300, p5: 130, p25: 134, p50: 136, p75: 138, p95: 142}
+cannon:
+  - {age_days: 0, p5: 11, p25: 12, p50: 12.5, p75: 13, p95: 14}
+  - {age_days: 300, p5: 16, p25: 17, p50: 17.5, p75: 18, p95: 19}
+`
+
+func TestPlanWeaning(t *testing.T) {
+	reference, err := ParseReference([]byte(testWeaningReference))
+	require.NoError(t, err)
+	now := time.Date(2024, 9, 1, 12, 0, 0, 0, time.UTC)
+	days := func(n int) *int { return &n }
+
+	tests := []struct {
+		name            string
+		ageDays         int
+		data            []models.GrowthData
+		damGestation    *int
+		wantStatus      models.WeaningPlanStatus
+		wantRecommended int // Age in days
+		wantLatest      int
+		wantReasons     int
+	}{
+		{name: "young foal", ageDays: 90, wantStatus: models.WeaningTooYoung, wantRecommended: 180, wantLatest: 240, wantReasons: 1},
+		{name: "median foal", ageDays: 150, data: []models.GrowthData{{AgeDays: 150, Weight: 170}}, wantStatus: models.WeaningReady, wantRecommended: 180, wantLatest: 240, wantReasons: 1},
+		{name: "small foal stays longer", ageDays: 150, data: []models.GrowthData{{AgeDays: 150, Weight: 120}}, wantStatus: models.WeaningReady, wantRecommended: 210, wantLatest: 240, wantReasons: 2},
+		{name: "large foal can go earlier", ageDays: 100, data: []models.GrowthData{{AgeDays: 100, Weight: 150}}, wantStatus: models.WeaningTooYoung, wantRecommended: 150, wantLatest: 240, wantReasons: 2},
+		{name: "dam in foal brings the latest date forward", ageDays: 100, damGestation: days(145), wantStatus: models.WeaningTooYoung, wantRecommended: 180, wantLatest: 180, wantReasons: 2},
+		{name: "dam far along", ageDays: 100, damGestation: days(220), wantStatus: models.WeaningTooYoung, wantRecommended: 120, wantLatest: 120, wantReasons: 2},
+		{name: "dam early in foal makes no difference", ageDays: 100, damGestation: days(60), wantStatus: models.WeaningTooYoung, wantRecommended: 180, wantLatest: 240, wantReasons: 1},
+		{name: "overdue", ageDays: 260, wantStatus: models.WeaningOverdue, wantRecommended: 260, wantLatest: 240, wantReasons: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			foal := &models.Horse{ID: 3, BirthDate: now.AddDate(0, 0, -tt.ageDays)}
+			plan := PlanWeaning(reference, foal, tt.data, tt.damGestation, now)
+
+			assert.Equal(t, tt.ageDays, plan.AgeDays)
+			assert.Equal(t, tt.wantStatus, plan.Status)
+			assert.Equal(t, foal.BirthDate.AddDate(0, 0, 120), plan.Earliest)
+			if tt.wantRecommended == tt.ageDays {
+				assert.Equal(t, now, plan.Recommended)
+			} else {
+				assert.Equal(t, foal.BirthDate.AddDate(0, 0, tt.wantRecommended), plan.Recommended)
+			}
+			assert.Equal(t, foal.BirthDate.AddDate(0, 0, tt.wantLatest), plan.Latest)
+			assert.Len(t, plan.Reasons, tt.wantReasons)
+			if tt.damGestation != nil {
+				assert.Equal(t, *tt.damGestation, *plan.DamGestationDays)
+			}
+		})
+	}
+
+	t.Run("dam stage", func(t *testing.T) {
+		foal := &models.Horse{BirthDate: now.AddDate(0, 0, -100)}
+		assert.Equal(t, models.PregnancyStageMid, PlanWeaning(reference, foal, nil, days(145), now).DamStage)
+		assert.Empty(t, PlanWeaning(reference, foal, nil, nil, now).DamStage)
+	})
+
+	t.Run("recommended date already passed", func(t *testing.T) {
+		foal := &models.Horse{BirthDate: now.AddDate(0, 0, -200)}
+		plan := PlanWeaning(reference, foal, nil, nil, now)
+		assert.Equal(t, models.WeaningReady, plan.Status)
+		assert.Equal(t, now, plan.Recommended)
+	})
+}
+
+func TestMonitorWeaning(t *testing.T) {
+	reference, err := ParseReference([]byte(testWeaningReference))
+	require.NoError(t, err)
+	birth := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	weaned := birth.AddDate(0, 0, 180)
+	record := &models.WeaningRecord{HorseID: 3, Date: weaned, Method: models.WeaningFenceLine}
+	weighing := func(ageDays int, weight float64) models.GrowthData {
+		return models.GrowthData{AgeDays: ageDays, Weight: weight, MeasurementDate: birth.AddDate(0, 0, ageDays)}
+	}
+	checkAt := func(days int, signs ...models.WeaningStressSign) models.WeaningCheck {
+		return models.WeaningCheck{CheckedAt: weaned.AddDate(0, 0, days), Signs: signs}
+	}
+
+	t.Run("steady gain", func(t *testing.T) {
+		data := []models.GrowthData{weighing(120, 150), weighing(175, 180), weighing(200, 192)}
+		progress := MonitorWeaning(reference, record, data, []models.WeaningCheck{checkAt(1, models.WeaningVocalizing)}, weaned.AddDate(0, 0, 21))
+
+		assert.Equal(t, 21, progress.DaysSinceWeaning)
+		assert.True(t, progress.Monitoring)
+		assert.Equal(t, weaned.AddDate(0, 0, WeaningMonitoringDays), progress.MonitoringEnds)
+		assert.Equal(t, 180.0, *progress.PreWeaningWeight)
+		assert.Equal(t, 192.0, *progress.LatestWeight)
+		assert.Equal(t, 12.0, *progress.WeightChangeKg)
+		assert.Equal(t, 0.48, *progress.DailyGainKg)
+		assert.Equal(t, 0.8, *progress.ExpectedDailyGain)
+		assert.Len(t, progress.Measurements, 2, "older weighings are left out")
+		assert.Len(t, progress.Checks, 1)
+		assert.Empty(t, progress.Alerts, "calling the day after weaning is expected")
+	})
+
+	t.Run("early small loss is expected", func(t *testing.T) {
+		data := []models.GrowthData{weighing(178, 180), weighing(185, 178)}
+		progress := MonitorWeaning(reference, record, data, nil, weaned.AddDate(0, 0, 6))
+		assert.Equal(t, -2.0, *progress.WeightChangeKg)
+		assert.Empty(t, progress.Alerts)
+	})
+
+	t.Run("large loss", func(t *testing.T) {
+		data := []models.GrowthData{weighing(178, 180), weighing(185, 168)}
+		progress := MonitorWeaning(reference, record, data, nil, weaned.AddDate(0, 0, 6))
+		require.Len(t, progress.Alerts, 1)
+		assert.Contains(t, progress.Alerts[0], "Lost 12.0 kg")
+	})
+
+	t.Run("still losing once settled", func(t *testing.T) {
+		data := []models.GrowthData{weighing(178, 180), weighing(196, 179)}
+		progress := MonitorWeaning(reference, record, data, nil, weaned.AddDate(0, 0, 16))
+		require.Len(t, progress.Alerts, 1)
+		assert.Contains(t, progress.Alerts[0], "Lost 1.0 kg")
+	})
+
+	t.Run("slow gain once settled", func(t *testing.T) {
+		data := []models.GrowthData{weighing(178, 180), weighing(198, 184)}
+		progress := MonitorWeaning(reference, record, data, nil, weaned.AddDate(0, 0, 18))
+		require.Len(t, progress.Alerts, 1)
+		assert.Contains(t, progress.Alerts[0], "Gaining 0.20 kg a day")
+	})
+
+	t.Run("not weighed since weaning", func(t *testing.T) {
+		progress := MonitorWeaning(reference, record, []models.GrowthData{weighing(178, 180)}, nil, weaned.AddDate(0, 0, 15))
+		assert.Nil(t, progress.LatestWeight)
+		require.Len(t, progress.Alerts, 1)
+		assert.Contains(t, progress.Alerts[0], "No weight since weaning")
+	})
+
+	t.Run("stress signs in the latest check", func(t *testing.T) {
+		checks := []models.WeaningCheck{
+			checkAt(9, models.WeaningPacing, models.WeaningNotEating, models.WeaningCough),
+			checkAt(1, models.WeaningInjury),
+		}
+		progress := MonitorWeaning(reference, record, nil, checks, weaned.AddDate(0, 0, 10))
+		assert.Equal(t, checkAt(1, models.WeaningInjury), progress.Checks[0], "checks are sorted")
+		assert.Len(t, progress.Alerts, 3, "not eating, cough and still pacing after a week")
+	})
+
+	t.Run("after the monitoring period", func(t *testing.T) {
+		progress := MonitorWeaning(reference, record, nil, []models.WeaningCheck{checkAt(40, models.WeaningInjury)}, weaned.AddDate(0, 0, 45))
+		assert.False(t, progress.Monitoring)
+		assert.Empty(t, progress.Checks)
+		assert.Empty(t, progress.Alerts)
+	})
+}
@@ -3,72 +3,187 @@ package service
 import (
 	"context"
 	"fmt"
+	"math"
+	"sort"
+	"strings"
 	"time"
 
+	"github.com/polyfant/hulta_pregnancy_app/internal/logger"
 	"github.com/polyfant/hulta_pregnancy_app/internal/models"
 	"github.com/polyfant/hulta_pregnancy_app/internal/repository"
+	"github.com/polyfant/hulta_pregnancy_app/internal/service/growth"
+	"github.com/polyfant/hulta_pregnancy_app/internal/service/notification"
 )
 
 type GrowthService interface {
-	RecordGrowthMeasurement(ctx context.Context, foalID uint, weight, height float64) error
+	RecordGrowthMeasurement(ctx context.Context, foalID uint, measurement *models.GrowthData) error
 	GetFoalGrowthData(ctx context.Context, foalID uint) ([]models.GrowthData, error)
 	AnalyzeGrowthTrends(ctx context.Context, foalID uint) (*GrowthAnalysis, error)
+	GetReferenceCurve(ctx context.Context, foalID uint) (*growth.Reference, error)
 }
 
 type GrowthServiceImpl struct {
 	growthRepo repository.GrowthRepository
 	horseRepo  repository.HorseRepository
+	curves     *growth.ReferenceCurves
+	notifier   Notifier
 }
 
+// growthProjectionDays is how far ahead AnalyzeGrowthTrends projects
+const growthProjectionDays = 30
+
+// GrowthAnalysis summarizes a foal's growth. Gains are per day; the
+// projection is growthProjectionDays after the latest measurement. Mature
+// is the projected adult size, left out once the horse is grown.
 type GrowthAnalysis struct {
-	AverageWeightGain float64 `json:"averageWeightGain"`
-	AverageHeightGain float64 `json:"averageHeightGain"`
-	ProjectedWeight   float64 `json:"projectedWeight"`
-	ProjectedHeight   float64 `json:"projectedHeight"`
-	GrowthStatus      string  `json:"growthStatus"`
+	AverageWeightGain float64                  `json:"averageWeightGain"`
+	AverageHeightGain float64                  `json:"averageHeightGain"`
+	ProjectedWeight   float64                  `json:"projectedWeight"`
+	ProjectedHeight   float64                  `json:"projectedHeight"`
+	GrowthStatus      string                   `json:"growthStatus"`
+	Velocity          *models.GrowthVelocity   `json:"velocity"`
+	Mature            *models.MatureProjection `json:"mature,omitempty"`
 }
 
-func NewGrowthService(growthRepo repository.GrowthRepository, horseRepo repository.HorseRepository) *GrowthServiceImpl {
+func NewGrowthService(growthRepo repository.GrowthRepository, horseRepo repository.HorseRepository, curves *growth.ReferenceCurves, notifier Notifier) *GrowthServiceImpl {
 	return &GrowthServiceImpl{
 		growthRepo: growthRepo,
 		horseRepo:  horseRepo,
+		curves:     curves,
+		notifier:   notifier,
 	}
 }
 
-func (s *GrowthServiceImpl) RecordGrowthMeasurement(ctx context.Context, foalID uint, weight, height float64) error {
-	// Validate horse exists
-	horse, err := s.horseRepo.GetByID(ctx, foalID)
+// RecordGrowthMeasurement saves a set of measurements with the foal's age in
+// days on the day they were taken, placed against the breed's reference curve
+func (s *GrowthServiceImpl) RecordGrowthMeasurement(ctx context.Context, foalID uint, measurement *models.GrowthData) error {
+	foal, err := s.horseRepo.GetByID(ctx, foalID)
 	if err != nil {
 		return fmt.Errorf("invalid foal ID: %w", err)
 	}
 
-	// Get existing growth data to calculate age
-	existingData, _ := s.growthRepo.GetGrowthDataByFoalID(ctx, foalID)
-	age := len(existingData)
+	now := timeNow()
+	if measurement.MeasurementDate.IsZero() {
+		measurement.MeasurementDate = now
+	}
+	if err := validateGrowthMeasurement(foal, measurement, now); err != nil {
+		return err
+	}
+
+	measurement.FoalID = foalID
+	measurement.AgeDays = growth.AgeInDays(foal.BirthDate, measurement.MeasurementDate)
+	if err := s.growthRepo.CreateGrowthData(ctx, measurement); err != nil {
+		return err
+	}
+
+	reference := s.reference(foal)
+	growth.Place(reference, measurement)
+	s.checkVelocity(ctx, foal, reference, measurement)
+	return nil
+}
 
-	// Determine expected values based on breed (simplified)
-	expectedWeight := calculateExpectedWeight(horse.Breed, age)
-	expectedHeight := calculateExpectedHeight(horse.Breed, age)
+// checkVelocity alerts the owner when the new weighing shows a growth spurt
+// or rapid growth. The measurement is already saved, so failures are logged
+// rather than returned.
+func (s *GrowthServiceImpl) checkVelocity(ctx context.Context, foal *models.Horse, reference *growth.Reference, measurement *models.GrowthData) {
+	if s.notifier == nil || measurement.Weight == 0 {
+		return
+	}
+	growthData, err := s.growthRepo.GetGrowthDataByFoalID(ctx, foal.ID)
+	if err != nil {
+		logger.Warn("Failed to check growth velocity", map[string]interface{}{"horseID": foal.ID, "error": err.Error()})
+		return
+	}
 
-	growthData := &models.GrowthData{
-		FoalID:          foalID,
-		Age:             age,
-		Weight:          weight,
-		Height:          height,
-		ExpectedWeight:  expectedWeight,
-		ExpectedHeight:  expectedHeight,
-		MeasurementDate: time.Now(),
+	velocity := growth.Velocity(reference, growthData)
+	current := velocity.Current
+	if current == nil || current.ToAgeDays != measurement.AgeDays {
+		return
 	}
 
-	return s.growthRepo.CreateGrowthData(ctx, growthData)
+	var alert *notification.Notification
+	switch current.Status {
+	case models.GrowthVelocitySpurt:
+		alert = &notification.Notification{
+			Title:    fmt.Sprintf("Growth spurt for %s", foal.Name),
+			Priority: notification.High,
+		}
+	case models.GrowthVelocityRapid:
+		alert = &notification.Notification{
+			Title:    fmt.Sprintf("Rapid growth for %s", foal.Name),
+			Priority: notification.Medium,
+		}
+	default:
+		return
+	}
+	alert.Type = notification.GrowthAlert
+	alert.UserID = foal.UserID
+	alert.HorseID = int64(foal.ID)
+	alert.Message = strings.Join(velocity.Suggestions, "; ")
+	if err := s.notifier.SendNotification(ctx, alert); err != nil {
+		logger.Warn("Failed to send growth alert", map[string]interface{}{"horseID": foal.ID, "error": err.Error()})
+	}
 }
 
+// GetFoalGrowthData returns the foal's measurements, youngest first, with
+// their expected values and percentiles
 func (s *GrowthServiceImpl) GetFoalGrowthData(ctx context.Context, foalID uint) ([]models.GrowthData, error) {
-	return s.growthRepo.GetGrowthDataByFoalID(ctx, foalID)
+	foal, err := s.horseRepo.GetByID(ctx, foalID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid foal ID: %w", err)
+	}
+	growthData, err := s.growthRepo.GetGrowthDataByFoalID(ctx, foalID)
+	if err != nil {
+		return nil, err
+	}
+
+	reference := s.reference(foal)
+	for i := range growthData {
+		growth.Place(reference, &growthData[i])
+	}
+	return growthData, nil
 }
 
+// GetReferenceCurve returns the percentile bands the foal is measured against
+func (s *GrowthServiceImpl) GetReferenceCurve(ctx context.Context, foalID uint) (*growth.Reference, error) {
+	foal, err := s.horseRepo.GetByID(ctx, foalID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid foal ID: %w", err)
+	}
+	reference := s.reference(foal)
+	if reference == nil {
+		return nil, fmt.Errorf("no growth reference loaded")
+	}
+	return reference, nil
+}
+
+func (s *GrowthServiceImpl) reference(foal *models.Horse) *growth.Reference {
+	if s.curves == nil {
+		return nil
+	}
+	return s.curves.ForBreed(foal.Breed)
+}
+
+func validateGrowthMeasurement(foal *models.Horse, measurement *models.GrowthData, now time.Time) error {
+	switch {
+	case foal.BirthDate.IsZero():
+		return fmt.Errorf("%w: foal has no birth date", models.ErrInvalidGrowthMeasurement)
+	case measurement.MeasurementDate.After(now):
+		return fmt.Errorf("%w: measurement date cannot be in the future", models.ErrInvalidGrowthMeasurement)
+	case measurement.MeasurementDate.Before(foal.BirthDate):
+		return fmt.Errorf("%w: measurement date is before the foal was born", models.ErrInvalidGrowthMeasurement)
+	case measurement.Weight < 0 || measurement.Height < 0 || measurement.CannonCircumference < 0:
+		return fmt.Errorf("%w: measurements cannot be negative", models.ErrInvalidGrowthMeasurement)
+	case measurement.Weight == 0 && measurement.Height == 0 && measurement.CannonCircumference == 0:
+		return fmt.Errorf("%w: weight, height or cannon circumference is required", models.ErrInvalidGrowthMeasurement)
+	}
+	return nil
+}
+
+// AnalyzeGrowthTrends works out average daily gain over the whole record and
+// the growth velocity between weighings against the breed reference
 func (s *GrowthServiceImpl) AnalyzeGrowthTrends(ctx context.Context, foalID uint) (*GrowthAnalysis, error) {
-	growthData, err := s.growthRepo.GetGrowthDataByFoalID(ctx, foalID)
+	growthData, err := s.GetFoalGrowthData(ctx, foalID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to retrieve growth data: %w", err)
 	}
@@ -76,58 +191,53 @@ func (s *GrowthServiceImpl) AnalyzeGrowthTrends(ctx context.Context, foalID uint
 	if len(growthData) < 2 {
 		return nil, fmt.Errorf("insufficient growth data for analysis")
 	}
+	sort.SliceStable(growthData, func(i, j int) bool { return growthData[i].AgeDays < growthData[j].AgeDays })
 
-	// Calculate average gains
-	totalWeightGain := growthData[len(growthData)-1].Weight - growthData[0].Weight
-	totalHeightGain := growthData[len(growthData)-1].Height - growthData[0].Height
-	averageWeightGain := totalWeightGain / float64(len(growthData)-1)
-	averageHeightGain := totalHeightGain / float64(len(growthData)-1)
-
-	// Simple growth status determination
-	growthStatus := "Normal"
-	if averageWeightGain < 0.5 {
-		growthStatus = "Slow"
-	} else if averageWeightGain > 1.5 {
-		growthStatus = "Rapid"
-	}
-
-	// Naive projection (linear extrapolation)
-	lastData := growthData[len(growthData)-1]
-	projectedWeight := lastData.Weight + (averageWeightGain * 2)
-	projectedHeight := lastData.Height + (averageHeightGain * 2)
-
-	return &GrowthAnalysis{
-		AverageWeightGain: averageWeightGain,
-		AverageHeightGain: averageHeightGain,
-		ProjectedWeight:   projectedWeight,
-		ProjectedHeight:   projectedHeight,
-		GrowthStatus:      growthStatus,
-	}, nil
-}
+	foal, err := s.horseRepo.GetByID(ctx, foalID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid foal ID: %w", err)
+	}
+	reference := s.reference(foal)
+	velocity := growth.Velocity(reference, growthData)
+
+	weightGain, lastWeight := averageDailyGain(growthData, func(d models.GrowthData) float64 { return d.Weight })
+	heightGain, lastHeight := averageDailyGain(growthData, func(d models.GrowthData) float64 { return d.Height })
+
+	analysis := &GrowthAnalysis{
+		AverageWeightGain: math.Round(weightGain*100) / 100,
+		AverageHeightGain: math.Round(heightGain*100) / 100,
+		ProjectedWeight:   math.Round((lastWeight+weightGain*growthProjectionDays)*10) / 10,
+		ProjectedHeight:   math.Round((lastHeight+heightGain*growthProjectionDays)*10) / 10,
+		GrowthStatus:      string(velocity.Status),
+		Velocity:          &velocity,
+	}
 
-// Simplified breed-specific growth expectation calculations
-func calculateExpectedWeight(breed string, age int) float64 {
-	switch breed {
-	case "Thoroughbred":
-		return 50 + (float64(age) * 1.2)
-	case "Warmblood":
-		return 55 + (float64(age) * 1.5)
-	case "Arabian":
-		return 45 + (float64(age) * 1.0)
-	default:
-		return 50 + (float64(age) * 1.2)
+	mature := growth.ProjectMature(reference, growthData)
+	if mature != nil && growth.AgeInDays(foal.BirthDate, timeNow()) < mature.MatureAgeDays {
+		mature.MatureDate = foal.BirthDate.AddDate(0, 0, mature.MatureAgeDays)
+		analysis.Mature = mature
 	}
+	return analysis, nil
 }
 
-func calculateExpectedHeight(breed string, age int) float64 {
-	switch breed {
-	case "Thoroughbred":
-		return 100 + (float64(age) * 0.8)
-	case "Warmblood":
-		return 110 + (float64(age) * 1.0)
-	case "Arabian":
-		return 95 + (float64(age) * 0.7)
-	default:
-		return 100 + (float64(age) * 0.8)
+// averageDailyGain is the gain per day between the first and last
+// measurement of a value, and the last measurement
+func averageDailyGain(growthData []models.GrowthData, value func(models.GrowthData) float64) (float64, float64) {
+	var first, last *models.GrowthData
+	for i := range growthData {
+		if value(growthData[i]) <= 0 {
+			continue
+		}
+		if first == nil {
+			first = &growthData[i]
+		}
+		last = &growthData[i]
+	}
+	if first == nil || last.AgeDays == first.AgeDays {
+		if last != nil {
+			return 0, value(*last)
+		}
+		return 0, 0
 	}
+	return (value(*last) - value(*first)) / float64(last.AgeDays-first.AgeDays), value(*last)
 }
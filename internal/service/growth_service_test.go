@@ -0,0 +1,245 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/polyfant/hulta_pregnancy_app/internal/mocks"
+	"github.com/polyfant/hulta_pregnancy_app/internal/models"
+	"github.com/polyfant/hulta_pregnancy_app/internal/service/growth"
+	"github.com/polyfant/hulta_pregnancy_app/internal/service/notification"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func testGrowthCurves() *growth.ReferenceCurves {
+	band := func(age int, p50 float64) growth.ReferencePoint {
+		return growth.ReferencePoint{AgeDays: age, P5: p50 * 0.9, P25: p50 * 0.95, P50: p50, P75: p50 * 1.05, P95: p50 * 1.1}
+	}
+	return &growth.ReferenceCurves{References: []growth.Reference{{
+		Breed:   "Light horse",
+		Default: true,
+		Weight:  []growth.ReferencePoint{band(0, 50), band(100, 150)},
+		Height:  []growth.ReferencePoint{band(0, 100), band(100, 120)},
+		Cannon:  []growth.ReferencePoint{band(0, 12), band(100, 16)},
+		Mature:  &growth.Mature{AgeDays: 400, Weight: band(400, 400), Height: band(400, 150)},
+	}}}
+}
+
+func TestGrowthService_RecordGrowthMeasurement(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	birth := now.AddDate(0, 0, -60)
+
+	tests := []struct {
+		name        string
+		foal        *models.Horse
+		measurement models.GrowthData
+		wantAge     int
+		wantErr     error
+	}{
+		{
+			name:        "age from birth date",
+			foal:        &models.Horse{ID: 9, BirthDate: birth},
+			measurement: models.GrowthData{Weight: 110, Height: 112},
+			wantAge:     60,
+		},
+		{
+			name:        "back-dated measurement",
+			foal:        &models.Horse{ID: 9, BirthDate: birth},
+			measurement: models.GrowthData{Weight: 80, MeasurementDate: birth.AddDate(0, 0, 20)},
+			wantAge:     20,
+		},
+		{name: "no birth date", foal: &models.Horse{ID: 9}, measurement: models.GrowthData{Weight: 110}, wantErr: models.ErrInvalidGrowthMeasurement},
+		{name: "before birth", foal: &models.Horse{ID: 9, BirthDate: birth}, measurement: models.GrowthData{Weight: 50, MeasurementDate: birth.AddDate(0, 0, -1)}, wantErr: models.ErrInvalidGrowthMeasurement},
+		{name: "in the future", foal: &models.Horse{ID: 9, BirthDate: birth}, measurement: models.GrowthData{Weight: 110, MeasurementDate: now.Add(time.Hour)}, wantErr: models.ErrInvalidGrowthMeasurement},
+		{name: "nothing measured", foal: &models.Horse{ID: 9, BirthDate: birth}, measurement: models.GrowthData{}, wantErr: models.ErrInvalidGrowthMeasurement},
+		{name: "negative value", foal: &models.Horse{ID: 9, BirthDate: birth}, measurement: models.GrowthData{Weight: 110, Height: -1}, wantErr: models.ErrInvalidGrowthMeasurement},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setTestNow(t, now)
+			growthRepo := new(mocks.MockGrowthRepository)
+			horseRepo := new(mocks.MockHorseRepository)
+			svc := NewGrowthService(growthRepo, horseRepo, testGrowthCurves(), new(mockNotifier))
+			horseRepo.On("GetByID", ctx, uint(9)).Return(tt.foal, nil)
+			growthRepo.On("CreateGrowthData", ctx, mock.AnythingOfType("*models.GrowthData")).Return(nil)
+			growthRepo.On("GetGrowthDataByFoalID", ctx, uint(9)).Return([]models.GrowthData{tt.measurement}, nil)
+
+			measurement := tt.measurement
+			err := svc.RecordGrowthMeasurement(ctx, 9, &measurement)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				growthRepo.AssertNotCalled(t, "CreateGrowthData", mock.Anything, mock.Anything)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, uint(9), measurement.FoalID)
+			assert.Equal(t, tt.wantAge, measurement.AgeDays)
+			assert.NotNil(t, measurement.WeightPercentile)
+		})
+	}
+}
+
+func TestGrowthService_GetFoalGrowthData(t *testing.T) {
+	ctx := context.Background()
+	growthRepo := new(mocks.MockGrowthRepository)
+	horseRepo := new(mocks.MockHorseRepository)
+	svc := NewGrowthService(growthRepo, horseRepo, testGrowthCurves(), new(mockNotifier))
+	horseRepo.On("GetByID", ctx, uint(9)).Return(&models.Horse{ID: 9, Breed: "Unknown"}, nil)
+	growthRepo.On("GetGrowthDataByFoalID", ctx, uint(9)).Return([]models.GrowthData{
+		{FoalID: 9, AgeDays: 50, Weight: 100, Height: 114},
+		{FoalID: 9, AgeDays: 100, Weight: 165},
+	}, nil)
+
+	data, err := svc.GetFoalGrowthData(ctx, 9)
+
+	require.NoError(t, err)
+	require.Len(t, data, 2)
+	assert.Equal(t, 100.0, data[0].ExpectedWeight)
+	assert.Equal(t, 50.0, *data[0].WeightPercentile)
+	assert.InDelta(t, 68.8, *data[0].HeightPercentile, 0.1)
+	assert.Nil(t, data[0].CannonPercentile)
+	assert.InDelta(t, 95.0, *data[1].WeightPercentile, 0.1)
+}
+
+func TestGrowthService_RecordGrowthMeasurementAlerts(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	foal := &models.Horse{ID: 9, UserID: "owner", Name: "Star", BirthDate: now.AddDate(0, 0, -60)}
+	previous := models.GrowthData{FoalID: 9, AgeDays: 46, Weight: 96}
+
+	tests := []struct {
+		name         string
+		weight       float64
+		wantPriority notification.Priority
+		wantAlert    bool
+	}{
+		{name: "normal growth", weight: 110},
+		{name: "rapid growth", weight: 114, wantAlert: true, wantPriority: notification.Medium},
+		{name: "growth spurt", weight: 120, wantAlert: true, wantPriority: notification.High},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setTestNow(t, now)
+			growthRepo := new(mocks.MockGrowthRepository)
+			horseRepo := new(mocks.MockHorseRepository)
+			notifier := new(mockNotifier)
+			svc := NewGrowthService(growthRepo, horseRepo, testGrowthCurves(), notifier)
+			horseRepo.On("GetByID", ctx, uint(9)).Return(foal, nil)
+			growthRepo.On("CreateGrowthData", ctx, mock.AnythingOfType("*models.GrowthData")).Return(nil)
+			growthRepo.On("GetGrowthDataByFoalID", ctx, uint(9)).Return([]models.GrowthData{
+				previous,
+				{FoalID: 9, AgeDays: 60, Weight: tt.weight},
+			}, nil)
+			if tt.wantAlert {
+				notifier.On("SendNotification", ctx, mock.MatchedBy(func(n *notification.Notification) bool {
+					return n.Type == notification.GrowthAlert && n.Priority == tt.wantPriority && n.UserID == "owner" && n.HorseID == 9
+				})).Return(nil)
+			}
+
+			err := svc.RecordGrowthMeasurement(ctx, 9, &models.GrowthData{Weight: tt.weight})
+
+			require.NoError(t, err)
+			if tt.wantAlert {
+				notifier.AssertExpectations(t)
+			} else {
+				notifier.AssertNotCalled(t, "SendNotification", mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestGrowthService_RecordGrowthMeasurementAlertFailureIsLogged(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	setTestNow(t, now)
+	growthRepo := new(mocks.MockGrowthRepository)
+	horseRepo := new(mocks.MockHorseRepository)
+	notifier := new(mockNotifier)
+	svc := NewGrowthService(growthRepo, horseRepo, testGrowthCurves(), notifier)
+	horseRepo.On("GetByID", ctx, uint(9)).Return(&models.Horse{ID: 9, BirthDate: now.AddDate(0, 0, -60)}, nil)
+	growthRepo.On("CreateGrowthData", ctx, mock.AnythingOfType("*models.GrowthData")).Return(nil)
+	growthRepo.On("GetGrowthDataByFoalID", ctx, uint(9)).Return([]models.GrowthData{
+		{FoalID: 9, AgeDays: 46, Weight: 96},
+		{FoalID: 9, AgeDays: 60, Weight: 120},
+	}, nil)
+	notifier.On("SendNotification", ctx, mock.Anything).Return(errors.New("smtp down"))
+
+	err := svc.RecordGrowthMeasurement(ctx, 9, &models.GrowthData{Weight: 120})
+
+	assert.NoError(t, err)
+	notifier.AssertExpectations(t)
+}
+
+func TestGrowthService_AnalyzeGrowthTrends(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	birth := now.AddDate(0, 0, -60)
+	setTestNow(t, now)
+	growthRepo := new(mocks.MockGrowthRepository)
+	horseRepo := new(mocks.MockHorseRepository)
+	svc := NewGrowthService(growthRepo, horseRepo, testGrowthCurves(), new(mockNotifier))
+	horseRepo.On("GetByID", ctx, uint(9)).Return(&models.Horse{ID: 9, BirthDate: birth}, nil)
+	growthRepo.On("GetGrowthDataByFoalID", ctx, uint(9)).Return([]models.GrowthData{
+		{FoalID: 9, AgeDays: 20, Weight: 70, Height: 104},
+		{FoalID: 9, AgeDays: 40, Weight: 90, Height: 108},
+		{FoalID: 9, AgeDays: 60, Weight: 120, Height: 112},
+	}, nil)
+
+	analysis, err := svc.AnalyzeGrowthTrends(ctx, 9)
+
+	require.NoError(t, err)
+	assert.Equal(t, 1.25, analysis.AverageWeightGain)
+	assert.Equal(t, 0.2, analysis.AverageHeightGain)
+	assert.Equal(t, 157.5, analysis.ProjectedWeight)
+	assert.Equal(t, 118.0, analysis.ProjectedHeight)
+	assert.Equal(t, string(models.GrowthVelocitySpurt), analysis.GrowthStatus)
+	require.NotNil(t, analysis.Velocity)
+	assert.Len(t, analysis.Velocity.Intervals, 2)
+	assert.NotEmpty(t, analysis.Velocity.Suggestions)
+
+	require.NotNil(t, analysis.Mature)
+	assert.Equal(t, birth.AddDate(0, 0, 400), analysis.Mature.MatureDate)
+	require.NotNil(t, analysis.Mature.Weight)
+	assert.Greater(t, analysis.Mature.Weight.Expected, 400.0, "the foal is above the median")
+	require.NotNil(t, analysis.Mature.Height)
+}
+
+func TestGrowthService_AnalyzeGrowthTrendsGrownHorse(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	setTestNow(t, now)
+	growthRepo := new(mocks.MockGrowthRepository)
+	horseRepo := new(mocks.MockHorseRepository)
+	svc := NewGrowthService(growthRepo, horseRepo, testGrowthCurves(), new(mockNotifier))
+	horseRepo.On("GetByID", ctx, uint(9)).Return(&models.Horse{ID: 9, BirthDate: now.AddDate(-5, 0, 0)}, nil)
+	growthRepo.On("GetGrowthDataByFoalID", ctx, uint(9)).Return([]models.GrowthData{
+		{FoalID: 9, AgeDays: 20, Weight: 70},
+		{FoalID: 9, AgeDays: 40, Weight: 90},
+	}, nil)
+
+	analysis, err := svc.AnalyzeGrowthTrends(ctx, 9)
+
+	require.NoError(t, err)
+	assert.Nil(t, analysis.Mature)
+}
+
+func TestGrowthService_AnalyzeGrowthTrendsNeedsTwoMeasurements(t *testing.T) {
+	ctx := context.Background()
+	growthRepo := new(mocks.MockGrowthRepository)
+	horseRepo := new(mocks.MockHorseRepository)
+	svc := NewGrowthService(growthRepo, horseRepo, testGrowthCurves(), new(mockNotifier))
+	horseRepo.On("GetByID", ctx, uint(9)).Return(&models.Horse{ID: 9}, nil)
+	growthRepo.On("GetGrowthDataByFoalID", ctx, uint(9)).Return([]models.GrowthData{{FoalID: 9, AgeDays: 20, Weight: 70}}, nil)
+
+	_, err := svc.AnalyzeGrowthTrends(ctx, 9)
+
+	assert.Error(t, err)
+}
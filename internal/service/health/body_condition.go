@@ -0,0 +1,179 @@
+package health
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/polyfant/hulta_pregnancy_app/internal/models"
+)
+
+const (
+	// trendWindowDays is how far back scores count towards the trend
+	trendWindowDays = 90
+	// minTrendSpanDays is the shortest span of scores a trend is read from
+	minTrendSpanDays = 14
+	// trendThresholdPerMonth is the change a month that counts as losing or
+	// gaining; half a point over two months is visible on the horse
+	trendThresholdPerMonth = 0.25
+)
+
+// henneke names the points of the Henneke scale
+var henneke = [...]string{"", "Poor", "Very thin", "Thin", "Moderately thin", "Moderate", "Moderately fleshy", "Fleshy", "Fat", "Extremely fat"}
+
+// HennekeScore is the overall score of a body condition record: the average
+// of the six regions, rounded to the nearest half point. Without regional
+// scores the overall score given is used as is.
+func HennekeScore(bc models.BodyCondition) (float64, error) {
+	regions := []float64{bc.Neck, bc.Withers, bc.Loin, bc.Tailhead, bc.Ribs, bc.Shoulder}
+
+	var sum float64
+	scored := 0
+	for _, region := range regions {
+		if region == 0 {
+			continue
+		}
+		if region < 1 || region > 9 {
+			return 0, fmt.Errorf("regional scores must be between 1 and 9, got %.1f", region)
+		}
+		sum += region
+		scored++
+	}
+
+	switch scored {
+	case 0:
+		if bc.Score < 1 || bc.Score > 9 {
+			return 0, fmt.Errorf("score must be between 1 and 9, got %.1f", bc.Score)
+		}
+		return roundHalf(bc.Score), nil
+	case len(regions):
+		return roundHalf(sum / float64(scored)), nil
+	default:
+		return 0, fmt.Errorf("score all six regions or none, got %d", scored)
+	}
+}
+
+// BodyConditionCategory names a score on the Henneke scale. A half-point
+// score takes the name of the point below it.
+func BodyConditionCategory(score float64) string {
+	point := int(math.Floor(score))
+	if point < 1 || point > 9 {
+		return ""
+	}
+	return henneke[point]
+}
+
+// BodyConditionTarget is the score range to aim for. Pregnant mares carry a
+// little more condition into foaling and lactation.
+func BodyConditionTarget(horse models.Horse) (float64, float64) {
+	if horse.IsPregnant {
+		return 5, 7
+	}
+	return 4, 6
+}
+
+// BodyConditionTrendOf fits a line through the scores of the last
+// trendWindowDays and returns the direction and the change per month
+func BodyConditionTrendOf(records []models.BodyCondition, now time.Time) (models.BodyConditionTrend, float64) {
+	since := now.AddDate(0, 0, -trendWindowDays)
+	var xs, ys []float64
+	var first, last time.Time
+	for _, record := range records {
+		if record.Date.Before(since) || record.Date.After(now) {
+			continue
+		}
+		if first.IsZero() || record.Date.Before(first) {
+			first = record.Date
+		}
+		if record.Date.After(last) {
+			last = record.Date
+		}
+		xs = append(xs, record.Date.Sub(since).Hours()/24)
+		ys = append(ys, record.Score)
+	}
+	if len(xs) < 2 || last.Sub(first) < minTrendSpanDays*24*time.Hour {
+		return models.BodyConditionUnknown, 0
+	}
+
+	var meanX, meanY float64
+	for i := range xs {
+		meanX += xs[i]
+		meanY += ys[i]
+	}
+	meanX /= float64(len(xs))
+	meanY /= float64(len(ys))
+
+	var cov, variance float64
+	for i := range xs {
+		cov += (xs[i] - meanX) * (ys[i] - meanY)
+		variance += (xs[i] - meanX) * (xs[i] - meanX)
+	}
+	perMonth := math.Round(cov/variance*30*100) / 100
+
+	switch {
+	case perMonth <= -trendThresholdPerMonth:
+		return models.BodyConditionLosing, perMonth
+	case perMonth >= trendThresholdPerMonth:
+		return models.BodyConditionGaining, perMonth
+	default:
+		return models.BodyConditionStable, perMonth
+	}
+}
+
+// SummarizeBodyCondition charts the scores against the horse's target range.
+// With a conception date the chart starts at conception and every point
+// carries its day of gestation.
+func SummarizeBodyCondition(horse models.Horse, records []models.BodyCondition, conception *time.Time, now time.Time) models.BodyConditionHistory {
+	sort.SliceStable(records, func(i, j int) bool { return records[i].Date.Before(records[j].Date) })
+
+	history := models.BodyConditionHistory{
+		HorseID:        horse.ID,
+		Points:         []models.BodyConditionPoint{},
+		ConceptionDate: conception,
+	}
+	history.TargetMin, history.TargetMax = BodyConditionTarget(horse)
+	history.Trend, history.ChangePerMonth = BodyConditionTrendOf(records, now)
+
+	for _, record := range records {
+		point := models.BodyConditionPoint{Date: record.Date, Score: record.Score}
+		if conception != nil {
+			if record.Date.Before(*conception) {
+				continue
+			}
+			day := int(record.Date.Sub(*conception).Hours() / 24)
+			point.GestationDay = &day
+		}
+		history.Points = append(history.Points, point)
+	}
+
+	if len(records) == 0 {
+		return history
+	}
+	latest := records[len(records)-1].Score
+	history.Latest = &latest
+	history.Category = BodyConditionCategory(latest)
+	history.Advice = bodyConditionAdvice(horse, history)
+	return history
+}
+
+func bodyConditionAdvice(horse models.Horse, history models.BodyConditionHistory) string {
+	latest := *history.Latest
+	switch {
+	case latest < history.TargetMin && horse.IsPregnant:
+		return fmt.Sprintf("Below the target of %.0f-%.0f for a pregnant mare. Thin mares foal weaker foals and are slower to rebreed; increase energy gradually and ask your vet to rule out illness.", history.TargetMin, history.TargetMax)
+	case latest < history.TargetMin:
+		return fmt.Sprintf("Below the target of %.0f-%.0f. Increase energy gradually and check teeth and worm burden.", history.TargetMin, history.TargetMax)
+	case history.Trend == models.BodyConditionLosing && horse.IsPregnant:
+		return fmt.Sprintf("Losing %.1f points a month. Pregnant mares should hold condition; increase the ration before she drops below %.0f.", -history.ChangePerMonth, history.TargetMin)
+	case history.Trend == models.BodyConditionLosing && latest <= history.TargetMax:
+		return fmt.Sprintf("Losing %.1f points a month. Review the ration and workload.", -history.ChangePerMonth)
+	case latest > history.TargetMax:
+		return fmt.Sprintf("Above the target of %.0f-%.0f. Cut back concentrates rather than forage and increase exercise where possible.", history.TargetMin, history.TargetMax)
+	}
+	return "Within the target range."
+}
+
+func roundHalf(x float64) float64 {
+	return math.Round(x*2) / 2
+}
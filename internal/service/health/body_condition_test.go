@@ -0,0 +1,129 @@
+package health
+
+import (
+	"testing"
+	"time"
+
+	"github.com/polyfant/hulta_pregnancy_app/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHennekeScore(t *testing.T) {
+	tests := []struct {
+		name    string
+		record  models.BodyCondition
+		want    float64
+		wantErr bool
+	}{
+		{
+			name:   "regions averaged",
+			record: models.BodyCondition{Neck: 5, Withers: 5, Loin: 6, Tailhead: 6, Ribs: 5, Shoulder: 5},
+			want:   5.5,
+		},
+		{
+			name:   "rounded to nearest half point",
+			record: models.BodyCondition{Neck: 4, Withers: 4, Loin: 4, Tailhead: 4, Ribs: 4, Shoulder: 5},
+			want:   4,
+		},
+		{name: "overall score without regions", record: models.BodyCondition{Score: 6.5}, want: 6.5},
+		{name: "regions override overall score", record: models.BodyCondition{Score: 9, Neck: 3, Withers: 3, Loin: 3, Tailhead: 3, Ribs: 3, Shoulder: 3}, want: 3},
+		{name: "some regions missing", record: models.BodyCondition{Neck: 5, Ribs: 5}, wantErr: true},
+		{name: "region out of range", record: models.BodyCondition{Neck: 10, Withers: 5, Loin: 5, Tailhead: 5, Ribs: 5, Shoulder: 5}, wantErr: true},
+		{name: "no score at all", record: models.BodyCondition{}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := HennekeScore(tt.record)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestBodyConditionTrendOf(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	scores := func(values ...float64) []models.BodyCondition {
+		records := make([]models.BodyCondition, len(values))
+		for i, v := range values {
+			records[i] = models.BodyCondition{Date: now.AddDate(0, 0, -30*(len(values)-1-i)), Score: v}
+		}
+		return records
+	}
+
+	tests := []struct {
+		name      string
+		records   []models.BodyCondition
+		want      models.BodyConditionTrend
+		wantMonth float64
+	}{
+		{name: "losing a point over two months", records: scores(6, 5.5, 5), want: models.BodyConditionLosing, wantMonth: -0.5},
+		{name: "gaining", records: scores(4, 4.5, 5), want: models.BodyConditionGaining, wantMonth: 0.5},
+		{name: "stable", records: scores(5, 5, 5), want: models.BodyConditionStable},
+		{name: "single score", records: scores(5), want: models.BodyConditionUnknown},
+		{
+			name: "scores too close together",
+			records: []models.BodyCondition{
+				{Date: now.AddDate(0, 0, -7), Score: 6},
+				{Date: now, Score: 4},
+			},
+			want: models.BodyConditionUnknown,
+		},
+		{
+			name: "scores outside the window ignored",
+			records: []models.BodyCondition{
+				{Date: now.AddDate(0, 0, -200), Score: 8},
+				{Date: now.AddDate(0, 0, -60), Score: 5},
+				{Date: now, Score: 5},
+			},
+			want: models.BodyConditionStable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trend, perMonth := BodyConditionTrendOf(tt.records, now)
+			assert.Equal(t, tt.want, trend)
+			assert.InDelta(t, tt.wantMonth, perMonth, 0.01)
+		})
+	}
+}
+
+func TestSummarizeBodyCondition(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	conception := now.AddDate(0, 0, -100)
+	mare := models.Horse{ID: 2, Gender: models.GenderMare, IsPregnant: true}
+	records := []models.BodyCondition{
+		{Date: now, Score: 4.5},
+		{Date: conception.AddDate(0, 0, -10), Score: 6},
+		{Date: now.AddDate(0, 0, -60), Score: 5.5},
+	}
+
+	history := SummarizeBodyCondition(mare, records, &conception, now)
+
+	require.Len(t, history.Points, 2, "scores before conception are left off the chart")
+	require.NotNil(t, history.Points[0].GestationDay)
+	assert.Equal(t, 40, *history.Points[0].GestationDay)
+	assert.Equal(t, 100, *history.Points[1].GestationDay)
+	require.NotNil(t, history.Latest)
+	assert.Equal(t, 4.5, *history.Latest)
+	assert.Equal(t, "Moderately thin", history.Category)
+	assert.Equal(t, 5.0, history.TargetMin)
+	assert.Equal(t, 7.0, history.TargetMax)
+	assert.Equal(t, models.BodyConditionLosing, history.Trend)
+	assert.Contains(t, history.Advice, "pregnant mare")
+}
+
+func TestSummarizeBodyCondition_NoScores(t *testing.T) {
+	history := SummarizeBodyCondition(models.Horse{ID: 1}, nil, nil, time.Now())
+
+	assert.Empty(t, history.Points)
+	assert.Nil(t, history.Latest)
+	assert.Equal(t, models.BodyConditionUnknown, history.Trend)
+	assert.Equal(t, 4.0, history.TargetMin)
+}
@@ -0,0 +1,308 @@
+package health
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/polyfant/hulta_pregnancy_app/internal/models"
+	"gopkg.in/yaml.v3"
+)
+
+var ErrInvalidCatalogue = errors.New("invalid condition catalogue")
+
+// minPartialMatch is the shortest observed symptom matched by substring, so
+// short words like "off" do not match half the catalogue
+const minPartialMatch = 4
+
+// Condition is one entry of the symptom checker catalogue
+type Condition struct {
+	Name       string             `yaml:"name" json:"name"`
+	Category   string             `yaml:"category" json:"category"`
+	Urgency    int                `yaml:"urgency" json:"urgency"`
+	Action     string             `yaml:"action" json:"action"`
+	Prevention []string           `yaml:"prevention" json:"prevention"`
+	Symptoms   []ConditionSymptom `yaml:"symptoms" json:"symptoms"`
+	Vitals     []VitalCriterion   `yaml:"vitals" json:"vitals"`
+}
+
+// ConditionSymptom is a sign of a condition. Weight defaults to 1; signs
+// that are more specific to the condition should weigh more.
+type ConditionSymptom struct {
+	Label   string   `yaml:"label" json:"label"`
+	Aliases []string `yaml:"aliases" json:"aliases"`
+	Weight  float64  `yaml:"weight" json:"weight"`
+}
+
+// VitalCriterion matches a vital sign. Numeric signs match a Direction
+// (high or low) outside the normal range for the horse's category; gut
+// sounds and mucous membranes match one of Values.
+type VitalCriterion struct {
+	Sign      string   `yaml:"sign" json:"sign"`
+	Direction string   `yaml:"direction" json:"direction"`
+	Values    []string `yaml:"values" json:"values"`
+	Weight    float64  `yaml:"weight" json:"weight"`
+}
+
+// ConditionCatalogue holds the conditions the symptom checker knows about
+type ConditionCatalogue struct {
+	Conditions []Condition
+}
+
+// catalogueFile is the layout of a catalogue file
+type catalogueFile struct {
+	Conditions []Condition `yaml:"conditions"`
+}
+
+// LoadConditionCatalogue reads every .yaml, .yml and .json file in dir.
+// JSON is valid YAML, so both formats share one parser.
+func LoadConditionCatalogue(dir string) (*ConditionCatalogue, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read condition catalogue: %w", err)
+	}
+
+	catalogue := &ConditionCatalogue{}
+	for _, entry := range entries {
+		ext := strings.ToLower(filepath.Ext(entry.Name()))
+		if entry.IsDir() || (ext != ".yaml" && ext != ".yml" && ext != ".json") {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", entry.Name(), err)
+		}
+		conditions, err := ParseConditions(data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", entry.Name(), err)
+		}
+		catalogue.Conditions = append(catalogue.Conditions, conditions...)
+	}
+
+	if err := catalogue.validate(); err != nil {
+		return nil, err
+	}
+	return catalogue, nil
+}
+
+// ParseConditions parses one catalogue file and fills in default weights
+func ParseConditions(data []byte) ([]Condition, error) {
+	var file catalogueFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCatalogue, err)
+	}
+	for i := range file.Conditions {
+		c := &file.Conditions[i]
+		for j := range c.Symptoms {
+			if c.Symptoms[j].Weight == 0 {
+				c.Symptoms[j].Weight = 1
+			}
+		}
+		for j := range c.Vitals {
+			if c.Vitals[j].Weight == 0 {
+				c.Vitals[j].Weight = 1
+			}
+		}
+	}
+	return file.Conditions, nil
+}
+
+func (c *ConditionCatalogue) validate() error {
+	if len(c.Conditions) == 0 {
+		return fmt.Errorf("%w: no conditions", ErrInvalidCatalogue)
+	}
+
+	names := map[string]bool{}
+	for _, condition := range c.Conditions {
+		key := normalizeSymptom(condition.Name)
+		switch {
+		case key == "":
+			return fmt.Errorf("%w: condition without a name", ErrInvalidCatalogue)
+		case names[key]:
+			return fmt.Errorf("%w: duplicate condition %q", ErrInvalidCatalogue, condition.Name)
+		case condition.Urgency < 1 || condition.Urgency > 5:
+			return fmt.Errorf("%w: %s urgency must be 1-5", ErrInvalidCatalogue, condition.Name)
+		case len(condition.Symptoms) == 0:
+			return fmt.Errorf("%w: %s has no symptoms", ErrInvalidCatalogue, condition.Name)
+		}
+		names[key] = true
+
+		for _, symptom := range condition.Symptoms {
+			if strings.TrimSpace(symptom.Label) == "" || symptom.Weight < 0 {
+				return fmt.Errorf("%w: %s has a symptom without a label or with a negative weight", ErrInvalidCatalogue, condition.Name)
+			}
+		}
+		for _, criterion := range condition.Vitals {
+			if err := criterion.validate(); err != nil {
+				return fmt.Errorf("%w: %s: %v", ErrInvalidCatalogue, condition.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+func (v VitalCriterion) validate() error {
+	switch v.Sign {
+	case "temperature", "heart_rate", "respiration_rate", "capillary_refill":
+		if v.Direction != "high" && (v.Direction != "low" || v.Sign == "capillary_refill") {
+			return fmt.Errorf("%s needs direction high or low", v.Sign)
+		}
+	case "gut_sounds":
+		for _, value := range v.Values {
+			if !models.GutSounds(value).IsValid() {
+				return fmt.Errorf("unknown gut sounds %q", value)
+			}
+		}
+	case "mucous_membrane":
+		for _, value := range v.Values {
+			if !models.MucousMembraneColour(value).IsValid() {
+				return fmt.Errorf("unknown mucous membrane colour %q", value)
+			}
+		}
+	default:
+		return fmt.Errorf("unknown vital sign %q", v.Sign)
+	}
+	if (v.Sign == "gut_sounds" || v.Sign == "mucous_membrane") && len(v.Values) == 0 {
+		return fmt.Errorf("%s needs values", v.Sign)
+	}
+	return nil
+}
+
+// Assess matches observed symptoms and vital signs against the catalogue.
+// Conditions need at least one matching symptom; vitals only strengthen a
+// match. Results are ranked by confidence, then urgency. The second return
+// value lists symptoms that matched nothing in the catalogue.
+func (c *ConditionCatalogue) Assess(symptoms []string, category string, vitals *models.VitalSignsRecord) ([]models.ConditionMatch, []string) {
+	recognised := make([]bool, len(symptoms))
+	matches := []models.ConditionMatch{}
+
+	for _, condition := range c.Conditions {
+		var total, matched float64
+		match := models.ConditionMatch{
+			Name:       condition.Name,
+			Category:   condition.Category,
+			Urgency:    condition.Urgency,
+			Action:     condition.Action,
+			Prevention: condition.Prevention,
+		}
+
+		for _, symptom := range condition.Symptoms {
+			total += symptom.Weight
+			for i, observed := range symptoms {
+				if symptom.matches(observed) {
+					recognised[i] = true
+					matched += symptom.Weight
+					match.MatchedSymptoms = append(match.MatchedSymptoms, symptom.Label)
+					break
+				}
+			}
+		}
+		if len(match.MatchedSymptoms) == 0 {
+			continue
+		}
+
+		for _, criterion := range condition.Vitals {
+			total += criterion.Weight
+			if vitals != nil {
+				if value, ok := criterion.matches(category, *vitals); ok {
+					matched += criterion.Weight
+					match.MatchedVitals = append(match.MatchedVitals, value)
+				}
+			}
+		}
+
+		match.Confidence = math.Round(matched/total*100) / 100
+		matches = append(matches, match)
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].Confidence != matches[j].Confidence {
+			return matches[i].Confidence > matches[j].Confidence
+		}
+		return matches[i].Urgency > matches[j].Urgency
+	})
+
+	var unrecognised []string
+	for i, ok := range recognised {
+		if !ok {
+			unrecognised = append(unrecognised, symptoms[i])
+		}
+	}
+	return matches, unrecognised
+}
+
+func (s ConditionSymptom) matches(observed string) bool {
+	observed = normalizeSymptom(observed)
+	if observed == "" {
+		return false
+	}
+	for _, name := range append([]string{s.Label}, s.Aliases...) {
+		name = normalizeSymptom(name)
+		if observed == name {
+			return true
+		}
+		if len(observed) >= minPartialMatch && len(name) >= minPartialMatch &&
+			(strings.Contains(observed, name) || strings.Contains(name, observed)) {
+			return true
+		}
+	}
+	return false
+}
+
+// matches reports whether the vital sign fits the criterion and describes the value
+func (v VitalCriterion) matches(category string, record models.VitalSignsRecord) (string, bool) {
+	ranges, ok := VitalSignRanges[category]
+	if !ok {
+		ranges = VitalSignRanges["Adult"]
+	}
+
+	direction := func(value, min, max float64) string {
+		switch {
+		case value > max:
+			return "high"
+		case value < min:
+			return "low"
+		}
+		return ""
+	}
+
+	switch v.Sign {
+	case "temperature":
+		if t := record.Temperature; t != nil && direction(*t, ranges.Temperature.Min, ranges.Temperature.Max) == v.Direction {
+			return fmt.Sprintf("temperature %.1f°C", *t), true
+		}
+	case "heart_rate":
+		if hr := record.HeartRate; hr != nil && direction(float64(*hr), float64(ranges.HeartRate.Min), float64(ranges.HeartRate.Max)) == v.Direction {
+			return fmt.Sprintf("heart rate %d bpm", *hr), true
+		}
+	case "respiration_rate":
+		if rr := record.RespirationRate; rr != nil && direction(float64(*rr), float64(ranges.RespirationRate.Min), float64(ranges.RespirationRate.Max)) == v.Direction {
+			return fmt.Sprintf("respiration rate %d breaths/min", *rr), true
+		}
+	case "capillary_refill":
+		if crt := record.CapillaryRefill; crt != nil && *crt > MaxCapillaryRefill {
+			return fmt.Sprintf("capillary refill %.1fs", *crt), true
+		}
+	case "gut_sounds":
+		for _, value := range v.Values {
+			if string(record.GutSounds) == value {
+				return "gut sounds " + strings.ToLower(value), true
+			}
+		}
+	case "mucous_membrane":
+		for _, value := range v.Values {
+			if string(record.MucousMembrane) == value {
+				return "mucous membranes " + strings.ToLower(strings.ReplaceAll(value, "_", " ")), true
+			}
+		}
+	}
+	return "", false
+}
+
+func normalizeSymptom(s string) string {
+	return strings.Join(strings.Fields(strings.ToLower(s)), " ")
+}
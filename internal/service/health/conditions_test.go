@@ -0,0 +1,96 @@
+package health
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/polyfant/hulta_pregnancy_app/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadConditionCatalogue(t *testing.T) {
+	t.Run("shipped catalogue is valid", func(t *testing.T) {
+		catalogue, err := LoadConditionCatalogue("../../../config/conditions")
+		require.NoError(t, err)
+
+		names := map[string]bool{}
+		for _, condition := range catalogue.Conditions {
+			names[condition.Name] = true
+		}
+		for _, name := range []string{"Colic", "Laminitis", "Respiratory infection"} {
+			assert.True(t, names[name], "catalogue covers %s", name)
+		}
+	})
+
+	tests := []struct {
+		name string
+		file string
+	}{
+		{"bad urgency", "conditions:\n  - name: X\n    urgency: 9\n    symptoms: [{label: a}]\n"},
+		{"no symptoms", "conditions:\n  - name: X\n    urgency: 2\n"},
+		{"unknown vital sign", "conditions:\n  - name: X\n    urgency: 2\n    symptoms: [{label: a}]\n    vitals: [{sign: blood_pressure, direction: high}]\n"},
+		{"gut sounds without values", "conditions:\n  - name: X\n    urgency: 2\n    symptoms: [{label: a}]\n    vitals: [{sign: gut_sounds}]\n"},
+		{"duplicate names", "conditions:\n  - name: X\n    urgency: 2\n    symptoms: [{label: a}]\n  - name: x\n    urgency: 2\n    symptoms: [{label: b}]\n"},
+		{"not yaml", "conditions: [\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			require.NoError(t, os.WriteFile(filepath.Join(dir, "bad.yaml"), []byte(tt.file), 0644))
+
+			_, err := LoadConditionCatalogue(dir)
+			assert.ErrorIs(t, err, ErrInvalidCatalogue)
+		})
+	}
+
+	t.Run("json files load too", func(t *testing.T) {
+		dir := t.TempDir()
+		data := `{"conditions": [{"name": "Eye injury", "urgency": 4, "symptoms": [{"label": "Squinting"}]}]}`
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "eyes.json"), []byte(data), 0644))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "README.md"), []byte("ignored"), 0644))
+
+		catalogue, err := LoadConditionCatalogue(dir)
+		require.NoError(t, err)
+		require.Len(t, catalogue.Conditions, 1)
+		assert.Equal(t, 1.0, catalogue.Conditions[0].Symptoms[0].Weight, "weight defaults to 1")
+	})
+}
+
+func TestConditionCatalogue_Assess(t *testing.T) {
+	catalogue, err := LoadConditionCatalogue("../../../config/conditions")
+	require.NoError(t, err)
+	heartRate := 60
+
+	t.Run("colic signs rank colic first", func(t *testing.T) {
+		matches, unrecognised := catalogue.Assess([]string{"Pawing", "looking at flanks", "no manure"}, "Adult", nil)
+		require.NotEmpty(t, matches)
+		assert.Equal(t, "Colic", matches[0].Name)
+		assert.Equal(t, 5, matches[0].Urgency)
+		assert.Empty(t, unrecognised)
+	})
+
+	t.Run("vitals strengthen a match", func(t *testing.T) {
+		symptoms := []string{"pawing", "sweating"}
+		without, _ := catalogue.Assess(symptoms, "Adult", nil)
+		with, _ := catalogue.Assess(symptoms, "Adult", &models.VitalSignsRecord{HeartRate: &heartRate, GutSounds: models.GutSoundsAbsent})
+
+		require.Equal(t, "Colic", with[0].Name)
+		assert.Greater(t, with[0].Confidence, without[0].Confidence)
+		assert.Equal(t, []string{"heart rate 60 bpm", "gut sounds absent"}, with[0].MatchedVitals)
+	})
+
+	t.Run("foal heart rate is not high for a foal", func(t *testing.T) {
+		matches, _ := catalogue.Assess([]string{"pawing"}, "Foal", &models.VitalSignsRecord{HeartRate: &heartRate})
+		require.NotEmpty(t, matches)
+		assert.Empty(t, matches[0].MatchedVitals)
+	})
+
+	t.Run("unknown symptoms are reported", func(t *testing.T) {
+		matches, unrecognised := catalogue.Assess([]string{"itchy tail"}, "Adult", nil)
+		assert.Empty(t, matches)
+		assert.Equal(t, []string{"itchy tail"}, unrecognised)
+	})
+}
@@ -0,0 +1,73 @@
+package health
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/polyfant/hulta_pregnancy_app/internal/models"
+)
+
+const (
+	// Default farrier intervals in weeks when the horse has no schedule
+	foalFarrierWeeks     = 4
+	shodFarrierWeeks     = 6
+	barefootFarrierWeeks = 8
+
+	// firstTrimMonths is the age of a foal's first trim
+	firstTrimMonths = 1
+	// FarrierDueSoonDays is shorter than DueSoonDays because visits are only weeks apart
+	FarrierDueSoonDays = 7
+)
+
+// FarrierInterval returns the weeks between farrier visits: the horse's
+// schedule if it has one, otherwise a default for its age and whether it
+// was shod at the last visit
+func FarrierInterval(horse models.Horse, schedule *models.FarrierSchedule, last *models.FarrierVisit, now time.Time) (int, string) {
+	if schedule != nil && schedule.IntervalWeeks > 0 {
+		return schedule.IntervalWeeks, fmt.Sprintf("Every %d weeks as scheduled", schedule.IntervalWeeks)
+	}
+	if !horse.BirthDate.IsZero() && horse.BirthDate.AddDate(1, 0, 0).After(now) {
+		return foalFarrierWeeks, fmt.Sprintf("Foals are trimmed every %d weeks while their hooves develop", foalFarrierWeeks)
+	}
+	if last != nil && last.Work.IsShod() {
+		return shodFarrierWeeks, fmt.Sprintf("Shod horses need resetting every %d weeks", shodFarrierWeeks)
+	}
+	return barefootFarrierWeeks, fmt.Sprintf("Barefoot horses need trimming every %d weeks", barefootFarrierWeeks)
+}
+
+// FarrierDue works out the next farrier visit. Horses without a schedule or
+// any visit on record are left out, since not every owner uses a farrier
+// through the app.
+func FarrierDue(horse models.Horse, schedule *models.FarrierSchedule, visits []models.FarrierVisit, now time.Time) (models.HealthDueItem, bool) {
+	item := models.HealthDueItem{
+		HorseID:   horse.ID,
+		HorseName: horse.Name,
+		Category:  models.HealthDueFarrier,
+		Name:      "Farrier visit",
+	}
+
+	var last *models.FarrierVisit
+	for i := range visits {
+		if last == nil || visits[i].VisitDate.After(last.VisitDate) {
+			last = &visits[i]
+		}
+	}
+
+	switch {
+	case last == nil && schedule == nil:
+		return item, false
+	case last == nil:
+		item.DueDate, item.Reason = firstDue(horse, now, 0, firstTrimMonths, "No farrier visit on record",
+			"First trim at one month of age")
+	case last.NextVisitDate != nil:
+		item.LastDate = &last.VisitDate
+		item.DueDate, item.Reason = *last.NextVisitDate, "Next visit as booked with the farrier"
+	default:
+		weeks, reason := FarrierInterval(horse, schedule, last, now)
+		item.LastDate = &last.VisitDate
+		item.DueDate, item.Reason = last.VisitDate.AddDate(0, 0, weeks*7), reason
+	}
+
+	item.Status = dueStatusWithin(item.DueDate, truncateDay(now), FarrierDueSoonDays)
+	return item, true
+}
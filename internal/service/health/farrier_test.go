@@ -0,0 +1,76 @@
+package health
+
+import (
+	"testing"
+	"time"
+
+	"github.com/polyfant/hulta_pregnancy_app/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFarrierDue(t *testing.T) {
+	now := time.Date(2024, 6, 1, 9, 0, 0, 0, time.UTC)
+	today := truncateDay(now)
+	adult := models.Horse{ID: 2, Name: "Storm", BirthDate: now.AddDate(-8, 0, 0)}
+	foal := models.Horse{ID: 3, Name: "Pip", BirthDate: now.AddDate(0, -4, 0)}
+	visit := func(weeksAgo int, work models.FarrierWork) models.FarrierVisit {
+		return models.FarrierVisit{VisitDate: today.AddDate(0, 0, -7*weeksAgo), Work: work}
+	}
+	booked := today.AddDate(0, 0, 3)
+
+	tests := []struct {
+		name       string
+		horse      models.Horse
+		schedule   *models.FarrierSchedule
+		visits     []models.FarrierVisit
+		wantOK     bool
+		wantDue    time.Time
+		wantStatus models.HealthDueStatus
+	}{
+		{name: "no schedule and no visits", horse: adult},
+		{
+			name: "barefoot default", horse: adult,
+			visits: []models.FarrierVisit{visit(10, models.FarrierWorkShoes), visit(3, models.FarrierWorkTrim)},
+			wantOK: true, wantDue: today.AddDate(0, 0, 35), wantStatus: models.HealthDueUpcoming,
+		},
+		{
+			name: "shod default", horse: adult,
+			visits: []models.FarrierVisit{visit(5, models.FarrierWorkReset)},
+			wantOK: true, wantDue: today.AddDate(0, 0, 7), wantStatus: models.HealthDueSoon,
+		},
+		{
+			name: "foal interval", horse: foal,
+			visits: []models.FarrierVisit{visit(5, models.FarrierWorkTrim)},
+			wantOK: true, wantDue: today.AddDate(0, 0, -7), wantStatus: models.HealthDueOverdue,
+		},
+		{
+			name: "schedule interval wins", horse: adult, schedule: &models.FarrierSchedule{IntervalWeeks: 5},
+			visits: []models.FarrierVisit{visit(3, models.FarrierWorkTrim)},
+			wantOK: true, wantDue: today.AddDate(0, 0, 14), wantStatus: models.HealthDueUpcoming,
+		},
+		{
+			name: "booked date wins", horse: adult, schedule: &models.FarrierSchedule{IntervalWeeks: 5},
+			visits: []models.FarrierVisit{{VisitDate: today.AddDate(0, 0, -21), Work: models.FarrierWorkTrim, NextVisitDate: &booked}},
+			wantOK: true, wantDue: booked, wantStatus: models.HealthDueSoon,
+		},
+		{
+			name: "scheduled but never visited", horse: adult, schedule: &models.FarrierSchedule{IntervalWeeks: 6},
+			wantOK: true, wantDue: today, wantStatus: models.HealthDueSoon,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			item, ok := FarrierDue(tt.horse, tt.schedule, tt.visits, now)
+			require.Equal(t, tt.wantOK, ok)
+			if !ok {
+				return
+			}
+			assert.Equal(t, models.HealthDueFarrier, item.Category)
+			assert.Equal(t, tt.horse.Name, item.HorseName)
+			assert.Equal(t, tt.wantDue, item.DueDate)
+			assert.Equal(t, tt.wantStatus, item.Status)
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/polyfant/hulta_pregnancy_app/internal/models"
+)
+
+// foalBirthWindow is how far a foal's recorded birth date may be from the
+// end of the pregnancy and still be taken as the foal from it
+const foalBirthWindow = 7 * 24 * time.Hour
+
+// Lactation reports the mare's lactation after her last completed
+// pregnancy, or nil when she is not nursing a foal: more than six months
+// after foaling, or once the foal is weaned.
+func (s *NutritionService) Lactation(ctx context.Context, horse *models.Horse) (*models.Lactation, error) {
+	latest, err := s.weights.GetLatest(ctx, horse.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get latest weight: %w", err)
+	}
+	weight, known := CurrentWeight(*horse, latest)
+	if !known {
+		weight = defaultWeightKg
+	}
+	return s.lactation(ctx, horse, weight)
+}
+
+func (s *NutritionService) lactation(ctx context.Context, horse *models.Horse, weight float64) (*models.Lactation, error) {
+	if horse.Gender == models.GenderStallion || horse.Gender == models.GenderGelding {
+		return nil, nil
+	}
+	pregnancy, err := s.pregnancies.GetLatestCompleted(ctx, horse.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get last pregnancy: %w", err)
+	}
+	if pregnancy == nil || pregnancy.EndDate == nil {
+		return nil, nil
+	}
+
+	now := timeNow()
+	foaled := *pregnancy.EndDate
+	ends := foaled.AddDate(0, models.LactationMonths, 0)
+	if now.Before(foaled) || !now.Before(ends) {
+		return nil, nil
+	}
+
+	lactation := &models.Lactation{
+		HorseID:          horse.ID,
+		PregnancyID:      pregnancy.ID,
+		FoalingDate:      foaled,
+		DaysSinceFoaling: int(now.Sub(foaled).Hours() / 24),
+		EndsOn:           ends,
+		WeightKg:         weight,
+	}
+	foal, err := s.foal(ctx, horse.ID, foaled)
+	if err != nil {
+		return nil, err
+	}
+	if foal != nil {
+		lactation.FoalID = &foal.ID
+		weaning, err := s.weanings.GetByHorse(ctx, foal.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get foal's weaning record: %w", err)
+		}
+		if weaning != nil && !weaning.Date.After(now) {
+			return nil, nil
+		}
+	}
+
+	lactation.Month = min(lactation.DaysSinceFoaling/30+1, models.LactationMonths)
+	lactation.Stage = models.PregnancyStageEarlyLactation
+	if lactation.Month > 3 {
+		lactation.Stage = models.PregnancyStageLateLactation
+	}
+	requirements, _, err := NutrientRequirements(weight, Maintenance, 0, lactation.Month)
+	if err != nil {
+		return nil, err
+	}
+	lactation.Requirements = requirements
+	lactation.WaterLitres = math.Round(s.calculateBaseFeedRequirement(models.Horse{Weight: weight}).Water * lactationWaterFactor(lactation.Month))
+	return lactation, nil
+}
+
+// foal finds the mare's foal born at the end of the pregnancy, if it has
+// been recorded
+func (s *NutritionService) foal(ctx context.Context, mareID uint, foaled time.Time) (*models.Horse, error) {
+	offspring, err := s.horseRepo.GetOffspring(ctx, mareID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get offspring: %w", err)
+	}
+	for i := range offspring {
+		foal := &offspring[i]
+		isDam := (foal.DamID != nil && *foal.DamID == mareID) || (foal.MotherId != nil && *foal.MotherId == mareID)
+		gap := foal.BirthDate.Sub(foaled)
+		if isDam && gap < foalBirthWindow && gap > -foalBirthWindow {
+			return foal, nil
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,157 @@
+package health
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/polyfant/hulta_pregnancy_app/internal/mocks"
+	"github.com/polyfant/hulta_pregnancy_app/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type mockWeaningRepo struct {
+	mock.Mock
+}
+
+func (m *mockWeaningRepo) GetByHorse(ctx context.Context, horseID uint) (*models.WeaningRecord, error) {
+	args := m.Called(ctx, horseID)
+	if r, ok := args.Get(0).(*models.WeaningRecord); ok {
+		return r, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *mockWeaningRepo) Save(ctx context.Context, record *models.WeaningRecord) error {
+	args := m.Called(ctx, record)
+	return args.Error(0)
+}
+
+func (m *mockWeaningRepo) CreateCheck(ctx context.Context, check *models.WeaningCheck) error {
+	args := m.Called(ctx, check)
+	return args.Error(0)
+}
+
+func (m *mockWeaningRepo) ListChecks(ctx context.Context, horseID uint) ([]models.WeaningCheck, error) {
+	args := m.Called(ctx, horseID)
+	return args.Get(0).([]models.WeaningCheck), args.Error(1)
+}
+
+// noFoaling is a pregnancy repository for a mare that has never foaled
+func noFoaling(ctx context.Context, horseID uint) *mocks.MockPregnancyRepository {
+	pregnancies := new(mocks.MockPregnancyRepository)
+	pregnancies.On("GetLatestCompleted", ctx, horseID).Return(nil, nil)
+	return pregnancies
+}
+
+func TestLactation(t *testing.T) {
+	oldTimeNow := timeNow
+	fixedTime := time.Date(2024, time.April, 15, 12, 0, 0, 0, time.UTC)
+	timeNow = func() time.Time { return fixedTime }
+	defer func() { timeNow = oldTimeNow }()
+
+	ctx := context.Background()
+	mareID := uint(1)
+	day := func(y int, m time.Month, d int) time.Time { return time.Date(y, m, d, 0, 0, 0, 0, time.UTC) }
+	completed := func(foaled time.Time) *models.Pregnancy {
+		return &models.Pregnancy{ID: 9, HorseID: mareID, Status: models.PregnancyStatusComplete, EndDate: &foaled}
+	}
+
+	tests := []struct {
+		name      string
+		gender    models.Gender
+		pregnancy *models.Pregnancy
+		foals     []models.Horse
+		weaning   *models.WeaningRecord
+		wantMonth int
+		wantStage models.PregnancyStage
+		wantFoal  bool
+	}{
+		{name: "never foaled", gender: models.GenderMare},
+		{name: "gelding", gender: models.GenderGelding},
+		{name: "second month", gender: models.GenderMare, pregnancy: completed(day(2024, 3, 1)), wantMonth: 2, wantStage: models.PregnancyStageEarlyLactation},
+		{
+			name:      "fifth month with the foal recorded",
+			gender:    models.GenderMare,
+			pregnancy: completed(day(2023, 12, 1)),
+			foals:     []models.Horse{{ID: 5, DamID: &mareID, BirthDate: day(2023, 11, 30)}, {ID: 6, DamID: &mareID, BirthDate: day(2022, 12, 1)}},
+			wantMonth: 5,
+			wantStage: models.PregnancyStageLateLactation,
+			wantFoal:  true,
+		},
+		{name: "more than six months after foaling", gender: models.GenderMare, pregnancy: completed(day(2023, 9, 1))},
+		{
+			name:      "foal already weaned",
+			gender:    models.GenderMare,
+			pregnancy: completed(day(2023, 12, 1)),
+			foals:     []models.Horse{{ID: 5, DamID: &mareID, BirthDate: day(2023, 12, 1)}},
+			weaning:   &models.WeaningRecord{HorseID: 5, Date: day(2024, 4, 1)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			horse := &models.Horse{ID: mareID, Gender: tt.gender, Weight: 500}
+			weights := new(mockWeightRepo)
+			weights.On("GetLatest", ctx, mareID).Return(nil, nil)
+			pregnancies := new(mocks.MockPregnancyRepository)
+			pregnancies.On("GetLatestCompleted", ctx, mareID).Return(tt.pregnancy, nil)
+			horses := new(mockHorseRepo)
+			horses.On("GetOffspring", ctx, mareID).Return(tt.foals, nil)
+			weanings := new(mockWeaningRepo)
+			weanings.On("GetByHorse", ctx, uint(5)).Return(tt.weaning, nil)
+			service := NewNutritionService(nil, horses, nil, weights, pregnancies, weanings, nil)
+
+			got, err := service.Lactation(ctx, horse)
+
+			require.NoError(t, err)
+			if tt.wantMonth == 0 {
+				assert.Nil(t, got)
+				if tt.gender == models.GenderGelding {
+					pregnancies.AssertNotCalled(t, "GetLatestCompleted", mock.Anything, mock.Anything)
+				}
+				return
+			}
+			require.NotNil(t, got)
+			assert.Equal(t, tt.wantMonth, got.Month)
+			assert.Equal(t, tt.wantStage, got.Stage)
+			assert.Equal(t, tt.pregnancy.EndDate.AddDate(0, 6, 0), got.EndsOn)
+			assert.Equal(t, tt.wantFoal, got.FoalID != nil)
+			assert.InDelta(t, lactationNutrients[tt.wantMonth].DigestibleEnergy*500, got.Requirements.DigestibleEnergy, 0.01)
+			assert.InDelta(t, lactationNutrients[tt.wantMonth].CrudeProtein*500, got.Requirements.CrudeProtein, 0.01)
+		})
+	}
+}
+
+func TestRecommendFeed_Lactation(t *testing.T) {
+	oldTimeNow := timeNow
+	fixedTime := time.Date(2024, time.April, 15, 12, 0, 0, 0, time.UTC)
+	timeNow = func() time.Time { return fixedTime }
+	defer func() { timeNow = oldTimeNow }()
+
+	ctx := context.Background()
+	foaled := time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC)
+	horse := &models.Horse{ID: 1, Gender: models.GenderMare, Weight: 500}
+	bodyConditions := new(mockBodyConditionRepo)
+	bodyConditions.On("ListByHorse", ctx, horse.ID, mock.Anything).Return([]models.BodyCondition(nil), nil)
+	weights := new(mockWeightRepo)
+	weights.On("GetLatest", ctx, horse.ID).Return(nil, nil)
+	pregnancies := new(mocks.MockPregnancyRepository)
+	pregnancies.On("GetLatestCompleted", ctx, horse.ID).Return(&models.Pregnancy{ID: 9, Status: models.PregnancyStatusComplete, EndDate: &foaled}, nil)
+	horses := new(mockHorseRepo)
+	horses.On("GetOffspring", ctx, horse.ID).Return([]models.Horse(nil), nil)
+	service := NewNutritionService(new(mockHealthRepo), horses, bodyConditions, weights, pregnancies, new(mockWeaningRepo), keptIn(ctx, horse.ID))
+
+	got, err := service.RecommendFeed(ctx, horse, Maintenance)
+
+	require.NoError(t, err)
+	require.NotNil(t, got.Lactation)
+	assert.Equal(t, 2, got.Lactation.Month)
+	assert.Equal(t, 44.0, got.Lactation.WaterLitres)
+	assert.InDelta(t, 12.5, got.Requirements.Hay, 0.001)
+	assert.InDelta(t, 3.75, got.Requirements.Grain, 0.001)
+	assert.InDelta(t, 43.75, got.Requirements.Water, 0.001)
+	assert.Contains(t, got.Adjustments, "Month 2 of lactation: 31.0 Mcal energy, 1500 g protein and 44 litres of water a day")
+}
@@ -0,0 +1,365 @@
+package health
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/polyfant/hulta_pregnancy_app/internal/models"
+)
+
+// singleDoseWindow is how long a one-off dose may be late before it counts as missed
+const singleDoseWindow = 12 * time.Hour
+
+// MedicationReference is what is known about an active ingredient. Withdrawal
+// periods depend on product, dose and jurisdiction, so they come from the
+// prescription rather than from here.
+type MedicationReference struct {
+	Name              string
+	Aliases           []string
+	PregnancyRisk     models.PregnancyRisk
+	PregnancyNote     string
+	FoodChainExcluded bool
+}
+
+// MedicationReferences are the ingredients with known pregnancy risks or
+// food chain restrictions
+var MedicationReferences = []MedicationReference{
+	{
+		Name:          "Cloprostenol",
+		Aliases:       []string{"estrumate"},
+		PregnancyRisk: models.PregnancyRiskContraindicated,
+		PregnancyNote: "prostaglandins end pregnancy and cause abortion",
+	},
+	{
+		Name:          "Dinoprost",
+		Aliases:       []string{"lutalyse"},
+		PregnancyRisk: models.PregnancyRiskContraindicated,
+		PregnancyNote: "prostaglandins end pregnancy and cause abortion",
+	},
+	{
+		Name:          "Oxytocin",
+		PregnancyRisk: models.PregnancyRiskContraindicated,
+		PregnancyNote: "causes uterine contractions and can induce premature foaling",
+	},
+	{
+		Name:          "Griseofulvin",
+		PregnancyRisk: models.PregnancyRiskContraindicated,
+		PregnancyNote: "causes birth defects",
+	},
+	{
+		Name:          "Dexamethasone",
+		PregnancyRisk: models.PregnancyRiskCaution,
+		PregnancyNote: "high doses of corticosteroids in late pregnancy can induce premature foaling",
+	},
+	{
+		Name:          "Prednisolone",
+		PregnancyRisk: models.PregnancyRiskCaution,
+		PregnancyNote: "high doses of corticosteroids in late pregnancy can induce premature foaling",
+	},
+	{
+		Name:          "Triamcinolone",
+		PregnancyRisk: models.PregnancyRiskCaution,
+		PregnancyNote: "high doses of corticosteroids in late pregnancy can induce premature foaling",
+	},
+	{
+		Name:          "Trimethoprim",
+		Aliases:       []string{"sulfadiazine", "sulfamethoxazole", "tmps"},
+		PregnancyRisk: models.PregnancyRiskCaution,
+		PregnancyNote: "potentiated sulfonamides interfere with folate and are best avoided in early pregnancy",
+	},
+	{
+		Name:              "Phenylbutazone",
+		Aliases:           []string{"bute", "equipalazone"},
+		PregnancyRisk:     models.PregnancyRiskCaution,
+		PregnancyNote:     "NSAIDs in late pregnancy may delay foaling and affect the foal's circulation",
+		FoodChainExcluded: true,
+	},
+	{
+		Name:          "Flunixin",
+		Aliases:       []string{"banamine", "finadyne"},
+		PregnancyRisk: models.PregnancyRiskCaution,
+		PregnancyNote: "NSAIDs in late pregnancy may delay foaling and affect the foal's circulation",
+	},
+	{
+		Name:          "Meloxicam",
+		Aliases:       []string{"metacam"},
+		PregnancyRisk: models.PregnancyRiskCaution,
+		PregnancyNote: "NSAIDs in late pregnancy may delay foaling and affect the foal's circulation",
+	},
+	{
+		Name:          "Xylazine",
+		PregnancyRisk: models.PregnancyRiskCaution,
+		PregnancyNote: "alpha-2 sedatives can cause uterine contractions in late pregnancy",
+	},
+	{
+		Name:          "Detomidine",
+		Aliases:       []string{"domosedan"},
+		PregnancyRisk: models.PregnancyRiskCaution,
+		PregnancyNote: "alpha-2 sedatives can cause uterine contractions in late pregnancy",
+	},
+	{
+		Name:          "Acepromazine",
+		Aliases:       []string{"ace", "acp"},
+		PregnancyRisk: models.PregnancyRiskCaution,
+		PregnancyNote: "lowers blood pressure, which reduces blood flow to the foal",
+	},
+}
+
+// LookupMedications returns the references whose name or alias appears as a
+// word in the drug name, so "Equipalazone 1g (phenylbutazone)" finds
+// phenylbutazone once
+func LookupMedications(drug string) []MedicationReference {
+	words := map[string]bool{}
+	for _, word := range strings.FieldsFunc(strings.ToLower(drug), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		words[word] = true
+	}
+
+	var found []MedicationReference
+	for _, ref := range MedicationReferences {
+		for _, name := range append([]string{ref.Name}, ref.Aliases...) {
+			if words[strings.ToLower(name)] {
+				found = append(found, ref)
+				break
+			}
+		}
+	}
+	return found
+}
+
+// TreatmentWarnings lists the pregnancy contraindications and food chain
+// restrictions that apply to the plan's drug
+func TreatmentWarnings(plan models.TreatmentPlan, pregnant bool) []string {
+	var warnings []string
+	for _, ref := range LookupMedications(plan.Drug) {
+		if pregnant {
+			switch ref.PregnancyRisk {
+			case models.PregnancyRiskContraindicated:
+				warnings = append(warnings, fmt.Sprintf("%s is contraindicated in pregnant mares: %s. Do not use without your veterinarian's approval.", ref.Name, ref.PregnancyNote))
+			case models.PregnancyRiskCaution:
+				warnings = append(warnings, fmt.Sprintf("Use %s with caution in pregnant mares: %s.", ref.Name, ref.PregnancyNote))
+			}
+		}
+		if ref.FoodChainExcluded {
+			warnings = append(warnings, fmt.Sprintf("%s permanently excludes the horse from the food chain. Record it in the horse's passport.", ref.Name))
+		}
+	}
+	return warnings
+}
+
+// IsFoodChainExcluded reports whether the drug takes the horse out of the food chain for good
+func IsFoodChainExcluded(drug string) bool {
+	for _, ref := range LookupMedications(drug) {
+		if ref.FoodChainExcluded {
+			return true
+		}
+	}
+	return false
+}
+
+// ScheduledDoses lists the doses due from the start of the plan up to and
+// including until, stopping at the plan's end date
+func ScheduledDoses(plan models.TreatmentPlan, until time.Time) []time.Time {
+	end := until
+	if plan.EndDate != nil && plan.EndDate.Before(end) {
+		end = *plan.EndDate
+	}
+
+	var doses []time.Time
+	if plan.FrequencyHours <= 0 {
+		if !plan.StartDate.After(end) {
+			doses = append(doses, plan.StartDate)
+		}
+		return doses
+	}
+
+	interval := time.Duration(plan.FrequencyHours) * time.Hour
+	for at := plan.StartDate; !at.After(end); at = at.Add(interval) {
+		doses = append(doses, at)
+	}
+	return doses
+}
+
+// doseWindow is how far either side of its scheduled time a dose may be given
+func doseWindow(plan models.TreatmentPlan) time.Duration {
+	if plan.FrequencyHours <= 0 {
+		return singleDoseWindow
+	}
+	return time.Duration(plan.FrequencyHours) * time.Hour / 2
+}
+
+// covered reports whether a dose was given within the window around the scheduled time
+func covered(scheduled time.Time, window time.Duration, doses []models.DoseAdministration) bool {
+	from, to := scheduled.Add(-window), scheduled.Add(window)
+	for _, dose := range doses {
+		if !dose.AdministeredAt.Before(from) && dose.AdministeredAt.Before(to) {
+			return true
+		}
+	}
+	return false
+}
+
+// MissedDoses returns the scheduled doses whose window has passed without a
+// logged administration
+func MissedDoses(plan models.TreatmentPlan, doses []models.DoseAdministration, now time.Time) []time.Time {
+	window := doseWindow(plan)
+	missed := []time.Time{}
+	for _, scheduled := range ScheduledDoses(plan, now) {
+		if scheduled.Add(window).After(now) {
+			break
+		}
+		if !covered(scheduled, window, doses) {
+			missed = append(missed, scheduled)
+		}
+	}
+	return missed
+}
+
+// NextDose returns the first scheduled dose that is still open and not yet
+// given, or nil once the course is over
+func NextDose(plan models.TreatmentPlan, doses []models.DoseAdministration, now time.Time) *time.Time {
+	if plan.Status != models.TreatmentStatusActive {
+		return nil
+	}
+	window := doseWindow(plan)
+
+	if plan.FrequencyHours <= 0 {
+		if plan.StartDate.Add(window).After(now) && !covered(plan.StartDate, window, doses) {
+			next := plan.StartDate
+			return &next
+		}
+		return nil
+	}
+
+	// Skip straight to the dose before now instead of walking a long course
+	interval := time.Duration(plan.FrequencyHours) * time.Hour
+	at := plan.StartDate
+	if now.After(at) {
+		at = at.Add(now.Sub(at) / interval * interval)
+	}
+	for ; plan.EndDate == nil || !at.After(*plan.EndDate); at = at.Add(interval) {
+		if at.Add(window).After(now) && !covered(at, window, doses) {
+			next := at
+			return &next
+		}
+	}
+	return nil
+}
+
+// LastDose is the basis for the withdrawal periods: the later of the last
+// logged dose and the last dose the plan schedules. Doses that were missed
+// still count, so the clearance errs on the safe side. It reports false for
+// an ongoing plan without an end date.
+func LastDose(plan models.TreatmentPlan, doses []models.DoseAdministration) (time.Time, bool) {
+	if plan.EndDate == nil {
+		return time.Time{}, false
+	}
+
+	var last time.Time
+	if scheduled := ScheduledDoses(plan, *plan.EndDate); len(scheduled) > 0 {
+		last = scheduled[len(scheduled)-1]
+	}
+	for _, dose := range doses {
+		if dose.AdministeredAt.After(last) {
+			last = dose.AdministeredAt
+		}
+	}
+	return last, !last.IsZero()
+}
+
+// SummarizeTreatment adds the dose log, missed doses, next dose and
+// withdrawal clearance to a plan. A plan past its end date is completed.
+func SummarizeTreatment(plan models.TreatmentPlan, doses []models.DoseAdministration, now time.Time) models.TreatmentPlanSummary {
+	if plan.Status == models.TreatmentStatusActive && plan.EndDate != nil && plan.EndDate.Before(now) {
+		plan.Status = models.TreatmentStatusCompleted
+	}
+
+	sort.Slice(doses, func(i, j int) bool { return doses[i].AdministeredAt.Before(doses[j].AdministeredAt) })
+	if doses == nil {
+		doses = []models.DoseAdministration{}
+	}
+
+	summary := models.TreatmentPlanSummary{
+		TreatmentPlan: plan,
+		Doses:         doses,
+		MissedDoses:   MissedDoses(plan, doses, now),
+		NextDoseAt:    NextDose(plan, doses, now),
+	}
+
+	if last, ok := LastDose(plan, doses); ok {
+		competition := last.Add(time.Duration(plan.CompetitionWithdrawalHours) * time.Hour)
+		slaughter := last.AddDate(0, 0, plan.SlaughterWithdrawalDays)
+		summary.CompetitionClearAt = &competition
+		summary.SlaughterClearAt = &slaughter
+	}
+	return summary
+}
+
+// Withdrawal combines the treatment plans into whether the horse may compete
+// or be slaughtered now. An ongoing plan with a withdrawal period and no end
+// date blocks until it is stopped.
+func Withdrawal(horseID uint, summaries []models.TreatmentPlanSummary, now time.Time) models.WithdrawalStatus {
+	status := models.WithdrawalStatus{
+		HorseID:             horseID,
+		ClearForCompetition: true,
+		ClearForSlaughter:   true,
+		BlockingPlans:       []uint{},
+	}
+
+	latest := func(current **time.Time, candidate *time.Time) {
+		if candidate != nil && (*current == nil || candidate.After(**current)) {
+			*current = candidate
+		}
+	}
+
+	// An ongoing plan leaves the clearance date open
+	competitionOpen, slaughterOpen := false, false
+	for _, summary := range summaries {
+		blocking := false
+		if summary.FoodChainExcluded {
+			status.FoodChainExcluded = true
+		}
+
+		if summary.CompetitionWithdrawalHours > 0 {
+			if summary.CompetitionClearAt == nil {
+				competitionOpen = true
+			}
+			if summary.CompetitionClearAt == nil || summary.CompetitionClearAt.After(now) {
+				status.ClearForCompetition = false
+				blocking = true
+			}
+			latest(&status.CompetitionClearAt, summary.CompetitionClearAt)
+		}
+		if summary.SlaughterWithdrawalDays > 0 {
+			if summary.SlaughterClearAt == nil {
+				slaughterOpen = true
+			}
+			if summary.SlaughterClearAt == nil || summary.SlaughterClearAt.After(now) {
+				status.ClearForSlaughter = false
+				blocking = true
+			}
+			latest(&status.SlaughterClearAt, summary.SlaughterClearAt)
+		}
+
+		if blocking {
+			status.BlockingPlans = append(status.BlockingPlans, summary.ID)
+		}
+	}
+
+	if status.FoodChainExcluded {
+		status.ClearForSlaughter = false
+		status.SlaughterClearAt = nil
+	}
+	// Clearance dates only matter while they are still ahead and known
+	if status.ClearForCompetition || competitionOpen {
+		status.CompetitionClearAt = nil
+	}
+	if status.ClearForSlaughter || slaughterOpen {
+		status.SlaughterClearAt = nil
+	}
+	return status
+}
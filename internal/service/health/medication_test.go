@@ -0,0 +1,187 @@
+package health
+
+import (
+	"testing"
+	"time"
+
+	"github.com/polyfant/hulta_pregnancy_app/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLookupMedications(t *testing.T) {
+	tests := []struct {
+		drug string
+		want []string
+	}{
+		{"Equipalazone 1g (phenylbutazone)", []string{"Phenylbutazone"}},
+		{"bute", []string{"Phenylbutazone"}},
+		{"Trimethoprim/sulfadiazine", []string{"Trimethoprim"}},
+		{"Butorphanol", nil},
+		{"Omeprazole paste", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.drug, func(t *testing.T) {
+			var names []string
+			for _, ref := range LookupMedications(tt.drug) {
+				names = append(names, ref.Name)
+			}
+			assert.Equal(t, tt.want, names)
+		})
+	}
+}
+
+func TestTreatmentWarnings(t *testing.T) {
+	prostaglandin := models.TreatmentPlan{Drug: "Estrumate"}
+	assert.Empty(t, TreatmentWarnings(prostaglandin, false))
+	warnings := TreatmentWarnings(prostaglandin, true)
+	require.Len(t, warnings, 1)
+	assert.Contains(t, warnings[0], "Cloprostenol is contraindicated")
+
+	bute := models.TreatmentPlan{Drug: "Phenylbutazone"}
+	assert.Len(t, TreatmentWarnings(bute, false), 1, "food chain warning applies regardless of pregnancy")
+	assert.Len(t, TreatmentWarnings(bute, true), 2)
+}
+
+func TestMissedDoses(t *testing.T) {
+	start := time.Date(2024, 6, 1, 8, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 0, 3)
+	plan := models.TreatmentPlan{FrequencyHours: 12, StartDate: start, EndDate: &end, Status: models.TreatmentStatusActive}
+	given := func(hours float64) models.DoseAdministration {
+		return models.DoseAdministration{AdministeredAt: start.Add(time.Duration(hours * float64(time.Hour)))}
+	}
+
+	t.Run("doses within half an interval count", func(t *testing.T) {
+		now := start.Add(40 * time.Hour)
+		doses := []models.DoseAdministration{given(0), given(13), given(23)}
+		assert.Empty(t, MissedDoses(plan, doses, now))
+	})
+
+	t.Run("gap is reported once its window closes", func(t *testing.T) {
+		doses := []models.DoseAdministration{given(0), given(24)}
+		assert.Empty(t, MissedDoses(plan, doses, start.Add(17*time.Hour)))
+		assert.Equal(t, []time.Time{start.Add(12 * time.Hour)}, MissedDoses(plan, doses, start.Add(19*time.Hour)))
+	})
+
+	t.Run("nothing is scheduled after the end date", func(t *testing.T) {
+		doses := []models.DoseAdministration{}
+		for h := 0; h <= 72; h += 12 {
+			doses = append(doses, given(float64(h)))
+		}
+		assert.Empty(t, MissedDoses(plan, doses, start.AddDate(0, 1, 0)))
+	})
+
+	t.Run("single dose", func(t *testing.T) {
+		once := models.TreatmentPlan{StartDate: start, Status: models.TreatmentStatusActive}
+		assert.Empty(t, MissedDoses(once, nil, start.Add(6*time.Hour)))
+		assert.Len(t, MissedDoses(once, nil, start.Add(13*time.Hour)), 1)
+	})
+}
+
+func TestNextDose(t *testing.T) {
+	start := time.Date(2024, 6, 1, 8, 0, 0, 0, time.UTC)
+	plan := models.TreatmentPlan{FrequencyHours: 24, StartDate: start, Status: models.TreatmentStatusActive}
+
+	t.Run("current dose not yet given", func(t *testing.T) {
+		next := NextDose(plan, nil, start.AddDate(0, 0, 10).Add(2*time.Hour))
+		require.NotNil(t, next)
+		assert.Equal(t, start.AddDate(0, 0, 10), *next)
+	})
+
+	t.Run("current dose given", func(t *testing.T) {
+		doses := []models.DoseAdministration{{AdministeredAt: start.AddDate(0, 0, 10).Add(time.Hour)}}
+		next := NextDose(plan, doses, start.AddDate(0, 0, 10).Add(2*time.Hour))
+		require.NotNil(t, next)
+		assert.Equal(t, start.AddDate(0, 0, 11), *next)
+	})
+
+	t.Run("course over", func(t *testing.T) {
+		end := start.AddDate(0, 0, 5)
+		ended := plan
+		ended.EndDate = &end
+		assert.Nil(t, NextDose(ended, nil, start.AddDate(0, 0, 7)))
+	})
+
+	t.Run("stopped plan", func(t *testing.T) {
+		stopped := plan
+		stopped.Status = models.TreatmentStatusStopped
+		assert.Nil(t, NextDose(stopped, nil, start.AddDate(0, 0, 1)))
+	})
+}
+
+func TestSummarizeTreatment(t *testing.T) {
+	start := time.Date(2024, 6, 1, 8, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 0, 4)
+	plan := models.TreatmentPlan{
+		ID: 1, FrequencyHours: 24, StartDate: start, EndDate: &end, Status: models.TreatmentStatusActive,
+		CompetitionWithdrawalHours: 96, SlaughterWithdrawalDays: 28,
+	}
+
+	summary := SummarizeTreatment(plan, nil, start.AddDate(0, 0, 6))
+	assert.Equal(t, models.TreatmentStatusCompleted, summary.Status)
+	assert.Len(t, summary.MissedDoses, 5)
+	assert.Nil(t, summary.NextDoseAt)
+	require.NotNil(t, summary.CompetitionClearAt)
+	assert.Equal(t, end.Add(96*time.Hour), *summary.CompetitionClearAt, "missed doses still count towards withdrawal")
+	assert.Equal(t, end.AddDate(0, 0, 28), *summary.SlaughterClearAt)
+
+	ongoing := plan
+	ongoing.EndDate = nil
+	summary = SummarizeTreatment(ongoing, nil, start.AddDate(0, 0, 1))
+	assert.Nil(t, summary.CompetitionClearAt)
+	assert.Nil(t, summary.SlaughterClearAt)
+}
+
+func TestWithdrawal(t *testing.T) {
+	now := time.Date(2024, 6, 10, 12, 0, 0, 0, time.UTC)
+	at := func(days int) *time.Time {
+		t := now.AddDate(0, 0, days)
+		return &t
+	}
+	plan := func(id uint, competitionHours, slaughterDays int, competitionClear, slaughterClear *time.Time) models.TreatmentPlanSummary {
+		return models.TreatmentPlanSummary{
+			TreatmentPlan: models.TreatmentPlan{
+				ID: id, CompetitionWithdrawalHours: competitionHours, SlaughterWithdrawalDays: slaughterDays,
+			},
+			CompetitionClearAt: competitionClear,
+			SlaughterClearAt:   slaughterClear,
+		}
+	}
+
+	t.Run("no treatments", func(t *testing.T) {
+		status := Withdrawal(1, nil, now)
+		assert.True(t, status.ClearForCompetition)
+		assert.True(t, status.ClearForSlaughter)
+		assert.Empty(t, status.BlockingPlans)
+	})
+
+	t.Run("latest clearance wins", func(t *testing.T) {
+		status := Withdrawal(1, []models.TreatmentPlanSummary{
+			plan(1, 48, 28, at(-5), at(2)),
+			plan(2, 96, 0, at(3), at(-1)),
+			plan(3, 24, 0, at(-10), at(-10)),
+		}, now)
+		assert.False(t, status.ClearForCompetition)
+		assert.Equal(t, at(3), status.CompetitionClearAt)
+		assert.False(t, status.ClearForSlaughter)
+		assert.Equal(t, at(2), status.SlaughterClearAt)
+		assert.Equal(t, []uint{1, 2}, status.BlockingPlans)
+	})
+
+	t.Run("ongoing plan leaves clearance open", func(t *testing.T) {
+		status := Withdrawal(1, []models.TreatmentPlanSummary{plan(1, 48, 0, at(2), nil), plan(2, 48, 0, nil, nil)}, now)
+		assert.False(t, status.ClearForCompetition)
+		assert.Nil(t, status.CompetitionClearAt)
+	})
+
+	t.Run("food chain exclusion is permanent", func(t *testing.T) {
+		excluded := plan(1, 0, 0, at(-100), at(-100))
+		excluded.FoodChainExcluded = true
+		status := Withdrawal(1, []models.TreatmentPlanSummary{excluded}, now)
+		assert.True(t, status.FoodChainExcluded)
+		assert.True(t, status.ClearForCompetition)
+		assert.False(t, status.ClearForSlaughter)
+		assert.Nil(t, status.SlaughterClearAt)
+	})
+}
@@ -44,76 +44,6 @@ var VitalSignRanges = map[string]VitalSigns{
 	},
 }
 
-// HealthAlert represents different types of health concerns
-type HealthAlert struct {
-	Category    string
-	Symptoms    []string
-	Urgency     int // 1-5, with 5 being most urgent
-	Action      string
-	Prevention  []string
-}
-
-var HealthAlerts = map[string]HealthAlert{
-	"Colic": {
-		Category: "Digestive",
-		Symptoms: []string{
-			"Pawing at ground",
-			"Looking at flanks",
-			"Rolling or attempting to roll",
-			"Lack of appetite",
-			"Reduced or no manure production",
-			"Elevated heart rate",
-		},
-		Urgency: 5,
-		Action:  "Contact veterinarian immediately. Remove feed. Walk horse if safe to do so.",
-		Prevention: []string{
-			"Regular dental care",
-			"Consistent feeding schedule",
-			"Fresh, clean water available",
-			"Gradual feed changes",
-			"Regular parasite control",
-		},
-	},
-	"Laminitis": {
-		Category: "Hoof",
-		Symptoms: []string{
-			"Reluctance to move",
-			"Shifting weight",
-			"Heat in hooves",
-			"Strong digital pulse",
-			"Characteristic stance (leaning back)",
-		},
-		Urgency: 4,
-		Action:  "Contact veterinarian. Keep horse still. Apply cold therapy if acute.",
-		Prevention: []string{
-			"Proper diet management",
-			"Limited access to rich grass",
-			"Regular exercise",
-			"Weight management",
-			"Regular hoof care",
-		},
-	},
-	"Respiratory": {
-		Category: "Respiratory",
-		Symptoms: []string{
-			"Coughing",
-			"Nasal discharge",
-			"Increased breathing rate",
-			"Exercise intolerance",
-			"Abnormal breathing sounds",
-		},
-		Urgency: 3,
-		Action:  "Monitor temperature and breathing rate. Contact vet if symptoms persist.",
-		Prevention: []string{
-			"Dust-free environment",
-			"Good ventilation",
-			"Clean bedding",
-			"Regular vaccination",
-			"Avoid dusty feed",
-		},
-	},
-}
-
 // VaccinationSchedule represents recommended vaccination timelines
 type VaccinationSchedule struct {
 	VaccineName string
@@ -218,10 +148,7 @@ func GetHealthAssessment(horse models.Horse) struct {
 	SpecialCare       []string
 } {
 	// Determine vital signs category
-	category := "Adult"
-	if horse.ConceptionDate != nil && time.Since(*horse.ConceptionDate) > 226*24*time.Hour {
-		category = "PregnantLate"
-	}
+	category := VitalSignsCategory(horse, time.Now())
 
 	// Determine dental care schedule based on age
 	var dentalCare DentalCareSchedule
@@ -0,0 +1,170 @@
+package health
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/polyfant/hulta_pregnancy_app/internal/models"
+)
+
+const (
+	// NeonatalPeriod is how long after foaling a foal is monitored as a newborn
+	NeonatalPeriod = foalVitalSignsAge
+
+	// IgG thresholds in mg/dL for passive transfer of colostral antibodies
+	iggAdequate = 800
+	iggPartial  = 400
+
+	// MinNeonatalDailyGain is the least a healthy newborn foal gains per day
+	// in kg; most gain 1-1.5 kg
+	MinNeonatalDailyGain = 0.5
+)
+
+// neonatalDeadline is when a milestone is late and when it is time to call the vet
+type neonatalDeadline struct {
+	milestone models.NeonatalMilestone
+	name      string
+	due       time.Duration
+	urgent    time.Duration
+	late      string
+	critical  string
+}
+
+// neonatalDeadlines follow the 1-2-3 rule: standing within an hour of birth
+// and nursing within two, with the vet called if the foal has not nursed by three
+var neonatalDeadlines = []neonatalDeadline{
+	{
+		milestone: models.NeonatalStand,
+		name:      "Standing",
+		due:       time.Hour,
+		urgent:    2 * time.Hour,
+		late:      "Most foals stand within an hour. Keep the foal warm and dry and call your vet if it is not up by two hours.",
+		critical:  "Not standing two hours after birth. Call your vet now: weakness can mean prematurity, sepsis or neonatal maladjustment.",
+	},
+	{
+		milestone: models.NeonatalNurse,
+		name:      "Nursing",
+		due:       2 * time.Hour,
+		urgent:    3 * time.Hour,
+		late:      "Most foals nurse within two hours. Check the mare's udder and help the foal find the teat; call your vet if it has not nursed by three hours.",
+		critical:  "Not nursing three hours after birth. Call your vet now: the foal needs colostrum, by tube if necessary, before gut closure at about 24 hours.",
+	},
+	{
+		milestone: models.NeonatalMeconium,
+		name:      "Meconium passed",
+		due:       12 * time.Hour,
+		urgent:    24 * time.Hour,
+		late:      "Meconium is usually passed within 12 hours. Watch for straining and tail flagging; retained meconium is the commonest cause of colic in newborn foals.",
+		critical:  "No meconium 24 hours after birth. Call your vet: the foal may need an enema or further treatment.",
+	},
+	{
+		milestone: models.NeonatalUrinate,
+		name:      "Urinated",
+		due:       12 * time.Hour,
+		urgent:    24 * time.Hour,
+		late:      "Colts usually urinate within 6 hours and fillies within 11. Watch for straining or a swelling belly.",
+		critical:  "Not urinated 24 hours after birth. Call your vet now to rule out a ruptured bladder.",
+	},
+	{
+		milestone: models.NeonatalIgGTest,
+		name:      "IgG tested",
+		due:       18 * time.Hour,
+		urgent:    24 * time.Hour,
+		late:      "Test IgG between 12 and 24 hours, while a low result can still be treated with colostrum.",
+		critical:  "No IgG result 24 hours after birth. Gut closure is complete by now, so ask your vet to test in case the foal needs plasma.",
+	},
+}
+
+// NeonatalMilestones checks each milestone of a foal's record at a given time
+func NeonatalMilestones(record models.NeonatalRecord, now time.Time) []models.NeonatalMilestoneCheck {
+	checks := make([]models.NeonatalMilestoneCheck, len(neonatalDeadlines))
+	for i, deadline := range neonatalDeadlines {
+		check := models.NeonatalMilestoneCheck{
+			Milestone: deadline.milestone,
+			Name:      deadline.name,
+			DueBy:     record.FoaledAt.Add(deadline.due),
+			UrgentBy:  record.FoaledAt.Add(deadline.urgent),
+			ReachedAt: record.ReachedAt(deadline.milestone),
+			Status:    models.NeonatalMilestonePending,
+		}
+		switch {
+		case check.ReachedAt != nil:
+			hours := math.Round(check.ReachedAt.Sub(record.FoaledAt).Hours()*10) / 10
+			check.HoursAfter = &hours
+			check.Status = models.NeonatalMilestoneDone
+		case !now.Before(check.UrgentBy):
+			check.Status = models.NeonatalMilestoneUrgent
+			check.Advice = deadline.critical
+		case !now.Before(check.DueBy):
+			check.Status = models.NeonatalMilestoneLate
+			check.Advice = deadline.late
+		}
+		checks[i] = check
+	}
+	return checks
+}
+
+// IgGStatusOf classifies an IgG result in mg/dL
+func IgGStatusOf(igg float64) models.IgGStatus {
+	switch {
+	case igg >= iggAdequate:
+		return models.IgGAdequate
+	case igg >= iggPartial:
+		return models.IgGPartial
+	}
+	return models.IgGFailure
+}
+
+// IgGAdvice is what to do about an IgG result, or "" when it is adequate
+func IgGAdvice(igg float64) string {
+	switch IgGStatusOf(igg) {
+	case models.IgGFailure:
+		return fmt.Sprintf("IgG %.0f mg/dL: failure of passive transfer. Call your vet; the foal is at high risk of infection and will likely need a plasma transfusion.", igg)
+	case models.IgGPartial:
+		return fmt.Sprintf("IgG %.0f mg/dL: partial failure of passive transfer. Ask your vet whether the foal needs plasma, especially if there is infection on the farm.", igg)
+	}
+	return ""
+}
+
+// ScoreApgar totals a modified APGAR assessment and interprets it
+func ScoreApgar(assessment models.NeonatalAssessment) (int, models.ApgarInterpretation, error) {
+	signs := []struct {
+		name  string
+		score models.ApgarScore
+	}{
+		{"heart_rate", assessment.HeartRate},
+		{"respiration", assessment.Respiration},
+		{"muscle_tone", assessment.MuscleTone},
+		{"nasal_response", assessment.NasalResponse},
+	}
+	total := 0
+	for _, sign := range signs {
+		if sign.score < 0 || sign.score > 2 {
+			return 0, "", fmt.Errorf("%s must score 0, 1 or 2, got %d", sign.name, sign.score)
+		}
+		total += int(sign.score)
+	}
+
+	switch {
+	case total >= 7:
+		return total, models.ApgarNormal, nil
+	case total >= 4:
+		return total, models.ApgarModerate, nil
+	}
+	return total, models.ApgarSevere, nil
+}
+
+// NeonatalDailyGain is the average daily gain in kg between the first
+// weighing and the latest one at least a day later
+func NeonatalDailyGain(weights []models.WeightRecord) (float64, bool) {
+	if len(weights) < 2 {
+		return 0, false
+	}
+	first, last := weights[0], weights[len(weights)-1]
+	days := last.Date.Sub(first.Date).Hours() / 24
+	if days < 1 {
+		return 0, false
+	}
+	return math.Round((last.WeightKg-first.WeightKg)/days*100) / 100, true
+}
@@ -0,0 +1,127 @@
+package health
+
+import (
+	"testing"
+	"time"
+
+	"github.com/polyfant/hulta_pregnancy_app/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNeonatalMilestones(t *testing.T) {
+	foaled := time.Date(2024, 4, 10, 2, 0, 0, 0, time.UTC)
+	at := func(hours float64) *time.Time {
+		t := foaled.Add(time.Duration(hours * float64(time.Hour)))
+		return &t
+	}
+	status := func(checks []models.NeonatalMilestoneCheck, milestone models.NeonatalMilestone) models.NeonatalMilestoneCheck {
+		for _, check := range checks {
+			if check.Milestone == milestone {
+				return check
+			}
+		}
+		t.Fatalf("no check for %s", milestone)
+		return models.NeonatalMilestoneCheck{}
+	}
+
+	tests := []struct {
+		name      string
+		record    models.NeonatalRecord
+		hours     float64
+		milestone models.NeonatalMilestone
+		want      models.NeonatalMilestoneStatus
+	}{
+		{"not standing yet", models.NeonatalRecord{}, 0.5, models.NeonatalStand, models.NeonatalMilestonePending},
+		{"not standing by 1h", models.NeonatalRecord{}, 1, models.NeonatalStand, models.NeonatalMilestoneLate},
+		{"not standing by 2h", models.NeonatalRecord{}, 2.5, models.NeonatalStand, models.NeonatalMilestoneUrgent},
+		{"stood late", models.NeonatalRecord{StoodAt: at(1.5)}, 5, models.NeonatalStand, models.NeonatalMilestoneDone},
+		{"not nursing by 2h", models.NeonatalRecord{StoodAt: at(0.5)}, 2, models.NeonatalNurse, models.NeonatalMilestoneLate},
+		{"not nursing by 3h", models.NeonatalRecord{StoodAt: at(0.5)}, 3, models.NeonatalNurse, models.NeonatalMilestoneUrgent},
+		{"no meconium by 12h", models.NeonatalRecord{}, 12, models.NeonatalMeconium, models.NeonatalMilestoneLate},
+		{"not urinated by 24h", models.NeonatalRecord{}, 24, models.NeonatalUrinate, models.NeonatalMilestoneUrgent},
+		{"IgG not tested by 18h", models.NeonatalRecord{}, 20, models.NeonatalIgGTest, models.NeonatalMilestoneLate},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			record := tt.record
+			record.FoaledAt = foaled
+
+			checks := NeonatalMilestones(record, foaled.Add(time.Duration(tt.hours*float64(time.Hour))))
+
+			require.Len(t, checks, 5)
+			check := status(checks, tt.milestone)
+			assert.Equal(t, tt.want, check.Status)
+			if tt.want == models.NeonatalMilestoneLate || tt.want == models.NeonatalMilestoneUrgent {
+				assert.NotEmpty(t, check.Advice)
+			} else {
+				assert.Empty(t, check.Advice)
+			}
+		})
+	}
+
+	t.Run("hours after foaling", func(t *testing.T) {
+		checks := NeonatalMilestones(models.NeonatalRecord{FoaledAt: foaled, StoodAt: at(0.75)}, foaled.Add(time.Hour))
+		stand := status(checks, models.NeonatalStand)
+		require.NotNil(t, stand.HoursAfter)
+		assert.Equal(t, 0.8, *stand.HoursAfter)
+		assert.Equal(t, foaled.Add(time.Hour), stand.DueBy)
+		assert.Equal(t, foaled.Add(2*time.Hour), stand.UrgentBy)
+	})
+}
+
+func TestIgGStatusOf(t *testing.T) {
+	assert.Equal(t, models.IgGAdequate, IgGStatusOf(800))
+	assert.Equal(t, models.IgGPartial, IgGStatusOf(650))
+	assert.Equal(t, models.IgGPartial, IgGStatusOf(400))
+	assert.Equal(t, models.IgGFailure, IgGStatusOf(399))
+
+	assert.Empty(t, IgGAdvice(1200))
+	assert.Contains(t, IgGAdvice(300), "failure of passive transfer")
+	assert.Contains(t, IgGAdvice(600), "partial failure")
+}
+
+func TestScoreApgar(t *testing.T) {
+	tests := []struct {
+		name       string
+		assessment models.NeonatalAssessment
+		wantScore  int
+		want       models.ApgarInterpretation
+		wantErr    bool
+	}{
+		{"vigorous foal", models.NeonatalAssessment{HeartRate: 2, Respiration: 2, MuscleTone: 2, NasalResponse: 1}, 7, models.ApgarNormal, false},
+		{"moderate asphyxia", models.NeonatalAssessment{HeartRate: 2, Respiration: 1, MuscleTone: 1, NasalResponse: 1}, 5, models.ApgarModerate, false},
+		{"severe asphyxia", models.NeonatalAssessment{HeartRate: 1, Respiration: 1}, 2, models.ApgarSevere, false},
+		{"score out of range", models.NeonatalAssessment{HeartRate: 3}, 0, "", true},
+		{"negative score", models.NeonatalAssessment{MuscleTone: -1}, 0, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			score, interpretation, err := ScoreApgar(tt.assessment)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantScore, score)
+			assert.Equal(t, tt.want, interpretation)
+		})
+	}
+}
+
+func TestNeonatalDailyGain(t *testing.T) {
+	day := time.Date(2024, 4, 10, 8, 0, 0, 0, time.UTC)
+
+	gain, ok := NeonatalDailyGain([]models.WeightRecord{
+		{Date: day, WeightKg: 50},
+		{Date: day.AddDate(0, 0, 2), WeightKg: 52},
+		{Date: day.AddDate(0, 0, 4), WeightKg: 55},
+	})
+	require.True(t, ok)
+	assert.Equal(t, 1.25, gain)
+
+	_, ok = NeonatalDailyGain([]models.WeightRecord{{Date: day, WeightKg: 50}, {Date: day.Add(6 * time.Hour), WeightKg: 51}})
+	assert.False(t, ok, "weighings less than a day apart")
+}
@@ -1,7 +1,9 @@
 package health
 
 import (
+	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/polyfant/hulta_pregnancy_app/internal/logger"
@@ -35,22 +37,127 @@ const (
 // It takes into account factors such as:
 // - Horse weight and size
 // - Activity level
-// - Pregnancy status
-// - Environmental conditions
+// - Pregnancy status, and lactation after foaling
+// - Environmental conditions, and grazing when turned out
+// - Body condition and whether it is changing
 type NutritionService struct {
-	healthRepo repository.HealthRepository
-	horseRepo  repository.HorseRepository
+	healthRepo     repository.HealthRepository
+	horseRepo      repository.HorseRepository
+	bodyConditions repository.BodyConditionRepository
+	weights        repository.WeightRepository
+	pregnancies    repository.PregnancyRepository
+	weanings       repository.WeaningRepository
+	pastures       repository.PastureRepository
 }
 
-func NewNutritionService(healthRepo repository.HealthRepository, horseRepo repository.HorseRepository) *NutritionService {
+const (
+	// bodyConditionHistoryMonths is how much scoring history the feed recommendation looks at
+	bodyConditionHistoryMonths = 12
+	// maxDailyGrain matches the upper bound of FeedRequirements.Validate
+	maxDailyGrain = 5
+)
+
+func NewNutritionService(healthRepo repository.HealthRepository, horseRepo repository.HorseRepository, bodyConditions repository.BodyConditionRepository, weights repository.WeightRepository, pregnancies repository.PregnancyRepository, weanings repository.WeaningRepository, pastures repository.PastureRepository) *NutritionService {
 	return &NutritionService{
-		healthRepo: healthRepo,
-		horseRepo:  horseRepo,
+		healthRepo:     healthRepo,
+		horseRepo:      horseRepo,
+		bodyConditions: bodyConditions,
+		weights:        weights,
+		pregnancies:    pregnancies,
+		weanings:       weanings,
+		pastures:       pastures,
+	}
+}
+
+// RecommendFeed calculates the daily ration from the horse's latest weight
+// and adjusts it to its body condition: more energy for horses below target
+// or losing condition, fewer concentrates for horses above target. Grazing
+// takes the place of hay while the horse is turned out, and horses prone to
+// laminitis are warned off rich grass.
+func (s *NutritionService) RecommendFeed(ctx context.Context, horse *models.Horse, activity ActivityLevel) (*models.FeedRecommendation, error) {
+	latest, err := s.weights.GetLatest(ctx, horse.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get latest weight: %w", err)
+	}
+	weighed := *horse
+	weight, known := CurrentWeight(*horse, latest)
+	weighed.Weight = weight
+
+	lactation, err := s.lactation(ctx, horse, weight)
+	if err != nil {
+		return nil, err
+	}
+	lactationMonth := 0
+	if lactation != nil {
+		lactationMonth = lactation.Month
+	}
+	turnout, err := s.pastures.GetTurnout(ctx, horse.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get turnout: %w", err)
+	}
+	requirements, err := s.calculateRequirements(weighed, activity, lactationMonth, turnout)
+	if err != nil {
+		return nil, err
+	}
+
+	recommendation := &models.FeedRecommendation{
+		HorseID:      horse.ID,
+		WeightKg:     weight,
+		Requirements: requirements,
+		Lactation:    lactation,
+		Turnout:      turnout,
+		Adjustments:  []string{},
+	}
+	if !known {
+		recommendation.Adjustments = append(recommendation.Adjustments, "No weight on record; ration based on a 500 kg horse. Weigh or tape the horse to tailor the ration")
+	}
+	if lactation != nil {
+		recommendation.Adjustments = append(recommendation.Adjustments, fmt.Sprintf("Month %d of lactation: %.1f Mcal energy, %.0f g protein and %.0f litres of water a day", lactation.Month, lactation.Requirements.DigestibleEnergy, lactation.Requirements.CrudeProtein, lactation.WaterLitres))
+	}
+	if turnout != nil {
+		recommendation.Adjustments = append(recommendation.Adjustments, turnoutAdjustment(turnout, getCurrentSeason(), requirements.Hay))
+	}
+
+	now := timeNow()
+	since := now.AddDate(0, -bodyConditionHistoryMonths, 0)
+	records, err := s.bodyConditions.ListByHorse(ctx, horse.ID, &since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get body condition scores: %w", err)
+	}
+	recommendation.Warnings, err = s.laminitisWarnings(ctx, horse, turnout, records)
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		recommendation.Adjustments = append(recommendation.Adjustments, "No body condition score on record; score the horse to tailor the ration")
+		return recommendation, nil
 	}
+
+	var conception *time.Time
+	if horse.IsPregnant {
+		conception = horse.ConceptionDate
+	}
+	history := SummarizeBodyCondition(*horse, records, conception, now)
+	recommendation.BodyCondition = &history
+	adjusted, adjustments := s.adjustForBodyCondition(requirements, history, horse.IsPregnant)
+	recommendation.Requirements = adjusted
+	recommendation.Adjustments = append(recommendation.Adjustments, adjustments...)
+
+	if err := recommendation.Requirements.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid feed requirements: %w", err)
+	}
+	return recommendation, nil
 }
 
 // CalculateDailyFeedRequirements calculates feed requirements based on horse's condition
 func (s *NutritionService) CalculateDailyFeedRequirements(horse models.Horse, activity ActivityLevel) (models.FeedRequirements, error) {
+	return s.calculateRequirements(horse, activity, 0, nil)
+}
+
+// calculateRequirements is CalculateDailyFeedRequirements for a mare in the
+// given month of lactation, or none when it is 0, and a horse on the given
+// turnout, or kept in when it is nil
+func (s *NutritionService) calculateRequirements(horse models.Horse, activity ActivityLevel, lactationMonth int, turnout *models.Turnout) (models.FeedRequirements, error) {
 	if err := activity.Validate(); err != nil {
 		return models.FeedRequirements{}, err
 	}
@@ -66,8 +173,20 @@ func (s *NutritionService) CalculateDailyFeedRequirements(horse models.Horse, ac
 		baseRequirement = s.adjustForPregnancy(baseRequirement, stage)
 	}
 
-	// Adjust for seasonal changes
-	baseRequirement = s.adjustForSeason(baseRequirement, getCurrentSeason())
+	// Adjust for lactation if nursing a foal
+	if lactationMonth > 0 {
+		baseRequirement = s.adjustForLactation(baseRequirement, lactationMonth)
+	}
+
+	// Adjust for seasonal changes. Grazing replaces hay while turned out;
+	// without turnout data some summer grazing is assumed instead
+	season := getCurrentSeason()
+	if turnout != nil {
+		baseRequirement = s.adjustForWeather(baseRequirement, season)
+		baseRequirement = s.adjustForTurnout(baseRequirement, turnout, season)
+	} else {
+		baseRequirement = s.adjustForSeason(baseRequirement, season)
+	}
 
 	// Validate the final requirements
 	if err := baseRequirement.Validate(); err != nil {
@@ -117,6 +236,42 @@ func (s *NutritionService) adjustForPregnancy(base models.FeedRequirements, stag
 	return base
 }
 
+func (s *NutritionService) adjustForLactation(base models.FeedRequirements, month int) models.FeedRequirements {
+	if month <= 3 {
+		// Peak milk yield
+		base.Hay *= 1.25
+		base.Grain *= 1.5
+		base.Minerals *= 1.5
+	} else {
+		// Yield falling as the foal eats more
+		base.Hay *= 1.2
+		base.Grain *= 1.3
+		base.Minerals *= 1.3
+	}
+	base.Water *= lactationWaterFactor(month)
+	return base
+}
+
+// lactationWaterFactor is how much more a nursing mare drinks; milk is
+// nearly 90% water
+func lactationWaterFactor(month int) float64 {
+	if month <= 3 {
+		return 1.75
+	}
+	return 1.5
+}
+
+// RationMultipliers is how much more of each part of its usual ration a
+// horse needs for the stage of its pregnancy: 1 for every part when it is
+// not pregnant
+func (s *NutritionService) RationMultipliers(horse models.Horse) models.FeedRequirements {
+	multipliers := models.FeedRequirements{Hay: 1, Grain: 1, Minerals: 1, Water: 1}
+	if !horse.IsPregnant {
+		return multipliers
+	}
+	return s.adjustForPregnancy(multipliers, s.getPregnancyStage(horse))
+}
+
 func (s *NutritionService) getPregnancyStage(horse models.Horse) models.PregnancyStage {
 	if !horse.IsPregnant || horse.ConceptionDate == nil {
 		return ""
@@ -133,6 +288,44 @@ func (s *NutritionService) getPregnancyStage(horse models.Horse) models.Pregnanc
 	}
 }
 
+func (s *NutritionService) adjustForBodyCondition(base models.FeedRequirements, history models.BodyConditionHistory, pregnant bool) (models.FeedRequirements, []string) {
+	adjustments := []string{}
+	if history.Latest == nil {
+		return base, adjustments
+	}
+	score := *history.Latest
+
+	if score < history.TargetMin {
+		base.Hay *= 1.1
+		base.Grain *= 1.25
+		adjustments = append(adjustments, fmt.Sprintf("Score %.1f is below the target of %.0f-%.0f: forage up 10%%, concentrates up 25%%", score, history.TargetMin, history.TargetMax))
+	}
+	if history.Trend == models.BodyConditionLosing && score <= history.TargetMax {
+		base.Hay *= 1.05
+		base.Grain *= 1.15
+		reason := fmt.Sprintf("Losing %.1f points a month", -history.ChangePerMonth)
+		if pregnant {
+			reason += " during pregnancy"
+		}
+		adjustments = append(adjustments, reason+": forage up 5%, concentrates up 15%")
+	}
+
+	switch {
+	case score > history.TargetMax:
+		base.Grain *= 0.5
+		adjustments = append(adjustments, fmt.Sprintf("Score %.1f is above the target of %.0f-%.0f: concentrates halved, forage kept", score, history.TargetMin, history.TargetMax))
+	case score >= history.TargetMax && history.Trend == models.BodyConditionGaining:
+		base.Grain *= 0.75
+		adjustments = append(adjustments, "Gaining at the top of the target range: concentrates down 25%")
+	}
+
+	if base.Grain > maxDailyGrain {
+		base.Grain = maxDailyGrain
+		adjustments = append(adjustments, fmt.Sprintf("Concentrates capped at %d kg a day; add energy through forage or oil instead", maxDailyGrain))
+	}
+	return base, adjustments
+}
+
 func (s *NutritionService) adjustForActivity(base models.FeedRequirements, activity ActivityLevel) models.FeedRequirements {
 	switch activity {
 	case LightWork:
@@ -166,6 +359,17 @@ func getCurrentSeason() Season {
 }
 
 func (s *NutritionService) adjustForSeason(base models.FeedRequirements, season Season) models.FeedRequirements {
+	base = s.adjustForWeather(base, season)
+	if season == Summer {
+		// Slightly decrease hay due to available pasture
+		base.Hay *= 0.9
+	}
+	return base
+}
+
+// adjustForWeather is adjustForSeason without the summer grazing, for
+// horses whose grazing is known from their turnout
+func (s *NutritionService) adjustForWeather(base models.FeedRequirements, season Season) models.FeedRequirements {
 	switch season {
 	case Winter:
 		// Increase hay for warmth and energy
@@ -174,12 +378,25 @@ func (s *NutritionService) adjustForSeason(base models.FeedRequirements, season
 	case Summer:
 		// Increase water for hydration
 		base.Water *= 1.3
-		// Slightly decrease hay due to available pasture
-		base.Hay *= 0.9
 	}
 	return base
 }
 
+// ParseActivityLevel reads an activity level by name, as used in query strings
+func ParseActivityLevel(name string) (ActivityLevel, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", "maintenance":
+		return Maintenance, nil
+	case "light":
+		return LightWork, nil
+	case "moderate":
+		return ModerateWork, nil
+	case "heavy":
+		return HeavyWork, nil
+	}
+	return Maintenance, fmt.Errorf("invalid activity level: %q", name)
+}
+
 func (a ActivityLevel) Validate() error {
 	if a < Maintenance || a > HeavyWork {
 		return fmt.Errorf("invalid activity level: %d", a)
@@ -44,6 +44,11 @@ func (m *mockHealthRepo) GetRecords(ctx context.Context, horseID uint) ([]models
 	return args.Get(0).([]models.HealthRecord), args.Error(1)
 }
 
+func (m *mockHealthRepo) FindRecords(ctx context.Context, horseID uint, filter models.HealthRecordFilter) ([]models.HealthRecord, error) {
+	args := m.Called(ctx, horseID, filter)
+	return args.Get(0).([]models.HealthRecord), args.Error(1)
+}
+
 func (m *mockHealthRepo) UpdateRecord(ctx context.Context, record *models.HealthRecord) error {
 	args := m.Called(ctx, record)
 	return args.Error(0)
@@ -71,6 +76,31 @@ func (m *mockHorseRepo) ListByUser(ctx context.Context, userID string) ([]models
 	return args.Get(0).([]models.Horse), args.Error(1)
 }
 
+func (m *mockHorseRepo) ListAllByUser(ctx context.Context, userID string) ([]models.Horse, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]models.Horse), args.Error(1)
+}
+
+func (m *mockHorseRepo) ListActive(ctx context.Context) ([]models.Horse, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]models.Horse), args.Error(1)
+}
+
+func (m *mockHorseRepo) UpdateStatus(ctx context.Context, id uint, status models.HorseStatus, date time.Time, reason string) error {
+	args := m.Called(ctx, id, status, date, reason)
+	return args.Error(0)
+}
+
+func (m *mockHorseRepo) CreateBatch(ctx context.Context, items []models.HorseImportItem) error {
+	args := m.Called(ctx, items)
+	return args.Error(0)
+}
+
+func (m *mockHorseRepo) ListByOrganization(ctx context.Context, orgID uint) ([]models.Horse, error) {
+	args := m.Called(ctx, orgID)
+	return args.Get(0).([]models.Horse), args.Error(1)
+}
+
 func (m *mockHorseRepo) Create(ctx context.Context, horse *models.Horse) error {
 	args := m.Called(ctx, horse)
 	return args.Error(0)
@@ -202,7 +232,7 @@ func TestCalculateDailyFeedRequirements(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			healthRepo := &mockHealthRepo{}
 			horseRepo := &mockHorseRepo{}
-			s := NewNutritionService(healthRepo, horseRepo)
+			s := NewNutritionService(healthRepo, horseRepo, nil, nil, nil, nil, nil)
 
 			got, err := s.CalculateDailyFeedRequirements(tt.horse, tt.activity)
 			if tt.wantErr {
@@ -306,4 +336,194 @@ func TestAdjustForSeason(t *testing.T) {
 			assertFeedRequirementsEqual(t, tt.want, got)
 		})
 	}
-} 
\ No newline at end of file
+} 
+type mockBodyConditionRepo struct {
+	mock.Mock
+}
+
+func (m *mockBodyConditionRepo) Create(ctx context.Context, record *models.BodyCondition) error {
+	args := m.Called(ctx, record)
+	return args.Error(0)
+}
+
+func (m *mockBodyConditionRepo) ListByHorse(ctx context.Context, horseID uint, since *time.Time) ([]models.BodyCondition, error) {
+	args := m.Called(ctx, horseID, since)
+	return args.Get(0).([]models.BodyCondition), args.Error(1)
+}
+
+type mockWeightRepo struct {
+	mock.Mock
+}
+
+func (m *mockWeightRepo) Create(ctx context.Context, record *models.WeightRecord) error {
+	args := m.Called(ctx, record)
+	return args.Error(0)
+}
+
+func (m *mockWeightRepo) ListByHorse(ctx context.Context, horseID uint, since *time.Time) ([]models.WeightRecord, error) {
+	args := m.Called(ctx, horseID, since)
+	return args.Get(0).([]models.WeightRecord), args.Error(1)
+}
+
+func (m *mockWeightRepo) GetLatest(ctx context.Context, horseID uint) (*models.WeightRecord, error) {
+	args := m.Called(ctx, horseID)
+	if record, ok := args.Get(0).(*models.WeightRecord); ok {
+		return record, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func TestRecommendFeed(t *testing.T) {
+	oldTimeNow := timeNow
+	fixedTime := time.Date(2024, time.April, 15, 12, 0, 0, 0, time.UTC)
+	timeNow = func() time.Time { return fixedTime }
+	defer func() { timeNow = oldTimeNow }()
+
+	scores := func(values ...float64) []models.BodyCondition {
+		records := make([]models.BodyCondition, len(values))
+		for i, v := range values {
+			records[i] = models.BodyCondition{Date: fixedTime.AddDate(0, 0, -30*(len(values)-1-i)), Score: v}
+		}
+		return records
+	}
+
+	tests := []struct {
+		name            string
+		activity        ActivityLevel
+		records         []models.BodyCondition
+		wantHay         float64
+		wantGrain       float64
+		wantAdjustments int
+		wantTrend       models.BodyConditionTrend
+	}{
+		{name: "no scores on record", activity: Maintenance, wantHay: 10, wantGrain: 2.5, wantAdjustments: 1},
+		{name: "within target", activity: Maintenance, records: scores(5, 5), wantHay: 10, wantGrain: 2.5, wantTrend: models.BodyConditionStable},
+		{name: "below target", activity: Maintenance, records: scores(3.5, 3.5), wantHay: 11, wantGrain: 3.125, wantAdjustments: 1, wantTrend: models.BodyConditionStable},
+		{name: "losing condition", activity: Maintenance, records: scores(6, 5.5, 5), wantHay: 10.5, wantGrain: 2.875, wantAdjustments: 1, wantTrend: models.BodyConditionLosing},
+		{name: "above target", activity: Maintenance, records: scores(7.5, 7.5), wantHay: 10, wantGrain: 1.25, wantAdjustments: 1, wantTrend: models.BodyConditionStable},
+		{name: "thin and losing in heavy work is capped", activity: HeavyWork, records: scores(4.5, 4, 3.5), wantHay: 15.015, wantGrain: 5, wantAdjustments: 3, wantTrend: models.BodyConditionLosing},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			horse := &models.Horse{ID: 1, Weight: 500}
+			bodyConditions := new(mockBodyConditionRepo)
+			bodyConditions.On("ListByHorse", ctx, horse.ID, mock.Anything).Return(tt.records, nil)
+			weights := new(mockWeightRepo)
+			weights.On("GetLatest", ctx, horse.ID).Return(nil, nil)
+			service := NewNutritionService(new(mockHealthRepo), new(mockHorseRepo), bodyConditions, weights, noFoaling(ctx, horse.ID), nil, keptIn(ctx, horse.ID))
+
+			got, err := service.RecommendFeed(ctx, horse, tt.activity)
+
+			assert.NoError(t, err)
+			assert.InDelta(t, tt.wantHay, got.Requirements.Hay, 0.001)
+			assert.InDelta(t, tt.wantGrain, got.Requirements.Grain, 0.001)
+			assert.Len(t, got.Adjustments, tt.wantAdjustments)
+			if tt.records == nil {
+				assert.Nil(t, got.BodyCondition)
+				return
+			}
+			assert.Equal(t, tt.wantTrend, got.BodyCondition.Trend)
+		})
+	}
+}
+
+func TestRecommendFeed_Weight(t *testing.T) {
+	oldTimeNow := timeNow
+	fixedTime := time.Date(2024, time.April, 15, 12, 0, 0, 0, time.UTC)
+	timeNow = func() time.Time { return fixedTime }
+	defer func() { timeNow = oldTimeNow }()
+
+	tests := []struct {
+		name            string
+		horseWeight     float64
+		latest          *models.WeightRecord
+		wantWeight      float64
+		wantHay         float64
+		wantAdjustments int
+	}{
+		{name: "latest weighing wins over the horse record", horseWeight: 500, latest: &models.WeightRecord{WeightKg: 400}, wantWeight: 400, wantHay: 8, wantAdjustments: 1},
+		{name: "horse record when never weighed", horseWeight: 450, wantWeight: 450, wantHay: 9, wantAdjustments: 1},
+		{name: "default weight is flagged", wantHay: 10, wantAdjustments: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			horse := &models.Horse{ID: 1, Weight: tt.horseWeight}
+			bodyConditions := new(mockBodyConditionRepo)
+			bodyConditions.On("ListByHorse", ctx, horse.ID, mock.Anything).Return([]models.BodyCondition(nil), nil)
+			weights := new(mockWeightRepo)
+			weights.On("GetLatest", ctx, horse.ID).Return(tt.latest, nil)
+			service := NewNutritionService(new(mockHealthRepo), new(mockHorseRepo), bodyConditions, weights, noFoaling(ctx, horse.ID), nil, keptIn(ctx, horse.ID))
+
+			got, err := service.RecommendFeed(ctx, horse, Maintenance)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantWeight, got.WeightKg)
+			assert.InDelta(t, tt.wantHay, got.Requirements.Hay, 0.001)
+			assert.Len(t, got.Adjustments, tt.wantAdjustments)
+			assert.Equal(t, tt.horseWeight, horse.Weight, "horse passed in is left alone")
+		})
+	}
+}
+
+func TestRationMultipliers(t *testing.T) {
+	tests := []struct {
+		name  string
+		horse models.Horse
+		want  models.FeedRequirements
+	}{
+		{
+			name:  "not pregnant",
+			horse: models.Horse{},
+			want:  models.FeedRequirements{Hay: 1, Grain: 1, Minerals: 1, Water: 1},
+		},
+		{
+			name:  "early gestation",
+			horse: models.Horse{IsPregnant: true, ConceptionDate: timePtr(time.Now().AddDate(0, -2, 0))},
+			want:  models.FeedRequirements{Hay: 1.1, Grain: 1, Minerals: 1.2, Water: 1},
+		},
+		{
+			name:  "late gestation",
+			horse: models.Horse{IsPregnant: true, ConceptionDate: timePtr(time.Now().AddDate(0, -9, 0))},
+			want:  models.FeedRequirements{Hay: 1.3, Grain: 1.2, Minerals: 1.5, Water: 1.2},
+		},
+	}
+
+	s := NewNutritionService(nil, nil, nil, nil, nil, nil, nil)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := s.RationMultipliers(tt.horse)
+			assert.InDelta(t, tt.want.Hay, got.Hay, 0.001)
+			assert.InDelta(t, tt.want.Grain, got.Grain, 0.001)
+			assert.InDelta(t, tt.want.Minerals, got.Minerals, 0.001)
+			assert.InDelta(t, tt.want.Water, got.Water, 0.001)
+		})
+	}
+}
+
+func TestParseActivityLevel(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    ActivityLevel
+		wantErr bool
+	}{
+		{name: "", want: Maintenance},
+		{name: "light", want: LightWork},
+		{name: "Moderate", want: ModerateWork},
+		{name: "heavy", want: HeavyWork},
+		{name: "racing", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseActivityLevel(tt.name)
+		if tt.wantErr {
+			assert.Error(t, err)
+			continue
+		}
+		assert.NoError(t, err)
+		assert.Equal(t, tt.want, got)
+	}
+}
@@ -0,0 +1,248 @@
+package health
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/polyfant/hulta_pregnancy_app/internal/models"
+)
+
+const (
+	// LowShedderMaxEPG and HighShedderMinEPG are the strongyle egg count cut-offs for shedder classes
+	LowShedderMaxEPG  = 200
+	HighShedderMinEPG = 500
+
+	// minReductionTestEPG is the lowest pre-treatment count that gives a meaningful reduction test
+	minReductionTestEPG = 200
+	// preTreatmentWindowDays is how long before a deworming the pre-treatment sample may be taken
+	preTreatmentWindowDays = 14
+	// postTreatmentFrom and postTreatmentTo bound the follow-up sample, in days after deworming
+	postTreatmentFrom = 10
+	postTreatmentTo   = 17
+	// shedderSamples is how many recent counts are used to classify a horse
+	shedderSamples = 4
+	// adultParasiteAge is the age in years from which horses are classified by their counts
+	adultParasiteAge = 3
+	// defaultReappearanceWeeks is used for dewormers of an unknown class
+	defaultReappearanceWeeks = 8
+)
+
+// DewormerClass groups dewormers that share a mode of action, and so share resistance
+type DewormerClass struct {
+	Name              string
+	Ingredients       []string
+	ResistantBelow    float64 // Reduction percentage under which resistance is confirmed
+	EffectiveFrom     float64 // Reduction percentage expected from a dewormer that works
+	ReappearanceWeeks int     // Weeks before eggs reappear after treatment
+}
+
+var DewormerClasses = []DewormerClass{
+	{Name: "Macrocyclic lactone", Ingredients: []string{"ivermectin", "abamectin"}, ResistantBelow: 95, EffectiveFrom: 98, ReappearanceWeeks: 8},
+	{Name: "Macrocyclic lactone", Ingredients: []string{"moxidectin"}, ResistantBelow: 95, EffectiveFrom: 98, ReappearanceWeeks: 12},
+	{Name: "Benzimidazole", Ingredients: []string{"fenbendazole", "oxibendazole", "mebendazole"}, ResistantBelow: 90, EffectiveFrom: 95, ReappearanceWeeks: 4},
+	{Name: "Tetrahydropyrimidine", Ingredients: []string{"pyrantel"}, ResistantBelow: 85, EffectiveFrom: 90, ReappearanceWeeks: 5},
+}
+
+// shedderIntervals is the adult deworming interval in months per shedder level
+var shedderIntervals = map[models.ShedderLevel]int{
+	models.ShedderUnknown:  6,
+	models.ShedderLow:      6,
+	models.ShedderModerate: 4,
+	models.ShedderHigh:     3,
+}
+
+var shedderLabels = map[models.ShedderLevel]string{
+	models.ShedderLow:      "Low",
+	models.ShedderModerate: "Moderate",
+	models.ShedderHigh:     "High",
+}
+
+// RecommendDeworming classifies the horse from its strongyle counts, runs a
+// reduction test for every deworming with counts before and after it, and
+// recommends how often the horse should be dewormed
+func RecommendDeworming(horse models.Horse, counts []models.FecalEggCount, records []models.HealthRecord, now time.Time) models.DewormingRecommendation {
+	var dewormings []models.HealthRecord
+	for _, record := range records {
+		if models.HealthRecordType(strings.ToUpper(record.Type)) == models.HealthRecordTypeDeworming {
+			dewormings = append(dewormings, record)
+		}
+	}
+	dewormings = sortByDate(dewormings)
+
+	var strongyles []models.FecalEggCount
+	for _, count := range counts {
+		if count.ParasiteType == models.ParasiteStrongyle {
+			strongyles = append(strongyles, count)
+		}
+	}
+	sort.SliceStable(strongyles, func(i, j int) bool { return strongyles[i].SampleDate.Before(strongyles[j].SampleDate) })
+
+	rec := models.DewormingRecommendation{
+		HorseID:        horse.ID,
+		ReductionTests: ReductionTests(strongyles, dewormings),
+	}
+	rec.AvoidDrugs = resistantClasses(rec.ReductionTests)
+	rec.ShedderLevel, rec.TypicalEPG = ClassifyShedder(strongyles, dewormings)
+
+	if !horse.BirthDate.IsZero() && horse.BirthDate.AddDate(adultParasiteAge, 0, 0).After(now) {
+		// Young horses have not built immunity yet, so counts do not predict their needs
+		rec.IntervalMonths = 3
+		rec.Reason = fmt.Sprintf("Horses under %d years are dewormed like high shedders", adultParasiteAge)
+		if horse.BirthDate.AddDate(1, 0, 0).After(now) {
+			rec.IntervalMonths = 2
+			rec.Reason = "Foals every 2 months until yearling"
+		}
+	} else {
+		rec.IntervalMonths = shedderIntervals[rec.ShedderLevel]
+		switch rec.ShedderLevel {
+		case models.ShedderUnknown:
+			rec.Reason = "Every 6 months until a fecal egg count classifies the horse"
+		default:
+			rec.Reason = fmt.Sprintf("%s shedder (%d EPG): every %d months",
+				shedderLabels[rec.ShedderLevel], *rec.TypicalEPG, rec.IntervalMonths)
+		}
+	}
+
+	if len(rec.AvoidDrugs) > 0 {
+		rec.Reason += "; avoid " + strings.Join(rec.AvoidDrugs, ", ") + " dewormers"
+	}
+	return rec
+}
+
+// ClassifyShedder uses the median of the latest strongyle counts taken
+// outside a dewormer's egg reappearance period, since counts taken while a
+// dewormer still works say nothing about the horse itself
+func ClassifyShedder(strongyles []models.FecalEggCount, dewormings []models.HealthRecord) (models.ShedderLevel, *int) {
+	var qualifying []int
+	for i := len(strongyles) - 1; i >= 0 && len(qualifying) < shedderSamples; i-- {
+		if !suppressedByDewormer(strongyles[i].SampleDate, dewormings) {
+			qualifying = append(qualifying, strongyles[i].EggsPerGram)
+		}
+	}
+	if len(qualifying) == 0 {
+		return models.ShedderUnknown, nil
+	}
+
+	sort.Ints(qualifying)
+	median := qualifying[len(qualifying)/2]
+	if len(qualifying)%2 == 0 {
+		median = (qualifying[len(qualifying)/2-1] + median) / 2
+	}
+
+	switch {
+	case median < LowShedderMaxEPG:
+		return models.ShedderLow, &median
+	case median <= HighShedderMinEPG:
+		return models.ShedderModerate, &median
+	default:
+		return models.ShedderHigh, &median
+	}
+}
+
+// ReductionTests pairs each deworming with a strongyle count taken up to two
+// weeks before it and a follow-up count 10-17 days after it
+func ReductionTests(strongyles []models.FecalEggCount, dewormings []models.HealthRecord) []models.EggCountReductionTest {
+	tests := []models.EggCountReductionTest{}
+	for _, treatment := range dewormings {
+		var pre, post *models.FecalEggCount
+		for i := range strongyles {
+			count := &strongyles[i]
+			days := count.SampleDate.Sub(treatment.Date).Hours() / 24
+			switch {
+			case days <= 0 && days >= -preTreatmentWindowDays:
+				pre = count // Latest sample before treatment wins
+			case days >= postTreatmentFrom && days <= postTreatmentTo && post == nil:
+				post = count
+			}
+		}
+		if pre == nil || post == nil || pre.EggsPerGram < minReductionTestEPG {
+			continue
+		}
+
+		class, ingredient := dewormerClass(dewormingIngredient(treatment))
+		reduction := float64(pre.EggsPerGram-post.EggsPerGram) / float64(pre.EggsPerGram) * 100
+		test := models.EggCountReductionTest{
+			TreatmentDate:    treatment.Date,
+			ActiveIngredient: ingredient,
+			PreTreatmentEPG:  pre.EggsPerGram,
+			PostTreatmentEPG: post.EggsPerGram,
+			ReductionPercent: math.Round(reduction*10) / 10,
+			Result:           models.ReductionEffective,
+		}
+
+		resistantBelow, effectiveFrom := 90.0, 95.0
+		if class != nil {
+			test.DrugClass = class.Name
+			resistantBelow, effectiveFrom = class.ResistantBelow, class.EffectiveFrom
+		}
+		switch {
+		case test.ReductionPercent < resistantBelow:
+			test.Result = models.ReductionResistant
+		case test.ReductionPercent < effectiveFrom:
+			test.Result = models.ReductionSuspectedResistance
+		}
+		tests = append(tests, test)
+	}
+	return tests
+}
+
+// resistantClasses lists drug classes whose latest reduction test showed resistance
+func resistantClasses(tests []models.EggCountReductionTest) []string {
+	latest := map[string]models.EggCountReductionResult{}
+	var order []string
+	for _, test := range tests {
+		if test.DrugClass == "" {
+			continue
+		}
+		if _, seen := latest[test.DrugClass]; !seen {
+			order = append(order, test.DrugClass)
+		}
+		latest[test.DrugClass] = test.Result
+	}
+
+	var resistant []string
+	for _, class := range order {
+		if latest[class] == models.ReductionResistant {
+			resistant = append(resistant, class)
+		}
+	}
+	return resistant
+}
+
+func suppressedByDewormer(sampleDate time.Time, dewormings []models.HealthRecord) bool {
+	for _, treatment := range dewormings {
+		weeks := defaultReappearanceWeeks
+		if class, _ := dewormerClass(dewormingIngredient(treatment)); class != nil {
+			weeks = class.ReappearanceWeeks
+		}
+		if sampleDate.After(treatment.Date) && sampleDate.Before(treatment.Date.AddDate(0, 0, weeks*7)) {
+			return true
+		}
+	}
+	return false
+}
+
+// dewormingIngredient reads the active ingredient from the typed payload,
+// falling back to the description of older records
+func dewormingIngredient(record models.HealthRecord) string {
+	if record.Details != nil && record.Details.Deworming != nil {
+		return strings.ToLower(record.Details.Deworming.ActiveIngredient)
+	}
+	return strings.ToLower(record.Description)
+}
+
+// dewormerClass finds the class of the ingredient named in text and returns
+// it with the ingredient name, or nil and text itself if it is not known
+func dewormerClass(text string) (*DewormerClass, string) {
+	for i, class := range DewormerClasses {
+		for _, name := range class.Ingredients {
+			if strings.Contains(text, name) {
+				return &DewormerClasses[i], name
+			}
+		}
+	}
+	return nil, text
+}
@@ -0,0 +1,134 @@
+package health
+
+import (
+	"testing"
+	"time"
+
+	"github.com/polyfant/hulta_pregnancy_app/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClassifyShedder(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	count := func(monthsAgo, epg int) models.FecalEggCount {
+		return models.FecalEggCount{SampleDate: now.AddDate(0, -monthsAgo, 0), EggsPerGram: epg, ParasiteType: models.ParasiteStrongyle}
+	}
+
+	tests := []struct {
+		name       string
+		counts     []models.FecalEggCount
+		dewormings []models.HealthRecord
+		want       models.ShedderLevel
+		wantEPG    int
+	}{
+		{name: "no counts", want: models.ShedderUnknown},
+		{name: "low shedder", counts: []models.FecalEggCount{count(6, 50), count(3, 150)}, want: models.ShedderLow, wantEPG: 100},
+		{name: "moderate shedder", counts: []models.FecalEggCount{count(9, 200), count(6, 350), count(3, 400)}, want: models.ShedderModerate, wantEPG: 350},
+		{name: "high shedder", counts: []models.FecalEggCount{count(6, 900), count(3, 1200)}, want: models.ShedderHigh, wantEPG: 1050},
+		{
+			name:       "counts during the egg reappearance period are ignored",
+			counts:     []models.FecalEggCount{count(6, 800), count(1, 0)},
+			dewormings: []models.HealthRecord{{Type: "DEWORMING", Date: now.AddDate(0, -2, 0), Description: "Moxidectin gel"}},
+			want:       models.ShedderHigh,
+			wantEPG:    800,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			level, epg := ClassifyShedder(tt.counts, tt.dewormings)
+			assert.Equal(t, tt.want, level)
+			if tt.want == models.ShedderUnknown {
+				assert.Nil(t, epg)
+				return
+			}
+			require.NotNil(t, epg)
+			assert.Equal(t, tt.wantEPG, *epg)
+		})
+	}
+}
+
+func TestReductionTests(t *testing.T) {
+	treated := time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+	deworming := func(ingredient string) models.HealthRecord {
+		return models.HealthRecord{
+			Type: "DEWORMING", Date: treated,
+			Details: &models.HealthRecordDetails{Deworming: &models.DewormingDetails{ActiveIngredient: ingredient, Dose: 1, DoseUnit: "tube"}},
+		}
+	}
+	counts := func(pre, post int) []models.FecalEggCount {
+		return []models.FecalEggCount{
+			{SampleDate: treated.AddDate(0, 0, -3), EggsPerGram: pre, ParasiteType: models.ParasiteStrongyle},
+			{SampleDate: treated.AddDate(0, 0, 14), EggsPerGram: post, ParasiteType: models.ParasiteStrongyle},
+		}
+	}
+
+	tests := []struct {
+		name       string
+		counts     []models.FecalEggCount
+		treatment  models.HealthRecord
+		wantResult models.EggCountReductionResult
+		wantClass  string
+		wantCount  int
+	}{
+		{"ivermectin works", counts(800, 0), deworming("Ivermectin"), models.ReductionEffective, "Macrocyclic lactone", 1},
+		{"fenbendazole resistance", counts(800, 240), deworming("Fenbendazole"), models.ReductionResistant, "Benzimidazole", 1},
+		{"pyrantel suspected", counts(1000, 120), deworming("Pyrantel pamoate"), models.ReductionSuspectedResistance, "Tetrahydropyrimidine", 1},
+		{"pre-treatment count too low", counts(100, 50), deworming("Ivermectin"), "", "", 0},
+		{"no follow-up count", counts(800, 0)[:1], deworming("Ivermectin"), "", "", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results := ReductionTests(tt.counts, []models.HealthRecord{tt.treatment})
+			require.Len(t, results, tt.wantCount)
+			if tt.wantCount == 0 {
+				return
+			}
+			assert.Equal(t, tt.wantResult, results[0].Result)
+			assert.Equal(t, tt.wantClass, results[0].DrugClass)
+		})
+	}
+}
+
+func TestRecommendDeworming(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	adult := models.Horse{ID: 3, BirthDate: now.AddDate(-10, 0, 0)}
+	treated := now.AddDate(0, -4, 0)
+	counts := []models.FecalEggCount{
+		{SampleDate: treated.AddDate(0, 0, -2), EggsPerGram: 700, ParasiteType: models.ParasiteStrongyle},
+		{SampleDate: treated.AddDate(0, 0, 12), EggsPerGram: 350, ParasiteType: models.ParasiteStrongyle},
+		{SampleDate: now.AddDate(0, 0, -7), EggsPerGram: 650, ParasiteType: models.ParasiteStrongyle},
+		{SampleDate: now.AddDate(0, 0, -7), EggsPerGram: 2000, ParasiteType: models.ParasiteAscarid},
+	}
+	records := []models.HealthRecord{{Type: "DEWORMING", Date: treated, Description: "Fenbendazole 5 day"}}
+
+	t.Run("adult high shedder with resistance", func(t *testing.T) {
+		rec := RecommendDeworming(adult, counts, records, now)
+		assert.Equal(t, models.ShedderHigh, rec.ShedderLevel)
+		assert.Equal(t, 3, rec.IntervalMonths)
+		assert.Equal(t, []string{"Benzimidazole"}, rec.AvoidDrugs)
+		assert.Contains(t, rec.Reason, "avoid Benzimidazole")
+	})
+
+	t.Run("young horse ignores classification", func(t *testing.T) {
+		young := models.Horse{BirthDate: now.AddDate(-2, 0, 0)}
+		rec := RecommendDeworming(young, counts, records, now)
+		assert.Equal(t, 3, rec.IntervalMonths)
+	})
+
+	t.Run("unclassified adult", func(t *testing.T) {
+		rec := RecommendDeworming(adult, nil, nil, now)
+		assert.Equal(t, models.ShedderUnknown, rec.ShedderLevel)
+		assert.Equal(t, 6, rec.IntervalMonths)
+		assert.Empty(t, rec.ReductionTests)
+	})
+
+	t.Run("due date follows the recommended interval", func(t *testing.T) {
+		items := ComputeDueItems(adult, records, counts, now)
+		deworming := findDue(items, "Deworming")
+		assert.Equal(t, treated.AddDate(0, 3, 0), deworming.DueDate)
+		assert.Equal(t, models.HealthDueOverdue, deworming.Status)
+	})
+}
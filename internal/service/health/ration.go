@@ -0,0 +1,304 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/polyfant/hulta_pregnancy_app/internal/models"
+)
+
+// Nutrient requirements per kg of body weight a day, after the NRC Nutrient
+// Requirements of Horses (2007). Lysine follows crude protein.
+var (
+	maintenanceNutrients = models.Nutrients{
+		DigestibleEnergy: 0.0333,
+		CrudeProtein:     1.26,
+		Calcium:          0.04,
+		Phosphorus:       0.028,
+		Copper:           0.2,
+		Zinc:             0.8,
+		Selenium:         0.002,
+	}
+
+	workNutrients = map[ActivityLevel]models.Nutrients{
+		LightWork:    {DigestibleEnergy: 0.0400, CrudeProtein: 1.39, Calcium: 0.06, Phosphorus: 0.036, Copper: 0.2, Zinc: 0.8, Selenium: 0.0025},
+		ModerateWork: {DigestibleEnergy: 0.0466, CrudeProtein: 1.54, Calcium: 0.07, Phosphorus: 0.042, Copper: 0.225, Zinc: 0.9, Selenium: 0.0025},
+		HeavyWork:    {DigestibleEnergy: 0.0533, CrudeProtein: 1.72, Calcium: 0.08, Phosphorus: 0.058, Copper: 0.25, Zinc: 1.0, Selenium: 0.0025},
+	}
+
+	// gestationNutrients is indexed by month of gestation; the first four
+	// months need no more than maintenance
+	gestationNutrients = [...]models.Nutrients{
+		5:  {DigestibleEnergy: 0.0343, CrudeProtein: 1.37, Calcium: 0.04, Phosphorus: 0.028, Copper: 0.2, Zinc: 0.8, Selenium: 0.002},
+		6:  {DigestibleEnergy: 0.0346, CrudeProtein: 1.37, Calcium: 0.04, Phosphorus: 0.028, Copper: 0.2, Zinc: 0.8, Selenium: 0.002},
+		7:  {DigestibleEnergy: 0.0356, CrudeProtein: 1.43, Calcium: 0.056, Phosphorus: 0.04, Copper: 0.2, Zinc: 0.8, Selenium: 0.002},
+		8:  {DigestibleEnergy: 0.0370, CrudeProtein: 1.50, Calcium: 0.056, Phosphorus: 0.04, Copper: 0.2, Zinc: 0.8, Selenium: 0.002},
+		9:  {DigestibleEnergy: 0.0383, CrudeProtein: 1.59, Calcium: 0.072, Phosphorus: 0.0526, Copper: 0.25, Zinc: 0.8, Selenium: 0.002},
+		10: {DigestibleEnergy: 0.0400, CrudeProtein: 1.71, Calcium: 0.072, Phosphorus: 0.0526, Copper: 0.25, Zinc: 0.8, Selenium: 0.002},
+		11: {DigestibleEnergy: 0.0423, CrudeProtein: 1.79, Calcium: 0.072, Phosphorus: 0.0526, Copper: 0.25, Zinc: 0.8, Selenium: 0.002},
+	}
+
+	// lactationNutrients is indexed by month of lactation. Milk yield, and
+	// with it the requirement, falls after the third month.
+	lactationNutrients = [...]models.Nutrients{
+		1: {DigestibleEnergy: 0.0634, CrudeProtein: 3.07, Calcium: 0.118, Phosphorus: 0.0766, Copper: 0.25, Zinc: 1.0, Selenium: 0.0025},
+		2: {DigestibleEnergy: 0.0620, CrudeProtein: 3.00, Calcium: 0.118, Phosphorus: 0.0766, Copper: 0.25, Zinc: 1.0, Selenium: 0.0025},
+		3: {DigestibleEnergy: 0.0610, CrudeProtein: 2.94, Calcium: 0.118, Phosphorus: 0.0766, Copper: 0.25, Zinc: 1.0, Selenium: 0.0025},
+		4: {DigestibleEnergy: 0.0563, CrudeProtein: 2.53, Calcium: 0.08, Phosphorus: 0.049, Copper: 0.25, Zinc: 1.0, Selenium: 0.0025},
+		5: {DigestibleEnergy: 0.0556, CrudeProtein: 2.48, Calcium: 0.08, Phosphorus: 0.049, Copper: 0.25, Zinc: 1.0, Selenium: 0.0025},
+		6: {DigestibleEnergy: 0.0546, CrudeProtein: 2.43, Calcium: 0.08, Phosphorus: 0.049, Copper: 0.25, Zinc: 1.0, Selenium: 0.0025},
+	}
+
+	// maximumNutrients are the most a horse tolerates per kg of body weight,
+	// from the maximum concentrations in the diet at a dry matter intake of
+	// 2% of body weight. Energy, protein and lysine have no set maximum.
+	maximumNutrients = map[string]float64{
+		"calcium":    0.4,
+		"phosphorus": 0.2,
+		"copper":     5,
+		"zinc":       10,
+		"selenium":   0.04,
+	}
+)
+
+const (
+	// Lysine as a share of crude protein; milk is richer in it
+	lysineShare          = 0.043
+	lactationLysineShare = 0.055
+
+	maxGestationMonth = 11
+
+	// A nutrient is short below deficitRatio of the requirement; energy
+	// above energyExcessRatio and protein above proteinExcessRatio put
+	// weight on
+	deficitRatio       = 0.9
+	energyExcessRatio  = 1.15
+	proteinExcessRatio = 1.5
+
+	// defaultWeightKg is used when the horse has never been weighed
+	defaultWeightKg = 500
+)
+
+// NutrientRequirements is what a horse of the given weight needs a day.
+// Work, gestation and lactation each set a requirement and the horse needs
+// the largest of them for every nutrient. Months are counted from 1; zero
+// means the mare is not in foal or not nursing. The states that applied are
+// returned with the requirement.
+func NutrientRequirements(weight float64, activity ActivityLevel, gestationMonth, lactationMonth int) (models.Nutrients, []string, error) {
+	if err := activity.Validate(); err != nil {
+		return models.Nutrients{}, nil, err
+	}
+	if gestationMonth < 0 || lactationMonth < 0 || lactationMonth > models.LactationMonths {
+		return models.Nutrients{}, nil, fmt.Errorf("invalid reproductive state: gestation month %d, lactation month %d", gestationMonth, lactationMonth)
+	}
+
+	required := withLysine(maintenanceNutrients, lysineShare)
+	states := []string{}
+	if work, ok := workNutrients[activity]; ok {
+		required = maxNutrients(required, withLysine(work, lysineShare))
+		states = append(states, activityName(activity))
+	}
+	if gestationMonth > 0 {
+		month := min(gestationMonth, maxGestationMonth)
+		if month >= 5 {
+			required = maxNutrients(required, withLysine(gestationNutrients[month], lysineShare))
+		}
+		states = append(states, fmt.Sprintf("gestation month %d", month))
+	}
+	if lactationMonth > 0 {
+		required = maxNutrients(required, withLysine(lactationNutrients[lactationMonth], lactationLysineShare))
+		states = append(states, fmt.Sprintf("lactation month %d", lactationMonth))
+	}
+	if len(states) == 0 {
+		states = append(states, "maintenance")
+	}
+	return required.Scale(weight), states, nil
+}
+
+func withLysine(n models.Nutrients, share float64) models.Nutrients {
+	n.Lysine = n.CrudeProtein * share
+	return n
+}
+
+func maxNutrients(a, b models.Nutrients) models.Nutrients {
+	return models.Nutrients{
+		DigestibleEnergy: math.Max(a.DigestibleEnergy, b.DigestibleEnergy),
+		CrudeProtein:     math.Max(a.CrudeProtein, b.CrudeProtein),
+		Lysine:           math.Max(a.Lysine, b.Lysine),
+		Calcium:          math.Max(a.Calcium, b.Calcium),
+		Phosphorus:       math.Max(a.Phosphorus, b.Phosphorus),
+		Copper:           math.Max(a.Copper, b.Copper),
+		Zinc:             math.Max(a.Zinc, b.Zinc),
+		Selenium:         math.Max(a.Selenium, b.Selenium),
+	}
+}
+
+func activityName(activity ActivityLevel) string {
+	switch activity {
+	case LightWork:
+		return "light work"
+	case ModerateWork:
+		return "moderate work"
+	case HeavyWork:
+		return "heavy work"
+	}
+	return "maintenance"
+}
+
+// RationNutrients totals what the ration supplies a day. Feeds with no
+// nutrient composition are returned by name and count for nothing.
+func RationNutrients(rations []models.FeedRation) (models.Nutrients, []string) {
+	var supplied models.Nutrients
+	unrated := []string{}
+	for _, ration := range rations {
+		if ration.FeedType == nil || ration.FeedType.Nutrients == nil {
+			name := fmt.Sprintf("feed %d", ration.FeedTypeID)
+			if ration.FeedType != nil {
+				name = ration.FeedType.Name
+			}
+			unrated = append(unrated, name)
+			continue
+		}
+		supplied = supplied.Add(ration.FeedType.Nutrients.Scale(asFedKg(ration.DailyAmount, ration.FeedType.Unit)))
+	}
+	return supplied, unrated
+}
+
+// asFedKg converts an amount in a feed's unit to the kg or litres its
+// composition is given per
+func asFedKg(amount float64, unit string) float64 {
+	switch unit {
+	case models.FeedUnitGram, models.FeedUnitMl:
+		return amount / 1000
+	}
+	return amount
+}
+
+// BalanceNutrients compares each nutrient supplied with the requirement and,
+// for minerals, with the most a horse of the weight tolerates
+func BalanceNutrients(required, supplied models.Nutrients, weight float64) []models.NutrientBalance {
+	rows := []struct {
+		name, unit         string
+		required, supplied float64
+		excessRatio        float64
+	}{
+		{"digestible_energy", "Mcal", required.DigestibleEnergy, supplied.DigestibleEnergy, energyExcessRatio},
+		{"crude_protein", "g", required.CrudeProtein, supplied.CrudeProtein, proteinExcessRatio},
+		{"lysine", "g", required.Lysine, supplied.Lysine, 0},
+		{"calcium", "g", required.Calcium, supplied.Calcium, 0},
+		{"phosphorus", "g", required.Phosphorus, supplied.Phosphorus, 0},
+		{"copper", "mg", required.Copper, supplied.Copper, 0},
+		{"zinc", "mg", required.Zinc, supplied.Zinc, 0},
+		{"selenium", "mg", required.Selenium, supplied.Selenium, 0},
+	}
+
+	balance := make([]models.NutrientBalance, 0, len(rows))
+	for _, row := range rows {
+		b := models.NutrientBalance{
+			Nutrient:   row.name,
+			Unit:       row.unit,
+			Required:   round3(row.required),
+			Supplied:   round3(row.supplied),
+			Difference: round3(row.supplied - row.required),
+			Status:     models.NutrientAdequate,
+		}
+		if row.required > 0 {
+			b.Percent = math.Round(row.supplied / row.required * 100)
+		}
+		limit, hasLimit := maximumNutrients[row.name]
+		if hasLimit {
+			maximum := round3(limit * weight)
+			b.Maximum = &maximum
+		}
+		switch {
+		case row.supplied < row.required*deficitRatio:
+			b.Status = models.NutrientDeficit
+		case hasLimit && row.supplied > limit*weight:
+			b.Status = models.NutrientExcess
+		case row.excessRatio > 0 && row.supplied > row.required*row.excessRatio:
+			b.Status = models.NutrientExcess
+		}
+		balance = append(balance, b)
+	}
+	return balance
+}
+
+// EvaluateRation balances the horse's ration against its requirements for
+// its latest weight, its work, its stage of gestation and, when it is
+// nursing a foal, its month of lactation. A lactation month of 0 is taken
+// from the mare's last foaling.
+func (s *NutritionService) EvaluateRation(ctx context.Context, horse *models.Horse, activity ActivityLevel, lactationMonth int, rations []models.FeedRation) (*models.RationEvaluation, error) {
+	latest, err := s.weights.GetLatest(ctx, horse.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get latest weight: %w", err)
+	}
+	weight, known := CurrentWeight(*horse, latest)
+	if !known {
+		weight = defaultWeightKg
+	}
+	if lactationMonth == 0 {
+		lactation, err := s.lactation(ctx, horse, weight)
+		if err != nil {
+			return nil, err
+		}
+		if lactation != nil {
+			lactationMonth = lactation.Month
+		}
+	}
+
+	required, states, err := NutrientRequirements(weight, activity, gestationMonth(*horse), lactationMonth)
+	if err != nil {
+		return nil, err
+	}
+	supplied, unrated := RationNutrients(rations)
+
+	evaluation := &models.RationEvaluation{
+		HorseID:      horse.ID,
+		WeightKg:     weight,
+		States:       states,
+		Required:     required,
+		Supplied:     supplied,
+		Balance:      BalanceNutrients(required, supplied, weight),
+		UnratedFeeds: unrated,
+		Warnings:     []string{},
+	}
+	if !known {
+		evaluation.Warnings = append(evaluation.Warnings, fmt.Sprintf("No weight on record; requirements are for a %d kg horse. Weigh or tape the horse to tailor them", defaultWeightKg))
+	}
+	if len(rations) == 0 {
+		evaluation.Warnings = append(evaluation.Warnings, "No ration on record; set the horse's daily ration to evaluate it")
+	}
+	if len(unrated) > 0 {
+		evaluation.Warnings = append(evaluation.Warnings, fmt.Sprintf("No nutrient composition for %s; they are left out of the totals", strings.Join(unrated, ", ")))
+	}
+	if supplied.Phosphorus > 0 {
+		ratio := math.Round(supplied.Calcium/supplied.Phosphorus*100) / 100
+		evaluation.CalciumPhosphorusRatio = &ratio
+		switch {
+		case ratio < 1:
+			evaluation.Warnings = append(evaluation.Warnings, fmt.Sprintf("Calcium to phosphorus ratio is %.2f:1; more phosphorus than calcium weakens bone. Add forage such as alfalfa or a calcium supplement", ratio))
+		case ratio > 6:
+			evaluation.Warnings = append(evaluation.Warnings, fmt.Sprintf("Calcium to phosphorus ratio is %.2f:1, above 6:1; check the phosphorus supply", ratio))
+		}
+	}
+	return evaluation, nil
+}
+
+// gestationMonth is the month of pregnancy the mare is in, counted from 1,
+// or 0 when she is not in foal or her conception date is unknown
+func gestationMonth(horse models.Horse) int {
+	if !horse.IsPregnant || horse.ConceptionDate == nil {
+		return 0
+	}
+	days := int(timeNow().Sub(*horse.ConceptionDate).Hours() / 24)
+	if days < 0 {
+		return 0
+	}
+	return days/30 + 1
+}
+
+func round3(v float64) float64 {
+	return math.Round(v*1000) / 1000
+}
@@ -0,0 +1,179 @@
+package health
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/polyfant/hulta_pregnancy_app/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNutrientRequirements(t *testing.T) {
+	tests := []struct {
+		name           string
+		activity       ActivityLevel
+		gestationMonth int
+		lactationMonth int
+		wantStates     []string
+		want           models.Nutrients
+		wantErr        bool
+	}{
+		{
+			name:       "maintenance",
+			activity:   Maintenance,
+			wantStates: []string{"maintenance"},
+			want:       models.Nutrients{DigestibleEnergy: 16.65, CrudeProtein: 630, Lysine: 27.09, Calcium: 20, Phosphorus: 14, Copper: 100, Zinc: 400, Selenium: 1},
+		},
+		{
+			name:           "early gestation needs no more than maintenance",
+			activity:       Maintenance,
+			gestationMonth: 3,
+			wantStates:     []string{"gestation month 3"},
+			want:           models.Nutrients{DigestibleEnergy: 16.65, CrudeProtein: 630, Lysine: 27.09, Calcium: 20, Phosphorus: 14, Copper: 100, Zinc: 400, Selenium: 1},
+		},
+		{
+			name:           "work and late gestation take the larger of each",
+			activity:       ModerateWork,
+			gestationMonth: 9,
+			wantStates:     []string{"moderate work", "gestation month 9"},
+			want:           models.Nutrients{DigestibleEnergy: 23.3, CrudeProtein: 795, Lysine: 34.185, Calcium: 36, Phosphorus: 26.3, Copper: 125, Zinc: 450, Selenium: 1.25},
+		},
+		{
+			name:           "gestation past term uses the last month",
+			activity:       Maintenance,
+			gestationMonth: 12,
+			wantStates:     []string{"gestation month 11"},
+			want:           models.Nutrients{DigestibleEnergy: 21.15, CrudeProtein: 895, Lysine: 38.485, Calcium: 36, Phosphorus: 26.3, Copper: 125, Zinc: 400, Selenium: 1},
+		},
+		{
+			name:           "early lactation",
+			activity:       Maintenance,
+			lactationMonth: 1,
+			wantStates:     []string{"lactation month 1"},
+			want:           models.Nutrients{DigestibleEnergy: 31.7, CrudeProtein: 1535, Lysine: 84.425, Calcium: 59, Phosphorus: 38.3, Copper: 125, Zinc: 500, Selenium: 1.25},
+		},
+		{name: "lactation past six months", activity: Maintenance, lactationMonth: 7, wantErr: true},
+		{name: "invalid activity", activity: ActivityLevel(9), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, states, err := NutrientRequirements(500, tt.activity, tt.gestationMonth, tt.lactationMonth)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStates, states)
+			assert.InDelta(t, tt.want.DigestibleEnergy, got.DigestibleEnergy, 0.01)
+			assert.InDelta(t, tt.want.CrudeProtein, got.CrudeProtein, 0.01)
+			assert.InDelta(t, tt.want.Lysine, got.Lysine, 0.01)
+			assert.InDelta(t, tt.want.Calcium, got.Calcium, 0.01)
+			assert.InDelta(t, tt.want.Phosphorus, got.Phosphorus, 0.01)
+			assert.InDelta(t, tt.want.Copper, got.Copper, 0.01)
+			assert.InDelta(t, tt.want.Zinc, got.Zinc, 0.01)
+			assert.InDelta(t, tt.want.Selenium, got.Selenium, 0.001)
+		})
+	}
+}
+
+func TestRationNutrients(t *testing.T) {
+	hay := &models.FeedType{Name: "Hay", Unit: models.FeedUnitKg, Nutrients: &models.Nutrients{DigestibleEnergy: 1.9, CrudeProtein: 90, Calcium: 4}}
+	minerals := &models.FeedType{Name: "Minerals", Unit: models.FeedUnitGram, Nutrients: &models.Nutrients{Calcium: 200, Copper: 1200}}
+	carrots := &models.FeedType{Name: "Carrots", Unit: models.FeedUnitKg}
+
+	supplied, unrated := RationNutrients([]models.FeedRation{
+		{FeedTypeID: 1, FeedType: hay, DailyAmount: 10},
+		{FeedTypeID: 2, FeedType: minerals, DailyAmount: 100},
+		{FeedTypeID: 3, FeedType: carrots, DailyAmount: 1},
+		{FeedTypeID: 4, DailyAmount: 1},
+	})
+
+	assert.InDelta(t, 19, supplied.DigestibleEnergy, 0.001)
+	assert.InDelta(t, 900, supplied.CrudeProtein, 0.001)
+	assert.InDelta(t, 60, supplied.Calcium, 0.001)
+	assert.InDelta(t, 120, supplied.Copper, 0.001)
+	assert.Equal(t, []string{"Carrots", "feed 4"}, unrated)
+}
+
+func TestBalanceNutrients(t *testing.T) {
+	required := models.Nutrients{DigestibleEnergy: 20, CrudeProtein: 600, Lysine: 25, Calcium: 20, Phosphorus: 14, Copper: 100, Zinc: 400, Selenium: 1}
+	supplied := models.Nutrients{DigestibleEnergy: 24, CrudeProtein: 1000, Lysine: 20, Calcium: 19, Phosphorus: 14, Copper: 3000, Zinc: 400, Selenium: 0.5}
+
+	balance := BalanceNutrients(required, supplied, 500)
+	require.Len(t, balance, 8)
+	statuses := map[string]models.NutrientStatus{}
+	for _, b := range balance {
+		statuses[b.Nutrient] = b.Status
+	}
+	assert.Equal(t, map[string]models.NutrientStatus{
+		"digestible_energy": models.NutrientExcess,
+		"crude_protein":     models.NutrientExcess,
+		"lysine":            models.NutrientDeficit,
+		"calcium":           models.NutrientAdequate,
+		"phosphorus":        models.NutrientAdequate,
+		"copper":            models.NutrientExcess,
+		"zinc":              models.NutrientAdequate,
+		"selenium":          models.NutrientDeficit,
+	}, statuses)
+
+	copper := balance[5]
+	require.NotNil(t, copper.Maximum)
+	assert.Equal(t, 2500.0, *copper.Maximum)
+	assert.Equal(t, 2900.0, copper.Difference)
+	assert.Equal(t, 3000.0, copper.Percent)
+	assert.Nil(t, balance[0].Maximum)
+}
+
+func TestEvaluateRation(t *testing.T) {
+	oldTimeNow := timeNow
+	fixedTime := time.Date(2024, time.April, 15, 12, 0, 0, 0, time.UTC)
+	timeNow = func() time.Time { return fixedTime }
+	defer func() { timeNow = oldTimeNow }()
+
+	ctx := context.Background()
+	oats := &models.FeedType{Name: "Oats", Unit: models.FeedUnitKg, Nutrients: &models.Nutrients{DigestibleEnergy: 2.85, CrudeProtein: 115, Calcium: 0.8, Phosphorus: 3.4}}
+	hay := &models.FeedType{Name: "Hay", Unit: models.FeedUnitKg, Nutrients: &models.Nutrients{DigestibleEnergy: 1.9, CrudeProtein: 90, Calcium: 4, Phosphorus: 2.2}}
+
+	t.Run("mare in late gestation on oats and little hay", func(t *testing.T) {
+		horse := &models.Horse{ID: 1, IsPregnant: true, ConceptionDate: timePtr(fixedTime.AddDate(0, 0, -250))}
+		weights := new(mockWeightRepo)
+		weights.On("GetLatest", ctx, horse.ID).Return(&models.WeightRecord{WeightKg: 550}, nil)
+		service := NewNutritionService(nil, nil, nil, weights, noFoaling(ctx, horse.ID), nil, nil)
+
+		got, err := service.EvaluateRation(ctx, horse, Maintenance, 0, []models.FeedRation{
+			{FeedType: hay, DailyAmount: 3},
+			{FeedType: oats, DailyAmount: 4},
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, 550.0, got.WeightKg)
+		assert.Equal(t, []string{"gestation month 9"}, got.States)
+		require.NotNil(t, got.CalciumPhosphorusRatio)
+		assert.Equal(t, 0.75, *got.CalciumPhosphorusRatio)
+		assert.Equal(t, models.NutrientDeficit, got.Balance[0].Status, "energy")
+		assert.Equal(t, models.NutrientDeficit, got.Balance[3].Status, "calcium")
+		assert.Len(t, got.Warnings, 1, "inverse calcium to phosphorus ratio")
+		assert.Empty(t, got.UnratedFeeds)
+	})
+
+	t.Run("unweighed horse with no ration", func(t *testing.T) {
+		horse := &models.Horse{ID: 2}
+		weights := new(mockWeightRepo)
+		weights.On("GetLatest", ctx, horse.ID).Return(nil, nil)
+		service := NewNutritionService(nil, nil, nil, weights, nil, nil, nil)
+
+		got, err := service.EvaluateRation(ctx, horse, LightWork, 2, nil)
+
+		require.NoError(t, err)
+		assert.Equal(t, float64(defaultWeightKg), got.WeightKg)
+		assert.Equal(t, []string{"light work", "lactation month 2"}, got.States)
+		assert.Nil(t, got.CalciumPhosphorusRatio)
+		assert.Len(t, got.Warnings, 2)
+		for _, b := range got.Balance {
+			assert.Equal(t, models.NutrientDeficit, b.Status, b.Nutrient)
+		}
+	})
+}
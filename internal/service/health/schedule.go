@@ -0,0 +1,237 @@
+package health
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/polyfant/hulta_pregnancy_app/internal/models"
+)
+
+const (
+	// DueSoonDays is how far ahead an item counts as due soon
+	DueSoonDays = 30
+
+	// foalVaccinationAge is when foals of vaccinated mares start their primary series
+	foalVaccinationAge = 6
+	// primarySeriesGapDays is the gap between the first two doses of a primary series
+	primarySeriesGapDays = 30
+	// preFoalingBoosterDays is when the pre-foaling booster is given, before the expected foaling date
+	preFoalingBoosterDays = 42
+	// preFoalingWindowDays is how long before foaling a dose still counts as the pre-foaling booster
+	preFoalingWindowDays = 60
+	// gestationDoseToleranceDays lets a gestation dose given slightly early count
+	gestationDoseToleranceDays = 14
+
+	// foalDewormingStart is the age in months of the first deworming
+	foalDewormingStart = 2
+	// preFoalingDewormDays is when mares are dewormed before foaling to protect the foal
+	preFoalingDewormDays = 28
+	// preFoalingDewormWindowDays is how long before foaling a deworming still counts
+	preFoalingDewormWindowDays = 56
+
+	// firstDentalCheckAge is the age in years of the first routine dental check
+	firstDentalCheckAge = 2
+)
+
+// VaccineProtocol describes when a vaccine from RecommendedVaccinations is boosted
+type VaccineProtocol struct {
+	Name           string
+	Keywords       []string // Matched against the product and description of vaccination records
+	IntervalMonths int
+	GestationDoses []int // Months of gestation at which pregnant mares get an extra dose
+	PreFoaling     bool  // Boosted 4-6 weeks before foaling to protect the foal through colostrum
+}
+
+var VaccineProtocols = []VaccineProtocol{
+	{Name: "Tetanus", Keywords: []string{"tetanus"}, IntervalMonths: 12, PreFoaling: true},
+	{Name: "Equine Influenza", Keywords: []string{"influenza", "flu"}, IntervalMonths: 6, PreFoaling: true},
+	{Name: "Rhinopneumonitis (EHV)", Keywords: []string{"rhino", "ehv", "herpes"}, IntervalMonths: 6, GestationDoses: []int{5, 7, 9}},
+	{Name: "West Nile Virus", Keywords: []string{"west nile", "wnv"}, IntervalMonths: 12, PreFoaling: true},
+}
+
+// ComputeDueItems works out the next vaccination, deworming and dental dates
+// for a horse from its health records, sorted by due date. Fecal egg counts
+// set the deworming interval for adult horses.
+func ComputeDueItems(horse models.Horse, records []models.HealthRecord, eggCounts []models.FecalEggCount, now time.Time) []models.HealthDueItem {
+	var vaccinations, dewormings, dentals []models.HealthRecord
+	for _, record := range records {
+		switch models.HealthRecordType(strings.ToUpper(record.Type)) {
+		case models.HealthRecordTypeVaccination:
+			vaccinations = append(vaccinations, record)
+		case models.HealthRecordTypeDeworming:
+			dewormings = append(dewormings, record)
+		case models.HealthRecordTypeDental:
+			dentals = append(dentals, record)
+		}
+	}
+
+	var items []models.HealthDueItem
+	for _, protocol := range VaccineProtocols {
+		items = append(items, vaccineDue(horse, protocol, matchVaccine(vaccinations, protocol), now))
+	}
+	items = append(items, dewormingDue(horse, sortByDate(dewormings), RecommendDeworming(horse, eggCounts, records, now), now))
+	items = append(items, dentalDue(horse, sortByDate(dentals), now))
+
+	today := truncateDay(now)
+	for i := range items {
+		items[i].HorseID = horse.ID
+		items[i].HorseName = horse.Name
+		items[i].Status = dueStatus(items[i].DueDate, today)
+	}
+	sort.SliceStable(items, func(i, j int) bool { return items[i].DueDate.Before(items[j].DueDate) })
+	return items
+}
+
+func vaccineDue(horse models.Horse, protocol VaccineProtocol, doses []models.HealthRecord, now time.Time) models.HealthDueItem {
+	item := models.HealthDueItem{Category: models.HealthDueVaccination, Name: protocol.Name}
+
+	if len(doses) == 0 {
+		item.DueDate, item.Reason = firstDue(horse, now, 0, foalVaccinationAge, "No vaccination on record",
+			fmt.Sprintf("Primary series starts at %d months of age", foalVaccinationAge))
+	} else {
+		last := doses[len(doses)-1]
+		item.LastDate = &last.Date
+		switch {
+		case last.Details != nil && last.Details.Vaccination != nil && last.Details.Vaccination.NextDueDate != nil:
+			item.DueDate, item.Reason = *last.Details.Vaccination.NextDueDate, "Next dose as recorded by the vet"
+		case len(doses) == 1 && !horse.BirthDate.IsZero() && last.Date.Before(horse.BirthDate.AddDate(1, 0, 0)):
+			item.DueDate, item.Reason = last.Date.AddDate(0, 0, primarySeriesGapDays), "Second dose of the primary series"
+		default:
+			item.DueDate, item.Reason = last.Date.AddDate(0, protocol.IntervalMonths, 0), fmt.Sprintf("Booster every %d months", protocol.IntervalMonths)
+		}
+	}
+
+	if !horse.IsPregnant || horse.ConceptionDate == nil {
+		return item
+	}
+	for _, month := range protocol.GestationDoses {
+		doseDate := horse.ConceptionDate.AddDate(0, month, 0)
+		if !dosedSince(doses, doseDate.AddDate(0, 0, -gestationDoseToleranceDays)) {
+			if doseDate.Before(item.DueDate) {
+				item.DueDate, item.Reason = doseDate, fmt.Sprintf("Pregnant mare: dose at month %d of gestation", month)
+			}
+			break
+		}
+	}
+	if foaling := horse.ExpectedFoalingDate(); protocol.PreFoaling && foaling.After(now) &&
+		!dosedSince(doses, foaling.AddDate(0, 0, -preFoalingWindowDays)) {
+		if booster := foaling.AddDate(0, 0, -preFoalingBoosterDays); booster.Before(item.DueDate) {
+			item.DueDate, item.Reason = booster, "Pregnant mare: booster 4-6 weeks before foaling"
+		}
+	}
+	return item
+}
+
+func dewormingDue(horse models.Horse, doses []models.HealthRecord, plan models.DewormingRecommendation, now time.Time) models.HealthDueItem {
+	item := models.HealthDueItem{Category: models.HealthDueDeworming, Name: "Deworming"}
+
+	if len(doses) == 0 {
+		item.DueDate, item.Reason = firstDue(horse, now, 0, foalDewormingStart, "No deworming on record",
+			fmt.Sprintf("Foals are dewormed from %d months of age", foalDewormingStart))
+	} else {
+		last := doses[len(doses)-1]
+		item.LastDate = &last.Date
+		item.DueDate, item.Reason = last.Date.AddDate(0, plan.IntervalMonths, 0), plan.Reason
+	}
+
+	if !horse.IsPregnant || horse.ConceptionDate == nil {
+		return item
+	}
+	if foaling := horse.ExpectedFoalingDate(); foaling.After(now) && !dosedSince(doses, foaling.AddDate(0, 0, -preFoalingDewormWindowDays)) {
+		if due := foaling.AddDate(0, 0, -preFoalingDewormDays); due.Before(item.DueDate) {
+			item.DueDate, item.Reason = due, "Pregnant mare: deworm in the last month before foaling"
+		}
+	}
+	return item
+}
+
+func dentalDue(horse models.Horse, checks []models.HealthRecord, now time.Time) models.HealthDueItem {
+	item := models.HealthDueItem{Category: models.HealthDueDental, Name: "Dental check"}
+
+	if len(checks) == 0 {
+		item.DueDate, item.Reason = firstDue(horse, now, firstDentalCheckAge, 0, "No dental check on record",
+			fmt.Sprintf("First dental check at %d years of age", firstDentalCheckAge))
+		return item
+	}
+
+	last := checks[len(checks)-1]
+	item.LastDate = &last.Date
+	if last.Details != nil && last.Details.Dental != nil && last.Details.Dental.NextCheckDate != nil {
+		item.DueDate, item.Reason = *last.Details.Dental.NextCheckDate, "Next check as recorded by the vet"
+		return item
+	}
+
+	// Intervals follow DentalCareGuidelines
+	interval := 6
+	if !horse.BirthDate.IsZero() {
+		if age := now.Sub(horse.BirthDate).Hours() / (24 * 365); age >= 5 && age < 15 {
+			interval = 12
+		}
+	}
+	item.DueDate, item.Reason = last.Date.AddDate(0, interval, 0), fmt.Sprintf("Check every %d months for this age", interval)
+	return item
+}
+
+// firstDue is the due date of an item that was never recorded: the starting
+// age for young horses, and today for everyone else
+func firstDue(horse models.Horse, now time.Time, years, months int, missing, young string) (time.Time, string) {
+	if horse.BirthDate.IsZero() {
+		return truncateDay(now), missing
+	}
+	if start := horse.BirthDate.AddDate(years, months, 0); start.After(now) {
+		return start, young
+	}
+	return truncateDay(now), missing
+}
+
+// matchVaccine returns the vaccination records covering the protocol in date
+// order. Combination vaccines match every protocol they name.
+func matchVaccine(records []models.HealthRecord, protocol VaccineProtocol) []models.HealthRecord {
+	var matched []models.HealthRecord
+	for _, record := range records {
+		text := record.Description
+		if record.Details != nil && record.Details.Vaccination != nil {
+			text = record.Details.Vaccination.Product + " " + text
+		}
+		text = strings.ToLower(text)
+		for _, keyword := range protocol.Keywords {
+			if strings.Contains(text, keyword) {
+				matched = append(matched, record)
+				break
+			}
+		}
+	}
+	return sortByDate(matched)
+}
+
+func sortByDate(records []models.HealthRecord) []models.HealthRecord {
+	sort.SliceStable(records, func(i, j int) bool { return records[i].Date.Before(records[j].Date) })
+	return records
+}
+
+func dosedSince(doses []models.HealthRecord, since time.Time) bool {
+	return len(doses) > 0 && !doses[len(doses)-1].Date.Before(since)
+}
+
+func dueStatus(due, today time.Time) models.HealthDueStatus {
+	return dueStatusWithin(due, today, DueSoonDays)
+}
+
+// dueStatusWithin is dueStatus with its own due soon window, for items that
+// recur faster than DueSoonDays
+func dueStatusWithin(due, today time.Time, soonDays int) models.HealthDueStatus {
+	switch {
+	case due.Before(today):
+		return models.HealthDueOverdue
+	case due.Before(today.AddDate(0, 0, soonDays+1)):
+		return models.HealthDueSoon
+	default:
+		return models.HealthDueUpcoming
+	}
+}
+
+func truncateDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
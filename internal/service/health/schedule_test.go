@@ -0,0 +1,162 @@
+package health
+
+import (
+	"testing"
+	"time"
+
+	"github.com/polyfant/hulta_pregnancy_app/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func findDue(items []models.HealthDueItem, name string) models.HealthDueItem {
+	for _, item := range items {
+		if item.Name == name {
+			return item
+		}
+	}
+	return models.HealthDueItem{}
+}
+
+func TestComputeDueItems(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	adult := models.Horse{ID: 1, Name: "Storm", BirthDate: now.AddDate(-8, 0, 0)}
+	nextCheck := now.AddDate(0, 2, 0)
+
+	tests := []struct {
+		name       string
+		horse      models.Horse
+		records    []models.HealthRecord
+		item       string
+		wantDue    time.Time
+		wantStatus models.HealthDueStatus
+	}{
+		{
+			name:       "annual tetanus booster",
+			horse:      adult,
+			records:    []models.HealthRecord{{Type: "VACCINATION", Date: now.AddDate(0, -11, 0), Description: "Tetanus toxoid"}},
+			item:       "Tetanus",
+			wantDue:    now.AddDate(0, -11, 0).AddDate(0, 12, 0),
+			wantStatus: models.HealthDueSoon,
+		},
+		{
+			name:  "product in details matches combination vaccine",
+			horse: adult,
+			records: []models.HealthRecord{{
+				Type: "VACCINATION", Date: now.AddDate(0, -1, 0),
+				Details: &models.HealthRecordDetails{Vaccination: &models.VaccinationDetails{Product: "Flu/Tetanus combo"}},
+			}},
+			item:       "Equine Influenza",
+			wantDue:    now.AddDate(0, -1, 0).AddDate(0, 6, 0),
+			wantStatus: models.HealthDueUpcoming,
+		},
+		{
+			name:       "never vaccinated adult is due today",
+			horse:      adult,
+			item:       "West Nile Virus",
+			wantDue:    time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC),
+			wantStatus: models.HealthDueSoon,
+		},
+		{
+			name:       "young foal starts at six months",
+			horse:      models.Horse{BirthDate: now.AddDate(0, -2, 0)},
+			item:       "Tetanus",
+			wantDue:    now.AddDate(0, -2, 0).AddDate(0, 6, 0),
+			wantStatus: models.HealthDueUpcoming,
+		},
+		{
+			name:       "foal primary series second dose",
+			horse:      models.Horse{BirthDate: now.AddDate(0, -7, 0)},
+			records:    []models.HealthRecord{{Type: "vaccination", Date: now.AddDate(0, 0, -40), Description: "Tetanus"}},
+			item:       "Tetanus",
+			wantDue:    now.AddDate(0, 0, -10),
+			wantStatus: models.HealthDueOverdue,
+		},
+		{
+			name:  "vet recorded next dose wins",
+			horse: adult,
+			records: []models.HealthRecord{{
+				Type: "VACCINATION", Date: now.AddDate(0, -1, 0),
+				Details: &models.HealthRecordDetails{Vaccination: &models.VaccinationDetails{Product: "West Nile", NextDueDate: &nextCheck}},
+			}},
+			item:       "West Nile Virus",
+			wantDue:    nextCheck,
+			wantStatus: models.HealthDueUpcoming,
+		},
+		{
+			name:       "foal deworming every two months",
+			horse:      models.Horse{BirthDate: now.AddDate(0, -5, 0)},
+			records:    []models.HealthRecord{{Type: "DEWORMING", Date: now.AddDate(0, -1, 0)}},
+			item:       "Deworming",
+			wantDue:    now.AddDate(0, -1, 0).AddDate(0, 2, 0),
+			wantStatus: models.HealthDueSoon,
+		},
+		{
+			name:       "adult deworming every six months",
+			horse:      adult,
+			records:    []models.HealthRecord{{Type: "DEWORMING", Date: now.AddDate(0, -7, 0)}},
+			item:       "Deworming",
+			wantDue:    now.AddDate(0, -7, 0).AddDate(0, 6, 0),
+			wantStatus: models.HealthDueOverdue,
+		},
+		{
+			name:       "adult dental check annually",
+			horse:      adult,
+			records:    []models.HealthRecord{{Type: "DENTAL", Date: now.AddDate(0, -3, 0)}},
+			item:       "Dental check",
+			wantDue:    now.AddDate(0, -3, 0).AddDate(0, 12, 0),
+			wantStatus: models.HealthDueUpcoming,
+		},
+		{
+			name:       "senior dental check every six months",
+			horse:      models.Horse{BirthDate: now.AddDate(-20, 0, 0)},
+			records:    []models.HealthRecord{{Type: "DENTAL", Date: now.AddDate(0, -3, 0)}},
+			item:       "Dental check",
+			wantDue:    now.AddDate(0, -3, 0).AddDate(0, 6, 0),
+			wantStatus: models.HealthDueUpcoming,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			items := ComputeDueItems(tt.horse, tt.records, nil, now)
+			require.Len(t, items, len(VaccineProtocols)+2)
+
+			item := findDue(items, tt.item)
+			assert.Equal(t, tt.wantDue, item.DueDate)
+			assert.Equal(t, tt.wantStatus, item.Status)
+			assert.Equal(t, tt.horse.ID, item.HorseID)
+		})
+	}
+}
+
+func TestComputeDueItemsPregnantMare(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	conception := now.AddDate(0, -4, 0)
+	mare := models.Horse{
+		Gender: models.GenderMare, BirthDate: now.AddDate(-9, 0, 0),
+		IsPregnant: true, ConceptionDate: &conception,
+	}
+	records := []models.HealthRecord{
+		{Type: "VACCINATION", Date: now.AddDate(0, -2, 0), Description: "EHV-1/4"},
+		{Type: "VACCINATION", Date: now.AddDate(0, -2, 0), Description: "Tetanus"},
+		{Type: "DEWORMING", Date: now.AddDate(0, -2, 0)},
+	}
+
+	items := ComputeDueItems(mare, records, nil, now)
+
+	ehv := findDue(items, "Rhinopneumonitis (EHV)")
+	assert.Equal(t, conception.AddDate(0, 5, 0), ehv.DueDate, "EHV dose at month 5 of gestation")
+
+	// The annual tetanus booster would fall after foaling, so the pre-foaling booster comes first
+	foaling := conception.AddDate(0, 0, 340)
+	tetanus := findDue(items, "Tetanus")
+	assert.Equal(t, foaling.AddDate(0, 0, -42), tetanus.DueDate)
+
+	deworming := findDue(items, "Deworming")
+	assert.Equal(t, now.AddDate(0, -2, 0).AddDate(0, 6, 0), deworming.DueDate, "regular interval comes before the pre-foaling dose")
+
+	for i := 1; i < len(items); i++ {
+		assert.False(t, items[i].DueDate.Before(items[i-1].DueDate), "items are sorted by due date")
+	}
+}
@@ -0,0 +1,123 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+
+	"github.com/polyfant/hulta_pregnancy_app/internal/models"
+)
+
+// grazingIntake is roughly how much hay an hour's grazing replaces, in kg,
+// on pasture in each condition while the grass is growing fastest
+var grazingIntake = map[models.GrassCondition]float64{
+	models.GrassConditionPoor: 0.2,
+	models.GrassConditionFair: 0.4,
+	models.GrassConditionGood: 0.6,
+	models.GrassConditionLush: 0.8,
+}
+
+// grassGrowth scales grazing intake to how much the grass grows in a season
+var grassGrowth = map[Season]float64{
+	Spring: 1,
+	Summer: 0.8,
+	Autumn: 0.6,
+	Winter: 0.2,
+}
+
+const (
+	// laminitisTurnoutHours is the most a horse prone to laminitis should
+	// graze a day
+	laminitisTurnoutHours = 4
+	// laminitisBodyCondition is the score from which a horse is overweight
+	// enough to be prone to laminitis
+	laminitisBodyCondition = 7
+)
+
+// laminitisHistory matches health records of laminitis or of the metabolic
+// disorders behind most cases of it
+var laminitisHistory = regexp.MustCompile(`(?i)\b(laminiti\w*|founder\w*|equine metabolic syndrome|ems|insulin \w+|ppid|cushing\w*)\b`)
+
+// pastureIntake is how much hay the horse's grazing replaces a day
+func pastureIntake(turnout *models.Turnout, season Season) float64 {
+	return turnout.HoursPerDay * grazingIntake[grassCondition(turnout)] * grassGrowth[season]
+}
+
+// grassCondition is the condition of the turnout's pasture, taken as fair
+// when the pasture is not loaded
+func grassCondition(turnout *models.Turnout) models.GrassCondition {
+	if turnout.Pasture == nil {
+		return models.GrassConditionFair
+	}
+	return turnout.Pasture.GrassCondition
+}
+
+func (s *NutritionService) adjustForTurnout(base models.FeedRequirements, turnout *models.Turnout, season Season) models.FeedRequirements {
+	base.Hay = math.Max(base.Hay-pastureIntake(turnout, season), 0)
+	return base
+}
+
+// turnoutAdjustment explains how much hay grazing took off the ration,
+// leaving hay kg to feed
+func turnoutAdjustment(turnout *models.Turnout, season Season, hay float64) string {
+	pasture := "pasture"
+	if turnout.Pasture != nil {
+		pasture = turnout.Pasture.Name
+	}
+	condition := strings.ToLower(string(grassCondition(turnout)))
+	if hay == 0 {
+		return fmt.Sprintf("Out %g hours a day on %s (%s grass): grazing covers all the forage", turnout.HoursPerDay, pasture, condition)
+	}
+	return fmt.Sprintf("Out %g hours a day on %s (%s grass): hay down %.1f kg for grazing", turnout.HoursPerDay, pasture, condition, pastureIntake(turnout, season))
+}
+
+// laminitisWarnings warns against the horse's turnout when it is prone to
+// laminitis: overweight, or with laminitis or a metabolic disorder in its
+// health records. Sugar-rich grass and long hours out are both a risk.
+func (s *NutritionService) laminitisWarnings(ctx context.Context, horse *models.Horse, turnout *models.Turnout, scores []models.BodyCondition) ([]string, error) {
+	if turnout == nil {
+		return nil, nil
+	}
+
+	var reasons []string
+	var latest *models.BodyCondition
+	for i := range scores {
+		if latest == nil || scores[i].Date.After(latest.Date) {
+			latest = &scores[i]
+		}
+	}
+	if latest != nil && latest.Score >= laminitisBodyCondition {
+		reasons = append(reasons, fmt.Sprintf("body condition %.1f", latest.Score))
+	}
+	records, err := s.healthRepo.GetRecords(ctx, horse.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get health records: %w", err)
+	}
+	for _, record := range records {
+		text := record.Description
+		if record.Details != nil && record.Details.VetVisit != nil {
+			text += " " + record.Details.VetVisit.Reason + " " + record.Details.VetVisit.Diagnosis
+		}
+		if found := laminitisHistory.FindString(text); found != "" {
+			reasons = append(reasons, "history of "+found)
+			break
+		}
+	}
+	if len(reasons) == 0 {
+		return nil, nil
+	}
+
+	risk := "Prone to laminitis (" + strings.Join(reasons, ", ") + ")"
+	season := getCurrentSeason()
+	condition := grassCondition(turnout)
+	var warnings []string
+	if condition == models.GrassConditionLush || (condition == models.GrassConditionGood && (season == Spring || season == Autumn)) {
+		warnings = append(warnings, fmt.Sprintf("%s and grazing %s grass while its sugars are high: move to a sparser pasture or a track, or use a grazing muzzle", risk, strings.ToLower(string(condition))))
+	}
+	if turnout.HoursPerDay > laminitisTurnoutHours {
+		warnings = append(warnings, fmt.Sprintf("%s and out %g hours a day: limit grazing to %d hours, early in the morning when grass sugars are lowest", risk, turnout.HoursPerDay, laminitisTurnoutHours))
+	}
+	return warnings, nil
+}
@@ -0,0 +1,171 @@
+package health
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/polyfant/hulta_pregnancy_app/internal/mocks"
+	"github.com/polyfant/hulta_pregnancy_app/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// keptIn is a pasture repository for a horse that is not turned out
+func keptIn(ctx context.Context, horseID uint) *mocks.MockPastureRepository {
+	pastures := new(mocks.MockPastureRepository)
+	pastures.On("GetTurnout", ctx, horseID).Return(nil, nil)
+	return pastures
+}
+
+func TestAdjustForTurnout(t *testing.T) {
+	s := &NutritionService{}
+	base := models.FeedRequirements{Hay: 10, Grain: 2.5, Minerals: 0.1, Water: 25}
+	pasture := func(condition models.GrassCondition) *models.Pasture {
+		return &models.Pasture{Name: "Top field", GrassCondition: condition}
+	}
+
+	tests := []struct {
+		name    string
+		turnout models.Turnout
+		season  Season
+		wantHay float64
+	}{
+		{name: "good grass in spring", turnout: models.Turnout{HoursPerDay: 8, Pasture: pasture(models.GrassConditionGood)}, season: Spring, wantHay: 5.2},
+		{name: "good grass in summer", turnout: models.Turnout{HoursPerDay: 8, Pasture: pasture(models.GrassConditionGood)}, season: Summer, wantHay: 6.16},
+		{name: "poor grass in winter", turnout: models.Turnout{HoursPerDay: 8, Pasture: pasture(models.GrassConditionPoor)}, season: Winter, wantHay: 9.68},
+		{name: "pasture not loaded is taken as fair", turnout: models.Turnout{HoursPerDay: 10}, season: Spring, wantHay: 6},
+		{name: "out all day on lush grass", turnout: models.Turnout{HoursPerDay: 24, Pasture: pasture(models.GrassConditionLush)}, season: Spring, wantHay: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := s.adjustForTurnout(base, &tt.turnout, tt.season)
+			assert.InDelta(t, tt.wantHay, got.Hay, 0.001)
+			assert.Equal(t, base.Grain, got.Grain)
+			assert.Equal(t, base.Water, got.Water)
+		})
+	}
+}
+
+func TestRecommendFeed_Turnout(t *testing.T) {
+	oldTimeNow := timeNow
+	fixedTime := time.Date(2024, time.April, 15, 12, 0, 0, 0, time.UTC)
+	timeNow = func() time.Time { return fixedTime }
+	defer func() { timeNow = oldTimeNow }()
+
+	ctx := context.Background()
+	score := func(value float64) []models.BodyCondition {
+		return []models.BodyCondition{
+			{HorseID: 1, Date: fixedTime.AddDate(0, -2, 0), Score: 5},
+			{HorseID: 1, Date: fixedTime.AddDate(0, 0, -3), Score: value},
+		}
+	}
+	laminitis := []models.HealthRecord{{HorseID: 1, Type: "vet_visit", Description: "Check-up", Details: &models.HealthRecordDetails{
+		VetVisit: &models.VetVisitDetails{Reason: "Lame in front", Diagnosis: "Laminitis"},
+	}}}
+
+	tests := []struct {
+		name         string
+		turnout      *models.Turnout
+		scores       []models.BodyCondition
+		records      []models.HealthRecord
+		wantHay      float64
+		wantWarnings int
+	}{
+		{name: "kept in", scores: score(5), wantHay: 10},
+		{
+			name:    "short turnout on fair grass",
+			turnout: &models.Turnout{HoursPerDay: 4, Pasture: &models.Pasture{Name: "Paddock", GrassCondition: models.GrassConditionFair}},
+			scores:  score(5),
+			wantHay: 8.4,
+		},
+		{
+			name:    "healthy horse on good spring grass all day",
+			turnout: &models.Turnout{HoursPerDay: 12, Pasture: &models.Pasture{Name: "Top field", GrassCondition: models.GrassConditionGood}},
+			scores:  score(5),
+			wantHay: 2.8,
+		},
+		{
+			name:         "overweight horse on good spring grass all day",
+			turnout:      &models.Turnout{HoursPerDay: 12, Pasture: &models.Pasture{Name: "Top field", GrassCondition: models.GrassConditionGood}},
+			scores:       score(7.5),
+			wantHay:      2.8,
+			wantWarnings: 2,
+		},
+		{
+			name:         "laminitic horse on a short turnout on fair grass",
+			turnout:      &models.Turnout{HoursPerDay: 3, Pasture: &models.Pasture{Name: "Track", GrassCondition: models.GrassConditionFair}},
+			scores:       score(5),
+			records:      laminitis,
+			wantHay:      8.8,
+			wantWarnings: 0,
+		},
+		{
+			name:         "laminitic horse out for long hours",
+			turnout:      &models.Turnout{HoursPerDay: 8, Pasture: &models.Pasture{Name: "Track", GrassCondition: models.GrassConditionFair}},
+			scores:       score(5),
+			records:      laminitis,
+			wantHay:      6.8,
+			wantWarnings: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			horse := &models.Horse{ID: 1, Gender: models.GenderGelding, Weight: 500}
+			bodyConditions := new(mockBodyConditionRepo)
+			bodyConditions.On("ListByHorse", ctx, horse.ID, mock.Anything).Return(tt.scores, nil)
+			weights := new(mockWeightRepo)
+			weights.On("GetLatest", ctx, horse.ID).Return(nil, nil)
+			healthRepo := new(mockHealthRepo)
+			healthRepo.On("GetRecords", ctx, horse.ID).Return(tt.records, nil)
+			pastures := new(mocks.MockPastureRepository)
+			pastures.On("GetTurnout", ctx, horse.ID).Return(tt.turnout, nil)
+			service := NewNutritionService(healthRepo, new(mockHorseRepo), bodyConditions, weights, nil, nil, pastures)
+
+			got, err := service.RecommendFeed(ctx, horse, Maintenance)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.turnout, got.Turnout)
+			assert.InDelta(t, tt.wantHay, got.Requirements.Hay, 0.001)
+			assert.Len(t, got.Warnings, tt.wantWarnings)
+			if tt.turnout == nil {
+				healthRepo.AssertNotCalled(t, "GetRecords", mock.Anything, mock.Anything)
+				return
+			}
+			assert.Contains(t, got.Adjustments[0], tt.turnout.Pasture.Name)
+		})
+	}
+}
+
+func TestCalculateRequirements_SummerGrazing(t *testing.T) {
+	oldTimeNow := timeNow
+	fixedTime := time.Date(2024, time.July, 15, 12, 0, 0, 0, time.UTC)
+	timeNow = func() time.Time { return fixedTime }
+	defer func() { timeNow = oldTimeNow }()
+
+	s := &NutritionService{}
+	horse := models.Horse{ID: 1, Gender: models.GenderGelding, Weight: 500}
+
+	tests := []struct {
+		name    string
+		turnout *models.Turnout
+		wantHay float64
+	}{
+		{name: "no turnout data assumes some grazing", wantHay: 9},
+		{name: "turnout replaces the assumed grazing", turnout: &models.Turnout{HoursPerDay: 8, Pasture: &models.Pasture{GrassCondition: models.GrassConditionGood}}, wantHay: 6.16},
+		{name: "an hour on poor grass", turnout: &models.Turnout{HoursPerDay: 1, Pasture: &models.Pasture{GrassCondition: models.GrassConditionPoor}}, wantHay: 9.84},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.calculateRequirements(horse, Maintenance, 0, tt.turnout)
+
+			require.NoError(t, err)
+			assert.InDelta(t, tt.wantHay, got.Hay, 0.001)
+			assert.InDelta(t, 32.5, got.Water, 0.001)
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package health
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/polyfant/hulta_pregnancy_app/internal/models"
+)
+
+const (
+	// foalVitalSignsAge is how long the newborn foal ranges apply
+	foalVitalSignsAge = 14 * 24 * time.Hour
+	// latePregnancyAge is when a mare moves to the late pregnancy ranges
+	latePregnancyAge = 226 * 24 * time.Hour
+
+	// MaxCapillaryRefill is the slowest normal capillary refill time in seconds
+	MaxCapillaryRefill = 2.0
+)
+
+// VitalSignsCategory picks the VitalSignRanges key that applies to the horse at the given time
+func VitalSignsCategory(horse models.Horse, at time.Time) string {
+	if !horse.BirthDate.IsZero() && at.Sub(horse.BirthDate) < foalVitalSignsAge {
+		return "Foal"
+	}
+	if horse.IsPregnant && horse.ConceptionDate != nil && at.Sub(*horse.ConceptionDate) > latePregnancyAge {
+		return "PregnantLate"
+	}
+	return "Adult"
+}
+
+// EvaluateVitalSigns compares a record against the ranges for its category
+// and returns a finding for every value outside them
+func EvaluateVitalSigns(category string, record models.VitalSignsRecord) []models.VitalSignFinding {
+	ranges, ok := VitalSignRanges[category]
+	if !ok {
+		ranges = VitalSignRanges["Adult"]
+	}
+
+	var findings []models.VitalSignFinding
+	if t := record.Temperature; t != nil && (*t < ranges.Temperature.Min || *t > ranges.Temperature.Max) {
+		findings = append(findings, models.VitalSignFinding{
+			Sign:     "temperature",
+			Value:    fmt.Sprintf("%.1f°C", *t),
+			Expected: fmt.Sprintf("%.1f-%.1f°C", ranges.Temperature.Min, ranges.Temperature.Max),
+		})
+	}
+	if hr := record.HeartRate; hr != nil && (*hr < ranges.HeartRate.Min || *hr > ranges.HeartRate.Max) {
+		findings = append(findings, models.VitalSignFinding{
+			Sign:     "heart_rate",
+			Value:    fmt.Sprintf("%d bpm", *hr),
+			Expected: fmt.Sprintf("%d-%d bpm", ranges.HeartRate.Min, ranges.HeartRate.Max),
+		})
+	}
+	if rr := record.RespirationRate; rr != nil && (*rr < ranges.RespirationRate.Min || *rr > ranges.RespirationRate.Max) {
+		findings = append(findings, models.VitalSignFinding{
+			Sign:     "respiration_rate",
+			Value:    fmt.Sprintf("%d breaths/min", *rr),
+			Expected: fmt.Sprintf("%d-%d breaths/min", ranges.RespirationRate.Min, ranges.RespirationRate.Max),
+		})
+	}
+	if record.GutSounds != "" && record.GutSounds != models.GutSoundsNormal {
+		findings = append(findings, models.VitalSignFinding{
+			Sign:     "gut_sounds",
+			Value:    string(record.GutSounds),
+			Expected: string(models.GutSoundsNormal),
+		})
+	}
+	if crt := record.CapillaryRefill; crt != nil && *crt > MaxCapillaryRefill {
+		findings = append(findings, models.VitalSignFinding{
+			Sign:     "capillary_refill",
+			Value:    fmt.Sprintf("%.1fs", *crt),
+			Expected: fmt.Sprintf("%.0fs or less", MaxCapillaryRefill),
+		})
+	}
+	if record.MucousMembrane != "" && record.MucousMembrane != models.MucousMembranePink {
+		findings = append(findings, models.VitalSignFinding{
+			Sign:     "mucous_membrane",
+			Value:    string(record.MucousMembrane),
+			Expected: string(models.MucousMembranePink),
+		})
+	}
+	return findings
+}
@@ -0,0 +1,92 @@
+package health
+
+import (
+	"testing"
+	"time"
+
+	"github.com/polyfant/hulta_pregnancy_app/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVitalSignsCategory(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	lateConception := now.AddDate(0, 0, -250)
+	earlyConception := now.AddDate(0, 0, -100)
+	oldConception := now.AddDate(-1, 0, -100)
+
+	tests := []struct {
+		name  string
+		horse models.Horse
+		want  string
+	}{
+		{"newborn foal", models.Horse{BirthDate: now.AddDate(0, 0, -5)}, "Foal"},
+		{"weanling is adult", models.Horse{BirthDate: now.AddDate(0, -6, 0)}, "Adult"},
+		{"late pregnancy", models.Horse{BirthDate: now.AddDate(-8, 0, 0), IsPregnant: true, ConceptionDate: &lateConception}, "PregnantLate"},
+		{"early pregnancy", models.Horse{BirthDate: now.AddDate(-8, 0, 0), IsPregnant: true, ConceptionDate: &earlyConception}, "Adult"},
+		{"foaled mare with old conception date", models.Horse{BirthDate: now.AddDate(-8, 0, 0), ConceptionDate: &oldConception}, "Adult"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, VitalSignsCategory(tt.horse, now))
+		})
+	}
+}
+
+func TestEvaluateVitalSigns(t *testing.T) {
+	temp := func(v float64) *float64 { return &v }
+	rate := func(v int) *int { return &v }
+
+	tests := []struct {
+		name      string
+		category  string
+		record    models.VitalSignsRecord
+		wantSigns []string
+	}{
+		{
+			name:     "normal adult",
+			category: "Adult",
+			record: models.VitalSignsRecord{
+				Temperature: temp(37.9), HeartRate: rate(36), RespirationRate: rate(12),
+				GutSounds: models.GutSoundsNormal, CapillaryRefill: temp(1.5), MucousMembrane: models.MucousMembranePink,
+			},
+		},
+		{
+			name:      "foal heart rate is normal for a foal",
+			category:  "Foal",
+			record:    models.VitalSignsRecord{HeartRate: rate(100)},
+			wantSigns: nil,
+		},
+		{
+			name:      "same heart rate is abnormal for an adult",
+			category:  "Adult",
+			record:    models.VitalSignsRecord{HeartRate: rate(100)},
+			wantSigns: []string{"heart_rate"},
+		},
+		{
+			name:     "colic presentation",
+			category: "Adult",
+			record: models.VitalSignsRecord{
+				Temperature: temp(38.0), HeartRate: rate(64), RespirationRate: rate(24),
+				GutSounds: models.GutSoundsAbsent, CapillaryRefill: temp(3), MucousMembrane: models.MucousMembraneBrickRed,
+			},
+			wantSigns: []string{"heart_rate", "respiration_rate", "gut_sounds", "capillary_refill", "mucous_membrane"},
+		},
+		{
+			name:      "fever in late pregnancy",
+			category:  "PregnantLate",
+			record:    models.VitalSignsRecord{Temperature: temp(39.4), HeartRate: rate(48)},
+			wantSigns: []string{"temperature"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var signs []string
+			for _, finding := range EvaluateVitalSigns(tt.category, tt.record) {
+				signs = append(signs, finding.Sign)
+			}
+			assert.Equal(t, tt.wantSigns, signs)
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package health
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/polyfant/hulta_pregnancy_app/internal/models"
+)
+
+// ponyMaxHeightCm is the 14.2 hands limit below which a horse is a pony
+const ponyMaxHeightCm = 148
+
+// weightDivisors turn heart girth² × body length in cm into kilograms. They
+// are the metric forms of the imperial divisors 330 (adult), 299 (pony),
+// 301 (yearling) and 280 (weanling).
+var weightDivisors = map[models.WeightFormula]float64{
+	models.WeightFormulaAdult:    11880,
+	models.WeightFormulaPony:     10780,
+	models.WeightFormulaYearling: 10850,
+	models.WeightFormulaWeanling: 10090,
+}
+
+// ponyBreeds are breeds weighed with the pony formula whatever their recorded height
+var ponyBreeds = []string{
+	"pony", "shetland", "welsh", "connemara", "dartmoor", "exmoor", "fell",
+	"dales", "highland", "new forest", "haflinger", "icelandic", "fjord",
+}
+
+// IsPony reports whether the horse is a pony by height or, without a
+// height on record, by breed
+func IsPony(horse models.Horse) bool {
+	if horse.Height > 0 {
+		return horse.Height <= ponyMaxHeightCm
+	}
+	breed := strings.ToLower(horse.Breed)
+	for _, pony := range ponyBreeds {
+		if strings.Contains(breed, pony) {
+			return true
+		}
+	}
+	return false
+}
+
+// WeightFormulaFor picks the formula for the horse's age on the day it was
+// measured. Young stock take the foal variants whatever their breed.
+func WeightFormulaFor(horse models.Horse, on time.Time) models.WeightFormula {
+	if !horse.BirthDate.IsZero() {
+		switch {
+		case on.Before(horse.BirthDate.AddDate(1, 0, 0)):
+			return models.WeightFormulaWeanling
+		case on.Before(horse.BirthDate.AddDate(2, 0, 0)):
+			return models.WeightFormulaYearling
+		}
+	}
+	if IsPony(horse) {
+		return models.WeightFormulaPony
+	}
+	return models.WeightFormulaAdult
+}
+
+// EstimateWeight estimates body weight in kg from heart girth and body
+// length (point of shoulder to point of buttock) in cm. Tape estimates are
+// within about 5% of a scale, so the result is rounded to the kilogram.
+func EstimateWeight(heartGirthCm, bodyLengthCm float64, formula models.WeightFormula) (float64, error) {
+	divisor, ok := weightDivisors[formula]
+	if !ok {
+		return 0, fmt.Errorf("unknown formula %q", formula)
+	}
+	if heartGirthCm < 50 || heartGirthCm > 260 {
+		return 0, fmt.Errorf("heart girth must be between 50 and 260 cm, got %.1f", heartGirthCm)
+	}
+	if bodyLengthCm < 40 || bodyLengthCm > 250 {
+		return 0, fmt.Errorf("body length must be between 40 and 250 cm, got %.1f", bodyLengthCm)
+	}
+	return math.Round(heartGirthCm * heartGirthCm * bodyLengthCm / divisor), nil
+}
+
+// CurrentWeight is the weight to calculate rations and doses from: the
+// latest weighing, or the weight entered on the horse when it has never
+// been weighed
+func CurrentWeight(horse models.Horse, latest *models.WeightRecord) (float64, bool) {
+	if latest != nil && latest.WeightKg > 0 {
+		return latest.WeightKg, true
+	}
+	if horse.Weight > 0 {
+		return horse.Weight, true
+	}
+	return 0, false
+}
@@ -0,0 +1,78 @@
+package health
+
+import (
+	"testing"
+	"time"
+
+	"github.com/polyfant/hulta_pregnancy_app/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEstimateWeight(t *testing.T) {
+	tests := []struct {
+		name    string
+		girth   float64
+		length  float64
+		formula models.WeightFormula
+		want    float64
+		wantErr bool
+	}{
+		{name: "adult horse", girth: 190, length: 165, formula: models.WeightFormulaAdult, want: 501},
+		{name: "pony", girth: 150, length: 130, formula: models.WeightFormulaPony, want: 271},
+		{name: "yearling", girth: 160, length: 140, formula: models.WeightFormulaYearling, want: 330},
+		{name: "weanling", girth: 130, length: 115, formula: models.WeightFormulaWeanling, want: 193},
+		{name: "girth too small", girth: 30, length: 100, formula: models.WeightFormulaAdult, wantErr: true},
+		{name: "length too large", girth: 190, length: 300, formula: models.WeightFormulaAdult, wantErr: true},
+		{name: "unknown formula", girth: 190, length: 165, formula: "DRAFT", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := EstimateWeight(tt.girth, tt.length, tt.formula)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestWeightFormulaFor(t *testing.T) {
+	on := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		horse models.Horse
+		want  models.WeightFormula
+	}{
+		{name: "foal", horse: models.Horse{BirthDate: on.AddDate(0, -5, 0), Height: 120}, want: models.WeightFormulaWeanling},
+		{name: "yearling", horse: models.Horse{BirthDate: on.AddDate(-1, -3, 0)}, want: models.WeightFormulaYearling},
+		{name: "adult horse", horse: models.Horse{BirthDate: on.AddDate(-8, 0, 0), Height: 165}, want: models.WeightFormulaAdult},
+		{name: "pony by height", horse: models.Horse{BirthDate: on.AddDate(-8, 0, 0), Height: 140}, want: models.WeightFormulaPony},
+		{name: "pony by breed", horse: models.Horse{Breed: "Welsh Section B"}, want: models.WeightFormulaPony},
+		{name: "height wins over breed", horse: models.Horse{Breed: "Connemara", Height: 150}, want: models.WeightFormulaAdult},
+		{name: "no birth date", horse: models.Horse{Breed: "Thoroughbred"}, want: models.WeightFormulaAdult},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, WeightFormulaFor(tt.horse, on))
+		})
+	}
+}
+
+func TestCurrentWeight(t *testing.T) {
+	weight, ok := CurrentWeight(models.Horse{Weight: 500}, &models.WeightRecord{WeightKg: 480})
+	assert.True(t, ok)
+	assert.Equal(t, 480.0, weight)
+
+	weight, ok = CurrentWeight(models.Horse{Weight: 500}, nil)
+	assert.True(t, ok)
+	assert.Equal(t, 500.0, weight)
+
+	_, ok = CurrentWeight(models.Horse{}, nil)
+	assert.False(t, ok)
+}
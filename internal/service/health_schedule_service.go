@@ -0,0 +1,182 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/polyfant/hulta_pregnancy_app/internal/logger"
+	"github.com/polyfant/hulta_pregnancy_app/internal/models"
+	"github.com/polyfant/hulta_pregnancy_app/internal/repository"
+	"github.com/polyfant/hulta_pregnancy_app/internal/service/health"
+	"github.com/polyfant/hulta_pregnancy_app/internal/service/notification"
+)
+
+// HealthScheduleServiceImpl computes preventive care due dates and reminds owners
+type HealthScheduleServiceImpl struct {
+	horseService HorseService
+	horseRepo    repository.HorseRepository
+	healthRepo   repository.HealthRepository
+	eggCounts    repository.FecalEggCountRepository
+	farrier      repository.FarrierRepository
+	reminders    repository.HealthReminderRepository
+	notifier     Notifier
+}
+
+func NewHealthScheduleService(
+	horseService HorseService,
+	horseRepo repository.HorseRepository,
+	healthRepo repository.HealthRepository,
+	eggCounts repository.FecalEggCountRepository,
+	farrier repository.FarrierRepository,
+	reminders repository.HealthReminderRepository,
+	notifier Notifier,
+) HealthScheduleService {
+	return &HealthScheduleServiceImpl{
+		horseService: horseService,
+		horseRepo:    horseRepo,
+		healthRepo:   healthRepo,
+		eggCounts:    eggCounts,
+		farrier:      farrier,
+		reminders:    reminders,
+		notifier:     notifier,
+	}
+}
+
+func (s *HealthScheduleServiceImpl) GetDueItems(ctx context.Context, horse *models.Horse) ([]models.HealthDueItem, error) {
+	records, err := s.healthRepo.GetRecords(ctx, horse.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get health records: %w", err)
+	}
+	counts, err := s.eggCounts.ListByHorse(ctx, horse.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get fecal egg counts: %w", err)
+	}
+	schedule, err := s.farrier.GetSchedule(ctx, horse.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get farrier schedule: %w", err)
+	}
+	visits, err := s.farrier.ListVisitsByHorse(ctx, horse.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get farrier visits: %w", err)
+	}
+
+	now := timeNow()
+	items := health.ComputeDueItems(*horse, records, counts, now)
+	if item, ok := health.FarrierDue(*horse, schedule, visits, now); ok {
+		items = append(items, item)
+		sortDueItems(items)
+	}
+	return items, nil
+}
+
+// ListOverdue returns the overdue items of every horse the user can see,
+// including organization horses, most overdue first. A horse whose records
+// cannot be loaded is logged and left out rather than failing the list.
+func (s *HealthScheduleServiceImpl) ListOverdue(ctx context.Context, userID string) ([]models.HealthDueItem, error) {
+	horses, err := s.horseService.ListByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get horses: %w", err)
+	}
+
+	overdue := []models.HealthDueItem{}
+	for i := range horses {
+		items, err := s.GetDueItems(ctx, &horses[i])
+		if err != nil {
+			logger.Warn("Failed to compute health due dates", map[string]interface{}{"horseID": horses[i].ID, "error": err.Error()})
+			continue
+		}
+		for _, item := range items {
+			if item.Status == models.HealthDueOverdue {
+				overdue = append(overdue, item)
+			}
+		}
+	}
+	sortDueItems(overdue)
+	return overdue, nil
+}
+
+// SendReminders notifies owners about items that are due soon or overdue,
+// once per item and due date. It returns the number of reminders sent.
+func (s *HealthScheduleServiceImpl) SendReminders(ctx context.Context) (int, error) {
+	horses, err := s.horseRepo.ListActive(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get horses: %w", err)
+	}
+
+	now := timeNow()
+	sent := 0
+	for i := range horses {
+		horse := &horses[i]
+		items, err := s.GetDueItems(ctx, horse)
+		if err != nil {
+			logger.Warn("Failed to compute health due dates", map[string]interface{}{"horseID": horse.ID, "error": err.Error()})
+			continue
+		}
+		for _, item := range items {
+			// Items that were never recorded are due every day; they show up in
+			// the overdue list but would otherwise trigger a reminder daily
+			if item.Status == models.HealthDueUpcoming || (item.LastDate == nil && !item.DueDate.After(now)) {
+				continue
+			}
+			ok, err := s.reminders.MarkSent(ctx, &models.HealthReminder{
+				HorseID: horse.ID,
+				Item:    item.Name,
+				DueDate: item.DueDate,
+				SentAt:  now,
+			})
+			if err != nil {
+				return sent, fmt.Errorf("failed to record reminder: %w", err)
+			}
+			if !ok {
+				continue
+			}
+			if err := s.notifier.SendNotification(ctx, dueNotification(horse, item)); err != nil {
+				logger.Warn("Failed to send health reminder", map[string]interface{}{"horseID": horse.ID, "item": item.Name, "error": err.Error()})
+				continue
+			}
+			sent++
+		}
+	}
+	return sent, nil
+}
+
+// ScheduleReminders runs SendReminders in the background at the given interval
+func (s *HealthScheduleServiceImpl) ScheduleReminders(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	go func() {
+		for range ticker.C {
+			sent, err := s.SendReminders(context.Background())
+			if err != nil {
+				logger.Error(err, "Health reminder sweep failed")
+				continue
+			}
+			logger.Info("Health reminders sent", map[string]interface{}{"count": sent})
+		}
+	}()
+}
+
+func dueNotification(horse *models.Horse, item models.HealthDueItem) *notification.Notification {
+	n := &notification.Notification{
+		Type:     notification.HealthCheckDue,
+		UserID:   horse.UserID,
+		HorseID:  int64(horse.ID),
+		Title:    fmt.Sprintf("%s due for %s", item.Name, horse.Name),
+		Message:  fmt.Sprintf("%s is due on %s. %s.", item.Name, item.DueDate.Format("2 Jan 2006"), item.Reason),
+		DueDate:  item.DueDate,
+		Priority: notification.Medium,
+	}
+	if item.Category == models.HealthDueVaccination {
+		n.Type = notification.VaccinationDue
+	}
+	if item.Status == models.HealthDueOverdue {
+		n.Title = fmt.Sprintf("%s overdue for %s", item.Name, horse.Name)
+		n.Priority = notification.High
+	}
+	return n
+}
+
+func sortDueItems(items []models.HealthDueItem) {
+	sort.SliceStable(items, func(i, j int) bool { return items[i].DueDate.Before(items[j].DueDate) })
+}
@@ -0,0 +1,178 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/polyfant/hulta_pregnancy_app/internal/mocks"
+	"github.com/polyfant/hulta_pregnancy_app/internal/models"
+	"github.com/polyfant/hulta_pregnancy_app/internal/service/notification"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// upToDateRecords covers everything except deworming, which was last done
+// seven months ago and is overdue
+func upToDateRecords(now time.Time) []models.HealthRecord {
+	return []models.HealthRecord{
+		{Type: "VACCINATION", Date: now.AddDate(0, -1, 0), Description: "Tetanus, influenza, EHV and West Nile"},
+		{Type: "DENTAL", Date: now.AddDate(0, -1, 0)},
+		{Type: "DEWORMING", Date: now.AddDate(0, -7, 0)},
+	}
+}
+
+func TestHealthScheduleService_ListOverdue(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 6, 1, 9, 0, 0, 0, time.UTC)
+	setTestNow(t, now)
+	horseRepo := new(mocks.MockHorseRepository)
+	orgRepo := new(mocks.MockOrganizationRepository)
+	healthRepo := new(mocks.MockHealthRepository)
+	eggCounts := new(mocks.MockFecalEggCountRepository)
+	eggCounts.On("ListByHorse", mock.Anything, mock.Anything).Return([]models.FecalEggCount{}, nil)
+	farrier := new(mocks.MockFarrierRepository)
+	farrier.On("GetSchedule", mock.Anything, mock.Anything).Return(nil, nil)
+	farrier.On("ListVisitsByHorse", mock.Anything, mock.Anything).Return([]models.FarrierVisit{}, nil)
+	svc := NewHealthScheduleService(NewHorseService(horseRepo, orgRepo, nil), horseRepo, healthRepo, eggCounts, farrier, new(mocks.MockHealthReminderRepository), new(mockNotifier))
+
+	horses := []models.Horse{
+		{ID: 1, UserID: "owner", Name: "Storm", BirthDate: now.AddDate(-8, 0, 0)},
+		{ID: 2, UserID: "owner", Name: "Bella", BirthDate: now.AddDate(-6, 0, 0)},
+	}
+	horseRepo.On("ListByUser", ctx, "owner").Return(horses, nil)
+	orgRepo.On("GetMemberships", ctx, "owner").Return([]models.OrganizationMember{}, nil)
+	healthRepo.On("GetRecords", ctx, uint(1)).Return(upToDateRecords(now), nil)
+	healthRepo.On("GetRecords", ctx, uint(2)).Return([]models.HealthRecord{
+		{Type: "VACCINATION", Date: now.AddDate(0, -1, 0), Description: "Tetanus, influenza, EHV and West Nile"},
+		{Type: "DEWORMING", Date: now.AddDate(0, -1, 0)},
+		{Type: "DENTAL", Date: now.AddDate(-2, 0, 0)},
+	}, nil)
+
+	items, err := svc.ListOverdue(ctx, "owner")
+
+	require.NoError(t, err)
+	require.Len(t, items, 2)
+	assert.Equal(t, "Bella", items[0].HorseName, "most overdue first")
+	assert.Equal(t, models.HealthDueDental, items[0].Category)
+	assert.Equal(t, "Storm", items[1].HorseName)
+	assert.Equal(t, models.HealthDueDeworming, items[1].Category)
+}
+
+func TestHealthScheduleService_ListOverdueSkipsFailingHorse(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 6, 1, 9, 0, 0, 0, time.UTC)
+	setTestNow(t, now)
+	horseRepo := new(mocks.MockHorseRepository)
+	orgRepo := new(mocks.MockOrganizationRepository)
+	healthRepo := new(mocks.MockHealthRepository)
+	eggCounts := new(mocks.MockFecalEggCountRepository)
+	eggCounts.On("ListByHorse", mock.Anything, mock.Anything).Return([]models.FecalEggCount{}, nil)
+	farrier := new(mocks.MockFarrierRepository)
+	farrier.On("GetSchedule", mock.Anything, mock.Anything).Return(nil, nil)
+	farrier.On("ListVisitsByHorse", mock.Anything, mock.Anything).Return([]models.FarrierVisit{}, nil)
+	svc := NewHealthScheduleService(NewHorseService(horseRepo, orgRepo, nil), horseRepo, healthRepo, eggCounts, farrier, new(mocks.MockHealthReminderRepository), new(mockNotifier))
+
+	horses := []models.Horse{
+		{ID: 1, UserID: "owner", Name: "Storm", BirthDate: now.AddDate(-8, 0, 0)},
+		{ID: 2, UserID: "owner", Name: "Bella", BirthDate: now.AddDate(-6, 0, 0)},
+	}
+	horseRepo.On("ListByUser", ctx, "owner").Return(horses, nil)
+	orgRepo.On("GetMemberships", ctx, "owner").Return([]models.OrganizationMember{}, nil)
+	healthRepo.On("GetRecords", ctx, uint(1)).Return([]models.HealthRecord(nil), errors.New("connection reset"))
+	healthRepo.On("GetRecords", ctx, uint(2)).Return(upToDateRecords(now), nil)
+
+	items, err := svc.ListOverdue(ctx, "owner")
+
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	assert.Equal(t, "Bella", items[0].HorseName)
+}
+
+func TestHealthScheduleService_SendReminders(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 6, 1, 9, 0, 0, 0, time.UTC)
+
+	t.Run("sends one reminder per due date", func(t *testing.T) {
+		setTestNow(t, now)
+		horseRepo := new(mocks.MockHorseRepository)
+		healthRepo := new(mocks.MockHealthRepository)
+		reminders := new(mocks.MockHealthReminderRepository)
+		notifier := new(mockNotifier)
+		eggCounts := new(mocks.MockFecalEggCountRepository)
+		eggCounts.On("ListByHorse", mock.Anything, mock.Anything).Return([]models.FecalEggCount{}, nil)
+		farrier := new(mocks.MockFarrierRepository)
+		farrier.On("GetSchedule", mock.Anything, mock.Anything).Return(nil, nil)
+		farrier.On("ListVisitsByHorse", mock.Anything, mock.Anything).Return([]models.FarrierVisit{}, nil)
+		svc := NewHealthScheduleService(NewHorseService(horseRepo, new(mocks.MockOrganizationRepository), nil), horseRepo, healthRepo, eggCounts, farrier, reminders, notifier)
+		horse := models.Horse{ID: 1, UserID: "owner", Name: "Storm", BirthDate: now.AddDate(-8, 0, 0)}
+		horseRepo.On("ListActive", ctx).Return([]models.Horse{horse}, nil)
+		healthRepo.On("GetRecords", ctx, uint(1)).Return(upToDateRecords(now), nil)
+		reminders.On("MarkSent", ctx, mock.MatchedBy(func(r *models.HealthReminder) bool {
+			return r.HorseID == 1 && r.Item == "Deworming"
+		})).Return(true, nil).Once()
+		notifier.On("SendNotification", ctx, mock.MatchedBy(func(n *notification.Notification) bool {
+			return n.Type == notification.HealthCheckDue && n.Priority == notification.High && n.UserID == "owner"
+		})).Return(nil).Once()
+
+		sent, err := svc.SendReminders(ctx)
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, sent)
+		reminders.AssertExpectations(t)
+		notifier.AssertExpectations(t)
+	})
+
+	t.Run("already reminded", func(t *testing.T) {
+		setTestNow(t, now)
+		horseRepo := new(mocks.MockHorseRepository)
+		healthRepo := new(mocks.MockHealthRepository)
+		reminders := new(mocks.MockHealthReminderRepository)
+		notifier := new(mockNotifier)
+		eggCounts := new(mocks.MockFecalEggCountRepository)
+		eggCounts.On("ListByHorse", mock.Anything, mock.Anything).Return([]models.FecalEggCount{}, nil)
+		farrier := new(mocks.MockFarrierRepository)
+		farrier.On("GetSchedule", mock.Anything, mock.Anything).Return(nil, nil)
+		farrier.On("ListVisitsByHorse", mock.Anything, mock.Anything).Return([]models.FarrierVisit{}, nil)
+		svc := NewHealthScheduleService(NewHorseService(horseRepo, new(mocks.MockOrganizationRepository), nil), horseRepo, healthRepo, eggCounts, farrier, reminders, notifier)
+		horseRepo.On("ListActive", ctx).Return([]models.Horse{{ID: 1, BirthDate: now.AddDate(-8, 0, 0)}}, nil)
+		healthRepo.On("GetRecords", ctx, uint(1)).Return(upToDateRecords(now), nil)
+		reminders.On("MarkSent", ctx, mock.Anything).Return(false, nil)
+
+		sent, err := svc.SendReminders(ctx)
+
+		require.NoError(t, err)
+		assert.Zero(t, sent)
+		notifier.AssertNotCalled(t, "SendNotification", mock.Anything, mock.Anything)
+	})
+
+	t.Run("vaccines due soon use vaccination reminders", func(t *testing.T) {
+		setTestNow(t, now)
+		horseRepo := new(mocks.MockHorseRepository)
+		healthRepo := new(mocks.MockHealthRepository)
+		reminders := new(mocks.MockHealthReminderRepository)
+		notifier := new(mockNotifier)
+		eggCounts := new(mocks.MockFecalEggCountRepository)
+		eggCounts.On("ListByHorse", mock.Anything, mock.Anything).Return([]models.FecalEggCount{}, nil)
+		farrier := new(mocks.MockFarrierRepository)
+		farrier.On("GetSchedule", mock.Anything, mock.Anything).Return(nil, nil)
+		farrier.On("ListVisitsByHorse", mock.Anything, mock.Anything).Return([]models.FarrierVisit{}, nil)
+		svc := NewHealthScheduleService(NewHorseService(horseRepo, new(mocks.MockOrganizationRepository), nil), horseRepo, healthRepo, eggCounts, farrier, reminders, notifier)
+		horseRepo.On("ListActive", ctx).Return([]models.Horse{{ID: 1, BirthDate: now.AddDate(-8, 0, 0)}}, nil)
+		healthRepo.On("GetRecords", ctx, uint(1)).Return([]models.HealthRecord{
+			{Type: "VACCINATION", Date: now.AddDate(-1, 0, 14), Description: "Tetanus"},
+		}, nil)
+		reminders.On("MarkSent", ctx, mock.Anything).Return(true, nil)
+		notifier.On("SendNotification", ctx, mock.MatchedBy(func(n *notification.Notification) bool {
+			return n.Type == notification.VaccinationDue && n.Priority == notification.Medium
+		})).Return(nil).Once()
+
+		sent, err := svc.SendReminders(ctx)
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, sent, "items never recorded do not trigger reminders")
+		notifier.AssertExpectations(t)
+	})
+}
@@ -9,21 +9,31 @@ import (
 )
 
 type HealthServiceImpl struct {
-	repo repository.HealthRepository
+	repo   repository.HealthRepository
+	horses HorseService
 }
 
-func NewHealthService(repo repository.HealthRepository) HealthService {
-	return &HealthServiceImpl{repo: repo}
+func NewHealthService(repo repository.HealthRepository, horses HorseService) HealthService {
+	return &HealthServiceImpl{repo: repo, horses: horses}
 }
 
-func (s *HealthServiceImpl) CreateRecord(ctx context.Context, record *models.HealthRecord) error {
+func (s *HealthServiceImpl) CreateRecord(ctx context.Context, userID string, record *models.HealthRecord) error {
+	if _, err := s.horses.GetForRecords(ctx, userID, record.HorseID, models.RecordScopeHealth, models.PermissionLog); err != nil {
+		return err
+	}
+	if err := record.ValidateDetails(); err != nil {
+		return err
+	}
 	if err := s.repo.CreateRecord(ctx, record); err != nil {
 		return fmt.Errorf("failed to create health record: %w", err)
 	}
 	return nil
 }
 
-func (s *HealthServiceImpl) GetRecords(ctx context.Context, horseID uint) ([]models.HealthRecord, error) {
+func (s *HealthServiceImpl) GetRecords(ctx context.Context, userID string, horseID uint) ([]models.HealthRecord, error) {
+	if _, err := s.horses.GetForRecords(ctx, userID, horseID, models.RecordScopeHealth, models.PermissionView); err != nil {
+		return nil, err
+	}
 	records, err := s.repo.GetRecords(ctx, horseID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get health records: %w", err)
@@ -31,16 +41,47 @@ func (s *HealthServiceImpl) GetRecords(ctx context.Context, horseID uint) ([]mod
 	return records, nil
 }
 
-func (s *HealthServiceImpl) UpdateRecord(ctx context.Context, record *models.HealthRecord) error {
+// FindRecords filters a horse's health records by type, date range and payload fields
+func (s *HealthServiceImpl) FindRecords(ctx context.Context, userID string, horseID uint, filter models.HealthRecordFilter) ([]models.HealthRecord, error) {
+	if _, err := s.horses.GetForRecords(ctx, userID, horseID, models.RecordScopeHealth, models.PermissionView); err != nil {
+		return nil, err
+	}
+	if len(filter.Fields) > 0 && !filter.Type.IsValid() {
+		return nil, fmt.Errorf("%w: filtering by details requires a type", models.ErrInvalidHealthRecord)
+	}
+	for field := range filter.Fields {
+		if _, _, ok := models.DetailFieldPath(filter.Type, field); !ok {
+			return nil, fmt.Errorf("%w: cannot filter %s records by %q", models.ErrInvalidHealthRecord, filter.Type, field)
+		}
+	}
+
+	records, err := s.repo.FindRecords(ctx, horseID, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find health records: %w", err)
+	}
+	return records, nil
+}
+
+func (s *HealthServiceImpl) UpdateRecord(ctx context.Context, userID string, record *models.HealthRecord) error {
+	if _, err := s.horses.GetForRecords(ctx, userID, record.HorseID, models.RecordScopeHealth, models.PermissionLog); err != nil {
+		return err
+	}
+	if err := record.ValidateDetails(); err != nil {
+		return err
+	}
 	if err := s.repo.UpdateRecord(ctx, record); err != nil {
 		return fmt.Errorf("failed to update health record: %w", err)
 	}
 	return nil
 }
 
-func (s *HealthServiceImpl) DeleteRecord(ctx context.Context, id uint) error {
+// DeleteRecord removes a health record; vet grants never allow deleting
+func (s *HealthServiceImpl) DeleteRecord(ctx context.Context, userID string, horseID, id uint) error {
+	if _, err := s.horses.GetByID(ctx, userID, horseID, models.PermissionDelete); err != nil {
+		return err
+	}
 	if err := s.repo.DeleteRecord(ctx, id); err != nil {
 		return fmt.Errorf("failed to delete health record: %w", err)
 	}
 	return nil
-}
\ No newline at end of file
+}
@@ -0,0 +1,142 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/polyfant/hulta_pregnancy_app/internal/mocks"
+	"github.com/polyfant/hulta_pregnancy_app/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestHealthService_CreateRecordValidatesDetails(t *testing.T) {
+	ctx := context.Background()
+	date := time.Date(2024, 4, 10, 0, 0, 0, 0, time.UTC)
+	before := date.AddDate(0, 0, -1)
+
+	tests := []struct {
+		name    string
+		record  models.HealthRecord
+		wantErr bool
+	}{
+		{
+			name:   "description only record",
+			record: models.HealthRecord{Type: "Checkup", Description: "All good", Date: date},
+		},
+		{
+			name: "vaccination with product and lot",
+			record: models.HealthRecord{Type: string(models.HealthRecordTypeVaccination), Date: date, Details: &models.HealthRecordDetails{
+				Vaccination: &models.VaccinationDetails{Product: "Equilis Prequenza Te", LotNumber: "A123", Route: models.VaccinationRouteIntramuscular},
+			}},
+		},
+		{
+			name: "vaccination without product",
+			record: models.HealthRecord{Type: string(models.HealthRecordTypeVaccination), Date: date, Details: &models.HealthRecordDetails{
+				Vaccination: &models.VaccinationDetails{LotNumber: "A123"},
+			}},
+			wantErr: true,
+		},
+		{
+			name: "vaccination next due before record date",
+			record: models.HealthRecord{Type: string(models.HealthRecordTypeVaccination), Date: date, Details: &models.HealthRecordDetails{
+				Vaccination: &models.VaccinationDetails{Product: "Tetanus", NextDueDate: &before},
+			}},
+			wantErr: true,
+		},
+		{
+			name: "deworming needs a dose",
+			record: models.HealthRecord{Type: string(models.HealthRecordTypeDeworming), Date: date, Details: &models.HealthRecordDetails{
+				Deworming: &models.DewormingDetails{ActiveIngredient: "ivermectin"},
+			}},
+			wantErr: true,
+		},
+		{
+			name: "payload must match type",
+			record: models.HealthRecord{Type: string(models.HealthRecordTypeDental), Date: date, Details: &models.HealthRecordDetails{
+				Injury: &models.InjuryDetails{Location: "Left fore", Severity: models.InjurySeverityMinor},
+			}},
+			wantErr: true,
+		},
+		{
+			name: "injury with unknown severity",
+			record: models.HealthRecord{Type: string(models.HealthRecordTypeInjury), Date: date, Details: &models.HealthRecordDetails{
+				Injury: &models.InjuryDetails{Location: "Left fore", Severity: "BAD"},
+			}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(mocks.MockHealthRepository)
+			if !tt.wantErr {
+				repo.On("CreateRecord", ctx, mock.AnythingOfType("*models.HealthRecord")).Return(nil)
+			}
+
+			horseRepo := new(mocks.MockHorseRepository)
+			horseRepo.On("GetByID", ctx, uint(1)).Return(&models.Horse{ID: 1, UserID: "owner"}, nil)
+
+			svc := NewHealthService(repo, NewHorseService(horseRepo, new(mocks.MockOrganizationRepository), nil))
+			record := tt.record
+			record.HorseID = 1
+			err := svc.CreateRecord(ctx, "owner", &record)
+
+			if tt.wantErr {
+				assert.ErrorIs(t, err, models.ErrInvalidHealthRecord)
+				repo.AssertNotCalled(t, "CreateRecord", mock.Anything, mock.Anything)
+			} else {
+				assert.NoError(t, err)
+				repo.AssertExpectations(t)
+			}
+		})
+	}
+}
+
+func TestHealthService_FindRecords(t *testing.T) {
+	ctx := context.Background()
+	repo := new(mocks.MockHealthRepository)
+	filter := models.HealthRecordFilter{
+		Type:   models.HealthRecordTypeVaccination,
+		Fields: map[string]string{"lot_number": "A123"},
+	}
+	repo.On("FindRecords", ctx, uint(2), filter).Return([]models.HealthRecord{{ID: 9}}, nil)
+
+	horseRepo := new(mocks.MockHorseRepository)
+	horseRepo.On("GetByID", ctx, uint(2)).Return(&models.Horse{ID: 2, UserID: "owner"}, nil)
+
+	svc := NewHealthService(repo, NewHorseService(horseRepo, new(mocks.MockOrganizationRepository), nil))
+
+	records, err := svc.FindRecords(ctx, "owner", 2, filter)
+	assert.NoError(t, err)
+	assert.Len(t, records, 1)
+
+	_, err = svc.FindRecords(ctx, "owner", 2, models.HealthRecordFilter{Fields: map[string]string{"lot_number": "A123"}})
+	assert.ErrorIs(t, err, models.ErrInvalidHealthRecord)
+
+	_, err = svc.FindRecords(ctx, "owner", 2, models.HealthRecordFilter{Type: models.HealthRecordTypeVaccination, Fields: map[string]string{"1=1; --": "x"}})
+	assert.ErrorIs(t, err, models.ErrInvalidHealthRecord)
+}
+
+func TestHealthService_DeniesStrangers(t *testing.T) {
+	ctx := context.Background()
+	repo := new(mocks.MockHealthRepository)
+	horseRepo := new(mocks.MockHorseRepository)
+	horseRepo.On("GetByID", ctx, uint(2)).Return(&models.Horse{ID: 2, UserID: "owner"}, nil)
+
+	svc := NewHealthService(repo, NewHorseService(horseRepo, new(mocks.MockOrganizationRepository), nil))
+
+	_, err := svc.GetRecords(ctx, "stranger", 2)
+	assert.ErrorIs(t, err, models.ErrAccessDenied)
+
+	err = svc.CreateRecord(ctx, "stranger", &models.HealthRecord{HorseID: 2, Type: "Checkup", Description: "All good"})
+	assert.ErrorIs(t, err, models.ErrAccessDenied)
+
+	err = svc.DeleteRecord(ctx, "stranger", 2, 9)
+	assert.ErrorIs(t, err, models.ErrAccessDenied)
+
+	repo.AssertNotCalled(t, "GetRecords", mock.Anything, mock.Anything)
+	repo.AssertNotCalled(t, "CreateRecord", mock.Anything, mock.Anything)
+	repo.AssertNotCalled(t, "DeleteRecord", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,14 @@
+package service
+
+import (
+	"testing"
+	"time"
+)
+
+// setTestNow makes timeNow return now until the test ends
+func setTestNow(t *testing.T, now time.Time) {
+	t.Helper()
+	saved := timeNow
+	timeNow = func() time.Time { return now }
+	t.Cleanup(func() { timeNow = saved })
+}
@@ -0,0 +1,436 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/polyfant/hulta_pregnancy_app/internal/models"
+	"github.com/polyfant/hulta_pregnancy_app/internal/validation"
+)
+
+// MaxImportRows limits how many horses a single import may contain
+const MaxImportRows = 1000
+
+const importDateLayout = "2006-01-02"
+
+// importColumns maps normalized CSV header names to import fields. It accepts
+// the columns written by ExportHorsesToCSV plus extended fields.
+var importColumns = map[string]string{
+	"id":                 "id",
+	"name":               "name",
+	"breed":              "breed",
+	"dateofbirth":        "birth_date",
+	"birthdate":          "birth_date",
+	"conceptiondate":     "conception_date",
+	"motherid":           "mother_id",
+	"damid":              "mother_id",
+	"fatherid":           "father_id",
+	"sireid":             "father_id",
+	"status":             "status",
+	"statusdate":         "status_date",
+	"statusreason":       "status_reason",
+	"gender":             "gender",
+	"sex":                "gender",
+	"color":              "color",
+	"colour":             "color",
+	"weight":             "weight",
+	"height":             "height",
+	"registrationnumber": "registration_number",
+	"registrynumber":     "registration_number",
+	"mother":             "mother",
+	"dam":                "mother",
+	"father":             "father",
+	"sire":               "father",
+	"notes":              "notes",
+}
+
+// importRow holds one parsed CSV row before parents are resolved
+type importRow struct {
+	line      int
+	sourceID  string
+	horse     *models.Horse
+	motherID  string
+	fatherID  string
+	mother    string
+	father    string
+	motherRef *int
+	fatherRef *int
+}
+
+// parentIndex looks up candidate parents by source ID, registration number or name
+type parentIndex struct {
+	rowsBySourceID map[string]int
+	rowsByReg      map[string]int
+	rowsByName     map[string][]int
+	existingByID   map[uint]*models.Horse
+	existingByReg  map[string]*models.Horse
+	existingByName map[string][]*models.Horse
+}
+
+// ImportCSV imports horses for a user from a CSV export. Workbook formats
+// (.xlsx, .xls, .ods) are rejected with models.ErrImportNotCSV. Every row is
+// validated and all errors are reported; nothing is stored unless every row is
+// valid, and nothing at all is stored on a dry run.
+func (s *HorseServiceImpl) ImportCSV(ctx context.Context, userID string, r io.Reader, dryRun bool) (*models.HorseImportResult, error) {
+	records, err := readImportCSV(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(records) < 2 {
+		return nil, fmt.Errorf("CSV must contain a header row and at least one horse")
+	}
+	if len(records)-1 > MaxImportRows {
+		return nil, fmt.Errorf("CSV contains %d horses, the maximum is %d", len(records)-1, MaxImportRows)
+	}
+
+	columns, err := mapImportHeader(records[0])
+	if err != nil {
+		return nil, err
+	}
+
+	result := &models.HorseImportResult{
+		DryRun: dryRun,
+		Rows:   len(records) - 1,
+		Errors: []models.HorseImportError{},
+	}
+
+	rows := make([]*importRow, 0, len(records)-1)
+	for i, record := range records[1:] {
+		row, rowErrors := parseImportRow(i+2, record, columns, userID)
+		result.Errors = append(result.Errors, rowErrors...)
+		rows = append(rows, row)
+	}
+
+	existing, err := s.repo.ListAllByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load existing horses: %w", err)
+	}
+
+	index, indexErrors := buildParentIndex(rows, existing)
+	result.Errors = append(result.Errors, indexErrors...)
+
+	validator := validation.NewHorseValidator()
+	for i, row := range rows {
+		result.Errors = append(result.Errors, resolveParents(i, row, rows, index)...)
+		if err := validator.ValidateHorse(row.horse); err != nil {
+			result.Errors = append(result.Errors, models.HorseImportError{Row: row.line, Message: err.Error()})
+		}
+	}
+
+	result.Horses = make([]models.Horse, len(rows))
+	for i, row := range rows {
+		result.Horses[i] = *row.horse
+	}
+
+	if dryRun || result.HasErrors() {
+		return result, nil
+	}
+
+	items := make([]models.HorseImportItem, len(rows))
+	for i, row := range rows {
+		items[i] = models.HorseImportItem{Horse: row.horse, MotherRef: row.motherRef, FatherRef: row.fatherRef}
+	}
+	if err := s.repo.CreateBatch(ctx, items); err != nil {
+		return nil, fmt.Errorf("failed to import horses: %w", err)
+	}
+
+	for i, row := range rows {
+		result.Horses[i] = *row.horse
+	}
+	result.Imported = len(rows)
+	return result, nil
+}
+
+// spreadsheetSignatures are the leading bytes of workbook files: .xlsx and
+// .ods are zip archives, .xls is an OLE compound document
+var spreadsheetSignatures = [][]byte{
+	{0x50, 0x4B, 0x03, 0x04},
+	{0xD0, 0xCF, 0x11, 0xE0},
+}
+
+// readImportCSV reads all records, accepting the byte order mark and
+// semicolon delimiter that spreadsheet programs often write
+func readImportCSV(r io.Reader) ([][]string, error) {
+	br := bufio.NewReader(r)
+	if head, err := br.Peek(4); err == nil {
+		for _, signature := range spreadsheetSignatures {
+			if bytes.Equal(head, signature) {
+				return nil, models.ErrImportNotCSV
+			}
+		}
+	}
+	if bom, err := br.Peek(3); err == nil && bytes.Equal(bom, []byte{0xEF, 0xBB, 0xBF}) {
+		br.Discard(3)
+	}
+
+	data, err := io.ReadAll(br)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV: %w", err)
+	}
+
+	reader := csv.NewReader(bytes.NewReader(data))
+	headerLine, _, _ := strings.Cut(string(data), "\n")
+	if strings.Count(headerLine, ";") > strings.Count(headerLine, ",") {
+		reader.Comma = ';'
+	}
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse CSV: %w", err)
+	}
+	return records, nil
+}
+
+// mapImportHeader returns the column index of every recognised field
+func mapImportHeader(header []string) (map[string]int, error) {
+	columns := make(map[string]int)
+	for i, name := range header {
+		normalized := strings.NewReplacer(" ", "", "_", "", "-", "").Replace(strings.ToLower(strings.TrimSpace(name)))
+		field, ok := importColumns[normalized]
+		if !ok {
+			continue
+		}
+		if _, dup := columns[field]; dup {
+			return nil, fmt.Errorf("duplicate column %q", name)
+		}
+		columns[field] = i
+	}
+
+	if _, ok := columns["name"]; !ok {
+		return nil, fmt.Errorf("CSV must contain a Name column")
+	}
+	return columns, nil
+}
+
+func parseImportRow(line int, record []string, columns map[string]int, userID string) (*importRow, []models.HorseImportError) {
+	var errs []models.HorseImportError
+	get := func(field string) string {
+		i, ok := columns[field]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+	fail := func(column, format string, args ...interface{}) {
+		errs = append(errs, models.HorseImportError{Row: line, Column: column, Message: fmt.Sprintf(format, args...)})
+	}
+	parseDate := func(field string) *time.Time {
+		value := get(field)
+		if value == "" {
+			return nil
+		}
+		date, err := time.Parse(importDateLayout, value)
+		if err != nil {
+			fail(field, "invalid date %q, expected YYYY-MM-DD", value)
+			return nil
+		}
+		return &date
+	}
+	parseFloat := func(field string) float64 {
+		value := get(field)
+		if value == "" {
+			return 0
+		}
+		f, err := strconv.ParseFloat(strings.Replace(value, ",", ".", 1), 64)
+		if err != nil {
+			fail(field, "invalid number %q", value)
+		}
+		return f
+	}
+
+	horse := &models.Horse{
+		UserID:             userID,
+		Name:               get("name"),
+		Breed:              get("breed"),
+		Color:              get("color"),
+		RegistrationNumber: get("registration_number"),
+		Notes:              get("notes"),
+		Weight:             parseFloat("weight"),
+		Height:             parseFloat("height"),
+		Status:             models.HorseStatusActive,
+	}
+
+	if birthDate := parseDate("birth_date"); birthDate != nil {
+		horse.BirthDate = *birthDate
+	}
+	if gender := get("gender"); gender != "" {
+		horse.Gender = models.Gender(strings.ToUpper(gender))
+		if !horse.ValidateGender() {
+			fail("gender", "invalid gender %q, expected MARE, STALLION or GELDING", gender)
+		}
+	}
+
+	// A conception date makes a mare pregnant. Without a gender the row does
+	// not say she is a mare, so the date is left out rather than guessed at.
+	if conception := parseDate("conception_date"); conception != nil && horse.Gender != "" {
+		horse.ConceptionDate = conception
+		horse.IsPregnant = true
+	}
+
+	if status := get("status"); status != "" {
+		horse.Status = models.HorseStatus(strings.ToUpper(status))
+		if !horse.Status.IsValid() {
+			fail("status", "invalid status %q", status)
+		}
+		horse.StatusDate = parseDate("status_date")
+		horse.StatusReason = get("status_reason")
+		if horse.Status == models.HorseStatusArchived {
+			horse.DeletedAt = horse.StatusDate
+		}
+	}
+
+	return &importRow{
+		line:     line,
+		sourceID: get("id"),
+		horse:    horse,
+		motherID: get("mother_id"),
+		fatherID: get("father_id"),
+		mother:   get("mother"),
+		father:   get("father"),
+	}, errs
+}
+
+func buildParentIndex(rows []*importRow, existing []models.Horse) (*parentIndex, []models.HorseImportError) {
+	var errs []models.HorseImportError
+	index := &parentIndex{
+		rowsBySourceID: make(map[string]int),
+		rowsByReg:      make(map[string]int),
+		rowsByName:     make(map[string][]int),
+		existingByID:   make(map[uint]*models.Horse),
+		existingByReg:  make(map[string]*models.Horse),
+		existingByName: make(map[string][]*models.Horse),
+	}
+
+	for i := range existing {
+		horse := &existing[i]
+		index.existingByID[horse.ID] = horse
+		if horse.RegistrationNumber != "" {
+			index.existingByReg[strings.ToLower(horse.RegistrationNumber)] = horse
+		}
+		name := strings.ToLower(horse.Name)
+		index.existingByName[name] = append(index.existingByName[name], horse)
+	}
+
+	for i, row := range rows {
+		if row.sourceID != "" {
+			if first, dup := index.rowsBySourceID[row.sourceID]; dup {
+				errs = append(errs, models.HorseImportError{Row: row.line, Column: "id", Message: fmt.Sprintf("duplicate ID %s, also used on row %d", row.sourceID, rows[first].line)})
+			} else {
+				index.rowsBySourceID[row.sourceID] = i
+			}
+		}
+
+		if reg := strings.ToLower(row.horse.RegistrationNumber); reg != "" {
+			if first, dup := index.rowsByReg[reg]; dup {
+				errs = append(errs, models.HorseImportError{Row: row.line, Column: "registration_number", Message: fmt.Sprintf("duplicate registration number, also used on row %d", rows[first].line)})
+			} else if _, exists := index.existingByReg[reg]; exists {
+				errs = append(errs, models.HorseImportError{Row: row.line, Column: "registration_number", Message: "a horse with this registration number already exists"})
+			} else {
+				index.rowsByReg[reg] = i
+			}
+		}
+
+		name := strings.ToLower(row.horse.Name)
+		index.rowsByName[name] = append(index.rowsByName[name], i)
+	}
+
+	return index, errs
+}
+
+// resolveParents links a row to its mother and father. Exported IDs refer to
+// rows in the same file or to the user's existing horses; names and
+// registration numbers are matched against both, and names that match
+// nothing are kept as external parents.
+func resolveParents(i int, row *importRow, rows []*importRow, index *parentIndex) []models.HorseImportError {
+	var errs []models.HorseImportError
+
+	resolve := func(column, id, ref string, wantGender models.Gender) (rowRef *int, horseID *uint, external string) {
+		fail := func(format string, args ...interface{}) {
+			errs = append(errs, models.HorseImportError{Row: row.line, Column: column, Message: fmt.Sprintf(format, args...)})
+		}
+		// Sires may have been gelded since, so only the mare/non-mare split is checked
+		checkGender := func(gender models.Gender) bool {
+			if gender == "" || (gender == models.GenderMare) == (wantGender == models.GenderMare) {
+				return true
+			}
+			if wantGender == models.GenderMare {
+				fail("mother must be a mare")
+			} else {
+				fail("father cannot be a mare")
+			}
+			return false
+		}
+		linkRow := func(j int) (*int, *uint, string) {
+			if j == i {
+				fail("a horse cannot be its own parent")
+				return nil, nil, ""
+			}
+			if !checkGender(rows[j].horse.Gender) {
+				return nil, nil, ""
+			}
+			return &j, nil, ""
+		}
+		linkHorse := func(h *models.Horse) (*int, *uint, string) {
+			if !checkGender(h.Gender) {
+				return nil, nil, ""
+			}
+			id := h.ID
+			return nil, &id, ""
+		}
+
+		if id != "" {
+			if j, ok := index.rowsBySourceID[id]; ok {
+				return linkRow(j)
+			}
+			parsed, err := strconv.ParseUint(id, 10, 64)
+			if err == nil {
+				if h, ok := index.existingByID[uint(parsed)]; ok {
+					return linkHorse(h)
+				}
+			}
+			fail("parent ID %s matches no horse in the file or your stable", id)
+			return nil, nil, ""
+		}
+
+		if ref == "" {
+			return nil, nil, ""
+		}
+		key := strings.ToLower(ref)
+		if j, ok := index.rowsByReg[key]; ok {
+			return linkRow(j)
+		}
+		if h, ok := index.existingByReg[key]; ok {
+			return linkHorse(h)
+		}
+
+		fileMatches := index.rowsByName[key]
+		existingMatches := index.existingByName[key]
+		switch {
+		case len(fileMatches)+len(existingMatches) > 1:
+			fail("parent name %q is ambiguous, use a registration number", ref)
+			return nil, nil, ""
+		case len(fileMatches) == 1:
+			return linkRow(fileMatches[0])
+		case len(existingMatches) == 1:
+			return linkHorse(existingMatches[0])
+		}
+		return nil, nil, ref
+	}
+
+	var motherID, fatherID *uint
+	row.motherRef, motherID, row.horse.ExternalMother = resolve("mother", row.motherID, row.mother, models.GenderMare)
+	row.fatherRef, fatherID, row.horse.ExternalFather = resolve("father", row.fatherID, row.father, models.GenderStallion)
+	row.horse.MotherId, row.horse.DamID = motherID, motherID
+	row.horse.FatherId, row.horse.SireID = fatherID, fatherID
+
+	return errs
+}
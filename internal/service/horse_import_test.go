@@ -0,0 +1,135 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/polyfant/hulta_pregnancy_app/internal/mocks"
+	"github.com/polyfant/hulta_pregnancy_app/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHorseService_ImportCSV(t *testing.T) {
+	ctx := context.Background()
+	existing := []models.Horse{
+		{ID: 40, UserID: "user1", Name: "Old Mare", Gender: models.GenderMare, RegistrationNumber: "SE-001"},
+	}
+
+	t.Run("export format with parents in the same file", func(t *testing.T) {
+		repo := new(mocks.MockHorseRepository)
+		repo.On("ListAllByUser", ctx, "user1").Return(existing, nil)
+		repo.On("CreateBatch", ctx, mock.AnythingOfType("[]models.HorseImportItem")).Return(nil)
+		svc := NewHorseService(repo, new(mocks.MockOrganizationRepository), nil)
+
+		csv := "ID,Name,Breed,Date of Birth,Conception Date,Mother ID,Father ID,Gender\n" +
+			"1,Bella,Arabian,2015-04-01,,,,MARE\n" +
+			"2,Storm,Arabian,2014-05-01,,,,STALLION\n" +
+			"3,Luna,Arabian,2019-06-01,,1,2,MARE\n"
+
+		result, err := svc.ImportCSV(ctx, "user1", strings.NewReader(csv), false)
+
+		require.NoError(t, err)
+		assert.Empty(t, result.Errors)
+		assert.Equal(t, 3, result.Imported)
+
+		items := repo.Calls[1].Arguments.Get(1).([]models.HorseImportItem)
+		require.Len(t, items, 3)
+		require.NotNil(t, items[2].MotherRef)
+		require.NotNil(t, items[2].FatherRef)
+		assert.Equal(t, 0, *items[2].MotherRef)
+		assert.Equal(t, 1, *items[2].FatherRef)
+	})
+
+	t.Run("dry run resolves existing parents and stores nothing", func(t *testing.T) {
+		repo := new(mocks.MockHorseRepository)
+		repo.On("ListAllByUser", ctx, "user1").Return(existing, nil)
+		svc := NewHorseService(repo, new(mocks.MockOrganizationRepository), nil)
+
+		csv := "\xEF\xBB\xBFName;Date of Birth;Gender;Dam;Sire\n" +
+			"Nova;2018-03-01;mare;SE-001;Unknown Stallion\n"
+
+		result, err := svc.ImportCSV(ctx, "user1", strings.NewReader(csv), true)
+
+		require.NoError(t, err)
+		assert.True(t, result.DryRun)
+		assert.Empty(t, result.Errors)
+		assert.Equal(t, 0, result.Imported)
+		require.Len(t, result.Horses, 1)
+		require.NotNil(t, result.Horses[0].MotherId)
+		assert.Equal(t, uint(40), *result.Horses[0].MotherId)
+		assert.Equal(t, "Unknown Stallion", result.Horses[0].ExternalFather)
+		repo.AssertNotCalled(t, "CreateBatch", mock.Anything, mock.Anything)
+	})
+
+	t.Run("row errors prevent commit", func(t *testing.T) {
+		repo := new(mocks.MockHorseRepository)
+		repo.On("ListAllByUser", ctx, "user1").Return(existing, nil)
+		svc := NewHorseService(repo, new(mocks.MockOrganizationRepository), nil)
+
+		csv := "Name,Date of Birth,Gender,Mother ID,Registration Number\n" +
+			"Good,2016-01-01,MARE,,\n" +
+			"BadDate,01/02/2016,MARE,,\n" +
+			"Orphan,2016-01-01,MARE,999,\n" +
+			"Copy,2016-01-01,MARE,,SE-001\n"
+
+		result, err := svc.ImportCSV(ctx, "user1", strings.NewReader(csv), false)
+
+		require.NoError(t, err)
+		assert.Equal(t, 0, result.Imported)
+		rows := map[int]bool{}
+		for _, e := range result.Errors {
+			rows[e.Row] = true
+		}
+		assert.Equal(t, map[int]bool{3: true, 4: true, 5: true}, rows)
+		repo.AssertNotCalled(t, "CreateBatch", mock.Anything, mock.Anything)
+	})
+
+	t.Run("foals and pregnancies", func(t *testing.T) {
+		repo := new(mocks.MockHorseRepository)
+		repo.On("ListAllByUser", ctx, "user1").Return(existing, nil)
+		svc := NewHorseService(repo, new(mocks.MockOrganizationRepository), nil)
+
+		foaled := time.Now().AddDate(0, -3, 0).Format("2006-01-02")
+		conceived := time.Now().AddDate(0, -5, 0).Format("2006-01-02")
+		csv := "Name,Gender,Date of Birth,Conception Date\n" +
+			"Foal,MARE," + foaled + ",\n" +
+			"Bella,MARE,2015-04-01," + conceived + "\n" +
+			"Unknown,,2015-04-01," + conceived + "\n" +
+			"Storm,STALLION,2014-05-01," + conceived + "\n" +
+			"Filly,MARE," + time.Now().AddDate(-1, 0, 0).Format("2006-01-02") + "," + conceived + "\n"
+
+		result, err := svc.ImportCSV(ctx, "user1", strings.NewReader(csv), true)
+
+		require.NoError(t, err)
+		require.Len(t, result.Horses, 5)
+		assert.True(t, result.Horses[1].IsPregnant)
+		assert.False(t, result.Horses[2].IsPregnant)
+		assert.Nil(t, result.Horses[2].ConceptionDate)
+		rows := map[int]bool{}
+		for _, e := range result.Errors {
+			rows[e.Row] = true
+		}
+		assert.Equal(t, map[int]bool{5: true, 6: true}, rows)
+	})
+
+	t.Run("missing name column", func(t *testing.T) {
+		svc := NewHorseService(new(mocks.MockHorseRepository), new(mocks.MockOrganizationRepository), nil)
+
+		_, err := svc.ImportCSV(ctx, "user1", strings.NewReader("Breed\nArabian\n"), true)
+
+		assert.Error(t, err)
+	})
+
+	t.Run("workbook upload is rejected", func(t *testing.T) {
+		svc := NewHorseService(new(mocks.MockHorseRepository), new(mocks.MockOrganizationRepository), nil)
+		xlsx := "PK\x03\x04\x14\x00\x06\x00[Content_Types].xml"
+
+		_, err := svc.ImportCSV(ctx, "user1", strings.NewReader(xlsx), true)
+
+		assert.ErrorIs(t, err, models.ErrImportNotCSV)
+	})
+}
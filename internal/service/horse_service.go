@@ -2,37 +2,148 @@ package service
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	"github.com/polyfant/hulta_pregnancy_app/internal/models"
 	"github.com/polyfant/hulta_pregnancy_app/internal/repository"
 )
 
 type HorseServiceImpl struct {
-	repo repository.HorseRepository
+	repo      repository.HorseRepository
+	orgRepo   repository.OrganizationRepository
+	vetAccess VetAccessService
 }
 
-func NewHorseService(repo repository.HorseRepository) *HorseServiceImpl {
-	return &HorseServiceImpl{repo: repo}
+// NewHorseService creates a horse service. vetAccess may be nil, in which
+// case only owners and farm members can reach a horse's records.
+func NewHorseService(repo repository.HorseRepository, orgRepo repository.OrganizationRepository, vetAccess VetAccessService) *HorseServiceImpl {
+	return &HorseServiceImpl{repo: repo, orgRepo: orgRepo, vetAccess: vetAccess}
 }
 
-func (s *HorseServiceImpl) GetByID(ctx context.Context, id uint) (*models.Horse, error) {
-	return s.repo.GetByID(ctx, id)
+// GetByID loads a horse the user may act on with the given permission
+func (s *HorseServiceImpl) GetByID(ctx context.Context, userID string, id uint, permission models.Permission) (*models.Horse, error) {
+	horse, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get horse: %w", err)
+	}
+	if err := s.CheckAccess(ctx, userID, horse, permission); err != nil {
+		return nil, err
+	}
+	return horse, nil
 }
 
+// GetForRecords loads a horse for access to its health or pregnancy records.
+// Owners and farm members are checked first, then vet access grants.
+func (s *HorseServiceImpl) GetForRecords(ctx context.Context, userID string, id uint, scope models.RecordScope, permission models.Permission) (*models.Horse, error) {
+	horse, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get horse: %w", err)
+	}
+	if err := s.CheckAccess(ctx, userID, horse, permission); err == nil {
+		return horse, nil
+	}
+	if s.vetAccess == nil {
+		return nil, models.ErrAccessDenied
+	}
+	if err := s.vetAccess.Authorize(ctx, userID, horse, scope, permission); err != nil {
+		return nil, err
+	}
+	return horse, nil
+}
+
+// ListByUserID returns the user's own horses plus horses of every farm they belong to
 func (s *HorseServiceImpl) ListByUserID(ctx context.Context, userID string) ([]models.Horse, error) {
-	return s.repo.ListByUser(ctx, userID)
+	horses, err := s.repo.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	memberships, err := s.orgRepo.GetMemberships(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get organization memberships: %w", err)
+	}
+
+	seen := make(map[uint]bool, len(horses))
+	for _, horse := range horses {
+		seen[horse.ID] = true
+	}
+	for _, membership := range memberships {
+		orgHorses, err := s.repo.ListByOrganization(ctx, membership.OrganizationID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get organization horses: %w", err)
+		}
+		for _, horse := range orgHorses {
+			if !seen[horse.ID] {
+				seen[horse.ID] = true
+				horses = append(horses, horse)
+			}
+		}
+	}
+	return horses, nil
+}
+
+// ListAllByUserID returns every horse the user has owned, including sold, deceased and archived ones
+func (s *HorseServiceImpl) ListAllByUserID(ctx context.Context, userID string) ([]models.Horse, error) {
+	return s.repo.ListAllByUser(ctx, userID)
 }
 
 func (s *HorseServiceImpl) Create(ctx context.Context, horse *models.Horse) error {
+	horse.Status = models.HorseStatusActive
+	horse.StatusDate = nil
+	horse.StatusReason = ""
+	if err := s.validateOrganization(ctx, horse); err != nil {
+		return err
+	}
 	return s.repo.Create(context.Background(), horse)
 }
 
 func (s *HorseServiceImpl) Update(ctx context.Context, horse *models.Horse) error {
+	if err := s.validateOrganization(ctx, horse); err != nil {
+		return err
+	}
 	return s.repo.Update(context.Background(), horse)
 }
 
+// Delete archives a horse so its breeding history stays available for pedigrees and reports
 func (s *HorseServiceImpl) Delete(ctx context.Context, id uint) error {
-	return s.repo.Delete(context.Background(), id)
+	_, err := s.ChangeStatus(ctx, id, models.HorseStatusChange{
+		Status: models.HorseStatusArchived,
+		Reason: "Deleted by owner",
+	})
+	return err
+}
+
+// Purge permanently removes a horse and cannot be undone
+func (s *HorseServiceImpl) Purge(ctx context.Context, id uint) error {
+	return s.repo.Delete(ctx, id)
+}
+
+// ChangeStatus moves a horse to a new lifecycle status. The date defaults to now
+// and may not lie in the future.
+func (s *HorseServiceImpl) ChangeStatus(ctx context.Context, id uint, change models.HorseStatusChange) (*models.Horse, error) {
+	if !change.Status.IsValid() {
+		return nil, fmt.Errorf("%w: %q", models.ErrInvalidHorseStatus, change.Status)
+	}
+
+	now := time.Now()
+	date := now
+	if change.Date != nil {
+		date = *change.Date
+	}
+	if date.After(now) {
+		return nil, fmt.Errorf("%w: status date cannot be in the future", models.ErrInvalidHorseStatus)
+	}
+
+	if err := s.repo.UpdateStatus(ctx, id, change.Status, date, change.Reason); err != nil {
+		return nil, fmt.Errorf("failed to update horse status: %w", err)
+	}
+
+	horse, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get horse: %w", err)
+	}
+	return horse, nil
 }
 
 func (s *HorseServiceImpl) GetPregnant(ctx context.Context, userID string) ([]models.Horse, error) {
@@ -50,3 +161,37 @@ func (s *HorseServiceImpl) GetOffspring(ctx context.Context, horseID uint) ([]mo
 func (s *HorseServiceImpl) GetPregnantHorses(ctx context.Context, userID string) ([]models.Horse, error) {
 	return s.repo.GetPregnantHorses(ctx, userID)
 }
+
+// CheckAccess verifies that the user may perform the action on the horse.
+// Owners always have full access; other users need a farm membership whose
+// role grants the permission.
+func (s *HorseServiceImpl) CheckAccess(ctx context.Context, userID string, horse *models.Horse, permission models.Permission) error {
+	if horse.UserID == userID {
+		return nil
+	}
+	if horse.OrganizationID == nil {
+		return models.ErrAccessDenied
+	}
+
+	member, err := s.orgRepo.GetMember(ctx, *horse.OrganizationID, userID)
+	if err != nil {
+		return models.ErrAccessDenied
+	}
+	if !member.Role.Can(permission) {
+		return models.ErrAccessDenied
+	}
+	return nil
+}
+
+// validateOrganization ensures a horse is only placed in a farm where its owner may edit horses
+func (s *HorseServiceImpl) validateOrganization(ctx context.Context, horse *models.Horse) error {
+	if horse.OrganizationID == nil {
+		return nil
+	}
+
+	member, err := s.orgRepo.GetMember(ctx, *horse.OrganizationID, horse.UserID)
+	if err != nil || !member.Role.Can(models.PermissionEdit) {
+		return fmt.Errorf("owner cannot add horses to organization %d: %w", *horse.OrganizationID, models.ErrAccessDenied)
+	}
+	return nil
+}
@@ -0,0 +1,207 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/polyfant/hulta_pregnancy_app/internal/mocks"
+	"github.com/polyfant/hulta_pregnancy_app/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestHorseService_CheckAccess(t *testing.T) {
+	orgID := uint(7)
+
+	tests := []struct {
+		name       string
+		horse      *models.Horse
+		userID     string
+		permission models.Permission
+		member     *models.OrganizationMember
+		memberErr  error
+		wantErr    bool
+	}{
+		{
+			name:       "owner has full access",
+			horse:      &models.Horse{ID: 1, UserID: "owner"},
+			userID:     "owner",
+			permission: models.PermissionDelete,
+		},
+		{
+			name:       "stranger denied on private horse",
+			horse:      &models.Horse{ID: 1, UserID: "owner"},
+			userID:     "stranger",
+			permission: models.PermissionView,
+			wantErr:    true,
+		},
+		{
+			name:       "staff can log observations",
+			horse:      &models.Horse{ID: 1, UserID: "owner", OrganizationID: &orgID},
+			userID:     "groom",
+			permission: models.PermissionLog,
+			member:     &models.OrganizationMember{OrganizationID: orgID, UserID: "groom", Role: models.MemberRoleStaff},
+		},
+		{
+			name:       "staff cannot delete",
+			horse:      &models.Horse{ID: 1, UserID: "owner", OrganizationID: &orgID},
+			userID:     "groom",
+			permission: models.PermissionDelete,
+			member:     &models.OrganizationMember{OrganizationID: orgID, UserID: "groom", Role: models.MemberRoleStaff},
+			wantErr:    true,
+		},
+		{
+			name:       "farm manager can edit",
+			horse:      &models.Horse{ID: 1, UserID: "owner", OrganizationID: &orgID},
+			userID:     "manager",
+			permission: models.PermissionEdit,
+			member:     &models.OrganizationMember{OrganizationID: orgID, UserID: "manager", Role: models.MemberRoleFarmManager},
+		},
+		{
+			name:       "non-member denied on farm horse",
+			horse:      &models.Horse{ID: 1, UserID: "owner", OrganizationID: &orgID},
+			userID:     "stranger",
+			permission: models.PermissionView,
+			memberErr:  errors.New("record not found"),
+			wantErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orgRepo := new(mocks.MockOrganizationRepository)
+			if tt.member != nil || tt.memberErr != nil {
+				orgRepo.On("GetMember", mock.Anything, orgID, tt.userID).Return(tt.member, tt.memberErr)
+			}
+
+			svc := NewHorseService(new(mocks.MockHorseRepository), orgRepo, nil)
+			err := svc.CheckAccess(context.Background(), tt.userID, tt.horse, tt.permission)
+
+			if tt.wantErr {
+				assert.ErrorIs(t, err, models.ErrAccessDenied)
+			} else {
+				assert.NoError(t, err)
+			}
+			orgRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestHorseService_GetForRecords(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	horse := &models.Horse{ID: 1, UserID: "owner"}
+	vet := "vet"
+	readGrant := models.VetAccessGrant{
+		ID:            3,
+		OwnerID:       "owner",
+		VetUserID:     &vet,
+		AccessLevel:   models.AccessLevelRead,
+		IncludeHealth: true,
+		Horses:        []models.VetAccessGrantHorse{{HorseID: 1}},
+		ExpiresAt:     now.Add(time.Hour),
+	}
+
+	tests := []struct {
+		name       string
+		userID     string
+		grants     []models.VetAccessGrant
+		permission models.Permission
+		wantErr    bool
+	}{
+		{"owner reads without a grant", "owner", nil, models.PermissionView, false},
+		{"vet reads with a grant", "vet", []models.VetAccessGrant{readGrant}, models.PermissionView, false},
+		{"read grant cannot log", "vet", []models.VetAccessGrant{readGrant}, models.PermissionLog, true},
+		{"stranger without a grant", "stranger", []models.VetAccessGrant{}, models.PermissionView, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			horseRepo := new(mocks.MockHorseRepository)
+			horseRepo.On("GetByID", ctx, uint(1)).Return(horse, nil)
+			vetRepo := new(mocks.MockVetAccessRepository)
+			vetRepo.On("ListActiveForVetAndHorse", ctx, tt.userID, uint(1), now).Return(tt.grants, nil)
+			setTestNow(t, now)
+			vetAccess := NewVetAccessService(vetRepo, horseRepo, &stubPrivacy{shareWithVets: true}, &recordingAudit{})
+
+			svc := NewHorseService(horseRepo, new(mocks.MockOrganizationRepository), vetAccess)
+			got, err := svc.GetForRecords(ctx, tt.userID, 1, models.RecordScopeHealth, tt.permission)
+
+			if tt.wantErr {
+				assert.ErrorIs(t, err, models.ErrAccessDenied)
+				assert.Nil(t, got)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, horse, got)
+			}
+		})
+	}
+
+	t.Run("grants only cover records, not the horse", func(t *testing.T) {
+		horseRepo := new(mocks.MockHorseRepository)
+		horseRepo.On("GetByID", ctx, uint(1)).Return(horse, nil)
+		vetRepo := new(mocks.MockVetAccessRepository)
+		setTestNow(t, now)
+		vetAccess := NewVetAccessService(vetRepo, horseRepo, &stubPrivacy{shareWithVets: true}, &recordingAudit{})
+
+		svc := NewHorseService(horseRepo, new(mocks.MockOrganizationRepository), vetAccess)
+		_, err := svc.GetByID(ctx, "vet", 1, models.PermissionView)
+
+		assert.ErrorIs(t, err, models.ErrAccessDenied)
+		vetRepo.AssertNotCalled(t, "ListActiveForVetAndHorse", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestHorseService_ChangeStatus(t *testing.T) {
+	ctx := context.Background()
+	past := time.Now().AddDate(0, -1, 0)
+	future := time.Now().AddDate(0, 1, 0)
+
+	tests := []struct {
+		name    string
+		change  models.HorseStatusChange
+		wantErr bool
+	}{
+		{"sold with date and reason", models.HorseStatusChange{Status: models.HorseStatusSold, Date: &past, Reason: "Sold to neighbour"}, false},
+		{"deceased defaults to now", models.HorseStatusChange{Status: models.HorseStatusDeceased}, false},
+		{"unknown status", models.HorseStatusChange{Status: "MISSING"}, true},
+		{"future date", models.HorseStatusChange{Status: models.HorseStatusRetired, Date: &future}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(mocks.MockHorseRepository)
+			if !tt.wantErr {
+				repo.On("UpdateStatus", ctx, uint(1), tt.change.Status, mock.AnythingOfType("time.Time"), tt.change.Reason).Return(nil)
+				repo.On("GetByID", ctx, uint(1)).Return(&models.Horse{ID: 1, Status: tt.change.Status}, nil)
+			}
+
+			svc := NewHorseService(repo, new(mocks.MockOrganizationRepository), nil)
+			horse, err := svc.ChangeStatus(ctx, 1, tt.change)
+
+			if tt.wantErr {
+				assert.ErrorIs(t, err, models.ErrInvalidHorseStatus)
+				repo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.change.Status, horse.Status)
+				repo.AssertExpectations(t)
+			}
+		})
+	}
+}
+
+func TestHorseService_DeleteArchives(t *testing.T) {
+	ctx := context.Background()
+	repo := new(mocks.MockHorseRepository)
+	repo.On("UpdateStatus", ctx, uint(3), models.HorseStatusArchived, mock.AnythingOfType("time.Time"), mock.Anything).Return(nil)
+	repo.On("GetByID", ctx, uint(3)).Return(&models.Horse{ID: 3, Status: models.HorseStatusArchived}, nil)
+
+	svc := NewHorseService(repo, new(mocks.MockOrganizationRepository), nil)
+
+	assert.NoError(t, svc.Delete(ctx, 3))
+	repo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+	repo.AssertExpectations(t)
+}
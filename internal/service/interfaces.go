@@ -2,14 +2,17 @@ package service
 
 import (
 	"context"
+	"io"
 	"time"
 
 	"github.com/polyfant/hulta_pregnancy_app/internal/models"
+	"github.com/polyfant/hulta_pregnancy_app/internal/service/health"
 )
 
 // HorseService defines the interface for horse-related operations
 type HorseService interface {
-	GetByID(ctx context.Context, id uint) (*models.Horse, error)
+	GetByID(ctx context.Context, userID string, id uint, permission models.Permission) (*models.Horse, error)
+	GetForRecords(ctx context.Context, userID string, id uint, scope models.RecordScope, permission models.Permission) (*models.Horse, error)
 	Create(ctx context.Context, horse *models.Horse) error
 	Update(ctx context.Context, horse *models.Horse) error
 	Delete(ctx context.Context, id uint) error
@@ -18,32 +21,38 @@ type HorseService interface {
 	GetOffspring(ctx context.Context, horseID uint) ([]models.Horse, error)
 	GetPregnantHorses(ctx context.Context, userID string) ([]models.Horse, error)
 	ListByUserID(ctx context.Context, userID string) ([]models.Horse, error)
+	ListAllByUserID(ctx context.Context, userID string) ([]models.Horse, error)
+	ChangeStatus(ctx context.Context, id uint, change models.HorseStatusChange) (*models.Horse, error)
+	Purge(ctx context.Context, id uint) error
+	ImportCSV(ctx context.Context, userID string, r io.Reader, dryRun bool) (*models.HorseImportResult, error)
+	CheckAccess(ctx context.Context, userID string, horse *models.Horse, permission models.Permission) error
 }
 
 // HealthService defines the interface for health-related operations
 type HealthService interface {
-	CreateRecord(ctx context.Context, record *models.HealthRecord) error
-	GetRecords(ctx context.Context, horseID uint) ([]models.HealthRecord, error)
-	UpdateRecord(ctx context.Context, record *models.HealthRecord) error
-	DeleteRecord(ctx context.Context, id uint) error
+	CreateRecord(ctx context.Context, userID string, record *models.HealthRecord) error
+	GetRecords(ctx context.Context, userID string, horseID uint) ([]models.HealthRecord, error)
+	FindRecords(ctx context.Context, userID string, horseID uint, filter models.HealthRecordFilter) ([]models.HealthRecord, error)
+	UpdateRecord(ctx context.Context, userID string, record *models.HealthRecord) error
+	DeleteRecord(ctx context.Context, userID string, horseID, id uint) error
 }
 
 // PregnancyService defines the interface for pregnancy-related operations
 type PregnancyService interface {
-	GetPregnancy(ctx context.Context, horseID uint) (*models.Pregnancy, error)
-	StartTracking(ctx context.Context, horseID uint, start models.PregnancyStart) error
-	GetStatus(ctx context.Context, horseID uint) (*models.PregnancyStatus, error)
-	GetPregnancyEvents(ctx context.Context, horseID uint) ([]models.PregnancyEvent, error)
-	AddPregnancyEvent(ctx context.Context, event *models.PregnancyEvent) error
+	GetPregnancy(ctx context.Context, userID string, horseID uint) (*models.Pregnancy, error)
+	StartTracking(ctx context.Context, userID string, horseID uint, start models.PregnancyStart) error
+	GetStatus(ctx context.Context, userID string, horseID uint) (*models.PregnancyStatus, error)
+	GetPregnancyEvents(ctx context.Context, userID string, horseID uint) ([]models.PregnancyEvent, error)
+	AddPregnancyEvent(ctx context.Context, userID string, horseID uint, event *models.PregnancyEvent) error
 	GetGuidelines(ctx context.Context, stage models.PregnancyStage) ([]models.Guideline, error)
 	GetActive(ctx context.Context, userID string) ([]models.Pregnancy, error)
-	GetPregnancyStage(ctx context.Context, horseID uint) (models.PregnancyStage, error)
-	EndPregnancy(ctx context.Context, horseID uint, status string, date time.Time) error
-	GetPreFoalingSigns(ctx context.Context, horseID uint) ([]models.PreFoalingSign, error)
-	AddPreFoalingSign(ctx context.Context, sign *models.PreFoalingSign) error
-	UpdatePregnancy(ctx context.Context, pregnancy *models.Pregnancy) error
-	GetPreFoalingChecklist(ctx context.Context, horseID uint) ([]models.PreFoalingChecklistItem, error)
-	AddPreFoalingChecklistItem(ctx context.Context, item *models.PreFoalingChecklistItem) error
+	GetPregnancyStage(ctx context.Context, userID string, horseID uint) (models.PregnancyStage, error)
+	EndPregnancy(ctx context.Context, userID string, horseID uint, status string, date time.Time) (*models.Pregnancy, error)
+	GetPreFoalingSigns(ctx context.Context, userID string, horseID uint) ([]models.PreFoalingSign, error)
+	AddPreFoalingSign(ctx context.Context, userID string, sign *models.PreFoalingSign) error
+	UpdatePregnancy(ctx context.Context, userID string, pregnancy *models.Pregnancy) error
+	GetPreFoalingChecklist(ctx context.Context, userID string, horseID uint) ([]models.PreFoalingChecklistItem, error)
+	AddPreFoalingChecklistItem(ctx context.Context, userID string, item *models.PreFoalingChecklistItem) error
 }
 
 // UserService defines the interface for user-related operations
@@ -64,3 +73,151 @@ type BreedingService interface {
 	UpdateRecord(ctx context.Context, record *models.BreedingRecord) error
 	DeleteRecord(ctx context.Context, id uint) error
 }
+
+// OrganizationService defines the interface for farm membership operations
+type OrganizationService interface {
+	CreateOrganization(ctx context.Context, org *models.Organization) error
+	GetOrganization(ctx context.Context, userID string, orgID uint) (*models.Organization, error)
+	ListOrganizations(ctx context.Context, userID string) ([]models.Organization, error)
+	GetMembers(ctx context.Context, userID string, orgID uint) ([]models.OrganizationMember, error)
+	AddMember(ctx context.Context, userID string, member *models.OrganizationMember) error
+	UpdateMemberRole(ctx context.Context, userID string, orgID uint, memberUserID string, role models.MemberRole) (*models.OrganizationMember, error)
+	RemoveMember(ctx context.Context, userID string, orgID uint, memberUserID string) error
+}
+
+// VetAccessService defines the interface for sharing records with veterinarians
+type VetAccessService interface {
+	CreateGrant(ctx context.Context, grant *models.VetAccessGrant, horseIDs []uint) (string, error)
+	ListGrants(ctx context.Context, ownerID string) ([]models.VetAccessGrant, error)
+	ListReceivedGrants(ctx context.Context, vetUserID string) ([]models.VetAccessGrant, error)
+	RevokeGrant(ctx context.Context, ownerID string, grantID uint) error
+	Authorize(ctx context.Context, vetUserID string, horse *models.Horse, scope models.RecordScope, permission models.Permission) error
+	AuthorizeToken(ctx context.Context, token string, horseID uint, scope models.RecordScope, permission models.Permission) (*models.VetAccessGrant, error)
+}
+
+// MediaService defines the interface for horse photos and documents
+// VitalSignsService records vital signs checks and evaluates them against normal ranges
+type VitalSignsService interface {
+	Record(ctx context.Context, horse *models.Horse, record *models.VitalSignsRecord) error
+	List(ctx context.Context, horseID uint, since *time.Time) ([]models.VitalSignsRecord, error)
+}
+
+// FecalEggCountService tracks parasite egg counts and recommends targeted deworming
+type FecalEggCountService interface {
+	Record(ctx context.Context, horse *models.Horse, count *models.FecalEggCount) error
+	List(ctx context.Context, horseID uint) ([]models.FecalEggCount, error)
+	Recommend(ctx context.Context, horse *models.Horse) (*models.DewormingRecommendation, error)
+}
+
+// TreatmentService tracks courses of medication, their dose log and withdrawal periods
+type TreatmentService interface {
+	CreatePlan(ctx context.Context, horse *models.Horse, plan *models.TreatmentPlan) error
+	ListPlans(ctx context.Context, horse *models.Horse) ([]models.TreatmentPlanSummary, error)
+	GetPlan(ctx context.Context, horse *models.Horse, planID uint) (*models.TreatmentPlanSummary, error)
+	LogDose(ctx context.Context, horse *models.Horse, planID uint, dose *models.DoseAdministration) error
+	StopPlan(ctx context.Context, horse *models.Horse, planID uint, reason string) (*models.TreatmentPlan, error)
+	GetWithdrawal(ctx context.Context, horse *models.Horse) (*models.WithdrawalStatus, error)
+}
+
+// FarrierService records hoof care and schedules the next farrier visit
+type FarrierService interface {
+	RecordVisit(ctx context.Context, horse *models.Horse, visit *models.FarrierVisit) error
+	ListVisits(ctx context.Context, horseID uint) ([]models.FarrierVisit, error)
+	GetSchedule(ctx context.Context, horse *models.Horse) (*models.FarrierScheduleStatus, error)
+	SetSchedule(ctx context.Context, horse *models.Horse, schedule *models.FarrierSchedule) error
+	DeleteSchedule(ctx context.Context, horseID uint) error
+}
+
+// BodyConditionService records Henneke body condition scores and charts them
+type BodyConditionService interface {
+	Record(ctx context.Context, horse *models.Horse, record *models.BodyCondition) error
+	History(ctx context.Context, horse *models.Horse) (*models.BodyConditionHistory, error)
+	PregnancyTrend(ctx context.Context, horse *models.Horse) (*models.BodyConditionHistory, error)
+}
+
+// NutritionService recommends a daily ration from workload, pregnancy, lactation, season and body condition
+type NutritionService interface {
+	RecommendFeed(ctx context.Context, horse *models.Horse, activity health.ActivityLevel) (*models.FeedRecommendation, error)
+	RationMultipliers(horse models.Horse) models.FeedRequirements
+	Lactation(ctx context.Context, horse *models.Horse) (*models.Lactation, error)
+	EvaluateRation(ctx context.Context, horse *models.Horse, activity health.ActivityLevel, lactationMonth int, rations []models.FeedRation) (*models.RationEvaluation, error)
+}
+
+// WeightService keeps a horse's weight history from scales and tape measurements
+type WeightService interface {
+	Record(ctx context.Context, horse *models.Horse, record *models.WeightRecord) error
+	History(ctx context.Context, horse *models.Horse) (*models.WeightHistory, error)
+}
+
+// NeonatalService follows a newborn foal's milestones, APGAR scores, vital
+// signs and weight through its first 14 days
+type NeonatalService interface {
+	Update(ctx context.Context, horse *models.Horse, update *models.NeonatalRecord) (*models.NeonatalRecord, error)
+	RecordAssessment(ctx context.Context, horse *models.Horse, assessment *models.NeonatalAssessment) error
+	Summary(ctx context.Context, horse *models.Horse) (*models.NeonatalSummary, error)
+	CheckMilestones(ctx context.Context) (int, error)
+	ScheduleMilestoneChecks(interval time.Duration)
+}
+
+// FeedService keeps the feed catalogue, each user's feed store, horses'
+// rations and the feeding log, which draws the store down, and forecasts
+// when the store will run out
+type FeedService interface {
+	ListTypes(ctx context.Context) ([]models.FeedType, error)
+	CreateType(ctx context.Context, feedType *models.FeedType) error
+	SetNutrients(ctx context.Context, userID string, feedTypeID uint, nutrients *models.Nutrients) (*models.FeedType, error)
+	ListInventory(ctx context.Context, userID string) ([]models.FeedInventoryItem, error)
+	AddInventory(ctx context.Context, item *models.FeedInventoryItem) error
+	UpdateInventory(ctx context.Context, item *models.FeedInventoryItem) error
+	DeleteInventory(ctx context.Context, userID string, itemID uint) error
+	StockLevels(ctx context.Context, userID string) ([]models.FeedStockLevel, error)
+	GetRations(ctx context.Context, horse *models.Horse) ([]models.FeedRation, error)
+	SetRations(ctx context.Context, horse *models.Horse, rations []models.FeedRation) ([]models.FeedRation, error)
+	LogFeeding(ctx context.Context, horse *models.Horse, log *models.FeedLog) error
+	ListFeedings(ctx context.Context, horse *models.Horse, since *time.Time) ([]models.FeedLog, error)
+	Forecast(ctx context.Context, userID string, days int) (*models.FeedForecast, error)
+	CheckStock(ctx context.Context) (int, error)
+	ScheduleStockChecks(interval time.Duration)
+}
+
+// PastureService keeps pastures, turns horses out on them and plans the
+// grazing rotation
+type PastureService interface {
+	ListPastures(ctx context.Context, userID string) ([]models.Pasture, error)
+	CreatePasture(ctx context.Context, pasture *models.Pasture) error
+	UpdatePasture(ctx context.Context, pasture *models.Pasture) error
+	DeletePasture(ctx context.Context, userID string, pastureID uint) error
+	Rotation(ctx context.Context, userID string) ([]models.PastureRotation, error)
+	GetTurnout(ctx context.Context, horse *models.Horse) (*models.Turnout, error)
+	TurnOut(ctx context.Context, horse *models.Horse, turnout *models.Turnout) error
+	BringIn(ctx context.Context, horse *models.Horse) error
+}
+
+// WeaningService plans when to wean a foal and follows its weight and
+// stress through the month after
+type WeaningService interface {
+	Plan(ctx context.Context, horse *models.Horse) (*models.WeaningPlan, error)
+	Record(ctx context.Context, horse *models.Horse, record *models.WeaningRecord) error
+	RecordCheck(ctx context.Context, horse *models.Horse, check *models.WeaningCheck) error
+	Progress(ctx context.Context, horse *models.Horse) (*models.WeaningProgress, error)
+}
+
+// SymptomCheckerService triages observed symptoms against the condition catalogue
+type SymptomCheckerService interface {
+	Assess(ctx context.Context, horse *models.Horse, userID string, req models.SymptomAssessmentRequest) (*models.SymptomAssessment, error)
+}
+
+// HealthScheduleService tracks when vaccinations, deworming and dental checks are due
+type HealthScheduleService interface {
+	GetDueItems(ctx context.Context, horse *models.Horse) ([]models.HealthDueItem, error)
+	ListOverdue(ctx context.Context, userID string) ([]models.HealthDueItem, error)
+	SendReminders(ctx context.Context) (int, error)
+	ScheduleReminders(interval time.Duration)
+}
+
+type MediaService interface {
+	Upload(ctx context.Context, upload MediaUpload) (*models.HorseMedia, error)
+	List(ctx context.Context, horseID uint, filter models.MediaFilter) ([]models.HorseMedia, error)
+	Open(ctx context.Context, horseID, mediaID uint, thumbnail bool) (*models.HorseMedia, io.ReadCloser, error)
+	Delete(ctx context.Context, horseID, mediaID uint) error
+}
@@ -0,0 +1,297 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/polyfant/hulta_pregnancy_app/internal/logger"
+	"github.com/polyfant/hulta_pregnancy_app/internal/models"
+	"github.com/polyfant/hulta_pregnancy_app/internal/repository"
+	"github.com/polyfant/hulta_pregnancy_app/internal/service/storage"
+)
+
+const (
+	MaxImageSize    = 10 << 20 // 10 MB
+	MaxDocumentSize = 20 << 20 // 20 MB
+
+	// MediaURLExpiry is how long signed download links in listings stay valid
+	MediaURLExpiry = 15 * time.Minute
+)
+
+// mediaExtensions lists the accepted MIME types and the extension files are stored under
+var mediaExtensions = map[string]string{
+	"image/jpeg":      ".jpg",
+	"image/png":       ".png",
+	"image/gif":       ".gif",
+	"image/webp":      ".webp",
+	"application/pdf": ".pdf",
+}
+
+// thumbnailTypes are the image formats thumbnails can be generated for
+var thumbnailTypes = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+	"image/gif":  true,
+}
+
+// MediaStore persists media files for a horse
+type MediaStore interface {
+	SaveHorsePhoto(horseID int64, file io.Reader, filename string) (string, error)
+	OpenHorseFile(horseID int64, filename string) (io.ReadCloser, error)
+	DeleteHorsePhoto(horseID int64, filename string) error
+	HorseFileURL(horseID int64, filename string, expiry time.Duration) (string, error)
+}
+
+// MediaUpload describes a file uploaded for a horse
+type MediaUpload struct {
+	HorseID          uint
+	UserID           string
+	Type             models.MediaType
+	OriginalName     string
+	Description      string
+	HealthRecordID   *uint
+	PregnancyEventID *uint
+	Content          io.Reader
+}
+
+// MediaServiceImpl manages horse photos and documents
+type MediaServiceImpl struct {
+	repo             repository.MediaRepository
+	store            MediaStore
+	healthService    HealthService
+	pregnancyService PregnancyService
+}
+
+func NewMediaService(repo repository.MediaRepository, store MediaStore, healthService HealthService, pregnancyService PregnancyService) MediaService {
+	return &MediaServiceImpl{
+		repo:             repo,
+		store:            store,
+		healthService:    healthService,
+		pregnancyService: pregnancyService,
+	}
+}
+
+// Upload validates, stores and records a media file. The content type is
+// sniffed from the file itself rather than trusted from the client.
+func (s *MediaServiceImpl) Upload(ctx context.Context, upload MediaUpload) (*models.HorseMedia, error) {
+	if !upload.Type.IsValid() {
+		return nil, fmt.Errorf("%w: unknown media type %q", models.ErrUnsupportedMediaType, upload.Type)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(upload.Content, MaxDocumentSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read upload: %w", err)
+	}
+	if len(data) == 0 {
+		return nil, fmt.Errorf("%w: file is empty", models.ErrUnsupportedMediaType)
+	}
+
+	mimeType := http.DetectContentType(data)
+	ext, ok := mediaExtensions[mimeType]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", models.ErrUnsupportedMediaType, mimeType)
+	}
+	if err := checkMediaSize(upload.Type, mimeType, int64(len(data))); err != nil {
+		return nil, err
+	}
+
+	if err := s.validateAttachments(ctx, upload); err != nil {
+		return nil, err
+	}
+
+	horseID := int64(upload.HorseID)
+	fileName, err := s.store.SaveHorsePhoto(horseID, bytes.NewReader(data), "media"+ext)
+	if err != nil {
+		return nil, fmt.Errorf("failed to store media: %w", err)
+	}
+
+	media := &models.HorseMedia{
+		HorseID:          upload.HorseID,
+		UserID:           upload.UserID,
+		Type:             upload.Type,
+		FileName:         fileName,
+		OriginalName:     upload.OriginalName,
+		MimeType:         mimeType,
+		Size:             int64(len(data)),
+		Description:      upload.Description,
+		HealthRecordID:   upload.HealthRecordID,
+		PregnancyEventID: upload.PregnancyEventID,
+	}
+
+	// A missing thumbnail is not worth failing the upload for
+	if thumbnailTypes[mimeType] {
+		if thumb, err := storage.GenerateThumbnail(bytes.NewReader(data), storage.ThumbnailSize); err != nil {
+			logger.Warn("Skipping thumbnail", map[string]interface{}{"horseID": horseID, "error": err.Error()})
+		} else if thumbName, err := s.store.SaveHorsePhoto(horseID, bytes.NewReader(thumb), "thumb.jpg"); err != nil {
+			logger.Warn("Skipping thumbnail", map[string]interface{}{"horseID": horseID, "error": err.Error()})
+		} else {
+			media.ThumbnailName = thumbName
+		}
+	}
+
+	if err := s.repo.Create(ctx, media); err != nil {
+		s.removeFiles(ctx, media)
+		return nil, fmt.Errorf("failed to save media record: %w", err)
+	}
+
+	media.HasThumbnail = media.ThumbnailName != ""
+	return media, nil
+}
+
+func (s *MediaServiceImpl) List(ctx context.Context, horseID uint, filter models.MediaFilter) ([]models.HorseMedia, error) {
+	media, err := s.repo.ListByHorse(ctx, horseID, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list media: %w", err)
+	}
+	for i := range media {
+		media[i].HasThumbnail = media[i].ThumbnailName != ""
+		s.signURLs(&media[i])
+	}
+	return media, nil
+}
+
+// Open returns the media record and a reader for the file or its thumbnail
+func (s *MediaServiceImpl) Open(ctx context.Context, horseID, mediaID uint, thumbnail bool) (*models.HorseMedia, io.ReadCloser, error) {
+	media, err := s.get(ctx, horseID, mediaID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	fileName := media.FileName
+	if thumbnail {
+		if media.ThumbnailName == "" {
+			return nil, nil, models.ErrMediaNotFound
+		}
+		fileName = media.ThumbnailName
+		media.MimeType = "image/jpeg"
+	}
+
+	file, err := s.store.OpenHorseFile(int64(horseID), fileName)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open media: %w", err)
+	}
+	return media, file, nil
+}
+
+func (s *MediaServiceImpl) Delete(ctx context.Context, horseID, mediaID uint) error {
+	media, err := s.get(ctx, horseID, mediaID)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.Delete(ctx, mediaID); err != nil {
+		return fmt.Errorf("failed to delete media: %w", err)
+	}
+	s.removeFiles(ctx, media)
+	return nil
+}
+
+// get loads a media record and makes sure it belongs to the horse
+func (s *MediaServiceImpl) get(ctx context.Context, horseID, mediaID uint) (*models.HorseMedia, error) {
+	media, err := s.repo.GetByID(ctx, mediaID)
+	if err != nil || media.HorseID != horseID {
+		return nil, models.ErrMediaNotFound
+	}
+	return media, nil
+}
+
+// validateAttachments checks that linked health records and pregnancy events belong to the horse
+func (s *MediaServiceImpl) validateAttachments(ctx context.Context, upload MediaUpload) error {
+	if upload.HealthRecordID != nil {
+		records, err := s.healthService.GetRecords(ctx, upload.UserID, upload.HorseID)
+		if err != nil {
+			return fmt.Errorf("failed to get health records: %w", err)
+		}
+		found := false
+		for _, record := range records {
+			if record.ID == *upload.HealthRecordID {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("%w: health record %d", models.ErrInvalidAttachment, *upload.HealthRecordID)
+		}
+	}
+
+	if upload.PregnancyEventID != nil {
+		events, err := s.pregnancyService.GetPregnancyEvents(ctx, upload.UserID, upload.HorseID)
+		if err != nil {
+			return fmt.Errorf("failed to get pregnancy events: %w", err)
+		}
+		found := false
+		for _, event := range events {
+			if event.ID == *upload.PregnancyEventID {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("%w: pregnancy event %d", models.ErrInvalidAttachment, *upload.PregnancyEventID)
+		}
+	}
+	return nil
+}
+
+// removeFiles deletes the media's files unless another record of the same
+// horse still uses them; identical uploads share one content-addressed file
+func (s *MediaServiceImpl) removeFiles(ctx context.Context, media *models.HorseMedia) {
+	inUse := make(map[string]bool)
+	others, err := s.repo.ListByHorse(ctx, media.HorseID, models.MediaFilter{})
+	if err != nil {
+		logger.Warn("Keeping media files, could not check references", map[string]interface{}{"mediaID": media.ID, "error": err.Error()})
+		return
+	}
+	for _, other := range others {
+		if other.ID != media.ID {
+			inUse[other.FileName] = true
+			inUse[other.ThumbnailName] = true
+		}
+	}
+
+	for _, name := range []string{media.FileName, media.ThumbnailName} {
+		if name == "" || inUse[name] {
+			continue
+		}
+		if err := s.store.DeleteHorsePhoto(int64(media.HorseID), name); err != nil {
+			logger.Warn("Failed to remove media file", map[string]interface{}{"mediaID": media.ID, "error": err.Error()})
+		}
+	}
+}
+
+// signURLs fills in time-limited download links; a missing link only
+// means the client falls back to the download endpoint
+func (s *MediaServiceImpl) signURLs(media *models.HorseMedia) {
+	horseID := int64(media.HorseID)
+	if url, err := s.store.HorseFileURL(horseID, media.FileName, MediaURLExpiry); err == nil {
+		media.URL = url
+	} else {
+		logger.Warn("Failed to sign media URL", map[string]interface{}{"mediaID": media.ID, "error": err.Error()})
+	}
+	if media.ThumbnailName != "" {
+		if url, err := s.store.HorseFileURL(horseID, media.ThumbnailName, MediaURLExpiry); err == nil {
+			media.ThumbnailURL = url
+		}
+	}
+}
+
+// checkMediaSize applies the size limit for the file's kind. Photos and
+// ultrasound scans must be images; reports and passports may also be PDFs.
+func checkMediaSize(mediaType models.MediaType, mimeType string, size int64) error {
+	isPDF := mimeType == "application/pdf"
+	if isPDF && (mediaType == models.MediaTypePhoto || mediaType == models.MediaTypeUltrasound) {
+		return fmt.Errorf("%w: %s must be an image", models.ErrUnsupportedMediaType, mediaType)
+	}
+
+	limit := int64(MaxImageSize)
+	if isPDF {
+		limit = MaxDocumentSize
+	}
+	if size > limit {
+		return fmt.Errorf("%w: maximum is %d MB", models.ErrMediaTooLarge, limit>>20)
+	}
+	return nil
+}
@@ -0,0 +1,159 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"image/png"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/polyfant/hulta_pregnancy_app/internal/mocks"
+	"github.com/polyfant/hulta_pregnancy_app/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// memoryMediaStore keeps files in memory for tests
+type memoryMediaStore struct {
+	files map[string][]byte
+}
+
+func newMemoryMediaStore() *memoryMediaStore {
+	return &memoryMediaStore{files: make(map[string][]byte)}
+}
+
+func (s *memoryMediaStore) SaveHorsePhoto(horseID int64, file io.Reader, filename string) (string, error) {
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return "", err
+	}
+	name := fmt.Sprintf("%d_%s", len(s.files), filename)
+	s.files[name] = data
+	return name, nil
+}
+
+func (s *memoryMediaStore) OpenHorseFile(horseID int64, filename string) (io.ReadCloser, error) {
+	data, ok := s.files[filename]
+	if !ok {
+		return nil, fmt.Errorf("file not found")
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+func (s *memoryMediaStore) DeleteHorsePhoto(horseID int64, filename string) error {
+	delete(s.files, filename)
+	return nil
+}
+
+func (s *memoryMediaStore) HorseFileURL(horseID int64, filename string, expiry time.Duration) (string, error) {
+	return fmt.Sprintf("https://files.example/%d/%s", horseID, filename), nil
+}
+
+func testPNG(t *testing.T) []byte {
+	var buf bytes.Buffer
+	assert.NoError(t, png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 640, 480))))
+	return buf.Bytes()
+}
+
+func TestMediaService_Upload(t *testing.T) {
+	ctx := context.Background()
+	pdf := []byte("%PDF-1.4\n%test document\n")
+
+	tests := []struct {
+		name          string
+		mediaType     models.MediaType
+		content       []byte
+		wantMime      string
+		wantThumbnail bool
+		wantErr       error
+	}{
+		{"photo gets thumbnail", models.MediaTypePhoto, testPNG(t), "image/png", true, nil},
+		{"vet report as pdf", models.MediaTypeVetReport, pdf, "application/pdf", false, nil},
+		{"pdf rejected as photo", models.MediaTypePhoto, pdf, "", false, models.ErrUnsupportedMediaType},
+		{"executable rejected", models.MediaTypeOther, []byte("MZ\x90\x00binary"), "", false, models.ErrUnsupportedMediaType},
+		{"unknown type rejected", "SELFIE", testPNG(t), "", false, models.ErrUnsupportedMediaType},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(mocks.MockMediaRepository)
+			store := newMemoryMediaStore()
+			if tt.wantErr == nil {
+				repo.On("Create", ctx, mock.AnythingOfType("*models.HorseMedia")).Return(nil)
+			}
+
+			svc := NewMediaService(repo, store, nil, nil)
+			media, err := svc.Upload(ctx, MediaUpload{
+				HorseID:      1,
+				UserID:       "owner",
+				Type:         tt.mediaType,
+				OriginalName: "upload.bin",
+				Content:      bytes.NewReader(tt.content),
+			})
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Empty(t, store.files)
+				repo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantMime, media.MimeType)
+			assert.Equal(t, tt.wantThumbnail, media.HasThumbnail)
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestMediaService_UploadRemovesFilesOnRepositoryError(t *testing.T) {
+	ctx := context.Background()
+	repo := new(mocks.MockMediaRepository)
+	repo.On("Create", ctx, mock.Anything).Return(fmt.Errorf("db down"))
+	repo.On("ListByHorse", ctx, uint(1), models.MediaFilter{}).Return([]models.HorseMedia{}, nil)
+	store := newMemoryMediaStore()
+
+	svc := NewMediaService(repo, store, nil, nil)
+	_, err := svc.Upload(ctx, MediaUpload{HorseID: 1, Type: models.MediaTypePhoto, Content: bytes.NewReader(testPNG(t))})
+
+	assert.Error(t, err)
+	assert.Empty(t, store.files)
+}
+
+func TestMediaService_OpenOtherHorse(t *testing.T) {
+	ctx := context.Background()
+	repo := new(mocks.MockMediaRepository)
+	repo.On("GetByID", ctx, uint(5)).Return(&models.HorseMedia{ID: 5, HorseID: 2, FileName: "a.jpg"}, nil)
+
+	svc := NewMediaService(repo, newMemoryMediaStore(), nil, nil)
+	_, _, err := svc.Open(ctx, 1, 5, false)
+
+	assert.ErrorIs(t, err, models.ErrMediaNotFound)
+}
+
+func TestMediaService_DeleteKeepsSharedFiles(t *testing.T) {
+	ctx := context.Background()
+	store := newMemoryMediaStore()
+	store.files["shared.png"] = []byte("image")
+	store.files["own.pdf"] = []byte("report")
+
+	repo := new(mocks.MockMediaRepository)
+	repo.On("GetByID", ctx, uint(1)).Return(&models.HorseMedia{ID: 1, HorseID: 1, FileName: "shared.png"}, nil)
+	repo.On("GetByID", ctx, uint(3)).Return(&models.HorseMedia{ID: 3, HorseID: 1, FileName: "own.pdf"}, nil)
+	repo.On("Delete", ctx, mock.Anything).Return(nil)
+	repo.On("ListByHorse", ctx, uint(1), models.MediaFilter{}).Return([]models.HorseMedia{
+		{ID: 1, HorseID: 1, FileName: "shared.png"},
+		{ID: 2, HorseID: 1, FileName: "shared.png"},
+		{ID: 3, HorseID: 1, FileName: "own.pdf"},
+	}, nil)
+
+	svc := NewMediaService(repo, store, nil, nil)
+
+	assert.NoError(t, svc.Delete(ctx, 1, 1))
+	assert.Contains(t, store.files, "shared.png")
+
+	assert.NoError(t, svc.Delete(ctx, 1, 3))
+	assert.NotContains(t, store.files, "own.pdf")
+}
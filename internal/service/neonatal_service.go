@@ -0,0 +1,349 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/polyfant/hulta_pregnancy_app/internal/logger"
+	"github.com/polyfant/hulta_pregnancy_app/internal/models"
+	"github.com/polyfant/hulta_pregnancy_app/internal/repository"
+	"github.com/polyfant/hulta_pregnancy_app/internal/service/health"
+	"github.com/polyfant/hulta_pregnancy_app/internal/service/notification"
+)
+
+// maxIgG is the highest plausible foal IgG in mg/dL; anything above is a typing mistake
+const maxIgG = 5000
+
+// NeonatalServiceImpl follows a foal through its first 14 days and alerts
+// the owner when a milestone is missed
+type NeonatalServiceImpl struct {
+	repo      repository.NeonatalRepository
+	horseRepo repository.HorseRepository
+	vitals    repository.VitalSignsRepository
+	weights   repository.WeightRepository
+	reminders repository.HealthReminderRepository
+	notifier  Notifier
+}
+
+func NewNeonatalService(
+	repo repository.NeonatalRepository,
+	horseRepo repository.HorseRepository,
+	vitals repository.VitalSignsRepository,
+	weights repository.WeightRepository,
+	reminders repository.HealthReminderRepository,
+	notifier Notifier,
+) NeonatalService {
+	return &NeonatalServiceImpl{
+		repo:      repo,
+		horseRepo: horseRepo,
+		vitals:    vitals,
+		weights:   weights,
+		reminders: reminders,
+		notifier:  notifier,
+	}
+}
+
+// Update starts monitoring or records milestones on the foal's record.
+// Only the fields sent are changed; foaled_at defaults to the birth date.
+func (s *NeonatalServiceImpl) Update(ctx context.Context, horse *models.Horse, update *models.NeonatalRecord) (*models.NeonatalRecord, error) {
+	record, err := s.repo.GetByHorse(ctx, horse.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get neonatal record: %w", err)
+	}
+	if record == nil {
+		record = &models.NeonatalRecord{HorseID: horse.ID, UserID: update.UserID, FoaledAt: horse.BirthDate}
+	}
+
+	if !update.FoaledAt.IsZero() {
+		record.FoaledAt = update.FoaledAt
+	}
+	for _, field := range []struct {
+		from *time.Time
+		to   **time.Time
+	}{
+		{update.StoodAt, &record.StoodAt},
+		{update.NursedAt, &record.NursedAt},
+		{update.MeconiumAt, &record.MeconiumAt},
+		{update.UrinatedAt, &record.UrinatedAt},
+		{update.IgGTestedAt, &record.IgGTestedAt},
+	} {
+		if field.from != nil {
+			*field.to = field.from
+		}
+	}
+	if update.IgG != nil {
+		record.IgG = update.IgG
+	}
+	if update.Notes != "" {
+		record.Notes = update.Notes
+	}
+
+	now := timeNow()
+	if record.IgG != nil && record.IgGTestedAt == nil {
+		record.IgGTestedAt = &now
+	}
+	if err := validateNeonatalRecord(record, now); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Save(ctx, record); err != nil {
+		return nil, fmt.Errorf("failed to save neonatal record: %w", err)
+	}
+
+	if update.IgG != nil {
+		if advice := health.IgGAdvice(*update.IgG); advice != "" {
+			priority := notification.Medium
+			if health.IgGStatusOf(*update.IgG) == models.IgGFailure {
+				priority = notification.High
+			}
+			s.notify(ctx, horse, fmt.Sprintf("Low IgG for %s", horse.Name), advice, priority)
+		}
+	}
+	return record, nil
+}
+
+// RecordAssessment scores a modified APGAR assessment and alerts the owner
+// when the foal shows signs of asphyxia
+func (s *NeonatalServiceImpl) RecordAssessment(ctx context.Context, horse *models.Horse, assessment *models.NeonatalAssessment) error {
+	now := timeNow()
+	if assessment.AssessedAt.IsZero() {
+		assessment.AssessedAt = now
+	}
+	if assessment.AssessedAt.After(now) {
+		return fmt.Errorf("%w: assessed_at cannot be in the future", models.ErrInvalidNeonatal)
+	}
+	if err := checkNeonatalAge(horse.BirthDate, assessment.AssessedAt); err != nil {
+		return err
+	}
+
+	score, interpretation, err := health.ScoreApgar(*assessment)
+	if err != nil {
+		return fmt.Errorf("%w: %v", models.ErrInvalidNeonatal, err)
+	}
+	assessment.HorseID = horse.ID
+	assessment.Score = score
+	assessment.Interpretation = interpretation
+
+	if err := s.repo.CreateAssessment(ctx, assessment); err != nil {
+		return fmt.Errorf("failed to save neonatal assessment: %w", err)
+	}
+
+	switch interpretation {
+	case models.ApgarSevere:
+		s.notify(ctx, horse, fmt.Sprintf("Foal %s scored %d/8", horse.Name, score),
+			"Severe asphyxia. Call your vet now; clear the airway, stimulate the foal by rubbing and keep it warm.", notification.High)
+	case models.ApgarModerate:
+		s.notify(ctx, horse, fmt.Sprintf("Foal %s scored %d/8", horse.Name, score),
+			"Moderate asphyxia. Call your vet and rub the foal dry to stimulate breathing.", notification.High)
+	}
+	return nil
+}
+
+// Summary gathers the foal's milestones, assessments, vital signs and
+// weights from its first 14 days
+func (s *NeonatalServiceImpl) Summary(ctx context.Context, horse *models.Horse) (*models.NeonatalSummary, error) {
+	record, err := s.repo.GetByHorse(ctx, horse.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get neonatal record: %w", err)
+	}
+	if record == nil {
+		return nil, models.ErrNeonatalNotFound
+	}
+	assessments, err := s.repo.ListAssessments(ctx, horse.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get neonatal assessments: %w", err)
+	}
+	vitals, err := s.vitals.ListByHorse(ctx, horse.ID, &record.FoaledAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get vital signs: %w", err)
+	}
+	weights, err := s.weights.ListByHorse(ctx, horse.ID, &record.FoaledAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get weights: %w", err)
+	}
+
+	end := record.FoaledAt.Add(health.NeonatalPeriod)
+	now := timeNow()
+	summary := &models.NeonatalSummary{
+		HorseID:     horse.ID,
+		Record:      record,
+		AgeHours:    math.Round(now.Sub(record.FoaledAt).Hours()*10) / 10,
+		Milestones:  health.NeonatalMilestones(*record, now),
+		Assessments: assessments,
+		VitalSigns:  []models.VitalSignsRecord{},
+		Weights:     []models.WeightRecord{},
+		Alerts:      []string{},
+	}
+	for _, v := range vitals {
+		if v.RecordedAt.Before(end) {
+			summary.VitalSigns = append(summary.VitalSigns, v)
+		}
+	}
+	for _, w := range weights {
+		if w.Date.Before(end) {
+			summary.Weights = append(summary.Weights, w)
+		}
+	}
+
+	for _, check := range summary.Milestones {
+		if check.Advice != "" {
+			summary.Alerts = append(summary.Alerts, check.Advice)
+		}
+	}
+	if record.IgG != nil {
+		summary.IgGStatus = health.IgGStatusOf(*record.IgG)
+		if advice := health.IgGAdvice(*record.IgG); advice != "" {
+			summary.Alerts = append(summary.Alerts, advice)
+		}
+	}
+	if n := len(summary.VitalSigns); n > 0 && summary.VitalSigns[n-1].Abnormal {
+		summary.Alerts = append(summary.Alerts, "Latest vital signs are outside the newborn foal ranges")
+	}
+	if gain, ok := health.NeonatalDailyGain(summary.Weights); ok {
+		summary.DailyGainKg = &gain
+		if gain < health.MinNeonatalDailyGain {
+			summary.Alerts = append(summary.Alerts, fmt.Sprintf("Gaining %.2f kg a day; newborn foals usually gain 1-1.5 kg. Check the foal is nursing and the mare has milk.", gain))
+		}
+	}
+	return summary, nil
+}
+
+// CheckMilestones alerts owners of newborn foals that have missed a
+// milestone: once when it is late and again when it is time to call the
+// vet. An alert that cannot be sent is retried on the next check. It
+// returns the number of alerts sent.
+func (s *NeonatalServiceImpl) CheckMilestones(ctx context.Context) (int, error) {
+	now := timeNow()
+	records, err := s.repo.ListFoaledSince(ctx, now.Add(-health.NeonatalPeriod))
+	if err != nil {
+		return 0, fmt.Errorf("failed to get neonatal records: %w", err)
+	}
+
+	sent := 0
+	for _, record := range records {
+		var horse *models.Horse
+		for _, check := range health.NeonatalMilestones(record, now) {
+			if check.Status != models.NeonatalMilestoneLate && check.Status != models.NeonatalMilestoneUrgent {
+				continue
+			}
+			if horse == nil {
+				if horse, err = s.horseRepo.GetByID(ctx, record.HorseID); err != nil {
+					logger.Warn("Failed to get foal for neonatal alert", map[string]interface{}{"horseID": record.HorseID, "error": err.Error()})
+					break
+				}
+			}
+			reminder := &models.HealthReminder{
+				HorseID: record.HorseID,
+				Item:    fmt.Sprintf("Neonatal %s %s", check.Milestone, check.Status),
+				DueDate: record.FoaledAt,
+				SentAt:  now,
+			}
+			ok, err := s.reminders.MarkSent(ctx, reminder)
+			if err != nil {
+				logger.Warn("Failed to record neonatal alert", map[string]interface{}{"horseID": record.HorseID, "item": reminder.Item, "error": err.Error()})
+				continue
+			}
+			if !ok {
+				continue
+			}
+			if s.sendMilestoneAlert(ctx, horse, check) {
+				sent++
+				continue
+			}
+			if err := s.reminders.Unmark(ctx, reminder); err != nil {
+				logger.Warn("Failed to clear unsent neonatal alert", map[string]interface{}{"horseID": record.HorseID, "item": reminder.Item, "error": err.Error()})
+			}
+		}
+	}
+	return sent, nil
+}
+
+// ScheduleMilestoneChecks runs CheckMilestones in the background at the given interval
+func (s *NeonatalServiceImpl) ScheduleMilestoneChecks(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	go func() {
+		for range ticker.C {
+			sent, err := s.CheckMilestones(context.Background())
+			if err != nil {
+				logger.Error(err, "Neonatal milestone check failed")
+				continue
+			}
+			if sent > 0 {
+				logger.Info("Neonatal alerts sent", map[string]interface{}{"count": sent})
+			}
+		}
+	}()
+}
+
+func (s *NeonatalServiceImpl) sendMilestoneAlert(ctx context.Context, horse *models.Horse, check models.NeonatalMilestoneCheck) bool {
+	name := strings.ToLower(check.Name)
+	title := fmt.Sprintf("%s: %s overdue", horse.Name, name)
+	priority := notification.Medium
+	if check.Status == models.NeonatalMilestoneUrgent {
+		title = fmt.Sprintf("%s: %s overdue, call your vet", horse.Name, name)
+		priority = notification.High
+	}
+	return s.notify(ctx, horse, title, check.Advice, priority)
+}
+
+// notify sends a neonatal alert to the owner. Delivery failures are logged
+// rather than returned, as whatever triggered the alert is already saved.
+func (s *NeonatalServiceImpl) notify(ctx context.Context, horse *models.Horse, title, message string, priority notification.Priority) bool {
+	if s.notifier == nil {
+		return false
+	}
+	alert := &notification.Notification{
+		Type:     notification.NeonatalAlert,
+		UserID:   horse.UserID,
+		HorseID:  int64(horse.ID),
+		Title:    title,
+		Message:  message,
+		Priority: priority,
+	}
+	if err := s.notifier.SendNotification(ctx, alert); err != nil {
+		logger.Warn("Failed to send neonatal alert", map[string]interface{}{"horseID": horse.ID, "error": err.Error()})
+		return false
+	}
+	return true
+}
+
+// validateNeonatalRecord checks the foaling time and that every milestone
+// falls between foaling and now
+func validateNeonatalRecord(record *models.NeonatalRecord, now time.Time) error {
+	if record.FoaledAt.IsZero() {
+		return fmt.Errorf("%w: foaled_at is required when the horse has no birth date", models.ErrInvalidNeonatal)
+	}
+	if record.FoaledAt.After(now) {
+		return fmt.Errorf("%w: foaled_at cannot be in the future", models.ErrInvalidNeonatal)
+	}
+	if err := checkNeonatalAge(record.FoaledAt, now); err != nil {
+		return err
+	}
+	for _, milestone := range []models.NeonatalMilestone{models.NeonatalStand, models.NeonatalNurse, models.NeonatalMeconium, models.NeonatalUrinate, models.NeonatalIgGTest} {
+		at := record.ReachedAt(milestone)
+		if at == nil {
+			continue
+		}
+		if at.Before(record.FoaledAt) || at.After(now) {
+			return fmt.Errorf("%w: %s time must be between foaling and now", models.ErrInvalidNeonatal, strings.ToLower(string(milestone)))
+		}
+	}
+	if record.IgG != nil && (*record.IgG < 0 || *record.IgG > maxIgG) {
+		return fmt.Errorf("%w: IgG %.0f mg/dL is not plausible", models.ErrInvalidNeonatal, *record.IgG)
+	}
+	return nil
+}
+
+// checkNeonatalAge rejects records for foals past the neonatal period
+func checkNeonatalAge(birth, at time.Time) error {
+	if birth.IsZero() {
+		return fmt.Errorf("%w: the foal has no birth date", models.ErrInvalidNeonatal)
+	}
+	if at.Sub(birth) > health.NeonatalPeriod {
+		return fmt.Errorf("%w: neonatal monitoring covers the first %d days", models.ErrInvalidNeonatal, int(health.NeonatalPeriod.Hours()/24))
+	}
+	return nil
+}
@@ -0,0 +1,345 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/polyfant/hulta_pregnancy_app/internal/mocks"
+	"github.com/polyfant/hulta_pregnancy_app/internal/models"
+	"github.com/polyfant/hulta_pregnancy_app/internal/service/notification"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNeonatalService_Update(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 4, 10, 6, 0, 0, 0, time.UTC)
+	foaled := now.Add(-4 * time.Hour)
+	foal := &models.Horse{ID: 7, UserID: "owner", Name: "Comet", BirthDate: time.Date(2024, 4, 10, 0, 0, 0, 0, time.UTC)}
+	at := func(d time.Duration) *time.Time {
+		t := foaled.Add(d)
+		return &t
+	}
+	floatPtr := func(v float64) *float64 { return &v }
+
+	t.Run("starts monitoring", func(t *testing.T) {
+		setTestNow(t, now)
+		repo := new(mocks.MockNeonatalRepository)
+		notifier := new(mockNotifier)
+		svc := NewNeonatalService(repo, new(mocks.MockHorseRepository), new(mocks.MockVitalSignsRepository), new(mocks.MockWeightRepository), new(mocks.MockHealthReminderRepository), notifier)
+		repo.On("GetByHorse", ctx, uint(7)).Return(nil, nil)
+		repo.On("Save", ctx, mock.AnythingOfType("*models.NeonatalRecord")).Return(nil)
+
+		record, err := svc.Update(ctx, foal, &models.NeonatalRecord{UserID: "owner", FoaledAt: foaled, StoodAt: at(40 * time.Minute)})
+
+		require.NoError(t, err)
+		assert.Equal(t, uint(7), record.HorseID)
+		assert.Equal(t, foaled, record.FoaledAt)
+		assert.Equal(t, at(40*time.Minute), record.StoodAt)
+		notifier.AssertNotCalled(t, "SendNotification", mock.Anything, mock.Anything)
+	})
+
+	t.Run("keeps milestones already recorded", func(t *testing.T) {
+		setTestNow(t, now)
+		repo := new(mocks.MockNeonatalRepository)
+		svc := NewNeonatalService(repo, new(mocks.MockHorseRepository), new(mocks.MockVitalSignsRepository), new(mocks.MockWeightRepository), new(mocks.MockHealthReminderRepository), new(mockNotifier))
+		repo.On("GetByHorse", ctx, uint(7)).Return(&models.NeonatalRecord{ID: 3, HorseID: 7, FoaledAt: foaled, StoodAt: at(time.Hour)}, nil)
+		repo.On("Save", ctx, mock.AnythingOfType("*models.NeonatalRecord")).Return(nil)
+
+		record, err := svc.Update(ctx, foal, &models.NeonatalRecord{NursedAt: at(2 * time.Hour)})
+
+		require.NoError(t, err)
+		assert.Equal(t, uint(3), record.ID)
+		assert.Equal(t, at(time.Hour), record.StoodAt)
+		assert.Equal(t, at(2*time.Hour), record.NursedAt)
+	})
+
+	t.Run("low IgG alerts the owner", func(t *testing.T) {
+		setTestNow(t, now)
+		repo := new(mocks.MockNeonatalRepository)
+		notifier := new(mockNotifier)
+		svc := NewNeonatalService(repo, new(mocks.MockHorseRepository), new(mocks.MockVitalSignsRepository), new(mocks.MockWeightRepository), new(mocks.MockHealthReminderRepository), notifier)
+		repo.On("GetByHorse", ctx, uint(7)).Return(&models.NeonatalRecord{ID: 3, HorseID: 7, FoaledAt: foaled}, nil)
+		repo.On("Save", ctx, mock.AnythingOfType("*models.NeonatalRecord")).Return(nil)
+		notifier.On("SendNotification", ctx, mock.MatchedBy(func(n *notification.Notification) bool {
+			return n.Type == notification.NeonatalAlert && n.Priority == notification.High && n.UserID == "owner" && n.HorseID == 7
+		})).Return(nil).Once()
+
+		record, err := svc.Update(ctx, foal, &models.NeonatalRecord{IgG: floatPtr(350)})
+
+		require.NoError(t, err)
+		require.NotNil(t, record.IgGTestedAt, "tested now when no time is given")
+		assert.Equal(t, now, *record.IgGTestedAt)
+		notifier.AssertExpectations(t)
+	})
+
+	invalid := []struct {
+		name   string
+		horse  *models.Horse
+		update models.NeonatalRecord
+	}{
+		{"no birth date or foaling time", &models.Horse{ID: 7}, models.NeonatalRecord{}},
+		{"foaled in the future", foal, models.NeonatalRecord{FoaledAt: now.Add(time.Hour)}},
+		{"past the neonatal period", foal, models.NeonatalRecord{FoaledAt: now.AddDate(0, 0, -15)}},
+		{"milestone before foaling", foal, models.NeonatalRecord{FoaledAt: foaled, StoodAt: at(-time.Minute)}},
+		{"milestone in the future", foal, models.NeonatalRecord{FoaledAt: foaled, NursedAt: at(5 * time.Hour)}},
+		{"implausible IgG", foal, models.NeonatalRecord{FoaledAt: foaled, IgG: floatPtr(9000)}},
+	}
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			setTestNow(t, now)
+			repo := new(mocks.MockNeonatalRepository)
+			svc := NewNeonatalService(repo, new(mocks.MockHorseRepository), new(mocks.MockVitalSignsRepository), new(mocks.MockWeightRepository), new(mocks.MockHealthReminderRepository), new(mockNotifier))
+			repo.On("GetByHorse", ctx, uint(7)).Return(nil, nil)
+
+			update := tt.update
+			_, err := svc.Update(ctx, tt.horse, &update)
+
+			assert.ErrorIs(t, err, models.ErrInvalidNeonatal)
+			repo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestNeonatalService_RecordAssessment(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 4, 10, 6, 0, 0, 0, time.UTC)
+	foal := &models.Horse{ID: 7, UserID: "owner", Name: "Comet", BirthDate: time.Date(2024, 4, 10, 0, 0, 0, 0, time.UTC)}
+
+	tests := []struct {
+		name       string
+		assessment models.NeonatalAssessment
+		want       models.ApgarInterpretation
+		wantAlert  bool
+		wantErr    bool
+	}{
+		{name: "normal", assessment: models.NeonatalAssessment{HeartRate: 2, Respiration: 2, MuscleTone: 2, NasalResponse: 2}, want: models.ApgarNormal},
+		{name: "severe", assessment: models.NeonatalAssessment{HeartRate: 1, Respiration: 1}, want: models.ApgarSevere, wantAlert: true},
+		{name: "invalid score", assessment: models.NeonatalAssessment{HeartRate: 5}, wantErr: true},
+		{name: "in the future", assessment: models.NeonatalAssessment{AssessedAt: now.Add(time.Hour)}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setTestNow(t, now)
+			repo := new(mocks.MockNeonatalRepository)
+			notifier := new(mockNotifier)
+			svc := NewNeonatalService(repo, new(mocks.MockHorseRepository), new(mocks.MockVitalSignsRepository), new(mocks.MockWeightRepository), new(mocks.MockHealthReminderRepository), notifier)
+			repo.On("CreateAssessment", ctx, mock.AnythingOfType("*models.NeonatalAssessment")).Return(nil)
+			if tt.wantAlert {
+				notifier.On("SendNotification", ctx, mock.MatchedBy(func(n *notification.Notification) bool {
+					return n.Type == notification.NeonatalAlert && n.Priority == notification.High
+				})).Return(nil).Once()
+			}
+
+			assessment := tt.assessment
+			err := svc.RecordAssessment(ctx, foal, &assessment)
+
+			if tt.wantErr {
+				assert.ErrorIs(t, err, models.ErrInvalidNeonatal)
+				repo.AssertNotCalled(t, "CreateAssessment", mock.Anything, mock.Anything)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, assessment.Interpretation)
+			assert.Equal(t, uint(7), assessment.HorseID)
+			if tt.wantAlert {
+				notifier.AssertExpectations(t)
+			} else {
+				notifier.AssertNotCalled(t, "SendNotification", mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestNeonatalService_Summary(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 4, 13, 6, 0, 0, 0, time.UTC)
+	foaled := now.AddDate(0, 0, -3)
+	foal := &models.Horse{ID: 7, BirthDate: foaled}
+	igg := 600.0
+
+	t.Run("not started", func(t *testing.T) {
+		setTestNow(t, now)
+		repo := new(mocks.MockNeonatalRepository)
+		svc := NewNeonatalService(repo, new(mocks.MockHorseRepository), new(mocks.MockVitalSignsRepository), new(mocks.MockWeightRepository), new(mocks.MockHealthReminderRepository), new(mockNotifier))
+		repo.On("GetByHorse", ctx, uint(7)).Return(nil, nil)
+
+		_, err := svc.Summary(ctx, foal)
+
+		assert.ErrorIs(t, err, models.ErrNeonatalNotFound)
+	})
+
+	t.Run("collects the first days", func(t *testing.T) {
+		setTestNow(t, now)
+		repo := new(mocks.MockNeonatalRepository)
+		vitals := new(mocks.MockVitalSignsRepository)
+		weights := new(mocks.MockWeightRepository)
+		svc := NewNeonatalService(repo, new(mocks.MockHorseRepository), vitals, weights, new(mocks.MockHealthReminderRepository), new(mockNotifier))
+		stood, nursed := foaled.Add(30*time.Minute), foaled.Add(90*time.Minute)
+		repo.On("GetByHorse", ctx, uint(7)).Return(&models.NeonatalRecord{HorseID: 7, FoaledAt: foaled, StoodAt: &stood, NursedAt: &nursed, IgG: &igg}, nil)
+		repo.On("ListAssessments", ctx, uint(7)).Return([]models.NeonatalAssessment{{Score: 8, Interpretation: models.ApgarNormal}}, nil)
+		vitals.On("ListByHorse", ctx, uint(7), &foaled).Return([]models.VitalSignsRecord{
+			{RecordedAt: foaled.Add(time.Hour), Category: "Foal"},
+			{RecordedAt: foaled.AddDate(0, 0, 1), Category: "Foal", Abnormal: true},
+		}, nil)
+		weights.On("ListByHorse", ctx, uint(7), &foaled).Return([]models.WeightRecord{
+			{Date: foaled.Add(2 * time.Hour), WeightKg: 50},
+			{Date: foaled.Add(50 * time.Hour), WeightKg: 50.5},
+		}, nil)
+
+		summary, err := svc.Summary(ctx, foal)
+
+		require.NoError(t, err)
+		assert.Equal(t, 72.0, summary.AgeHours)
+		require.Len(t, summary.Milestones, 5)
+		assert.Equal(t, models.NeonatalMilestoneDone, summary.Milestones[0].Status)
+		assert.Equal(t, models.IgGPartial, summary.IgGStatus)
+		assert.Len(t, summary.VitalSigns, 2)
+		assert.Len(t, summary.Weights, 2)
+		require.NotNil(t, summary.DailyGainKg)
+		assert.Equal(t, 0.25, *summary.DailyGainKg)
+		// Meconium, urination and the IgG test are unrecorded and urgent;
+		// partial IgG, the abnormal vitals and the slow gain are flagged too
+		assert.Len(t, summary.Alerts, 6)
+	})
+}
+
+func TestNeonatalService_CheckMilestones(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 4, 10, 6, 0, 0, 0, time.UTC)
+	foal := &models.Horse{ID: 7, UserID: "owner", Name: "Comet"}
+
+	t.Run("alerts on a late milestone", func(t *testing.T) {
+		setTestNow(t, now)
+		repo := new(mocks.MockNeonatalRepository)
+		horseRepo := new(mocks.MockHorseRepository)
+		reminders := new(mocks.MockHealthReminderRepository)
+		notifier := new(mockNotifier)
+		svc := NewNeonatalService(repo, horseRepo, new(mocks.MockVitalSignsRepository), new(mocks.MockWeightRepository), reminders, notifier)
+		foaled := now.Add(-75 * time.Minute)
+		repo.On("ListFoaledSince", ctx, now.Add(-14*24*time.Hour)).Return([]models.NeonatalRecord{{HorseID: 7, FoaledAt: foaled}}, nil)
+		reminders.On("MarkSent", ctx, mock.MatchedBy(func(r *models.HealthReminder) bool {
+			return r.HorseID == 7 && r.Item == "Neonatal STAND LATE"
+		})).Return(true, nil).Once()
+		horseRepo.On("GetByID", ctx, uint(7)).Return(foal, nil)
+		notifier.On("SendNotification", ctx, mock.MatchedBy(func(n *notification.Notification) bool {
+			return n.Type == notification.NeonatalAlert && n.Priority == notification.Medium && n.UserID == "owner"
+		})).Return(nil).Once()
+
+		sent, err := svc.CheckMilestones(ctx)
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, sent)
+		reminders.AssertExpectations(t)
+		notifier.AssertExpectations(t)
+	})
+
+	t.Run("escalates when it is time to call the vet", func(t *testing.T) {
+		setTestNow(t, now)
+		repo := new(mocks.MockNeonatalRepository)
+		horseRepo := new(mocks.MockHorseRepository)
+		reminders := new(mocks.MockHealthReminderRepository)
+		notifier := new(mockNotifier)
+		svc := NewNeonatalService(repo, horseRepo, new(mocks.MockVitalSignsRepository), new(mocks.MockWeightRepository), reminders, notifier)
+		stood := now.Add(-3 * time.Hour)
+		repo.On("ListFoaledSince", ctx, mock.Anything).Return([]models.NeonatalRecord{{HorseID: 7, FoaledAt: now.Add(-200 * time.Minute), StoodAt: &stood}}, nil)
+		reminders.On("MarkSent", ctx, mock.MatchedBy(func(r *models.HealthReminder) bool {
+			return r.Item == "Neonatal NURSE URGENT"
+		})).Return(true, nil).Once()
+		horseRepo.On("GetByID", ctx, uint(7)).Return(foal, nil)
+		notifier.On("SendNotification", ctx, mock.MatchedBy(func(n *notification.Notification) bool {
+			return n.Priority == notification.High
+		})).Return(nil).Once()
+
+		sent, err := svc.CheckMilestones(ctx)
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, sent)
+		notifier.AssertExpectations(t)
+	})
+
+	t.Run("already alerted", func(t *testing.T) {
+		setTestNow(t, now)
+		repo := new(mocks.MockNeonatalRepository)
+		horseRepo := new(mocks.MockHorseRepository)
+		reminders := new(mocks.MockHealthReminderRepository)
+		notifier := new(mockNotifier)
+		svc := NewNeonatalService(repo, horseRepo, new(mocks.MockVitalSignsRepository), new(mocks.MockWeightRepository), reminders, notifier)
+		repo.On("ListFoaledSince", ctx, mock.Anything).Return([]models.NeonatalRecord{{HorseID: 7, FoaledAt: now.Add(-75 * time.Minute)}}, nil)
+		reminders.On("MarkSent", ctx, mock.Anything).Return(false, nil)
+		horseRepo.On("GetByID", ctx, uint(7)).Return(foal, nil)
+
+		sent, err := svc.CheckMilestones(ctx)
+
+		require.NoError(t, err)
+		assert.Zero(t, sent)
+		notifier.AssertNotCalled(t, "SendNotification", mock.Anything, mock.Anything)
+	})
+
+	t.Run("failed alert is retried on the next check", func(t *testing.T) {
+		setTestNow(t, now)
+		repo := new(mocks.MockNeonatalRepository)
+		horseRepo := new(mocks.MockHorseRepository)
+		reminders := new(mocks.MockHealthReminderRepository)
+		notifier := new(mockNotifier)
+		svc := NewNeonatalService(repo, horseRepo, new(mocks.MockVitalSignsRepository), new(mocks.MockWeightRepository), reminders, notifier)
+		repo.On("ListFoaledSince", ctx, mock.Anything).Return([]models.NeonatalRecord{{HorseID: 7, FoaledAt: now.Add(-75 * time.Minute)}}, nil)
+		horseRepo.On("GetByID", ctx, uint(7)).Return(foal, nil)
+		reminders.On("MarkSent", ctx, mock.Anything).Return(true, nil)
+		notifier.On("SendNotification", ctx, mock.Anything).Return(errors.New("smtp down"))
+		reminders.On("Unmark", ctx, mock.MatchedBy(func(r *models.HealthReminder) bool {
+			return r.HorseID == 7 && r.Item == "Neonatal STAND LATE"
+		})).Return(nil).Once()
+
+		sent, err := svc.CheckMilestones(ctx)
+
+		require.NoError(t, err)
+		assert.Zero(t, sent)
+		reminders.AssertExpectations(t)
+	})
+
+	t.Run("unknown foal is not marked as alerted", func(t *testing.T) {
+		setTestNow(t, now)
+		repo := new(mocks.MockNeonatalRepository)
+		horseRepo := new(mocks.MockHorseRepository)
+		reminders := new(mocks.MockHealthReminderRepository)
+		svc := NewNeonatalService(repo, horseRepo, new(mocks.MockVitalSignsRepository), new(mocks.MockWeightRepository), reminders, new(mockNotifier))
+		repo.On("ListFoaledSince", ctx, mock.Anything).Return([]models.NeonatalRecord{{HorseID: 7, FoaledAt: now.Add(-75 * time.Minute)}}, nil)
+		horseRepo.On("GetByID", ctx, uint(7)).Return(nil, errors.New("record not found"))
+
+		sent, err := svc.CheckMilestones(ctx)
+
+		require.NoError(t, err)
+		assert.Zero(t, sent)
+		reminders.AssertNotCalled(t, "MarkSent", mock.Anything, mock.Anything)
+	})
+
+	t.Run("a recording error does not stop the sweep", func(t *testing.T) {
+		setTestNow(t, now)
+		repo := new(mocks.MockNeonatalRepository)
+		horseRepo := new(mocks.MockHorseRepository)
+		reminders := new(mocks.MockHealthReminderRepository)
+		notifier := new(mockNotifier)
+		svc := NewNeonatalService(repo, horseRepo, new(mocks.MockVitalSignsRepository), new(mocks.MockWeightRepository), reminders, notifier)
+		other := &models.Horse{ID: 8, UserID: "owner", Name: "Nova"}
+		repo.On("ListFoaledSince", ctx, mock.Anything).Return([]models.NeonatalRecord{
+			{HorseID: 7, FoaledAt: now.Add(-75 * time.Minute)},
+			{HorseID: 8, FoaledAt: now.Add(-75 * time.Minute)},
+		}, nil)
+		horseRepo.On("GetByID", ctx, uint(7)).Return(foal, nil)
+		horseRepo.On("GetByID", ctx, uint(8)).Return(other, nil)
+		reminders.On("MarkSent", ctx, mock.MatchedBy(func(r *models.HealthReminder) bool { return r.HorseID == 7 })).Return(false, errors.New("connection reset"))
+		reminders.On("MarkSent", ctx, mock.MatchedBy(func(r *models.HealthReminder) bool { return r.HorseID == 8 })).Return(true, nil)
+		notifier.On("SendNotification", ctx, mock.MatchedBy(func(n *notification.Notification) bool { return n.HorseID == 8 })).Return(nil).Once()
+
+		sent, err := svc.CheckMilestones(ctx)
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, sent)
+		notifier.AssertExpectations(t)
+	})
+}
@@ -18,6 +18,10 @@ const (
 	PregnancyMilestone   NotificationType = "PREGNANCY_MILESTONE"
 	VaccinationDue       NotificationType = "VACCINATION_DUE"
 	WeatherAlert         NotificationType = "WEATHER_ALERT"
+	VitalSignsAlert      NotificationType = "VITAL_SIGNS_ALERT"
+	GrowthAlert          NotificationType = "GROWTH_ALERT"
+	NeonatalAlert        NotificationType = "NEONATAL_ALERT"
+	FeedAlert            NotificationType = "FEED_ALERT"
 )
 
 // Priority represents the importance level of a notification
@@ -0,0 +1,48 @@
+package notification
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+// GormRepository stores notifications in the notifications table
+type GormRepository struct {
+	db *gorm.DB
+}
+
+func NewGormRepository(db *gorm.DB) *GormRepository {
+	return &GormRepository{db: db}
+}
+
+func (r *GormRepository) SaveNotification(ctx context.Context, notification *Notification) error {
+	return r.db.WithContext(ctx).Create(notification).Error
+}
+
+func (r *GormRepository) GetNotifications(ctx context.Context, userID string, limit int) ([]*Notification, error) {
+	var notifications []*Notification
+	query := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("created_at DESC")
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	if err := query.Find(&notifications).Error; err != nil {
+		return nil, err
+	}
+	return notifications, nil
+}
+
+func (r *GormRepository) GetNotificationByID(ctx context.Context, id uint) (*Notification, error) {
+	var notification Notification
+	if err := r.db.WithContext(ctx).First(&notification, id).Error; err != nil {
+		return nil, err
+	}
+	return &notification, nil
+}
+
+func (r *GormRepository) MarkAsRead(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Model(&Notification{}).Where("id = ?", id).Update("read", true).Error
+}
+
+func (r *GormRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Delete(&Notification{}, id).Error
+}
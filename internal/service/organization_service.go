@@ -0,0 +1,141 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/polyfant/hulta_pregnancy_app/internal/models"
+	"github.com/polyfant/hulta_pregnancy_app/internal/repository"
+)
+
+// OrganizationServiceImpl handles farm membership and role management
+type OrganizationServiceImpl struct {
+	repo repository.OrganizationRepository
+}
+
+func NewOrganizationService(repo repository.OrganizationRepository) OrganizationService {
+	return &OrganizationServiceImpl{repo: repo}
+}
+
+func (s *OrganizationServiceImpl) CreateOrganization(ctx context.Context, org *models.Organization) error {
+	if org.Name == "" {
+		return fmt.Errorf("organization name is required")
+	}
+	if org.OwnerID == "" {
+		return fmt.Errorf("organization owner is required")
+	}
+	if err := s.repo.Create(ctx, org); err != nil {
+		return fmt.Errorf("failed to create organization: %w", err)
+	}
+	return nil
+}
+
+func (s *OrganizationServiceImpl) GetOrganization(ctx context.Context, userID string, orgID uint) (*models.Organization, error) {
+	if _, err := s.authorize(ctx, userID, orgID, models.PermissionView); err != nil {
+		return nil, err
+	}
+	org, err := s.repo.GetByID(ctx, orgID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get organization: %w", err)
+	}
+	return org, nil
+}
+
+func (s *OrganizationServiceImpl) ListOrganizations(ctx context.Context, userID string) ([]models.Organization, error) {
+	orgs, err := s.repo.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list organizations: %w", err)
+	}
+	return orgs, nil
+}
+
+func (s *OrganizationServiceImpl) GetMembers(ctx context.Context, userID string, orgID uint) ([]models.OrganizationMember, error) {
+	if _, err := s.authorize(ctx, userID, orgID, models.PermissionView); err != nil {
+		return nil, err
+	}
+	members, err := s.repo.GetMembers(ctx, orgID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get organization members: %w", err)
+	}
+	return members, nil
+}
+
+func (s *OrganizationServiceImpl) AddMember(ctx context.Context, userID string, member *models.OrganizationMember) error {
+	actor, err := s.authorize(ctx, userID, member.OrganizationID, models.PermissionManageMembers)
+	if err != nil {
+		return err
+	}
+	if err := s.validateRoleAssignment(actor, member.Role); err != nil {
+		return err
+	}
+	if err := s.repo.AddMember(ctx, member); err != nil {
+		return fmt.Errorf("failed to add organization member: %w", err)
+	}
+	return nil
+}
+
+func (s *OrganizationServiceImpl) UpdateMemberRole(ctx context.Context, userID string, orgID uint, memberUserID string, role models.MemberRole) (*models.OrganizationMember, error) {
+	actor, err := s.authorize(ctx, userID, orgID, models.PermissionManageMembers)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.validateRoleAssignment(actor, role); err != nil {
+		return nil, err
+	}
+
+	member, err := s.repo.GetMember(ctx, orgID, memberUserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get organization member: %w", err)
+	}
+	if member.Role == models.MemberRoleOwner && actor.Role != models.MemberRoleOwner {
+		return nil, models.ErrAccessDenied
+	}
+
+	member.Role = role
+	if err := s.repo.UpdateMember(ctx, member); err != nil {
+		return nil, fmt.Errorf("failed to update organization member: %w", err)
+	}
+	return member, nil
+}
+
+func (s *OrganizationServiceImpl) RemoveMember(ctx context.Context, userID string, orgID uint, memberUserID string) error {
+	if _, err := s.authorize(ctx, userID, orgID, models.PermissionManageMembers); err != nil {
+		return err
+	}
+
+	org, err := s.repo.GetByID(ctx, orgID)
+	if err != nil {
+		return fmt.Errorf("failed to get organization: %w", err)
+	}
+	if org.OwnerID == memberUserID {
+		return fmt.Errorf("the organization owner cannot be removed")
+	}
+
+	if err := s.repo.RemoveMember(ctx, orgID, memberUserID); err != nil {
+		return fmt.Errorf("failed to remove organization member: %w", err)
+	}
+	return nil
+}
+
+// authorize returns the caller's membership if their role grants the permission
+func (s *OrganizationServiceImpl) authorize(ctx context.Context, userID string, orgID uint, permission models.Permission) (*models.OrganizationMember, error) {
+	member, err := s.repo.GetMember(ctx, orgID, userID)
+	if err != nil {
+		return nil, models.ErrAccessDenied
+	}
+	if !member.Role.Can(permission) {
+		return nil, models.ErrAccessDenied
+	}
+	return member, nil
+}
+
+// validateRoleAssignment prevents farm managers from handing out ownership
+func (s *OrganizationServiceImpl) validateRoleAssignment(actor *models.OrganizationMember, role models.MemberRole) error {
+	if !role.IsValid() {
+		return models.ErrInvalidRole
+	}
+	if role == models.MemberRoleOwner && actor.Role != models.MemberRoleOwner {
+		return models.ErrAccessDenied
+	}
+	return nil
+}
@@ -0,0 +1,306 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/polyfant/hulta_pregnancy_app/internal/models"
+	"github.com/polyfant/hulta_pregnancy_app/internal/repository"
+)
+
+const (
+	// defaultGrazingDays and defaultRestDays are the rotation of a pasture
+	// created without one: two weeks of grazing, four weeks for the grass
+	// to regrow and the worm larvae on it to die off
+	defaultGrazingDays = 14
+	defaultRestDays    = 28
+	// minHectaresPerHorse is the least grazing a horse should have to itself
+	minHectaresPerHorse = 0.4
+	// rotationHistoryDays is how far back the rotation looks for a pasture's
+	// last grazing
+	rotationHistoryDays = 365
+)
+
+// PastureServiceImpl keeps each user's pastures and plans their grazing
+// rotation. Horses are turned out on their owner's pastures.
+type PastureServiceImpl struct {
+	repo repository.PastureRepository
+}
+
+func NewPastureService(repo repository.PastureRepository) PastureService {
+	return &PastureServiceImpl{
+		repo: repo,
+	}
+}
+
+func (s *PastureServiceImpl) ListPastures(ctx context.Context, userID string) ([]models.Pasture, error) {
+	pastures, err := s.repo.ListPastures(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pastures: %w", err)
+	}
+	return pastures, nil
+}
+
+func (s *PastureServiceImpl) CreatePasture(ctx context.Context, pasture *models.Pasture) error {
+	if err := validatePasture(pasture); err != nil {
+		return err
+	}
+	pasture.ID = 0
+	if err := s.repo.SavePasture(ctx, pasture); err != nil {
+		return fmt.Errorf("failed to save pasture: %w", err)
+	}
+	return nil
+}
+
+// UpdatePasture replaces a pasture, for instance when its grass has been
+// re-graded
+func (s *PastureServiceImpl) UpdatePasture(ctx context.Context, pasture *models.Pasture) error {
+	existing, err := s.ownedPasture(ctx, pasture.UserID, pasture.ID)
+	if err != nil {
+		return err
+	}
+	if err := validatePasture(pasture); err != nil {
+		return err
+	}
+	pasture.CreatedAt = existing.CreatedAt
+	if err := s.repo.SavePasture(ctx, pasture); err != nil {
+		return fmt.Errorf("failed to save pasture: %w", err)
+	}
+	return nil
+}
+
+func (s *PastureServiceImpl) DeletePasture(ctx context.Context, userID string, pastureID uint) error {
+	if _, err := s.ownedPasture(ctx, userID, pastureID); err != nil {
+		return err
+	}
+	if err := s.repo.DeletePasture(ctx, pastureID); err != nil {
+		return fmt.Errorf("failed to delete pasture: %w", err)
+	}
+	return nil
+}
+
+// ownedPasture loads a pasture and makes sure it is the user's
+func (s *PastureServiceImpl) ownedPasture(ctx context.Context, userID string, pastureID uint) (*models.Pasture, error) {
+	pasture, err := s.repo.GetPasture(ctx, pastureID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pasture: %w", err)
+	}
+	if pasture == nil || pasture.UserID != userID {
+		return nil, models.ErrPastureNotFound
+	}
+	return pasture, nil
+}
+
+func validatePasture(pasture *models.Pasture) error {
+	pasture.Name = strings.TrimSpace(pasture.Name)
+	pasture.GrassCondition = models.GrassCondition(strings.ToUpper(strings.TrimSpace(string(pasture.GrassCondition))))
+	if pasture.GrazingDays == 0 {
+		pasture.GrazingDays = defaultGrazingDays
+	}
+	if pasture.RestDays == 0 {
+		pasture.RestDays = defaultRestDays
+	}
+	switch {
+	case pasture.Name == "":
+		return fmt.Errorf("%w: name is required", models.ErrInvalidPasture)
+	case pasture.AreaHectares <= 0:
+		return fmt.Errorf("%w: area_hectares must be positive", models.ErrInvalidPasture)
+	case !pasture.GrassCondition.IsValid():
+		return fmt.Errorf("%w: unknown grass condition %q", models.ErrInvalidPasture, pasture.GrassCondition)
+	case pasture.GrazingDays < 0 || pasture.RestDays < 0:
+		return fmt.Errorf("%w: grazing_days and rest_days cannot be negative", models.ErrInvalidPasture)
+	}
+	return nil
+}
+
+// GetTurnout returns the pasture the horse is out on, or nil when it is
+// kept in
+func (s *PastureServiceImpl) GetTurnout(ctx context.Context, horse *models.Horse) (*models.Turnout, error) {
+	turnout, err := s.repo.GetTurnout(ctx, horse.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get turnout: %w", err)
+	}
+	return turnout, nil
+}
+
+// TurnOut puts the horse on one of its owner's pastures, moving it off the
+// pasture it was on
+func (s *PastureServiceImpl) TurnOut(ctx context.Context, horse *models.Horse, turnout *models.Turnout) error {
+	now := timeNow()
+	if turnout.StartDate.IsZero() {
+		turnout.StartDate = now
+	}
+	switch {
+	case turnout.HoursPerDay <= 0 || turnout.HoursPerDay > 24:
+		return fmt.Errorf("%w: hours_per_day must be between 0 and 24", models.ErrInvalidPasture)
+	case turnout.StartDate.After(now):
+		return fmt.Errorf("%w: start_date cannot be in the future", models.ErrInvalidPasture)
+	}
+	pasture, err := s.ownedPasture(ctx, horse.UserID, turnout.PastureID)
+	if err != nil {
+		return err
+	}
+	current, err := s.repo.GetTurnout(ctx, horse.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get turnout: %w", err)
+	}
+	if current != nil && turnout.StartDate.Before(current.StartDate) {
+		return fmt.Errorf("%w: start_date is before the current turnout began", models.ErrInvalidPasture)
+	}
+
+	turnout.ID = 0
+	turnout.HorseID = horse.ID
+	turnout.EndDate = nil
+	if err := s.repo.StartTurnout(ctx, turnout); err != nil {
+		return fmt.Errorf("failed to save turnout: %w", err)
+	}
+	turnout.Pasture = pasture
+	return nil
+}
+
+// BringIn ends the horse's turnout
+func (s *PastureServiceImpl) BringIn(ctx context.Context, horse *models.Horse) error {
+	current, err := s.repo.GetTurnout(ctx, horse.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get turnout: %w", err)
+	}
+	if current == nil {
+		return models.ErrNotTurnedOut
+	}
+	if err := s.repo.EndTurnout(ctx, current.ID, timeNow()); err != nil {
+		return fmt.Errorf("failed to end turnout: %w", err)
+	}
+	return nil
+}
+
+// Rotation shows where each of the user's pastures is in its rotation and
+// which rested pasture each grazed one should move its horses to
+func (s *PastureServiceImpl) Rotation(ctx context.Context, userID string) ([]models.PastureRotation, error) {
+	pastures, err := s.repo.ListPastures(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pastures: %w", err)
+	}
+	ids := make([]uint, 0, len(pastures))
+	for _, pasture := range pastures {
+		ids = append(ids, pasture.ID)
+	}
+	now := timeNow()
+	turnouts, err := s.repo.ListTurnouts(ctx, ids, now.AddDate(0, 0, -rotationHistoryDays))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get turnouts: %w", err)
+	}
+	return pastureRotation(pastures, turnouts, now), nil
+}
+
+func pastureRotation(pastures []models.Pasture, turnouts []models.Turnout, now time.Time) []models.PastureRotation {
+	byPasture := map[uint][]models.Turnout{}
+	for _, turnout := range turnouts {
+		byPasture[turnout.PastureID] = append(byPasture[turnout.PastureID], turnout)
+	}
+
+	rotations := make([]models.PastureRotation, 0, len(pastures))
+	for _, pasture := range pastures {
+		rotation := models.PastureRotation{
+			Pasture:  pasture,
+			Status:   models.PastureStatusReady,
+			HorseIDs: []uint{},
+			Warnings: []string{},
+		}
+		for _, turnout := range byPasture[pasture.ID] {
+			if turnout.Open(now) {
+				rotation.HorseIDs = append(rotation.HorseIDs, turnout.HorseID)
+			}
+		}
+
+		start, end, grazed := grazingSpell(byPasture[pasture.ID], now)
+		switch {
+		case len(rotation.HorseIDs) > 0:
+			moveBy := start.AddDate(0, 0, pasture.GrazingDays)
+			rotation.Status = models.PastureStatusGrazing
+			rotation.GrazedSince = &start
+			rotation.MoveBy = &moveBy
+			rotation.HectaresPerHorse = round2(pasture.AreaHectares / float64(len(rotation.HorseIDs)))
+			if !now.Before(moveBy) {
+				rotation.Warnings = append(rotation.Warnings, fmt.Sprintf("Grazed for %d days, longer than its %d-day grazing period: move the horses and rest it", int(now.Sub(start).Hours()/24), pasture.GrazingDays))
+			}
+			if rotation.HectaresPerHorse < minHectaresPerHorse {
+				rotation.Warnings = append(rotation.Warnings, fmt.Sprintf("%.2f ha per horse is less than %.1f ha: the grass will be grazed down fast", rotation.HectaresPerHorse, minHectaresPerHorse))
+			}
+		case grazed:
+			ready := end.AddDate(0, 0, pasture.RestDays)
+			rotation.RestingSince = &end
+			rotation.ReadyOn = &ready
+			if now.Before(ready) {
+				rotation.Status = models.PastureStatusResting
+			}
+		}
+		if pasture.GrassCondition == models.GrassConditionPoor {
+			rotation.Warnings = append(rotation.Warnings, "Grass in poor condition: rest it longer, or reseed and fertilise it before grazing")
+		}
+		rotations = append(rotations, rotation)
+	}
+
+	planPastureMoves(rotations)
+	return rotations
+}
+
+// grazingSpell finds the pasture's latest spell of grazing, counting
+// turnouts that overlap or follow on from each other as one. A spell that
+// is still going ends now.
+func grazingSpell(turnouts []models.Turnout, now time.Time) (start, end time.Time, ok bool) {
+	sorted := append([]models.Turnout(nil), turnouts...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].StartDate.Before(sorted[j].StartDate) })
+	for _, turnout := range sorted {
+		if turnout.StartDate.After(now) {
+			continue
+		}
+		until := now
+		if turnout.EndDate != nil && turnout.EndDate.Before(now) {
+			until = *turnout.EndDate
+		}
+		switch {
+		case !ok || turnout.StartDate.After(end):
+			start, end, ok = turnout.StartDate, until, true
+		case until.After(end):
+			end = until
+		}
+	}
+	return start, end, ok
+}
+
+// planPastureMoves gives each grazed pasture, the one due to be left first
+// first, the pasture that has been ready longest or will be ready soonest
+func planPastureMoves(rotations []models.PastureRotation) {
+	var grazed, rested []*models.PastureRotation
+	for i := range rotations {
+		rotation := &rotations[i]
+		if rotation.Status == models.PastureStatusGrazing {
+			grazed = append(grazed, rotation)
+		} else {
+			rested = append(rested, rotation)
+		}
+	}
+	sort.SliceStable(grazed, func(i, j int) bool { return grazed[i].MoveBy.Before(*grazed[j].MoveBy) })
+	sort.SliceStable(rested, func(i, j int) bool {
+		a, b := rested[i].ReadyOn, rested[j].ReadyOn
+		switch {
+		case a == nil:
+			return b != nil
+		case b == nil:
+			return false
+		}
+		return a.Before(*b)
+	})
+
+	for i, rotation := range grazed {
+		if i >= len(rested) {
+			rotation.Warnings = append(rotation.Warnings, "No free pasture to move the horses to")
+			continue
+		}
+		next := rested[i].Pasture.ID
+		rotation.NextPastureID = &next
+	}
+}
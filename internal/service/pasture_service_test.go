@@ -0,0 +1,183 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/polyfant/hulta_pregnancy_app/internal/mocks"
+	"github.com/polyfant/hulta_pregnancy_app/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPastureService_CreatePasture(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		pasture models.Pasture
+		wantErr bool
+	}{
+		{name: "valid", pasture: models.Pasture{Name: "Top field", AreaHectares: 2, GrassCondition: "good"}},
+		{name: "no name", pasture: models.Pasture{Name: " ", AreaHectares: 2, GrassCondition: "GOOD"}, wantErr: true},
+		{name: "no area", pasture: models.Pasture{Name: "Top field", GrassCondition: "GOOD"}, wantErr: true},
+		{name: "unknown grass condition", pasture: models.Pasture{Name: "Top field", AreaHectares: 2, GrassCondition: "GREEN"}, wantErr: true},
+		{name: "negative rest", pasture: models.Pasture{Name: "Top field", AreaHectares: 2, GrassCondition: "GOOD", RestDays: -1}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(mocks.MockPastureRepository)
+			svc := NewPastureService(repo)
+			repo.On("SavePasture", ctx, mock.AnythingOfType("*models.Pasture")).Return(nil)
+
+			pasture := tt.pasture
+			err := svc.CreatePasture(ctx, &pasture)
+
+			if tt.wantErr {
+				assert.ErrorIs(t, err, models.ErrInvalidPasture)
+				repo.AssertNotCalled(t, "SavePasture", mock.Anything, mock.Anything)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, models.GrassConditionGood, pasture.GrassCondition)
+			assert.Equal(t, defaultGrazingDays, pasture.GrazingDays)
+			assert.Equal(t, defaultRestDays, pasture.RestDays)
+		})
+	}
+}
+
+func TestPastureService_TurnOut(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, time.May, 20, 12, 0, 0, 0, time.UTC)
+	horse := &models.Horse{ID: 1, UserID: "owner"}
+	pasture := &models.Pasture{ID: 3, UserID: "owner", Name: "Top field", AreaHectares: 2, GrassCondition: models.GrassConditionGood}
+	current := &models.Turnout{ID: 7, HorseID: 1, PastureID: 4, HoursPerDay: 8, StartDate: now.AddDate(0, 0, -10)}
+
+	tests := []struct {
+		name    string
+		turnout models.Turnout
+		current *models.Turnout
+		wantErr error
+	}{
+		{name: "first turnout starts now", turnout: models.Turnout{PastureID: 3, HoursPerDay: 8}},
+		{name: "moves off the current pasture", turnout: models.Turnout{PastureID: 3, HoursPerDay: 6}, current: current},
+		{name: "no hours", turnout: models.Turnout{PastureID: 3}, wantErr: models.ErrInvalidPasture},
+		{name: "more than a day", turnout: models.Turnout{PastureID: 3, HoursPerDay: 25}, wantErr: models.ErrInvalidPasture},
+		{name: "starts in the future", turnout: models.Turnout{PastureID: 3, HoursPerDay: 8, StartDate: now.AddDate(0, 0, 1)}, wantErr: models.ErrInvalidPasture},
+		{name: "someone else's pasture", turnout: models.Turnout{PastureID: 5, HoursPerDay: 8}, wantErr: models.ErrPastureNotFound},
+		{name: "starts before the current turnout", turnout: models.Turnout{PastureID: 3, HoursPerDay: 8, StartDate: now.AddDate(0, 0, -20)}, current: current, wantErr: models.ErrInvalidPasture},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setTestNow(t, now)
+			repo := new(mocks.MockPastureRepository)
+			svc := NewPastureService(repo)
+			repo.On("GetPasture", ctx, uint(3)).Return(pasture, nil)
+			repo.On("GetPasture", ctx, uint(5)).Return(&models.Pasture{ID: 5, UserID: "neighbour"}, nil)
+			repo.On("GetTurnout", ctx, horse.ID).Return(tt.current, nil)
+			repo.On("StartTurnout", ctx, mock.AnythingOfType("*models.Turnout")).Return(nil)
+
+			turnout := tt.turnout
+			err := svc.TurnOut(ctx, horse, &turnout)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				repo.AssertNotCalled(t, "StartTurnout", mock.Anything, mock.Anything)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, horse.ID, turnout.HorseID)
+			assert.Equal(t, pasture, turnout.Pasture)
+			assert.False(t, turnout.StartDate.IsZero())
+		})
+	}
+}
+
+func TestPastureService_BringIn(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, time.May, 20, 12, 0, 0, 0, time.UTC)
+	horse := &models.Horse{ID: 1, UserID: "owner"}
+
+	t.Run("ends the open turnout", func(t *testing.T) {
+		setTestNow(t, now)
+		repo := new(mocks.MockPastureRepository)
+		svc := NewPastureService(repo)
+		repo.On("GetTurnout", ctx, horse.ID).Return(&models.Turnout{ID: 7, HorseID: 1}, nil)
+		repo.On("EndTurnout", ctx, uint(7), now).Return(nil)
+
+		require.NoError(t, svc.BringIn(ctx, horse))
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("horse is not out", func(t *testing.T) {
+		setTestNow(t, now)
+		repo := new(mocks.MockPastureRepository)
+		svc := NewPastureService(repo)
+		repo.On("GetTurnout", ctx, horse.ID).Return(nil, nil)
+
+		assert.ErrorIs(t, svc.BringIn(ctx, horse), models.ErrNotTurnedOut)
+	})
+}
+
+func TestPastureService_Rotation(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, time.May, 20, 12, 0, 0, 0, time.UTC)
+	day := func(month time.Month, d int) time.Time { return time.Date(2024, month, d, 8, 0, 0, 0, time.UTC) }
+	ended := func(month time.Month, d int) *time.Time { end := day(month, d); return &end }
+
+	pastures := []models.Pasture{
+		{ID: 1, UserID: "owner", Name: "North", AreaHectares: 2, GrassCondition: models.GrassConditionGood, GrazingDays: 14, RestDays: 28},
+		{ID: 2, UserID: "owner", Name: "South", AreaHectares: 1, GrassCondition: models.GrassConditionFair, GrazingDays: 14, RestDays: 28},
+		{ID: 3, UserID: "owner", Name: "East", AreaHectares: 0.5, GrassCondition: models.GrassConditionPoor, GrazingDays: 14, RestDays: 28},
+		{ID: 4, UserID: "owner", Name: "West", AreaHectares: 0.6, GrassCondition: models.GrassConditionGood, GrazingDays: 14, RestDays: 28},
+	}
+	turnouts := []models.Turnout{
+		{HorseID: 2, PastureID: 1, HoursPerDay: 12, StartDate: day(time.April, 25), EndDate: ended(time.May, 5)},
+		{HorseID: 1, PastureID: 1, HoursPerDay: 12, StartDate: day(time.May, 1)},
+		{HorseID: 3, PastureID: 1, HoursPerDay: 12, StartDate: day(time.May, 3)},
+		{HorseID: 4, PastureID: 2, HoursPerDay: 12, StartDate: day(time.March, 1), EndDate: ended(time.April, 1)},
+		{HorseID: 5, PastureID: 3, HoursPerDay: 12, StartDate: day(time.April, 20), EndDate: ended(time.May, 10)},
+		{HorseID: 6, PastureID: 4, HoursPerDay: 8, StartDate: day(time.May, 15)},
+		{HorseID: 7, PastureID: 4, HoursPerDay: 8, StartDate: day(time.May, 15)},
+	}
+
+	setTestNow(t, now)
+	repo := new(mocks.MockPastureRepository)
+	svc := NewPastureService(repo)
+	repo.On("ListPastures", ctx, "owner").Return(pastures, nil)
+	repo.On("ListTurnouts", ctx, []uint{1, 2, 3, 4}, now.AddDate(0, 0, -rotationHistoryDays)).Return(turnouts, nil)
+
+	got, err := svc.Rotation(ctx, "owner")
+	require.NoError(t, err)
+	require.Len(t, got, 4)
+
+	north, south, east, west := got[0], got[1], got[2], got[3]
+
+	assert.Equal(t, models.PastureStatusGrazing, north.Status)
+	assert.Equal(t, []uint{1, 3}, north.HorseIDs)
+	assert.Equal(t, 1.0, north.HectaresPerHorse)
+	assert.Equal(t, day(time.April, 25), *north.GrazedSince)
+	assert.Equal(t, day(time.May, 9), *north.MoveBy)
+	require.NotNil(t, north.NextPastureID)
+	assert.Equal(t, uint(2), *north.NextPastureID)
+	assert.Len(t, north.Warnings, 1)
+
+	assert.Equal(t, models.PastureStatusReady, south.Status)
+	assert.Equal(t, day(time.April, 1), *south.RestingSince)
+	assert.Equal(t, day(time.April, 29), *south.ReadyOn)
+	assert.Empty(t, south.Warnings)
+
+	assert.Equal(t, models.PastureStatusResting, east.Status)
+	assert.Equal(t, day(time.June, 7), *east.ReadyOn)
+	assert.Len(t, east.Warnings, 1)
+
+	assert.Equal(t, models.PastureStatusGrazing, west.Status)
+	assert.Equal(t, 0.3, west.HectaresPerHorse)
+	require.NotNil(t, west.NextPastureID)
+	assert.Equal(t, uint(3), *west.NextPastureID)
+	assert.Len(t, west.Warnings, 1)
+}
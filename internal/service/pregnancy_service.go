@@ -2,32 +2,45 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/polyfant/hulta_pregnancy_app/internal/models"
 	"github.com/polyfant/hulta_pregnancy_app/internal/repository"
 	"github.com/polyfant/hulta_pregnancy_app/internal/service/pregnancy"
+	"gorm.io/gorm"
 )
 
 // PregnancyServiceImpl handles pregnancy-related business logic
 type PregnancyServiceImpl struct {
 	horseRepo     repository.HorseRepository
 	pregnancyRepo repository.PregnancyRepository
+	horses        HorseService
 	calculator    *pregnancy.Calculator
 }
 
 // NewPregnancyService creates a new pregnancy service instance
-func NewPregnancyService(horseRepo repository.HorseRepository, pregnancyRepo repository.PregnancyRepository) PregnancyService {
+func NewPregnancyService(horseRepo repository.HorseRepository, pregnancyRepo repository.PregnancyRepository, horses HorseService) PregnancyService {
 	return &PregnancyServiceImpl{
 		horseRepo:     horseRepo,
 		pregnancyRepo: pregnancyRepo,
+		horses:        horses,
 		calculator:    pregnancy.NewCalculator(),
 	}
 }
 
+// authorize checks that the user may view or log the horse's pregnancy records
+func (s *PregnancyServiceImpl) authorize(ctx context.Context, userID string, horseID uint, permission models.Permission) error {
+	_, err := s.horses.GetForRecords(ctx, userID, horseID, models.RecordScopePregnancy, permission)
+	return err
+}
+
 // GetPregnancy retrieves pregnancy information for a horse
-func (s *PregnancyServiceImpl) GetPregnancy(ctx context.Context, horseID uint) (*models.Pregnancy, error) {
+func (s *PregnancyServiceImpl) GetPregnancy(ctx context.Context, userID string, horseID uint) (*models.Pregnancy, error) {
+	if err := s.authorize(ctx, userID, horseID, models.PermissionView); err != nil {
+		return nil, err
+	}
 	return s.pregnancyRepo.GetByHorseID(ctx, horseID)
 }
 
@@ -41,7 +54,12 @@ func (s *PregnancyServiceImpl) GetPregnancies(ctx context.Context, userID string
 }
 
 // StartTracking begins tracking a new pregnancy
-func (s *PregnancyServiceImpl) StartTracking(ctx context.Context, horseID uint, start models.PregnancyStart) error {
+func (s *PregnancyServiceImpl) StartTracking(ctx context.Context, userID string, horseID uint, start models.PregnancyStart) error {
+	horse, err := s.horses.GetByID(ctx, userID, horseID, models.PermissionEdit)
+	if err != nil {
+		return err
+	}
+
 	pregnancy := &models.Pregnancy{
 		HorseID:        horseID,
 		StartDate:      start.ConceptionDate,
@@ -53,11 +71,6 @@ func (s *PregnancyServiceImpl) StartTracking(ctx context.Context, horseID uint,
 		return fmt.Errorf("failed to create pregnancy: %w", err)
 	}
 
-	horse, err := s.horseRepo.GetByID(ctx, horseID)
-	if err != nil {
-		return fmt.Errorf("failed to get horse: %w", err)
-	}
-
 	horse.IsPregnant = true
 	if err := s.horseRepo.Update(ctx, horse); err != nil {
 		return fmt.Errorf("failed to update horse: %w", err)
@@ -67,13 +80,17 @@ func (s *PregnancyServiceImpl) StartTracking(ctx context.Context, horseID uint,
 }
 
 // GetStatus retrieves the current pregnancy status
-func (s *PregnancyServiceImpl) GetStatus(ctx context.Context, horseID uint) (*models.PregnancyStatus, error) {
+func (s *PregnancyServiceImpl) GetStatus(ctx context.Context, userID string, horseID uint) (*models.PregnancyStatus, error) {
+	if err := s.authorize(ctx, userID, horseID, models.PermissionView); err != nil {
+		return nil, err
+	}
+
 	pregnancy, err := s.pregnancyRepo.GetByHorseID(ctx, horseID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get pregnancy: %w", err)
 	}
 
-	stage, err := s.GetPregnancyStage(ctx, horseID)
+	stage, err := s.pregnancyStage(ctx, horseID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get pregnancy stage: %w", err)
 	}
@@ -85,7 +102,10 @@ func (s *PregnancyServiceImpl) GetStatus(ctx context.Context, horseID uint) (*mo
 }
 
 // GetPregnancyEvents retrieves all events for a pregnancy
-func (s *PregnancyServiceImpl) GetPregnancyEvents(ctx context.Context, horseID uint) ([]models.PregnancyEvent, error) {
+func (s *PregnancyServiceImpl) GetPregnancyEvents(ctx context.Context, userID string, horseID uint) ([]models.PregnancyEvent, error) {
+	if err := s.authorize(ctx, userID, horseID, models.PermissionView); err != nil {
+		return nil, err
+	}
 	return s.pregnancyRepo.GetEvents(ctx, horseID)
 }
 
@@ -125,6 +145,29 @@ func (s *PregnancyServiceImpl) GetGuidelines(ctx context.Context, stage models.P
 				},
 			},
 		},
+		models.PregnancyStageEarlyLactation: {
+			{
+				Stage:       models.PregnancyStageEarlyLactation,
+				Description: "Early lactation care",
+				Tips: []string{
+					"Expect foal heat 7-12 days after foaling",
+					"Feed for peak milk production in the second and third months",
+					"Provide plenty of fresh water",
+					"Check the udder daily for heat or swelling",
+				},
+			},
+		},
+		models.PregnancyStageLateLactation: {
+			{
+				Stage:       models.PregnancyStageLateLactation,
+				Description: "Late lactation care",
+				Tips: []string{
+					"Introduce the foal to creep feed",
+					"Reduce the mare's concentrates before weaning",
+					"Monitor the mare's body condition",
+				},
+			},
+		},
 	}
 
 	if g, ok := guidelines[stage]; ok {
@@ -137,22 +180,47 @@ func (s *PregnancyServiceImpl) GetActive(ctx context.Context, userID string) ([]
 	return s.pregnancyRepo.GetActive(ctx, userID)
 }
 
-func (s *PregnancyServiceImpl) UpdatePregnancy(ctx context.Context, pregnancy *models.Pregnancy) error {
+// UpdatePregnancy saves changes to a pregnancy, checking access against the
+// horse it is stored for rather than the one in the request
+func (s *PregnancyServiceImpl) UpdatePregnancy(ctx context.Context, userID string, pregnancy *models.Pregnancy) error {
+	stored, err := s.pregnancyRepo.GetPregnancy(ctx, pregnancy.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get pregnancy: %w", err)
+	}
+	if _, err := s.horses.GetByID(ctx, userID, stored.HorseID, models.PermissionEdit); err != nil {
+		return err
+	}
+	pregnancy.HorseID = stored.HorseID
+
 	if err := s.pregnancyRepo.Update(ctx, pregnancy); err != nil {
 		return fmt.Errorf("failed to update pregnancy: %w", err)
 	}
 	return nil
 }
 
-func (s *PregnancyServiceImpl) GetPreFoalingSigns(ctx context.Context, horseID uint) ([]models.PreFoalingSign, error) {
+func (s *PregnancyServiceImpl) GetPreFoalingSigns(ctx context.Context, userID string, horseID uint) ([]models.PreFoalingSign, error) {
+	if err := s.authorize(ctx, userID, horseID, models.PermissionView); err != nil {
+		return nil, err
+	}
 	return s.pregnancyRepo.GetPreFoaling(ctx, horseID)
 }
 
-func (s *PregnancyServiceImpl) AddPreFoalingSign(ctx context.Context, sign *models.PreFoalingSign) error {
+func (s *PregnancyServiceImpl) AddPreFoalingSign(ctx context.Context, userID string, sign *models.PreFoalingSign) error {
+	if err := s.authorize(ctx, userID, sign.HorseID, models.PermissionLog); err != nil {
+		return err
+	}
 	return s.pregnancyRepo.AddPreFoaling(ctx, sign)
 }
 
-func (s *PregnancyServiceImpl) GetPregnancyStage(ctx context.Context, horseID uint) (models.PregnancyStage, error) {
+func (s *PregnancyServiceImpl) GetPregnancyStage(ctx context.Context, userID string, horseID uint) (models.PregnancyStage, error) {
+	if err := s.authorize(ctx, userID, horseID, models.PermissionView); err != nil {
+		return "", err
+	}
+	return s.pregnancyStage(ctx, horseID)
+}
+
+// pregnancyStage is the stage of the horse's pregnancy by days since conception
+func (s *PregnancyServiceImpl) pregnancyStage(ctx context.Context, horseID uint) (models.PregnancyStage, error) {
 	pregnancy, err := s.pregnancyRepo.GetByHorseID(ctx, horseID)
 	if err != nil {
 		return "", fmt.Errorf("failed to get pregnancy: %w", err)
@@ -176,23 +244,71 @@ func (s *PregnancyServiceImpl) GetPregnancyStage(ctx context.Context, horseID ui
 	}
 }
 
-func (s *PregnancyServiceImpl) EndPregnancy(ctx context.Context, horseID uint, status string, date time.Time) error {
-	pregnancy, err := s.pregnancyRepo.GetByHorseID(ctx, horseID)
+// EndPregnancy closes the mare's active pregnancy with its outcome: COMPLETE
+// when she foaled, which starts her lactation, or LOST or ABORTED
+func (s *PregnancyServiceImpl) EndPregnancy(ctx context.Context, userID string, horseID uint, status string, date time.Time) (*models.Pregnancy, error) {
+	horse, err := s.horses.GetByID(ctx, userID, horseID, models.PermissionEdit)
 	if err != nil {
-		return fmt.Errorf("failed to get pregnancy: %w", err)
+		return nil, err
+	}
+
+	switch status {
+	case models.PregnancyStatusComplete, models.PregnancyStatusLost, models.PregnancyStatusAborted:
+	default:
+		return nil, fmt.Errorf("%w: unknown status %q", models.ErrInvalidPregnancyEnd, status)
+	}
+
+	pregnancy, err := s.pregnancyRepo.GetCurrentPregnancy(ctx, horseID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, models.ErrNoActivePregnancy
+		}
+		return nil, fmt.Errorf("failed to get current pregnancy: %w", err)
+	}
+	if pregnancy == nil || !pregnancy.IsActive() {
+		return nil, models.ErrNoActivePregnancy
+	}
+
+	conception := pregnancy.StartDate
+	if pregnancy.ConceptionDate != nil {
+		conception = *pregnancy.ConceptionDate
+	}
+	switch {
+	case date.IsZero():
+		return nil, fmt.Errorf("%w: date is required", models.ErrInvalidPregnancyEnd)
+	case date.After(timeNow()):
+		return nil, fmt.Errorf("%w: date cannot be in the future", models.ErrInvalidPregnancyEnd)
+	case date.Before(conception):
+		return nil, fmt.Errorf("%w: date is before conception", models.ErrInvalidPregnancyEnd)
 	}
 
 	pregnancy.Status = status
 	pregnancy.EndDate = &date
-
 	if err := s.pregnancyRepo.Update(ctx, pregnancy); err != nil {
-		return fmt.Errorf("failed to update pregnancy: %w", err)
+		return nil, fmt.Errorf("failed to update pregnancy: %w", err)
 	}
 
-	return nil
+	horse.IsPregnant = false
+	horse.ConceptionDate = nil
+	if err := s.horseRepo.Update(ctx, horse); err != nil {
+		return nil, fmt.Errorf("failed to update horse: %w", err)
+	}
+
+	return pregnancy, nil
 }
 
-func (s *PregnancyServiceImpl) AddPregnancyEvent(ctx context.Context, event *models.PregnancyEvent) error {
+// AddPregnancyEvent adds an event to the horse's pregnancy. It returns
+// gorm.ErrRecordNotFound when no pregnancy is recorded for the horse.
+func (s *PregnancyServiceImpl) AddPregnancyEvent(ctx context.Context, userID string, horseID uint, event *models.PregnancyEvent) error {
+	if err := s.authorize(ctx, userID, horseID, models.PermissionLog); err != nil {
+		return err
+	}
+	pregnancy, err := s.pregnancyRepo.GetByHorseID(ctx, horseID)
+	if err != nil {
+		return fmt.Errorf("failed to get pregnancy: %w", err)
+	}
+	event.PregnancyID = pregnancy.ID
+
 	if err := s.pregnancyRepo.AddPregnancyEvent(ctx, event); err != nil {
 		return fmt.Errorf("failed to add pregnancy event: %w", err)
 	}
@@ -207,7 +323,10 @@ func (s *PregnancyServiceImpl) GetEvents(ctx context.Context, horseID uint) ([]m
 	return events, nil
 }
 
-func (s *PregnancyServiceImpl) GetPreFoalingChecklist(ctx context.Context, horseID uint) ([]models.PreFoalingChecklistItem, error) {
+func (s *PregnancyServiceImpl) GetPreFoalingChecklist(ctx context.Context, userID string, horseID uint) ([]models.PreFoalingChecklistItem, error) {
+	if err := s.authorize(ctx, userID, horseID, models.PermissionView); err != nil {
+		return nil, err
+	}
 	items, err := s.pregnancyRepo.GetPreFoalingChecklist(ctx, horseID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get pre-foaling checklist: %w", err)
@@ -215,7 +334,10 @@ func (s *PregnancyServiceImpl) GetPreFoalingChecklist(ctx context.Context, horse
 	return items, nil
 }
 
-func (s *PregnancyServiceImpl) AddPreFoalingChecklistItem(ctx context.Context, item *models.PreFoalingChecklistItem) error {
+func (s *PregnancyServiceImpl) AddPreFoalingChecklistItem(ctx context.Context, userID string, item *models.PreFoalingChecklistItem) error {
+	if err := s.authorize(ctx, userID, item.HorseID, models.PermissionLog); err != nil {
+		return err
+	}
 	if err := s.pregnancyRepo.AddPreFoalingChecklistItem(ctx, item); err != nil {
 		return fmt.Errorf("failed to add pre-foaling checklist item: %w", err)
 	}
@@ -0,0 +1,91 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/polyfant/hulta_pregnancy_app/internal/mocks"
+	"github.com/polyfant/hulta_pregnancy_app/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestPregnancyService_EndPregnancy(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 4, 10, 6, 0, 0, 0, time.UTC)
+	conception := now.AddDate(0, 0, -340)
+	foaled := now.Add(-6 * time.Hour)
+	active := func() *models.Pregnancy {
+		return &models.Pregnancy{ID: 5, HorseID: 1, StartDate: conception, ConceptionDate: &conception, Status: models.PregnancyStatusActive}
+	}
+
+	t.Run("foaling completes the pregnancy", func(t *testing.T) {
+		setTestNow(t, now)
+		horseRepo := new(mocks.MockHorseRepository)
+		pregnancyRepo := new(mocks.MockPregnancyRepository)
+		svc := NewPregnancyService(horseRepo, pregnancyRepo, NewHorseService(horseRepo, new(mocks.MockOrganizationRepository), nil))
+		pregnancyRepo.On("GetCurrentPregnancy", ctx, uint(1)).Return(active(), nil)
+		pregnancyRepo.On("Update", ctx, mock.AnythingOfType("*models.Pregnancy")).Return(nil)
+		horseRepo.On("GetByID", ctx, uint(1)).Return(&models.Horse{ID: 1, UserID: "owner", IsPregnant: true, ConceptionDate: &conception}, nil)
+		horseRepo.On("Update", ctx, mock.MatchedBy(func(h *models.Horse) bool {
+			return !h.IsPregnant && h.ConceptionDate == nil
+		})).Return(nil)
+
+		pregnancy, err := svc.EndPregnancy(ctx, "owner", 1, models.PregnancyStatusComplete, foaled)
+
+		require.NoError(t, err)
+		assert.Equal(t, models.PregnancyStatusComplete, pregnancy.Status)
+		require.NotNil(t, pregnancy.EndDate)
+		assert.Equal(t, foaled, *pregnancy.EndDate)
+		horseRepo.AssertExpectations(t)
+	})
+
+	t.Run("no active pregnancy", func(t *testing.T) {
+		horseRepo := new(mocks.MockHorseRepository)
+		pregnancyRepo := new(mocks.MockPregnancyRepository)
+		svc := NewPregnancyService(horseRepo, pregnancyRepo, NewHorseService(horseRepo, new(mocks.MockOrganizationRepository), nil))
+		horseRepo.On("GetByID", ctx, uint(1)).Return(&models.Horse{ID: 1, UserID: "owner"}, nil)
+		pregnancyRepo.On("GetCurrentPregnancy", ctx, uint(1)).Return(nil, gorm.ErrRecordNotFound)
+
+		_, err := svc.EndPregnancy(ctx, "owner", 1, models.PregnancyStatusComplete, foaled)
+		assert.ErrorIs(t, err, models.ErrNoActivePregnancy)
+	})
+
+	t.Run("stranger cannot end the pregnancy", func(t *testing.T) {
+		horseRepo := new(mocks.MockHorseRepository)
+		pregnancyRepo := new(mocks.MockPregnancyRepository)
+		svc := NewPregnancyService(horseRepo, pregnancyRepo, NewHorseService(horseRepo, new(mocks.MockOrganizationRepository), nil))
+		horseRepo.On("GetByID", ctx, uint(1)).Return(&models.Horse{ID: 1, UserID: "owner"}, nil)
+
+		_, err := svc.EndPregnancy(ctx, "stranger", 1, models.PregnancyStatusComplete, foaled)
+		assert.ErrorIs(t, err, models.ErrAccessDenied)
+		pregnancyRepo.AssertNotCalled(t, "GetCurrentPregnancy", mock.Anything, mock.Anything)
+	})
+
+	tests := []struct {
+		name   string
+		status string
+		date   time.Time
+	}{
+		{"unknown outcome", "INACTIVE", foaled},
+		{"future date", models.PregnancyStatusComplete, now.Add(time.Hour)},
+		{"before conception", models.PregnancyStatusLost, conception.AddDate(0, 0, -1)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setTestNow(t, now)
+			horseRepo := new(mocks.MockHorseRepository)
+			pregnancyRepo := new(mocks.MockPregnancyRepository)
+			svc := NewPregnancyService(horseRepo, pregnancyRepo, NewHorseService(horseRepo, new(mocks.MockOrganizationRepository), nil))
+			horseRepo.On("GetByID", ctx, uint(1)).Return(&models.Horse{ID: 1, UserID: "owner"}, nil)
+			pregnancyRepo.On("GetCurrentPregnancy", ctx, uint(1)).Return(active(), nil)
+
+			_, err := svc.EndPregnancy(ctx, "owner", 1, tt.status, tt.date)
+			assert.ErrorIs(t, err, models.ErrInvalidPregnancyEnd)
+			pregnancyRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+		})
+	}
+}
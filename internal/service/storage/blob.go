@@ -0,0 +1,156 @@
+package storage
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+var (
+	ErrBlobNotFound     = errors.New("blob not found")
+	ErrChecksumMismatch = errors.New("blob checksum mismatch")
+	ErrInvalidSignature = errors.New("invalid or expired signature")
+	ErrInvalidBlobKey   = errors.New("invalid blob key")
+	ErrUnknownBackend   = errors.New("unknown storage backend")
+)
+
+const (
+	BackendLocal = "local"
+	BackendS3    = "s3"
+)
+
+// BlobStore stores opaque blobs under slash-separated keys. Every blob is
+// stored with its SHA-256 checksum, which is verified again when it is read.
+type BlobStore interface {
+	// Put writes the blob. If checksum is not empty the content must hash to it.
+	Put(ctx context.Context, key string, r io.Reader, checksum string) (*BlobInfo, error)
+	// Get returns a reader that fails with ErrChecksumMismatch at EOF if the
+	// content no longer matches the checksum recorded by Put.
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+	List(ctx context.Context, prefix string) ([]string, error)
+	// SignedURL returns a URL that can download the blob without further
+	// authentication until it expires.
+	SignedURL(ctx context.Context, key string, expiry time.Duration) (string, error)
+}
+
+// BlobInfo describes a stored blob
+type BlobInfo struct {
+	Key      string
+	Size     int64
+	Checksum string
+}
+
+// Config selects and configures a blob store backend
+type Config struct {
+	Backend    string
+	BasePath   string
+	PublicURL  string
+	SigningKey string
+
+	S3Endpoint  string
+	S3Bucket    string
+	S3Region    string
+	S3AccessKey string
+	S3SecretKey string
+}
+
+// NewBlobStore creates the blob store selected by cfg.Backend
+func NewBlobStore(cfg Config) (BlobStore, error) {
+	switch cfg.Backend {
+	case "", BackendLocal:
+		return NewLocalBlobStore(cfg.BasePath, cfg.PublicURL, []byte(cfg.SigningKey))
+	case BackendS3:
+		return NewS3BlobStore(S3Config{
+			Endpoint:  cfg.S3Endpoint,
+			Bucket:    cfg.S3Bucket,
+			Region:    cfg.S3Region,
+			AccessKey: cfg.S3AccessKey,
+			SecretKey: cfg.S3SecretKey,
+		})
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownBackend, cfg.Backend)
+	}
+}
+
+// PutContent stores r under a content-addressed key of the form
+// prefix/<sha256><ext>, so identical uploads share one blob.
+func PutContent(ctx context.Context, store BlobStore, prefix, ext string, r io.Reader) (*BlobInfo, error) {
+	tmp, checksum, _, err := spool(r)
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	return store.Put(ctx, ContentKey(prefix, checksum, ext), tmp, checksum)
+}
+
+// ContentKey builds the content-addressed key for a checksum
+func ContentKey(prefix, checksum, ext string) string {
+	return strings.TrimSuffix(prefix, "/") + "/" + checksum + ext
+}
+
+// validateKey rejects keys that could escape the store's namespace
+func validateKey(key string) error {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return fmt.Errorf("%w: %q", ErrInvalidBlobKey, key)
+	}
+	for _, part := range strings.Split(key, "/") {
+		if part == "" || part == "." || part == ".." {
+			return fmt.Errorf("%w: %q", ErrInvalidBlobKey, key)
+		}
+	}
+	return nil
+}
+
+// spool copies r to a temporary file, returning it rewound together with
+// the content's checksum and size
+func spool(r io.Reader) (*os.File, string, int64, error) {
+	tmp, err := os.CreateTemp("", "blob-*")
+	if err != nil {
+		return nil, "", 0, fmt.Errorf("failed to create temp file: %w", err)
+	}
+
+	h := sha256.New()
+	size, err := io.Copy(io.MultiWriter(tmp, h), r)
+	if err == nil {
+		_, err = tmp.Seek(0, io.SeekStart)
+	}
+	if err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return nil, "", 0, fmt.Errorf("failed to buffer blob: %w", err)
+	}
+	return tmp, hex.EncodeToString(h.Sum(nil)), size, nil
+}
+
+// verifyingReader checks the content's checksum once the reader hits EOF
+type verifyingReader struct {
+	io.ReadCloser
+	hash     hash.Hash
+	checksum string
+}
+
+func newVerifyingReader(rc io.ReadCloser, checksum string) io.ReadCloser {
+	if checksum == "" {
+		return rc
+	}
+	return &verifyingReader{ReadCloser: rc, hash: sha256.New(), checksum: checksum}
+}
+
+func (v *verifyingReader) Read(p []byte) (int, error) {
+	n, err := v.ReadCloser.Read(p)
+	v.hash.Write(p[:n])
+	if err == io.EOF && hex.EncodeToString(v.hash.Sum(nil)) != v.checksum {
+		return n, ErrChecksumMismatch
+	}
+	return n, err
+}
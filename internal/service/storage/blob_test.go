@@ -0,0 +1,257 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeS3 is a minimal in-memory stand-in for an S3-compatible server such as
+// MinIO. Like S3 it rejects uploads whose body does not match the signed hash.
+type fakeS3 struct {
+	mu      sync.Mutex
+	bucket  string
+	objects map[string][]byte
+	meta    map[string]string
+}
+
+func newFakeS3(bucket string) *fakeS3 {
+	return &fakeS3{bucket: bucket, objects: make(map[string][]byte), meta: make(map[string]string)}
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if !strings.HasPrefix(r.Header.Get("Authorization"), s3Algorithm+" Credential=") && r.URL.Query().Get("X-Amz-Signature") == "" {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	path := strings.TrimPrefix(r.URL.Path, "/"+f.bucket)
+	key := strings.TrimPrefix(path, "/")
+
+	switch {
+	case r.Method == http.MethodGet && key == "":
+		var result struct {
+			XMLName  xml.Name `xml:"ListBucketResult"`
+			Contents []struct {
+				Key string `xml:"Key"`
+			} `xml:"Contents"`
+		}
+		var keys []string
+		for k := range f.objects {
+			if strings.HasPrefix(k, r.URL.Query().Get("prefix")) {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			result.Contents = append(result.Contents, struct {
+				Key string `xml:"Key"`
+			}{k})
+		}
+		xml.NewEncoder(w).Encode(result)
+	case r.Method == http.MethodPut:
+		body, _ := io.ReadAll(r.Body)
+		sum := sha256.Sum256(body)
+		if hex.EncodeToString(sum[:]) != r.Header.Get("x-amz-content-sha256") {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		f.objects[key] = body
+		f.meta[key] = r.Header.Get(s3ChecksumHeader)
+	case r.Method == http.MethodGet:
+		body, ok := f.objects[key]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set(s3ChecksumHeader, f.meta[key])
+		w.Write(body)
+	case r.Method == http.MethodDelete:
+		delete(f.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func newTestStores(t *testing.T) (map[string]BlobStore, *fakeS3) {
+	local, err := NewLocalBlobStore(t.TempDir(), "http://localhost:8080/api/v1/files", []byte("secret"))
+	require.NoError(t, err)
+
+	fake := newFakeS3("hulta")
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	s3, err := NewS3BlobStore(S3Config{Endpoint: server.URL, Bucket: "hulta", AccessKey: "minio", SecretKey: "minio123"})
+	require.NoError(t, err)
+
+	return map[string]BlobStore{BackendLocal: local, BackendS3: s3}, fake
+}
+
+func TestBlobStore_RoundTrip(t *testing.T) {
+	ctx := context.Background()
+	stores, _ := newTestStores(t)
+
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			info, err := PutContent(ctx, store, "horses/horse_1", ".jpg", strings.NewReader("photo"))
+			require.NoError(t, err)
+			sum := sha256.Sum256([]byte("photo"))
+			assert.Equal(t, "horses/horse_1/"+hex.EncodeToString(sum[:])+".jpg", info.Key)
+			assert.Equal(t, int64(5), info.Size)
+
+			file, err := store.Get(ctx, info.Key)
+			require.NoError(t, err)
+			data, err := io.ReadAll(file)
+			file.Close()
+			require.NoError(t, err)
+			assert.Equal(t, "photo", string(data))
+
+			keys, err := store.List(ctx, "horses/")
+			require.NoError(t, err)
+			assert.Equal(t, []string{info.Key}, keys)
+
+			require.NoError(t, store.Delete(ctx, info.Key))
+			_, err = store.Get(ctx, info.Key)
+			assert.ErrorIs(t, err, ErrBlobNotFound)
+		})
+	}
+}
+
+func TestBlobStore_RejectsWrongChecksum(t *testing.T) {
+	stores, _ := newTestStores(t)
+
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			_, err := store.Put(context.Background(), "exports/a.csv", strings.NewReader("data"), "deadbeef")
+			assert.ErrorIs(t, err, ErrChecksumMismatch)
+		})
+	}
+}
+
+func TestLocalBlobStore_FailedPutLeavesNoChecksum(t *testing.T) {
+	ctx := context.Background()
+	stores, _ := newTestStores(t)
+	local := stores[BackendLocal].(*LocalBlobStore)
+
+	// A directory at the blob path makes the final rename fail
+	_, err := local.Put(ctx, "exports/a.csv/nested", strings.NewReader("x"), "")
+	require.NoError(t, err)
+	_, err = local.Put(ctx, "exports/a.csv", strings.NewReader("data"), "")
+	require.Error(t, err)
+
+	entries, err := os.ReadDir(filepath.Join(local.basePath, "exports"))
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "a.csv", entries[0].Name())
+	assert.True(t, entries[0].IsDir())
+}
+
+func TestBlobStore_InvalidKeys(t *testing.T) {
+	stores, _ := newTestStores(t)
+
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			for _, key := range []string{"", "/etc/passwd", "../secret", "a//b", "a/./b"} {
+				_, err := store.Put(context.Background(), key, strings.NewReader("x"), "")
+				assert.ErrorIs(t, err, ErrInvalidBlobKey, key)
+			}
+		})
+	}
+}
+
+func TestBlobStore_DetectsCorruption(t *testing.T) {
+	ctx := context.Background()
+	stores, fake := newTestStores(t)
+
+	info, err := stores[BackendS3].Put(ctx, "backups/db.sql", strings.NewReader("dump"), "")
+	require.NoError(t, err)
+	fake.objects[info.Key] = []byte("tampered")
+
+	file, err := stores[BackendS3].Get(ctx, info.Key)
+	require.NoError(t, err)
+	_, err = io.ReadAll(file)
+	assert.ErrorIs(t, err, ErrChecksumMismatch)
+
+	local := stores[BackendLocal].(*LocalBlobStore)
+	info, err = local.Put(ctx, "backups/db.sql", strings.NewReader("dump"), "")
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(local.basePath, "backups", "db.sql"), []byte("tampered"), 0644))
+
+	file, err = local.Get(ctx, info.Key)
+	require.NoError(t, err)
+	_, err = io.ReadAll(file)
+	assert.ErrorIs(t, err, ErrChecksumMismatch)
+}
+
+func TestLocalBlobStore_SignedURL(t *testing.T) {
+	store, err := NewLocalBlobStore(t.TempDir(), "http://localhost:8080/api/v1/files/", []byte("secret"))
+	require.NoError(t, err)
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	store.now = func() time.Time { return now }
+
+	signed, err := store.SignedURL(context.Background(), "exports/horses export.csv", time.Hour)
+	require.NoError(t, err)
+
+	u, err := url.Parse(signed)
+	require.NoError(t, err)
+	assert.Equal(t, "/api/v1/files/exports/horses export.csv", u.Path)
+	expires, err := strconv.ParseInt(u.Query().Get("expires"), 10, 64)
+	require.NoError(t, err)
+	signature := u.Query().Get("signature")
+
+	assert.NoError(t, store.VerifySignature("exports/horses export.csv", expires, signature))
+	assert.ErrorIs(t, store.VerifySignature("exports/other.csv", expires, signature), ErrInvalidSignature)
+	assert.ErrorIs(t, store.VerifySignature("exports/horses export.csv", expires+60, signature), ErrInvalidSignature)
+
+	now = now.Add(2 * time.Hour)
+	assert.ErrorIs(t, store.VerifySignature("exports/horses export.csv", expires, signature), ErrInvalidSignature)
+}
+
+func TestS3BlobStore_SignedURL(t *testing.T) {
+	stores, _ := newTestStores(t)
+	store := stores[BackendS3].(*S3BlobStore)
+	store.now = func() time.Time { return time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC) }
+
+	_, err := store.Put(context.Background(), "horses/horse_1/photo.jpg", bytes.NewReader([]byte("photo")), "")
+	require.NoError(t, err)
+
+	signed, err := store.SignedURL(context.Background(), "horses/horse_1/photo.jpg", 15*time.Minute)
+	require.NoError(t, err)
+
+	u, err := url.Parse(signed)
+	require.NoError(t, err)
+	assert.Equal(t, "/hulta/horses/horse_1/photo.jpg", u.Path)
+	assert.Equal(t, "minio/20240501/us-east-1/s3/aws4_request", u.Query().Get("X-Amz-Credential"))
+	assert.Equal(t, "900", u.Query().Get("X-Amz-Expires"))
+	assert.Len(t, u.Query().Get("X-Amz-Signature"), 64)
+
+	resp, err := http.Get(signed)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	assert.Equal(t, "photo", string(body))
+
+	_, err = store.SignedURL(context.Background(), "horses/horse_1/photo.jpg", 8*24*time.Hour)
+	assert.Error(t, err)
+}
@@ -0,0 +1,209 @@
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// checksumSuffix names the sidecar file holding a blob's checksum
+const checksumSuffix = ".sha256"
+
+// LocalBlobStore keeps blobs on the local filesystem. Signed URLs point at
+// PublicURL and are validated with VerifySignature by the file handler.
+type LocalBlobStore struct {
+	basePath   string
+	publicURL  string
+	signingKey []byte
+	now        func() time.Time
+}
+
+func NewLocalBlobStore(basePath, publicURL string, signingKey []byte) (*LocalBlobStore, error) {
+	if err := os.MkdirAll(basePath, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create storage directory: %w", err)
+	}
+	return &LocalBlobStore{
+		basePath:   basePath,
+		publicURL:  strings.TrimSuffix(publicURL, "/"),
+		signingKey: signingKey,
+		now:        time.Now,
+	}, nil
+}
+
+// Put writes to a temporary file first so a failed or mismatching upload
+// never replaces an existing blob
+func (s *LocalBlobStore) Put(ctx context.Context, key string, r io.Reader, checksum string) (*BlobInfo, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create blob directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	h := sha256.New()
+	size, err := io.Copy(io.MultiWriter(tmp, h), r)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to save file: %w", err)
+	}
+
+	sum := hex.EncodeToString(h.Sum(nil))
+	if checksum != "" && checksum != sum {
+		return nil, fmt.Errorf("%w: expected %s, got %s", ErrChecksumMismatch, checksum, sum)
+	}
+
+	// Stage the checksum next to the blob and only move it into place once
+	// the blob itself is there, so a sidecar never describes a missing file
+	sidecar, err := os.CreateTemp(filepath.Dir(path), ".upload-*"+checksumSuffix)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save checksum: %w", err)
+	}
+	defer os.Remove(sidecar.Name())
+	_, err = sidecar.WriteString(sum)
+	if closeErr := sidecar.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to save checksum: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return nil, fmt.Errorf("failed to save file: %w", err)
+	}
+	if err := os.Rename(sidecar.Name(), path+checksumSuffix); err != nil {
+		os.Remove(path)
+		return nil, fmt.Errorf("failed to save checksum: %w", err)
+	}
+
+	return &BlobInfo{Key: key, Size: size, Checksum: sum}, nil
+}
+
+func (s *LocalBlobStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s", ErrBlobNotFound, key)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+
+	checksum, err := os.ReadFile(path + checksumSuffix)
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to read checksum: %w", err)
+	}
+	return newVerifyingReader(file, string(checksum)), nil
+}
+
+func (s *LocalBlobStore) Delete(ctx context.Context, key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("%w: %s", ErrBlobNotFound, key)
+		}
+		return fmt.Errorf("failed to delete file: %w", err)
+	}
+	os.Remove(path + checksumSuffix)
+	return nil
+}
+
+// List returns the keys starting with prefix in lexical order
+func (s *LocalBlobStore) List(ctx context.Context, prefix string) ([]string, error) {
+	var keys []string
+	err := filepath.WalkDir(s.basePath, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || strings.HasSuffix(path, checksumSuffix) || strings.HasPrefix(d.Name(), ".upload-") {
+			return nil
+		}
+		rel, err := filepath.Rel(s.basePath, path)
+		if err != nil {
+			return err
+		}
+		if key := filepath.ToSlash(rel); strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list blobs: %w", err)
+	}
+	return keys, nil
+}
+
+func (s *LocalBlobStore) SignedURL(ctx context.Context, key string, expiry time.Duration) (string, error) {
+	if err := validateKey(key); err != nil {
+		return "", err
+	}
+	expires := s.now().Add(expiry).Unix()
+	query := url.Values{
+		"expires":   {strconv.FormatInt(expires, 10)},
+		"signature": {s.sign(key, expires)},
+	}
+	return fmt.Sprintf("%s/%s?%s", s.publicURL, escapeKey(key), query.Encode()), nil
+}
+
+// VerifySignature checks a signature issued by SignedURL
+func (s *LocalBlobStore) VerifySignature(key string, expires int64, signature string) error {
+	if s.now().Unix() > expires {
+		return ErrInvalidSignature
+	}
+	if !hmac.Equal([]byte(signature), []byte(s.sign(key, expires))) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+func (s *LocalBlobStore) sign(key string, expires int64) string {
+	mac := hmac.New(sha256.New, s.signingKey)
+	fmt.Fprintf(mac, "%s\n%d", key, expires)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (s *LocalBlobStore) path(key string) (string, error) {
+	if err := validateKey(key); err != nil {
+		return "", err
+	}
+	if strings.HasSuffix(key, checksumSuffix) {
+		return "", fmt.Errorf("%w: %q", ErrInvalidBlobKey, key)
+	}
+	return filepath.Join(s.basePath, filepath.FromSlash(key)), nil
+}
+
+// escapeKey escapes each path segment of a key for use in a URL
+func escapeKey(key string) string {
+	parts := strings.Split(key, "/")
+	for i, part := range parts {
+		parts[i] = url.PathEscape(part)
+	}
+	return strings.Join(parts, "/")
+}
@@ -1,9 +1,10 @@
 package storage
 
 import (
+	"context"
 	"fmt"
 	"io"
-	"os"
+	"path"
 	"path/filepath"
 	"strings"
 	"time"
@@ -11,65 +12,80 @@ import (
 	"github.com/polyfant/hulta_pregnancy_app/internal/logger"
 )
 
+// FileStorage keeps horse photos and documents in a BlobStore. Files are
+// content-addressed per horse, so the returned filename is the checksum.
 type FileStorage struct {
-	basePath string
+	blobs BlobStore
 }
 
-func NewFileStorage(basePath string) (*FileStorage, error) {
-	if err := os.MkdirAll(basePath, 0755); err != nil {
-		return nil, fmt.Errorf("failed to create storage directory: %w", err)
-	}
-	return &FileStorage{basePath: basePath}, nil
+func NewFileStorage(blobs BlobStore) *FileStorage {
+	return &FileStorage{blobs: blobs}
 }
 
 func (fs *FileStorage) SaveHorsePhoto(horseID int64, file io.Reader, filename string) (string, error) {
-	// Create horse-specific directory
-	horsePath := filepath.Join(fs.basePath, fmt.Sprintf("horse_%d", horseID))
-	if err := os.MkdirAll(horsePath, 0755); err != nil {
-		return "", fmt.Errorf("failed to create horse directory: %w", err)
-	}
-
-	// Generate unique filename
-	ext := filepath.Ext(filename)
-	newFilename := fmt.Sprintf("%d_%s%s", time.Now().UnixNano(), strings.TrimSuffix(filepath.Base(filename), ext), ext)
-	fullPath := filepath.Join(horsePath, newFilename)
-
-	// Create the file
-	dst, err := os.Create(fullPath)
+	ext := strings.ToLower(filepath.Ext(filename))
+	info, err := PutContent(context.Background(), fs.blobs, horsePrefix(horseID), ext, file)
 	if err != nil {
-		return "", fmt.Errorf("failed to create file: %w", err)
-	}
-	defer dst.Close()
-
-	// Copy the file content
-	if _, err := io.Copy(dst, file); err != nil {
 		return "", fmt.Errorf("failed to save file: %w", err)
 	}
 
+	newFilename := path.Base(info.Key)
 	logger.Info("Saved horse photo", map[string]interface{}{
-		"horseID":   horseID,
-		"filename":  newFilename,
-		"fullPath": fullPath,
+		"horseID":  horseID,
+		"filename": newFilename,
+		"key":      info.Key,
 	})
 
 	return newFilename, nil
 }
 
-func (fs *FileStorage) GetHorsePhotoPath(horseID int64, filename string) string {
-	return filepath.Join(fs.basePath, fmt.Sprintf("horse_%d", horseID), filename)
-}
-
 func (fs *FileStorage) DeleteHorsePhoto(horseID int64, filename string) error {
-	fullPath := fs.GetHorsePhotoPath(horseID, filename)
-	if err := os.Remove(fullPath); err != nil {
+	key, err := horseKey(horseID, filename)
+	if err != nil {
+		return err
+	}
+	if err := fs.blobs.Delete(context.Background(), key); err != nil {
 		return fmt.Errorf("failed to delete file: %w", err)
 	}
 
 	logger.Info("Deleted horse photo", map[string]interface{}{
-		"horseID":   horseID,
-		"filename":  filename,
-		"fullPath": fullPath,
+		"horseID":  horseID,
+		"filename": filename,
+		"key":      key,
 	})
 
 	return nil
 }
+
+// OpenHorseFile opens a stored horse file for reading
+func (fs *FileStorage) OpenHorseFile(horseID int64, filename string) (io.ReadCloser, error) {
+	key, err := horseKey(horseID, filename)
+	if err != nil {
+		return nil, err
+	}
+	file, err := fs.blobs.Get(context.Background(), key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	return file, nil
+}
+
+// HorseFileURL returns a signed download URL for a stored horse file
+func (fs *FileStorage) HorseFileURL(horseID int64, filename string, expiry time.Duration) (string, error) {
+	key, err := horseKey(horseID, filename)
+	if err != nil {
+		return "", err
+	}
+	return fs.blobs.SignedURL(context.Background(), key, expiry)
+}
+
+func horsePrefix(horseID int64) string {
+	return fmt.Sprintf("horses/horse_%d", horseID)
+}
+
+func horseKey(horseID int64, filename string) (string, error) {
+	if filename == "" || filename != filepath.Base(filename) {
+		return "", fmt.Errorf("invalid filename: %s", filename)
+	}
+	return horsePrefix(horseID) + "/" + filename, nil
+}
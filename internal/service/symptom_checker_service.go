@@ -0,0 +1,121 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/polyfant/hulta_pregnancy_app/internal/models"
+	"github.com/polyfant/hulta_pregnancy_app/internal/repository"
+	"github.com/polyfant/hulta_pregnancy_app/internal/service/health"
+)
+
+const (
+	// recentVitalsWindow is how old stored vital signs may be to count for an assessment
+	recentVitalsWindow = 24 * time.Hour
+
+	symptomCheckDisclaimer = "This is a triage aid, not a diagnosis. Contact your veterinarian whenever you are worried about your horse."
+)
+
+// SymptomCheckerServiceImpl matches observed symptoms against the condition catalogue
+type SymptomCheckerServiceImpl struct {
+	catalogue  *health.ConditionCatalogue
+	healthRepo repository.HealthRepository
+	vitalsRepo repository.VitalSignsRepository
+}
+
+func NewSymptomCheckerService(catalogue *health.ConditionCatalogue, healthRepo repository.HealthRepository, vitalsRepo repository.VitalSignsRepository) SymptomCheckerService {
+	return &SymptomCheckerServiceImpl{
+		catalogue:  catalogue,
+		healthRepo: healthRepo,
+		vitalsRepo: vitalsRepo,
+	}
+}
+
+// Assess ranks the conditions matching the symptoms and logs the assessment
+// as a health record so it shows up in the horse's history
+func (s *SymptomCheckerServiceImpl) Assess(ctx context.Context, horse *models.Horse, userID string, req models.SymptomAssessmentRequest) (*models.SymptomAssessment, error) {
+	symptoms := make([]string, 0, len(req.Symptoms))
+	for _, symptom := range req.Symptoms {
+		if symptom = strings.TrimSpace(symptom); symptom != "" {
+			symptoms = append(symptoms, symptom)
+		}
+	}
+	if len(symptoms) == 0 {
+		return nil, fmt.Errorf("%w: at least one symptom is required", models.ErrInvalidHealthRecord)
+	}
+
+	now := timeNow()
+	vitals := req.Vitals
+	if vitals == nil {
+		since := now.Add(-recentVitalsWindow)
+		recent, err := s.vitalsRepo.ListByHorse(ctx, horse.ID, &since)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get vital signs: %w", err)
+		}
+		if len(recent) > 0 {
+			vitals = &recent[0]
+		}
+	}
+
+	assessment := &models.SymptomAssessment{
+		HorseID:    horse.ID,
+		AssessedAt: now,
+		Symptoms:   symptoms,
+		Action:     "No matching condition found. Keep monitoring and contact your veterinarian if you are concerned.",
+		Disclaimer: symptomCheckDisclaimer,
+	}
+
+	category := health.VitalSignsCategory(*horse, now)
+	if vitals != nil {
+		assessment.VitalFindings = health.EvaluateVitalSigns(category, *vitals)
+	}
+	assessment.Conditions, assessment.Unrecognised = s.catalogue.Assess(symptoms, category, vitals)
+
+	// The most urgent candidate sets the advice, even if it is not the most likely
+	for _, condition := range assessment.Conditions {
+		if condition.Urgency > assessment.Urgency {
+			assessment.Urgency = condition.Urgency
+			assessment.Action = condition.Action
+		}
+	}
+	if assessment.Urgency == 0 {
+		assessment.Urgency = 1
+	}
+
+	record := &models.HealthRecord{
+		HorseID:     horse.ID,
+		UserID:      userID,
+		Type:        string(models.HealthRecordTypeOther),
+		Date:        now,
+		Description: describeAssessment(assessment, req.Notes),
+	}
+	if err := s.healthRepo.CreateRecord(ctx, record); err != nil {
+		return nil, fmt.Errorf("failed to log symptom check: %w", err)
+	}
+	assessment.HealthRecordID = record.ID
+
+	return assessment, nil
+}
+
+func describeAssessment(assessment *models.SymptomAssessment, notes string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Symptom check: %s.", strings.Join(assessment.Symptoms, ", "))
+	if len(assessment.Conditions) == 0 {
+		b.WriteString(" No matching condition.")
+	} else {
+		top := make([]string, 0, 3)
+		for i, condition := range assessment.Conditions {
+			if i == 3 {
+				break
+			}
+			top = append(top, fmt.Sprintf("%s (%.0f%%)", condition.Name, condition.Confidence*100))
+		}
+		fmt.Fprintf(&b, " Possible: %s. Urgency %d/5.", strings.Join(top, ", "), assessment.Urgency)
+	}
+	if notes = strings.TrimSpace(notes); notes != "" {
+		fmt.Fprintf(&b, " Notes: %s", notes)
+	}
+	return b.String()
+}
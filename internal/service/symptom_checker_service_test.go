@@ -0,0 +1,89 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/polyfant/hulta_pregnancy_app/internal/mocks"
+	"github.com/polyfant/hulta_pregnancy_app/internal/models"
+	"github.com/polyfant/hulta_pregnancy_app/internal/service/health"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSymptomCheckerService_Assess(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	horse := &models.Horse{ID: 7, BirthDate: now.AddDate(-9, 0, 0)}
+
+	conditions, err := health.ParseConditions([]byte(`
+conditions:
+  - name: Cough
+    urgency: 2
+    action: Monitor
+    symptoms: [{label: Coughing}]
+  - name: Colic
+    urgency: 5
+    action: Call the vet
+    symptoms: [{label: Pawing}, {label: Rolling}, {label: Sweating}]
+    vitals: [{sign: heart_rate, direction: high}]
+`))
+	require.NoError(t, err)
+	catalogue := &health.ConditionCatalogue{Conditions: conditions}
+
+	newService := func() (*SymptomCheckerServiceImpl, *mocks.MockHealthRepository, *mocks.MockVitalSignsRepository) {
+		healthRepo := new(mocks.MockHealthRepository)
+		vitalsRepo := new(mocks.MockVitalSignsRepository)
+		svc := NewSymptomCheckerService(catalogue, healthRepo, vitalsRepo).(*SymptomCheckerServiceImpl)
+		setTestNow(t, now)
+		return svc, healthRepo, vitalsRepo
+	}
+
+	t.Run("uses recent vitals and logs a health record", func(t *testing.T) {
+		svc, healthRepo, vitalsRepo := newService()
+		heartRate := 64
+		since := now.Add(-24 * time.Hour)
+		vitalsRepo.On("ListByHorse", ctx, uint(7), &since).Return([]models.VitalSignsRecord{{HeartRate: &heartRate}}, nil)
+		healthRepo.On("CreateRecord", ctx, mock.MatchedBy(func(r *models.HealthRecord) bool {
+			return r.Type == "OTHER" && r.UserID == "owner" &&
+				r.Description == "Symptom check: pawing, coughing. Possible: Cough (100%), Colic (50%). Urgency 5/5."
+		})).Return(nil).Run(func(args mock.Arguments) {
+			args.Get(1).(*models.HealthRecord).ID = 99
+		})
+
+		assessment, err := svc.Assess(ctx, horse, "owner", models.SymptomAssessmentRequest{Symptoms: []string{"pawing", " coughing "}})
+
+		require.NoError(t, err)
+		require.Len(t, assessment.Conditions, 2)
+		assert.Equal(t, "Cough", assessment.Conditions[0].Name, "most likely first")
+		assert.Equal(t, 5, assessment.Urgency, "most urgent candidate sets the advice")
+		assert.Equal(t, "Call the vet", assessment.Action)
+		assert.Len(t, assessment.VitalFindings, 1)
+		assert.Equal(t, uint(99), assessment.HealthRecordID)
+		assert.NotEmpty(t, assessment.Disclaimer)
+	})
+
+	t.Run("vitals in the request skip the lookup", func(t *testing.T) {
+		svc, healthRepo, vitalsRepo := newService()
+		healthRepo.On("CreateRecord", ctx, mock.Anything).Return(nil)
+
+		assessment, err := svc.Assess(ctx, horse, "owner", models.SymptomAssessmentRequest{
+			Symptoms: []string{"itchy"},
+			Vitals:   &models.VitalSignsRecord{},
+		})
+
+		require.NoError(t, err)
+		assert.Empty(t, assessment.Conditions)
+		assert.Equal(t, 1, assessment.Urgency)
+		assert.Equal(t, []string{"itchy"}, assessment.Unrecognised)
+		vitalsRepo.AssertNotCalled(t, "ListByHorse", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("blank symptoms", func(t *testing.T) {
+		svc, _, _ := newService()
+		_, err := svc.Assess(ctx, horse, "owner", models.SymptomAssessmentRequest{Symptoms: []string{" "}})
+		assert.ErrorIs(t, err, models.ErrInvalidHealthRecord)
+	})
+}
@@ -0,0 +1,236 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/polyfant/hulta_pregnancy_app/internal/models"
+	"github.com/polyfant/hulta_pregnancy_app/internal/repository"
+	"github.com/polyfant/hulta_pregnancy_app/internal/service/health"
+	"gorm.io/gorm"
+)
+
+// TreatmentServiceImpl records treatment plans and checks them against the horse's pregnancy
+type TreatmentServiceImpl struct {
+	repo          repository.TreatmentRepository
+	pregnancyRepo repository.PregnancyRepository
+	weights       repository.WeightRepository
+}
+
+func NewTreatmentService(repo repository.TreatmentRepository, pregnancyRepo repository.PregnancyRepository, weights repository.WeightRepository) TreatmentService {
+	return &TreatmentServiceImpl{
+		repo:          repo,
+		pregnancyRepo: pregnancyRepo,
+		weights:       weights,
+	}
+}
+
+// CreatePlan saves the plan and fills in its warnings. Contraindicated drugs
+// are still accepted because the vet may have prescribed them knowingly.
+// Plans prescribed per kg are dosed for the horse's latest weight.
+func (s *TreatmentServiceImpl) CreatePlan(ctx context.Context, horse *models.Horse, plan *models.TreatmentPlan) error {
+	plan.Drug = strings.TrimSpace(plan.Drug)
+	plan.Status = models.TreatmentStatusActive
+	plan.StoppedReason = ""
+	if err := s.dosePerKg(ctx, horse, plan); err != nil {
+		return err
+	}
+	if err := validateTreatmentPlan(plan); err != nil {
+		return err
+	}
+
+	pregnant, err := s.isPregnant(ctx, horse.ID)
+	if err != nil {
+		return err
+	}
+
+	plan.HorseID = horse.ID
+	plan.FoodChainExcluded = plan.FoodChainExcluded || health.IsFoodChainExcluded(plan.Drug)
+	if err := s.repo.CreatePlan(ctx, plan); err != nil {
+		return fmt.Errorf("failed to save treatment plan: %w", err)
+	}
+	plan.Warnings = health.TreatmentWarnings(*plan, pregnant)
+	return nil
+}
+
+func (s *TreatmentServiceImpl) ListPlans(ctx context.Context, horse *models.Horse) ([]models.TreatmentPlanSummary, error) {
+	plans, err := s.repo.ListPlansByHorse(ctx, horse.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get treatment plans: %w", err)
+	}
+	doses, err := s.repo.ListDosesByHorse(ctx, horse.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get dose log: %w", err)
+	}
+	pregnant, err := s.isPregnant(ctx, horse.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	byPlan := make(map[uint][]models.DoseAdministration)
+	for _, dose := range doses {
+		byPlan[dose.PlanID] = append(byPlan[dose.PlanID], dose)
+	}
+
+	now := timeNow()
+	summaries := make([]models.TreatmentPlanSummary, 0, len(plans))
+	for _, plan := range plans {
+		summaries = append(summaries, s.summarize(plan, byPlan[plan.ID], pregnant, now))
+	}
+	return summaries, nil
+}
+
+func (s *TreatmentServiceImpl) GetPlan(ctx context.Context, horse *models.Horse, planID uint) (*models.TreatmentPlanSummary, error) {
+	summaries, err := s.ListPlans(ctx, horse)
+	if err != nil {
+		return nil, err
+	}
+	for i := range summaries {
+		if summaries[i].ID == planID {
+			return &summaries[i], nil
+		}
+	}
+	return nil, models.ErrTreatmentNotFound
+}
+
+// LogDose records an administration. The dose defaults to the planned dose.
+func (s *TreatmentServiceImpl) LogDose(ctx context.Context, horse *models.Horse, planID uint, dose *models.DoseAdministration) error {
+	plan, err := s.get(ctx, horse.ID, planID)
+	if err != nil {
+		return err
+	}
+
+	now := timeNow()
+	if dose.AdministeredAt.IsZero() {
+		dose.AdministeredAt = now
+	}
+	if dose.Dose == 0 {
+		dose.Dose = plan.Dose
+	}
+	switch {
+	case dose.Dose < 0:
+		return fmt.Errorf("%w: dose must be positive", models.ErrInvalidTreatment)
+	case dose.AdministeredAt.After(now):
+		return fmt.Errorf("%w: administered_at cannot be in the future", models.ErrInvalidTreatment)
+	case dose.AdministeredAt.Before(plan.StartDate):
+		return fmt.Errorf("%w: administered_at is before the plan starts", models.ErrInvalidTreatment)
+	case plan.EndDate != nil && dose.AdministeredAt.After(*plan.EndDate):
+		return fmt.Errorf("%w: administered_at is after the plan ended", models.ErrInvalidTreatment)
+	}
+
+	dose.PlanID = plan.ID
+	dose.HorseID = horse.ID
+	if err := s.repo.CreateDose(ctx, dose); err != nil {
+		return fmt.Errorf("failed to save dose: %w", err)
+	}
+	return nil
+}
+
+// StopPlan ends a course early. The end date moves to now so the withdrawal
+// periods run from the last dose actually due.
+func (s *TreatmentServiceImpl) StopPlan(ctx context.Context, horse *models.Horse, planID uint, reason string) (*models.TreatmentPlan, error) {
+	plan, err := s.get(ctx, horse.ID, planID)
+	if err != nil {
+		return nil, err
+	}
+	if plan.Status == models.TreatmentStatusStopped {
+		return nil, fmt.Errorf("%w: plan is already stopped", models.ErrInvalidTreatment)
+	}
+
+	now := timeNow()
+	if plan.EndDate == nil || plan.EndDate.After(now) {
+		plan.EndDate = &now
+	}
+	plan.Status = models.TreatmentStatusStopped
+	plan.StoppedReason = strings.TrimSpace(reason)
+	if err := s.repo.UpdatePlan(ctx, plan); err != nil {
+		return nil, fmt.Errorf("failed to stop treatment plan: %w", err)
+	}
+	return plan, nil
+}
+
+func (s *TreatmentServiceImpl) GetWithdrawal(ctx context.Context, horse *models.Horse) (*models.WithdrawalStatus, error) {
+	summaries, err := s.ListPlans(ctx, horse)
+	if err != nil {
+		return nil, err
+	}
+	status := health.Withdrawal(horse.ID, summaries, timeNow())
+	return &status, nil
+}
+
+func (s *TreatmentServiceImpl) summarize(plan models.TreatmentPlan, doses []models.DoseAdministration, pregnant bool, now time.Time) models.TreatmentPlanSummary {
+	summary := health.SummarizeTreatment(plan, doses, now)
+	// Pregnancy warnings only matter while the horse is still being treated
+	summary.Warnings = health.TreatmentWarnings(plan, pregnant && summary.Status == models.TreatmentStatusActive)
+	return summary
+}
+
+// get loads a plan and makes sure it belongs to the horse
+func (s *TreatmentServiceImpl) get(ctx context.Context, horseID, planID uint) (*models.TreatmentPlan, error) {
+	plan, err := s.repo.GetPlan(ctx, planID)
+	if err != nil || plan.HorseID != horseID {
+		return nil, models.ErrTreatmentNotFound
+	}
+	return plan, nil
+}
+
+func (s *TreatmentServiceImpl) isPregnant(ctx context.Context, horseID uint) (bool, error) {
+	pregnancy, err := s.pregnancyRepo.GetCurrentPregnancy(ctx, horseID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to get pregnancy: %w", err)
+	}
+	return pregnancy.IsActive(), nil
+}
+
+// dosePerKg calculates the dose of a plan prescribed per kg of body weight
+func (s *TreatmentServiceImpl) dosePerKg(ctx context.Context, horse *models.Horse, plan *models.TreatmentPlan) error {
+	if plan.DosePerKg < 0 {
+		return fmt.Errorf("%w: dose_per_kg cannot be negative", models.ErrInvalidTreatment)
+	}
+	if plan.DosePerKg == 0 {
+		plan.WeightKg = 0
+		return nil
+	}
+
+	latest, err := s.weights.GetLatest(ctx, horse.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get latest weight: %w", err)
+	}
+	weight, ok := health.CurrentWeight(*horse, latest)
+	if !ok {
+		return fmt.Errorf("%w: weigh the horse before prescribing per kg", models.ErrInvalidTreatment)
+	}
+
+	plan.WeightKg = weight
+	plan.Dose = math.Round(plan.DosePerKg*weight*1000) / 1000
+	return nil
+}
+
+func validateTreatmentPlan(plan *models.TreatmentPlan) error {
+	switch {
+	case plan.Drug == "":
+		return fmt.Errorf("%w: drug is required", models.ErrInvalidTreatment)
+	case plan.Dose <= 0:
+		return fmt.Errorf("%w: dose must be positive", models.ErrInvalidTreatment)
+	case strings.TrimSpace(plan.DoseUnit) == "":
+		return fmt.Errorf("%w: dose_unit is required", models.ErrInvalidTreatment)
+	case !plan.Route.IsValid():
+		return fmt.Errorf("%w: unknown route %q", models.ErrInvalidTreatment, plan.Route)
+	case plan.FrequencyHours < 0:
+		return fmt.Errorf("%w: frequency_hours cannot be negative", models.ErrInvalidTreatment)
+	case plan.StartDate.IsZero():
+		return fmt.Errorf("%w: start_date is required", models.ErrInvalidTreatment)
+	case plan.EndDate != nil && plan.EndDate.Before(plan.StartDate):
+		return fmt.Errorf("%w: end_date is before start_date", models.ErrInvalidTreatment)
+	case plan.CompetitionWithdrawalHours < 0 || plan.SlaughterWithdrawalDays < 0:
+		return fmt.Errorf("%w: withdrawal periods cannot be negative", models.ErrInvalidTreatment)
+	}
+	return nil
+}
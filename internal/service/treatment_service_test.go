@@ -0,0 +1,257 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/polyfant/hulta_pregnancy_app/internal/mocks"
+	"github.com/polyfant/hulta_pregnancy_app/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestTreatmentService_CreatePlan(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	horse := &models.Horse{ID: 4}
+	valid := func() models.TreatmentPlan {
+		return models.TreatmentPlan{
+			Drug: "Flunixin", Dose: 500, DoseUnit: "mg", Route: models.MedicationRouteIntravenous,
+			FrequencyHours: 24, StartDate: now,
+		}
+	}
+	before := now.AddDate(0, 0, -1)
+
+	tests := []struct {
+		name         string
+		plan         func() models.TreatmentPlan
+		pregnancy    *models.Pregnancy
+		wantErr      error
+		wantWarnings int
+	}{
+		{name: "not pregnant", plan: valid},
+		{name: "pregnant mare gets a warning", plan: valid, pregnancy: &models.Pregnancy{Status: models.PregnancyStatusActive}, wantWarnings: 1},
+		{name: "missing drug", plan: func() models.TreatmentPlan { p := valid(); p.Drug = " "; return p }, wantErr: models.ErrInvalidTreatment},
+		{name: "zero dose", plan: func() models.TreatmentPlan { p := valid(); p.Dose = 0; return p }, wantErr: models.ErrInvalidTreatment},
+		{name: "unknown route", plan: func() models.TreatmentPlan { p := valid(); p.Route = "NASAL"; return p }, wantErr: models.ErrInvalidTreatment},
+		{name: "end before start", plan: func() models.TreatmentPlan { p := valid(); p.EndDate = &before; return p }, wantErr: models.ErrInvalidTreatment},
+		{name: "negative withdrawal", plan: func() models.TreatmentPlan { p := valid(); p.SlaughterWithdrawalDays = -1; return p }, wantErr: models.ErrInvalidTreatment},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setTestNow(t, now)
+			repo := new(mocks.MockTreatmentRepository)
+			pregnancyRepo := new(mocks.MockPregnancyRepository)
+			svc := NewTreatmentService(repo, pregnancyRepo, new(mocks.MockWeightRepository))
+			if tt.pregnancy != nil {
+				pregnancyRepo.On("GetCurrentPregnancy", ctx, horse.ID).Return(tt.pregnancy, nil)
+			} else {
+				pregnancyRepo.On("GetCurrentPregnancy", ctx, horse.ID).Return(nil, gorm.ErrRecordNotFound)
+			}
+			repo.On("CreatePlan", ctx, mock.AnythingOfType("*models.TreatmentPlan")).Return(nil)
+
+			plan := tt.plan()
+			err := svc.CreatePlan(ctx, horse, &plan)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				repo.AssertNotCalled(t, "CreatePlan", mock.Anything, mock.Anything)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, horse.ID, plan.HorseID)
+			assert.Equal(t, models.TreatmentStatusActive, plan.Status)
+			assert.Len(t, plan.Warnings, tt.wantWarnings)
+		})
+	}
+}
+
+func TestTreatmentService_CreatePlanFlagsFoodChainExclusion(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	setTestNow(t, now)
+	repo := new(mocks.MockTreatmentRepository)
+	pregnancyRepo := new(mocks.MockPregnancyRepository)
+	svc := NewTreatmentService(repo, pregnancyRepo, new(mocks.MockWeightRepository))
+	pregnancyRepo.On("GetCurrentPregnancy", ctx, uint(4)).Return(nil, gorm.ErrRecordNotFound)
+	repo.On("CreatePlan", ctx, mock.AnythingOfType("*models.TreatmentPlan")).Return(nil)
+
+	plan := models.TreatmentPlan{Drug: "Bute", Dose: 2, DoseUnit: "g", Route: models.MedicationRouteOral, StartDate: now}
+	require.NoError(t, svc.CreatePlan(ctx, &models.Horse{ID: 4}, &plan))
+	assert.True(t, plan.FoodChainExcluded)
+}
+
+func TestTreatmentService_CreatePlanPregnancyLookupFails(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	setTestNow(t, now)
+	repo := new(mocks.MockTreatmentRepository)
+	pregnancyRepo := new(mocks.MockPregnancyRepository)
+	svc := NewTreatmentService(repo, pregnancyRepo, new(mocks.MockWeightRepository))
+	pregnancyRepo.On("GetCurrentPregnancy", ctx, uint(4)).Return(nil, errors.New("connection refused"))
+
+	plan := models.TreatmentPlan{Drug: "Flunixin", Dose: 1, DoseUnit: "g", Route: models.MedicationRouteOral, StartDate: now}
+	err := svc.CreatePlan(ctx, &models.Horse{ID: 4}, &plan)
+	assert.Error(t, err)
+	repo.AssertNotCalled(t, "CreatePlan", mock.Anything, mock.Anything)
+}
+
+func TestTreatmentService_LogDose(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 6, 5, 12, 0, 0, 0, time.UTC)
+	start := now.AddDate(0, 0, -4)
+	end := now.AddDate(0, 0, -1)
+	plan := &models.TreatmentPlan{ID: 9, HorseID: 4, Dose: 500, FrequencyHours: 24, StartDate: start, Status: models.TreatmentStatusActive}
+	ended := &models.TreatmentPlan{ID: 10, HorseID: 4, Dose: 500, StartDate: start, EndDate: &end, Status: models.TreatmentStatusCompleted}
+
+	tests := []struct {
+		name    string
+		planID  uint
+		horseID uint
+		dose    models.DoseAdministration
+		wantErr error
+	}{
+		{name: "defaults to now and planned dose", planID: 9, horseID: 4},
+		{name: "future dose", planID: 9, horseID: 4, dose: models.DoseAdministration{AdministeredAt: now.Add(time.Hour)}, wantErr: models.ErrInvalidTreatment},
+		{name: "before the plan", planID: 9, horseID: 4, dose: models.DoseAdministration{AdministeredAt: start.Add(-time.Hour)}, wantErr: models.ErrInvalidTreatment},
+		{name: "after the plan ended", planID: 10, horseID: 4, wantErr: models.ErrInvalidTreatment},
+		{name: "plan of another horse", planID: 9, horseID: 5, wantErr: models.ErrTreatmentNotFound},
+		{name: "unknown plan", planID: 11, horseID: 4, wantErr: models.ErrTreatmentNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setTestNow(t, now)
+			repo := new(mocks.MockTreatmentRepository)
+			svc := NewTreatmentService(repo, new(mocks.MockPregnancyRepository), new(mocks.MockWeightRepository))
+			repo.On("GetPlan", ctx, uint(9)).Return(plan, nil)
+			repo.On("GetPlan", ctx, uint(10)).Return(ended, nil)
+			repo.On("GetPlan", ctx, uint(11)).Return(nil, gorm.ErrRecordNotFound)
+			repo.On("CreateDose", ctx, mock.AnythingOfType("*models.DoseAdministration")).Return(nil)
+
+			dose := tt.dose
+			err := svc.LogDose(ctx, &models.Horse{ID: tt.horseID}, tt.planID, &dose)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				repo.AssertNotCalled(t, "CreateDose", mock.Anything, mock.Anything)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, now, dose.AdministeredAt)
+			assert.Equal(t, 500.0, dose.Dose)
+			assert.Equal(t, uint(9), dose.PlanID)
+		})
+	}
+}
+
+func TestTreatmentService_StopPlan(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 6, 5, 12, 0, 0, 0, time.UTC)
+	setTestNow(t, now)
+	repo := new(mocks.MockTreatmentRepository)
+	svc := NewTreatmentService(repo, new(mocks.MockPregnancyRepository), new(mocks.MockWeightRepository))
+	plan := &models.TreatmentPlan{ID: 9, HorseID: 4, StartDate: now.AddDate(0, 0, -2), Status: models.TreatmentStatusActive}
+	repo.On("GetPlan", ctx, uint(9)).Return(plan, nil)
+	repo.On("UpdatePlan", ctx, plan).Return(nil)
+
+	stopped, err := svc.StopPlan(ctx, &models.Horse{ID: 4}, 9, " hives ")
+	require.NoError(t, err)
+	assert.Equal(t, models.TreatmentStatusStopped, stopped.Status)
+	assert.Equal(t, "hives", stopped.StoppedReason)
+	require.NotNil(t, stopped.EndDate)
+	assert.Equal(t, now, *stopped.EndDate)
+
+	_, err = svc.StopPlan(ctx, &models.Horse{ID: 4}, 9, "")
+	assert.ErrorIs(t, err, models.ErrInvalidTreatment)
+}
+
+func TestTreatmentService_GetWithdrawal(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 6, 10, 12, 0, 0, 0, time.UTC)
+	setTestNow(t, now)
+	repo := new(mocks.MockTreatmentRepository)
+	pregnancyRepo := new(mocks.MockPregnancyRepository)
+	svc := NewTreatmentService(repo, pregnancyRepo, new(mocks.MockWeightRepository))
+	horse := &models.Horse{ID: 4}
+
+	start := now.AddDate(0, 0, -5)
+	end := now.AddDate(0, 0, -2)
+	repo.On("ListPlansByHorse", ctx, horse.ID).Return([]models.TreatmentPlan{{
+		ID: 9, HorseID: 4, Drug: "Flunixin", FrequencyHours: 24, StartDate: start, EndDate: &end,
+		Status: models.TreatmentStatusActive, CompetitionWithdrawalHours: 24, SlaughterWithdrawalDays: 28,
+	}}, nil)
+	repo.On("ListDosesByHorse", ctx, horse.ID).Return([]models.DoseAdministration{
+		{PlanID: 9, AdministeredAt: start},
+		{PlanID: 9, AdministeredAt: end},
+	}, nil)
+	pregnancyRepo.On("GetCurrentPregnancy", ctx, horse.ID).Return(&models.Pregnancy{Status: models.PregnancyStatusActive}, nil)
+
+	plans, err := svc.ListPlans(ctx, horse)
+	require.NoError(t, err)
+	require.Len(t, plans, 1)
+	assert.Equal(t, models.TreatmentStatusCompleted, plans[0].Status)
+	assert.Len(t, plans[0].MissedDoses, 2)
+	assert.Empty(t, plans[0].Warnings, "pregnancy warnings are dropped once the course is over")
+
+	status, err := svc.GetWithdrawal(ctx, horse)
+	require.NoError(t, err)
+	assert.True(t, status.ClearForCompetition)
+	assert.False(t, status.ClearForSlaughter)
+	require.NotNil(t, status.SlaughterClearAt)
+	assert.Equal(t, end.AddDate(0, 0, 28), *status.SlaughterClearAt)
+	assert.Equal(t, []uint{9}, status.BlockingPlans)
+}
+
+func TestTreatmentService_CreatePlanPerKg(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		horseWeight float64
+		latest      *models.WeightRecord
+		wantDose    float64
+		wantWeight  float64
+		wantErr     error
+	}{
+		{name: "dosed for the latest weighing", horseWeight: 500, latest: &models.WeightRecord{WeightKg: 452}, wantDose: 1.989, wantWeight: 452},
+		{name: "falls back to the horse record", horseWeight: 500, wantDose: 2.2, wantWeight: 500},
+		{name: "never weighed", wantErr: models.ErrInvalidTreatment},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(mocks.MockTreatmentRepository)
+			pregnancyRepo := new(mocks.MockPregnancyRepository)
+			weights := new(mocks.MockWeightRepository)
+			svc := NewTreatmentService(repo, pregnancyRepo, weights).(*TreatmentServiceImpl)
+			setTestNow(t, now)
+			horse := &models.Horse{ID: 4, Weight: tt.horseWeight}
+			if tt.latest != nil {
+				weights.On("GetLatest", ctx, horse.ID).Return(tt.latest, nil)
+			} else {
+				weights.On("GetLatest", ctx, horse.ID).Return(nil, nil)
+			}
+			pregnancyRepo.On("GetCurrentPregnancy", ctx, horse.ID).Return(nil, gorm.ErrRecordNotFound)
+			repo.On("CreatePlan", ctx, mock.AnythingOfType("*models.TreatmentPlan")).Return(nil)
+
+			plan := models.TreatmentPlan{Drug: "Flunixin", DosePerKg: 0.0044, DoseUnit: "g", Route: models.MedicationRouteIntravenous, StartDate: now}
+			err := svc.CreatePlan(ctx, horse, &plan)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				repo.AssertNotCalled(t, "CreatePlan", mock.Anything, mock.Anything)
+				return
+			}
+			require.NoError(t, err)
+			assert.InDelta(t, tt.wantDose, plan.Dose, 0.0005)
+			assert.Equal(t, tt.wantWeight, plan.WeightKg)
+		})
+	}
+}
@@ -0,0 +1,282 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/polyfant/hulta_pregnancy_app/internal/models"
+	"github.com/polyfant/hulta_pregnancy_app/internal/repository"
+)
+
+// MaxVetAccessDuration caps how long a single grant may stay valid
+const MaxVetAccessDuration = 90 * 24 * time.Hour
+
+// PrivacyPreferencesProvider looks up a user's privacy preferences
+type PrivacyPreferencesProvider interface {
+	GetPrivacyPreferences(ctx context.Context, userID string) (*models.PrivacyPreferences, error)
+}
+
+// AuditLogger records security-relevant events
+type AuditLogger interface {
+	LogEvent(userID, action string, details map[string]interface{}) error
+}
+
+// VetAccessServiceImpl manages time-limited sharing of health and pregnancy records with vets
+type VetAccessServiceImpl struct {
+	repo      repository.VetAccessRepository
+	horseRepo repository.HorseRepository
+	privacy   PrivacyPreferencesProvider
+	audit     AuditLogger
+}
+
+func NewVetAccessService(
+	repo repository.VetAccessRepository,
+	horseRepo repository.HorseRepository,
+	privacy PrivacyPreferencesProvider,
+	audit AuditLogger,
+) VetAccessService {
+	return &VetAccessServiceImpl{
+		repo:      repo,
+		horseRepo: horseRepo,
+		privacy:   privacy,
+		audit:     audit,
+	}
+}
+
+// CreateGrant stores a new grant for the given horses. When no vet account is
+// named a signed link is issued instead; its token is returned once and only
+// its hash is stored.
+func (s *VetAccessServiceImpl) CreateGrant(ctx context.Context, grant *models.VetAccessGrant, horseIDs []uint) (string, error) {
+	if err := s.validateGrant(grant, horseIDs); err != nil {
+		return "", err
+	}
+	if err := s.checkSharingEnabled(ctx, grant.OwnerID); err != nil {
+		return "", err
+	}
+
+	grant.Horses = make([]models.VetAccessGrantHorse, 0, len(horseIDs))
+	for _, horseID := range horseIDs {
+		horse, err := s.horseRepo.GetByID(ctx, horseID)
+		if err != nil {
+			return "", fmt.Errorf("failed to get horse %d: %w", horseID, err)
+		}
+		if horse.UserID != grant.OwnerID {
+			return "", fmt.Errorf("horse %d: %w", horseID, models.ErrAccessDenied)
+		}
+		grant.Horses = append(grant.Horses, models.VetAccessGrantHorse{HorseID: horseID})
+	}
+
+	var token string
+	if grant.VetUserID == nil {
+		var err error
+		token, err = generateAccessToken()
+		if err != nil {
+			return "", fmt.Errorf("failed to generate access token: %w", err)
+		}
+		tokenHash := hashAccessToken(token)
+		grant.TokenHash = &tokenHash
+	} else {
+		grant.TokenHash = nil
+	}
+	grant.RevokedAt = nil
+
+	if err := s.repo.Create(ctx, grant); err != nil {
+		return "", fmt.Errorf("failed to create vet access grant: %w", err)
+	}
+
+	if err := s.record(grant.OwnerID, "vet_access.granted", grant, map[string]interface{}{
+		"horse_ids": horseIDs,
+	}); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+func (s *VetAccessServiceImpl) ListGrants(ctx context.Context, ownerID string) ([]models.VetAccessGrant, error) {
+	grants, err := s.repo.ListByOwner(ctx, ownerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list vet access grants: %w", err)
+	}
+	return grants, nil
+}
+
+// ListReceivedGrants returns the grants that are currently active for a vet
+func (s *VetAccessServiceImpl) ListReceivedGrants(ctx context.Context, vetUserID string) ([]models.VetAccessGrant, error) {
+	grants, err := s.repo.ListByVet(ctx, vetUserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list vet access grants: %w", err)
+	}
+
+	now := timeNow()
+	active := make([]models.VetAccessGrant, 0, len(grants))
+	for _, grant := range grants {
+		if grant.IsActive(now) {
+			active = append(active, grant)
+		}
+	}
+	return active, nil
+}
+
+func (s *VetAccessServiceImpl) RevokeGrant(ctx context.Context, ownerID string, grantID uint) error {
+	grant, err := s.repo.GetByID(ctx, grantID)
+	if err != nil {
+		return fmt.Errorf("failed to get vet access grant: %w", err)
+	}
+	if grant.OwnerID != ownerID {
+		return models.ErrAccessDenied
+	}
+	if grant.RevokedAt != nil {
+		return nil
+	}
+
+	if err := s.repo.Revoke(ctx, grantID, timeNow()); err != nil {
+		return fmt.Errorf("failed to revoke vet access grant: %w", err)
+	}
+	return s.record(ownerID, "vet_access.revoked", grant, nil)
+}
+
+// Authorize checks whether a vet account holds an active grant for the horse's
+// records and records the access in the audit trail.
+func (s *VetAccessServiceImpl) Authorize(ctx context.Context, vetUserID string, horse *models.Horse, scope models.RecordScope, permission models.Permission) error {
+	grants, err := s.repo.ListActiveForVetAndHorse(ctx, vetUserID, horse.ID, timeNow())
+	if err != nil {
+		return models.ErrAccessDenied
+	}
+
+	for i := range grants {
+		grant := &grants[i]
+		if !s.permits(grant, horse, scope, permission) {
+			continue
+		}
+		if err := s.checkSharingEnabled(ctx, grant.OwnerID); err != nil {
+			return models.ErrAccessDenied
+		}
+		return s.recordAccess(vetUserID, grant, horse.ID, scope, permission)
+	}
+	return models.ErrAccessDenied
+}
+
+// AuthorizeToken checks a signed link token against the horse's records and
+// records the access in the audit trail.
+func (s *VetAccessServiceImpl) AuthorizeToken(ctx context.Context, token string, horseID uint, scope models.RecordScope, permission models.Permission) (*models.VetAccessGrant, error) {
+	if token == "" {
+		return nil, models.ErrAccessDenied
+	}
+
+	grant, err := s.repo.GetByTokenHash(ctx, hashAccessToken(token))
+	if err != nil {
+		return nil, models.ErrAccessDenied
+	}
+	horse, err := s.horseRepo.GetByID(ctx, horseID)
+	if err != nil {
+		return nil, models.ErrAccessDenied
+	}
+	if !grant.IsActive(timeNow()) || !s.permits(grant, horse, scope, permission) {
+		return nil, models.ErrAccessDenied
+	}
+	if err := s.checkSharingEnabled(ctx, grant.OwnerID); err != nil {
+		return nil, models.ErrAccessDenied
+	}
+
+	if err := s.recordAccess(fmt.Sprintf("link:%d", grant.ID), grant, horse.ID, scope, permission); err != nil {
+		return nil, err
+	}
+	return grant, nil
+}
+
+// permits checks a grant against the horse, record scope and requested action.
+// Grants lapse when the horse changes hands.
+func (s *VetAccessServiceImpl) permits(grant *models.VetAccessGrant, horse *models.Horse, scope models.RecordScope, permission models.Permission) bool {
+	return horse.UserID == grant.OwnerID &&
+		grant.CoversHorse(horse.ID) &&
+		grant.CoversScope(scope) &&
+		grant.Allows(permission)
+}
+
+func (s *VetAccessServiceImpl) validateGrant(grant *models.VetAccessGrant, horseIDs []uint) error {
+	if grant.OwnerID == "" {
+		return fmt.Errorf("%w: owner is required", models.ErrInvalidAccessGrant)
+	}
+	if grant.VetUserID != nil && *grant.VetUserID == grant.OwnerID {
+		return fmt.Errorf("%w: cannot grant access to yourself", models.ErrInvalidAccessGrant)
+	}
+	if len(horseIDs) == 0 {
+		return fmt.Errorf("%w: at least one horse is required", models.ErrInvalidAccessGrant)
+	}
+	if !grant.AccessLevel.IsValid() {
+		return fmt.Errorf("%w: unknown access level %q", models.ErrInvalidAccessGrant, grant.AccessLevel)
+	}
+	if !grant.IncludeHealth && !grant.IncludePregnancy {
+		return fmt.Errorf("%w: grant must include health or pregnancy records", models.ErrInvalidAccessGrant)
+	}
+
+	now := timeNow()
+	if !grant.ExpiresAt.After(now) {
+		return fmt.Errorf("%w: expiry must be in the future", models.ErrInvalidAccessGrant)
+	}
+	if grant.ExpiresAt.Sub(now) > MaxVetAccessDuration {
+		return fmt.Errorf("%w: access may not exceed %d days", models.ErrInvalidAccessGrant, int(MaxVetAccessDuration.Hours()/24))
+	}
+	return nil
+}
+
+// checkSharingEnabled enforces the owner's ShareWithVets privacy preference
+func (s *VetAccessServiceImpl) checkSharingEnabled(ctx context.Context, ownerID string) error {
+	prefs, err := s.privacy.GetPrivacyPreferences(ctx, ownerID)
+	if err != nil {
+		return fmt.Errorf("failed to get privacy preferences: %w", err)
+	}
+	if !prefs.HealthPrefs.ShareWithVets {
+		return models.ErrVetSharingDisabled
+	}
+	return nil
+}
+
+func (s *VetAccessServiceImpl) recordAccess(actor string, grant *models.VetAccessGrant, horseID uint, scope models.RecordScope, permission models.Permission) error {
+	return s.record(actor, "vet_access.used", grant, map[string]interface{}{
+		"horse_id":   horseID,
+		"scope":      scope,
+		"permission": permission,
+	})
+}
+
+// record writes an audit event. Access fails closed when the audit trail
+// cannot be written.
+func (s *VetAccessServiceImpl) record(actor, action string, grant *models.VetAccessGrant, extra map[string]interface{}) error {
+	if s.audit == nil {
+		return nil
+	}
+
+	details := map[string]interface{}{
+		"grant_id":     grant.ID,
+		"owner_id":     grant.OwnerID,
+		"vet_user_id":  grant.VetUserID,
+		"access_level": grant.AccessLevel,
+		"signed_link":  grant.TokenHash != nil,
+	}
+	for k, v := range extra {
+		details[k] = v
+	}
+
+	if err := s.audit.LogEvent(actor, action, details); err != nil {
+		return fmt.Errorf("failed to record audit event: %w", err)
+	}
+	return nil
+}
+
+func generateAccessToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func hashAccessToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
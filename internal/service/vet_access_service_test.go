@@ -0,0 +1,215 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/polyfant/hulta_pregnancy_app/internal/mocks"
+	"github.com/polyfant/hulta_pregnancy_app/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type stubPrivacy struct {
+	shareWithVets bool
+}
+
+func (s *stubPrivacy) GetPrivacyPreferences(ctx context.Context, userID string) (*models.PrivacyPreferences, error) {
+	prefs := models.GetDefaultPrivacyPreferences(userID)
+	prefs.HealthPrefs.ShareWithVets = s.shareWithVets
+	return &prefs, nil
+}
+
+type recordingAudit struct {
+	actions []string
+}
+
+func (a *recordingAudit) LogEvent(userID, action string, details map[string]interface{}) error {
+	a.actions = append(a.actions, action)
+	return nil
+}
+
+func TestVetAccessService_CreateGrant(t *testing.T) {
+	vet := "vet"
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	ctx := context.Background()
+
+	t.Run("sharing disabled", func(t *testing.T) {
+		repo := new(mocks.MockVetAccessRepository)
+		setTestNow(t, now)
+		svc := NewVetAccessService(repo, new(mocks.MockHorseRepository), &stubPrivacy{shareWithVets: false}, &recordingAudit{})
+
+		_, err := svc.CreateGrant(ctx, &models.VetAccessGrant{
+			OwnerID:       "owner",
+			VetUserID:     &vet,
+			AccessLevel:   models.AccessLevelRead,
+			IncludeHealth: true,
+			ExpiresAt:     now.Add(24 * time.Hour),
+		}, []uint{1})
+
+		assert.ErrorIs(t, err, models.ErrVetSharingDisabled)
+		repo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("expiry beyond maximum", func(t *testing.T) {
+		setTestNow(t, now)
+		svc := NewVetAccessService(new(mocks.MockVetAccessRepository), new(mocks.MockHorseRepository), &stubPrivacy{shareWithVets: true}, &recordingAudit{})
+
+		_, err := svc.CreateGrant(ctx, &models.VetAccessGrant{
+			OwnerID:       "owner",
+			VetUserID:     &vet,
+			AccessLevel:   models.AccessLevelRead,
+			IncludeHealth: true,
+			ExpiresAt:     now.Add(MaxVetAccessDuration + time.Hour),
+		}, []uint{1})
+
+		assert.ErrorIs(t, err, models.ErrInvalidAccessGrant)
+	})
+
+	t.Run("horse owned by someone else", func(t *testing.T) {
+		horseRepo := new(mocks.MockHorseRepository)
+		horseRepo.On("GetByID", ctx, uint(1)).Return(&models.Horse{ID: 1, UserID: "other"}, nil)
+		setTestNow(t, now)
+		svc := NewVetAccessService(new(mocks.MockVetAccessRepository), horseRepo, &stubPrivacy{shareWithVets: true}, &recordingAudit{})
+
+		_, err := svc.CreateGrant(ctx, &models.VetAccessGrant{
+			OwnerID:       "owner",
+			VetUserID:     &vet,
+			AccessLevel:   models.AccessLevelRead,
+			IncludeHealth: true,
+			ExpiresAt:     now.Add(24 * time.Hour),
+		}, []uint{1})
+
+		assert.ErrorIs(t, err, models.ErrAccessDenied)
+	})
+
+	t.Run("signed link stores only the token hash", func(t *testing.T) {
+		repo := new(mocks.MockVetAccessRepository)
+		horseRepo := new(mocks.MockHorseRepository)
+		audit := &recordingAudit{}
+		horseRepo.On("GetByID", ctx, uint(1)).Return(&models.Horse{ID: 1, UserID: "owner"}, nil)
+		repo.On("Create", ctx, mock.AnythingOfType("*models.VetAccessGrant")).Return(nil)
+		setTestNow(t, now)
+		svc := NewVetAccessService(repo, horseRepo, &stubPrivacy{shareWithVets: true}, audit)
+
+		grant := &models.VetAccessGrant{
+			OwnerID:          "owner",
+			AccessLevel:      models.AccessLevelRead,
+			IncludePregnancy: true,
+			ExpiresAt:        now.Add(24 * time.Hour),
+		}
+		token, err := svc.CreateGrant(ctx, grant, []uint{1})
+
+		require.NoError(t, err)
+		assert.NotEmpty(t, token)
+		require.NotNil(t, grant.TokenHash)
+		assert.Equal(t, hashAccessToken(token), *grant.TokenHash)
+		assert.NotEqual(t, token, *grant.TokenHash)
+		assert.Equal(t, []string{"vet_access.granted"}, audit.actions)
+	})
+}
+
+func TestVetAccessService_Authorize(t *testing.T) {
+	vet := "vet"
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	ctx := context.Background()
+	horse := &models.Horse{ID: 1, UserID: "owner"}
+
+	readGrant := models.VetAccessGrant{
+		ID:            3,
+		OwnerID:       "owner",
+		VetUserID:     &vet,
+		AccessLevel:   models.AccessLevelRead,
+		IncludeHealth: true,
+		Horses:        []models.VetAccessGrantHorse{{HorseID: 1}},
+		ExpiresAt:     now.Add(time.Hour),
+	}
+
+	tests := []struct {
+		name       string
+		grants     []models.VetAccessGrant
+		share      bool
+		scope      models.RecordScope
+		permission models.Permission
+		wantErr    bool
+	}{
+		{"read grant allows viewing health", []models.VetAccessGrant{readGrant}, true, models.RecordScopeHealth, models.PermissionView, false},
+		{"read grant denies logging", []models.VetAccessGrant{readGrant}, true, models.RecordScopeHealth, models.PermissionLog, true},
+		{"health grant denies pregnancy", []models.VetAccessGrant{readGrant}, true, models.RecordScopePregnancy, models.PermissionView, true},
+		{"no active grant", []models.VetAccessGrant{}, true, models.RecordScopeHealth, models.PermissionView, true},
+		{"owner turned off vet sharing", []models.VetAccessGrant{readGrant}, false, models.RecordScopeHealth, models.PermissionView, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(mocks.MockVetAccessRepository)
+			repo.On("ListActiveForVetAndHorse", ctx, "vet", uint(1), now).Return(tt.grants, nil)
+			audit := &recordingAudit{}
+			setTestNow(t, now)
+			svc := NewVetAccessService(repo, new(mocks.MockHorseRepository), &stubPrivacy{shareWithVets: tt.share}, audit)
+
+			err := svc.Authorize(ctx, "vet", horse, tt.scope, tt.permission)
+
+			if tt.wantErr {
+				assert.ErrorIs(t, err, models.ErrAccessDenied)
+				assert.Empty(t, audit.actions)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, []string{"vet_access.used"}, audit.actions)
+			}
+		})
+	}
+}
+
+func TestVetAccessService_AuthorizeToken(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	ctx := context.Background()
+	horse := &models.Horse{ID: 1, UserID: "owner"}
+	revokedAt := now.Add(-time.Minute)
+
+	active := &models.VetAccessGrant{
+		ID:               4,
+		OwnerID:          "owner",
+		AccessLevel:      models.AccessLevelReadWrite,
+		IncludePregnancy: true,
+		Horses:           []models.VetAccessGrantHorse{{HorseID: 1}},
+		ExpiresAt:        now.Add(time.Hour),
+	}
+	revoked := *active
+	revoked.RevokedAt = &revokedAt
+	expired := *active
+	expired.ExpiresAt = now.Add(-time.Hour)
+
+	tests := []struct {
+		name    string
+		grant   *models.VetAccessGrant
+		wantErr bool
+	}{
+		{"active link", active, false},
+		{"revoked link", &revoked, true},
+		{"expired link", &expired, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(mocks.MockVetAccessRepository)
+			repo.On("GetByTokenHash", ctx, hashAccessToken("secret")).Return(tt.grant, nil)
+			horseRepo := new(mocks.MockHorseRepository)
+			horseRepo.On("GetByID", ctx, horse.ID).Return(horse, nil)
+			setTestNow(t, now)
+			svc := NewVetAccessService(repo, horseRepo, &stubPrivacy{shareWithVets: true}, &recordingAudit{})
+
+			grant, err := svc.AuthorizeToken(ctx, "secret", horse.ID, models.RecordScopePregnancy, models.PermissionLog)
+
+			if tt.wantErr {
+				assert.ErrorIs(t, err, models.ErrAccessDenied)
+				assert.Nil(t, grant)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.grant.ID, grant.ID)
+			}
+		})
+	}
+}
@@ -0,0 +1,120 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/polyfant/hulta_pregnancy_app/internal/logger"
+	"github.com/polyfant/hulta_pregnancy_app/internal/models"
+	"github.com/polyfant/hulta_pregnancy_app/internal/repository"
+	"github.com/polyfant/hulta_pregnancy_app/internal/service/health"
+	"github.com/polyfant/hulta_pregnancy_app/internal/service/notification"
+)
+
+// Notifier delivers notifications to users; satisfied by *notification.Service
+type Notifier interface {
+	SendNotification(ctx context.Context, notification *notification.Notification) error
+}
+
+// VitalSignsServiceImpl records vital signs and raises alerts for abnormal values
+type VitalSignsServiceImpl struct {
+	repo     repository.VitalSignsRepository
+	notifier Notifier
+}
+
+func NewVitalSignsService(repo repository.VitalSignsRepository, notifier Notifier) VitalSignsService {
+	return &VitalSignsServiceImpl{
+		repo:     repo,
+		notifier: notifier,
+	}
+}
+
+// Record validates and evaluates the record against the ranges for the
+// horse's category, saves it, and notifies the owner when anything is abnormal
+func (s *VitalSignsServiceImpl) Record(ctx context.Context, horse *models.Horse, record *models.VitalSignsRecord) error {
+	if record.RecordedAt.IsZero() {
+		record.RecordedAt = timeNow()
+	}
+	if err := validateVitalSigns(record, timeNow()); err != nil {
+		return err
+	}
+
+	record.HorseID = horse.ID
+	record.Category = health.VitalSignsCategory(*horse, record.RecordedAt)
+	record.Findings = health.EvaluateVitalSigns(record.Category, *record)
+	record.Abnormal = len(record.Findings) > 0
+
+	if err := s.repo.Create(ctx, record); err != nil {
+		return fmt.Errorf("failed to save vital signs: %w", err)
+	}
+
+	if record.Abnormal {
+		s.notifyAbnormal(ctx, horse, record)
+	}
+	return nil
+}
+
+func (s *VitalSignsServiceImpl) List(ctx context.Context, horseID uint, since *time.Time) ([]models.VitalSignsRecord, error) {
+	records, err := s.repo.ListByHorse(ctx, horseID, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get vital signs: %w", err)
+	}
+	return records, nil
+}
+
+// notifyAbnormal sends a high-priority alert to the owner. The record is
+// already saved, so a delivery failure is logged rather than returned.
+func (s *VitalSignsServiceImpl) notifyAbnormal(ctx context.Context, horse *models.Horse, record *models.VitalSignsRecord) {
+	if s.notifier == nil {
+		return
+	}
+
+	details := make([]string, len(record.Findings))
+	for i, finding := range record.Findings {
+		details[i] = fmt.Sprintf("%s %s (normal %s)", strings.ReplaceAll(finding.Sign, "_", " "), finding.Value, finding.Expected)
+	}
+
+	alert := &notification.Notification{
+		Type:     notification.VitalSignsAlert,
+		UserID:   horse.UserID,
+		HorseID:  int64(horse.ID),
+		Title:    fmt.Sprintf("Abnormal vital signs for %s", horse.Name),
+		Message:  strings.Join(details, "; "),
+		Priority: notification.High,
+	}
+	if err := s.notifier.SendNotification(ctx, alert); err != nil {
+		logger.Warn("Failed to send vital signs alert", map[string]interface{}{"horseID": horse.ID, "error": err.Error()})
+	}
+}
+
+// validateVitalSigns rejects empty records, future timestamps and values no
+// living horse could have, which are almost always typing mistakes
+func validateVitalSigns(record *models.VitalSignsRecord, now time.Time) error {
+	if !record.HasMeasurements() {
+		return fmt.Errorf("%w: at least one vital sign is required", models.ErrInvalidVitalSigns)
+	}
+	if record.RecordedAt.After(now) {
+		return fmt.Errorf("%w: recorded_at cannot be in the future", models.ErrInvalidVitalSigns)
+	}
+	if t := record.Temperature; t != nil && (*t < 30 || *t > 45) {
+		return fmt.Errorf("%w: temperature %.1f°C is not plausible", models.ErrInvalidVitalSigns, *t)
+	}
+	if hr := record.HeartRate; hr != nil && (*hr < 10 || *hr > 250) {
+		return fmt.Errorf("%w: heart rate %d is not plausible", models.ErrInvalidVitalSigns, *hr)
+	}
+	if rr := record.RespirationRate; rr != nil && (*rr < 2 || *rr > 150) {
+		return fmt.Errorf("%w: respiration rate %d is not plausible", models.ErrInvalidVitalSigns, *rr)
+	}
+	if crt := record.CapillaryRefill; crt != nil && (*crt < 0 || *crt > 10) {
+		return fmt.Errorf("%w: capillary refill %.1fs is not plausible", models.ErrInvalidVitalSigns, *crt)
+	}
+	if record.GutSounds != "" && !record.GutSounds.IsValid() {
+		return fmt.Errorf("%w: unknown gut sounds %q", models.ErrInvalidVitalSigns, record.GutSounds)
+	}
+	if record.MucousMembrane != "" && !record.MucousMembrane.IsValid() {
+		return fmt.Errorf("%w: unknown mucous membrane colour %q", models.ErrInvalidVitalSigns, record.MucousMembrane)
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/polyfant/hulta_pregnancy_app/internal/mocks"
+	"github.com/polyfant/hulta_pregnancy_app/internal/models"
+	"github.com/polyfant/hulta_pregnancy_app/internal/service/notification"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type mockNotifier struct {
+	mock.Mock
+}
+
+func (m *mockNotifier) SendNotification(ctx context.Context, n *notification.Notification) error {
+	args := m.Called(ctx, n)
+	return args.Error(0)
+}
+
+func TestVitalSignsService_Record(t *testing.T) {
+	ctx := context.Background()
+	horse := &models.Horse{ID: 4, UserID: "owner", Name: "Bella", BirthDate: time.Now().AddDate(-6, 0, 0)}
+	intPtr := func(v int) *int { return &v }
+	floatPtr := func(v float64) *float64 { return &v }
+
+	tests := []struct {
+		name      string
+		record    models.VitalSignsRecord
+		wantErr   error
+		wantAlert bool
+		wantFinds int
+	}{
+		{
+			name:   "normal values are saved without alert",
+			record: models.VitalSignsRecord{Temperature: floatPtr(38.0), HeartRate: intPtr(36)},
+		},
+		{
+			name:      "abnormal values raise a high priority alert",
+			record:    models.VitalSignsRecord{Temperature: floatPtr(39.6), GutSounds: models.GutSoundsReduced},
+			wantAlert: true,
+			wantFinds: 2,
+		},
+		{
+			name:    "empty record",
+			record:  models.VitalSignsRecord{Notes: "looked fine"},
+			wantErr: models.ErrInvalidVitalSigns,
+		},
+		{
+			name:    "implausible temperature",
+			record:  models.VitalSignsRecord{Temperature: floatPtr(100.4)},
+			wantErr: models.ErrInvalidVitalSigns,
+		},
+		{
+			name:    "unknown membrane colour",
+			record:  models.VitalSignsRecord{MucousMembrane: "PURPLE"},
+			wantErr: models.ErrInvalidVitalSigns,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(mocks.MockVitalSignsRepository)
+			notifier := new(mockNotifier)
+			if tt.wantErr == nil {
+				repo.On("Create", ctx, mock.AnythingOfType("*models.VitalSignsRecord")).Return(nil)
+			}
+			if tt.wantAlert {
+				notifier.On("SendNotification", ctx, mock.MatchedBy(func(n *notification.Notification) bool {
+					return n.Priority == notification.High && n.UserID == "owner" && n.HorseID == 4
+				})).Return(nil)
+			}
+
+			svc := NewVitalSignsService(repo, notifier)
+			record := tt.record
+			err := svc.Record(ctx, horse, &record)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				repo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, "Adult", record.Category)
+			assert.Equal(t, tt.wantAlert, record.Abnormal)
+			assert.Len(t, record.Findings, tt.wantFinds)
+			assert.False(t, record.RecordedAt.IsZero())
+			repo.AssertExpectations(t)
+			notifier.AssertExpectations(t)
+			if !tt.wantAlert {
+				notifier.AssertNotCalled(t, "SendNotification", mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
@@ -0,0 +1,200 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/polyfant/hulta_pregnancy_app/internal/models"
+	"github.com/polyfant/hulta_pregnancy_app/internal/repository"
+	"github.com/polyfant/hulta_pregnancy_app/internal/service/growth"
+	"gorm.io/gorm"
+)
+
+// WeaningServiceImpl plans weaning from the foal's age and growth and the
+// dam's new pregnancy, and watches the foal through the month after
+type WeaningServiceImpl struct {
+	repo          repository.WeaningRepository
+	growthRepo    repository.GrowthRepository
+	pregnancyRepo repository.PregnancyRepository
+	curves        *growth.ReferenceCurves
+}
+
+func NewWeaningService(
+	repo repository.WeaningRepository,
+	growthRepo repository.GrowthRepository,
+	pregnancyRepo repository.PregnancyRepository,
+	curves *growth.ReferenceCurves,
+) WeaningService {
+	return &WeaningServiceImpl{
+		repo:          repo,
+		growthRepo:    growthRepo,
+		pregnancyRepo: pregnancyRepo,
+		curves:        curves,
+	}
+}
+
+// Plan proposes a weaning window for a foal that has not been weaned yet
+func (s *WeaningServiceImpl) Plan(ctx context.Context, horse *models.Horse) (*models.WeaningPlan, error) {
+	if horse.BirthDate.IsZero() {
+		return nil, fmt.Errorf("%w: the foal has no birth date", models.ErrInvalidWeaning)
+	}
+	record, err := s.repo.GetByHorse(ctx, horse.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get weaning record: %w", err)
+	}
+	if record != nil {
+		return nil, fmt.Errorf("%w: the foal was weaned on %s", models.ErrInvalidWeaning, record.Date.Format("2006-01-02"))
+	}
+
+	growthData, err := s.growthRepo.GetGrowthDataByFoalID(ctx, horse.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get growth data: %w", err)
+	}
+	now := timeNow()
+	gestation, err := s.damGestationDays(ctx, horse, now)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := growth.PlanWeaning(s.reference(horse), horse, growthData, gestation, now)
+	return &plan, nil
+}
+
+// damGestationDays is how far along the dam's current pregnancy is, or nil
+// when the foal has no recorded dam or she is not in foal
+func (s *WeaningServiceImpl) damGestationDays(ctx context.Context, horse *models.Horse, now time.Time) (*int, error) {
+	damID := horse.DamID
+	if damID == nil {
+		damID = horse.MotherId
+	}
+	if damID == nil {
+		return nil, nil
+	}
+
+	pregnancy, err := s.pregnancyRepo.GetCurrentPregnancy(ctx, *damID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get dam's pregnancy: %w", err)
+	}
+	if pregnancy == nil || !pregnancy.IsActive() {
+		return nil, nil
+	}
+
+	conception := pregnancy.StartDate
+	if pregnancy.ConceptionDate != nil {
+		conception = *pregnancy.ConceptionDate
+	}
+	days := growth.AgeInDays(conception, now)
+	return &days, nil
+}
+
+// Record saves when and how the foal was weaned, replacing any earlier record
+func (s *WeaningServiceImpl) Record(ctx context.Context, horse *models.Horse, record *models.WeaningRecord) error {
+	now := timeNow()
+	if record.Date.IsZero() {
+		record.Date = now
+	}
+	if err := validateWeaningRecord(horse, record, now); err != nil {
+		return err
+	}
+
+	existing, err := s.repo.GetByHorse(ctx, horse.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get weaning record: %w", err)
+	}
+	record.ID = 0
+	if existing != nil {
+		record.ID = existing.ID
+		record.CreatedAt = existing.CreatedAt
+	}
+	record.HorseID = horse.ID
+
+	if err := s.repo.Save(ctx, record); err != nil {
+		return fmt.Errorf("failed to save weaning record: %w", err)
+	}
+	return nil
+}
+
+// RecordCheck saves the stress signs seen on a look at the weaned foal
+func (s *WeaningServiceImpl) RecordCheck(ctx context.Context, horse *models.Horse, check *models.WeaningCheck) error {
+	record, err := s.repo.GetByHorse(ctx, horse.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get weaning record: %w", err)
+	}
+	if record == nil {
+		return models.ErrWeaningNotFound
+	}
+
+	now := timeNow()
+	if check.CheckedAt.IsZero() {
+		check.CheckedAt = now
+	}
+	if check.CheckedAt.After(now) || check.CheckedAt.Before(record.Date) {
+		return fmt.Errorf("%w: checked_at must be between weaning and now", models.ErrInvalidWeaning)
+	}
+	for _, sign := range check.Signs {
+		if !sign.IsValid() {
+			return fmt.Errorf("%w: unknown stress sign %q", models.ErrInvalidWeaning, sign)
+		}
+	}
+	if check.Signs == nil {
+		check.Signs = []models.WeaningStressSign{}
+	}
+	check.HorseID = horse.ID
+
+	if err := s.repo.CreateCheck(ctx, check); err != nil {
+		return fmt.Errorf("failed to save weaning check: %w", err)
+	}
+	return nil
+}
+
+// Progress follows the foal's weight and stress since weaning
+func (s *WeaningServiceImpl) Progress(ctx context.Context, horse *models.Horse) (*models.WeaningProgress, error) {
+	record, err := s.repo.GetByHorse(ctx, horse.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get weaning record: %w", err)
+	}
+	if record == nil {
+		return nil, models.ErrWeaningNotFound
+	}
+	growthData, err := s.growthRepo.GetGrowthDataByFoalID(ctx, horse.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get growth data: %w", err)
+	}
+	checks, err := s.repo.ListChecks(ctx, horse.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get weaning checks: %w", err)
+	}
+
+	reference := s.reference(horse)
+	for i := range growthData {
+		growth.Place(reference, &growthData[i])
+	}
+	progress := growth.MonitorWeaning(reference, record, growthData, checks, timeNow())
+	return &progress, nil
+}
+
+func (s *WeaningServiceImpl) reference(horse *models.Horse) *growth.Reference {
+	if s.curves == nil {
+		return nil
+	}
+	return s.curves.ForBreed(horse.Breed)
+}
+
+func validateWeaningRecord(horse *models.Horse, record *models.WeaningRecord, now time.Time) error {
+	switch {
+	case !record.Method.IsValid():
+		return fmt.Errorf("%w: method must be ABRUPT, GRADUAL, FENCE_LINE or INTERVAL", models.ErrInvalidWeaning)
+	case record.Date.After(now):
+		return fmt.Errorf("%w: date cannot be in the future", models.ErrInvalidWeaning)
+	case !horse.BirthDate.IsZero() && !record.Date.After(horse.BirthDate):
+		return fmt.Errorf("%w: date must be after the foal was born", models.ErrInvalidWeaning)
+	case len(record.Group) > 100:
+		return fmt.Errorf("%w: group name is too long", models.ErrInvalidWeaning)
+	}
+	return nil
+}
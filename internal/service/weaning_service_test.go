@@ -0,0 +1,223 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/polyfant/hulta_pregnancy_app/internal/mocks"
+	"github.com/polyfant/hulta_pregnancy_app/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestWeaningService_Plan(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 9, 1, 12, 0, 0, 0, time.UTC)
+	damID := uint(2)
+	foal := &models.Horse{ID: 5, BirthDate: now.AddDate(0, 0, -150), DamID: &damID}
+
+	t.Run("dam in foal", func(t *testing.T) {
+		setTestNow(t, now)
+		repo := new(mocks.MockWeaningRepository)
+		growthRepo := new(mocks.MockGrowthRepository)
+		pregnancyRepo := new(mocks.MockPregnancyRepository)
+		svc := NewWeaningService(repo, growthRepo, pregnancyRepo, testGrowthCurves())
+		conception := now.AddDate(0, 0, -180)
+		repo.On("GetByHorse", ctx, uint(5)).Return(nil, nil)
+		growthRepo.On("GetGrowthDataByFoalID", ctx, uint(5)).Return([]models.GrowthData{}, nil)
+		pregnancyRepo.On("GetCurrentPregnancy", ctx, damID).Return(&models.Pregnancy{
+			Status:         models.PregnancyStatusActive,
+			StartDate:      conception.AddDate(0, 0, -5),
+			ConceptionDate: &conception,
+		}, nil)
+
+		plan, err := svc.Plan(ctx, foal)
+
+		require.NoError(t, err)
+		assert.Equal(t, 180, *plan.DamGestationDays)
+		assert.Equal(t, models.PregnancyStageMid, plan.DamStage)
+		assert.Equal(t, foal.BirthDate.AddDate(0, 0, 195), plan.Latest)
+		assert.Equal(t, models.WeaningReady, plan.Status)
+	})
+
+	t.Run("dam not in foal", func(t *testing.T) {
+		setTestNow(t, now)
+		repo := new(mocks.MockWeaningRepository)
+		growthRepo := new(mocks.MockGrowthRepository)
+		pregnancyRepo := new(mocks.MockPregnancyRepository)
+		svc := NewWeaningService(repo, growthRepo, pregnancyRepo, testGrowthCurves())
+		repo.On("GetByHorse", ctx, uint(5)).Return(nil, nil)
+		growthRepo.On("GetGrowthDataByFoalID", ctx, uint(5)).Return([]models.GrowthData{}, nil)
+		pregnancyRepo.On("GetCurrentPregnancy", ctx, damID).Return(nil, gorm.ErrRecordNotFound)
+
+		plan, err := svc.Plan(ctx, foal)
+
+		require.NoError(t, err)
+		assert.Nil(t, plan.DamGestationDays)
+		assert.Equal(t, foal.BirthDate.AddDate(0, 0, 240), plan.Latest)
+	})
+
+	t.Run("pregnancy lookup fails", func(t *testing.T) {
+		setTestNow(t, now)
+		repo := new(mocks.MockWeaningRepository)
+		growthRepo := new(mocks.MockGrowthRepository)
+		pregnancyRepo := new(mocks.MockPregnancyRepository)
+		svc := NewWeaningService(repo, growthRepo, pregnancyRepo, testGrowthCurves())
+		repo.On("GetByHorse", ctx, uint(5)).Return(nil, nil)
+		growthRepo.On("GetGrowthDataByFoalID", ctx, uint(5)).Return([]models.GrowthData{}, nil)
+		pregnancyRepo.On("GetCurrentPregnancy", ctx, damID).Return(nil, errors.New("connection refused"))
+
+		_, err := svc.Plan(ctx, foal)
+		assert.Error(t, err)
+	})
+
+	t.Run("already weaned", func(t *testing.T) {
+		setTestNow(t, now)
+		repo := new(mocks.MockWeaningRepository)
+		svc := NewWeaningService(repo, new(mocks.MockGrowthRepository), new(mocks.MockPregnancyRepository), testGrowthCurves())
+		repo.On("GetByHorse", ctx, uint(5)).Return(&models.WeaningRecord{Date: now.AddDate(0, 0, -3)}, nil)
+
+		_, err := svc.Plan(ctx, foal)
+		assert.ErrorIs(t, err, models.ErrInvalidWeaning)
+	})
+
+	t.Run("no birth date", func(t *testing.T) {
+		setTestNow(t, now)
+		svc := NewWeaningService(new(mocks.MockWeaningRepository), new(mocks.MockGrowthRepository), new(mocks.MockPregnancyRepository), testGrowthCurves())
+		_, err := svc.Plan(ctx, &models.Horse{ID: 5})
+		assert.ErrorIs(t, err, models.ErrInvalidWeaning)
+	})
+}
+
+func TestWeaningService_Record(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 9, 1, 12, 0, 0, 0, time.UTC)
+	foal := &models.Horse{ID: 5, BirthDate: now.AddDate(0, 0, -180)}
+
+	tests := []struct {
+		name    string
+		record  models.WeaningRecord
+		wantErr bool
+	}{
+		{name: "valid", record: models.WeaningRecord{Method: models.WeaningFenceLine, Group: "2024 colts"}},
+		{name: "unknown method", record: models.WeaningRecord{Method: "SUDDEN"}, wantErr: true},
+		{name: "in the future", record: models.WeaningRecord{Method: models.WeaningAbrupt, Date: now.AddDate(0, 0, 1)}, wantErr: true},
+		{name: "before birth", record: models.WeaningRecord{Method: models.WeaningAbrupt, Date: foal.BirthDate.AddDate(0, 0, -1)}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setTestNow(t, now)
+			repo := new(mocks.MockWeaningRepository)
+			svc := NewWeaningService(repo, new(mocks.MockGrowthRepository), new(mocks.MockPregnancyRepository), testGrowthCurves())
+			repo.On("GetByHorse", ctx, uint(5)).Return(nil, nil)
+			repo.On("Save", ctx, mock.AnythingOfType("*models.WeaningRecord")).Return(nil)
+
+			record := tt.record
+			err := svc.Record(ctx, foal, &record)
+
+			if tt.wantErr {
+				assert.ErrorIs(t, err, models.ErrInvalidWeaning)
+				repo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, uint(5), record.HorseID)
+			assert.Equal(t, now, record.Date)
+		})
+	}
+
+	t.Run("replaces the earlier record", func(t *testing.T) {
+		setTestNow(t, now)
+		repo := new(mocks.MockWeaningRepository)
+		svc := NewWeaningService(repo, new(mocks.MockGrowthRepository), new(mocks.MockPregnancyRepository), testGrowthCurves())
+		repo.On("GetByHorse", ctx, uint(5)).Return(&models.WeaningRecord{ID: 4, HorseID: 5}, nil)
+		repo.On("Save", ctx, mock.AnythingOfType("*models.WeaningRecord")).Return(nil)
+
+		record := models.WeaningRecord{Method: models.WeaningGradual}
+		require.NoError(t, svc.Record(ctx, foal, &record))
+		assert.Equal(t, uint(4), record.ID)
+	})
+}
+
+func TestWeaningService_RecordCheck(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 9, 1, 12, 0, 0, 0, time.UTC)
+	foal := &models.Horse{ID: 5}
+	weaning := &models.WeaningRecord{ID: 4, HorseID: 5, Date: now.AddDate(0, 0, -3)}
+
+	t.Run("saves the signs", func(t *testing.T) {
+		setTestNow(t, now)
+		repo := new(mocks.MockWeaningRepository)
+		svc := NewWeaningService(repo, new(mocks.MockGrowthRepository), new(mocks.MockPregnancyRepository), testGrowthCurves())
+		repo.On("GetByHorse", ctx, uint(5)).Return(weaning, nil)
+		repo.On("CreateCheck", ctx, mock.AnythingOfType("*models.WeaningCheck")).Return(nil)
+
+		check := models.WeaningCheck{Signs: []models.WeaningStressSign{models.WeaningPacing}}
+		require.NoError(t, svc.RecordCheck(ctx, foal, &check))
+		assert.Equal(t, uint(5), check.HorseID)
+		assert.Equal(t, now, check.CheckedAt)
+	})
+
+	t.Run("unknown sign", func(t *testing.T) {
+		setTestNow(t, now)
+		repo := new(mocks.MockWeaningRepository)
+		svc := NewWeaningService(repo, new(mocks.MockGrowthRepository), new(mocks.MockPregnancyRepository), testGrowthCurves())
+		repo.On("GetByHorse", ctx, uint(5)).Return(weaning, nil)
+
+		check := models.WeaningCheck{Signs: []models.WeaningStressSign{"SULKING"}}
+		assert.ErrorIs(t, svc.RecordCheck(ctx, foal, &check), models.ErrInvalidWeaning)
+	})
+
+	t.Run("before weaning", func(t *testing.T) {
+		setTestNow(t, now)
+		repo := new(mocks.MockWeaningRepository)
+		svc := NewWeaningService(repo, new(mocks.MockGrowthRepository), new(mocks.MockPregnancyRepository), testGrowthCurves())
+		repo.On("GetByHorse", ctx, uint(5)).Return(weaning, nil)
+
+		check := models.WeaningCheck{CheckedAt: now.AddDate(0, 0, -4)}
+		assert.ErrorIs(t, svc.RecordCheck(ctx, foal, &check), models.ErrInvalidWeaning)
+	})
+
+	t.Run("not weaned", func(t *testing.T) {
+		setTestNow(t, now)
+		repo := new(mocks.MockWeaningRepository)
+		svc := NewWeaningService(repo, new(mocks.MockGrowthRepository), new(mocks.MockPregnancyRepository), testGrowthCurves())
+		repo.On("GetByHorse", ctx, uint(5)).Return(nil, nil)
+
+		assert.ErrorIs(t, svc.RecordCheck(ctx, foal, &models.WeaningCheck{}), models.ErrWeaningNotFound)
+	})
+}
+
+func TestWeaningService_Progress(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 9, 1, 12, 0, 0, 0, time.UTC)
+	birth := now.AddDate(0, 0, -110)
+	weaned := now.AddDate(0, 0, -20)
+	foal := &models.Horse{ID: 5, BirthDate: birth}
+
+	setTestNow(t, now)
+	repo := new(mocks.MockWeaningRepository)
+	growthRepo := new(mocks.MockGrowthRepository)
+	svc := NewWeaningService(repo, growthRepo, new(mocks.MockPregnancyRepository), testGrowthCurves())
+	repo.On("GetByHorse", ctx, uint(5)).Return(&models.WeaningRecord{HorseID: 5, Date: weaned, Method: models.WeaningAbrupt}, nil)
+	growthRepo.On("GetGrowthDataByFoalID", ctx, uint(5)).Return([]models.GrowthData{
+		{AgeDays: 89, Weight: 139, MeasurementDate: birth.AddDate(0, 0, 89)},
+		{AgeDays: 100, Weight: 150, MeasurementDate: birth.AddDate(0, 0, 100)},
+	}, nil)
+	repo.On("ListChecks", ctx, uint(5)).Return([]models.WeaningCheck{}, nil)
+
+	progress, err := svc.Progress(ctx, foal)
+
+	require.NoError(t, err)
+	assert.Equal(t, 20, progress.DaysSinceWeaning)
+	assert.Equal(t, 11.0, *progress.WeightChangeKg)
+	assert.Equal(t, 1.0, *progress.DailyGainKg)
+	require.Len(t, progress.Measurements, 2)
+	assert.Equal(t, 150.0, progress.Measurements[1].ExpectedWeight, "measurements are placed on the breed curve")
+	assert.Empty(t, progress.Alerts)
+}
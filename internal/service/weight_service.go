@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/polyfant/hulta_pregnancy_app/internal/models"
+	"github.com/polyfant/hulta_pregnancy_app/internal/repository"
+	"github.com/polyfant/hulta_pregnancy_app/internal/service/health"
+)
+
+const (
+	minWeightKg = 20
+	maxWeightKg = 1500
+)
+
+// WeightServiceImpl records weighings and estimates weight from tape measurements
+type WeightServiceImpl struct {
+	repo repository.WeightRepository
+}
+
+func NewWeightService(repo repository.WeightRepository) WeightService {
+	return &WeightServiceImpl{
+		repo: repo,
+	}
+}
+
+// Record saves a weighing. A record with heart girth and body length is a
+// tape estimate: its weight is calculated with the formula given, or the
+// one for the horse's age and type.
+func (s *WeightServiceImpl) Record(ctx context.Context, horse *models.Horse, record *models.WeightRecord) error {
+	now := timeNow()
+	if record.Date.IsZero() {
+		record.Date = now
+	}
+	if record.Date.After(now) {
+		return fmt.Errorf("%w: date cannot be in the future", models.ErrInvalidWeight)
+	}
+
+	if record.HeartGirthCm > 0 || record.BodyLengthCm > 0 {
+		if record.Method != "" && record.Method != models.WeightMethodTape {
+			return fmt.Errorf("%w: measurements are only used by the %s method", models.ErrInvalidWeight, models.WeightMethodTape)
+		}
+		if record.Formula == "" {
+			record.Formula = health.WeightFormulaFor(*horse, record.Date)
+		}
+		weight, err := health.EstimateWeight(record.HeartGirthCm, record.BodyLengthCm, record.Formula)
+		if err != nil {
+			return fmt.Errorf("%w: %v", models.ErrInvalidWeight, err)
+		}
+		record.Method = models.WeightMethodTape
+		record.WeightKg = weight
+	} else {
+		if record.Method == "" {
+			record.Method = models.WeightMethodScale
+		}
+		if !record.Method.IsValid() || record.Method == models.WeightMethodTape {
+			return fmt.Errorf("%w: method %q needs heart_girth_cm and body_length_cm", models.ErrInvalidWeight, record.Method)
+		}
+		record.Formula = ""
+	}
+
+	if record.WeightKg < minWeightKg || record.WeightKg > maxWeightKg {
+		return fmt.Errorf("%w: weight must be between %d and %d kg, got %.1f", models.ErrInvalidWeight, minWeightKg, maxWeightKg, record.WeightKg)
+	}
+
+	record.HorseID = horse.ID
+	if err := s.repo.Create(ctx, record); err != nil {
+		return fmt.Errorf("failed to save weight: %w", err)
+	}
+	return nil
+}
+
+func (s *WeightServiceImpl) History(ctx context.Context, horse *models.Horse) (*models.WeightHistory, error) {
+	records, err := s.repo.ListByHorse(ctx, horse.ID, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get weight history: %w", err)
+	}
+
+	history := &models.WeightHistory{HorseID: horse.ID, Records: records}
+	if len(records) > 0 {
+		history.Latest = &records[len(records)-1]
+	}
+	return history, nil
+}
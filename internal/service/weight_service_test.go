@@ -0,0 +1,88 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/polyfant/hulta_pregnancy_app/internal/mocks"
+	"github.com/polyfant/hulta_pregnancy_app/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWeightService_Record(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	horse := &models.Horse{ID: 7, BirthDate: now.AddDate(-10, 0, 0), Height: 165}
+
+	tests := []struct {
+		name        string
+		record      models.WeightRecord
+		wantWeight  float64
+		wantMethod  models.WeightMethod
+		wantFormula models.WeightFormula
+		wantErr     error
+	}{
+		{name: "scale weighing", record: models.WeightRecord{WeightKg: 512}, wantWeight: 512, wantMethod: models.WeightMethodScale},
+		{name: "visual estimate", record: models.WeightRecord{WeightKg: 480, Method: models.WeightMethodVisual}, wantWeight: 480, wantMethod: models.WeightMethodVisual},
+		{
+			name:        "tape estimate picks the formula",
+			record:      models.WeightRecord{HeartGirthCm: 190, BodyLengthCm: 165},
+			wantWeight:  501,
+			wantMethod:  models.WeightMethodTape,
+			wantFormula: models.WeightFormulaAdult,
+		},
+		{
+			name:        "tape estimate with formula given",
+			record:      models.WeightRecord{HeartGirthCm: 150, BodyLengthCm: 130, Formula: models.WeightFormulaPony, WeightKg: 999},
+			wantWeight:  271,
+			wantMethod:  models.WeightMethodTape,
+			wantFormula: models.WeightFormulaPony,
+		},
+		{name: "tape without length", record: models.WeightRecord{HeartGirthCm: 190}, wantErr: models.ErrInvalidWeight},
+		{name: "tape method without measurements", record: models.WeightRecord{WeightKg: 500, Method: models.WeightMethodTape}, wantErr: models.ErrInvalidWeight},
+		{name: "measurements with scale method", record: models.WeightRecord{HeartGirthCm: 190, BodyLengthCm: 165, Method: models.WeightMethodScale}, wantErr: models.ErrInvalidWeight},
+		{name: "implausible weight", record: models.WeightRecord{WeightKg: 5000}, wantErr: models.ErrInvalidWeight},
+		{name: "future date", record: models.WeightRecord{WeightKg: 500, Date: now.Add(time.Hour)}, wantErr: models.ErrInvalidWeight},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(mocks.MockWeightRepository)
+			repo.On("Create", ctx, mock.AnythingOfType("*models.WeightRecord")).Return(nil)
+			svc := NewWeightService(repo).(*WeightServiceImpl)
+			setTestNow(t, now)
+
+			record := tt.record
+			err := svc.Record(ctx, horse, &record)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				repo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, horse.ID, record.HorseID)
+			assert.Equal(t, now, record.Date)
+			assert.Equal(t, tt.wantWeight, record.WeightKg)
+			assert.Equal(t, tt.wantMethod, record.Method)
+			assert.Equal(t, tt.wantFormula, record.Formula)
+		})
+	}
+}
+
+func TestWeightService_History(t *testing.T) {
+	ctx := context.Background()
+	repo := new(mocks.MockWeightRepository)
+	records := []models.WeightRecord{{ID: 1, WeightKg: 480}, {ID: 2, WeightKg: 495}}
+	repo.On("ListByHorse", ctx, uint(7), (*time.Time)(nil)).Return(records, nil)
+
+	history, err := NewWeightService(repo).History(ctx, &models.Horse{ID: 7})
+
+	require.NoError(t, err)
+	assert.Len(t, history.Records, 2)
+	require.NotNil(t, history.Latest)
+	assert.Equal(t, uint(2), history.Latest.ID)
+}
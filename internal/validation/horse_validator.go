@@ -13,10 +13,12 @@ type HorseValidator struct {
 	maxAge time.Duration
 }
 
-// NewHorseValidator creates a new horse validator with default age limits
+// NewHorseValidator creates a new horse validator with default age limits.
+// Horses of any age up to the maximum are valid, foals included; the minimum
+// age applies to pregnant mares only.
 func NewHorseValidator() *HorseValidator {
 	return &HorseValidator{
-		minAge: 2 * 365 * 24 * time.Hour,  // 2 years
+		minAge: 2 * 365 * 24 * time.Hour,  // 2 years, to be in foal
 		maxAge: 25 * 365 * 24 * time.Hour, // 25 years
 	}
 }
@@ -51,8 +53,8 @@ func (v *HorseValidator) validateAge(horse *models.Horse) error {
 	}
 
 	age := time.Since(horse.BirthDate)
-	if age < v.minAge {
-		return fmt.Errorf("horse is too young (minimum age: 2 years)")
+	if age < 0 {
+		return fmt.Errorf("birth date cannot be in the future")
 	}
 	if age > v.maxAge {
 		return fmt.Errorf("horse age exceeds maximum (maximum age: 25 years)")
@@ -86,5 +88,9 @@ func (v *HorseValidator) validateBreedingEligibility(horse *models.Horse) error
 		return fmt.Errorf("conception date is required for pregnant horse")
 	}
 
+	if horse.ConceptionDate.Sub(horse.BirthDate) < v.minAge {
+		return fmt.Errorf("mare is too young to be pregnant (minimum age: 2 years)")
+	}
+
 	return nil
 }
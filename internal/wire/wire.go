@@ -7,6 +7,8 @@ import (
 	"github.com/polyfant/hulta_pregnancy_app/internal/config"
 	"github.com/polyfant/hulta_pregnancy_app/internal/repository"
 	"github.com/polyfant/hulta_pregnancy_app/internal/service"
+	"github.com/polyfant/hulta_pregnancy_app/internal/service/growth"
+	"github.com/polyfant/hulta_pregnancy_app/internal/service/storage"
 	"gorm.io/gorm"
 )
 
@@ -19,6 +21,23 @@ func ProvideHandlerConfig(
 	healthService service.HealthService,
 	breedingService service.BreedingService,
 	growthService service.GrowthService,
+	orgService service.OrganizationService,
+	vetAccessService service.VetAccessService,
+	mediaService service.MediaService,
+	vitalSignsService service.VitalSignsService,
+	healthScheduleService service.HealthScheduleService,
+	eggCountService service.FecalEggCountService,
+	symptomChecker service.SymptomCheckerService,
+	treatmentService service.TreatmentService,
+	farrierService service.FarrierService,
+	bodyConditionService service.BodyConditionService,
+	nutritionService service.NutritionService,
+	weightService service.WeightService,
+	neonatalService service.NeonatalService,
+	weaningService service.WeaningService,
+	feedService service.FeedService,
+	pastureService service.PastureService,
+	localFiles *storage.LocalBlobStore,
 	cacheService cache.Cache,
 	horseRepo repository.HorseRepository,
 	breedingRepo repository.BreedingRepository,
@@ -33,6 +52,23 @@ func ProvideHandlerConfig(
 		HealthService:    healthService,
 		BreedingService:  breedingService,
 		GrowthService:    growthService,
+		OrgService:       orgService,
+		VetAccessService: vetAccessService,
+		MediaService:     mediaService,
+		VitalSigns:       vitalSignsService,
+		HealthSchedule:   healthScheduleService,
+		EggCounts:        eggCountService,
+		SymptomChecker:   symptomChecker,
+		Treatments:       treatmentService,
+		Farrier:          farrierService,
+		BodyConditions:   bodyConditionService,
+		Nutrition:        nutritionService,
+		Weights:          weightService,
+		Neonatal:         neonatalService,
+		Weaning:          weaningService,
+		Feed:             feedService,
+		Pastures:         pastureService,
+		LocalFiles:       localFiles,
 		Cache:            cacheService,
 		HorseRepo:        horseRepo,
 		BreedingRepo:     breedingRepo,
@@ -45,14 +81,23 @@ func ProvideHandlerConfig(
 func ProvideGrowthService(
 	growthRepo repository.GrowthRepository,
 	horseRepo repository.HorseRepository,
+	curves *growth.ReferenceCurves,
+	notifier service.Notifier,
 ) service.GrowthService {
-	return service.NewGrowthService(growthRepo, horseRepo)
+	return service.NewGrowthService(growthRepo, horseRepo, curves, notifier)
+}
+
+// ProvidePastureService sets up the pasture and turnout service
+func ProvidePastureService(pastureRepo repository.PastureRepository) service.PastureService {
+	return service.NewPastureService(pastureRepo)
 }
 
 // WireSet for API dependencies
 var WireSet = wire.NewSet(
 	ProvideHandlerConfig,
 	ProvideGrowthService,
+	ProvidePastureService,
+	repository.NewPastureRepository,
 	api.NewHandler,
 	api.NewGrowthHandler,
 )
@@ -11,7 +11,7 @@ import (
 )
 
 func TestHealthService(t *testing.T) {
-	handler, _, _, _, mockHealthRepo, _ := setupTestHandler()
+	handler, mockHorse, _, _, mockHealthRepo, _ := setupTestHandler()
 	ctx := setupTestContext(t)
 
 	t.Run("AddHealthRecord", func(t *testing.T) {
@@ -23,12 +23,16 @@ func TestHealthService(t *testing.T) {
 			Date:        time.Now(),
 		}
 
+		mockHorse.On("GetByID", mock.Anything, horseID).
+			Return(&models.Horse{ID: horseID, UserID: "test_user"}, nil).Once()
+
 		mockHealthRepo.On("CreateRecord", mock.Anything, record).
 			Return(nil).Once()
 
-		err := handler.GetHealthService().CreateRecord(ctx, record)
+		err := handler.GetHealthService().CreateRecord(ctx, "test_user", record)
 		assert.NoError(t, err)
 
 		mockHealthRepo.AssertExpectations(t)
+		mockHorse.AssertExpectations(t)
 	})
 }
\ No newline at end of file
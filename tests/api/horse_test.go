@@ -17,9 +17,9 @@ func TestHorseService(t *testing.T) {
 		horseID := uint(1)
 
 		mockHorseRepo.On("GetByID", mock.Anything, horseID).
-			Return(&models.Horse{ID: horseID}, nil).Once()
+			Return(&models.Horse{ID: horseID, UserID: "test_user"}, nil).Once()
 
-		horse, err := handler.GetHorseService().GetByID(ctx, horseID)
+		horse, err := handler.GetHorseService().GetByID(ctx, "test_user", horseID, models.PermissionView)
 		assert.NoError(t, err)
 		assert.Equal(t, horseID, horse.ID)
 
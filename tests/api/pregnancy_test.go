@@ -25,7 +25,7 @@ func TestPregnancyService(t *testing.T) {
 		})).Return(nil).Once()
 
 		mockHorse.On("GetByID", mock.Anything, horseID).
-			Return(&models.Horse{ID: horseID}, nil).Once()
+			Return(&models.Horse{ID: horseID, UserID: "test_user"}, nil).Twice()
 
 		mockHorse.On("Update", mock.Anything, mock.MatchedBy(func(h *models.Horse) bool {
 			return h.ID == horseID && h.IsPregnant
@@ -34,10 +34,10 @@ func TestPregnancyService(t *testing.T) {
 		mockPregnancyRepo.On("GetByHorseID", mock.Anything, horseID).
 			Return(&models.Pregnancy{HorseID: horseID}, nil).Once()
 
-		err := handler.GetPregnancyService().StartTracking(ctx, horseID, start)
+		err := handler.GetPregnancyService().StartTracking(ctx, "test_user", horseID, start)
 		assert.NoError(t, err)
 
-		pregnancy, err := handler.GetPregnancyService().GetPregnancy(ctx, horseID)
+		pregnancy, err := handler.GetPregnancyService().GetPregnancy(ctx, "test_user", horseID)
 		assert.NoError(t, err)
 		assert.Equal(t, horseID, pregnancy.HorseID)
 
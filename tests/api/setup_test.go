@@ -19,13 +19,14 @@ func setupTestHandler() (*api.Handler, *mocks.MockHorseRepository, *mocks.MockUs
 	mockPregnancyRepo := new(mocks.PregnancyRepository)
 	mockHealthRepo := new(mocks.MockHealthRepository)
 	mockBreedingRepo := new(mocks.MockBreedingRepository)
+	mockOrgRepo := new(mocks.MockOrganizationRepository)
 
 	// Initialize services with mock repositories
 	userService := service.NewUserService(mockUserRepo)
-	pregnancyService := service.NewPregnancyService(mockHorseRepo, mockPregnancyRepo)
-	healthService := service.NewHealthService(mockHealthRepo)
+	horseService := service.NewHorseService(mockHorseRepo, mockOrgRepo, nil)
+	pregnancyService := service.NewPregnancyService(mockHorseRepo, mockPregnancyRepo, horseService)
+	healthService := service.NewHealthService(mockHealthRepo, horseService)
 	breedingService := breeding.NewBreedingService(mockBreedingRepo)
-	horseService := service.NewHorseService(mockHorseRepo)
 	// Initialize cache
 	cache := cache.NewMemoryCache()
 
@@ -36,6 +37,7 @@ func setupTestHandler() (*api.Handler, *mocks.MockHorseRepository, *mocks.MockUs
 		PregnancyService: pregnancyService,
 		HealthService:    healthService,
 		BreedingService:  breedingService,
+		OrgService:       service.NewOrganizationService(mockOrgRepo),
 		Cache:            cache,
 		HorseRepo:        mockHorseRepo,
 		BreedingRepo:     mockBreedingRepo,